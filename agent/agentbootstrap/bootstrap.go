@@ -104,6 +104,17 @@ func InitializeState(
 		}
 	}()
 	servingInfo.SharedSecret = machineCfg.SharedSecret
+	if servingInfo.SecretsKey == "" {
+		// The secrets key is kept only in the controllers' agent
+		// config, and never in state.
+		servingInfo.SecretsKey, err = state.NewSecretsKey()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	if err := st.SetSecretsKey(servingInfo.SecretsKey); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	c.SetStateServingInfo(servingInfo)
 
 	// Filter out any LXC bridge addresses from the machine addresses.
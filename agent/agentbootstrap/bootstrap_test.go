@@ -229,6 +229,14 @@ LXC_BRIDGE="ignored"`[1:])
 	newCfg, err := agent.ReadConfig(agent.ConfigPath(dataDir, machine0))
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(newCfg.Tag(), gc.Equals, machine0)
+
+	// Check that a secrets key has been generated and written to
+	// the agent config, which is the only place it is kept.
+	newServingInfo, ok := newCfg.StateServingInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(newServingInfo.SecretsKey, gc.Not(gc.Equals), "")
+	err = st.SetSecretsKey(newServingInfo.SecretsKey)
+	c.Assert(err, jc.ErrorIsNil)
 	info, ok := cfg.MongoInfo()
 	c.Assert(ok, jc.IsTrue)
 	c.Assert(info.Password, gc.Not(gc.Equals), testing.DefaultMongoPassword)
@@ -57,6 +57,7 @@ type format_1_18Serialization struct {
 	StatePort      int    `yaml:",omitempty"`
 	SharedSecret   string `yaml:",omitempty"`
 	SystemIdentity string `yaml:",omitempty"`
+	SecretsKey     string `yaml:",omitempty"`
 	MongoVersion   string `yaml:",omitempty"`
 }
 
@@ -128,6 +129,7 @@ func (formatter_1_18) unmarshal(data []byte) (*configInternal, error) {
 			StatePort:      format.StatePort,
 			SharedSecret:   format.SharedSecret,
 			SystemIdentity: format.SystemIdentity,
+			SecretsKey:     format.SecretsKey,
 		}
 		// There's a private key, then we need the state port,
 		// which wasn't always in the  1.18 format. If it's not present
@@ -183,6 +185,7 @@ func (formatter_1_18) marshal(config *configInternal) ([]byte, error) {
 		format.StatePort = config.servingInfo.StatePort
 		format.SharedSecret = config.servingInfo.SharedSecret
 		format.SystemIdentity = config.servingInfo.SystemIdentity
+		format.SecretsKey = config.servingInfo.SecretsKey
 	}
 	if config.stateDetails != nil {
 		format.StateAddresses = config.stateDetails.addresses
@@ -52,6 +52,7 @@ func (s *servingInfoSuite) TestStateServingInfo(c *gc.C) {
 		SharedSecret: ssi.SharedSecret,
 		APIPort:      ssi.APIPort,
 		StatePort:    ssi.StatePort,
+		SecretsKey:   coretesting.SecretsKey,
 	}
 	s.State.SetStateServingInfo(ssi)
 	info, err := apiagent.NewState(st).StateServingInfo()
@@ -10,10 +10,19 @@ import (
 )
 
 // Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// returns the metadata associated with the resulting backup. If the
+// key is not empty, the backup archive is encrypted with it; controllers
+// too old to encrypt backups are refused the request, rather than
+// creating an unencrypted backup.
+func (c *Client) Create(notes string, key params.BackupsEncryptionKey) (*params.BackupsMetadataResult, error) {
+	if key != (params.BackupsEncryptionKey{}) && c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("encrypted backups on this controller")
+	}
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:      notes,
+		Encryption: key,
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -24,6 +24,27 @@ func PatchClientFacadeCall(c *Client, mockCall func(request string, params inter
 	}
 }
 
+// PatchBestAPIVersion makes the client report the given version as the
+// best API version supported by both it and the controller. The function
+// returned is a cleanup function that returns the client to its original
+// state.
+func PatchBestAPIVersion(c *Client, version int) func() {
+	orig := c.ClientFacade
+	c.ClientFacade = &versionFacade{orig, version}
+	return func() {
+		c.ClientFacade = orig
+	}
+}
+
+type versionFacade struct {
+	base.ClientFacade
+	version int
+}
+
+func (f *versionFacade) BestAPIVersion() int {
+	return f.version
+}
+
 type resultCaller struct {
 	mockCall func(request string, params interface{}, response interface{}) error
 }
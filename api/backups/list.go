@@ -18,3 +18,19 @@ func (c *Client) List() (*params.BackupsListResult, error) {
 	}
 	return &result, nil
 }
+
+// ListRemote returns the metadata for the backups held in the remote
+// backup storage configured for the controller. Controllers too old to
+// have remote backup storage are refused the request, rather than
+// listing their local backups instead.
+func (c *Client) ListRemote() (*params.BackupsListResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("remote backup storage on this controller")
+	}
+	var result params.BackupsListResult
+	args := params.BackupsListArgs{Remote: true}
+	if err := c.facade.FacadeCall("List", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
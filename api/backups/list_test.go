@@ -43,3 +43,40 @@ func (s *listSuite) TestList(c *gc.C) {
 	resultItem := result.List[0]
 	s.checkMetadataResult(c, &resultItem, s.Meta)
 }
+
+func (s *listSuite) TestListRemote(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "List")
+			c.Check(paramsIn, jc.DeepEquals, params.BackupsListArgs{Remote: true})
+
+			result := resp.(*params.BackupsListResult)
+			result.List = []params.BackupsMetadataResult{
+				apiserverbackups.ResultFromMetadata(s.Meta),
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.ListRemote()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(result.List, gc.HasLen, 1)
+	s.checkMetadataResult(c, &result.List[0], s.Meta)
+}
+
+func (s *listSuite) TestListRemoteNotSupported(c *gc.C) {
+	cleanup := backups.PatchBestAPIVersion(s.client, 1)
+	defer cleanup()
+	cleanup = backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Fatalf("unexpected call to %s", req)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	_, err := s.client.ListRemote()
+	c.Assert(err, gc.ErrorMatches, "remote backup storage on this controller not supported")
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/httprequest"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type downloadModelParams struct {
+	httprequest.Route `httprequest:"GET /model-backup"`
+}
+
+// DownloadModel returns a backup archive of the model the client is
+// connected to, holding the model together with its charms, tools
+// and resources.
+func (c *Client) DownloadModel() (io.ReadCloser, error) {
+	var resp *http.Response
+	if err := c.client.Call(&downloadModelParams{}, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.Body, nil
+}
+
+// RestoreModel recreates the model held in the given model backup
+// archive. If the model already exists it is replaced when replace
+// is true; otherwise it is an error.
+func (c *Client) RestoreModel(archive io.ReadSeeker, replace bool) (params.BackupsRestoreModelResult, error) {
+	path := "/model-backup"
+	if replace {
+		path += "?replace=true"
+	}
+	req, err := http.NewRequest("POST", path, nil)
+	if err != nil {
+		return params.BackupsRestoreModelResult{}, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", params.ContentTypeRaw)
+	var result params.BackupsRestoreModelResult
+	if err := c.client.Do(req, archive, &result); err != nil {
+		return params.BackupsRestoreModelResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/migration"
+)
+
+type modelBackupSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&modelBackupSuite{})
+
+func (s *modelBackupSuite) TestDownloadModel(c *gc.C) {
+	archive, err := s.client.DownloadModel()
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	modelArchive, err := migration.OpenModelArchive(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	defer modelArchive.Close()
+	c.Check(modelArchive.Model().Tag(), gc.Equals, s.State.ModelTag())
+}
+
+func (s *modelBackupSuite) TestRestoreModelExisting(c *gc.C) {
+	archive, err := s.client.DownloadModel()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.client.RestoreModel(bytes.NewReader(data), false)
+	c.Assert(err, gc.ErrorMatches, `POST https://.*/model/.*/model-backup: model ".*" already exists`)
+}
+
+func (s *modelBackupSuite) TestRestoreModelControllerModel(c *gc.C) {
+	archive, err := s.client.DownloadModel()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.client.RestoreModel(bytes.NewReader(data), true)
+	c.Assert(err, gc.ErrorMatches, `.*cannot replace the controller model`)
+}
@@ -62,6 +62,87 @@ func (c *Client) StatusHistory(kind params.HistoryKind, name string, size int) (
 	return &results, nil
 }
 
+// HookExecutions returns the most recent hook, action and juju-run
+// executions recorded for the named unit, most recent first.
+func (c *Client) HookExecutions(unitName string) ([]params.HookExecution, error) {
+	if !names.IsValidUnit(unitName) {
+		return nil, errors.NotValidf("unit name %q", unitName)
+	}
+	var results params.HookExecutionsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUnitTag(unitName).String()}},
+	}
+	if err := c.facade.FacadeCall("HookExecutions", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Executions, nil
+}
+
+// UnitCharmState returns the key/value data the named unit's charm has
+// persisted between hooks.
+func (c *Client) UnitCharmState(unitName string) (map[string]string, error) {
+	if !names.IsValidUnit(unitName) {
+		return nil, errors.NotValidf("unit name %q", unitName)
+	}
+	var results params.CharmStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewUnitTag(unitName).String()}},
+	}
+	if err := c.facade.FacadeCall("UnitsCharmState", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.CharmState, nil
+}
+
+// ListSecrets returns the details of every secret in the model,
+// without their values.
+func (c *Client) ListSecrets() ([]params.SecretDetails, error) {
+	var results params.SecretDetailsResults
+	if err := c.facade.FacadeCall("ListSecrets", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets := make([]params.SecretDetails, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		secrets[i] = *result.Result
+	}
+	return secrets, nil
+}
+
+// ShowSecret returns the details and revision history of the secret
+// with the given id, without its values.
+func (c *Client) ShowSecret(id string) (*params.SecretDetails, error) {
+	var results params.SecretDetailsResults
+	args := params.SecretIds{Ids: []string{id}}
+	if err := c.facade.FacadeCall("ShowSecrets", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
 // Resolved clears errors on a unit.
 func (c *Client) Resolved(unit string, retry bool) error {
 	p := params.Resolved{
@@ -246,10 +327,40 @@ func (c *Client) ModelUnset(keys ...string) error {
 }
 
 // SetModelAgentVersion sets the model agent-version setting
-// to the given value.
-func (c *Client) SetModelAgentVersion(version version.Number) error {
+// to the given value. If the upgrade cannot be rolled back, the
+// result says why.
+func (c *Client) SetModelAgentVersion(version version.Number) (params.AgentVersionUpgradeResult, error) {
+	var result params.AgentVersionUpgradeResult
 	args := params.SetModelAgentVersion{Version: version}
-	return c.facade.FacadeCall("SetModelAgentVersion", args, nil)
+	err := c.facade.FacadeCall("SetModelAgentVersion", args, &result)
+	return result, err
+}
+
+// StartUpgradeRollout sets the model agent version, releasing it to
+// the machines with the given ids first; the remaining machines are
+// upgraded batchSize at a time once the canaries have upgraded. If the
+// upgrade cannot be rolled back, the result says why.
+func (c *Client) StartUpgradeRollout(version version.Number, canaries []string, batchSize int) (params.AgentVersionUpgradeResult, error) {
+	var result params.AgentVersionUpgradeResult
+	args := params.StartUpgradeRollout{
+		Version:   version,
+		Canaries:  make([]string, len(canaries)),
+		BatchSize: batchSize,
+	}
+	for i, id := range canaries {
+		if !names.IsValidMachine(id) {
+			return result, errors.NotValidf("machine id %q", id)
+		}
+		args.Canaries[i] = names.NewMachineTag(id).String()
+	}
+	err := c.facade.FacadeCall("StartUpgradeRollout", args, &result)
+	return result, err
+}
+
+// ResumeUpgradeRollout resumes a staged upgrade that was paused
+// because a machine failed to upgrade.
+func (c *Client) ResumeUpgradeRollout() error {
+	return c.facade.FacadeCall("ResumeUpgradeRollout", nil, nil)
 }
 
 // AbortCurrentUpgrade aborts and archives the current upgrade
@@ -258,6 +369,21 @@ func (c *Client) AbortCurrentUpgrade() error {
 	return c.facade.FacadeCall("AbortCurrentUpgrade", nil, nil)
 }
 
+// PrepareUpgradeRollback checks that the model's most recent agent
+// upgrade can be rolled back, and returns what is needed to do so.
+func (c *Client) PrepareUpgradeRollback() (params.UpgradeRollbackInfo, error) {
+	var result params.UpgradeRollbackInfo
+	err := c.facade.FacadeCall("PrepareUpgradeRollback", nil, &result)
+	return result, err
+}
+
+// FinishUpgradeRollback sets the model's agent version back to the
+// version it ran before the given upgrade, so that its agents
+// downgrade.
+func (c *Client) FinishUpgradeRollback(info params.UpgradeRollbackInfo) error {
+	return c.facade.FacadeCall("FinishUpgradeRollback", info, nil)
+}
+
 // FindTools returns a List containing all tools matching the specified parameters.
 func (c *Client) FindTools(majorVersion, minorVersion int, series, arch string) (result params.FindToolsResult, err error) {
 	args := params.FindToolsParams{
@@ -455,7 +455,7 @@ func (s *clientSuite) TestSetEnvironAgentVersionDuringUpgrade(c *gc.C) {
 	_, err = s.State.EnsureUpgradeInfo(machine.Id(), agentVersion, nextVersion)
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = s.APIState.Client().SetModelAgentVersion(nextVersion)
+	_, err = s.APIState.Client().SetModelAgentVersion(nextVersion)
 
 	// Expect an error with a error code that indicates this specific
 	// situation. The client needs to be able to reliably identify
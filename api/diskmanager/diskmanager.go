@@ -43,3 +43,20 @@ func (st *State) SetMachineBlockDevices(devices []storage.BlockDevice) error {
 	}
 	return results.OneError()
 }
+
+// SetMachineFilesystemUsage sets the usage of the filesystems mounted on
+// the machine identified by the authenticated machine tag.
+func (st *State) SetMachineFilesystemUsage(usage []storage.FilesystemUsage) error {
+	args := params.SetMachineFilesystemUsage{
+		MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+			Machine: st.tag.String(),
+			Usage:   usage,
+		}},
+	}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetMachineFilesystemUsage", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
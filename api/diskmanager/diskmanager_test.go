@@ -124,3 +124,39 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesResultCountInvalid(c *gc.C)
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *DiskManagerSuite) TestSetMachineFilesystemUsage(c *gc.C) {
+	usage := []storage.FilesystemUsage{{
+		MountPoint: "/srv",
+		Size:       1024,
+		Used:       256,
+		Available:  768,
+	}}
+
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "DiskManager")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetMachineFilesystemUsage")
+		c.Check(arg, gc.DeepEquals, params.SetMachineFilesystemUsage{
+			MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+				Machine: "machine-123",
+				Usage:   usage,
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetMachineFilesystemUsage(usage)
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(callCount, gc.Equals, 1)
+}
@@ -22,20 +22,21 @@ var facadeVersions = map[string]int{
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Backups":                      1,
+	"Backups":                      2,
 	"Block":                        2,
 	"CharmRevisionUpdater":         1,
 	"Charms":                       2,
 	"Cleaner":                      2,
-	"Client":                       1,
+	"Client":                       2,
 	"Controller":                   2,
 	"Deployer":                     1,
 	"DiscoverSpaces":               2,
-	"DiskManager":                  2,
+	"DiskManager":                  3,
 	"EntityWatcher":                2,
 	"FilesystemAttachmentsWatcher": 2,
 	"Firewaller":                   2,
-	"HighAvailability":             2,
+	"FirewallRules":                1,
+	"HighAvailability":             3,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
 	"ImageMetadata":                2,
@@ -46,6 +47,7 @@ var facadeVersions = map[string]int{
 	"LifeFlag":                     1,
 	"Logger":                       1,
 	"MachineActions":               1,
+	"MachineFirewaller":            1,
 	"MachineManager":               2,
 	"Machiner":                     1,
 	"MeterStatus":                  1,
@@ -66,7 +68,7 @@ var facadeVersions = map[string]int{
 	"RelationUnitsWatcher":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
-	"Service":                      3,
+	"Service":                      4,
 	"ServiceScaler":                1,
 	"Singular":                     1,
 	"Spaces":                       2,
@@ -77,7 +79,8 @@ var facadeVersions = map[string]int{
 	"Subnets":                      2,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       3,
+	"Uniter":                       4,
+	"UpgradeRollout":               1,
 	"Upgrader":                     1,
 	"UserManager":                  1,
 	"VolumeAttachmentsWatcher":     2,
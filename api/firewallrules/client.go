@@ -0,0 +1,61 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// Client provides access to the FirewallRules facade, used to manage
+// the model-level firewall rules.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new firewall rules client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "FirewallRules")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddFirewallRule adds the specified firewall rule to the model.
+func (c *Client) AddFirewallRule(rule network.FirewallRule) error {
+	args := params.FirewallRules{
+		Rules: []params.FirewallRule{params.FromNetworkFirewallRule(rule)},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddFirewallRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RemoveFirewallRule removes the firewall rule with the specified
+// name from the model.
+func (c *Client) RemoveFirewallRule(name string) error {
+	args := params.FirewallRuleNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveFirewallRules", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListFirewallRules returns all of the model's firewall rules.
+func (c *Client) ListFirewallRules() ([]network.FirewallRule, error) {
+	var result params.ListFirewallRulesResults
+	if err := c.facade.FacadeCall("ListFirewallRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules := make([]network.FirewallRule, len(result.Rules))
+	for i, rule := range result.Rules {
+		rules[i] = rule.NetworkFirewallRule()
+	}
+	return rules, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type firewallRulesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&firewallRulesSuite{})
+
+var (
+	dmzToApp = network.FirewallRule{
+		Name:             "dmz-to-app",
+		SourceSpace:      "dmz",
+		DestinationSpace: "app",
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}
+	dmzToAppParams = params.FirewallRule{
+		Name:             "dmz-to-app",
+		SourceSpace:      "dmz",
+		DestinationSpace: "app",
+		Ports:            []params.PortRange{{FromPort: 443, ToPort: 443, Protocol: "tcp"}},
+	}
+)
+
+func (s *firewallRulesSuite) TestAddFirewallRule(c *gc.C) {
+	var called bool
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "FirewallRules")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "AddFirewallRules")
+		c.Check(arg, jc.DeepEquals, params.FirewallRules{
+			Rules: []params.FirewallRule{dmzToAppParams},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	client := firewallrules.NewClient(apiCaller)
+	err := client.AddFirewallRule(dmzToApp)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *firewallRulesSuite) TestRemoveFirewallRule(c *gc.C) {
+	var called bool
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "FirewallRules")
+		c.Check(request, gc.Equals, "RemoveFirewallRules")
+		c.Check(arg, jc.DeepEquals, params.FirewallRuleNames{
+			Names: []string{"dmz-to-app"},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	client := firewallrules.NewClient(apiCaller)
+	err := client.RemoveFirewallRule("dmz-to-app")
+	c.Assert(called, jc.IsTrue)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *firewallRulesSuite) TestListFirewallRules(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "FirewallRules")
+		c.Check(request, gc.Equals, "ListFirewallRules")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.ListFirewallRulesResults{})
+		*(result.(*params.ListFirewallRulesResults)) = params.ListFirewallRulesResults{
+			Rules: []params.FirewallRule{dmzToAppParams},
+		}
+		return nil
+	})
+	client := firewallrules.NewClient(apiCaller)
+	rules, err := client.ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.FirewallRule{dmzToApp})
+}
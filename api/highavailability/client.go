@@ -86,3 +86,42 @@ func (c *Client) ResumeHAReplicationAfterUpgrade(members []replicaset.Member) er
 	}
 	return nil
 }
+
+// HAStatus returns the replica set status of the controller machines.
+func (c *Client) HAStatus() (params.HAStatusResult, error) {
+	var result params.HAStatusResult
+	if err := c.facade.FacadeCall("HAStatus", nil, &result); err != nil {
+		return params.HAStatusResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// RemoveController takes the controller machine with the given id one
+// step further towards removal. If replace is true, and the machine has
+// yet to be demoted, a new controller machine is started to take over
+// its vote; cons, series and placement apply to the new machine.
+func (c *Client) RemoveController(
+	machineId string, replace bool, cons constraints.Value, series, placement string,
+) (params.ControllersChanges, error) {
+	var results params.ControllersChangeResults
+	arg := params.RemoveControllerSpecs{
+		Specs: []params.RemoveControllerSpec{{
+			MachineTag:  names.NewMachineTag(machineId).String(),
+			Replace:     replace,
+			Constraints: cons,
+			Series:      series,
+			Placement:   placement,
+		}},
+	}
+	if err := c.facade.FacadeCall("RemoveController", arg, &results); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ControllersChanges{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ControllersChanges{}, result.Error
+	}
+	return result.Result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type removeControllerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&removeControllerSuite{})
+
+func (s *removeControllerSuite) TestRemoveController(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "HighAvailability")
+		c.Check(request, gc.Equals, "RemoveController")
+		c.Check(arg, jc.DeepEquals, params.RemoveControllerSpecs{
+			Specs: []params.RemoveControllerSpec{{
+				MachineTag:  "machine-1",
+				Replace:     true,
+				Constraints: constraints.MustParse("mem=8G"),
+				Series:      "trusty",
+				Placement:   "zone=z2",
+			}},
+		})
+		*(result.(*params.ControllersChangeResults)) = params.ControllersChangeResults{
+			Results: []params.ControllersChangeResult{{
+				Result: params.ControllersChanges{
+					Added:   []string{"machine-3"},
+					Demoted: []string{"machine-1"},
+				},
+			}},
+		}
+		return nil
+	})
+	client := highavailability.NewClient(apiCaller)
+	changes, err := client.RemoveController("1", true, constraints.MustParse("mem=8G"), "trusty", "zone=z2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, params.ControllersChanges{
+		Added:   []string{"machine-3"},
+		Demoted: []string{"machine-1"},
+	})
+}
+
+func (s *removeControllerSuite) TestRemoveControllerError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.ControllersChangeResults)) = params.ControllersChangeResults{
+			Results: []params.ControllersChangeResult{{
+				Error: &params.Error{Message: "machine 1 is not a controller"},
+			}},
+		}
+		return nil
+	})
+	client := highavailability.NewClient(apiCaller)
+	_, err := client.RemoveController("1", false, constraints.Value{}, "", "")
+	c.Assert(err, gc.ErrorMatches, "machine 1 is not a controller")
+}
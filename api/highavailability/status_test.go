@@ -0,0 +1,53 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type statusSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&statusSuite{})
+
+func (s *statusSuite) TestHAStatus(c *gc.C) {
+	expected := params.HAStatusResult{
+		Members: []params.HAMemberStatus{{
+			MachineTag: "machine-0",
+			Voting:     true,
+			State:      "PRIMARY",
+		}},
+		Problems: []string{"even number of voting members (2)"},
+	}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "HighAvailability")
+		c.Check(request, gc.Equals, "HAStatus")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.HAStatusResult)) = expected
+		return nil
+	})
+	client := highavailability.NewClient(apiCaller)
+	status, err := client.HAStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, expected)
+}
+
+func (s *statusSuite) TestHAStatusError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := highavailability.NewClient(apiCaller)
+	_, err := client.HAStatus()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/watcher"
+)
+
+// Client provides access to the MachineFirewaller facade, used by
+// machine agents to enforce the model-level firewall rules.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new machine firewaller client.
+func NewClient(apiCaller base.APICaller) *Client {
+	return &Client{base.NewFacadeCaller(apiCaller, "MachineFirewaller")}
+}
+
+// EgressRules returns the egress rules that apply to the
+// specified machine.
+func (c *Client) EgressRules(tag names.MachineTag) ([]network.EgressRule, error) {
+	var results params.EgressRulesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	if err := c.facade.FacadeCall("EgressRules", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	rules := make([]network.EgressRule, len(result.Rules))
+	for i, rule := range result.Rules {
+		ports := make([]network.PortRange, len(rule.Ports))
+		for j, pr := range rule.Ports {
+			ports[j] = pr.NetworkPortRange()
+		}
+		rules[i] = network.EgressRule{
+			Name:             rule.Name,
+			DestinationCIDRs: rule.DestinationCIDRs,
+			Ports:            ports,
+		}
+	}
+	return rules, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher that notifies when the
+// egress rules for the specified machine may have changed.
+func (c *Client) WatchFirewallRules(tag names.MachineTag) (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	if err := c.facade.FacadeCall("WatchFirewallRules", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// SetProviderEgressRules asks the controller to enforce the egress
+// rules for the specified machine with the provider's firewall, and
+// reports whether it did. If not, the rules must be enforced on the
+// machine itself.
+func (c *Client) SetProviderEgressRules(tag names.MachineTag) (bool, error) {
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	if err := c.facade.FacadeCall("SetProviderEgressRules", args, &results); err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, errors.Trace(result.Error)
+	}
+	return result.Result, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machinefirewaller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type machineFirewallerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&machineFirewallerSuite{})
+
+func (s *machineFirewallerSuite) TestEgressRules(c *gc.C) {
+	tag := names.NewMachineTag("0")
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineFirewaller")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "EgressRules")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: tag.String()}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.EgressRulesResults{})
+		*(result.(*params.EgressRulesResults)) = params.EgressRulesResults{
+			Results: []params.EgressRulesResult{{
+				Rules: []params.EgressRule{{
+					Name:             "https",
+					DestinationCIDRs: []string{"0.0.0.0/0"},
+					Ports:            []params.PortRange{{FromPort: 443, ToPort: 443, Protocol: "tcp"}},
+				}},
+			}},
+		}
+		return nil
+	})
+	client := machinefirewaller.NewClient(apiCaller)
+	rules, err := client.EgressRules(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{{
+		Name:             "https",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}})
+}
+
+func (s *machineFirewallerSuite) TestEgressRulesError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.EgressRulesResults)) = params.EgressRulesResults{
+			Results: []params.EgressRulesResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	client := machinefirewaller.NewClient(apiCaller)
+	_, err := client.EgressRules(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machineFirewallerSuite) TestWatchFirewallRulesError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineFirewaller")
+		c.Check(request, gc.Equals, "WatchFirewallRules")
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResults{})
+		*(result.(*params.NotifyWatchResults)) = params.NotifyWatchResults{
+			Results: []params.NotifyWatchResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	client := machinefirewaller.NewClient(apiCaller)
+	_, err := client.WatchFirewallRules(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRules(c *gc.C) {
+	tag := names.NewMachineTag("0")
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineFirewaller")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetProviderEgressRules")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: tag.String()}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.BoolResults{})
+		*(result.(*params.BoolResults)) = params.BoolResults{
+			Results: []params.BoolResult{{Result: true}},
+		}
+		return nil
+	})
+	client := machinefirewaller.NewClient(apiCaller)
+	enforced, err := client.SetProviderEgressRules(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enforced, jc.IsTrue)
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRulesError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.BoolResults)) = params.BoolResults{
+			Results: []params.BoolResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	client := machinefirewaller.NewClient(apiCaller)
+	_, err := client.SetProviderEgressRules(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -162,8 +162,16 @@ func (c *Client) SetCharm(cfg SetCharmConfig) error {
 }
 
 // Update updates the service attributes, including charm URL,
-// minimum number of units, settings and constraints.
+// minimum number of units, settings and constraints. Controllers too
+// old to set the status aggregation policy or hook timeout are refused
+// the request, rather than silently ignoring it.
 func (c *Client) Update(args params.ServiceUpdate) error {
+	if args.StatusAggregation != nil && c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("status aggregation on this controller")
+	}
+	if args.HookTimeout != nil && c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("hook timeouts on this controller")
+	}
 	return c.facade.FacadeCall("Update", args, nil)
 }
 
@@ -4,6 +4,8 @@
 package service_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -163,3 +165,51 @@ func (s *serviceSuite) TestServiceSetCharm(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
+
+func (s *serviceSuite) TestServiceUpdateStatusAggregation(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "Update")
+		args, ok := a.(params.ServiceUpdate)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(args.ServiceName, gc.Equals, "service")
+		c.Assert(*args.StatusAggregation, gc.Equals, "majority")
+		return nil
+	})
+	policy := "majority"
+	err := s.client.Update(params.ServiceUpdate{
+		ServiceName:       "service",
+		StatusAggregation: &policy,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestServiceUpdateStatusAggregationNotSupported(c *gc.C) {
+	service.PatchBestAPIVersion(s, s.client, 3)
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	policy := "majority"
+	err := s.client.Update(params.ServiceUpdate{
+		ServiceName:       "service",
+		StatusAggregation: &policy,
+	})
+	c.Assert(err, gc.ErrorMatches, "status aggregation on this controller not supported")
+}
+
+func (s *serviceSuite) TestServiceUpdateHookTimeoutNotSupported(c *gc.C) {
+	service.PatchBestAPIVersion(s, s.client, 3)
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	timeout := time.Minute
+	err := s.client.Update(params.ServiceUpdate{
+		ServiceName: "service",
+		HookTimeout: &timeout,
+	})
+	c.Assert(err, gc.ErrorMatches, "hook timeouts on this controller not supported")
+}
@@ -4,6 +4,7 @@
 package service
 
 import (
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/base/testing"
 )
 
@@ -13,3 +14,19 @@ import (
 func PatchFacadeCall(p testing.Patcher, client *Client, f func(request string, params, response interface{}) error) {
 	testing.PatchFacadeCall(p, &client.facade, f)
 }
+
+// PatchBestAPIVersion patches the client such that it reports the
+// given version as the best API version supported by both it and the
+// controller.
+func PatchBestAPIVersion(p testing.Patcher, client *Client, version int) {
+	p.PatchValue(&client.ClientFacade, base.ClientFacade(&versionFacade{client.ClientFacade, version}))
+}
+
+type versionFacade struct {
+	base.ClientFacade
+	version int
+}
+
+func (f *versionFacade) BestAPIVersion() int {
+	return f.version
+}
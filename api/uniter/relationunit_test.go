@@ -207,6 +207,45 @@ func (s *relationUnitSuite) TestSettings(c *gc.C) {
 	})
 }
 
+func (s *relationUnitSuite) TestCommitHookChanges(c *gc.C) {
+	wpRelUnit, apiRelUnit := s.getRelationUnits(c)
+	err := wpRelUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+	apiUnit, err := s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := apiRelUnit.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("some", "different")
+	settings.Set("other", "things")
+	err = apiUnit.CommitHookChanges(
+		[]*uniter.Settings{settings},
+		map[string]string{"initialised": "true"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	written, err := wpRelUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(written, gc.DeepEquals, map[string]interface{}{
+		"some":  "different",
+		"other": "things",
+	})
+	charmState, err := s.wordpressUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"initialised": "true"})
+
+	// An invalid charm state means the settings are not written either.
+	settings.Set("some", "invalid")
+	err = apiUnit.CommitHookChanges(
+		[]*uniter.Settings{settings},
+		map[string]string{"": "value"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot commit hook changes for unit "wordpress/0": empty key not valid`)
+	written, err = wpRelUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(written["some"], gc.Equals, "different")
+}
+
 func (s *relationUnitSuite) TestReadSettings(c *gc.C) {
 	// First try to read the settings which are not set.
 	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
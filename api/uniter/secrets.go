@@ -0,0 +1,123 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// AddSecret stores a new secret owned by the unit's service, and
+// returns its id.
+func (u *Unit) AddSecret(description string, rotateInterval time.Duration, data map[string]string) (string, error) {
+	return u.addSecret(params.AddSecretArg{
+		UnitTag:        u.tag.String(),
+		Description:    description,
+		RotateInterval: rotateInterval,
+		Data:           data,
+	})
+}
+
+// AddSecretRevision stores the supplied values as a new revision of a
+// secret owned by the unit's service.
+func (u *Unit) AddSecretRevision(id string, data map[string]string) error {
+	_, err := u.addSecret(params.AddSecretArg{
+		UnitTag:  u.tag.String(),
+		SecretId: id,
+		Data:     data,
+	})
+	return err
+}
+
+func (u *Unit) addSecret(arg params.AddSecretArg) (string, error) {
+	var results params.StringResults
+	args := params.AddSecretArgs{Args: []params.AddSecretArg{arg}}
+	err := u.st.facade.FacadeCall("AddSecrets", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// Secret returns the values of the supplied revision of a secret, or
+// of its latest revision if revision is zero, along with the revision
+// read. The secret must be owned by the unit's service, or have been
+// granted to the unit or to a relation its service is in.
+func (u *Unit) Secret(id string, revision int) (map[string]string, int, error) {
+	var results params.SecretValueResults
+	args := params.GetSecretArgs{
+		Args: []params.GetSecretArg{
+			{UnitTag: u.tag.String(), SecretId: id, Revision: revision},
+		},
+	}
+	err := u.st.facade.FacadeCall("GetSecrets", args, &results)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(results.Results) != 1 {
+		return nil, 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return result.Data, result.Revision, nil
+}
+
+// SecretsDueForRotation returns the ids of the secrets owned by the
+// unit's service that are due to be rotated.
+func (u *Unit) SecretsDueForRotation() ([]string, error) {
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("SecretsDueForRotation", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// GrantSecret shares a secret owned by the unit's service with the
+// supplied unit or relation.
+func (u *Unit) GrantSecret(id string, subject names.Tag) error {
+	return u.updateSecretGrant("GrantSecrets", id, subject)
+}
+
+// RevokeSecret withdraws a grant made by GrantSecret.
+func (u *Unit) RevokeSecret(id string, subject names.Tag) error {
+	return u.updateSecretGrant("RevokeSecrets", id, subject)
+}
+
+func (u *Unit) updateSecretGrant(method, id string, subject names.Tag) error {
+	var result params.ErrorResults
+	args := params.GrantSecretArgs{
+		Args: []params.GrantSecretArg{
+			{UnitTag: u.tag.String(), SecretId: id, SubjectTag: subject.String()},
+		},
+	}
+	err := u.st.facade.FacadeCall(method, args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
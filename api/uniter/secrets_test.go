@@ -0,0 +1,143 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&secretsSuite{})
+
+type secretsSuite struct {
+	coretesting.BaseSuite
+}
+
+// newUnit returns a unit whose secrets calls are handled by the
+// supplied function.
+func (s *secretsSuite) newUnit(c *gc.C, handle func(request string, arg, result interface{}) error) *uniter.Unit {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(version, gc.Equals, 4)
+		c.Check(id, gc.Equals, "")
+		if request == "Life" {
+			*(result.(*params.LifeResults)) = params.LifeResults{
+				Results: []params.LifeResult{{Life: params.Alive}},
+			}
+			return nil
+		}
+		return handle(request, arg, result)
+	})
+	tag := names.NewUnitTag("mysql/0")
+	unit, err := uniter.NewState(apiCaller, tag).Unit(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *secretsSuite) TestAddSecret(c *gc.C) {
+	unit := s.newUnit(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "AddSecrets")
+		c.Check(arg, jc.DeepEquals, params.AddSecretArgs{
+			Args: []params.AddSecretArg{{
+				UnitTag:        "unit-mysql-0",
+				Description:    "root",
+				RotateInterval: time.Hour,
+				Data:           map[string]string{"password": "s3cr3t"},
+			}},
+		})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Result: "7"}},
+		}
+		return nil
+	})
+	id, err := unit.AddSecret("root", time.Hour, map[string]string{"password": "s3cr3t"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "7")
+}
+
+func (s *secretsSuite) TestAddSecretRevision(c *gc.C) {
+	unit := s.newUnit(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "AddSecrets")
+		c.Check(arg, jc.DeepEquals, params.AddSecretArgs{
+			Args: []params.AddSecretArg{{
+				UnitTag:  "unit-mysql-0",
+				SecretId: "7",
+				Data:     map[string]string{"password": "n3w"},
+			}},
+		})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Error: &params.Error{Message: "bad"}}},
+		}
+		return nil
+	})
+	err := unit.AddSecretRevision("7", map[string]string{"password": "n3w"})
+	c.Assert(err, gc.ErrorMatches, "bad")
+}
+
+func (s *secretsSuite) TestSecret(c *gc.C) {
+	unit := s.newUnit(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "GetSecrets")
+		c.Check(arg, jc.DeepEquals, params.GetSecretArgs{
+			Args: []params.GetSecretArg{{UnitTag: "unit-mysql-0", SecretId: "7"}},
+		})
+		*(result.(*params.SecretValueResults)) = params.SecretValueResults{
+			Results: []params.SecretValueResult{{
+				Revision: 3,
+				Data:     map[string]string{"password": "s3cr3t"},
+			}},
+		}
+		return nil
+	})
+	data, revision, err := unit.Secret("7", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "s3cr3t"})
+	c.Assert(revision, gc.Equals, 3)
+}
+
+func (s *secretsSuite) TestGrantRevokeSecret(c *gc.C) {
+	var requests []string
+	unit := s.newUnit(c, func(request string, arg, result interface{}) error {
+		requests = append(requests, request)
+		c.Check(arg, jc.DeepEquals, params.GrantSecretArgs{
+			Args: []params.GrantSecretArg{{
+				UnitTag:    "unit-mysql-0",
+				SecretId:   "7",
+				SubjectTag: "unit-wordpress-0",
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	err := unit.GrantSecret("7", names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.RevokeSecret("7", names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requests, jc.DeepEquals, []string{"GrantSecrets", "RevokeSecrets"})
+}
+
+func (s *secretsSuite) TestSecretsDueForRotation(c *gc.C) {
+	unit := s.newUnit(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SecretsDueForRotation")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+		})
+		*(result.(*params.StringsResults)) = params.StringsResults{
+			Results: []params.StringsResult{{Result: []string{"7", "9"}}},
+		}
+		return nil
+	})
+	ids, err := unit.SecretsDueForRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{"7", "9"})
+}
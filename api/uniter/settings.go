@@ -74,19 +74,9 @@ func (s *Settings) Delete(key string) {
 // to make sure we update the address (and other settings) correctly,
 // without overwritting.
 func (s *Settings) Write() error {
-	// First make a copy of the map, including deleted keys.
-	settingsCopy := make(params.Settings)
-	for k, v := range s.settings {
-		settingsCopy[k] = v
-	}
-
 	var result params.ErrorResults
 	args := params.RelationUnitsSettings{
-		RelationUnits: []params.RelationUnitSettings{{
-			Relation: s.relationTag,
-			Unit:     s.unitTag,
-			Settings: settingsCopy,
-		}},
+		RelationUnits: []params.RelationUnitSettings{s.relationUnitSettings()},
 	}
 	err := s.st.facade.FacadeCall("UpdateSettings", args, &result)
 	if err != nil {
@@ -94,3 +84,18 @@ func (s *Settings) Write() error {
 	}
 	return result.OneError()
 }
+
+// relationUnitSettings returns the changes made to s, as sent to the
+// uniter facade to write them.
+func (s *Settings) relationUnitSettings() params.RelationUnitSettings {
+	// Make a copy of the map, including deleted keys.
+	settingsCopy := make(params.Settings)
+	for k, v := range s.settings {
+		settingsCopy[k] = v
+	}
+	return params.RelationUnitSettings{
+		Relation: s.relationTag,
+		Unit:     s.unitTag,
+		Settings: settingsCopy,
+	}
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -211,6 +212,163 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	return charm.Settings(result.Settings), nil
 }
 
+// WorkloadVersion returns the version of the running workload set by
+// the charm.
+func (u *Unit) WorkloadVersion() (string, error) {
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WorkloadVersion", args, &results)
+	if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SetWorkloadVersion sets the version of the workload that the unit is
+// running, as opposed to the version of its charm.
+func (u *Unit) SetWorkloadVersion(version string) error {
+	var result params.ErrorResults
+	args := params.EntityWorkloadVersions{
+		Entities: []params.EntityWorkloadVersion{
+			{Tag: u.tag.String(), WorkloadVersion: version},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadVersion", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// SetHealth sets the health of the unit, as evaluated against the
+// health checks declared by its charm.
+func (u *Unit) SetHealth(health status.Status, info string, data map[string]interface{}) error {
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: u.tag.String(), Status: health, Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetUnitHealth", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// CharmState returns the key/value data the unit's charm has persisted
+// between hooks.
+func (u *Unit) CharmState() (map[string]string, error) {
+	var results params.CharmStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("CharmState", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.CharmState, nil
+}
+
+// CommitHookChanges commits the changes made by one of the unit's
+// hooks: the changes made to each of relationSettings, which must be
+// the unit's own settings, and, unless charmState is nil, the unit's
+// new charm state. Either all of them are applied or none are.
+func (u *Unit) CommitHookChanges(relationSettings []*Settings, charmState map[string]string) error {
+	arg := params.CommitHookChangesArg{
+		Tag:              u.tag.String(),
+		UpdateCharmState: charmState != nil,
+		CharmState:       charmState,
+	}
+	for _, settings := range relationSettings {
+		arg.RelationUnitSettings = append(arg.RelationUnitSettings, settings.relationUnitSettings())
+	}
+	var result params.ErrorResults
+	args := params.CommitHookChangesArgs{Args: []params.CommitHookChangesArg{arg}}
+	err := u.st.facade.FacadeCall("CommitHookChanges", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// GoalState returns the units expected in the unit's service and in
+// each of its relations, with their status.
+func (u *Unit) GoalState() (params.GoalState, error) {
+	var results params.GoalStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("GoalStates", args, &results)
+	if err != nil {
+		return params.GoalState{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.GoalState{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.GoalState{}, result.Error
+	}
+	return *result.Result, nil
+}
+
+// HookTimeout returns the time after which the unit's running hooks
+// should be killed. Zero means they may run indefinitely.
+func (u *Unit) HookTimeout() (time.Duration, error) {
+	var results params.HookTimeoutResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookTimeouts", args, &results)
+	if err != nil {
+		return 0, err
+	}
+	if len(results.Results) != 1 {
+		return 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.Timeout, nil
+}
+
+// RecordHookExecutions records the timing and outcome of a batch of
+// hook, action and juju-run executions by the unit.
+func (u *Unit) RecordHookExecutions(executions []params.HookExecution) error {
+	if u.st.facade.BestAPIVersion() < 4 {
+		return errors.NotImplementedf("RecordHookExecutions() (need V4+)")
+	}
+	var result params.ErrorResults
+	args := params.EntityHookExecutions{
+		Entities: []params.EntityHookExecution{
+			{Tag: u.tag.String(), Executions: executions},
+		},
+	}
+	err := u.st.facade.FacadeCall("RecordHookExecutions", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // ServiceName returns the service name.
 func (u *Unit) ServiceName() string {
 	service, err := names.UnitService(u.Name())
@@ -443,6 +443,113 @@ func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *unitSuite) TestWorkloadVersion(c *gc.C) {
+	version, err := s.apiUnit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "")
+
+	err = s.apiUnit.SetWorkloadVersion("4.5.2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	version, err = s.apiUnit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "4.5.2")
+	version, err = s.wordpressUnit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "4.5.2")
+}
+
+func (s *unitSuite) TestSetHealth(c *gc.C) {
+	err := s.apiUnit.SetHealth(status.StatusUnhealthy, "http: connection refused", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	health, err := s.wordpressUnit.Health()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, status.StatusUnhealthy)
+	c.Assert(health.Message, gc.Equals, "http: connection refused")
+
+	err = s.apiUnit.SetHealth(status.StatusActive, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set health for unit "wordpress/0": invalid health status "active"`)
+}
+
+func (s *unitSuite) TestCharmState(c *gc.C) {
+	charmState, err := s.apiUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, gc.HasLen, 0)
+
+	err = s.apiUnit.CommitHookChanges(nil, map[string]string{"initialised": "true"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	charmState, err = s.apiUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"initialised": "true"})
+	charmState, err = s.wordpressUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"initialised": "true"})
+
+	err = s.apiUnit.CommitHookChanges(nil, map[string]string{"": "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot commit hook changes for unit "wordpress/0": empty key not valid`)
+}
+
+func (s *unitSuite) TestGoalState(c *gc.C) {
+	err := s.wordpressUnit.SetAgentStatus(status.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	goalState, err := s.apiUnit.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState, jc.DeepEquals, params.GoalState{
+		Units: map[string]params.GoalStateStatus{
+			"wordpress/0": {Status: "active"},
+		},
+		Relations: map[string]map[string]params.GoalStateStatus{},
+	})
+}
+
+func (s *unitSuite) TestHookTimeout(c *gc.C) {
+	timeout, err := s.apiUnit.HookTimeout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeout, gc.Equals, time.Duration(0))
+
+	err = s.wordpressService.SetHookTimeout(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	timeout, err = s.apiUnit.HookTimeout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeout, gc.Equals, time.Minute)
+}
+
+func (s *unitSuite) TestRecordHookExecutions(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.RecordHookExecutions([]params.HookExecution{{
+		Kind:     "hook",
+		Name:     "config-changed",
+		Started:  started,
+		Duration: time.Minute,
+		ExitCode: 0,
+	}, {
+		Kind:     "hook",
+		Name:     "update-status",
+		Started:  started.Add(time.Minute),
+		Duration: time.Second,
+		ExitCode: 1,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.wordpressUnit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{{
+		Kind:     "hook",
+		Name:     "update-status",
+		Started:  started.Add(time.Minute),
+		Duration: time.Second,
+		ExitCode: 1,
+	}, {
+		Kind:     "hook",
+		Name:     "config-changed",
+		Started:  started,
+		Duration: time.Minute,
+	}})
+}
+
 func (s *unitSuite) TestWatchConfigSettings(c *gc.C) {
 	// Make sure WatchConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
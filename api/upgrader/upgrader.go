@@ -46,6 +46,14 @@ func (st *State) SetVersion(tag string, v version.Binary) error {
 }
 
 func (st *State) DesiredVersion(tag string) (version.Number, error) {
+	vers, _, err := st.DesiredVersionInfo(tag)
+	return vers, err
+}
+
+// DesiredVersionInfo returns the agent version that the given entity
+// should run, and whether that version is the result of rolling back
+// an upgrade, in which case the entity should downgrade to it.
+func (st *State) DesiredVersionInfo(tag string) (version.Number, bool, error) {
 	var results params.VersionResults
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: tag}},
@@ -53,21 +61,21 @@ func (st *State) DesiredVersion(tag string) (version.Number, error) {
 	err := st.facade.FacadeCall("DesiredVersion", args, &results)
 	if err != nil {
 		// TODO: Not directly tested
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if len(results.Results) != 1 {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+		return version.Number{}, false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
 	}
 	result := results.Results[0]
 	if err := result.Error; err != nil {
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if result.Version == nil {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("received no error, but got a nil Version")
+		return version.Number{}, false, fmt.Errorf("received no error, but got a nil Version")
 	}
-	return *result.Version, nil
+	return *result.Version, result.Rollback, nil
 }
 
 // Tools returns the agent tools that should run on the given entity,
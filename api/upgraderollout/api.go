@@ -0,0 +1,27 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+)
+
+// API makes calls to the UpgradeRollout facade.
+type API struct {
+	caller base.FacadeCaller
+}
+
+// NewAPI returns a new API using the supplied caller.
+func NewAPI(caller base.APICaller) *API {
+	return &API{
+		caller: base.NewFacadeCaller(caller, "UpgradeRollout"),
+	}
+}
+
+// Advance takes the model's staged upgrade, if any, one step further.
+func (api *API) Advance() error {
+	return errors.Trace(api.caller.FacadeCall("Advance", nil, nil))
+}
@@ -0,0 +1,43 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/upgraderollout"
+)
+
+type APISuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&APISuite{})
+
+func (s *APISuite) TestAdvance(c *gc.C) {
+	var called bool
+	caller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "UpgradeRollout")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Advance")
+		c.Check(arg, gc.IsNil)
+		return nil
+	})
+	err := upgraderollout.NewAPI(caller).Advance()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *APISuite) TestAdvanceError(c *gc.C) {
+	caller := basetesting.APICallerFunc(func(_ string, _ int, _, _ string, _, _ interface{}) error {
+		return errors.New("snarf")
+	})
+	err := upgraderollout.NewAPI(caller).Advance()
+	c.Check(err, gc.ErrorMatches, "snarf")
+}
@@ -415,6 +415,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			ctxt: strictCtxt,
 		},
 	)
+	add("/model/:modeluuid/model-backup",
+		&modelBackupHandler{
+			ctxt: httpCtxt,
+		},
+	)
 	add("/model/:modeluuid/api", mainAPIHandler)
 
 	add("/model/:modeluuid/images/:kind/:series/:arch/:filename",
@@ -452,6 +457,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			ctxt: httpCtxt,
 		},
 	)
+	add("/model-backup",
+		&modelBackupHandler{
+			ctxt: httpCtxt,
+		},
+	)
 	add("/register",
 		&registerUserHandler{
 			httpCtxt,
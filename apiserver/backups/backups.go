@@ -17,6 +17,14 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Backups", 1, NewAPI)
+
+	// Version 2 has the same methods as version 1, but accepts keys
+	// with which to encrypt and decrypt backups, and can list the
+	// backups in remote storage. Version 1 clients never ask for
+	// either, so they are served by the same implementation; the new
+	// version lets clients refuse to make such requests of a
+	// controller that would silently ignore them.
+	common.RegisterStandardFacade("Backups", 2, NewAPI)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.backups")
@@ -35,7 +43,12 @@ func NewAPI(st *state.State, resources *common.Resources, authorizer common.Auth
 	if !authorizer.AuthClient() {
 		return nil, errors.Trace(common.ErrPerm)
 	}
+	return newAPI(st, resources)
+}
 
+// newAPI creates a new instance of the Backups API facade, without
+// checking that it is being used by a client.
+func newAPI(st *state.State, resources *common.Resources) (*API, error) {
 	// For now, backup operations are only permitted on the controller environment.
 	if !st.IsController() {
 		return nil, errors.New("backups are not supported for hosted models")
@@ -86,6 +99,19 @@ var newBackups = func(st *state.State) (backups.Backups, io.Closer) {
 	return backups.NewBackups(stor), stor
 }
 
+var newDestination = backups.NewDestination
+
+// destination returns the remote destination configured for backups,
+// or nil if there is none.
+func (a *API) destination() (backups.Destination, error) {
+	cfg, err := a.st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dest, err := newDestination(cfg)
+	return dest, errors.Annotate(err, "cannot open remote backup storage")
+}
+
 // ResultFromMetadata updates the result with the information in the
 // metadata value.
 func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
@@ -105,6 +131,9 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.Scheduled = meta.Scheduled
+	result.Failure = meta.Failure
+	result.Encryption = meta.Encryption
 
 	result.Model = meta.Origin.Model
 	result.Machine = meta.Origin.Machine
@@ -137,6 +166,9 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Hostname = result.Hostname
 	meta.Origin.Version = result.Version
 	meta.Notes = result.Notes
+	meta.Scheduled = result.Scheduled
+	meta.Failure = result.Failure
+	meta.Encryption = result.Encryption
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 )
 
@@ -16,6 +18,50 @@ var waitUntilReady = replicaset.WaitUntilReady
 // Create is the API method that requests juju to create a new backup
 // of its state.  It returns the metadata for that backup.
 func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataResult, err error) {
+	key, err := a.encryptionKey(args)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	meta, err := a.create(args.Notes, key)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+
+	// Copy the backup off the controller, if so configured.
+	dest, err := a.destination()
+	if err != nil {
+		return p, errors.Annotatef(err, "backup %q created", meta.ID())
+	}
+	if dest != nil {
+		backupsMethods, closer := newBackups(a.st)
+		defer closer.Close()
+		if err := backups.Push(backupsMethods, dest, meta.ID()); err != nil {
+			return p, errors.Annotatef(err, "backup %q created", meta.ID())
+		}
+	}
+
+	return ResultFromMetadata(meta), nil
+}
+
+// CreateLocal creates an unencrypted backup of the controller for the
+// controller's own use, such as the one taken before an upgrade so
+// that it can be rolled back, and returns its id. The backup is kept
+// in the controller's backup storage only.
+func CreateLocal(st *state.State, resources *common.Resources, notes string) (string, error) {
+	a, err := newAPI(st, resources)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	meta, err := a.create(notes, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return meta.ID(), nil
+}
+
+// create creates a new backup with the given notes, encrypted with
+// the given key if it is not nil.
+func (a *API) create(notes string, key *backups.EncryptionKey) (*backups.Metadata, error) {
 	backupsMethods, closer := newBackups(a.st)
 	defer closer.Close()
 
@@ -23,27 +69,49 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	defer session.Close()
 
 	// Don't go if HA isn't ready.
-	err = waitUntilReady(session, 60)
+	err := waitUntilReady(session, 60)
 	if err != nil {
-		return p, errors.Annotatef(err, "HA not ready; try again later")
+		return nil, errors.Annotatef(err, "HA not ready; try again later")
 	}
 
 	mgoInfo := a.st.MongoConnectionInfo()
 	dbInfo, err := backups.NewDBInfo(mgoInfo, session)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	meta, err := backups.NewMetadataState(a.st, a.machineID)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
-	meta.Notes = args.Notes
+	meta.Notes = notes
 
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	err = backupsMethods.Create(meta, a.paths, dbInfo, key)
 	if err != nil {
-		return p, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
+	return meta, nil
+}
 
-	return ResultFromMetadata(meta), nil
+// encryptionKey returns the key with which to encrypt the new backup,
+// or nil if it should not be encrypted.
+func (a *API) encryptionKey(args params.BackupsCreateArgs) (*backups.EncryptionKey, error) {
+	key := backups.EncryptionKey{
+		Passphrase: args.Encryption.Passphrase,
+		PublicKey:  args.Encryption.PublicKey,
+	}
+	if key.Passphrase == "" && key.PublicKey == "" {
+		cfg, err := a.st.ModelConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		key.PublicKey = cfg.BackupEncryptionPublicKey()
+		if key.PublicKey == "" {
+			return nil, nil
+		}
+	}
+	if err := key.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &key, nil
 }
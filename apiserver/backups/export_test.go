@@ -5,5 +5,6 @@ package backups
 
 var (
 	NewBackups     = &newBackups
+	NewDestination = &newDestination
 	WaitUntilReady = &waitUntilReady
 )
@@ -7,18 +7,36 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/backups"
 )
 
-// List provides the implementation of the API method.
+// List provides the implementation of the API method. If Remote is
+// set, the backups held in the remote backup storage are listed
+// instead of those stored on the controller.
 func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error) {
 	var result params.BackupsListResult
 
-	backups, closer := newBackups(a.st)
-	defer closer.Close()
-
-	metaList, err := backups.List()
-	if err != nil {
-		return result, errors.Trace(err)
+	var metaList []*backups.Metadata
+	if args.Remote {
+		dest, err := a.destination()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if dest == nil {
+			return result, errors.NotFoundf("remote backup storage")
+		}
+		metaList, err = dest.List()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+	} else {
+		backupsMethods, closer := newBackups(a.st)
+		defer closer.Close()
+		var err error
+		metaList, err = backupsMethods.List()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
 	}
 
 	result.List = make([]params.BackupsMetadataResult, len(metaList))
@@ -70,6 +70,10 @@ func (a *API) Restore(p params.RestoreArgs) error {
 		NewInstId:      instanceId,
 		NewInstTag:     machine.Tag(),
 		NewInstSeries:  machine.Series(),
+		DecryptionKey: backups.DecryptionKey{
+			Passphrase: p.Key.Passphrase,
+			PrivateKey: p.Key.PrivateKey,
+		},
 	}
 
 	oldTagString, err := backup.Restore(p.BackupId, restoreArgs)
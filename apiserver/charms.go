@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/core/healthcheck"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
 )
@@ -237,6 +238,9 @@ func (h *charmsHandler) processPost(r *http.Request, st *state.State) (*charm.UR
 	if err != nil {
 		return nil, fmt.Errorf("invalid charm archive: %v", err)
 	}
+	if _, err := healthcheck.ReadArchiveChecks(tempFile.Name()); err != nil {
+		return nil, fmt.Errorf("invalid charm archive: %v", err)
+	}
 	// We got it, now let's reserve a charm URL for it in state.
 	archiveURL := &charm.URL{
 		Schema:   "local",
@@ -237,6 +237,27 @@ func (s *charmsSuite) TestUploadRespectsLocalRevision(c *gc.C) {
 	c.Assert(downloadedSHA256, gc.Equals, expectedSHA256)
 }
 
+func (s *charmsSuite) TestUploadRejectsInvalidHealthChecks(c *gc.C) {
+	// Make a dummy charm dir declaring a health check with nothing
+	// to check.
+	dir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
+	metadata, err := os.OpenFile(filepath.Join(dir.Path, "metadata.yaml"), os.O_APPEND|os.O_WRONLY, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = metadata.WriteString("health-checks:\n  web:\n    interval: 10s\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.Close(), jc.ErrorIsNil)
+	tempFile, err := ioutil.TempFile(c.MkDir(), "charm")
+	c.Assert(err, jc.ErrorIsNil)
+	defer tempFile.Close()
+	defer os.Remove(tempFile.Name())
+	err = dir.ArchiveTo(tempFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.uploadRequest(c, s.charmsURI(c, "?series=quantal"), "application/zip", tempFile.Name())
+	s.assertErrorResponse(c, resp, http.StatusBadRequest,
+		`invalid charm archive: health check "web": expected exactly one of http, tcp or exec`)
+}
+
 func (s *charmsSuite) TestUploadAllowsTopLevelPath(c *gc.C) {
 	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
 	// Backwards compatibility check, that we can upload charms to
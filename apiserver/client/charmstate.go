@@ -0,0 +1,45 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// UnitsCharmState returns, for each given unit, the key/value data its
+// charm has persisted between hooks with the state-set hook tool.
+func (c *ClientV2) UnitsCharmState(args params.Entities) (params.CharmStateResults, error) {
+	result := params.CharmStateResults{
+		Results: make([]params.CharmStateResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		charmState, err := c.unitCharmState(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].CharmState = charmState
+	}
+	return result, nil
+}
+
+func (c *Client) unitCharmState(tagString string) (map[string]string, error) {
+	tag, err := names.ParseUnitTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := c.api.stateAccessor.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	charmState, err := unit.CharmState()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charmState, nil
+}
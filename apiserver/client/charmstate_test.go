@@ -0,0 +1,48 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&charmStateSuite{})
+
+type charmStateSuite struct {
+	testing.BaseSuite
+	st  *mockState
+	api *client.ClientV2
+}
+
+func (s *charmStateSuite) SetUpTest(c *gc.C) {
+	s.st = &mockState{}
+	client.PatchState(s, s.st)
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("user")}
+	var err error
+	s.api, err = client.NewClientV2(nil, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *charmStateSuite) TestUnitsCharmState(c *gc.C) {
+	s.st.charmState = map[string]string{"initialised": "true"}
+
+	result, err := s.api.UnitsCharmState(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-unit-0"},
+		{Tag: "unit-unit-1"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].CharmState, jc.DeepEquals, map[string]string{"initialised": "true"})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "unit/1 not found")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid unit tag`)
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
@@ -26,6 +28,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 1, NewClient)
+	common.RegisterStandardFacade("Client", 2, NewClientV2)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -54,6 +57,13 @@ type Client struct {
 	check *common.BlockChecker
 }
 
+// ClientV2 serves version 2 of the client-specific API methods, which
+// adds staged agent upgrades and their rollback, hook execution
+// timings, secrets and charm state.
+type ClientV2 struct {
+	*Client
+}
+
 var getState = func(st *state.State) stateInterface {
 	return &stateShim{st}
 }
@@ -77,6 +87,15 @@ func NewClient(st *state.State, resources *common.Resources, authorizer common.A
 	return client, nil
 }
 
+// NewClientV2 creates a new instance of version 2 of the Client Facade.
+func NewClientV2(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ClientV2, error) {
+	client, err := NewClient(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV2{client}, nil
+}
+
 func (c *Client) WatchAll() (params.AllWatcherId, error) {
 	w := c.api.stateAccessor.Watch()
 	return params.AllWatcherId{
@@ -439,8 +458,137 @@ func (c *Client) ModelUnset(args params.ModelUnset) error {
 	return c.api.stateAccessor.UpdateModelConfig(nil, args.Keys, nil)
 }
 
-// SetModelAgentVersion sets the model agent version.
-func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) error {
+// SetModelAgentVersion sets the model agent version. If the upgrade
+// cannot be rolled back, the result says why.
+func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) (params.AgentVersionUpgradeResult, error) {
+	if err := c.checkCanChangeAgentVersion(); err != nil {
+		return params.AgentVersionUpgradeResult{}, errors.Trace(err)
+	}
+	return c.upgradeAgentVersion(args.Version, func() error {
+		return c.api.stateAccessor.SetModelAgentVersion(args.Version)
+	})
+}
+
+// StartUpgradeRollout sets the model agent version, but only releases
+// it to the given canary machines at first. The remaining machines
+// are released in batches as earlier ones complete their upgrades. If
+// the upgrade cannot be rolled back, the result says why.
+func (c *ClientV2) StartUpgradeRollout(args params.StartUpgradeRollout) (params.AgentVersionUpgradeResult, error) {
+	if err := c.checkCanChangeAgentVersion(); err != nil {
+		return params.AgentVersionUpgradeResult{}, errors.Trace(err)
+	}
+	canaries := make([]string, len(args.Canaries))
+	for i, canary := range args.Canaries {
+		tag, err := names.ParseMachineTag(canary)
+		if err != nil {
+			return params.AgentVersionUpgradeResult{}, errors.Trace(err)
+		}
+		canaries[i] = tag.Id()
+	}
+	return c.upgradeAgentVersion(args.Version, func() error {
+		_, err := c.api.stateAccessor.StartUpgradeRollout(state.UpgradeRolloutParams{
+			Version:   args.Version,
+			Canaries:  canaries,
+			BatchSize: args.BatchSize,
+		})
+		return errors.Trace(err)
+	})
+}
+
+// ResumeUpgradeRollout resumes a staged upgrade that was paused
+// because a machine failed to upgrade.
+func (c *ClientV2) ResumeUpgradeRollout() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.stateAccessor.ResumeUpgradeRollout()
+}
+
+// createPreUpgradeBackup creates a backup of the controller, and
+// returns its id.
+var createPreUpgradeBackup = backups.CreateLocal
+
+// upgradeAgentVersion calls setVersion to change the model's agent
+// version to target. If that is an upgrade, it is recorded so that it
+// can be rolled back; and if this is the controller model, a backup of
+// the controller is taken first, for the rollback to restore. Failing
+// to take the backup or to record the upgrade does not prevent the
+// upgrade, but the result tells the caller that it cannot be rolled
+// back.
+func (c *Client) upgradeAgentVersion(target version.Number, setVersion func() error) (params.AgentVersionUpgradeResult, error) {
+	var result params.AgentVersionUpgradeResult
+	cfg, err := c.api.stateAccessor.ModelConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	previous, _ := cfg.AgentVersion()
+	if target.Compare(previous) <= 0 {
+		return result, setVersion()
+	}
+	var backupId string
+	var noRollback error
+	if c.api.stateAccessor.IsController() {
+		notes := fmt.Sprintf("taken before upgrading from %s to %s", previous, target)
+		backupId, err = createPreUpgradeBackup(c.api.state(), c.api.resources, notes)
+		if err != nil {
+			noRollback = errors.Annotate(err, "cannot back up the controller")
+			backupId = ""
+		}
+	}
+	if err := setVersion(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := c.api.stateAccessor.RecordUpgrade(previous, target, backupId); err != nil {
+		noRollback = errors.Annotate(err, "cannot record the upgrade")
+	}
+	if noRollback != nil {
+		logger.Errorf("the upgrade to %s cannot be rolled back: %v", target, noRollback)
+		result.NoRollback = noRollback.Error()
+	}
+	return result, nil
+}
+
+// PrepareUpgradeRollback checks that the model's most recent agent
+// upgrade can be rolled back, and returns what is needed to do so.
+func (c *ClientV2) PrepareUpgradeRollback() (params.UpgradeRollbackInfo, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.UpgradeRollbackInfo{}, errors.Trace(err)
+	}
+	rollback, err := c.api.stateAccessor.CheckUpgradeRollback()
+	if err != nil {
+		return params.UpgradeRollbackInfo{}, errors.Trace(err)
+	}
+	return params.UpgradeRollbackInfo{
+		PreviousVersion: rollback.PreviousVersion(),
+		TargetVersion:   rollback.TargetVersion(),
+		BackupId:        rollback.BackupId(),
+	}, nil
+}
+
+// FinishUpgradeRollback sets the model's agent version back to the
+// version it ran before the given upgrade, so that its agents
+// downgrade. The controller is expected to have been restored from
+// the upgrade's backup first, if there is one.
+func (c *ClientV2) FinishUpgradeRollback(args params.UpgradeRollbackInfo) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	// An upgrade that is still recorded has not been undone by
+	// restoring a backup, so it must be checked again.
+	rollback, err := c.api.stateAccessor.UpgradeRollback()
+	if err == nil && rollback.TargetVersion() == args.TargetVersion && !rollback.RolledBack() {
+		if _, err := c.api.stateAccessor.CheckUpgradeRollback(); err != nil {
+			return errors.Trace(err)
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return c.api.stateAccessor.FinishUpgradeRollback(args.PreviousVersion, args.TargetVersion)
+}
+
+// checkCanChangeAgentVersion returns an error if the model's agent
+// version cannot be changed.
+func (c *Client) checkCanChangeAgentVersion() error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -454,10 +602,7 @@ func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := environs.CheckProviderAPI(env); err != nil {
-		return err
-	}
-	return c.api.stateAccessor.SetModelAgentVersion(args.Version)
+	return environs.CheckProviderAPI(env)
 }
 
 var getEnvironment = func(cfg *config.Config) (environs.Environ, error) {
@@ -46,7 +46,7 @@ type Killer interface {
 
 type serverSuite struct {
 	baseSuite
-	client *client.Client
+	client *client.ClientV2
 }
 
 var _ = gc.Suite(&serverSuite{})
@@ -59,7 +59,7 @@ func (s *serverSuite) SetUpTest(c *gc.C) {
 		Tag:            s.AdminUserTag(c),
 		EnvironManager: true,
 	}
-	s.client, err = client.NewClient(s.State, common.NewResources(), auth)
+	s.client, err = client.NewClientV2(s.State, common.NewResources(), auth)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -168,7 +168,7 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetModelAgentVersion{
 		Version: version.MustParse("9.8.7"),
 	}
-	err := s.client.SetModelAgentVersion(args)
+	_, err := s.client.SetModelAgentVersion(args)
 	c.Assert(err, jc.ErrorIsNil)
 
 	envConfig, err := s.State.ModelConfig()
@@ -178,6 +178,78 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	c.Assert(agentVersion, gc.Equals, "9.8.7")
 }
 
+func (s *serverSuite) TestSetModelAgentVersionRecordsUpgrade(c *gc.C) {
+	var notes string
+	s.PatchValue(client.CreatePreUpgradeBackup, func(_ *state.State, _ *common.Resources, n string) (string, error) {
+		notes = n
+		return "backup-id", nil
+	})
+	current := jujuversion.Current
+	result, err := s.client.SetModelAgentVersion(params.SetModelAgentVersion{
+		Version: version.MustParse("9.8.7"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NoRollback, gc.Equals, "")
+	c.Assert(notes, gc.Equals, "taken before upgrading from "+current.String()+" to 9.8.7")
+
+	rollback, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.PreviousVersion(), gc.Equals, current)
+	c.Assert(rollback.TargetVersion(), gc.Equals, version.MustParse("9.8.7"))
+	c.Assert(rollback.BackupId(), gc.Equals, "backup-id")
+}
+
+func (s *serverSuite) TestSetModelAgentVersionBackupFailure(c *gc.C) {
+	s.PatchValue(client.CreatePreUpgradeBackup, func(*state.State, *common.Resources, string) (string, error) {
+		return "", errors.New("disk full")
+	})
+	result, err := s.client.SetModelAgentVersion(params.SetModelAgentVersion{
+		Version: version.MustParse("9.8.7"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NoRollback, gc.Equals, "cannot back up the controller: disk full")
+
+	envConfig, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envConfig.AllAttrs()["agent-version"], gc.Equals, "9.8.7")
+	rollback, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.BackupId(), gc.Equals, "")
+}
+
+func (s *serverSuite) TestUpgradeRollback(c *gc.C) {
+	_, err := s.client.PrepareUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "no upgrade to roll back")
+
+	current := jujuversion.Current
+	_, err = s.client.SetModelAgentVersion(params.SetModelAgentVersion{
+		Version: version.MustParse("9.8.7"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.client.PrepareUpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, params.UpgradeRollbackInfo{
+		PreviousVersion: current,
+		TargetVersion:   version.MustParse("9.8.7"),
+		BackupId:        "backup-id",
+	})
+
+	err = s.client.FinishUpgradeRollback(info)
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envConfig.AllAttrs()["agent-version"], gc.Equals, current.String())
+
+	_, err = s.client.PrepareUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to 9.8.7: upgrade has already been rolled back")
+}
+
+func (s *serverSuite) TestBlockChangesUpgradeRollback(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesUpgradeRollback")
+	_, err := s.client.PrepareUpgradeRollback()
+	s.AssertBlocked(c, err, "TestBlockChangesUpgradeRollback")
+}
+
 type mockEnviron struct {
 	environs.Environ
 	allInstancesCalled bool
@@ -197,7 +269,7 @@ func (s *serverSuite) assertCheckProviderAPI(c *gc.C, envError error, expectErr
 	args := params.SetModelAgentVersion{
 		Version: version.MustParse("9.8.7"),
 	}
-	err := s.client.SetModelAgentVersion(args)
+	_, err := s.client.SetModelAgentVersion(args)
 	c.Assert(env.allInstancesCalled, jc.IsTrue)
 	if expectErr != "" {
 		c.Assert(err, gc.ErrorMatches, expectErr)
@@ -220,7 +292,7 @@ func (s *serverSuite) assertSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetModelAgentVersion{
 		Version: version.MustParse("9.8.7"),
 	}
-	err := s.client.SetModelAgentVersion(args)
+	_, err := s.client.SetModelAgentVersion(args)
 	c.Assert(err, jc.ErrorIsNil)
 	envConfig, err := s.State.ModelConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -233,7 +305,7 @@ func (s *serverSuite) assertSetEnvironAgentVersionBlocked(c *gc.C, msg string) {
 	args := params.SetModelAgentVersion{
 		Version: version.MustParse("9.8.7"),
 	}
-	err := s.client.SetModelAgentVersion(args)
+	_, err := s.client.SetModelAgentVersion(args)
 	s.AssertBlocked(c, err, msg)
 }
 
@@ -252,6 +324,55 @@ func (s *serverSuite) TestBlockChangesSetEnvironAgentVersion(c *gc.C) {
 	s.assertSetEnvironAgentVersionBlocked(c, "TestBlockChangesSetEnvironAgentVersion")
 }
 
+func (s *serverSuite) TestStartUpgradeRollout(c *gc.C) {
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	current, _ := cfg.AgentVersion()
+	for i := 0; i < 2; i++ {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetAgentVersion(version.Binary{Number: current, Series: "quantal", Arch: "amd64"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err = s.client.StartUpgradeRollout(params.StartUpgradeRollout{
+		Version:   version.MustParse("9.8.7"),
+		Canaries:  []string{"machine-1"},
+		BatchSize: 5,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollout, err := s.State.UpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Version(), gc.Equals, version.MustParse("9.8.7"))
+	c.Assert(rollout.Canaries(), jc.DeepEquals, []string{"1"})
+	c.Assert(rollout.BatchSize(), gc.Equals, 5)
+	cfg, err = s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["agent-version"], gc.Equals, "9.8.7")
+
+	err = s.client.ResumeUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "upgrade rollout to 9.8.7 is running, not paused")
+}
+
+func (s *serverSuite) TestStartUpgradeRolloutBadCanary(c *gc.C) {
+	_, err := s.client.StartUpgradeRollout(params.StartUpgradeRollout{
+		Version:   version.MustParse("9.8.7"),
+		Canaries:  []string{"unit-foo-0"},
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+}
+
+func (s *serverSuite) TestBlockChangesStartUpgradeRollout(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockChangesStartUpgradeRollout")
+	_, err := s.client.StartUpgradeRollout(params.StartUpgradeRollout{
+		Version:   version.MustParse("9.8.7"),
+		Canaries:  []string{"machine-0"},
+		BatchSize: 1,
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesStartUpgradeRollout")
+}
+
 func (s *serverSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	// Create a provisioned controller.
 	machine, err := s.State.AddMachine("series", state.JobManageModel)
@@ -20,7 +20,8 @@ var (
 type MachineAndContainers machineAndContainers
 
 var (
-	GetEnvironment = &getEnvironment
+	GetEnvironment         = &getEnvironment
+	CreatePreUpgradeBackup = &createPreUpgradeBackup
 )
 
 type StateInterface stateInterface
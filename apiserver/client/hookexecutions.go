@@ -0,0 +1,57 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// HookExecutions returns, for each given unit, the most recent hook,
+// action and juju-run executions recorded by its agent, most recent
+// first.
+func (c *ClientV2) HookExecutions(args params.Entities) (params.HookExecutionsResults, error) {
+	result := params.HookExecutionsResults{
+		Results: make([]params.HookExecutionsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		executions, err := c.unitHookExecutions(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Executions = executions
+	}
+	return result, nil
+}
+
+func (c *Client) unitHookExecutions(tagString string) ([]params.HookExecution, error) {
+	tag, err := names.ParseUnitTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unit, err := c.api.stateAccessor.Unit(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	executions, err := unit.HookExecutions()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]params.HookExecution, len(executions))
+	for i, execution := range executions {
+		results[i] = params.HookExecution{
+			Kind:      execution.Kind,
+			Name:      execution.Name,
+			Started:   execution.Started,
+			Duration:  execution.Duration,
+			ExitCode:  execution.ExitCode,
+			ToolCalls: execution.ToolCalls,
+		}
+	}
+	return results, nil
+}
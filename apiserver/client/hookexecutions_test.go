@@ -0,0 +1,76 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&hookExecutionsSuite{})
+
+type hookExecutionsSuite struct {
+	testing.BaseSuite
+	st  *mockState
+	api *client.ClientV2
+}
+
+func (s *hookExecutionsSuite) SetUpTest(c *gc.C) {
+	s.st = &mockState{}
+	client.PatchState(s, s.st)
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("user")}
+	var err error
+	s.api, err = client.NewClientV2(nil, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *hookExecutionsSuite) TestHookExecutions(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.st.hookExecutions = []state.HookExecution{{
+		Kind:      "hook",
+		Name:      "update-status",
+		Started:   started,
+		Duration:  2 * time.Second,
+		ExitCode:  0,
+		ToolCalls: map[string]int{"status-set": 1},
+	}, {
+		Kind:     "juju-run",
+		Started:  started.Add(-time.Minute),
+		Duration: time.Second,
+		ExitCode: 1,
+	}}
+
+	result, err := s.api.HookExecutions(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-unit-0"},
+		{Tag: "unit-unit-1"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Executions, jc.DeepEquals, []params.HookExecution{{
+		Kind:      "hook",
+		Name:      "update-status",
+		Started:   started,
+		Duration:  2 * time.Second,
+		ExitCode:  0,
+		ToolCalls: map[string]int{"status-set": 1},
+	}, {
+		Kind:     "juju-run",
+		Started:  started.Add(-time.Minute),
+		Duration: time.Second,
+		ExitCode: 1,
+	}})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "unit/1 not found")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid unit tag`)
+}
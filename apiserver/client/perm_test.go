@@ -434,7 +434,7 @@ func opClientSetEnvironAgentVersion(c *gc.C, st api.Connection, mst *state.State
 	if err != nil {
 		return func() {}, err
 	}
-	err = st.Client().SetModelAgentVersion(jujuversion.Current)
+	_, err = st.Client().SetModelAgentVersion(jujuversion.Current)
 	if err != nil {
 		return func() {}, err
 	}
@@ -0,0 +1,97 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ListSecrets returns the details of every secret in the model. Secret
+// values are never returned.
+func (c *ClientV2) ListSecrets() (params.SecretDetailsResults, error) {
+	secrets, err := c.api.stateAccessor.AllSecrets()
+	if err != nil {
+		return params.SecretDetailsResults{}, errors.Trace(err)
+	}
+	result := params.SecretDetailsResults{
+		Results: make([]params.SecretDetailsResult, len(secrets)),
+	}
+	for i, secret := range secrets {
+		details, err := secretDetails(secret, false)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = details
+	}
+	return result, nil
+}
+
+// ShowSecrets returns the details, including the revision history, of
+// each given secret. Secret values are never returned.
+func (c *ClientV2) ShowSecrets(args params.SecretIds) (params.SecretDetailsResults, error) {
+	result := params.SecretDetailsResults{
+		Results: make([]params.SecretDetailsResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		details, err := c.showSecret(id)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = details
+	}
+	return result, nil
+}
+
+func (c *ClientV2) showSecret(id string) (*params.SecretDetails, error) {
+	secret, err := c.api.stateAccessor.Secret(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return secretDetails(secret, true)
+}
+
+// secretDetails returns the details of the secret, optionally with its
+// revision history.
+func secretDetails(secret Secret, withRevisions bool) (*params.SecretDetails, error) {
+	grants, err := secret.Grants()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details := &params.SecretDetails{
+		Id:             secret.Id(),
+		OwnerTag:       secret.Owner().String(),
+		Description:    secret.Description(),
+		RotateInterval: secret.RotateInterval(),
+		Revision:       secret.Revision(),
+		Keys:           secret.Keys(),
+		Created:        secret.Created(),
+		Updated:        secret.Updated(),
+	}
+	if next, ok := secret.NextRotation(); ok {
+		details.NextRotation = &next
+	}
+	for _, grant := range grants {
+		details.Grants = append(details.Grants, grant.String())
+	}
+	if !withRevisions {
+		return details, nil
+	}
+	revisions, err := secret.Revisions()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, revision := range revisions {
+		details.Revisions = append(details.Revisions, params.SecretRevision{
+			Revision: revision.Revision,
+			Keys:     revision.Keys,
+			Created:  revision.Created,
+		})
+	}
+	return details, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&secretsSuite{})
+
+type secretsSuite struct {
+	testing.BaseSuite
+	st  *mockState
+	api *client.ClientV2
+}
+
+var secretTime = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *secretsSuite) SetUpTest(c *gc.C) {
+	s.st = &mockState{
+		secrets: []client.Secret{
+			&mockSecret{
+				id:          "0",
+				owner:       names.NewServiceTag("mysql"),
+				description: "root credentials",
+				rotate:      time.Hour,
+				revisions: []state.SecretRevision{
+					{Revision: 1, Keys: []string{"password"}, Created: secretTime},
+					{Revision: 2, Keys: []string{"password", "user"}, Created: secretTime.Add(time.Minute)},
+				},
+				grants: []names.Tag{names.NewUnitTag("wordpress/0")},
+			},
+			&mockSecret{
+				id:    "1",
+				owner: names.NewServiceTag("wordpress"),
+				revisions: []state.SecretRevision{
+					{Revision: 1, Keys: []string{"token"}, Created: secretTime},
+				},
+			},
+		},
+	}
+	client.PatchState(s, s.st)
+	authorizer := &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("user")}
+	var err error
+	s.api, err = client.NewClientV2(nil, nil, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestListSecrets(c *gc.C) {
+	result, err := s.api.ListSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	next := secretTime.Add(time.Minute + time.Hour)
+	c.Assert(result.Results, jc.DeepEquals, []params.SecretDetailsResult{{
+		Result: &params.SecretDetails{
+			Id:             "0",
+			OwnerTag:       "service-mysql",
+			Description:    "root credentials",
+			RotateInterval: time.Hour,
+			NextRotation:   &next,
+			Revision:       2,
+			Keys:           []string{"password", "user"},
+			Grants:         []string{"unit-wordpress-0"},
+			Created:        secretTime,
+			Updated:        secretTime.Add(time.Minute),
+		},
+	}, {
+		Result: &params.SecretDetails{
+			Id:       "1",
+			OwnerTag: "service-wordpress",
+			Revision: 1,
+			Keys:     []string{"token"},
+			Created:  secretTime,
+			Updated:  secretTime,
+		},
+	}})
+}
+
+func (s *secretsSuite) TestShowSecrets(c *gc.C) {
+	result, err := s.api.ShowSecrets(params.SecretIds{Ids: []string{"1", "2"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result, jc.DeepEquals, &params.SecretDetails{
+		Id:       "1",
+		OwnerTag: "service-wordpress",
+		Revision: 1,
+		Keys:     []string{"token"},
+		Created:  secretTime,
+		Updated:  secretTime,
+		Revisions: []params.SecretRevision{
+			{Revision: 1, Keys: []string{"token"}, Created: secretTime},
+		},
+	})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `secret "2" not found`)
+	c.Assert(result.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (m *mockState) Secret(id string) (client.Secret, error) {
+	for _, secret := range m.secrets {
+		if secret.Id() == id {
+			return secret, nil
+		}
+	}
+	return nil, errors.NotFoundf("secret %q", id)
+}
+
+func (m *mockState) AllSecrets() ([]client.Secret, error) {
+	return m.secrets, nil
+}
+
+type mockSecret struct {
+	client.Secret
+	id          string
+	owner       names.ServiceTag
+	description string
+	rotate      time.Duration
+	revisions   []state.SecretRevision
+	grants      []names.Tag
+}
+
+func (s *mockSecret) Id() string                    { return s.id }
+func (s *mockSecret) Owner() names.ServiceTag       { return s.owner }
+func (s *mockSecret) Description() string           { return s.description }
+func (s *mockSecret) RotateInterval() time.Duration { return s.rotate }
+func (s *mockSecret) Revision() int                 { return len(s.revisions) }
+func (s *mockSecret) Created() time.Time            { return s.revisions[0].Created }
+func (s *mockSecret) Grants() ([]names.Tag, error)  { return s.grants, nil }
+
+func (s *mockSecret) Keys() []string {
+	return s.revisions[len(s.revisions)-1].Keys
+}
+
+func (s *mockSecret) Updated() time.Time {
+	return s.revisions[len(s.revisions)-1].Created
+}
+
+func (s *mockSecret) NextRotation() (time.Time, bool) {
+	if s.rotate == 0 {
+		return time.Time{}, false
+	}
+	return s.Updated().Add(s.rotate), true
+}
+
+func (s *mockSecret) Revisions() ([]state.SecretRevision, error) {
+	return s.revisions, nil
+}
@@ -4,6 +4,8 @@
 package client
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -26,6 +28,24 @@ type Unit interface {
 	PrivateAddress() (network.Address, error)
 	Resolve(retryHooks bool) error
 	AgentHistory() status.StatusHistoryGetter
+	WorkloadVersionHistory(size int) ([]status.StatusInfo, error)
+	HookExecutions() ([]state.HookExecution, error)
+	CharmState() (map[string]string, error)
+}
+
+// Secret represents a state.Secret.
+type Secret interface {
+	Id() string
+	Owner() names.ServiceTag
+	Description() string
+	RotateInterval() time.Duration
+	NextRotation() (time.Time, bool)
+	Revision() int
+	Keys() []string
+	Created() time.Time
+	Updated() time.Time
+	Grants() ([]names.Tag, error)
+	Revisions() ([]state.SecretRevision, error)
 }
 
 // stateInterface contains the state.State methods used in this package,
@@ -50,6 +70,14 @@ type stateInterface interface {
 	Model() (*state.Model, error)
 	ForModel(tag names.ModelTag) (*state.State, error)
 	SetModelAgentVersion(version.Number) error
+	UpgradeRollout() (*state.UpgradeRollout, error)
+	StartUpgradeRollout(state.UpgradeRolloutParams) (*state.UpgradeRollout, error)
+	ResumeUpgradeRollout() error
+	RecordUpgrade(previous, target version.Number, backupId string) error
+	UpgradeRollback() (*state.UpgradeRollback, error)
+	CheckUpgradeRollback() (*state.UpgradeRollback, error)
+	FinishUpgradeRollback(previous, target version.Number) error
+	IsController() bool
 	SetAnnotations(state.GlobalEntity, map[string]string) error
 	Annotations(state.GlobalEntity) (map[string]string, error)
 	InferEndpoints(...string) ([]state.Endpoint, error)
@@ -62,6 +90,8 @@ type stateInterface interface {
 	Watch() *state.Multiwatcher
 	AbortCurrentUpgrade() error
 	APIHostPorts() ([][]network.HostPort, error)
+	Secret(string) (Secret, error)
+	AllSecrets() ([]Secret, error)
 }
 
 type stateShim struct {
@@ -75,3 +105,23 @@ func (s *stateShim) Unit(name string) (Unit, error) {
 	}
 	return u, nil
 }
+
+func (s *stateShim) Secret(id string) (Secret, error) {
+	secret, err := s.State.Secret(id)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (s *stateShim) AllSecrets() ([]Secret, error) {
+	secrets, err := s.State.AllSecrets()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Secret, len(secrets))
+	for i, secret := range secrets {
+		results[i] = secret
+	}
+	return results, nil
+}
@@ -70,6 +70,13 @@ func (c *Client) unitStatusHistory(unitName string, size int, kind params.Histor
 		}
 		statuses = append(statuses, agentStatusFromStatusInfo(agentStatuses, params.KindUnitAgent)...)
 	}
+	if kind == params.KindWorkloadVersion {
+		versions, err := unit.WorkloadVersionHistory(size)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		statuses = agentStatusFromStatusInfo(versions, params.KindWorkloadVersion)
+	}
 
 	sort.Sort(sortableStatuses(statuses))
 	if kind == params.KindUnit {
@@ -116,7 +123,7 @@ func (c *Client) StatusHistory(args params.StatusHistoryArgs) (params.StatusHist
 	statuses := []params.DetailedStatus{}
 	var err error
 	switch args.Kind {
-	case params.KindUnit, params.KindWorkload, params.KindUnitAgent:
+	case params.KindUnit, params.KindWorkload, params.KindUnitAgent, params.KindWorkloadVersion:
 		statuses, err = c.unitStatusHistory(args.Name, args.Size, args.Kind)
 		if err != nil {
 			return params.StatusHistoryResults{}, errors.Annotatef(err, "fetching unit status history for %q", args.Name)
@@ -263,15 +270,54 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if err != nil {
 		return noStatus, errors.Annotate(err, "cannot determine mongo information")
 	}
+	rollout, err := c.upgradeRolloutStatus()
+	if err != nil {
+		return noStatus, errors.Annotate(err, "cannot determine upgrade rollout status")
+	}
 	return params.FullStatus{
 		ModelName:        cfg.Name(),
 		AvailableVersion: newToolsVersion,
+		UpgradeRollout:   rollout,
 		Machines:         processMachines(context.machines),
 		Services:         context.processServices(),
 		Relations:        context.processRelations(),
 	}, nil
 }
 
+// upgradeRolloutStatus returns the progress of the model's staged
+// agent upgrade, or nil if there is none in progress.
+func (c *Client) upgradeRolloutStatus() (*params.UpgradeRolloutStatus, error) {
+	rollout, err := c.api.stateAccessor.UpgradeRollout()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !rollout.Active() {
+		return nil, nil
+	}
+	machines, err := c.api.stateAccessor.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.UpgradeRolloutStatus{
+		Version:  rollout.Version().String(),
+		Status:   string(rollout.Status()),
+		Message:  rollout.Message(),
+		Canaries: rollout.Canaries(),
+	}
+	for _, m := range machines {
+		if m.IsManager() || m.Life() == state.Dead {
+			continue
+		}
+		result.Total++
+		if rollout.IsReleased(m.Id()) {
+			result.Released++
+		}
+	}
+	return result, nil
+}
+
 // newToolsVersionAvailable will return a string representing a tools
 // version only if the latest check is newer than current tools.
 func (c *Client) newToolsVersionAvailable() (string, error) {
@@ -578,6 +624,8 @@ func (context *statusContext) processService(service *state.Service) params.Serv
 		}
 	}
 
+	processedStatus.WorkloadVersion = commonWorkloadVersion(context.units[service.Name()])
+
 	var err error
 	processedStatus.Relations, processedStatus.SubordinateTo, err = context.processServiceRelations(service)
 	if err != nil {
@@ -601,6 +649,30 @@ func (context *statusContext) processService(service *state.Service) params.Serv
 	return processedStatus
 }
 
+// commonWorkloadVersion returns the workload version set by most of the
+// given units. Ties go to the greatest version, so that the result is
+// stable.
+func commonWorkloadVersion(units map[string]*state.Unit) string {
+	counts := make(map[string]int)
+	for _, unit := range units {
+		version, err := unit.WorkloadVersion()
+		if err != nil {
+			logger.Debugf("error fetching workload version of %q: %v", unit.Name(), err)
+			continue
+		}
+		if version != "" {
+			counts[version]++
+		}
+	}
+	var result string
+	for version, count := range counts {
+		if count > counts[result] || count == counts[result] && version > result {
+			result = version
+		}
+	}
+	return result
+}
+
 func isColorStatus(code state.MeterStatusCode) bool {
 	return code == state.MeterGreen || code == state.MeterAmber || code == state.MeterRed
 }
@@ -652,6 +724,17 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		result.Charm = curl.String()
 	}
 	processUnitAndAgentStatus(unit, &result)
+	version, err := unit.WorkloadVersion()
+	if err != nil {
+		logger.Debugf("error fetching workload version: %v", err)
+	}
+	result.WorkloadVersion = version
+	health, err := unit.Health()
+	if err != nil {
+		logger.Debugf("error fetching unit health: %v", err)
+	} else if health.Status != "" {
+		populateStatusFromStatusInfoAndErr(&result.Health, health, nil)
+	}
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]params.UnitStatus)
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/testing"
 )
@@ -106,6 +107,35 @@ func (s *statusHistoryTestSuite) TestStatusHistoryUnitOnly(c *gc.C) {
 	checkStatusInfo(c, h.Statuses, reverseStatusInfo(s.st.unitHistory))
 }
 
+func (s *statusHistoryTestSuite) TestStatusHistoryWorkloadVersion(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.StatusActive,
+			Message: "running",
+		},
+	})
+	s.st.versionHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.StatusActive,
+			Message: "9.5.3",
+		},
+		{
+			Status:  status.StatusActive,
+			Message: "9.4.8",
+		},
+	})
+	h, err := s.api.StatusHistory(params.StatusHistoryArgs{
+		Name: "unit/0",
+		Kind: params.KindWorkloadVersion,
+		Size: 10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	checkStatusInfo(c, h.Statuses, reverseStatusInfo(s.st.versionHistory))
+	for _, status := range h.Statuses {
+		c.Check(status.Kind, gc.Equals, params.KindWorkloadVersion)
+	}
+}
+
 func (s *statusHistoryTestSuite) TestStatusHistoryAgentOnly(c *gc.C) {
 	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
 		{
@@ -173,8 +203,12 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 
 type mockState struct {
 	client.StateInterface
-	unitHistory  []status.StatusInfo
-	agentHistory []status.StatusInfo
+	unitHistory    []status.StatusInfo
+	agentHistory   []status.StatusInfo
+	versionHistory []status.StatusInfo
+	hookExecutions []state.HookExecution
+	charmState     map[string]string
+	secrets        []client.Secret
 }
 
 func (m *mockState) ModelUUID() string {
@@ -186,21 +220,39 @@ func (m *mockState) Unit(name string) (client.Unit, error) {
 		return nil, errors.NotFoundf("%v", name)
 	}
 	return &mockUnit{
-		status: m.unitHistory,
-		agent:  &mockUnitAgent{m.agentHistory},
+		status:     m.unitHistory,
+		agent:      &mockUnitAgent{m.agentHistory},
+		versions:   m.versionHistory,
+		executions: m.hookExecutions,
+		charmState: m.charmState,
 	}, nil
 }
 
 type mockUnit struct {
-	status statuses
-	agent  *mockUnitAgent
+	status     statuses
+	agent      *mockUnitAgent
+	versions   statuses
+	executions []state.HookExecution
+	charmState map[string]string
 	client.Unit
 }
 
+func (m *mockUnit) HookExecutions() ([]state.HookExecution, error) {
+	return m.executions, nil
+}
+
+func (m *mockUnit) CharmState() (map[string]string, error) {
+	return m.charmState, nil
+}
+
 func (m *mockUnit) StatusHistory(size int) ([]status.StatusInfo, error) {
 	return m.status.StatusHistory(size)
 }
 
+func (m *mockUnit) WorkloadVersionHistory(size int) ([]status.StatusInfo, error) {
+	return m.versions.StatusHistory(size)
+}
+
 func (m *mockUnit) AgentHistory() status.StatusHistoryGetter {
 	return m.agent
 }
@@ -141,6 +141,14 @@ func (s *stateShim) AllSubnets() ([]BackingSubnet, error) {
 	return subnets, nil
 }
 
+func (s *stateShim) SetSubnetSpace(cidr, spaceName string) error {
+	subnet, err := s.st.Subnet(cidr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return subnet.SetSpaceName(spaceName)
+}
+
 type availZoneShim struct{}
 
 func (availZoneShim) Name() string    { return "not-set" }
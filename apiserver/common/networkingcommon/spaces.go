@@ -15,7 +15,8 @@ import (
 )
 
 // SupportsSpaces checks if the environment implements NetworkingEnviron
-// and also if it supports spaces.
+// and also if it supports spaces. Models with Juju-managed spaces
+// always support spaces.
 func SupportsSpaces(backing environ.ConfigGetter) error {
 	config, err := backing.ModelConfig()
 	if err != nil {
@@ -25,11 +26,20 @@ func SupportsSpaces(backing environ.ConfigGetter) error {
 	if err != nil {
 		return errors.Annotate(err, "validating model config")
 	}
+	err = supportsSpaces(env)
+	if errors.IsNotSupported(err) && config.JujuManagedSpaces() {
+		logger.Debugf("provider %v, using Juju-managed spaces", err)
+		return nil
+	}
+	return err
+}
+
+func supportsSpaces(env environs.Environ) error {
 	netEnv, ok := environs.SupportsNetworking(env)
 	if !ok {
 		return errors.NotSupportedf("networking")
 	}
-	ok, err = netEnv.SupportsSpaces()
+	ok, err := netEnv.SupportsSpaces()
 	if !ok {
 		if err != nil && !errors.IsNotSupported(err) {
 			logger.Warningf("checking model spaces support failed with: %v", err)
@@ -210,6 +210,23 @@ func (s *SpacesSuite) TestSuppportsSpacesWithoutNetworking(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 }
 
+func (s *SpacesSuite) TestSuppportsSpacesWithJujuManagedSpaces(c *gc.C) {
+	apiservertesting.BackingInstance.SetUp(
+		c,
+		apiservertesting.StubEnvironName,
+		apiservertesting.WithoutZones,
+		apiservertesting.WithoutSpaces,
+		apiservertesting.WithoutSubnets)
+	cfg, err := apiservertesting.BackingInstance.EnvConfig.Apply(map[string]interface{}{
+		"juju-managed-spaces": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	apiservertesting.BackingInstance.EnvConfig = cfg
+
+	err = networkingcommon.SupportsSpaces(apiservertesting.BackingInstance)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *SpacesSuite) TestSuppportsSpacesWithoutSpaces(c *gc.C) {
 	apiservertesting.BackingInstance.SetUp(
 		c,
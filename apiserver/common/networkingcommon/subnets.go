@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	providercommon "github.com/juju/juju/provider/common"
@@ -36,6 +37,11 @@ type addSubnetsCache struct {
 	// subnetsByProviderId maps unique subnet ProviderIds to pointers
 	// to entries in allSubnets.
 	subnetsByProviderId map[string]*network.SubnetInfo
+	// jujuManagedSpaces is true when the provider does not support
+	// networking and the model uses Juju-managed spaces instead, in
+	// which case knownCIDRs holds the CIDRs of all backing subnets.
+	jujuManagedSpaces bool
+	knownCIDRs        set.Strings
 }
 
 func NewAddSubnetsCache(api NetworkBacking) *addSubnetsCache {
@@ -190,8 +196,10 @@ func (cache *addSubnetsCache) cacheSubnets() error {
 		return nil
 	}
 
-	netEnv, err := networkingEnviron(cache.api)
-	if err != nil {
+	netEnv, envConfig, err := networkingEnvironAndConfig(cache.api)
+	if errors.IsNotSupported(err) && envConfig != nil && envConfig.JujuManagedSpaces() {
+		return cache.cacheBackingSubnets()
+	} else if err != nil {
 		return errors.Trace(err)
 	}
 	subnetInfo, err := netEnv.Subnets(instance.UnknownId, nil)
@@ -257,6 +265,23 @@ func (cache *addSubnetsCache) cacheSubnets() error {
 	return nil
 }
 
+// cacheBackingSubnets caches the CIDRs of all backing subnets, which
+// are the only subnets known with Juju-managed spaces.
+func (cache *addSubnetsCache) cacheBackingSubnets() error {
+	subnets, err := cache.api.AllSubnets()
+	if err != nil {
+		return errors.Annotate(err, "cannot get subnets")
+	}
+	cache.jujuManagedSpaces = true
+	cache.allSubnets = []network.SubnetInfo{}
+	cache.knownCIDRs = set.NewStrings()
+	for _, subnet := range subnets {
+		cache.knownCIDRs.Add(subnet.CIDR())
+	}
+	logger.Tracef("%d backing subnets cached", cache.knownCIDRs.Size())
+	return nil
+}
+
 // validateSubnet ensures either subnetTag or providerId is valid (not both),
 // then uses the cache to validate and lookup the provider SubnetInfo for the
 // subnet, if found.
@@ -283,6 +308,13 @@ func (cache *addSubnetsCache) validateSubnet(subnetTag, providerId string) (*net
 		return nil, errors.Trace(err)
 	}
 
+	if cache.jujuManagedSpaces {
+		if !haveTag {
+			return nil, errors.NotSupportedf("SubnetProviderId with Juju-managed spaces")
+		}
+		return &network.SubnetInfo{CIDR: tag.Id()}, nil
+	}
+
 	if haveTag {
 		providerIds, ok := cache.providerIdsByCIDR[tag.Id()]
 		if !ok || providerIds.IsEmpty() {
@@ -341,6 +373,9 @@ func addOneSubnet(api NetworkBacking, args params.AddSubnetParams, cache *addSub
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if cache.jujuManagedSpaces {
+		return addOneJujuManagedSubnet(api, subnetInfo.CIDR, spaceTag.Id(), args.Zones, cache)
+	}
 	zones, err := cache.validateZones(subnetInfo.AvailabilityZones, args.Zones)
 	if err != nil {
 		return errors.Trace(err)
@@ -366,6 +401,24 @@ func addOneSubnet(api NetworkBacking, args params.AddSubnetParams, cache *addSub
 	return nil
 }
 
+// addOneJujuManagedSubnet assigns the subnet with the given CIDR to
+// the space, adding the subnet first if it has not been discovered.
+func addOneJujuManagedSubnet(api NetworkBacking, cidr, spaceName string, zones []string, cache *addSubnetsCache) error {
+	if cache.knownCIDRs.Contains(cidr) {
+		return errors.Trace(api.SetSubnetSpace(cidr, spaceName))
+	}
+	backingInfo := BackingSubnetInfo{
+		CIDR:              cidr,
+		AvailabilityZones: zones,
+		SpaceName:         spaceName,
+	}
+	if _, err := api.AddSubnet(backingInfo); err != nil {
+		return errors.Trace(err)
+	}
+	cache.knownCIDRs.Add(cidr)
+	return nil
+}
+
 // AddSubnets adds.
 func AddSubnets(api NetworkBacking, args params.AddSubnetsParams) (params.ErrorResults, error) {
 	results := params.ErrorResults{
@@ -439,19 +492,27 @@ func ListSubnets(api NetworkBacking, args params.SubnetsFilters) (results params
 // environs.Networking, an error satisfying errors.IsNotSupported() will be
 // returned.
 func networkingEnviron(api NetworkBacking) (environs.NetworkingEnviron, error) {
+	netEnv, _, err := networkingEnvironAndConfig(api)
+	return netEnv, err
+}
+
+// networkingEnvironAndConfig is like networkingEnviron, but also
+// returns the model config, which is set even when the model does not
+// support environs.Networking.
+func networkingEnvironAndConfig(api NetworkBacking) (environs.NetworkingEnviron, *config.Config, error) {
 	envConfig, err := api.ModelConfig()
 	if err != nil {
-		return nil, errors.Annotate(err, "getting model config")
+		return nil, nil, errors.Annotate(err, "getting model config")
 	}
 
 	env, err := environs.New(envConfig)
 	if err != nil {
-		return nil, errors.Annotate(err, "opening model")
+		return nil, nil, errors.Annotate(err, "opening model")
 	}
 	if netEnv, ok := environs.SupportsNetworking(env); ok {
-		return netEnv, nil
+		return netEnv, envConfig, nil
 	}
-	return nil, errors.NotSupportedf("model networking features") // " not supported"
+	return nil, envConfig, errors.NotSupportedf("model networking features") // " not supported"
 }
 
 // AllZones is defined on the API interface.
@@ -728,6 +728,56 @@ func (s *SubnetsSuite) TestAddSubnetsWhenNetworkingEnvironNotSupported(c *gc.C)
 	)
 }
 
+func (s *SubnetsSuite) TestAddSubnetsWithJujuManagedSpaces(c *gc.C) {
+	apiservertesting.BackingInstance.SetUp(
+		c,
+		apiservertesting.StubEnvironName,
+		apiservertesting.WithoutZones,
+		apiservertesting.WithSpaces,
+		apiservertesting.WithSubnets)
+	cfg, err := apiservertesting.BackingInstance.EnvConfig.Apply(map[string]interface{}{
+		"juju-managed-spaces": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	apiservertesting.BackingInstance.EnvConfig = cfg
+
+	results, err := networkingcommon.AddSubnets(apiservertesting.BackingInstance, params.AddSubnetsParams{
+		Subnets: []params.AddSubnetParams{{
+			SubnetTag: "subnet-10.10.0.0/24",
+			SpaceTag:  "space-dmz",
+		}, {
+			SubnetTag: "subnet-10.30.0.0/24",
+			SpaceTag:  "space-private",
+			Zones:     []string{"zone1"},
+		}, {
+			SubnetProviderId: "sn-foo",
+			SpaceTag:         "space-dmz",
+		}, {
+			SubnetTag: "subnet-10.10.0.0/24",
+			SpaceTag:  "space-missing",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, "SubnetProviderId with Juju-managed spaces not supported")
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `space "missing" not found`)
+
+	apiservertesting.CheckMethodCalls(c, apiservertesting.SharedStub,
+		apiservertesting.BackingCall("ModelConfig"),
+		apiservertesting.ProviderCall("Open", cfg),
+		apiservertesting.BackingCall("AllSubnets"),
+		apiservertesting.BackingCall("AllSpaces"),
+		apiservertesting.BackingCall("SetSubnetSpace", "10.10.0.0/24", "dmz"),
+		apiservertesting.BackingCall("AddSubnet", networkingcommon.BackingSubnetInfo{
+			CIDR:              "10.30.0.0/24",
+			AvailabilityZones: []string{"zone1"},
+			SpaceName:         "private",
+		}),
+	)
+}
+
 func (s *SubnetsSuite) TestListSubnetsAndFiltering(c *gc.C) {
 	expected := []params.Subnet{{
 		CIDR:       "10.10.0.0/24",
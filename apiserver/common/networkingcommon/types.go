@@ -136,6 +136,10 @@ type NetworkBacking interface {
 
 	// AllSubnets returns all backing subnets.
 	AllSubnets() ([]BackingSubnet, error)
+
+	// SetSubnetSpace associates the backing subnet with the given CIDR
+	// with the named space.
+	SetSubnetSpace(cidr, spaceName string) error
 }
 
 func BackingSubnetToParamsSubnet(subnet BackingSubnet) params.Subnet {
@@ -156,8 +156,23 @@ func FilesystemAttachmentFromState(v state.FilesystemAttachment) (params.Filesys
 // to params.FilesystemAttachmentInfo.
 func FilesystemAttachmentInfoFromState(info state.FilesystemAttachmentInfo) params.FilesystemAttachmentInfo {
 	return params.FilesystemAttachmentInfo{
-		info.MountPoint,
-		info.ReadOnly,
+		MountPoint: info.MountPoint,
+		ReadOnly:   info.ReadOnly,
+	}
+}
+
+// FilesystemUsageFromState converts a state.FilesystemUsage
+// to params.FilesystemUsage.
+func FilesystemUsageFromState(usage state.FilesystemUsage) *params.FilesystemUsage {
+	updated := usage.Updated
+	return &params.FilesystemUsage{
+		Size:       usage.Size,
+		Used:       usage.Used,
+		Available:  usage.Available,
+		Inodes:     usage.Inodes,
+		InodesUsed: usage.InodesUsed,
+		InodesFree: usage.InodesFree,
+		Updated:    &updated,
 	}
 }
 
@@ -4,6 +4,9 @@
 package diskmanager
 
 import (
+	"path"
+
+	"github.com/juju/errors"
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
@@ -14,6 +17,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("DiskManager", 2, NewDiskManagerAPI)
+	common.RegisterStandardFacade("DiskManager", 3, NewDiskManagerAPIV3)
 }
 
 // DiskManagerAPI provides access to the DiskManager API facade.
@@ -23,6 +27,12 @@ type DiskManagerAPI struct {
 	getAuthFunc common.GetAuthFunc
 }
 
+// DiskManagerAPIV3 provides access to version 3 of the DiskManager
+// API facade, which adds SetMachineFilesystemUsage.
+type DiskManagerAPIV3 struct {
+	*DiskManagerAPI
+}
+
 var getState = func(st *state.State) stateInterface {
 	return stateShim{st}
 }
@@ -53,6 +63,20 @@ func NewDiskManagerAPI(
 	}, nil
 }
 
+// NewDiskManagerAPIV3 creates a new server-side DiskManager API
+// facade, version 3.
+func NewDiskManagerAPIV3(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*DiskManagerAPIV3, error) {
+	api, err := NewDiskManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskManagerAPIV3{api}, nil
+}
+
 func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevices) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.MachineBlockDevices)),
@@ -89,6 +113,70 @@ func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevic
 	return result, nil
 }
 
+// SetMachineFilesystemUsage records the usage of the filesystems mounted
+// on the specified machines. Usage is recorded against the filesystem
+// attachments whose mount points match the reported mount points; the
+// usage of other mounted filesystems is ignored.
+func (d *DiskManagerAPIV3) SetMachineFilesystemUsage(args params.SetMachineFilesystemUsage) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.MachineFilesystemUsage)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.MachineFilesystemUsage {
+		tag, err := names.ParseMachineTag(arg.Machine)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = d.setMachineFilesystemUsage(tag, arg.Usage)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (d *DiskManagerAPI) setMachineFilesystemUsage(tag names.MachineTag, usage []storage.FilesystemUsage) error {
+	byMountPoint := make(map[string]storage.FilesystemUsage)
+	for _, u := range usage {
+		byMountPoint[path.Clean(u.MountPoint)] = u
+	}
+	attachments, err := d.st.MachineFilesystemAttachments(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, attachment := range attachments {
+		info, err := attachment.Info()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		u, ok := byMountPoint[path.Clean(info.MountPoint)]
+		if !ok || info.MountPoint == "" {
+			continue
+		}
+		if err := d.st.SetFilesystemAttachmentUsage(
+			tag, attachment.Filesystem(), stateFilesystemUsage(u),
+		); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func stateFilesystemUsage(u storage.FilesystemUsage) state.FilesystemUsage {
+	return state.FilesystemUsage{
+		Size:       u.Size,
+		Used:       u.Used,
+		Available:  u.Available,
+		Inodes:     u.Inodes,
+		InodesUsed: u.InodesUsed,
+		InodesFree: u.InodesFree,
+	}
+}
+
 func stateBlockDeviceInfo(devices []storage.BlockDevice) []state.BlockDeviceInfo {
 	result := make([]state.BlockDeviceInfo, len(devices))
 	for i, dev := range devices {
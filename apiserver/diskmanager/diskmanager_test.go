@@ -4,8 +4,7 @@
 package diskmanager_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -26,7 +25,7 @@ type DiskManagerSuite struct {
 	resources  *common.Resources
 	authorizer *apiservertesting.FakeAuthorizer
 	st         *mockState
-	api        *diskmanager.DiskManagerAPI
+	api        *diskmanager.DiskManagerAPIV3
 }
 
 func (s *DiskManagerSuite) SetUpTest(c *gc.C) {
@@ -38,7 +37,7 @@ func (s *DiskManagerSuite) SetUpTest(c *gc.C) {
 	diskmanager.PatchState(s, s.st)
 
 	var err error
-	s.api, err = diskmanager.NewDiskManagerAPI(nil, nil, s.authorizer)
+	s.api, err = diskmanager.NewDiskManagerAPIV3(nil, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
@@ -107,10 +106,67 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesStateError(c *gc.C) {
 	})
 }
 
+func (s *DiskManagerSuite) TestSetMachineFilesystemUsage(c *gc.C) {
+	s.st.filesystemAttachments = []state.FilesystemAttachment{
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("0/0"),
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		},
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("0/1"),
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/logs"},
+		},
+		// Not provisioned yet.
+		&mockFilesystemAttachment{filesystem: names.NewFilesystemTag("0/2")},
+	}
+	results, err := s.api.SetMachineFilesystemUsage(params.SetMachineFilesystemUsage{
+		MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+			Machine: "machine-0",
+			Usage: []storage.FilesystemUsage{{
+				MountPoint: "/",
+				Size:       4096,
+				Used:       1024,
+				Available:  3072,
+			}, {
+				MountPoint: "/srv/data/",
+				Size:       1024,
+				Used:       512,
+				Available:  512,
+				Inodes:     10,
+				InodesUsed: 2,
+				InodesFree: 8,
+			}},
+		}, {
+			Machine: "machine-1",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: nil,
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+	c.Assert(s.st.usage, jc.DeepEquals, map[names.FilesystemTag]state.FilesystemUsage{
+		names.NewFilesystemTag("0/0"): {
+			Size:       1024,
+			Used:       512,
+			Available:  512,
+			Inodes:     10,
+			InodesUsed: 2,
+			InodesFree: 8,
+		},
+	})
+}
+
 type mockState struct {
 	calls   int
 	devices map[string][]state.BlockDeviceInfo
 	err     error
+
+	filesystemAttachments []state.FilesystemAttachment
+	usage                 map[names.FilesystemTag]state.FilesystemUsage
 }
 
 func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error {
@@ -121,3 +177,32 @@ func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.Bl
 	st.devices[machineId] = devices
 	return st.err
 }
+
+func (st *mockState) MachineFilesystemAttachments(tag names.MachineTag) ([]state.FilesystemAttachment, error) {
+	return st.filesystemAttachments, st.err
+}
+
+func (st *mockState) SetFilesystemAttachmentUsage(m names.MachineTag, f names.FilesystemTag, usage state.FilesystemUsage) error {
+	if st.usage == nil {
+		st.usage = make(map[names.FilesystemTag]state.FilesystemUsage)
+	}
+	st.usage[f] = usage
+	return st.err
+}
+
+type mockFilesystemAttachment struct {
+	state.FilesystemAttachment
+	filesystem names.FilesystemTag
+	info       *state.FilesystemAttachmentInfo
+}
+
+func (a *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
+	return a.filesystem
+}
+
+func (a *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
+	if a.info == nil {
+		return state.FilesystemAttachmentInfo{}, errors.NotProvisionedf("filesystem attachment")
+	}
+	return *a.info, nil
+}
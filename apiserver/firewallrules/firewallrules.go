@@ -0,0 +1,91 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("FirewallRules", 1, NewAPI)
+}
+
+// Backend defines the state methods used by the FirewallRules facade.
+type Backend interface {
+	AddFirewallRule(network.FirewallRule) error
+	RemoveFirewallRule(name string) error
+	FirewallRules() ([]network.FirewallRule, error)
+}
+
+// API implements the FirewallRules facade, used by clients to manage
+// the model-level firewall rules.
+type API struct {
+	backend    Backend
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewAPI returns a new FirewallRules facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	// Only clients can manage firewall rules.
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// AddFirewallRules adds the specified firewall rules to the model.
+func (api *API) AddFirewallRules(args params.FirewallRules) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Rules)),
+	}
+	for i, rule := range args.Rules {
+		err := api.backend.AddFirewallRule(rule.NetworkFirewallRule())
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveFirewallRules removes the firewall rules with the
+// specified names from the model.
+func (api *API) RemoveFirewallRules(args params.FirewallRuleNames) (params.ErrorResults, error) {
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		err := api.backend.RemoveFirewallRule(name)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListFirewallRules returns all of the model's firewall rules.
+func (api *API) ListFirewallRules() (params.ListFirewallRulesResults, error) {
+	rules, err := api.backend.FirewallRules()
+	if err != nil {
+		return params.ListFirewallRulesResults{}, common.ServerError(err)
+	}
+	result := params.ListFirewallRulesResults{
+		Rules: make([]params.FirewallRule, len(rules)),
+	}
+	for i, rule := range rules {
+		result.Rules[i] = params.FromNetworkFirewallRule(rule)
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type firewallRulesSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	api        *firewallrules.API
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&firewallRulesSuite{})
+
+func (s *firewallRulesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = firewallrules.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("dmz", "", []string{"10.0.1.0/24"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+var httpsEgress = params.FirewallRule{
+	Name:             "https",
+	SourceSpace:      "dmz",
+	DestinationCIDRs: []string{"0.0.0.0/0"},
+	Ports:            []params.PortRange{{FromPort: 443, ToPort: 443, Protocol: "tcp"}},
+}
+
+func (s *firewallRulesSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewMachineTag("0")
+	_, err := firewallrules.NewAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *firewallRulesSuite) TestAddFirewallRules(c *gc.C) {
+	invalid := httpsEgress
+	invalid.Name = "Invalid"
+	results, err := s.api.AddFirewallRules(params.FirewallRules{
+		Rules: []params.FirewallRule{httpsEgress, invalid},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add firewall rule "Invalid": firewall rule name "Invalid" not valid`)
+
+	rule, err := s.State.FirewallRule("https")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule, jc.DeepEquals, network.FirewallRule{
+		Name:             "https",
+		SourceSpace:      "dmz",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	})
+}
+
+func (s *firewallRulesSuite) TestBlockAddFirewallRules(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockAddFirewallRules")
+	_, err := s.api.AddFirewallRules(params.FirewallRules{
+		Rules: []params.FirewallRule{httpsEgress},
+	})
+	s.AssertBlocked(c, err, "TestBlockAddFirewallRules")
+}
+
+func (s *firewallRulesSuite) TestRemoveFirewallRules(c *gc.C) {
+	err := s.State.AddFirewallRule(httpsEgress.NetworkFirewallRule())
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.RemoveFirewallRules(params.FirewallRuleNames{
+		Names: []string{"https", "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	rules, err := s.State.FirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (s *firewallRulesSuite) TestBlockRemoveFirewallRules(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockRemoveFirewallRules")
+	_, err := s.api.RemoveFirewallRules(params.FirewallRuleNames{
+		Names: []string{"https"},
+	})
+	s.AssertBlocked(c, err, "TestBlockRemoveFirewallRules")
+}
+
+func (s *firewallRulesSuite) TestListFirewallRules(c *gc.C) {
+	err := s.State.AddFirewallRule(httpsEgress.NetworkFirewallRule())
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Rules, jc.DeepEquals, []params.FirewallRule{httpsEgress})
+}
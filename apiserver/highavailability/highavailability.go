@@ -15,12 +15,15 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/peergrouper"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.highavailability")
 
 func init() {
 	common.RegisterStandardFacade("HighAvailability", 2, NewHighAvailabilityAPI)
+
+	common.RegisterStandardFacade("HighAvailability", 3, NewHighAvailabilityAPIV3)
 }
 
 // HighAvailability defines the methods on the highavailability API end point.
@@ -28,6 +31,14 @@ type HighAvailability interface {
 	EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error)
 }
 
+// HighAvailabilityV3 defines the methods on version 3 of the
+// highavailability API end point.
+type HighAvailabilityV3 interface {
+	HighAvailability
+	HAStatus() (params.HAStatusResult, error)
+	RemoveController(args params.RemoveControllerSpecs) (params.ControllersChangeResults, error)
+}
+
 // HighAvailabilityAPI implements the HighAvailability interface and is the concrete
 // implementation of the api end point.
 type HighAvailabilityAPI struct {
@@ -36,7 +47,16 @@ type HighAvailabilityAPI struct {
 	authorizer common.Authorizer
 }
 
-var _ HighAvailability = (*HighAvailabilityAPI)(nil)
+// HighAvailabilityAPIV3 implements version 3 of the highavailability
+// API end point, which adds HAStatus and RemoveController.
+type HighAvailabilityAPIV3 struct {
+	*HighAvailabilityAPI
+}
+
+var (
+	_ HighAvailability   = (*HighAvailabilityAPI)(nil)
+	_ HighAvailabilityV3 = (*HighAvailabilityAPIV3)(nil)
+)
 
 // NewHighAvailabilityAPI creates a new server-side highavailability API end point.
 func NewHighAvailabilityAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*HighAvailabilityAPI, error) {
@@ -51,6 +71,16 @@ func NewHighAvailabilityAPI(st *state.State, resources *common.Resources, author
 	}, nil
 }
 
+// NewHighAvailabilityAPIV3 creates a new server-side highavailability
+// API end point, version 3.
+func NewHighAvailabilityAPIV3(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*HighAvailabilityAPIV3, error) {
+	api, err := NewHighAvailabilityAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &HighAvailabilityAPIV3{api}, nil
+}
+
 func (api *HighAvailabilityAPI) EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error) {
 	results := params.ControllersChangeResults{Results: make([]params.ControllersChangeResult, len(args.Specs))}
 	for i, controllersServersSpec := range args.Specs {
@@ -61,6 +91,49 @@ func (api *HighAvailabilityAPI) EnableHA(args params.ControllersSpecs) (params.C
 	return results, nil
 }
 
+// newReport is patched out in tests, which have no replica set.
+var newReport = peergrouper.NewReport
+
+// HAStatus returns the replica set status of each controller machine,
+// along with any problems that prevent the peergrouper from
+// reconciling the replica set with the controller machines.
+func (api *HighAvailabilityAPIV3) HAStatus() (params.HAStatusResult, error) {
+	if !api.state.IsController() {
+		return params.HAStatusResult{}, errors.New("unsupported with hosted models")
+	}
+	report, err := newReport(api.state)
+	if err != nil {
+		return params.HAStatusResult{}, errors.Trace(err)
+	}
+	result := params.HAStatusResult{
+		Members:  make([]params.HAMemberStatus, len(report.Members)),
+		Problems: report.Problems,
+	}
+	for i, m := range report.Members {
+		member := params.HAMemberStatus{
+			ReplicaSetId: m.ReplicaSetId,
+			Address:      m.Address,
+			WantsVote:    m.WantsVote,
+			HasVote:      m.HasVote,
+			Voting:       m.Voting,
+			State:        m.State,
+			Healthy:      m.Healthy,
+			Lag:          m.Lag,
+			Message:      m.Message,
+			Problems:     m.Problems,
+		}
+		if m.MachineId != "" {
+			member.MachineTag = names.NewMachineTag(m.MachineId).String()
+		}
+		if !m.LastHeartbeat.IsZero() {
+			heartbeat := m.LastHeartbeat
+			member.LastHeartbeat = &heartbeat
+		}
+		result.Members[i] = member
+	}
+	return result, nil
+}
+
 // Convert machine ids to tags.
 func machineIdsToTags(ids ...string) []string {
 	var result []string
@@ -167,6 +240,61 @@ func EnableHASingle(st *state.State, spec params.ControllersSpec) (params.Contro
 	return controllersChanges(changes), nil
 }
 
+// RemoveController takes each of the specified controller machines
+// one step further towards removal. It must be called repeatedly,
+// until each machine is reported as removed.
+func (api *HighAvailabilityAPIV3) RemoveController(args params.RemoveControllerSpecs) (params.ControllersChangeResults, error) {
+	results := params.ControllersChangeResults{Results: make([]params.ControllersChangeResult, len(args.Specs))}
+	for i, spec := range args.Specs {
+		result, err := removeControllerSingle(api.state, spec)
+		results.Results[i].Result = result
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func removeControllerSingle(st *state.State, spec params.RemoveControllerSpec) (params.ControllersChanges, error) {
+	if !st.IsController() {
+		return params.ControllersChanges{}, errors.New("unsupported with hosted models")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	tag, err := names.ParseMachineTag(spec.MachineTag)
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	var replacement *state.MachineTemplate
+	if spec.Replace {
+		m, err := st.Machine(tag.Id())
+		if err != nil {
+			return params.ControllersChanges{}, errors.Trace(err)
+		}
+		// The replacement defaults to the same series and
+		// constraints as the machine it replaces.
+		replacement = &state.MachineTemplate{
+			Series:      spec.Series,
+			Constraints: spec.Constraints,
+			Placement:   spec.Placement,
+		}
+		if replacement.Series == "" {
+			replacement.Series = m.Series()
+		}
+		if constraints.IsEmpty(&replacement.Constraints) {
+			replacement.Constraints, err = m.Constraints()
+			if err != nil {
+				return params.ControllersChanges{}, errors.Annotatef(err, "reading constraints for machine %v", tag.Id())
+			}
+		}
+	}
+	changes, err := st.RemoveControllerMachine(tag.Id(), replacement)
+	if err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+	return controllersChanges(changes), nil
+}
+
 // StopHAReplicationForUpgrade will prompt the HA cluster to enter upgrade
 // mongo mode.
 func (api *HighAvailabilityAPI) StopHAReplicationForUpgrade(args params.UpgradeMongoParams) (params.MongoUpgradeResults, error) {
@@ -5,6 +5,7 @@ package highavailability_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/state/presence"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker/peergrouper"
 )
 
 func TestAll(t *stdtesting.T) {
@@ -32,7 +34,7 @@ type clientSuite struct {
 
 	resources  *common.Resources
 	authoriser apiservertesting.FakeAuthorizer
-	haServer   *highavailability.HighAvailabilityAPI
+	haServer   *highavailability.HighAvailabilityAPIV3
 	pingers    []*presence.Pinger
 
 	commontesting.BlockHelper
@@ -65,7 +67,7 @@ func (s *clientSuite) SetUpTest(c *gc.C) {
 	}
 
 	var err error
-	s.haServer, err = highavailability.NewHighAvailabilityAPI(s.State, s.resources, s.authoriser)
+	s.haServer, err = highavailability.NewHighAvailabilityAPIV3(s.State, s.resources, s.authoriser)
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = s.State.AddMachines(state.MachineTemplate{
@@ -106,7 +108,7 @@ func (s *clientSuite) enableHA(
 }
 
 func enableHA(
-	c *gc.C, haServer *highavailability.HighAvailabilityAPI, numControllers int, cons constraints.Value, series string, placement []string,
+	c *gc.C, haServer highavailability.HighAvailability, numControllers int, cons constraints.Value, series string, placement []string,
 ) (params.ControllersChanges, error) {
 	arg := params.ControllersSpecs{
 		Specs: []params.ControllersSpec{{
@@ -391,3 +393,148 @@ func (s *clientSuite) TestEnableHAHostedEnvErrors(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(machines, gc.HasLen, 0)
 }
+
+func (s *clientSuite) TestHAStatus(c *gc.C) {
+	heartbeat := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(highavailability.NewReport, func(st *state.State) (*peergrouper.Report, error) {
+		c.Assert(st, gc.Equals, s.State)
+		return &peergrouper.Report{
+			Members: []peergrouper.MemberReport{{
+				MachineId:    "0",
+				ReplicaSetId: 1,
+				Address:      "10.0.0.1:37017",
+				WantsVote:    true,
+				HasVote:      true,
+				Voting:       true,
+				State:        "PRIMARY",
+				Healthy:      true,
+			}, {
+				ReplicaSetId:  2,
+				Address:       "10.0.0.2:37017",
+				State:         "SECONDARY",
+				Healthy:       true,
+				Lag:           time.Second,
+				LastHeartbeat: heartbeat,
+				Problems:      []string{"member has no controller machine and will be removed"},
+			}},
+		}, nil
+	})
+	result, err := s.haServer.HAStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.HAStatusResult{
+		Members: []params.HAMemberStatus{{
+			MachineTag:   "machine-0",
+			ReplicaSetId: 1,
+			Address:      "10.0.0.1:37017",
+			WantsVote:    true,
+			HasVote:      true,
+			Voting:       true,
+			State:        "PRIMARY",
+			Healthy:      true,
+		}, {
+			ReplicaSetId:  2,
+			Address:       "10.0.0.2:37017",
+			State:         "SECONDARY",
+			Healthy:       true,
+			Lag:           time.Second,
+			LastHeartbeat: &heartbeat,
+			Problems:      []string{"member has no controller machine and will be removed"},
+		}},
+	})
+}
+
+func (s *clientSuite) TestHAStatusHostedEnvErrors(c *gc.C) {
+	st2 := s.Factory.MakeModel(c, &factory.ModelParams{ConfigAttrs: coretesting.Attrs{"controller": false}})
+	defer st2.Close()
+
+	haServer, err := highavailability.NewHighAvailabilityAPIV3(st2, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = haServer.HAStatus()
+	c.Assert(err, gc.ErrorMatches, "unsupported with hosted models")
+}
+
+func (s *clientSuite) removeController(c *gc.C, spec params.RemoveControllerSpec) (params.ControllersChanges, error) {
+	results, err := s.haServer.RemoveController(params.RemoveControllerSpecs{
+		Specs: []params.RemoveControllerSpec{spec},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	// We explicitly return nil here so we can do typed nil checking
+	// of the result like normal.
+	err = nil
+	if result.Error != nil {
+		err = result.Error
+	}
+	return result.Result, err
+}
+
+func (s *clientSuite) TestRemoveControllerWithReplacement(c *gc.C) {
+	_, err := s.enableHA(c, 3, emptyCons, defaultSeries, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err := s.removeController(c, params.RemoveControllerSpec{
+		MachineTag: "machine-1",
+		Replace:    true,
+		Placement:  "zone=z2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, params.ControllersChanges{
+		Added:   []string{"machine-3"},
+		Demoted: []string{"machine-1"},
+	})
+
+	// The replacement has the same series and constraints as the
+	// machine it replaces.
+	m3, err := s.State.Machine("3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m3.Series(), gc.Equals, "quantal")
+	c.Assert(m3.Placement(), gc.Equals, "zone=z2")
+	cons, err := m3.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, controllerCons)
+
+	// Machine 1 never had its vote, so the next call removes it.
+	changes, err = s.removeController(c, params.RemoveControllerSpec{MachineTag: "machine-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, params.ControllersChanges{
+		Removed: []string{"machine-1"},
+	})
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsFalse)
+	c.Assert(m1.Life(), gc.Equals, state.Dying)
+}
+
+func (s *clientSuite) TestRemoveControllerNeedsReplacement(c *gc.C) {
+	_, err := s.removeController(c, params.RemoveControllerSpec{MachineTag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, "cannot remove controller machine 0: no replacement was requested, .*")
+}
+
+func (s *clientSuite) TestRemoveControllerInvalidTag(c *gc.C) {
+	_, err := s.removeController(c, params.RemoveControllerSpec{MachineTag: "unit-foo-0"})
+	c.Assert(err, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+}
+
+func (s *clientSuite) TestBlockRemoveController(c *gc.C) {
+	s.BlockRemoveObject(c, "TestBlockRemoveController")
+	_, err := s.removeController(c, params.RemoveControllerSpec{MachineTag: "machine-0", Replace: true})
+	s.AssertBlocked(c, err, "TestBlockRemoveController")
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *clientSuite) TestRemoveControllerHostedEnvErrors(c *gc.C) {
+	st2 := s.Factory.MakeModel(c, &factory.ModelParams{ConfigAttrs: coretesting.Attrs{"controller": false}})
+	defer st2.Close()
+
+	haServer, err := highavailability.NewHighAvailabilityAPIV3(st2, s.resources, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := haServer.RemoveController(params.RemoveControllerSpecs{
+		Specs: []params.RemoveControllerSpec{{MachineTag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "unsupported with hosted models")
+}
@@ -160,8 +160,7 @@ func (api *MachinerAPI) SetObservedNetworkConfig(args params.SetMachineNetworkCo
 		return errors.Trace(err)
 	}
 	if len(providerConfig) == 0 {
-		logger.Infof("not updating machine network config: no provider network config found")
-		return nil
+		return api.setObservedNetworkConfigWithJujuManagedSpaces(m, observedConfig)
 	}
 
 	mergedConfig := networkingcommon.MergeProviderAndObservedNetworkConfigs(providerConfig, observedConfig)
@@ -170,6 +169,26 @@ func (api *MachinerAPI) SetObservedNetworkConfig(args params.SetMachineNetworkCo
 	return api.setOneMachineNetworkConfig(m, mergedConfig)
 }
 
+// setObservedNetworkConfigWithJujuManagedSpaces sets the observed network
+// config of a machine for which the provider has none, and discovers the
+// subnets of its addresses, when the model uses Juju-managed spaces.
+func (api *MachinerAPI) setObservedNetworkConfigWithJujuManagedSpaces(m *state.Machine, observedConfig []params.NetworkConfig) error {
+	envConfig, err := api.st.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !envConfig.JujuManagedSpaces() {
+		logger.Infof("not updating machine network config: no provider network config found")
+		return nil
+	}
+
+	sortedConfig := networkingcommon.SortNetworkConfigsByParents(observedConfig)
+	if err := api.setOneMachineNetworkConfig(m, sortedConfig); err != nil {
+		return errors.Trace(err)
+	}
+	return m.DiscoverSubnets()
+}
+
 func (api *MachinerAPI) getMachineForSettingNetworkConfig(machineTag string) (*state.Machine, error) {
 	canModify, err := api.getCanModify()
 	if err != nil {
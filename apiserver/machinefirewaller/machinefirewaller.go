@@ -0,0 +1,209 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.machinefirewaller")
+
+func init() {
+	common.RegisterStandardFacade("MachineFirewaller", 1, NewAPI)
+}
+
+// newEnviron is a variable so it can be patched in tests.
+var newEnviron = environs.New
+
+// API implements the MachineFirewaller facade, used by machine agents
+// to enforce the model-level firewall rules that apply to them.
+type API struct {
+	st            *state.State
+	resources     *common.Resources
+	accessMachine common.GetAuthFunc
+}
+
+// NewAPI returns a new MachineFirewaller facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:        st,
+		resources: resources,
+		accessMachine: func() (common.AuthFunc, error) {
+			return authorizer.AuthOwner, nil
+		},
+	}, nil
+}
+
+// WatchFirewallRules returns a NotifyWatcher for each specified machine,
+// which notifies when the machine's egress rules may have changed.
+func (api *API) WatchFirewallRules(args params.Entities) (params.NotifyWatchResults, error) {
+	results := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := api.accessMachine()
+	if err != nil {
+		return params.NotifyWatchResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.st.Machine(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := machine.WatchFirewallRules()
+		// Consume the initial event. Technically, API calls to Watch
+		// 'transmit' the initial event in the Watch response. But
+		// NotifyWatchers have no state to transmit.
+		if _, ok := <-watch.Changes(); ok {
+			results.Results[i].NotifyWatcherId = api.resources.Register(watch)
+		} else {
+			results.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return results, nil
+}
+
+// EgressRules returns the egress rules that apply to each
+// specified machine.
+func (api *API) EgressRules(args params.Entities) (params.EgressRulesResults, error) {
+	results := params.EgressRulesResults{
+		Results: make([]params.EgressRulesResult, len(args.Entities)),
+	}
+	canAccess, err := api.accessMachine()
+	if err != nil {
+		return params.EgressRulesResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		rules, err := api.st.MachineEgressRules(tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Rules = make([]params.EgressRule, len(rules))
+		for j, rule := range rules {
+			ports := make([]params.PortRange, len(rule.Ports))
+			for k, pr := range rule.Ports {
+				ports[k] = params.FromNetworkPortRange(pr)
+			}
+			results.Results[i].Rules[j] = params.EgressRule{
+				Name:             rule.Name,
+				DestinationCIDRs: rule.DestinationCIDRs,
+				Ports:            ports,
+			}
+		}
+	}
+	return results, nil
+}
+
+// SetProviderEgressRules enforces the egress rules that apply to each
+// specified machine with the provider's firewall, if the provider
+// supports it. Each result reports whether the rules were enforced;
+// if not, the machine agent must enforce them itself.
+func (api *API) SetProviderEgressRules(args params.Entities) (params.BoolResults, error) {
+	results := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := api.accessMachine()
+	if err != nil {
+		return params.BoolResults{}, errors.Trace(err)
+	}
+	cfg, err := api.st.ModelConfig()
+	if err != nil {
+		return params.BoolResults{}, errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return params.BoolResults{}, errors.Trace(err)
+	}
+	firewaller, ok := env.(environs.EgressFirewaller)
+	if !ok {
+		logger.Debugf("provider %q does not support egress rules", cfg.Type())
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		enforced, err := api.setProviderEgressRules(firewaller, tag.Id())
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = enforced
+	}
+	return results, nil
+}
+
+// setProviderEgressRules enforces the egress rules that apply to the
+// machine with the given firewaller, and reports whether it did.
+func (api *API) setProviderEgressRules(firewaller environs.EgressFirewaller, machineId string) (bool, error) {
+	machine, err := api.st.Machine(machineId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if manual, err := machine.IsManual(); err != nil {
+		return false, errors.Trace(err)
+	} else if manual {
+		// Manual machines are not in the provider's security groups.
+		return false, nil
+	}
+	if _, err := machine.InstanceId(); errors.IsNotProvisioned(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	rules, err := api.st.MachineEgressRules(machineId)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	// Always allow traffic to the controllers, so that the
+	// agent can never be cut off by egress rules.
+	hostPorts, err := api.st.APIHostPorts()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	var allowed []string
+	for _, server := range hostPorts {
+		for _, hp := range server {
+			allowed = append(allowed, hp.Value)
+		}
+	}
+	err = firewaller.SetMachineEgressRules(machineId, rules, allowed)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot enforce egress rules for machine %s with the provider: %v", machineId, err)
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot set egress rules for machine %s", machineId)
+	}
+	return true, nil
+}
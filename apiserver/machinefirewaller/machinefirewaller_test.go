@@ -0,0 +1,230 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/machinefirewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	jujufactory "github.com/juju/juju/testing/factory"
+)
+
+type machineFirewallerSuite struct {
+	jujutesting.JujuConnSuite
+
+	authorizer apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+	machine    *state.Machine
+	api        *machinefirewaller.API
+}
+
+var _ = gc.Suite(&machineFirewallerSuite{})
+
+func (s *machineFirewallerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.machine = s.Factory.MakeMachine(c, &jujufactory.MachineParams{
+		Addresses: []network.Address{network.NewAddress("10.0.1.10")},
+	})
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.machine.MachineTag(),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.api, err = machinefirewaller.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineFirewallerSuite) TestNewAPIRefusesNonMachineAgent(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewUnitTag("mysql/0")
+	_, err := machinefirewaller.NewAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *machineFirewallerSuite) TestEgressRules(c *gc.C) {
+	err := s.State.AddFirewallRule(network.FirewallRule{
+		Name:             "https",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.EgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.EgressRulesResults{
+		Results: []params.EgressRulesResult{{
+			Rules: []params.EgressRule{{
+				Name:             "https",
+				DestinationCIDRs: []string{"0.0.0.0/0"},
+				Ports:            []params.PortRange{{FromPort: 443, ToPort: 443, Protocol: "tcp"}},
+			}},
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
+
+func (s *machineFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	results, err := s.api.WatchFirewallRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+		{Tag: "machine-42"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.AddFirewallRule(network.FirewallRule{
+		Name:             "https",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRulesNotSupportedByProvider(c *gc.C) {
+	// The dummy provider does not support egress rules.
+	results, err := s.api.SetProviderEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+		{Tag: "machine-42"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRules(c *gc.C) {
+	env := s.patchEgressEnviron(nil)
+	err := s.State.AddFirewallRule(network.FirewallRule{
+		Name:             "https",
+		DestinationCIDRs: []string{"10.0.0.0/8"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAPIHostPorts([][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.SetProviderEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+		{Tag: "machine-42"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	env.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "SetMachineEgressRules",
+		Args: []interface{}{s.machine.Id(), []network.EgressRule{{
+			Name:             "https",
+			DestinationCIDRs: []string{"10.0.0.0/8"},
+			Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+		}}, []string{"10.0.0.1"}},
+	}})
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRulesNotSupported(c *gc.C) {
+	env := s.patchEgressEnviron(errors.NotSupportedf("egress rules"))
+	results, err := s.api.SetProviderEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+	env.CheckCallNames(c, "SetMachineEgressRules")
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRulesError(c *gc.C) {
+	s.patchEgressEnviron(errors.New("boom"))
+	results, err := s.api.SetProviderEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: s.machine.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "cannot set egress rules for machine .*: boom")
+}
+
+func (s *machineFirewallerSuite) TestSetProviderEgressRulesUnprovisioned(c *gc.C) {
+	env := s.patchEgressEnviron(nil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := s.authorizer
+	authorizer.Tag = machine.Tag()
+	api, err := machinefirewaller.NewAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.SetProviderEgressRules(params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+	env.CheckNoCalls(c)
+}
+
+func (s *machineFirewallerSuite) patchEgressEnviron(err error) *egressEnviron {
+	env := &egressEnviron{}
+	env.SetErrors(err)
+	s.PatchValue(machinefirewaller.NewEnviron, func(cfg *config.Config) (environs.Environ, error) {
+		inner, err := environs.New(cfg)
+		env.Environ = inner
+		return env, err
+	})
+	return env
+}
+
+type egressEnviron struct {
+	environs.Environ
+	gitjujutesting.Stub
+}
+
+func (e *egressEnviron) SetMachineEgressRules(machineId string, rules []network.EgressRule, allowed []string) error {
+	e.MethodCall(e, "SetMachineEgressRules", machineId, rules, allowed)
+	return e.NextErr()
+}
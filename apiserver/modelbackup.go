@@ -0,0 +1,169 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/state"
+)
+
+// modelBackupHandler handles the download of model backup archives,
+// and the restoring of models from them.
+type modelBackupHandler struct {
+	ctxt httpContext
+}
+
+func (h *modelBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, entity, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+	user := entity.Tag().(names.UserTag)
+
+	switch r.Method {
+	case "GET":
+		err = h.serveGet(w, st, user)
+	case "POST":
+		err = h.servePost(w, r, st, user)
+	default:
+		err = errors.MethodNotAllowedf("unsupported method: %q", r.Method)
+	}
+	if err != nil {
+		sendError(w, err)
+	}
+}
+
+// serveGet sends a model backup archive for the request's model. The
+// archive is written in full before the response is started, so that
+// any error in creating it can be reported to the client.
+func (h *modelBackupHandler) serveGet(w http.ResponseWriter, st *state.State, user names.UserTag) error {
+	if err := checkModelAdmin(st, st.ModelTag(), user); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("handling model backup request for %q", st.ModelUUID())
+	f, err := ioutil.TempFile("", "juju-model-backup")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			logger.Warningf("cannot remove %q: %v", f.Name(), err)
+		}
+	}()
+	if err := migration.WriteModelArchive(f, st); err != nil {
+		return errors.Annotate(err, "cannot write model backup")
+	}
+	size, err := f.Seek(0, 2)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return errors.Trace(err)
+	}
+	w.Header().Set("Content-Type", params.ContentTypeRaw)
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		logger.Errorf("cannot send model backup for %q: %v", st.ModelUUID(), err)
+	}
+	return nil
+}
+
+// servePost restores the model held in the model backup archive in
+// the request body. Controller administrators may restore any model;
+// model administrators may replace their own model with a backup of
+// it.
+func (h *modelBackupHandler) servePost(w http.ResponseWriter, r *http.Request, st *state.State, user names.UserTag) error {
+	defer r.Body.Close()
+	replace := r.URL.Query().Get("replace") == "true"
+
+	archive, err := migration.OpenModelArchive(r.Body)
+	if err != nil {
+		return errors.NewBadRequest(err, "")
+	}
+	defer archive.Close()
+
+	tag := archive.Model().Tag()
+	if replace && r.URL.Query().Get(":modeluuid") != "" && tag != st.ModelTag() {
+		// Guard against replacing a model other than the one the
+		// client is connected to.
+		return errors.BadRequestf("archive holds a backup of model %q, not %q", tag.Id(), st.ModelUUID())
+	}
+	if replace {
+		err = checkModelAdmin(st, tag, user)
+	} else {
+		err = checkControllerAdmin(st, user)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := archive.Check(); err != nil {
+		return errors.NewBadRequest(err, "")
+	}
+	logger.Infof("handling model restore request for %q", tag.Id())
+	model, err := archive.Restore(st, replace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sendStatusAndJSON(w, http.StatusOK, &params.BackupsRestoreModelResult{
+		ModelTag: model.ModelTag().String(),
+		Name:     model.Name(),
+	})
+	return nil
+}
+
+func checkControllerAdmin(st *state.State, user names.UserTag) error {
+	isAdmin, err := st.IsControllerAdministrator(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// checkModelAdmin returns an error unless the user is an administrator
+// of the given model or of the controller.
+func checkModelAdmin(st *state.State, modelTag names.ModelTag, user names.UserTag) error {
+	if err := checkControllerAdmin(st, user); err == nil {
+		return nil
+	} else if errors.Cause(err) != common.ErrPerm {
+		return errors.Trace(err)
+	}
+	if _, err := st.GetModel(modelTag); errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	modelSt, err := st.ForModel(modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer modelSt.Close()
+	modelUser, err := modelSt.ModelUser(user)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if modelUser.Access() != state.ModelAdminAccess {
+		return common.ErrPerm
+	}
+	return nil
+}
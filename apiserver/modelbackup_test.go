@@ -0,0 +1,163 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type modelBackupSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&modelBackupSuite{})
+
+func (s *modelBackupSuite) modelBackupURL(c *gc.C, modelUUID string) string {
+	return s.makeURL(c, "https", "/model/"+modelUUID+"/model-backup", nil).String()
+}
+
+func (s *modelBackupSuite) restoreURL(c *gc.C, replace bool) string {
+	var query url.Values
+	if replace {
+		query = url.Values{"replace": {"true"}}
+	}
+	return s.makeURL(c, "https", "/model-backup", query).String()
+}
+
+func (s *modelBackupSuite) download(c *gc.C, modelUUID string) []byte {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.modelBackupURL(c, modelUUID)})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeRaw)
+	c.Assert(resp.ContentLength, gc.Equals, int64(len(body)))
+	return body
+}
+
+func (s *modelBackupSuite) assertError(c *gc.C, resp *http.Response, status int, msg string) {
+	body := assertResponse(c, resp, status, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, msg)
+}
+
+func (s *modelBackupSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.modelBackupURL(c, s.modelUUID)})
+	s.assertError(c, resp, http.StatusUnauthorized, "no credentials provided")
+}
+
+func (s *modelBackupSuite) TestInvalidMethod(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "PUT", url: s.modelBackupURL(c, s.modelUUID)})
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *modelBackupSuite) TestDownloadRequiresModelAdmin(c *gc.C) {
+	st := s.setupOtherModel(c)
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.modelBackupURL(c, st.ModelUUID())})
+	s.assertError(c, resp, http.StatusUnauthorized, "permission denied")
+}
+
+func (s *modelBackupSuite) TestRestoreExistingModel(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	archive := s.download(c, st.ModelUUID())
+
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    s.restoreURL(c, false),
+		body:   bytes.NewReader(archive),
+	})
+	s.assertError(c, resp, http.StatusInternalServerError, `model ".*" already exists`)
+}
+
+func (s *modelBackupSuite) TestRestoreReplace(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	archive := s.download(c, st.ModelUUID())
+
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    s.restoreURL(c, true),
+		body:   bytes.NewReader(archive),
+	})
+	body := assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var result params.BackupsRestoreModelResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ModelTag, gc.Equals, st.ModelTag().String())
+
+	model, err := s.State.GetModel(st.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeActive)
+}
+
+func (s *modelBackupSuite) TestRestoreReplaceTruncatedArchive(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	archive := s.download(c, st.ModelUUID())
+	f := factory.NewFactory(st)
+	f.MakeMachine(c, nil)
+
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    s.restoreURL(c, true),
+		body:   bytes.NewReader(archive[:len(archive)-16]),
+	})
+	s.assertError(c, resp, http.StatusBadRequest, "cannot read model backup archive: .*")
+
+	// The existing model is left as it was.
+	machines, err := st.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *modelBackupSuite) TestModelAdminCanReplaceOwnModel(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	archive := s.download(c, st.ModelUUID())
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true, Password: "password"})
+	_, err := st.AddModelUser(state.ModelUserSpec{
+		User:      user.UserTag(),
+		CreatedBy: s.userTag,
+		Access:    state.ModelAdminAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.userTag = user.UserTag()
+
+	// A model administrator cannot create models...
+	modelURL := s.makeURL(c, "https", "/model/"+st.ModelUUID()+"/model-backup", nil)
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    modelURL.String(),
+		body:   bytes.NewReader(archive),
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, "permission denied")
+
+	// ... but may replace their own.
+	modelURL.RawQuery = "replace=true"
+	resp = s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    modelURL.String(),
+		body:   bytes.NewReader(archive),
+	})
+	assertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+}
+
+func (s *modelBackupSuite) TestRestoreInvalidArchive(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{
+		method: "POST",
+		url:    s.restoreURL(c, false),
+		body:   bytes.NewReader([]byte("not an archive")),
+	})
+	s.assertError(c, resp, http.StatusBadRequest, "invalid model backup archive: .*")
+}
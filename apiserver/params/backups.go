@@ -12,6 +12,26 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string
+
+	// Encryption holds the key with which to encrypt the archive.
+	// When it is empty, the model's default backup encryption
+	// public key is used, if any.
+	Encryption BackupsEncryptionKey
+}
+
+// BackupsEncryptionKey holds either a passphrase from which to derive
+// the key for a backup archive, or a PEM-encoded RSA public key to
+// which to encrypt it.
+type BackupsEncryptionKey struct {
+	Passphrase string
+	PublicKey  string
+}
+
+// BackupsDecryptionKey holds either the passphrase or the PEM-encoded
+// RSA private key needed to decrypt an encrypted backup archive.
+type BackupsDecryptionKey struct {
+	Passphrase string
+	PrivateKey string
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -21,6 +41,9 @@ type BackupsInfoArgs struct {
 
 // BackupsListArgs holds the args for the API List method.
 type BackupsListArgs struct {
+	// Remote requests the backups held in the remote backup
+	// storage rather than those stored on the controller.
+	Remote bool
 }
 
 // BackupsDownloadArgs holds the args for the API Download method.
@@ -67,6 +90,16 @@ type BackupsMetadataResult struct {
 	Hostname string
 	Version  version.Number
 
+	// Scheduled is true for backups taken automatically by the
+	// controller, and Failure holds the reason a scheduled backup
+	// could not be created.
+	Scheduled bool
+	Failure   string
+
+	// Encryption names the scheme with which the archive is
+	// encrypted, if any.
+	Encryption string
+
 	CACert       string
 	CAPrivateKey string
 }
@@ -75,4 +108,14 @@ type BackupsMetadataResult struct {
 type RestoreArgs struct {
 	// BackupId holds the id of the backup in server if any
 	BackupId string
+
+	// Key is used to decrypt the backup, if it is encrypted.
+	Key BackupsDecryptionKey
+}
+
+// BackupsRestoreModelResult holds the result of restoring a model
+// from a model backup archive.
+type BackupsRestoreModelResult struct {
+	ModelTag string
+	Name     string
 }
@@ -141,6 +141,127 @@ type ResolvedModeResults struct {
 	Results []ResolvedModeResult
 }
 
+// EntityWorkloadVersion holds the workload version for an entity.
+type EntityWorkloadVersion struct {
+	Tag             string
+	WorkloadVersion string
+}
+
+// EntityWorkloadVersions holds the parameters for setting the workload
+// version for a set of entities.
+type EntityWorkloadVersions struct {
+	Entities []EntityWorkloadVersion
+}
+
+// CommitHookChangesArg holds the changes made by a hook of a unit,
+// which are committed together.
+type CommitHookChangesArg struct {
+	Tag string
+
+	// RelationUnitSettings holds the unit's changed settings in each
+	// relation. As for UpdateSettings, an empty value deletes a key.
+	RelationUnitSettings []RelationUnitSettings
+
+	// UpdateCharmState reports whether the unit's charm state is
+	// replaced with CharmState.
+	UpdateCharmState bool
+	CharmState       map[string]string
+}
+
+// CommitHookChangesArgs holds the parameters for committing the
+// changes made by hooks of a set of units.
+type CommitHookChangesArgs struct {
+	Args []CommitHookChangesArg
+}
+
+// CharmStateResult holds the charm state of a unit, or an error.
+type CharmStateResult struct {
+	CharmState map[string]string
+	Error      *Error
+}
+
+// CharmStateResults holds the bulk operation result of an API call
+// that returns charm state.
+type CharmStateResults struct {
+	Results []CharmStateResult
+}
+
+// GoalStateStatus holds the status of a unit in a goal state: one of
+// "active", "joining" or "dying".
+type GoalStateStatus struct {
+	Status string
+}
+
+// GoalState describes the units a unit's service is expected to have,
+// and the units expected in each relation of the service, keyed by
+// endpoint name and then by unit name.
+type GoalState struct {
+	Units     map[string]GoalStateStatus
+	Relations map[string]map[string]GoalStateStatus
+}
+
+// GoalStateResult holds a goal state or an error.
+type GoalStateResult struct {
+	Error  *Error
+	Result *GoalState
+}
+
+// GoalStateResults holds the bulk operation result of an API call
+// that returns goal states.
+type GoalStateResults struct {
+	Results []GoalStateResult
+}
+
+// HookTimeoutResult holds the time after which a unit's running hooks
+// are killed, or an error. A zero timeout means hooks are not killed.
+type HookTimeoutResult struct {
+	Error   *Error
+	Timeout time.Duration
+}
+
+// HookTimeoutResults holds the bulk operation result of an API call
+// that returns hook timeouts.
+type HookTimeoutResults struct {
+	Results []HookTimeoutResult
+}
+
+// HookExecution describes a single execution of a hook, action or
+// juju-run commands by a unit agent.
+type HookExecution struct {
+	Kind      string
+	Name      string
+	Started   time.Time
+	Duration  time.Duration
+	ExitCode  int
+	ToolCalls map[string]int
+}
+
+// EntityHookExecution holds a batch of executions recorded by an
+// entity.
+type EntityHookExecution struct {
+	Tag        string
+	Executions []HookExecution
+}
+
+// EntityHookExecutions holds the parameters for recording executions
+// by a set of entities.
+type EntityHookExecutions struct {
+	Entities []EntityHookExecution
+}
+
+// HookExecutionsResult holds the executions recorded for an entity,
+// most recent first, or an error.
+type HookExecutionsResult struct {
+	Error      *Error
+	Executions []HookExecution
+}
+
+// HookExecutionsResults holds the bulk operation result of an API call
+// that returns recorded executions.
+type HookExecutionsResults struct {
+	Results []HookExecutionsResult
+}
+
 // StringBoolResult holds the result of an API call that returns a
 // string and a boolean.
 type StringBoolResult struct {
@@ -383,6 +504,11 @@ type AgentGetEntitiesResult struct {
 type VersionResult struct {
 	Version *version.Number
 	Error   *Error
+
+	// Rollback is true if Version is the version the model's agents
+	// ran before an upgrade that has been rolled back; the agent
+	// should downgrade to it even if that would otherwise be refused.
+	Rollback bool
 }
 
 // VersionResults is a list of versions for the requested entities.
@@ -33,6 +33,37 @@ type SetModelAgentVersion struct {
 	Version version.Number
 }
 
+// StartUpgradeRollout contains the arguments for the
+// StartUpgradeRollout client API call.
+type StartUpgradeRollout struct {
+	Version version.Number `json:"version"`
+
+	// Canaries holds the tags of the machines to upgrade first.
+	Canaries []string `json:"canaries"`
+
+	// BatchSize is the number of machines to upgrade at a time
+	// once the canaries have upgraded.
+	BatchSize int `json:"batch-size"`
+}
+
+// AgentVersionUpgradeResult holds the result of the
+// SetModelAgentVersion and StartUpgradeRollout client API calls.
+type AgentVersionUpgradeResult struct {
+	// NoRollback, if not empty, explains why the upgrade cannot be
+	// rolled back.
+	NoRollback string `json:"no-rollback,omitempty"`
+}
+
+// UpgradeRollbackInfo describes an agent upgrade to be rolled back.
+type UpgradeRollbackInfo struct {
+	PreviousVersion version.Number `json:"previous-version"`
+	TargetVersion   version.Number `json:"target-version"`
+
+	// BackupId is the id of the backup of the controller to restore
+	// before the agent version is reset, if there is one.
+	BackupId string `json:"backup-id,omitempty"`
+}
+
 // ModelInfo holds information about the Juju model.
 type ModelInfo struct {
 	// The json names for the fields below are as per the older
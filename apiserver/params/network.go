@@ -586,6 +586,83 @@ type ProviderSpace struct {
 	Error      *Error   `json:"Error,omitempty"`
 }
 
+// FirewallRule holds a model-level firewall rule.
+type FirewallRule struct {
+	Name             string      `json:"Name"`
+	SourceSpace      string      `json:"SourceSpace,omitempty"`
+	DestinationSpace string      `json:"DestinationSpace,omitempty"`
+	DestinationCIDRs []string    `json:"DestinationCIDRs,omitempty"`
+	Ports            []PortRange `json:"Ports"`
+}
+
+// FromNetworkFirewallRule is a convenience helper to create a parameter
+// out of the network type, here for FirewallRule.
+func FromNetworkFirewallRule(rule network.FirewallRule) FirewallRule {
+	ports := make([]PortRange, len(rule.Ports))
+	for i, pr := range rule.Ports {
+		ports[i] = FromNetworkPortRange(pr)
+	}
+	return FirewallRule{
+		Name:             rule.Name,
+		SourceSpace:      rule.SourceSpace,
+		DestinationSpace: rule.DestinationSpace,
+		DestinationCIDRs: rule.DestinationCIDRs,
+		Ports:            ports,
+	}
+}
+
+// NetworkFirewallRule is a convenience helper to return the parameter
+// as network type, here for FirewallRule.
+func (rule FirewallRule) NetworkFirewallRule() network.FirewallRule {
+	var ports []network.PortRange
+	for _, pr := range rule.Ports {
+		ports = append(ports, pr.NetworkPortRange())
+	}
+	return network.FirewallRule{
+		Name:             rule.Name,
+		SourceSpace:      rule.SourceSpace,
+		DestinationSpace: rule.DestinationSpace,
+		DestinationCIDRs: rule.DestinationCIDRs,
+		Ports:            ports,
+	}
+}
+
+// FirewallRules holds the arguments of the AddFirewallRules API call.
+type FirewallRules struct {
+	Rules []FirewallRule `json:"Rules"`
+}
+
+// FirewallRuleNames holds the arguments of the RemoveFirewallRules
+// API call.
+type FirewallRuleNames struct {
+	Names []string `json:"Names"`
+}
+
+// ListFirewallRulesResults holds the result of a ListFirewallRules
+// API call.
+type ListFirewallRulesResults struct {
+	Rules []FirewallRule `json:"Rules"`
+}
+
+// EgressRule holds a model-level firewall rule resolved for a machine.
+type EgressRule struct {
+	Name             string      `json:"Name"`
+	DestinationCIDRs []string    `json:"DestinationCIDRs"`
+	Ports            []PortRange `json:"Ports"`
+}
+
+// EgressRulesResult holds the egress rules for a machine, or an error.
+type EgressRulesResult struct {
+	Rules []EgressRule `json:"Rules"`
+	Error *Error       `json:"Error,omitempty"`
+}
+
+// EgressRulesResults holds the bulk operation result of an API call
+// that returns egress rules.
+type EgressRulesResults struct {
+	Results []EgressRulesResult `json:"Results"`
+}
+
 type ProxyConfig struct {
 	HTTP    string `json:"HTTP"`
 	HTTPS   string `json:"HTTPS"`
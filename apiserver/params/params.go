@@ -227,6 +227,14 @@ type ServiceUpdate struct {
 	SettingsStrings map[string]string
 	SettingsYAML    string // Takes precedence over SettingsStrings if both are present.
 	Constraints     *constraints.Value
+
+	// StatusAggregation, if set, holds the policy used to derive the
+	// service's status from its units' statuses.
+	StatusAggregation *string
+
+	// HookTimeout, if set, holds the time after which the service's
+	// running hooks are killed; zero reverts to the model default.
+	HookTimeout *time.Duration
 }
 
 // ServiceSetCharm sets the charm for a given service.
@@ -475,6 +483,11 @@ type StateServingInfo struct {
 	// this will be passed as the KeyFile argument to MongoDB
 	SharedSecret   string
 	SystemIdentity string
+	// SecretsKey is the key with which the controllers encrypt
+	// charm secrets. Unlike the other fields it is not stored in
+	// state, so that a copy of the database alone does not reveal
+	// the secrets.
+	SecretsKey string `json:",omitempty"`
 }
 
 // IsMasterResult holds the result of an IsMaster API call.
@@ -661,8 +674,29 @@ type ControllersSpecs struct {
 	Specs []ControllersSpec
 }
 
+// RemoveControllerSpec contains arguments for removing a single
+// controller machine with the RemoveController API call.
+type RemoveControllerSpec struct {
+	MachineTag string `json:"machine-tag"`
+	// Replace requests that a new controller machine is started to
+	// take over the removed machine's vote.
+	Replace bool `json:"replace,omitempty"`
+	// Constraints, Series and Placement apply to the replacement
+	// machine. Constraints and Series default to those of the
+	// machine being removed.
+	Constraints constraints.Value `json:"constraints,omitempty"`
+	Series      string            `json:"series,omitempty"`
+	Placement   string            `json:"placement,omitempty"`
+}
+
+// RemoveControllerSpecs contains all the arguments
+// for the RemoveController API call.
+type RemoveControllerSpecs struct {
+	Specs []RemoveControllerSpec
+}
+
 // ControllersChangeResult contains the results
-// of a single EnableHA API call or
+// of a single EnableHA or RemoveController API call or
 // an error.
 type ControllersChangeResult struct {
 	Result ControllersChanges
@@ -670,7 +704,7 @@ type ControllersChangeResult struct {
 }
 
 // ControllersChangeResults contains the results
-// of the EnableHA API call.
+// of the EnableHA and RemoveController API calls.
 type ControllersChangeResults struct {
 	Results []ControllersChangeResult
 }
@@ -819,6 +853,31 @@ type ResumeReplicationParams struct {
 	Members []replicaset.Member
 }
 
+// HAMemberStatus holds the replica set status of a single
+// controller machine.
+type HAMemberStatus struct {
+	// MachineTag is empty for replica set members that have
+	// no corresponding controller machine.
+	MachineTag    string        `json:"machine-tag,omitempty"`
+	ReplicaSetId  int           `json:"replicaset-id"`
+	Address       string        `json:"address,omitempty"`
+	WantsVote     bool          `json:"wants-vote"`
+	HasVote       bool          `json:"has-vote"`
+	Voting        bool          `json:"voting"`
+	State         string        `json:"state,omitempty"`
+	Healthy       bool          `json:"healthy"`
+	Lag           time.Duration `json:"lag"`
+	LastHeartbeat *time.Time    `json:"last-heartbeat,omitempty"`
+	Message       string        `json:"message,omitempty"`
+	Problems      []string      `json:"problems,omitempty"`
+}
+
+// HAStatusResult holds the result of a HAStatus call.
+type HAStatusResult struct {
+	Members  []HAMemberStatus `json:"members"`
+	Problems []string         `json:"problems,omitempty"`
+}
+
 // MeterStatusParam holds meter status information to be set for the specified tag.
 type MeterStatusParam struct {
 	Tag  string `json:"tag"`
@@ -15,6 +15,14 @@ type RetryStrategy struct {
 	MaxRetryTime    time.Duration
 	JitterRetryTime bool
 	RetryTimeFactor int64
+
+	// MaxAttempts limits the number of times a failed hook is retried;
+	// zero means there is no limit.
+	MaxAttempts int
+
+	// HookKinds holds the kinds of hook that are retried; if it is
+	// empty, all hooks are retried.
+	HookKinds []string
 }
 
 // RetryStrategyResult holds a RetryStrategy or an error.
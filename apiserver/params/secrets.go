@@ -0,0 +1,108 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// AddSecretArg holds the parameters for a unit to add a secret, or a
+// new revision of an existing secret.
+type AddSecretArg struct {
+	UnitTag string
+
+	// SecretId identifies the secret to revise; it is empty when
+	// adding a new secret.
+	SecretId string
+
+	Description    string
+	RotateInterval time.Duration
+	Data           map[string]string
+}
+
+// AddSecretArgs holds the parameters for adding a set of secrets.
+type AddSecretArgs struct {
+	Args []AddSecretArg
+}
+
+// GetSecretArg holds the parameters for a unit to read a secret.
+type GetSecretArg struct {
+	UnitTag  string
+	SecretId string
+
+	// Revision is the revision to read, or zero for the latest.
+	Revision int
+}
+
+// GetSecretArgs holds the parameters for reading a set of secrets.
+type GetSecretArgs struct {
+	Args []GetSecretArg
+}
+
+// SecretValueResult holds the values of a revision of a secret, or an
+// error.
+type SecretValueResult struct {
+	Revision int
+	Data     map[string]string
+	Error    *Error
+}
+
+// SecretValueResults holds the bulk operation result of an API call
+// that returns secret values.
+type SecretValueResults struct {
+	Results []SecretValueResult
+}
+
+// GrantSecretArg holds the parameters for a unit to grant or revoke
+// access to a secret by a unit or relation.
+type GrantSecretArg struct {
+	UnitTag    string
+	SecretId   string
+	SubjectTag string
+}
+
+// GrantSecretArgs holds the parameters for granting or revoking
+// access to a set of secrets.
+type GrantSecretArgs struct {
+	Args []GrantSecretArg
+}
+
+// SecretIds holds the ids of a set of secrets.
+type SecretIds struct {
+	Ids []string
+}
+
+// SecretRevision describes a single revision of a secret.
+type SecretRevision struct {
+	Revision int
+	Keys     []string
+	Created  time.Time
+}
+
+// SecretDetails describes a secret without revealing its values.
+type SecretDetails struct {
+	Id             string
+	OwnerTag       string
+	Description    string
+	RotateInterval time.Duration
+	NextRotation   *time.Time
+	Revision       int
+	Keys           []string
+	Grants         []string
+	Created        time.Time
+	Updated        time.Time
+	Revisions      []SecretRevision
+}
+
+// SecretDetailsResult holds the details of a secret, or an error.
+type SecretDetailsResult struct {
+	Result *SecretDetails
+	Error  *Error
+}
+
+// SecretDetailsResults holds the bulk operation result of an API call
+// that returns secret details.
+type SecretDetailsResults struct {
+	Results []SecretDetailsResult
+}
@@ -26,11 +26,22 @@ type StatusParams struct {
 type FullStatus struct {
 	ModelName        string
 	AvailableVersion string
+	UpgradeRollout   *UpgradeRolloutStatus
 	Machines         map[string]MachineStatus
 	Services         map[string]ServiceStatus
 	Relations        []RelationStatus
 }
 
+// UpgradeRolloutStatus holds the progress of a staged agent upgrade.
+type UpgradeRolloutStatus struct {
+	Version  string
+	Status   string
+	Message  string
+	Canaries []string
+	Released int
+	Total    int
+}
+
 // MachineStatus holds status info about a machine.
 type MachineStatus struct {
 	AgentStatus    DetailedStatus
@@ -59,6 +70,10 @@ type ServiceStatus struct {
 	Units         map[string]UnitStatus
 	MeterStatuses map[string]MeterStatus
 	Status        DetailedStatus
+
+	// WorkloadVersion holds the workload version most common among
+	// the service's units.
+	WorkloadVersion string
 }
 
 // MeterStatus represents the meter status of a unit.
@@ -80,6 +95,14 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// WorkloadVersion holds the version of the workload the unit is
+	// running, as set by its charm.
+	WorkloadVersion string
+
+	// Health holds the health of the unit, as evaluated by its agent
+	// against the health checks declared by its charm.
+	Health DetailedStatus
 }
 
 // RelationStatus holds status info about a relation.
@@ -175,6 +198,8 @@ const (
 	KindUnitAgent HistoryKind = "juju-unit"
 	// KindWorkload represents a charm workload status history entry.
 	KindWorkload HistoryKind = "workload"
+	// KindWorkloadVersion represents a workload version history entry.
+	KindWorkloadVersion HistoryKind = "workload-version"
 	// KindMachineInstance represents an entry for a machine instance.
 	KindMachineInstance = "machine"
 	// KindMachine represents an entry for a machine agent.
@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/storage"
+import (
+	"time"
+
+	"github.com/juju/juju/storage"
+)
 
 // MachineBlockDevices holds a machine tag and the block devices present
 // on that machine.
@@ -18,6 +22,19 @@ type SetMachineBlockDevices struct {
 	MachineBlockDevices []MachineBlockDevices `json:"machineblockdevices"`
 }
 
+// MachineFilesystemUsage holds a machine tag and the usage of the
+// filesystems mounted on that machine.
+type MachineFilesystemUsage struct {
+	Machine string                    `json:"machine"`
+	Usage   []storage.FilesystemUsage `json:"usage,omitempty"`
+}
+
+// SetMachineFilesystemUsage holds the arguments for recording the usage
+// of the filesystems mounted on a set of machines.
+type SetMachineFilesystemUsage struct {
+	MachineFilesystemUsage []MachineFilesystemUsage `json:"machinefilesystemusage"`
+}
+
 // BlockDeviceResult holds the result of an API call to retrieve details
 // of a block device.
 type BlockDeviceResult struct {
@@ -313,6 +330,27 @@ type FilesystemAttachment struct {
 type FilesystemAttachmentInfo struct {
 	MountPoint string `json:"mountpoint,omitempty"`
 	ReadOnly   bool   `json:"read-only,omitempty"`
+
+	// Usage is the most recently reported usage of the attached
+	// filesystem, if any. Usage is reported by the machine agent,
+	// and is ignored when setting filesystem attachment info.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+}
+
+// FilesystemUsage describes the space and inode usage of an
+// attached filesystem.
+type FilesystemUsage struct {
+	// Size, Used and Available are measured in bytes.
+	Size      uint64 `json:"size"`
+	Used      uint64 `json:"used"`
+	Available uint64 `json:"available"`
+
+	Inodes     uint64 `json:"inodes,omitempty"`
+	InodesUsed uint64 `json:"inodes-used,omitempty"`
+	InodesFree uint64 `json:"inodes-free,omitempty"`
+
+	// Updated is the time at which the usage was reported.
+	Updated *time.Time `json:"updated,omitempty"`
 }
 
 // FilesystemAttachments describes a set of storage filesystem attachments.
@@ -462,6 +500,10 @@ type StorageAttachmentDetails struct {
 	// Location holds location (mount point/device path) of
 	// the attached storage.
 	Location string `json:"location,omitempty"`
+
+	// Usage holds the most recently reported usage of the
+	// attached storage's filesystem, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
 }
 
 // StoragePool holds data for a pool instance.
@@ -198,7 +198,8 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 }
 
 // machineSubnetsAndZones returns a map of subnet provider-specific id
-// to list of availability zone names for that subnet. The result can
+// (or CIDR, with Juju-managed spaces) to list of availability zone
+// names for that subnet. The result can
 // be empty if there are no spaces constraints specified for the
 // machine, or there's an error fetching them.
 func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]string, error) {
@@ -234,6 +235,10 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 	if len(subnets) == 0 {
 		return nil, errors.Errorf("cannot use space %q as deployment target: no subnets", spaceName)
 	}
+	envConfig, err := p.st.ModelConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get model config")
+	}
 	subnetsToZones := make(map[string][]string, len(subnets))
 	for _, subnet := range subnets {
 		warningPrefix := fmt.Sprintf(
@@ -241,6 +246,16 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 			subnet.CIDR(), spaceName, m.Id(),
 		)
 		providerId := subnet.ProviderId()
+		if providerId == "" && envConfig.JujuManagedSpaces() {
+			// With Juju-managed spaces, subnets are known to the
+			// provider only by their CIDRs, and may have no zone.
+			var zones []string
+			if zone := subnet.AvailabilityZone(); zone != "" {
+				zones = []string{zone}
+			}
+			subnetsToZones[subnet.CIDR()] = zones
+			continue
+		}
 		if providerId == "" {
 			logger.Warningf(warningPrefix + "no ProviderId set")
 			continue
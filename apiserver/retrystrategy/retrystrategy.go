@@ -12,12 +12,13 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
 
-// Right now, these are defined as constants, but the plan is to maybe make
-// them configurable in the future
+// These are the defaults for the retry times, which may be overridden
+// by the hook-retry-min-delay and hook-retry-max-delay model settings.
 const (
 	MinRetryTime    = 5 * time.Second
 	MaxRetryTime    = 5 * time.Minute
@@ -84,16 +85,7 @@ func (h *RetryStrategyAPI) RetryStrategy(args params.Entities) (params.RetryStra
 		}
 		err = common.ErrPerm
 		if canAccess(tag) {
-			// Right now the only real configurable value is ShouldRetry,
-			// which is taken from the environment
-			// The rest are hardcoded
-			results.Results[i].Result = &params.RetryStrategy{
-				ShouldRetry:     config.AutomaticallyRetryHooks(),
-				MinRetryTime:    MinRetryTime,
-				MaxRetryTime:    MaxRetryTime,
-				JitterRetryTime: JitterRetryTime,
-				RetryTimeFactor: RetryTimeFactor,
-			}
+			results.Results[i].Result = retryStrategy(config)
 			err = nil
 		}
 		results.Results[i].Error = common.ServerError(err)
@@ -101,8 +93,38 @@ func (h *RetryStrategyAPI) RetryStrategy(args params.Entities) (params.RetryStra
 	return results, nil
 }
 
-// WatchRetryStrategy watches for changes to the environment. Currently we only allow
-// changes to the boolean that determines whether retries should be attempted or not.
+// retryStrategy returns the hook retry strategy described by the
+// given model config. Jitter and the backoff factor are not
+// configurable.
+func retryStrategy(cfg *config.Config) *params.RetryStrategy {
+	strategy := &params.RetryStrategy{
+		ShouldRetry:     cfg.AutomaticallyRetryHooks(),
+		MinRetryTime:    cfg.HookRetryMinDelay(),
+		MaxRetryTime:    cfg.HookRetryMaxDelay(),
+		JitterRetryTime: JitterRetryTime,
+		RetryTimeFactor: RetryTimeFactor,
+		MaxAttempts:     cfg.HookRetryMaxAttempts(),
+		HookKinds:       cfg.HookRetryKinds(),
+	}
+	// When only one of the bounds is configured, keep the default
+	// for the other one consistent with it.
+	if strategy.MinRetryTime == 0 {
+		strategy.MinRetryTime = MinRetryTime
+		if strategy.MaxRetryTime > 0 && strategy.MaxRetryTime < MinRetryTime {
+			strategy.MinRetryTime = strategy.MaxRetryTime
+		}
+	}
+	if strategy.MaxRetryTime == 0 {
+		strategy.MaxRetryTime = MaxRetryTime
+		if strategy.MinRetryTime > MaxRetryTime {
+			strategy.MaxRetryTime = strategy.MinRetryTime
+		}
+	}
+	return strategy
+}
+
+// WatchRetryStrategy watches for changes to the environment, which may
+// change the retry strategy.
 func (h *RetryStrategyAPI) WatchRetryStrategy(args params.Entities) (params.NotifyWatchResults, error) {
 	results := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Entities)),
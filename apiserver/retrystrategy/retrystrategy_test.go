@@ -5,6 +5,8 @@
 package retrystrategy_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -118,6 +120,30 @@ func (s *retryStrategySuite) TestRetryStrategy(c *gc.C) {
 	c.Assert(r.Results[0].Result, jc.DeepEquals, expected)
 }
 
+func (s *retryStrategySuite) TestRetryStrategyPolicy(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"hook-retry-max-attempts": 3,
+		"hook-retry-min-delay":    "10s",
+		"hook-retry-kinds":        "install,config-changed",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.unit.Tag().String()}}}
+	r, err := s.strategy.RetryStrategy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 1)
+	c.Assert(r.Results[0].Error, gc.IsNil)
+	c.Assert(r.Results[0].Result, jc.DeepEquals, &params.RetryStrategy{
+		ShouldRetry:     true,
+		MinRetryTime:    10 * time.Second,
+		MaxRetryTime:    retrystrategy.MaxRetryTime,
+		JitterRetryTime: retrystrategy.JitterRetryTime,
+		RetryTimeFactor: retrystrategy.RetryTimeFactor,
+		MaxAttempts:     3,
+		HookKinds:       []string{"install", "config-changed"},
+	})
+}
+
 func (s *retryStrategySuite) setRetryStrategy(c *gc.C, automaticallyRetryHooks bool) {
 	err := s.State.UpdateModelConfig(map[string]interface{}{"automatically-retry-hooks": automaticallyRetryHooks}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/healthcheck"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	jujuversion "github.com/juju/juju/version"
@@ -93,6 +94,9 @@ func AddCharmWithAuthorization(st *state.State, args params.AddCharmWithAuthoriz
 	if !ok {
 		return errors.Errorf("expected a charm archive, got %T", downloadedCharm)
 	}
+	if _, err := healthcheck.ReadArchiveChecks(downloadedBundle.Path); err != nil {
+		return errors.Annotate(err, "invalid charm archive")
+	}
 	archive, err := os.Open(downloadedBundle.Path)
 	if err != nil {
 		return errors.Annotate(err, "cannot read downloaded charm")
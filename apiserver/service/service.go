@@ -29,6 +29,13 @@ var (
 
 func init() {
 	common.RegisterStandardFacade("Service", 3, NewAPI)
+
+	// Version 4 has the same methods as version 3, but Update accepts
+	// a status aggregation policy and a hook timeout. Version 3
+	// clients never send either, so they are served by the same
+	// implementation; the new version lets clients refuse to send them
+	// to a controller that would silently ignore them.
+	common.RegisterStandardFacade("Service", 4, NewAPI)
 }
 
 // Service defines the methods on the service API end point.
@@ -253,6 +260,19 @@ func (api *API) Update(args params.ServiceUpdate) error {
 			return errors.Trace(err)
 		}
 	}
+	// Set the policy for deriving the service's status.
+	if args.StatusAggregation != nil {
+		policy := state.StatusAggregation(*args.StatusAggregation)
+		if err = svc.SetStatusAggregation(policy); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// Set the time after which the service's hooks are killed.
+	if args.HookTimeout != nil {
+		if err = svc.SetHookTimeout(*args.HookTimeout); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// Set up service's settings.
 	if args.SettingsYAML != "" {
 		if err = serviceSetSettingsYAML(svc, args.SettingsYAML); err != nil {
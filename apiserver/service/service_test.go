@@ -8,6 +8,7 @@ import (
 	"io"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -1367,6 +1368,66 @@ func (s *serviceSuite) TestServiceUpdateSetMinUnitsError(c *gc.C) {
 	c.Assert(service.MinUnits(), gc.Equals, 0)
 }
 
+func (s *serviceSuite) TestServiceUpdateSetStatusAggregation(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	policy := "majority"
+	args := params.ServiceUpdate{
+		ServiceName:       "dummy",
+		StatusAggregation: &policy,
+	}
+	err := s.serviceApi.Update(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.StatusAggregation(), gc.Equals, state.StatusAggregationMajority)
+}
+
+func (s *serviceSuite) TestServiceUpdateSetStatusAggregationError(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	policy := "best-of"
+	args := params.ServiceUpdate{
+		ServiceName:       "dummy",
+		StatusAggregation: &policy,
+	}
+	err := s.serviceApi.Update(args)
+	c.Assert(err, gc.ErrorMatches, `status aggregation "best-of" not valid`)
+
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.StatusAggregation(), gc.Equals, state.StatusAggregationWorstOf)
+}
+
+func (s *serviceSuite) TestServiceUpdateSetHookTimeout(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	timeout := 5 * time.Minute
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		HookTimeout: &timeout,
+	}
+	err := s.serviceApi.Update(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.HookTimeout(), gc.Equals, 5*time.Minute)
+}
+
+func (s *serviceSuite) TestServiceUpdateSetHookTimeoutError(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	timeout := -time.Minute
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		HookTimeout: &timeout,
+	}
+	err := s.serviceApi.Update(args)
+	c.Assert(err, gc.ErrorMatches, `negative hook timeout -1m0s not valid`)
+
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.HookTimeout(), gc.Equals, time.Duration(0))
+}
+
 func (s *serviceSuite) TestServiceUpdateSetSettingsStrings(c *gc.C) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 
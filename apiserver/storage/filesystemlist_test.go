@@ -4,6 +4,8 @@
 package storage_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -136,6 +138,41 @@ func (s *filesystemSuite) TestListFilesystemsAttachmentInfo(c *gc.C) {
 	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
 }
 
+func (s *filesystemSuite) TestListFilesystemsAttachmentUsage(c *gc.C) {
+	updated := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.filesystemAttachment.info = &state.FilesystemAttachmentInfo{
+		MountPoint: "/srv",
+	}
+	s.filesystemAttachment.usage = &state.FilesystemUsage{
+		Size:      1024,
+		Used:      256,
+		Available: 768,
+		Updated:   updated,
+	}
+	usage := &params.FilesystemUsage{
+		Size:      1024,
+		Used:      256,
+		Available: 768,
+		Updated:   &updated,
+	}
+	expected := s.expectedFilesystemDetails()
+	expected.MachineAttachments[s.machineTag.String()] = params.FilesystemAttachmentInfo{
+		MountPoint: "/srv",
+		Usage:      usage,
+	}
+	expectedStorageAttachmentDetails := expected.Storage.Attachments["unit-mysql-0"]
+	expectedStorageAttachmentDetails.Location = "/srv"
+	expectedStorageAttachmentDetails.Usage = usage
+	expected.Storage.Attachments["unit-mysql-0"] = expectedStorageAttachmentDetails
+	found, err := s.api.ListFilesystems(params.FilesystemFilters{
+		[]params.FilesystemFilter{{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
+}
+
 func (s *filesystemSuite) TestListFilesystemsVolumeBacked(c *gc.C) {
 	s.filesystem.volume = &s.volumeTag
 	expected := s.expectedFilesystemDetails()
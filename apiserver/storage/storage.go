@@ -134,11 +134,13 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 	// Get information from underlying volume or filesystem.
 	var persistent bool
 	var statusEntity status.StatusGetter
+	var filesystem state.Filesystem
 	if si.Kind() != state.StorageKindBlock {
 		// TODO(axw) when we support persistent filesystems,
 		// e.g. CephFS, we'll need to do set "persistent"
 		// here too.
-		filesystem, err := st.StorageInstanceFilesystem(si.StorageTag())
+		var err error
+		filesystem, err = st.StorageInstanceFilesystem(si.StorageTag())
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -172,10 +174,18 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 				return nil, errors.Trace(err)
 			}
 			details := params.StorageAttachmentDetails{
-				a.StorageInstance().String(),
-				a.Unit().String(),
-				machineTag.String(),
-				location,
+				StorageTag: a.StorageInstance().String(),
+				UnitTag:    a.Unit().String(),
+				MachineTag: machineTag.String(),
+				Location:   location,
+			}
+			if filesystem != nil && location != "" {
+				// Usage is only reported for provisioned
+				// filesystem attachments, which have a location.
+				details.Usage, err = filesystemAttachmentUsage(st, machineTag, filesystem.FilesystemTag())
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
 			}
 			storageAttachmentDetails[a.Unit().String()] = details
 		}
@@ -207,6 +217,23 @@ func storageAttachmentInfo(st storageAccess, a state.StorageAttachment) (_ names
 	return machineTag, info.Location, nil
 }
 
+// filesystemAttachmentUsage returns the most recently reported usage of
+// the specified filesystem attachment, or nil if none has been reported.
+func filesystemAttachmentUsage(
+	st storageAccess, machineTag names.MachineTag, filesystemTag names.FilesystemTag,
+) (*params.FilesystemUsage, error) {
+	attachment, err := st.FilesystemAttachment(machineTag, filesystemTag)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if usage, ok := attachment.Usage(); ok {
+		return storagecommon.FilesystemUsageFromState(usage), nil
+	}
+	return nil, nil
+}
+
 // ListPools returns a list of pools.
 // If filter is provided, returned list only contains pools that match
 // the filter.
@@ -627,6 +654,9 @@ func createFilesystemDetails(
 			if err == nil {
 				info = storagecommon.FilesystemAttachmentInfoFromState(stateInfo)
 			}
+			if usage, ok := attachment.Usage(); ok {
+				info.Usage = storagecommon.FilesystemUsageFromState(usage)
+			}
 			details.MachineAttachments[attachment.Machine().String()] = info
 		}
 	}
@@ -245,7 +245,8 @@ func (s *storageSuite) createTestStorageDetails() params.StorageDetails {
 				s.storageTag.String(),
 				s.unitTag.String(),
 				s.machineTag.String(),
-				"", // location
+				"",  // location
+				nil, // usage
 			},
 		},
 	}
@@ -302,6 +303,7 @@ func (s *storageSuite) TestShowStorage(c *gc.C) {
 				s.unitTag.String(),
 				s.machineTag.String(),
 				"",
+				nil,
 			},
 		},
 	}
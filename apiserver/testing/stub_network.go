@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
@@ -499,6 +501,20 @@ func (sb *StubBacking) AddSpace(name string, providerId network.Id, subnets []st
 	return nil
 }
 
+func (sb *StubBacking) SetSubnetSpace(cidr, spaceName string) error {
+	sb.MethodCall(sb, "SetSubnetSpace", cidr, spaceName)
+	if err := sb.NextErr(); err != nil {
+		return err
+	}
+	for _, subnet := range sb.Subnets {
+		if fs, ok := subnet.(*FakeSubnet); ok && fs.info.CIDR == cidr {
+			fs.info.SpaceName = spaceName
+			return nil
+		}
+	}
+	return errors.NotFoundf("subnet %q", cidr)
+}
+
 // GoString implements fmt.GoStringer.
 func (se *StubBacking) GoString() string {
 	return "&StubBacking{}"
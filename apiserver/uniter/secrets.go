@@ -0,0 +1,271 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AddSecrets adds a secret owned by the service of each given unit,
+// or a new revision of a secret the service already owns, and returns
+// the ids of the secrets.
+func (u *UniterAPIV4) AddSecrets(args params.AddSecretArgs) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, arg := range args.Args {
+		id, err := u.addSecret(canAccess, arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = id
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) addSecret(canAccess common.AuthFunc, arg params.AddSecretArg) (string, error) {
+	unit, err := u.getSecretsUnit(canAccess, arg.UnitTag)
+	if err != nil {
+		return "", err
+	}
+	if arg.SecretId == "" {
+		secret, err := u.st.AddSecret(state.AddSecretParams{
+			Owner:          names.NewServiceTag(unit.ServiceName()),
+			Description:    arg.Description,
+			RotateInterval: arg.RotateInterval,
+			Data:           arg.Data,
+		})
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return secret.Id(), nil
+	}
+	secret, err := u.getOwnedSecret(unit, arg.SecretId)
+	if err != nil {
+		return "", err
+	}
+	if err := secret.AddRevision(arg.Data); err != nil {
+		return "", errors.Trace(err)
+	}
+	return secret.Id(), nil
+}
+
+// GetSecrets returns the values of the requested revision of each
+// given secret, if it is owned by the service of the given unit or
+// has been granted to the unit or to a relation its service is in.
+func (u *UniterAPIV4) GetSecrets(args params.GetSecretArgs) (params.SecretValueResults, error) {
+	result := params.SecretValueResults{
+		Results: make([]params.SecretValueResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SecretValueResults{}, err
+	}
+	for i, arg := range args.Args {
+		revision, data, err := u.getSecret(canAccess, arg)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Revision = revision
+		result.Results[i].Data = data
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) getSecret(canAccess common.AuthFunc, arg params.GetSecretArg) (int, map[string]string, error) {
+	unit, err := u.getSecretsUnit(canAccess, arg.UnitTag)
+	if err != nil {
+		return 0, nil, err
+	}
+	secret, err := u.st.Secret(arg.SecretId)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	canRead, err := u.canReadSecret(unit, secret)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	if !canRead {
+		return 0, nil, common.ErrPerm
+	}
+	revision := arg.Revision
+	if revision == 0 {
+		revision = secret.Revision()
+	}
+	data, err := secret.Value(revision)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return revision, data, nil
+}
+
+// canReadSecret returns whether the unit may read the secret.
+func (u *UniterAPIV4) canReadSecret(unit *state.Unit, secret *state.Secret) (bool, error) {
+	if secret.Owner().Id() == unit.ServiceName() || secret.IsGranted(unit.UnitTag()) {
+		return true, nil
+	}
+	grants, err := secret.Grants()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, grant := range grants {
+		relationTag, ok := grant.(names.RelationTag)
+		if !ok {
+			continue
+		}
+		relation, err := u.st.KeyRelation(relationTag.Id())
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		if _, err := relation.Endpoint(unit.ServiceName()); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SecretsDueForRotation returns, for each given unit, the ids of the
+// secrets owned by its service that are due to be rotated.
+func (u *UniterAPIV4) SecretsDueForRotation(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	now := time.Now()
+	for i, entity := range args.Entities {
+		ids, err := u.secretsDueForRotation(canAccess, entity.Tag, now)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = ids
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) secretsDueForRotation(canAccess common.AuthFunc, unitTag string, now time.Time) ([]string, error) {
+	unit, err := u.getSecretsUnit(canAccess, unitTag)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := u.st.SecretsDueForRotation(names.NewServiceTag(unit.ServiceName()), now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ids := make([]string, len(secrets))
+	for i, secret := range secrets {
+		ids[i] = secret.Id()
+	}
+	return ids, nil
+}
+
+// GrantSecrets shares each given secret with a unit, or with the units
+// of the services in a relation. The secret must be owned by the
+// service of the given unit, and the relation must be one it is in.
+func (u *UniterAPIV4) GrantSecrets(args params.GrantSecretArgs) (params.ErrorResults, error) {
+	return u.updateSecretGrants(args, func(secret *state.Secret, subject names.Tag) error {
+		return secret.Grant(subject)
+	})
+}
+
+// RevokeSecrets withdraws grants made by GrantSecrets.
+func (u *UniterAPIV4) RevokeSecrets(args params.GrantSecretArgs) (params.ErrorResults, error) {
+	return u.updateSecretGrants(args, func(secret *state.Secret, subject names.Tag) error {
+		return secret.Revoke(subject)
+	})
+}
+
+func (u *UniterAPIV4) updateSecretGrants(
+	args params.GrantSecretArgs, update func(*state.Secret, names.Tag) error,
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		err := u.updateSecretGrant(canAccess, arg, update)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) updateSecretGrant(
+	canAccess common.AuthFunc, arg params.GrantSecretArg, update func(*state.Secret, names.Tag) error,
+) error {
+	unit, err := u.getSecretsUnit(canAccess, arg.UnitTag)
+	if err != nil {
+		return err
+	}
+	secret, err := u.getOwnedSecret(unit, arg.SecretId)
+	if err != nil {
+		return err
+	}
+	subject, err := names.ParseTag(arg.SubjectTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch subject := subject.(type) {
+	case names.UnitTag:
+		if _, err := u.st.Unit(subject.Id()); err != nil {
+			return errors.Trace(err)
+		}
+	case names.RelationTag:
+		relation, err := u.st.KeyRelation(subject.Id())
+		if errors.IsNotFound(err) {
+			return common.ErrPerm
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := relation.Endpoint(unit.ServiceName()); err != nil {
+			return common.ErrPerm
+		}
+	default:
+		return errors.NotValidf("secret grant to %s", names.ReadableString(subject))
+	}
+	return errors.Trace(update(secret, subject))
+}
+
+// getSecretsUnit returns the unit with the supplied tag, if the caller
+// may act on its behalf.
+func (u *UniterAPIV4) getSecretsUnit(canAccess common.AuthFunc, unitTag string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
+
+// getOwnedSecret returns the secret with the supplied id, if it is
+// owned by the unit's service.
+func (u *UniterAPIV4) getOwnedSecret(unit *state.Unit, id string) (*state.Secret, error) {
+	secret, err := u.st.Secret(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if secret.Owner().Id() != unit.ServiceName() {
+		return nil, common.ErrPerm
+	}
+	return secret, nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -24,12 +25,33 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
 func init() {
 	common.RegisterStandardFacade("Uniter", 3, NewUniterAPIV3)
+	common.RegisterStandardFacade("Uniter", 4, NewUniterAPIV4)
+}
+
+// UniterAPIV4 implements the API version 4, used by the uniter worker.
+// It adds GoalStates, WorkloadVersion, SetWorkloadVersion, HookTimeouts,
+// RecordHookExecutions, SetUnitHealth, CharmState, CommitHookChanges and
+// the secrets methods.
+type UniterAPIV4 struct {
+	UniterAPIV3
+}
+
+// NewUniterAPIV4 creates a new instance of the Uniter API, version 4.
+func NewUniterAPIV4(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV4, error) {
+	baseAPI, err := NewUniterAPIV3(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV4{
+		UniterAPIV3: *baseAPI,
+	}, nil
 }
 
 // UniterAPIV3 implements the API version 3, used by the uniter worker.
@@ -409,6 +431,432 @@ func (u *UniterAPIV3) Resolved(args params.Entities) (params.ResolvedModeResults
 	return result, nil
 }
 
+// WorkloadVersion returns the workload version set for each given unit.
+func (u *UniterAPIV4) WorkloadVersion(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = unit.WorkloadVersion()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetWorkloadVersion sets the workload version of each given unit.
+func (u *UniterAPIV4) SetWorkloadVersion(args params.EntityWorkloadVersions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetWorkloadVersion(entity.WorkloadVersion)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CharmState returns the key/value data each given unit's charm has
+// persisted between hooks.
+func (u *UniterAPIV4) CharmState(args params.Entities) (params.CharmStateResults, error) {
+	result := params.CharmStateResults{
+		Results: make([]params.CharmStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.CharmStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].CharmState, err = unit.CharmState()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// CommitHookChanges commits the changes made by a hook of each given
+// unit: its relation settings and its charm state are written in a
+// single transaction, so that either all of them are applied or none
+// are.
+func (u *UniterAPIV4) CommitHookChanges(args params.CommitHookChangesArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = u.commitHookChanges(canAccess, tag, arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV4) commitHookChanges(canAccess common.AuthFunc, tag names.UnitTag, arg params.CommitHookChangesArg) error {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return err
+	}
+	relationSettings := make([]*state.Settings, len(arg.RelationUnitSettings))
+	for i, rus := range arg.RelationUnitSettings {
+		if rus.Unit != arg.Tag {
+			return common.ErrPerm
+		}
+		relUnit, err := u.getRelationUnit(canAccess, rus.Relation, tag)
+		if err != nil {
+			return err
+		}
+		settings, err := relUnit.Settings()
+		if err != nil {
+			return err
+		}
+		for k, v := range rus.Settings {
+			if v == "" {
+				settings.Delete(k)
+			} else {
+				settings.Set(k, v)
+			}
+		}
+		relationSettings[i] = settings
+	}
+	var charmState map[string]string
+	if arg.UpdateCharmState {
+		charmState = arg.CharmState
+		if charmState == nil {
+			charmState = map[string]string{}
+		}
+	}
+	return unit.CommitHookChanges(relationSettings, charmState)
+}
+
+// SetUnitHealth sets the health of each given unit, as evaluated by
+// the unit agent against the health checks declared by its charm.
+func (u *UniterAPIV4) SetUnitHealth(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetHealth(entity.Status, entity.Info, entity.Data)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GoalStates returns, for each given unit, the units expected in its
+// service and in each of the service's relations, with their status.
+func (u *UniterAPIV4) GoalStates(args params.Entities) (params.GoalStateResults, error) {
+	result := params.GoalStateResults{
+		Results: make([]params.GoalStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.GoalStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = u.oneGoalState(unit)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// HookTimeouts returns the time after which each given unit's running
+// hooks should be killed: the unit's service override, if set, or
+// else the model's hook-timeout.
+func (u *UniterAPIV4) HookTimeouts(args params.Entities) (params.HookTimeoutResults, error) {
+	result := params.HookTimeoutResults{
+		Results: make([]params.HookTimeoutResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.HookTimeoutResults{}, err
+	}
+	cfg, err := u.st.ModelConfig()
+	if err != nil {
+		return params.HookTimeoutResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			result.Results[i].Timeout, err = u.oneHookTimeout(tag, cfg.HookTimeout())
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RecordHookExecutions records, for each given unit, the timing and
+// outcome of a batch of hook, action and juju-run executions.
+func (u *UniterAPIV4) RecordHookExecutions(args params.EntityHookExecutions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				executions := make([]state.HookExecution, len(entity.Executions))
+				for j, execution := range entity.Executions {
+					executions[j] = state.HookExecution{
+						Kind:      execution.Kind,
+						Name:      execution.Name,
+						Started:   execution.Started,
+						Duration:  execution.Duration,
+						ExitCode:  execution.ExitCode,
+						ToolCalls: execution.ToolCalls,
+					}
+				}
+				err = unit.RecordHookExecutions(executions)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) oneHookTimeout(tag names.UnitTag, modelTimeout time.Duration) (time.Duration, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return 0, err
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return 0, err
+	}
+	if timeout := service.HookTimeout(); timeout > 0 {
+		return timeout, nil
+	}
+	return modelTimeout, nil
+}
+
+// Goal state statuses of units.
+const (
+	// goalStateActive is the status of a unit that is running, or
+	// that has joined the relation.
+	goalStateActive = "active"
+
+	// goalStateJoining is the status of a unit that is expected but
+	// has not yet started, or not yet joined the relation.
+	goalStateJoining = "joining"
+
+	// goalStateDying is the status of a unit that is being removed.
+	goalStateDying = "dying"
+)
+
+func (u *UniterAPIV3) oneGoalState(unit *state.Unit) (*params.GoalState, error) {
+	service, err := unit.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	goalState := &params.GoalState{
+		Units:     make(map[string]params.GoalStateStatus),
+		Relations: make(map[string]map[string]params.GoalStateStatus),
+	}
+	for _, other := range units {
+		status, err := unitGoalStatus(other)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		goalState.Units[other.Name()] = params.GoalStateStatus{Status: status}
+	}
+
+	relations, err := service.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, relation := range relations {
+		endpoint, err := relation.Endpoint(service.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relatedUnits, err := u.relatedUnitsGoalState(unit, relation, endpoint)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get goal state of relation %q", relation)
+		}
+		if goalState.Relations[endpoint.Name] == nil {
+			goalState.Relations[endpoint.Name] = make(map[string]params.GoalStateStatus)
+		}
+		for name, status := range relatedUnits {
+			goalState.Relations[endpoint.Name][name] = status
+		}
+	}
+	return goalState, nil
+}
+
+// relatedUnitsGoalState returns the status of the units expected at the
+// other end of the given relation of the unit. For container-scoped
+// relations only the units on the unit's machine are expected.
+func (u *UniterAPIV3) relatedUnitsGoalState(
+	unit *state.Unit, relation *state.Relation, endpoint state.Endpoint,
+) (map[string]params.GoalStateStatus, error) {
+	var machineId string
+	if endpoint.Scope == charm.ScopeContainer {
+		var err error
+		if machineId, err = unit.AssignedMachineId(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	relatedEndpoints, err := relation.RelatedEndpoints(endpoint.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]params.GoalStateStatus)
+	for _, relatedEndpoint := range relatedEndpoints {
+		relatedService, err := u.st.Service(relatedEndpoint.ServiceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relatedUnits, err := relatedService.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, related := range relatedUnits {
+			if related.Name() == unit.Name() {
+				continue
+			}
+			if machineId != "" {
+				relatedMachineId, err := related.AssignedMachineId()
+				if errors.IsNotAssigned(err) {
+					continue
+				} else if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if relatedMachineId != machineId {
+					continue
+				}
+			}
+			status := goalStateDying
+			if related.Life() == state.Alive {
+				relationUnit, err := relation.Unit(related)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				inScope, err := relationUnit.InScope()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				status = goalStateJoining
+				if inScope {
+					status = goalStateActive
+				}
+			}
+			result[related.Name()] = params.GoalStateStatus{Status: status}
+		}
+	}
+	return result, nil
+}
+
+// unitGoalStatus returns the goal state status of a unit of the
+// executing unit's service.
+func unitGoalStatus(unit *state.Unit) (string, error) {
+	if unit.Life() != state.Alive {
+		return goalStateDying, nil
+	}
+	agentStatus, err := unit.AgentStatus()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if agentStatus.Status == status.StatusAllocating {
+		return goalStateJoining, nil
+	}
+	return goalStateActive, nil
+}
+
 // ClearResolved removes any resolved setting from each given unit.
 func (u *UniterAPIV3) ClearResolved(args params.Entities) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -1630,6 +2078,22 @@ func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, unitTagArg,
 			return nil, errors.Annotatef(err, "getting machine %q preferred private address", machineID)
 		}
 
+		// Include the details of the device with the address, when the
+		// machine's devices are known.
+		addresses, err := machine.AllAddresses()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get devices addresses")
+		}
+		for _, addr := range addresses {
+			if addr.Value() != privateAddress.Value {
+				continue
+			}
+			config, err := addressNetworkConfig(addr)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return append(results, config), nil
+		}
 		results = append(results, params.NetworkConfig{
 			Address: privateAddress.Value,
 		})
@@ -1638,11 +2102,6 @@ func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, unitTagArg,
 		logger.Debugf("endpoint %q is explicitly bound to space %q", bindingName, boundSpace)
 	}
 
-	// TODO(dimitern): Use NetworkInterfaces() instead later, this is just for
-	// the PoC to enable minimal network-get implementation returning just the
-	// primary address.
-	//
-	// LKK Card: https://canonical.leankit.com/Boards/View/101652562/119258804
 	addresses, err := machine.AllAddresses()
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get devices addresses")
@@ -1652,9 +2111,23 @@ func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, unitTagArg,
 		machineID, addresses, unit.Name(), service.Name(), bindings,
 	)
 
+	envConfig, err := u.st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// When Juju manages spaces itself, subnets are discovered from
+	// the machines' devices, so an address may not be in a known
+	// subnet yet; it cannot be in the bound space, so is skipped.
+	// Otherwise every subnet comes from the provider, and a missing
+	// one is an error.
+	skipUnknownSubnets := envConfig.JujuManagedSpaces()
+
 	for _, addr := range addresses {
 		subnet, err := addr.Subnet()
-		if err != nil {
+		if errors.IsNotFound(err) && skipUnknownSubnets {
+			logger.Debugf("skipping %s: not linked to a known subnet", addr)
+			continue
+		} else if err != nil {
 			return nil, errors.Annotatef(err, "cannot get subnet for address %q", addr)
 		}
 		if subnet == nil {
@@ -1667,11 +2140,53 @@ func (u *UniterAPIV3) getOneNetworkConfig(canAccess common.AuthFunc, unitTagArg,
 		}
 		logger.Debugf("endpoint %q bound to space %q has address %q", bindingName, boundSpace, addr)
 
-		// TODO(dimitern): Fill in the rest later (see linked LKK card above).
-		results = append(results, params.NetworkConfig{
-			Address: addr.Value(),
-		})
+		config, err := addressNetworkConfig(addr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		results = append(results, config)
 	}
 
 	return results, nil
 }
+
+// addressNetworkConfig returns the network config of the given address,
+// including the details of the link-layer device it is assigned to.
+func addressNetworkConfig(addr *state.Address) (params.NetworkConfig, error) {
+	device, err := addr.Device()
+	if err != nil {
+		return params.NetworkConfig{}, errors.Annotatef(err, "cannot get device for address %q", addr)
+	}
+	return params.NetworkConfig{
+		MACAddress:        device.MACAddress(),
+		CIDR:              addr.SubnetCIDR(),
+		MTU:               int(device.MTU()),
+		ProviderId:        string(device.ProviderID()),
+		ProviderAddressId: string(addr.ProviderID()),
+		InterfaceName:     device.Name(),
+		InterfaceType:     string(device.Type()),
+		Disabled:          !device.IsUp(),
+		NoAutoStart:       !device.IsAutoStart(),
+		ConfigType:        string(addressConfigType(addr.ConfigMethod())),
+		Address:           addr.Value(),
+		DNSServers:        addr.DNSServers(),
+		DNSSearchDomains:  addr.DNSSearchDomains(),
+		GatewayAddress:    addr.GatewayAddress(),
+	}, nil
+}
+
+// addressConfigType returns the interface config type matching the
+// given address config method.
+func addressConfigType(method state.AddressConfigMethod) network.InterfaceConfigType {
+	switch method {
+	case state.StaticAddress:
+		return network.ConfigStatic
+	case state.DynamicAddress:
+		return network.ConfigDHCP
+	case state.LoopbackAddress:
+		return network.ConfigLoopback
+	case state.ManualAddress:
+		return network.ConfigManual
+	}
+	return network.ConfigUnknown
+}
@@ -38,7 +38,7 @@ type uniterSuite struct {
 
 	authorizer apiservertesting.FakeAuthorizer
 	resources  *common.Resources
-	uniter     *uniter.UniterAPIV3
+	uniter     *uniter.UniterAPIV4
 
 	machine0      *state.Machine
 	machine1      *state.Machine
@@ -117,19 +117,19 @@ func (s *uniterSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
 
-	uniterAPIV3, err := uniter.NewUniterAPIV3(
+	uniterAPIV4, err := uniter.NewUniterAPIV4(
 		s.State,
 		s.resources,
 		s.authorizer,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.uniter = uniterAPIV3
+	s.uniter = uniterAPIV4
 }
 
 func (s *uniterSuite) TestUniterFailsWithNonUnitAgentUser(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("9")
-	_, err := uniter.NewUniterAPIV3(s.State, s.resources, anAuthorizer)
+	_, err := uniter.NewUniterAPIV4(s.State, s.resources, anAuthorizer)
 	c.Assert(err, gc.NotNil)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
@@ -513,6 +513,461 @@ func (s *uniterSuite) TestResolved(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestWorkloadVersion(c *gc.C) {
+	err := s.wordpressUnit.SetWorkloadVersion("4.5.2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WorkloadVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: "4.5.2"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestSetWorkloadVersion(c *gc.C) {
+	args := params.EntityWorkloadVersions{Entities: []params.EntityWorkloadVersion{
+		{Tag: "unit-mysql-0", WorkloadVersion: "5.7"},
+		{Tag: "unit-wordpress-0", WorkloadVersion: "4.5.2"},
+		{Tag: "unit-foo-42", WorkloadVersion: "1.0"},
+	}}
+	result, err := s.uniter.SetWorkloadVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	version, err := s.wordpressUnit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "4.5.2")
+	version, err = s.mysqlUnit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "")
+}
+
+func (s *uniterSuite) TestSetUnitHealth(c *gc.C) {
+	args := params.SetStatus{Entities: []params.EntityStatusArgs{
+		{Tag: "unit-mysql-0", Status: status.StatusHealthy},
+		{Tag: "unit-wordpress-0", Status: status.StatusUnhealthy, Info: "tcp: connection refused"},
+		{Tag: "unit-foo-42", Status: status.StatusHealthy},
+	}}
+	result, err := s.uniter.SetUnitHealth(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	health, err := s.wordpressUnit.Health()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, status.StatusUnhealthy)
+	c.Assert(health.Message, gc.Equals, "tcp: connection refused")
+	health, err = s.mysqlUnit.Health()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, status.Status(""))
+}
+
+func (s *uniterSuite) TestCharmState(c *gc.C) {
+	err := s.wordpressUnit.SetCharmState(map[string]string{"initialised": "true"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.CharmState(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.CharmStateResults{
+		Results: []params.CharmStateResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{CharmState: map[string]string{"initialised": "true"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestCommitHookChanges(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.CommitHookChangesArgs{Args: []params.CommitHookChangesArg{{
+		Tag:              "unit-mysql-0",
+		UpdateCharmState: true,
+		CharmState:       map[string]string{"key": "value"},
+	}, {
+		Tag: "unit-wordpress-0",
+		RelationUnitSettings: []params.RelationUnitSettings{{
+			Relation: rel.Tag().String(),
+			Unit:     "unit-wordpress-0",
+			Settings: params.Settings{"some": "different"},
+		}},
+		UpdateCharmState: true,
+		CharmState:       map[string]string{"initialised": "true"},
+	}, {
+		Tag: "unit-wordpress-0",
+		RelationUnitSettings: []params.RelationUnitSettings{{
+			Relation: rel.Tag().String(),
+			Unit:     "unit-wordpress-0",
+			Settings: params.Settings{"some": "invalid"},
+		}},
+		UpdateCharmState: true,
+		CharmState:       map[string]string{"": "value"},
+	}, {
+		Tag: "unit-wordpress-0",
+		RelationUnitSettings: []params.RelationUnitSettings{{
+			Relation: rel.Tag().String(),
+			Unit:     "unit-mysql-0",
+		}},
+	}, {
+		Tag: "unit-foo-42",
+	}}}
+	result, err := s.uniter.CommitHookChanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 5)
+	c.Assert(result.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches,
+		`cannot commit hook changes for unit "wordpress/0": empty key not valid`)
+	c.Assert(result.Results[3].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[4].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	// Only the valid changes were written.
+	readSettings, err := relUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readSettings, gc.DeepEquals, map[string]interface{}{"some": "different"})
+	charmState, err := s.wordpressUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"initialised": "true"})
+	charmState, err = s.mysqlUnit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{})
+}
+
+func (s *uniterSuite) addSecret(c *gc.C, owner *state.Service, data map[string]string) *state.Secret {
+	secret, err := s.State.AddSecret(state.AddSecretParams{
+		Owner: owner.ServiceTag(),
+		Data:  data,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return secret
+}
+
+func (s *uniterSuite) TestAddSecrets(c *gc.C) {
+	mysqlSecret := s.addSecret(c, s.mysql, map[string]string{"password": "mysql"})
+	args := params.AddSecretArgs{Args: []params.AddSecretArg{
+		{UnitTag: "unit-wordpress-0", Description: "admin", RotateInterval: time.Hour, Data: map[string]string{"password": "s3cr3t"}},
+		{UnitTag: "unit-mysql-0", Data: map[string]string{"password": "s3cr3t"}},
+		{UnitTag: "unit-wordpress-0", SecretId: mysqlSecret.Id(), Data: map[string]string{"password": "stolen"}},
+		{UnitTag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.AddSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, "cannot add secret for service wordpress: no values specified")
+
+	secret, err := s.State.Secret(result.Results[0].Result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Owner(), gc.Equals, s.wordpress.ServiceTag())
+	c.Assert(secret.Description(), gc.Equals, "admin")
+	c.Assert(secret.RotateInterval(), gc.Equals, time.Hour)
+
+	// The owner's units can add revisions.
+	result, err = s.uniter.AddSecrets(params.AddSecretArgs{Args: []params.AddSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: secret.Id(), Data: map[string]string{"password": "n3w"}},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{{Result: secret.Id()}},
+	})
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret.Revision(), gc.Equals, 2)
+	err = mysqlSecret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mysqlSecret.Revision(), gc.Equals, 1)
+}
+
+func (s *uniterSuite) TestGetSecrets(c *gc.C) {
+	owned := s.addSecret(c, s.wordpress, map[string]string{"password": "mine"})
+	err := owned.AddRevision(map[string]string{"password": "mine2"})
+	c.Assert(err, jc.ErrorIsNil)
+	viaRelation := s.addSecret(c, s.mysql, map[string]string{"password": "relation"})
+	viaUnit := s.addSecret(c, s.mysql, map[string]string{"password": "unit"})
+	notGranted := s.addSecret(c, s.mysql, map[string]string{"password": "nope"})
+
+	rel := s.addRelation(c, "wordpress", "mysql")
+	err = viaRelation.Grant(rel.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = viaUnit.Grant(s.wordpressUnit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = notGranted.Grant(s.mysqlUnit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.GetSecretArgs{Args: []params.GetSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id()},
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), Revision: 1},
+		{UnitTag: "unit-wordpress-0", SecretId: viaRelation.Id()},
+		{UnitTag: "unit-wordpress-0", SecretId: viaUnit.Id()},
+		{UnitTag: "unit-wordpress-0", SecretId: notGranted.Id()},
+		{UnitTag: "unit-mysql-0", SecretId: notGranted.Id()},
+		{UnitTag: "unit-wordpress-0", SecretId: "999"},
+	}}
+	result, err := s.uniter.GetSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretValueResults{
+		Results: []params.SecretValueResult{
+			{Revision: 2, Data: map[string]string{"password": "mine2"}},
+			{Revision: 1, Data: map[string]string{"password": "mine"}},
+			{Revision: 1, Data: map[string]string{"password": "relation"}},
+			{Revision: 1, Data: map[string]string{"password": "unit"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`secret "999"`)},
+		},
+	})
+
+	// Access granted through a relation ends with the relation.
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.GetSecrets(params.GetSecretArgs{Args: []params.GetSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: viaRelation.Id()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *uniterSuite) TestSecretsDueForRotation(c *gc.C) {
+	due, err := s.State.AddSecret(state.AddSecretParams{
+		Owner:          s.wordpress.ServiceTag(),
+		RotateInterval: time.Nanosecond,
+		Data:           map[string]string{"password": "old"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSecret(state.AddSecretParams{
+		Owner:          s.wordpress.ServiceTag(),
+		RotateInterval: time.Hour,
+		Data:           map[string]string{"password": "new"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.addSecret(c, s.wordpress, map[string]string{"password": "never"})
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-mysql-0"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.SecretsDueForRotation(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{due.Id()}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestGrantRevokeSecrets(c *gc.C) {
+	owned := s.addSecret(c, s.wordpress, map[string]string{"password": "mine"})
+	other := s.addSecret(c, s.mysql, map[string]string{"password": "theirs"})
+	rel := s.addRelation(c, "wordpress", "mysql")
+	loggingCharm := s.AddTestingCharm(c, "logging")
+	s.AddTestingService(c, "logging", loggingCharm)
+	eps, err := s.State.InferEndpoints("logging", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	otherRel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.GrantSecretArgs{Args: []params.GrantSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: rel.Tag().String()},
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: "unit-mysql-0"},
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: otherRel.Tag().String()},
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: "unit-foo-42"},
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: "machine-0"},
+		{UnitTag: "unit-wordpress-0", SecretId: other.Id(), SubjectTag: "unit-wordpress-0"},
+		{UnitTag: "unit-mysql-0", SecretId: other.Id(), SubjectTag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.GrantSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.NotFoundError(`unit "foo/42"`)},
+			{&params.Error{Message: "secret grant to machine 0 not valid"}},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = owned.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	grants, err := owned.Grants()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grants, jc.DeepEquals, []names.Tag{rel.Tag(), s.mysqlUnit.UnitTag()})
+
+	result, err = s.uniter.RevokeSecrets(params.GrantSecretArgs{Args: []params.GrantSecretArg{
+		{UnitTag: "unit-wordpress-0", SecretId: owned.Id(), SubjectTag: "unit-mysql-0"},
+		{UnitTag: "unit-wordpress-0", SecretId: other.Id(), SubjectTag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = owned.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	grants, err = owned.Grants()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grants, jc.DeepEquals, []names.Tag{rel.Tag()})
+}
+
+func (s *uniterSuite) TestGoalStates(c *gc.C) {
+	err := s.wordpressUnit.SetAgentStatus(status.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// A second wordpress unit has not started yet.
+	s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.wordpress,
+		Machine: s.machine1,
+	})
+
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// A second mysql unit joined the relation and is now being removed,
+	// and a third has not joined it yet.
+	dyingUnit := s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.mysql,
+		Machine: s.machine1,
+	})
+	relUnit, err = rel.Unit(dyingUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = dyingUnit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.mysql,
+		Machine: s.machine1,
+	})
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.GoalStates(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.GoalStateResults{
+		Results: []params.GoalStateResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: &params.GoalState{
+				Units: map[string]params.GoalStateStatus{
+					"wordpress/0": {Status: "active"},
+					"wordpress/1": {Status: "joining"},
+				},
+				Relations: map[string]map[string]params.GoalStateStatus{
+					"db": {
+						"mysql/0": {Status: "active"},
+						"mysql/1": {Status: "dying"},
+						"mysql/2": {Status: "joining"},
+					},
+				},
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestHookTimeouts(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{"hook-timeout": "1h"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.HookTimeouts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.HookTimeoutResults{
+		Results: []params.HookTimeoutResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Timeout: time.Hour},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// The service's override takes precedence.
+	err = s.wordpress.SetHookTimeout(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.HookTimeouts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], gc.DeepEquals, params.HookTimeoutResult{Timeout: 10 * time.Minute})
+}
+
+func (s *uniterSuite) TestRecordHookExecutions(c *gc.C) {
+	execution := params.HookExecution{
+		Kind:      "hook",
+		Name:      "update-status",
+		Started:   time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  3 * time.Second,
+		ExitCode:  1,
+		ToolCalls: map[string]int{"status-set": 1},
+	}
+	args := params.EntityHookExecutions{Entities: []params.EntityHookExecution{
+		{Tag: "unit-mysql-0", Executions: []params.HookExecution{execution}},
+		{Tag: "unit-wordpress-0", Executions: []params.HookExecution{execution}},
+		{Tag: "unit-foo-42", Executions: []params.HookExecution{execution}},
+	}}
+	result, err := s.uniter.RecordHookExecutions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	executions, err := s.wordpressUnit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{{
+		Kind:      "hook",
+		Name:      "update-status",
+		Started:   time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  3 * time.Second,
+		ExitCode:  1,
+		ToolCalls: map[string]int{"status-set": 1},
+	}})
+}
+
 func (s *uniterSuite) TestClearResolved(c *gc.C) {
 	err := s.wordpressUnit.SetResolved(state.ResolvedRetryHooks)
 	c.Assert(err, jc.ErrorIsNil)
@@ -570,7 +1025,7 @@ func (s *uniterSuite) TestGetPrincipal(c *gc.C) {
 	// Now try as subordinate's agent.
 	subAuthorizer := s.authorizer
 	subAuthorizer.Tag = subordinate.Tag()
-	subUniter, err := uniter.NewUniterAPIV3(s.State, s.resources, subAuthorizer)
+	subUniter, err := uniter.NewUniterAPIV4(s.State, s.resources, subAuthorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	result, err = subUniter.GetPrincipal(args)
@@ -1170,7 +1625,7 @@ func (s *uniterSuite) TestActionsWrongUnit(c *gc.C) {
 	mysqlUnitAuthorizer := apiservertesting.FakeAuthorizer{
 		Tag: s.mysqlUnit.Tag(),
 	}
-	mysqlUnitFacade, err := uniter.NewUniterAPIV3(s.State, s.resources, mysqlUnitAuthorizer)
+	mysqlUnitFacade, err := uniter.NewUniterAPIV4(s.State, s.resources, mysqlUnitAuthorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
@@ -2236,7 +2691,7 @@ func (s *uniterSuite) TestAllMachinePorts(c *gc.C) {
 type unitMetricBatchesSuite struct {
 	uniterSuite
 	*commontesting.ModelWatcherTest
-	uniter *uniter.UniterAPIV3
+	uniter *uniter.UniterAPIV4
 }
 
 var _ = gc.Suite(&unitMetricBatchesSuite{})
@@ -2248,7 +2703,7 @@ func (s *unitMetricBatchesSuite) SetUpTest(c *gc.C) {
 		Tag: s.meteredUnit.Tag(),
 	}
 	var err error
-	s.uniter, err = uniter.NewUniterAPIV3(
+	s.uniter, err = uniter.NewUniterAPIV4(
 		s.State,
 		s.resources,
 		meteredAuthorizer,
@@ -2509,6 +2964,19 @@ func (s *uniterNetworkConfigSuite) makeMachineDevicesAndAddressesArgs(addrSuffix
 		}}
 }
 
+// deviceNetworkConfig returns the network config expected for the
+// given static address of a device added by
+// makeMachineDevicesAndAddressesArgs.
+func (s *uniterNetworkConfigSuite) deviceNetworkConfig(name string, deviceType state.LinkLayerDeviceType, address, cidr string) params.NetworkConfig {
+	return params.NetworkConfig{
+		InterfaceName: name,
+		InterfaceType: string(deviceType),
+		ConfigType:    string(network.ConfigStatic),
+		Address:       address,
+		CIDR:          cidr,
+	}
+}
+
 func (s *uniterNetworkConfigSuite) TearDownTest(c *gc.C) {
 	s.base.JujuConnSuite.TearDownTest(c)
 }
@@ -2521,7 +2989,7 @@ func (s *uniterNetworkConfigSuite) setupUniterAPIForUnit(c *gc.C, givenUnit *sta
 	}
 
 	var err error
-	s.base.uniter, err = uniter.NewUniterAPIV3(
+	s.base.uniter, err = uniter.NewUniterAPIV4(
 		s.base.State,
 		s.base.resources,
 		s.base.authorizer,
@@ -2576,14 +3044,14 @@ func (s *uniterNetworkConfigSuite) TestNetworkConfigForExplicitlyBoundEndpoint(c
 	// addresses bound to the "internal" space, where the "db" endpoint itself
 	// is bound to.
 	expectedConfigWithRelationName := []params.NetworkConfig{
-		{Address: "10.0.0.10"},
-		{Address: "10.0.0.11"},
+		s.deviceNetworkConfig("eth0.100", state.VLAN_8021QDevice, "10.0.0.10", "10.0.0.0/24"),
+		s.deviceNetworkConfig("eth1.100", state.VLAN_8021QDevice, "10.0.0.11", "10.0.0.0/24"),
 	}
 	// For the "admin-api" extra-binding we expect to see only addresses from
 	// the "public" space.
 	expectedConfigWithExtraBindingName := []params.NetworkConfig{
-		{Address: "8.8.8.10"},
-		{Address: "8.8.4.10"},
+		s.deviceNetworkConfig("eth0", state.EthernetDevice, "8.8.8.10", "8.8.0.0/16"),
+		s.deviceNetworkConfig("eth1", state.EthernetDevice, "8.8.4.10", "8.8.0.0/16"),
 	}
 
 	result, err := s.base.uniter.NetworkConfig(args)
@@ -2596,6 +3064,56 @@ func (s *uniterNetworkConfigSuite) TestNetworkConfigForExplicitlyBoundEndpoint(c
 	})
 }
 
+// addUnknownSubnetAddress adds to wordpress's machine an address in a
+// subnet that is not known to the model.
+func (s *uniterNetworkConfigSuite) addUnknownSubnetAddress(c *gc.C) {
+	err := s.base.machine0.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: "eth2",
+		Type: state.EthernetDevice,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.base.machine0.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth2",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "192.168.1.10/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *uniterNetworkConfigSuite) TestNetworkConfigUnknownSubnet(c *gc.C) {
+	s.addRelationAndAssertInScope(c)
+	s.addUnknownSubnetAddress(c)
+
+	args := params.UnitsNetworkConfig{Args: []params.UnitNetworkConfig{
+		{BindingName: "db", UnitTag: s.base.wordpressUnit.Tag().String()},
+	}}
+	result, err := s.base.uniter.NetworkConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `cannot get subnet for address .*192\.168\.1\.10.* not found`)
+}
+
+func (s *uniterNetworkConfigSuite) TestNetworkConfigUnknownSubnetJujuManagedSpaces(c *gc.C) {
+	s.addRelationAndAssertInScope(c)
+	s.addUnknownSubnetAddress(c)
+	err := s.base.State.UpdateModelConfig(map[string]interface{}{"juju-managed-spaces": true}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.UnitsNetworkConfig{Args: []params.UnitNetworkConfig{
+		{BindingName: "db", UnitTag: s.base.wordpressUnit.Tag().String()},
+	}}
+	result, err := s.base.uniter.NetworkConfig(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.UnitNetworkConfigResults{
+		Results: []params.UnitNetworkConfigResult{{
+			Config: []params.NetworkConfig{
+				s.deviceNetworkConfig("eth0.100", state.VLAN_8021QDevice, "10.0.0.10", "10.0.0.0/24"),
+				s.deviceNetworkConfig("eth1.100", state.VLAN_8021QDevice, "10.0.0.11", "10.0.0.0/24"),
+			},
+		}},
+	})
+}
+
 func (s *uniterNetworkConfigSuite) TestNetworkConfigForImplicitlyBoundEndpoint(c *gc.C) {
 	// Since wordpressUnit has explicit binding for "db", switch the API to
 	// mysqlUnit and check "mysql:server" uses the machine preferred private
@@ -2615,7 +3133,20 @@ func (s *uniterNetworkConfigSuite) TestNetworkConfigForImplicitlyBoundEndpoint(c
 	privateAddress, err := s.base.machine1.PrivateAddress()
 	c.Assert(err, jc.ErrorIsNil)
 
-	expectedConfig := []params.NetworkConfig{{Address: privateAddress.Value}}
+	// The preferred private address is assigned to one of the machine's
+	// devices, so its details are included.
+	addresses, err := s.base.machine1.AllAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	var deviceName string
+	for _, addr := range addresses {
+		if addr.Value() == privateAddress.Value {
+			deviceName = addr.DeviceName()
+		}
+	}
+	c.Assert(deviceName, gc.Not(gc.Equals), "")
+	expectedConfig := []params.NetworkConfig{
+		s.deviceNetworkConfig(deviceName, state.VLAN_8021QDevice, privateAddress.Value, "10.0.0.0/24"),
+	}
 
 	result, err := s.base.uniter.NetworkConfig(args)
 	c.Assert(err, jc.ErrorIsNil)
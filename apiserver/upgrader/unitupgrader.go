@@ -90,6 +90,10 @@ func (u *UnitUpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWa
 // The desired version is what the unit's assigned machine is running.
 func (u *UnitUpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResults, error) {
 	result := make([]params.VersionResult, len(args.Entities))
+	rollbackVersion, err := rolledBackVersion(u.st)
+	if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	}
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -99,6 +103,9 @@ func (u *UnitUpgraderAPI) DesiredVersion(args params.Entities) (params.VersionRe
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
 			result[i].Version, err = u.getMachineToolsVersion(tag)
+			if err == nil && rollbackVersion != nil {
+				result[i].Rollback = *result[i].Version == *rollbackVersion
+			}
 		}
 		result[i].Error = common.ServerError(err)
 	}
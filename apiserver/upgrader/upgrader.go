@@ -104,7 +104,12 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForModelConfigChanges()
+			// The desired version also depends on the progress
+			// of any staged upgrade.
+			watch := common.NewMultiNotifyWatcher(
+				u.st.WatchForModelConfigChanges(),
+				u.st.WatchUpgradeRollout(),
+			)
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -162,6 +167,16 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	}
 	// Is the desired version greater than the current API server version?
 	isNewerVersion := agentVersion.Compare(jujuversion.Current) > 0
+	rollout, err := u.st.UpgradeRollout()
+	if errors.IsNotFound(err) {
+		rollout = nil
+	} else if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	}
+	rollbackVersion, err := rolledBackVersion(u.st)
+	if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	}
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -180,15 +195,68 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
+			//
+			// Machines that a staged upgrade has not yet released
+			// keep the version they are running.
+			if heldVersion, held := u.heldVersion(tag, agentVersion, rollout); held {
+				logger.Debugf("desired version is %s, but %s is held at %s by a staged upgrade", agentVersion, tag, heldVersion)
+				results[i].Version = &heldVersion
+			} else if !isNewerVersion || u.entityIsManager(tag) {
 				results[i].Version = &agentVersion
 			} else {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
 				results[i].Version = &jujuversion.Current
 			}
+			results[i].Rollback = rollbackVersion != nil && *results[i].Version == *rollbackVersion
 			err = nil
 		}
 		results[i].Error = common.ServerError(err)
 	}
 	return params.VersionResults{Results: results}, nil
 }
+
+// rolledBackVersion returns the model's agent version if it was set
+// by rolling back an upgrade, or nil otherwise.
+func rolledBackVersion(st *state.State) (*version.Number, error) {
+	rollback, err := st.UpgradeRollback()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !rollback.RolledBack() {
+		return nil, nil
+	}
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	agentVersion, _ := cfg.AgentVersion()
+	if agentVersion != rollback.PreviousVersion() {
+		return nil, nil
+	}
+	return &agentVersion, nil
+}
+
+// heldVersion returns the version that the given machine agent is
+// running, and true, if the given upgrade rollout is holding the
+// machine back from the desired agent version.
+func (u *UpgraderAPI) heldVersion(tag names.Tag, agentVersion version.Number, rollout *state.UpgradeRollout) (version.Number, bool) {
+	if rollout == nil || !rollout.Active() || rollout.Version() != agentVersion {
+		return version.Number{}, false
+	}
+	if rollout.IsReleased(tag.Id()) {
+		return version.Number{}, false
+	}
+	machine, err := u.st.Machine(tag.Id())
+	if err != nil || machine.IsManager() {
+		return version.Number{}, false
+	}
+	// A machine that has not reported its tools yet was provisioned
+	// with the desired version.
+	tools, err := machine.AgentTools()
+	if err != nil {
+		return version.Number{}, false
+	}
+	return tools.Version.Number, true
+}
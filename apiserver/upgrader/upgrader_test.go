@@ -341,3 +341,68 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
+
+func (s *upgraderSuite) TestDesiredVersionHeldByUpgradeRollout(c *gc.C) {
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: series.HostSeries(),
+	}
+	s.apiMachine.SetAgentVersion(current)
+	s.rawMachine.SetAgentVersion(current)
+	canary, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = canary.SetAgentVersion(current)
+	c.Assert(err, jc.ErrorIsNil)
+	newer := current.Number
+	newer.Patch++
+	_, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   newer,
+		Canaries:  []string{canary.Id()},
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// Pretend that the controller has upgraded.
+	s.PatchValue(&jujuversion.Current, newer)
+
+	desiredVersion := func(m *state.Machine) version.Number {
+		authorizer := apiservertesting.FakeAuthorizer{Tag: m.Tag()}
+		upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+		c.Assert(err, jc.ErrorIsNil)
+		results, err := upgraderAPI.DesiredVersion(params.Entities{
+			Entities: []params.Entity{{Tag: m.Tag().String()}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.Results, gc.HasLen, 1)
+		c.Assert(results.Results[0].Error, gc.IsNil)
+		c.Assert(results.Results[0].Version, gc.NotNil)
+		return *results.Results[0].Version
+	}
+	c.Check(desiredVersion(s.apiMachine), gc.Equals, newer)
+	c.Check(desiredVersion(canary), gc.Equals, newer)
+	c.Check(desiredVersion(s.rawMachine), gc.Equals, current.Number)
+}
+
+func (s *upgraderSuite) TestDesiredVersionAfterRollback(c *gc.C) {
+	previous := jujuversion.Current
+	newVersion := s.bumpDesiredAgentVersion(c)
+	err := s.State.RecordUpgrade(previous, newVersion, "backup-id")
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Rollback, jc.IsFalse)
+
+	err = s.State.FinishUpgradeRollback(previous, newVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	// Pretend that the controller had upgraded.
+	s.PatchValue(&jujuversion.Current, newVersion)
+	results, err = s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, previous)
+	c.Check(results.Results[0].Rollback, jc.IsTrue)
+}
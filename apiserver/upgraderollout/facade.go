@@ -0,0 +1,37 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+// Backend exposes functionality required by Facade.
+type Backend interface {
+
+	// AdvanceUpgradeRollout releases the next machines of the
+	// model's staged upgrade, if it is running and every machine
+	// released so far has upgraded.
+	AdvanceUpgradeRollout() error
+}
+
+// Facade allows model-manager clients to advance staged upgrades.
+type Facade struct {
+	backend Backend
+}
+
+// NewFacade creates a new authorized Facade.
+func NewFacade(backend Backend, _ *common.Resources, auth common.Authorizer) (*Facade, error) {
+	if !auth.AuthModelManager() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{backend: backend}, nil
+}
+
+// Advance takes the model's staged upgrade, if any, one step further.
+func (facade *Facade) Advance() error {
+	return errors.Trace(facade.backend.AdvanceUpgradeRollout())
+}
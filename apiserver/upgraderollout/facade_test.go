@@ -0,0 +1,70 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/upgraderollout"
+)
+
+type FacadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&FacadeSuite{})
+
+// mockAuth implements common.Authorizer for the tests' convenience.
+type mockAuth struct {
+	common.Authorizer
+	modelManager bool
+}
+
+func (mock mockAuth) AuthModelManager() bool {
+	return mock.modelManager
+}
+
+// mockBackend implements upgraderollout.Backend.
+type mockBackend struct {
+	testing.Stub
+}
+
+func (mock *mockBackend) AdvanceUpgradeRollout() error {
+	mock.MethodCall(mock, "AdvanceUpgradeRollout")
+	return mock.NextErr()
+}
+
+func (s *FacadeSuite) TestModelManager(c *gc.C) {
+	facade, err := upgraderollout.NewFacade(nil, nil, mockAuth{modelManager: true})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(facade, gc.NotNil)
+}
+
+func (s *FacadeSuite) TestNotModelManager(c *gc.C) {
+	facade, err := upgraderollout.NewFacade(nil, nil, mockAuth{})
+	c.Check(err, gc.Equals, common.ErrPerm)
+	c.Check(facade, gc.IsNil)
+}
+
+func (s *FacadeSuite) TestAdvance(c *gc.C) {
+	backend := &mockBackend{}
+	facade, err := upgraderollout.NewFacade(backend, nil, mockAuth{modelManager: true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = facade.Advance()
+	c.Check(err, jc.ErrorIsNil)
+	backend.CheckCallNames(c, "AdvanceUpgradeRollout")
+}
+
+func (s *FacadeSuite) TestAdvanceError(c *gc.C) {
+	backend := &mockBackend{}
+	backend.SetErrors(errors.New("blammo"))
+	facade, err := upgraderollout.NewFacade(backend, nil, mockAuth{modelManager: true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = facade.Advance()
+	c.Check(err, gc.ErrorMatches, "blammo")
+}
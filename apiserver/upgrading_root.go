@@ -30,12 +30,19 @@ func newUpgradingRoot(finder rpc.MethodFinder) *upgradingRoot {
 // facade versions as well.
 var allowedMethodsDuringUpgrades = map[string]set.Strings{
 	"Client": set.NewStrings(
-		"FullStatus",          // for "juju status"
-		"ModelGet",            // for "juju ssh"
-		"PrivateAddress",      // for "juju ssh"
-		"PublicAddress",       // for "juju ssh"
-		"FindTools",           // for "juju upgrade-juju", before we can reset upgrade to re-run
-		"AbortCurrentUpgrade", // for "juju upgrade-juju", so that we can reset upgrade to re-run
+		"FullStatus",             // for "juju status"
+		"ModelGet",               // for "juju ssh"
+		"PrivateAddress",         // for "juju ssh"
+		"PublicAddress",          // for "juju ssh"
+		"FindTools",              // for "juju upgrade-juju", before we can reset upgrade to re-run
+		"AbortCurrentUpgrade",    // for "juju upgrade-juju", so that we can reset upgrade to re-run
+		"PrepareUpgradeRollback", // for "juju rollback-upgrade"
+		"FinishUpgradeRollback",  // for "juju rollback-upgrade"
+	),
+	"Backups": set.NewStrings(
+		"PrepareRestore", // for "juju rollback-upgrade"
+		"Restore",        // for "juju rollback-upgrade"
+		"FinishRestore",  // for "juju rollback-upgrade"
 	),
 	"Pinger": set.NewStrings(
 		"Ping",
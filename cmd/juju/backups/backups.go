@@ -6,7 +6,9 @@ package backups
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -24,21 +26,27 @@ import (
 type APIClient interface {
 	io.Closer
 	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	Create(notes string, key params.BackupsEncryptionKey) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
 	List() (*params.BackupsListResult, error)
+	// ListRemote gets the metadata of all backups in remote storage.
+	ListRemote() (*params.BackupsListResult, error)
 	// Download pulls the backup archive file.
 	Download(id string) (io.ReadCloser, error)
 	// Upload pushes a backup archive to storage.
 	Upload(ar io.ReadSeeker, meta params.BackupsMetadataResult) (string, error)
 	// Remove removes the stored backup.
 	Remove(id string) error
+	// DownloadModel pulls a backup archive of the current model.
+	DownloadModel() (io.ReadCloser, error)
+	// RestoreModel recreates a model from a model backup archive.
+	RestoreModel(archive io.ReadSeeker, replace bool) (params.BackupsRestoreModelResult, error)
 	// Restore will restore a backup with the given id into the controller.
-	Restore(string, backups.ClientConnection) error
+	Restore(string, params.BackupsDecryptionKey, backups.ClientConnection) error
 	// RestoreReader will restore a backup file into the controller.
-	RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, backups.ClientConnection) error
+	RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, params.BackupsDecryptionKey, backups.ClientConnection) error
 }
 
 // CommandBase is the base type for backups sub-commands.
@@ -79,6 +87,13 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
 	fmt.Fprintf(ctx.Stdout, "notes:           %q\n", result.Notes)
+	fmt.Fprintf(ctx.Stdout, "source:          %s\n", backupSource(result))
+	if result.Failure != "" {
+		fmt.Fprintf(ctx.Stdout, "failure:         %q\n", result.Failure)
+	}
+	if result.Encryption != "" {
+		fmt.Fprintf(ctx.Stdout, "encryption:      %s\n", result.Encryption)
+	}
 
 	fmt.Fprintf(ctx.Stdout, "model ID:        %q\n", result.Model)
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
@@ -86,13 +101,21 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", result.Version)
 }
 
+// backupSource describes how the backup came to be created.
+func backupSource(result *params.BackupsMetadataResult) string {
+	if result.Scheduled {
+		return "scheduled"
+	}
+	return "manual"
+}
+
 // ArchiveReader can read a backup archive.
 type ArchiveReader interface {
 	io.ReadSeeker
 	io.Closer
 }
 
-func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMetadataResult, err error) {
+func getArchive(filename string, key params.BackupsDecryptionKey) (rc ArchiveReader, metaResult *params.BackupsMetadataResult, err error) {
 	defer func() {
 		if err != nil && rc != nil {
 			rc.Close()
@@ -104,8 +127,28 @@ func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMe
 		return nil, nil, errors.Trace(err)
 	}
 
+	// Encrypted archives must be decrypted to get at the metadata; the
+	// archive itself is sent on still encrypted.
+	scheme, err := statebackups.ArchiveEncryption(archive)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var plaintext io.Reader = archive
+	if scheme != "" {
+		if key.Passphrase == "" && key.PrivateKey == "" {
+			return nil, nil, errors.Errorf("backup archive is encrypted (%s); use --passphrase-file or --private-key-file", scheme)
+		}
+		plaintext, err = statebackups.Decrypt(archive, statebackups.DecryptionKey{
+			Passphrase: key.Passphrase,
+			PrivateKey: key.PrivateKey,
+		})
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
 	// Extract the metadata.
-	ad, err := statebackups.NewArchiveDataReader(archive)
+	ad, err := statebackups.NewArchiveDataReader(plaintext)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -128,6 +171,14 @@ func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMe
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	meta.Encryption = scheme
+	if scheme != "" {
+		// The size and checksum are those of the encrypted file.
+		err := meta.SetFileInfo(fileMeta.Size(), fileMeta.Checksum(), fileMeta.ChecksumFormat())
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
 	if meta.Size() == int64(0) {
 		if err := meta.SetFileInfo(fileMeta.Size(), "", ""); err != nil {
 			return nil, nil, errors.Trace(err)
@@ -155,3 +206,61 @@ func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMe
 
 	return archive, metaResult, nil
 }
+
+// decryptionKeyFlags holds the options used to supply the key for an
+// encrypted backup archive.
+type decryptionKeyFlags struct {
+	passphraseFile string
+	privateKeyFile string
+}
+
+// SetFlags adds the decryption key options to the flag set.
+func (f *decryptionKeyFlags) SetFlags(fs *gnuflag.FlagSet) {
+	fs.StringVar(&f.passphraseFile, "passphrase-file", "", "read the passphrase for an encrypted backup from this file")
+	fs.StringVar(&f.privateKeyFile, "private-key-file", "", "read the RSA private key for an encrypted backup from this file")
+}
+
+// Validate checks that at most one key has been supplied.
+func (f *decryptionKeyFlags) Validate() error {
+	if f.passphraseFile != "" && f.privateKeyFile != "" {
+		return errors.Errorf("cannot mix --passphrase-file and --private-key-file")
+	}
+	return nil
+}
+
+// Key reads the decryption key from the files named in the options.
+func (f *decryptionKeyFlags) Key(ctx *cmd.Context) (params.BackupsDecryptionKey, error) {
+	var key params.BackupsDecryptionKey
+	var err error
+	if f.passphraseFile != "" {
+		key.Passphrase, err = readPassphrase(ctx, f.passphraseFile)
+	}
+	if f.privateKeyFile != "" {
+		key.PrivateKey, err = readKeyFile(ctx, f.privateKeyFile)
+	}
+	return key, errors.Trace(err)
+}
+
+// readPassphrase returns the passphrase held in the named file, less
+// any trailing newline.
+func readPassphrase(ctx *cmd.Context, filename string) (string, error) {
+	data, err := readKeyFile(ctx, filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	passphrase := strings.TrimRight(data, "\r\n")
+	if passphrase == "" {
+		return "", errors.Errorf("passphrase file %q is empty", filename)
+	}
+	return passphrase, nil
+}
+
+// readKeyFile returns the contents of the named file, relative to the
+// command's working directory.
+func readKeyFile(ctx *cmd.Context, filename string) (string, error) {
+	data, err := ioutil.ReadFile(ctx.AbsPath(filename))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
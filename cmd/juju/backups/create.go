@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/state/backups"
 )
@@ -44,6 +45,13 @@ Therefore, you should use the --download or --filename options, or use:
 to get a local copy of the backup archive.
 This local copy can then be used to restore an model even if that
 model was already destroyed or is otherwise unavailable.
+
+The archive holds the controller's keys and certificates, so it may be
+encrypted before it is stored.  Use --passphrase-file to encrypt it with
+a key derived from a passphrase, or --public-key-file to encrypt it to
+an RSA public key; the matching passphrase or private key is then needed
+to restore it.  If neither is given, the archive is encrypted to the
+model's backup-encryption-public-key, if that is set.
 `
 
 // NewCreateCommand returns a command used to create backups.
@@ -60,6 +68,12 @@ type createCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// PassphraseFile holds the passphrase with which to encrypt
+	// the backup archive.
+	PassphraseFile string
+	// PublicKeyFile holds the RSA public key to which to encrypt
+	// the backup archive.
+	PublicKeyFile string
 }
 
 // Info implements Command.Info.
@@ -77,6 +91,8 @@ func (c *createCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.BoolVar(&c.NoDownload, "no-download", false, "do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "download to this file")
+	f.StringVar(&c.PassphraseFile, "passphrase-file", "", "encrypt the archive with the passphrase in this file")
+	f.StringVar(&c.PublicKeyFile, "public-key-file", "", "encrypt the archive to the RSA public key in this file")
 }
 
 // Init implements Command.Init.
@@ -93,6 +109,9 @@ func (c *createCommand) Init(args []string) error {
 	if c.Filename == "" {
 		return errors.Errorf("missing filename")
 	}
+	if c.PassphraseFile != "" && c.PublicKeyFile != "" {
+		return errors.Errorf("cannot mix --passphrase-file and --public-key-file")
+	}
 
 	return nil
 }
@@ -104,13 +123,18 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 			return err
 		}
 	}
+	key, err := c.encryptionKey(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	result, err := client.Create(c.Notes, key)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -135,6 +159,20 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// encryptionKey reads the key with which to encrypt the backup from
+// the files named in the options.
+func (c *createCommand) encryptionKey(ctx *cmd.Context) (params.BackupsEncryptionKey, error) {
+	var key params.BackupsEncryptionKey
+	var err error
+	if c.PassphraseFile != "" {
+		key.Passphrase, err = readPassphrase(ctx, c.PassphraseFile)
+	}
+	if c.PublicKeyFile != "" {
+		key.PublicKey, err = readKeyFile(ctx, c.PublicKeyFile)
+	}
+	return key, errors.Trace(err)
+}
+
 func (c *createCommand) decideFilename(ctx *cmd.Context, filename string, timestamp time.Time) string {
 	if filename != notset {
 		return filename
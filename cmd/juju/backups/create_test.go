@@ -5,6 +5,8 @@ package backups_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -12,6 +14,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/testing"
 )
@@ -105,6 +108,35 @@ func (s *createSuite) TestNotes(c *gc.C) {
 	client.Check(c, s.metaresult.ID, "spam", "Create", "Download")
 }
 
+func (s *createSuite) TestPassphraseFile(c *gc.C) {
+	client := s.setSuccess()
+	passphraseFile := filepath.Join(c.MkDir(), "passphrase")
+	err := ioutil.WriteFile(passphraseFile, []byte("sekrit\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--no-download", "--passphrase-file", passphraseFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+	c.Check(client.encryptionKey, jc.DeepEquals, params.BackupsEncryptionKey{Passphrase: "sekrit"})
+}
+
+func (s *createSuite) TestPublicKeyFile(c *gc.C) {
+	client := s.setSuccess()
+	publicKeyFile := filepath.Join(c.MkDir(), "key.pem")
+	err := ioutil.WriteFile(publicKeyFile, []byte("<public key>"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--no-download", "--public-key-file", publicKeyFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(client.encryptionKey, jc.DeepEquals, params.BackupsEncryptionKey{PublicKey: "<public key>"})
+}
+
+func (s *createSuite) TestPassphraseAndPublicKey(c *gc.C) {
+	s.setSuccess()
+	_, err := testing.RunCommand(c, s.wrappedCommand, "--passphrase-file", "a", "--public-key-file", "b")
+	c.Check(err, gc.ErrorMatches, "cannot mix --passphrase-file and --public-key-file")
+}
+
 func (s *createSuite) TestFilename(c *gc.C) {
 	client := s.setDownload()
 	ctx, err := testing.RunCommand(c, s.wrappedCommand, "--filename", "backup.tgz", "--quiet")
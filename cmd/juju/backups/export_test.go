@@ -68,7 +68,7 @@ func NewRemoveCommandForTest() cmd.Command {
 func NewRestoreCommandForTest(
 	store jujuclient.ClientStore,
 	api RestoreAPI,
-	getArchive func(string) (ArchiveReader, *params.BackupsMetadataResult, error),
+	getArchive func(string, params.BackupsDecryptionKey) (ArchiveReader, *params.BackupsMetadataResult, error),
 	getEnviron func(string, *params.BackupsMetadataResult) (environs.Environ, error),
 ) cmd.Command {
 	c := &restoreCommand{
@@ -86,3 +86,19 @@ func NewRestoreCommandForTest(
 	c.SetClientStore(store)
 	return modelcmd.Wrap(c)
 }
+
+type CreateModelBackupCommand struct {
+	*createModelBackupCommand
+}
+
+func NewCreateModelBackupCommandForTest() (cmd.Command, *CreateModelBackupCommand) {
+	c := &createModelBackupCommand{}
+	c.Log = &cmd.Log{}
+	return modelcmd.Wrap(c), &CreateModelBackupCommand{c}
+}
+
+func NewRestoreModelBackupCommandForTest() cmd.Command {
+	c := &restoreModelBackupCommand{}
+	c.Log = &cmd.Log{}
+	return modelcmd.Wrap(c)
+}
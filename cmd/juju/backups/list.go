@@ -8,12 +8,23 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
 const listDoc = `
 list-backups provides the metadata associated with all backups.
+
+Backups taken automatically by the controller (see the backup-interval
+setting of the controller model) are marked as scheduled, and scheduled
+backups that could not be created are listed with the reason for the
+failure. Use --verbose to see the full metadata of each backup.
+
+When the controller copies backups to remote object storage (see the
+backup-s3-bucket setting of the controller model), use --remote to list
+the backups held there instead of those stored on the controller.
 `
 
 // NewListCommand returns a command used to list metadata for backups.
@@ -24,6 +35,8 @@ func NewListCommand() cmd.Command {
 // listCommand is the sub-command for listing all available backups.
 type listCommand struct {
 	CommandBase
+	// Remote means the backups in remote storage should be listed.
+	Remote bool
 }
 
 // Info implements Command.Info.
@@ -37,6 +50,12 @@ func (c *listCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *listCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.Remote, "remote", false, "list the backups held in remote storage")
+}
+
 // Init implements Command.Init.
 func (c *listCommand) Init(args []string) error {
 	if err := cmd.CheckEmpty(args); err != nil {
@@ -58,7 +77,12 @@ func (c *listCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.List()
+	var result *params.BackupsListResult
+	if c.Remote {
+		result, err = client.ListRemote()
+	} else {
+		result, err = client.List()
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -72,15 +96,27 @@ func (c *listCommand) Run(ctx *cmd.Context) error {
 	if verbose {
 		c.dumpMetadata(ctx, &result.List[0])
 	} else {
-		fmt.Fprintln(ctx.Stdout, result.List[0].ID)
+		fmt.Fprintln(ctx.Stdout, briefSummary(&result.List[0]))
 	}
 	for _, resultItem := range result.List[1:] {
 		if verbose {
 			fmt.Fprintln(ctx.Stdout)
 			c.dumpMetadata(ctx, &resultItem)
 		} else {
-			fmt.Fprintln(ctx.Stdout, resultItem.ID)
+			fmt.Fprintln(ctx.Stdout, briefSummary(&resultItem))
 		}
 	}
 	return nil
 }
+
+// briefSummary returns the backup's ID, noting whether it was
+// scheduled and, if so, whether it failed.
+func briefSummary(result *params.BackupsMetadataResult) string {
+	switch {
+	case result.Failure != "":
+		return fmt.Sprintf("%s (scheduled, failed: %s)", result.ID, result.Failure)
+	case result.Scheduled:
+		return fmt.Sprintf("%s (scheduled)", result.ID)
+	}
+	return result.ID
+}
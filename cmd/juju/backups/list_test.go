@@ -4,6 +4,8 @@
 package backups_test
 
 import (
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
@@ -36,6 +38,15 @@ func (s *listSuite) TestOkay(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+func (s *listSuite) TestRemote(c *gc.C) {
+	client := s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.subcommand, "--remote")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "ListRemote")
+	s.checkStd(c, ctx, s.metaresult.ID+"\n", "")
+}
+
 func (s *listSuite) TestBrief(c *gc.C) {
 	s.setSuccess()
 	ctx, err := testing.RunCommand(c, s.subcommand)
@@ -44,6 +55,34 @@ func (s *listSuite) TestBrief(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+func (s *listSuite) TestBriefScheduled(c *gc.C) {
+	s.metaresult.Scheduled = true
+	s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, "spam (scheduled)\n", "")
+}
+
+func (s *listSuite) TestBriefScheduledFailure(c *gc.C) {
+	s.metaresult.Scheduled = true
+	s.metaresult.Failure = "HA not ready"
+	s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, "spam (scheduled, failed: HA not ready)\n", "")
+}
+
+func (s *listSuite) TestVerboseScheduledFailure(c *gc.C) {
+	s.metaresult.Scheduled = true
+	s.metaresult.Failure = "HA not ready"
+	s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.subcommand, "--verbose")
+	c.Assert(err, jc.ErrorIsNil)
+	out := strings.Replace(MetaResultString, "source:          manual\n",
+		"source:          scheduled\nfailure:         \"HA not ready\"\n", 1)
+	s.checkStd(c, ctx, out, "")
+}
+
 func (s *listSuite) TestError(c *gc.C) {
 	s.setFailure("failed!")
 	_, err := testing.RunCommand(c, s.subcommand)
@@ -0,0 +1,203 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const createModelBackupDoc = `
+create-model-backup writes a backup of a single model to a local file.
+Unlike create-backup, which backs up the whole controller, the archive
+holds only the model's own description together with the charms, tools
+and resources it uses, and can be taken by any administrator of the
+model.
+
+If --filename is not used, the archive is written to a file named
+after the model and the current time, and the filename is printed to
+stdout.
+
+See also:
+    restore-model-backup
+`
+
+// NewCreateModelBackupCommand returns a command used to back up a
+// single model.
+func NewCreateModelBackupCommand() cmd.Command {
+	return modelcmd.Wrap(&createModelBackupCommand{})
+}
+
+// createModelBackupCommand is the sub-command for backing up a model.
+type createModelBackupCommand struct {
+	CommandBase
+	// Filename is where to save the archive.
+	Filename string
+}
+
+// Info implements Command.Info.
+func (c *createModelBackupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-model-backup",
+		Purpose: "back up a single model to a local file",
+		Doc:     createModelBackupDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *createModelBackupCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.Filename, "filename", "", "backup target")
+}
+
+// Init implements Command.Init.
+func (c *createModelBackupCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *createModelBackupCommand) Run(ctx *cmd.Context) error {
+	if c.Log != nil {
+		if err := c.Log.Start(ctx); err != nil {
+			return err
+		}
+	}
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	resultArchive, err := client.DownloadModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resultArchive.Close()
+
+	filename := c.ResolveFilename(time.Now())
+	archive, err := os.Create(filename)
+	if err != nil {
+		return errors.Annotate(err, "while creating local archive file")
+	}
+	defer archive.Close()
+	if _, err := io.Copy(archive, resultArchive); err != nil {
+		return errors.Annotate(err, "while writing local archive file")
+	}
+
+	fmt.Fprintln(ctx.Stdout, filename)
+	return nil
+}
+
+// ResolveFilename returns the filename used by the command for a
+// backup taken at the given time.
+func (c *createModelBackupCommand) ResolveFilename(now time.Time) string {
+	if c.Filename != "" {
+		return c.Filename
+	}
+	return fmt.Sprintf("juju-model-backup-%s-%s.tar.gz", c.ModelName(), now.UTC().Format("20060102-150405"))
+}
+
+const restoreModelBackupDoc = `
+restore-model-backup recreates a model from an archive written by
+create-model-backup. Other models on the controller are not affected.
+
+To recreate a model that no longer exists, on the same or another
+controller, run the command as a controller administrator against any
+model on the target controller:
+
+    juju restore-model-backup -m othercontroller:admin backup.tar.gz
+
+To roll an existing model back to the state held in the archive,
+use --replace while connected to that model. This discards all
+changes to the model made since the backup was taken; any model
+administrator may do this.
+
+See also:
+    create-model-backup
+`
+
+// NewRestoreModelBackupCommand returns a command used to restore a
+// model from a model backup.
+func NewRestoreModelBackupCommand() cmd.Command {
+	return modelcmd.Wrap(&restoreModelBackupCommand{})
+}
+
+// restoreModelBackupCommand is the sub-command for restoring a model
+// from a model backup.
+type restoreModelBackupCommand struct {
+	CommandBase
+	// Filename is the archive to restore.
+	Filename string
+	// Replace indicates that an existing model should be replaced.
+	Replace bool
+}
+
+// Info implements Command.Info.
+func (c *restoreModelBackupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore-model-backup",
+		Args:    "<filename>",
+		Purpose: "restore a single model from a model backup",
+		Doc:     restoreModelBackupDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreModelBackupCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.Replace, "replace", false, "replace the current model with the one in the backup")
+}
+
+// Init implements Command.Init.
+func (c *restoreModelBackupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing filename")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.Filename = filename
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreModelBackupCommand) Run(ctx *cmd.Context) error {
+	if c.Log != nil {
+		if err := c.Log.Start(ctx); err != nil {
+			return err
+		}
+	}
+	archive, err := os.Open(c.Filename)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.RestoreModel(archive, c.Replace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tag, err := names.ParseModelTag(result.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "restored model %q (%s)\n", result.Name, tag.Id())
+	return nil
+}
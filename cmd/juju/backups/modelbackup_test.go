@@ -0,0 +1,99 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/testing"
+)
+
+type createModelBackupSuite struct {
+	BaseBackupsSuite
+	wrappedCommand cmd.Command
+	command        *backups.CreateModelBackupCommand
+}
+
+var _ = gc.Suite(&createModelBackupSuite{})
+
+func (s *createModelBackupSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.wrappedCommand, s.command = backups.NewCreateModelBackupCommandForTest()
+}
+
+func (s *createModelBackupSuite) TestOkay(c *gc.C) {
+	client := s.setDownload()
+	s.filename = filepath.Join(c.MkDir(), "model.tar.gz")
+	ctx, err := testing.RunCommand(c, s.wrappedCommand, "--filename", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(client.calls, jc.DeepEquals, []string{"DownloadModel"})
+	s.checkStd(c, ctx, s.filename+"\n", "")
+	s.checkArchive(c)
+}
+
+func (s *createModelBackupSuite) TestDefaultFilename(c *gc.C) {
+	now := time.Date(2016, 6, 1, 12, 30, 0, 0, time.UTC)
+	filename := s.command.ResolveFilename(now)
+	c.Check(filename, gc.Matches, `juju-model-backup-.*-20160601-123000\.tar\.gz`)
+}
+
+func (s *createModelBackupSuite) TestError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := testing.RunCommand(c, s.wrappedCommand, "--filename", filepath.Join(c.MkDir(), "model.tar.gz"))
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
+
+type restoreModelBackupSuite struct {
+	BaseBackupsSuite
+	wrappedCommand cmd.Command
+	archive        string
+}
+
+var _ = gc.Suite(&restoreModelBackupSuite{})
+
+func (s *restoreModelBackupSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.wrappedCommand = backups.NewRestoreModelBackupCommandForTest()
+	s.archive = filepath.Join(c.MkDir(), "model.tar.gz")
+	err := ioutil.WriteFile(s.archive, []byte(s.data), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreModelBackupSuite) TestOkay(c *gc.C) {
+	client := s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.wrappedCommand, s.archive)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(client.calls, jc.DeepEquals, []string{"RestoreModel"})
+	c.Check(client.replace, jc.IsFalse)
+	c.Check(client.restoredData, gc.Equals, s.data)
+	s.checkStd(c, ctx, `restored model "mymodel" (deadbeef-0bad-400d-8000-4b1d0d06f00d)`+"\n", "")
+}
+
+func (s *restoreModelBackupSuite) TestReplace(c *gc.C) {
+	client := s.setSuccess()
+	_, err := testing.RunCommand(c, s.wrappedCommand, "--replace", s.archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.replace, jc.IsTrue)
+}
+
+func (s *restoreModelBackupSuite) TestMissingFilename(c *gc.C) {
+	_, err := testing.RunCommand(c, s.wrappedCommand)
+	c.Check(err, gc.ErrorMatches, "missing filename")
+}
+
+func (s *restoreModelBackupSuite) TestError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := testing.RunCommand(c, s.wrappedCommand, s.archive)
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
@@ -32,6 +32,7 @@ stored:          0001-01-01 00:00:00 +0000 UTC
 started:         0001-01-01 00:00:00 +0000 UTC
 finished:        0001-01-01 00:00:00 +0000 UTC
 notes:           ""
+source:          manual
 model ID:        ""
 machine ID:      ""
 created on host: ""
@@ -123,6 +124,11 @@ type fakeAPIClient struct {
 	args  []string
 	idArg string
 	notes string
+
+	encryptionKey params.BackupsEncryptionKey
+	uploadMeta    params.BackupsMetadataResult
+	replace       bool
+	restoredData  string
 }
 
 func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
@@ -131,10 +137,11 @@ func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
 	c.Check(f.notes, gc.Equals, notes)
 }
 
-func (c *fakeAPIClient) Create(notes string) (*params.BackupsMetadataResult, error) {
+func (c *fakeAPIClient) Create(notes string, key params.BackupsEncryptionKey) (*params.BackupsMetadataResult, error) {
 	c.calls = append(c.calls, "Create")
 	c.args = append(c.args, "notes")
 	c.notes = notes
+	c.encryptionKey = key
 	if c.err != nil {
 		return nil, c.err
 	}
@@ -161,6 +168,16 @@ func (c *fakeAPIClient) List() (*params.BackupsListResult, error) {
 	return &result, nil
 }
 
+func (c *fakeAPIClient) ListRemote() (*params.BackupsListResult, error) {
+	c.calls = append(c.calls, "ListRemote")
+	if c.err != nil {
+		return nil, c.err
+	}
+	var result params.BackupsListResult
+	result.List = []params.BackupsMetadataResult{*c.metaresult}
+	return &result, nil
+}
+
 func (c *fakeAPIClient) Download(id string) (io.ReadCloser, error) {
 	c.calls = append(c.calls, "Download")
 	c.args = append(c.args, "id")
@@ -173,6 +190,7 @@ func (c *fakeAPIClient) Download(id string) (io.ReadCloser, error) {
 
 func (c *fakeAPIClient) Upload(ar io.ReadSeeker, meta params.BackupsMetadataResult) (string, error) {
 	c.args = append(c.args, "ar", "meta")
+	c.uploadMeta = meta
 	if c.err != nil {
 		return "", c.err
 	}
@@ -189,14 +207,39 @@ func (c *fakeAPIClient) Remove(id string) error {
 	return nil
 }
 
+func (c *fakeAPIClient) DownloadModel() (io.ReadCloser, error) {
+	c.calls = append(c.calls, "DownloadModel")
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.archive, nil
+}
+
+func (c *fakeAPIClient) RestoreModel(archive io.ReadSeeker, replace bool) (params.BackupsRestoreModelResult, error) {
+	c.calls = append(c.calls, "RestoreModel")
+	c.replace = replace
+	if c.err != nil {
+		return params.BackupsRestoreModelResult{}, c.err
+	}
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return params.BackupsRestoreModelResult{}, err
+	}
+	c.restoredData = string(data)
+	return params.BackupsRestoreModelResult{
+		ModelTag: "model-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		Name:     "mymodel",
+	}, nil
+}
+
 func (c *fakeAPIClient) Close() error {
 	return nil
 }
 
-func (c *fakeAPIClient) RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, apibackups.ClientConnection) error {
+func (c *fakeAPIClient) RestoreReader(io.ReadSeeker, *params.BackupsMetadataResult, params.BackupsDecryptionKey, apibackups.ClientConnection) error {
 	return nil
 }
 
-func (c *fakeAPIClient) Restore(string, apibackups.ClientConnection) error {
+func (c *fakeAPIClient) Restore(string, params.BackupsDecryptionKey, apibackups.ClientConnection) error {
 	return nil
 }
@@ -44,10 +44,11 @@ type restoreCommand struct {
 	backupId    string
 	bootstrap   bool
 	uploadTools bool
+	keyFlags    decryptionKeyFlags
 
 	newAPIClientFunc func() (RestoreAPI, error)
 	getEnvironFunc   func(string, *params.BackupsMetadataResult) (environs.Environ, error)
-	getArchiveFunc   func(string) (ArchiveReader, *params.BackupsMetadataResult, error)
+	getArchiveFunc   func(string, params.BackupsDecryptionKey) (ArchiveReader, *params.BackupsMetadataResult, error)
 }
 
 // RestoreAPI is used to invoke various API calls.
@@ -56,10 +57,10 @@ type RestoreAPI interface {
 	Close() error
 
 	// Restore is taken from backups.Client.
-	Restore(backupId string, newClient backups.ClientConnection) error
+	Restore(backupId string, key params.BackupsDecryptionKey, newClient backups.ClientConnection) error
 
 	// RestoreReader is taken from backups.Client.
-	RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, newClient backups.ClientConnection) error
+	RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, key params.BackupsDecryptionKey, newClient backups.ClientConnection) error
 }
 
 var restoreDoc = `
@@ -77,6 +78,10 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+An encrypted backup is decrypted transparently when its key is given,
+either with --passphrase-file or, for backups encrypted to a public key,
+with --private-key-file.
 `
 
 var BootstrapFunc = bootstrap.Bootstrap
@@ -101,6 +106,7 @@ func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.filename, "file", "", "provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "provide the name of the backup to be restored.")
 	f.BoolVar(&c.uploadTools, "upload-tools", false, "upload tools if bootstraping a new machine.")
+	c.keyFlags.SetFlags(f)
 }
 
 // Init is where the preconditions for this commands can be checked.
@@ -114,6 +120,9 @@ func (c *restoreCommand) Init(args []string) error {
 	if c.backupId != "" && c.bootstrap {
 		return errors.Errorf("it is not possible to rebootstrap and restore from an id.")
 	}
+	if err := c.keyFlags.Validate(); err != nil {
+		return errors.Trace(err)
+	}
 	var err error
 	if c.filename != "" {
 		c.filename, err = filepath.Abs(c.filename)
@@ -239,6 +248,11 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	key, err := c.keyFlags.Key(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	var archive ArchiveReader
 	var meta *params.BackupsMetadataResult
 	target := c.backupId
@@ -247,8 +261,7 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		// we'll need the info later regardless if
 		// we need it now to rebootstrap.
 		target = c.filename
-		var err error
-		archive, meta, err = c.getArchiveFunc(c.filename)
+		archive, meta, err = c.getArchiveFunc(c.filename, key)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -270,9 +283,9 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	// We have a backup client, now use the relevant method
 	// to restore the backup.
 	if c.filename != "" {
-		err = client.RestoreReader(archive, meta, c.newClient)
+		err = client.RestoreReader(archive, meta, key, c.newClient)
 	} else {
-		err = client.Restore(c.backupId, c.newClient)
+		err = client.Restore(c.backupId, key, c.newClient)
 	}
 	if err != nil {
 		return nil
@@ -4,9 +4,15 @@
 package backups_test
 
 import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
 	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	apibackups "github.com/juju/juju/api/backups"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/cmd/juju/backups"
@@ -80,11 +86,41 @@ func (s *restoreSuite) TestRestoreArgs(c *gc.C) {
 
 	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "-b")
 	c.Assert(err, gc.ErrorMatches, "it is not possible to rebootstrap and restore from an id.")
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--id", "anid", "--passphrase-file", "a", "--private-key-file", "b")
+	c.Assert(err, gc.ErrorMatches, "cannot mix --passphrase-file and --private-key-file")
+}
+
+func (s *restoreSuite) TestRestorePassesKey(c *gc.C) {
+	api := &mockRestoreAPI{}
+	var archiveKey params.BackupsDecryptionKey
+	s.command = backups.NewRestoreCommandForTest(
+		s.store, api,
+		func(_ string, key params.BackupsDecryptionKey) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+			archiveKey = key
+			return &mockArchiveReader{}, &params.BackupsMetadataResult{}, nil
+		},
+		nil)
+	keyFile := filepath.Join(c.MkDir(), "key.pem")
+	err := ioutil.WriteFile(keyFile, []byte("<private key>"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = testing.RunCommand(c, s.command, "restore", "--file", "afile", "--private-key-file", keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	expected := params.BackupsDecryptionKey{PrivateKey: "<private key>"}
+	c.Check(archiveKey, jc.DeepEquals, expected)
+	c.Check(api.key, jc.DeepEquals, expected)
 }
 
 // TODO(wallyworld) - add more api related unit tests
 type mockRestoreAPI struct {
 	backups.RestoreAPI
+	key params.BackupsDecryptionKey
+}
+
+func (m *mockRestoreAPI) RestoreReader(_ io.ReadSeeker, _ *params.BackupsMetadataResult, key params.BackupsDecryptionKey, _ apibackups.ClientConnection) error {
+	m.key = key
+	return nil
 }
 
 func (*mockRestoreAPI) Close() error {
@@ -103,7 +139,7 @@ func (s *restoreSuite) TestRestoreReboostrapControllerExists(c *gc.C) {
 	fakeEnv := fakeEnviron{controllerInstances: []instance.Id{"1"}}
 	s.command = backups.NewRestoreCommandForTest(
 		s.store, &mockRestoreAPI{},
-		func(string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+		func(string, params.BackupsDecryptionKey) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
 			return &mockArchiveReader{}, &params.BackupsMetadataResult{}, nil
 		},
 		func(string, *params.BackupsMetadataResult) (environs.Environ, error) {
@@ -117,7 +153,7 @@ func (s *restoreSuite) TestRestoreReboostrapNoControllers(c *gc.C) {
 	fakeEnv := fakeEnviron{}
 	s.command = backups.NewRestoreCommandForTest(
 		s.store, &mockRestoreAPI{},
-		func(string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+		func(string, params.BackupsDecryptionKey) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
 			return &mockArchiveReader{}, &params.BackupsMetadataResult{}, nil
 		},
 		func(string, *params.BackupsMetadataResult) (environs.Environ, error) {
@@ -138,7 +174,7 @@ func (s *restoreSuite) TestRestoreReboostrapReadsMetadata(c *gc.C) {
 	}
 	s.command = backups.NewRestoreCommandForTest(
 		s.store, &mockRestoreAPI{},
-		func(string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+		func(string, params.BackupsDecryptionKey) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
 			return &mockArchiveReader{}, &metadata, nil
 		},
 		nil)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -15,6 +16,9 @@ import (
 
 const uploadDoc = `
 upload-backup sends a backup archive file to remote storage.
+
+An encrypted archive is stored as it is, but its key must be given with
+--passphrase-file or --private-key-file so that its metadata can be read.
 `
 
 // NewUploadCommand returns a command used to send a backup
@@ -28,6 +32,8 @@ type uploadCommand struct {
 	CommandBase
 	// Filename is where to find the archive to upload.
 	Filename string
+	// keyFlags supply the key for reading an encrypted archive.
+	keyFlags decryptionKeyFlags
 }
 
 // Info implements Command.Info.
@@ -40,8 +46,17 @@ func (c *uploadCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *uploadCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.keyFlags.SetFlags(f)
+}
+
 // Init implements Command.Init.
 func (c *uploadCommand) Init(args []string) error {
+	if err := c.keyFlags.Validate(); err != nil {
+		return errors.Trace(err)
+	}
 	if len(args) == 0 {
 		return errors.New("backup filename not specified")
 	}
@@ -60,13 +75,18 @@ func (c *uploadCommand) Run(ctx *cmd.Context) error {
 			return err
 		}
 	}
+	key, err := c.keyFlags.Key(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer client.Close()
 
-	archive, meta, err := getArchive(c.Filename)
+	archive, meta, err := getArchive(c.Filename, key)
 	if err != nil {
 		return errors.Trace(err)
 	}
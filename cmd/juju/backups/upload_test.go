@@ -5,8 +5,11 @@ package backups_test
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -14,6 +17,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/juju/backups"
+	statebackups "github.com/juju/juju/state/backups"
 	"github.com/juju/juju/testing"
 )
 
@@ -79,6 +83,55 @@ func (s *uploadSuite) TestOkay(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+// encryptArchive replaces the archive with an encrypted copy, and
+// returns the name of a file holding the passphrase.
+func (s *uploadSuite) encryptArchive(c *gc.C) string {
+	data, err := ioutil.ReadFile(s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	var encrypted bytes.Buffer
+	err = statebackups.Encrypt(&encrypted, bytes.NewReader(data), statebackups.EncryptionKey{Passphrase: "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(s.filename, encrypted.Bytes(), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	passphraseFile := filepath.Join(c.MkDir(), "passphrase")
+	err = ioutil.WriteFile(passphraseFile, []byte("sekrit\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return passphraseFile
+}
+
+func (s *uploadSuite) TestEncrypted(c *gc.C) {
+	s.createArchive(c)
+	passphraseFile := s.encryptArchive(c)
+	client := s.setSuccess()
+	_, err := testing.RunCommand(c, s.command, s.filename, "--passphrase-file", passphraseFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := os.Stat(s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.uploadMeta.Encryption, gc.Equals, statebackups.EncryptionPassphrase)
+	c.Check(client.uploadMeta.Size, gc.Equals, info.Size())
+}
+
+func (s *uploadSuite) TestEncryptedNoKey(c *gc.C) {
+	s.createArchive(c)
+	s.encryptArchive(c)
+	s.setSuccess()
+	_, err := testing.RunCommand(c, s.command, s.filename)
+	c.Check(err, gc.ErrorMatches, `backup archive is encrypted \(scrypt-aes256-gcm\); use --passphrase-file or --private-key-file`)
+}
+
+func (s *uploadSuite) TestEncryptedWrongKey(c *gc.C) {
+	s.createArchive(c)
+	s.encryptArchive(c)
+	s.setSuccess()
+	passphraseFile := filepath.Join(c.MkDir(), "passphrase")
+	err := ioutil.WriteFile(passphraseFile, []byte("guess"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, s.command, s.filename, "--passphrase-file", passphraseFile)
+	c.Check(err, gc.ErrorMatches, ".*wrong key or corrupt data")
+}
+
 func (s *uploadSuite) TestFileMissing(c *gc.C) {
 	s.setSuccess()
 	_, err := testing.RunCommand(c, s.command, s.filename)
@@ -23,10 +23,12 @@ import (
 	"github.com/juju/juju/cmd/juju/charmcmd"
 	"github.com/juju/juju/cmd/juju/cloud"
 	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/cmd/juju/firewall"
 	"github.com/juju/juju/cmd/juju/gui"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/cmd/juju/secrets"
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/setmeterstatus"
 	"github.com/juju/juju/cmd/juju/space"
@@ -202,6 +204,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewShowHookStatsCommand())
+	r.Register(status.NewShowUnitCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newRunCommand())
@@ -216,6 +220,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewModelSetConstraintsCommand())
 	r.Register(newSyncToolsCommand())
 	r.Register(newUpgradeJujuCommand(nil))
+	r.Register(newRollbackUpgradeCommand())
 	r.Register(service.NewUpgradeCharmCommand())
 
 	// Charm publishing commands.
@@ -233,6 +238,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(backups.NewRemoveCommand())
 	r.Register(backups.NewRestoreCommand())
 	r.Register(backups.NewUploadCommand())
+	r.Register(backups.NewCreateModelBackupCommand())
+	r.Register(backups.NewRestoreModelBackupCommand())
 
 	// Manage authorized ssh keys.
 	r.Register(NewAddKeysCommand())
@@ -283,6 +290,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage controller availability
 	r.Register(newEnableHACommand())
+	r.Register(newShowHACommand())
+	r.Register(newRemoveControllerCommand())
 
 	// Manage and control services
 	r.Register(service.NewAddUnitCommand())
@@ -322,6 +331,15 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 		r.Register(subnet.NewRemoveCommand())
 	}
 
+	// Manage firewall rules
+	r.Register(firewall.NewAddFirewallRuleCommand())
+	r.Register(firewall.NewListFirewallRulesCommand())
+	r.Register(firewall.NewRemoveFirewallRuleCommand())
+
+	// Inspect charm secrets
+	r.Register(secrets.NewListSecretsCommand())
+	r.Register(secrets.NewShowSecretCommand())
+
 	// Manage controllers
 	r.Register(controller.NewCreateModelCommand())
 	r.Register(controller.NewDestroyCommand())
@@ -328,6 +328,7 @@ var commandNames = []string{
 	"actions",
 	"add-cloud",
 	"add-credential",
+	"add-firewall-rule",
 	"add-machine",
 	"add-machines",
 	"add-relation",
@@ -352,6 +353,7 @@ var commandNames = []string{
 	"create-backup",
 	"create-budget",
 	"create-model",
+	"create-model-backup",
 	"create-storage-pool",
 	"debug-hooks",
 	"debug-log",
@@ -367,6 +369,7 @@ var commandNames = []string{
 	"enable-ha",
 	"enable-user",
 	"expose",
+	"firewall-rules",
 	"get-config",
 	"get-configs",
 	"get-constraints",
@@ -388,10 +391,12 @@ var commandNames = []string{
 	"list-clouds",
 	"list-controllers",
 	"list-credentials",
+	"list-firewall-rules",
 	"list-machine",
 	"list-machines",
 	"list-models",
 	"list-plans",
+	"list-secrets",
 	"list-shares",
 	"list-ssh-key",
 	"list-ssh-keys",
@@ -409,7 +414,9 @@ var commandNames = []string{
 	"remove-all-blocks",
 	"remove-backup",
 	"remove-cached-images",
+	"remove-controller-machine",
 	"remove-credential",
+	"remove-firewall-rule",
 	"remove-machine",
 	"remove-machines",
 	"remove-relation", // alias for destroy-relation
@@ -419,11 +426,14 @@ var commandNames = []string{
 	"remove-unit", // alias for destroy-unit
 	"resolved",
 	"restore-backup",
+	"restore-model-backup",
 	"retry-provisioning",
 	"revoke",
+	"rollback-upgrade",
 	"run",
 	"run-action",
 	"scp",
+	"secrets",
 	"set-budget",
 	"set-config",
 	"set-configs",
@@ -443,11 +453,15 @@ var commandNames = []string{
 	"show-cloud",
 	"show-controller",
 	"show-controllers",
+	"show-ha",
+	"show-hook-stats",
 	"show-machine",
 	"show-machines",
 	"show-model",
+	"show-secret",
 	"show-status",
 	"show-storage",
+	"show-unit",
 	"show-user",
 	"spaces",
 	"ssh",
@@ -0,0 +1,192 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/clock"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+)
+
+// removeControllerPollInterval is how often remove-controller-machine
+// checks whether the machine's vote has been removed.
+const removeControllerPollInterval = 5 * time.Second
+
+func newRemoveControllerCommand() cmd.Command {
+	return modelcmd.Wrap(&removeControllerCommand{clock: clock.WallClock})
+}
+
+// removeControllerCommand retires a single controller machine.
+type removeControllerCommand struct {
+	modelcmd.ModelCommandBase
+	haClient RemoveControllerClient
+	clock    clock.Clock
+
+	// MachineId holds the id of the controller machine to remove.
+	MachineId string
+	// Replace requests a new controller machine to take over the
+	// removed machine's vote.
+	Replace bool
+	// Series, Constraints and Placement apply to the replacement.
+	Series      string
+	Constraints constraints.Value
+	Placement   string
+	// NoWait stops the command once the machine has been demoted,
+	// rather than waiting for it to be removed.
+	NoWait bool
+	// Timeout is how long to wait for the machine to be removed.
+	Timeout time.Duration
+}
+
+const removeControllerDoc = `
+Remove a single controller machine from a highly available controller.
+
+The machine is first demoted, so that its vote in the controller's
+replica set is passed on to another controller. Once the replica set
+has converged, the machine stops being a controller, the new set of
+API addresses is published to all agents, and the machine is
+destroyed.
+
+A voting controller can only be removed if another controller can take
+over its vote. With --replace, a new controller machine is started for
+the purpose; it has the same series and constraints as the machine
+being removed, unless --series or --constraints are specified, and may
+be placed with --to (for example, in a different availability zone).
+Without --replace, an available non-voting controller is promoted.
+
+Controller machines that host units cannot be removed.
+
+If the command is interrupted, or is run with --no-wait, it can be run
+again with the same machine to continue the removal.
+
+remove-controller-machine must be run against the controller model.
+
+Examples:
+ juju remove-controller-machine -m controller 1 --replace
+     Start a new controller machine to replace machine 1, and remove
+     machine 1 once the new machine has taken over its vote.
+ juju remove-controller-machine -m controller 1 --replace --to zone=us-east-1c
+     As above, starting the replacement in zone us-east-1c.
+`
+
+func (c *removeControllerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-controller-machine",
+		Args:    "<machine>",
+		Purpose: "remove a controller machine, optionally replacing it",
+		Doc:     removeControllerDoc,
+	}
+}
+
+func (c *removeControllerCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Replace, "replace", false, "start a new controller machine to take over the removed machine's vote")
+	f.StringVar(&c.Series, "series", "", "the series of the replacement machine")
+	f.Var(constraints.ConstraintsValue{&c.Constraints}, "constraints", "the constraints of the replacement machine")
+	f.StringVar(&c.Placement, "to", "", "where to place the replacement machine")
+	f.BoolVar(&c.NoWait, "no-wait", false, "do not wait for the machine to be removed")
+	f.DurationVar(&c.Timeout, "timeout", 30*time.Minute, "how long to wait for the machine to be removed")
+}
+
+func (c *removeControllerCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine specified")
+	}
+	c.MachineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.MachineId) {
+		return errors.Errorf("invalid machine id %q", c.MachineId)
+	}
+	if names.IsContainerMachine(c.MachineId) {
+		return errors.Errorf("machine %s is a container, and cannot be a controller", c.MachineId)
+	}
+	if !c.Replace && (c.Series != "" || c.Placement != "" || !constraints.IsEmpty(&c.Constraints)) {
+		return errors.New("--series, --constraints and --to require --replace")
+	}
+	if c.Placement != "" {
+		p, err := instance.ParsePlacement(c.Placement)
+		if err == nil && p.Scope == instance.MachineScope {
+			return errors.New("remove-controller-machine cannot place the replacement on an existing machine; use enable-ha --to")
+		}
+		if err != nil && err != instance.ErrPlacementScopeMissing {
+			return errors.Errorf("unsupported placement directive %q", c.Placement)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// RemoveControllerClient defines the methods on the high
+// availability client api that the remove-controller-machine
+// command calls.
+type RemoveControllerClient interface {
+	Close() error
+	RemoveController(
+		machineId string, replace bool, cons constraints.Value,
+		series, placement string) (params.ControllersChanges, error)
+}
+
+func (c *removeControllerCommand) getHAClient() (RemoveControllerClient, error) {
+	if c.haClient != nil {
+		return c.haClient, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return highavailability.NewClient(root), nil
+}
+
+// Run demotes the controller machine, and then waits for the
+// controller to remove it.
+func (c *removeControllerCommand) Run(ctx *cmd.Context) error {
+	haClient, err := c.getHAClient()
+	if err != nil {
+		return err
+	}
+	defer haClient.Close()
+
+	deadline := c.clock.Now().Add(c.Timeout)
+	replace := c.Replace
+	waiting := false
+	for {
+		changes, err := haClient.RemoveController(c.MachineId, replace, c.Constraints, c.Series, c.Placement)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockRemove)
+		}
+		// The replacement is only started when the
+		// machine is demoted, by the first call.
+		replace = false
+		for _, id := range machineTagsToIds(changes.Added...) {
+			ctx.Infof("started controller machine %s to take over the vote of machine %s", id, c.MachineId)
+		}
+		for _, id := range machineTagsToIds(changes.Promoted...) {
+			ctx.Infof("promoted controller machine %s to take over the vote of machine %s", id, c.MachineId)
+		}
+		if len(changes.Removed) > 0 {
+			ctx.Infof("machine %s is no longer a controller, and will be destroyed", c.MachineId)
+			return nil
+		}
+		if c.NoWait {
+			ctx.Infof("removal of machine %s has started; run remove-controller-machine again to complete it", c.MachineId)
+			return nil
+		}
+		if !waiting {
+			ctx.Infof("waiting for the vote of machine %s to be removed", c.MachineId)
+			waiting = true
+		}
+		if !c.clock.Now().Before(deadline) {
+			return errors.Errorf("timed out waiting for the vote of machine %s to be removed; run remove-controller-machine again to resume", c.MachineId)
+		}
+		<-c.clock.After(removeControllerPollInterval)
+	}
+}
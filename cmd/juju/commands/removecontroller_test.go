@@ -0,0 +1,212 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveControllerSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  *fakeRemoveControllerClient
+	store *jujuclienttesting.MemStore
+	clock *testing.Clock
+}
+
+var _ = gc.Suite(&RemoveControllerSuite{})
+
+func (s *RemoveControllerSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = newControllerModelStore(c)
+	s.clock = testing.NewClock(time.Time{})
+	s.fake = &fakeRemoveControllerClient{}
+}
+
+// newControllerModelStore returns a client store holding the
+// controller model of a controller called "ctrl".
+func newControllerModelStore(c *gc.C) *jujuclienttesting.MemStore {
+	store := jujuclienttesting.NewMemStore()
+	err := store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "eeeeeeee-0bad-400d-8000-4b1d0d06f00d",
+		CACert:         "cert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = modelcmd.WriteCurrentController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateAccount("ctrl", "admin@local", jujuclient.AccountDetails{
+		User: "admin@local",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.SetCurrentAccount("ctrl", "admin@local")
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateModel("ctrl", "admin@local", "controller", jujuclient.ModelDetails{
+		ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return store
+}
+
+type fakeRemoveControllerClient struct {
+	jujutesting.Stub
+	results []params.ControllersChanges
+}
+
+func (f *fakeRemoveControllerClient) Close() error {
+	return nil
+}
+
+func (f *fakeRemoveControllerClient) RemoveController(
+	machineId string, replace bool, cons constraints.Value, series, placement string,
+) (params.ControllersChanges, error) {
+	f.MethodCall(f, "RemoveController", machineId, replace, cons, series, placement)
+	if err := f.NextErr(); err != nil {
+		return params.ControllersChanges{}, err
+	}
+	result := f.results[0]
+	if len(f.results) > 1 {
+		f.results = f.results[1:]
+	}
+	return result, nil
+}
+
+func (s *RemoveControllerSuite) runRemoveController(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &removeControllerCommand{haClient: s.fake, clock: s.clock}
+	command.SetClientStore(s.store)
+	return testing.RunCommand(c, modelcmd.Wrap(command), append([]string{"-m", "controller"}, args...)...)
+}
+
+func (s *RemoveControllerSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machine specified",
+	}, {
+		args: []string{"foo"},
+		err:  `invalid machine id "foo"`,
+	}, {
+		args: []string{"0/lxc/0"},
+		err:  "machine 0/lxc/0 is a container, and cannot be a controller",
+	}, {
+		args: []string{"1", "2"},
+		err:  `unrecognized args: \["2"\]`,
+	}, {
+		args: []string{"1", "--series", "trusty"},
+		err:  "--series, --constraints and --to require --replace",
+	}, {
+		args: []string{"1", "--replace", "--to", "2"},
+		err:  "remove-controller-machine cannot place the replacement on an existing machine; use enable-ha --to",
+	}, {
+		args: []string{"1", "--replace", "--to", "lxc:2"},
+		err:  `unsupported placement directive "lxc:2"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(modelcmd.Wrap(&removeControllerCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RemoveControllerSuite) TestRemoveWithReplacement(c *gc.C) {
+	s.fake.results = []params.ControllersChanges{{
+		Added:   []string{"machine-3"},
+		Demoted: []string{"machine-1"},
+	}, {
+		// Waiting for the vote to be removed.
+	}, {
+		Removed: []string{"machine-1"},
+	}}
+
+	type result struct {
+		ctx *cmd.Context
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		ctx, err := s.runRemoveController(c, "1", "--replace", "--to", "zone=z2", "--constraints", "mem=8G")
+		done <- result{ctx, err}
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-s.clock.Alarms():
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for poll %d", i)
+		}
+		s.clock.Advance(removeControllerPollInterval)
+	}
+	var r result
+	select {
+	case r = <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for command")
+	}
+	c.Assert(r.err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(r.ctx), gc.Equals, ""+
+		"started controller machine 3 to take over the vote of machine 1\n"+
+		"waiting for the vote of machine 1 to be removed\n"+
+		"machine 1 is no longer a controller, and will be destroyed\n",
+	)
+	cons := constraints.MustParse("mem=8G")
+	s.fake.CheckCalls(c, []jujutesting.StubCall{
+		{"RemoveController", []interface{}{"1", true, cons, "", "zone=z2"}},
+		{"RemoveController", []interface{}{"1", false, cons, "", "zone=z2"}},
+		{"RemoveController", []interface{}{"1", false, cons, "", "zone=z2"}},
+	})
+}
+
+func (s *RemoveControllerSuite) TestNoWait(c *gc.C) {
+	s.fake.results = []params.ControllersChanges{{
+		Promoted: []string{"machine-2"},
+		Demoted:  []string{"machine-1"},
+	}}
+	ctx, err := s.runRemoveController(c, "1", "--no-wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"promoted controller machine 2 to take over the vote of machine 1\n"+
+		"removal of machine 1 has started; run remove-controller-machine again to complete it\n",
+	)
+	s.fake.CheckCallNames(c, "RemoveController")
+}
+
+func (s *RemoveControllerSuite) TestTimeout(c *gc.C) {
+	s.fake.results = []params.ControllersChanges{{}}
+	done := make(chan error)
+	go func() {
+		_, err := s.runRemoveController(c, "1", "--timeout", "10s")
+		done <- err
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-s.clock.Alarms():
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for poll %d", i)
+		}
+		s.clock.Advance(removeControllerPollInterval)
+	}
+	select {
+	case err := <-done:
+		c.Assert(err, gc.ErrorMatches, "timed out waiting for the vote of machine 1 to be removed; .*")
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for command")
+	}
+	s.fake.CheckCallNames(c, "RemoveController", "RemoveController", "RemoveController")
+}
+
+func (s *RemoveControllerSuite) TestBlocked(c *gc.C) {
+	s.fake.SetErrors(common.OperationBlockedError("TestBlocked"))
+	_, err := s.runRemoveController(c, "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+}
@@ -0,0 +1,132 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+func newRollbackUpgradeCommand() cmd.Command {
+	command := &rollbackUpgradeCommand{}
+	command.newAPIFunc = func() (RollbackUpgradeAPI, error) {
+		return command.NewAPIClient()
+	}
+	command.restoreFunc = command.restore
+	return modelcmd.Wrap(command)
+}
+
+// rollbackUpgradeCommand returns a model to the agent version it ran
+// before its most recent upgrade.
+type rollbackUpgradeCommand struct {
+	modelcmd.ModelCommandBase
+	newAPIFunc  func() (RollbackUpgradeAPI, error)
+	restoreFunc func(backupId string) error
+}
+
+const rollbackUpgradeDoc = `
+Roll back the most recent agent upgrade of a model.
+
+The model's agent version is set back to the version it ran before the
+upgrade, and every agent that has already upgraded downgrades to it.
+
+Before the controller model is upgraded, a backup of the controller is
+taken. Rolling back an upgrade of the controller model first restores
+the controller from that backup, which returns the controllers to the
+previous agent version and undoes any changes the upgrade made to the
+controller's database. Anything else changed in the controller since
+the upgrade started is lost.
+
+An upgrade cannot be rolled back once it has run upgrade steps that
+cannot be undone, if the agent version has been changed again since,
+or if it has already been rolled back.
+
+Examples:
+ juju rollback-upgrade -m controller
+     Restore the controller to the state it was in before its most
+     recent upgrade.
+`
+
+func (c *rollbackUpgradeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rollback-upgrade",
+		Purpose: "roll back the most recent agent upgrade of a model",
+		Doc:     rollbackUpgradeDoc,
+	}
+}
+
+func (c *rollbackUpgradeCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// RollbackUpgradeAPI defines the methods on the client api that the
+// rollback-upgrade command calls.
+type RollbackUpgradeAPI interface {
+	Close() error
+	PrepareUpgradeRollback() (params.UpgradeRollbackInfo, error)
+	FinishUpgradeRollback(info params.UpgradeRollbackInfo) error
+}
+
+// newBackupsClient returns a client for the backups facade, for
+// restoring the controller.
+func (c *rollbackUpgradeCommand) newBackupsClient() (*backups.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return backups.NewClient(root)
+}
+
+// restore restores the controller from the backup with the given id.
+// The backup taken before an upgrade is not encrypted.
+func (c *rollbackUpgradeCommand) restore(backupId string) error {
+	client, err := c.newBackupsClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return client.Restore(backupId, params.BackupsDecryptionKey{}, c.newBackupsClient)
+}
+
+// Run checks that the model's most recent upgrade can be rolled back,
+// restores the controller from the backup taken before the upgrade if
+// there is one, and then sets the model's agent version back.
+func (c *rollbackUpgradeCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := client.PrepareUpgradeRollback()
+	client.Close()
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	if info.BackupId != "" {
+		ctx.Infof("restoring the controller from backup %s, taken before upgrading from %s to %s",
+			info.BackupId, info.PreviousVersion, info.TargetVersion)
+		if err := c.restoreFunc(info.BackupId); err != nil {
+			return errors.Annotate(err, "cannot restore the controller")
+		}
+		// The controller restarted when it was restored, so a new
+		// connection is needed.
+	}
+
+	client, err = c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if err := client.FinishUpgradeRollback(info); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("rolled back upgrade from %s to %s; agents will downgrade to %s",
+		info.PreviousVersion, info.TargetVersion, info.PreviousVersion)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type RollbackUpgradeSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  *fakeRollbackUpgradeAPI
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&RollbackUpgradeSuite{})
+
+func (s *RollbackUpgradeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = newControllerModelStore(c)
+	s.fake = &fakeRollbackUpgradeAPI{
+		info: params.UpgradeRollbackInfo{
+			PreviousVersion: version.MustParse("2.0.1"),
+			TargetVersion:   version.MustParse("2.1.0"),
+			BackupId:        "backup-id",
+		},
+	}
+}
+
+// fakeRollbackUpgradeAPI records calls to both the client api and
+// the restore of the controller.
+type fakeRollbackUpgradeAPI struct {
+	jujutesting.Stub
+	info params.UpgradeRollbackInfo
+}
+
+func (f *fakeRollbackUpgradeAPI) Close() error {
+	return nil
+}
+
+func (f *fakeRollbackUpgradeAPI) PrepareUpgradeRollback() (params.UpgradeRollbackInfo, error) {
+	f.MethodCall(f, "PrepareUpgradeRollback")
+	if err := f.NextErr(); err != nil {
+		return params.UpgradeRollbackInfo{}, err
+	}
+	return f.info, nil
+}
+
+func (f *fakeRollbackUpgradeAPI) FinishUpgradeRollback(info params.UpgradeRollbackInfo) error {
+	f.MethodCall(f, "FinishUpgradeRollback", info)
+	return f.NextErr()
+}
+
+func (f *fakeRollbackUpgradeAPI) restore(backupId string) error {
+	f.MethodCall(f, "Restore", backupId)
+	return f.NextErr()
+}
+
+func (s *RollbackUpgradeSuite) runRollbackUpgrade(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &rollbackUpgradeCommand{
+		newAPIFunc: func() (RollbackUpgradeAPI, error) {
+			return s.fake, nil
+		},
+		restoreFunc: s.fake.restore,
+	}
+	command.SetClientStore(s.store)
+	return testing.RunCommand(c, modelcmd.Wrap(command), append([]string{"-m", "controller"}, args...)...)
+}
+
+func (s *RollbackUpgradeSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(modelcmd.Wrap(&rollbackUpgradeCommand{}), []string{"2.0.1"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["2.0.1"\]`)
+}
+
+func (s *RollbackUpgradeSuite) TestRollbackController(c *gc.C) {
+	ctx, err := s.runRollbackUpgrade(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, ""+
+		"restoring the controller from backup backup-id, taken before upgrading from 2.0.1 to 2.1.0\n"+
+		"rolled back upgrade from 2.0.1 to 2.1.0; agents will downgrade to 2.0.1\n",
+	)
+	s.fake.CheckCalls(c, []jujutesting.StubCall{
+		{"PrepareUpgradeRollback", nil},
+		{"Restore", []interface{}{"backup-id"}},
+		{"FinishUpgradeRollback", []interface{}{s.fake.info}},
+	})
+}
+
+func (s *RollbackUpgradeSuite) TestRollbackWithoutBackup(c *gc.C) {
+	s.fake.info.BackupId = ""
+	ctx, err := s.runRollbackUpgrade(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "rolled back upgrade from 2.0.1 to 2.1.0; agents will downgrade to 2.0.1\n")
+	s.fake.CheckCallNames(c, "PrepareUpgradeRollback", "FinishUpgradeRollback")
+}
+
+func (s *RollbackUpgradeSuite) TestCannotRollback(c *gc.C) {
+	s.fake.SetErrors(errors.New("cannot roll back upgrade to 2.1.0: irreversible upgrade steps have run: foo"))
+	_, err := s.runRollbackUpgrade(c)
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to 2.1.0: irreversible upgrade steps have run: foo")
+	s.fake.CheckCallNames(c, "PrepareUpgradeRollback")
+}
+
+func (s *RollbackUpgradeSuite) TestRestoreFails(c *gc.C) {
+	s.fake.SetErrors(nil, errors.New("boom"))
+	_, err := s.runRollbackUpgrade(c)
+	c.Assert(err, gc.ErrorMatches, "cannot restore the controller: boom")
+	s.fake.CheckCallNames(c, "PrepareUpgradeRollback", "Restore")
+}
+
+func (s *RollbackUpgradeSuite) TestBlocked(c *gc.C) {
+	s.fake.SetErrors(common.OperationBlockedError("TestBlocked"))
+	_, err := s.runRollbackUpgrade(c)
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+}
@@ -0,0 +1,202 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/highavailability"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+func newShowHACommand() cmd.Command {
+	return modelcmd.Wrap(&showHACommand{})
+}
+
+// showHACommand shows the replica set status of the controller
+// machines.
+type showHACommand struct {
+	modelcmd.ModelCommandBase
+	out      cmd.Output
+	haClient HAStatusClient
+	isoTime  bool
+}
+
+const showHADoc = `
+Show the status of each controller machine's membership of the
+controller's mongo replica set: whether it has a vote, and whether
+it should; its replica set state (PRIMARY, SECONDARY, RECOVERING,
+...); how far its replication lags behind the primary; and when it
+last responded to a heartbeat.
+
+Any problems that prevent the controller from reconciling the
+replica set with the controller machines are listed after the
+members. Members that are not controller machines are shown
+without a machine id.
+
+show-ha must be run against the controller model.
+
+Examples:
+ juju show-ha -m controller
+ juju show-ha -m controller --format yaml
+`
+
+func (c *showHACommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-ha",
+		Purpose: "show the status of the controller replica set",
+		Doc:     showHADoc,
+	}
+}
+
+func (c *showHACommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatHAStatusTabular,
+	})
+}
+
+func (c *showHACommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// HAStatusClient defines the methods on the high availability
+// client api that the show-ha command calls.
+type HAStatusClient interface {
+	Close() error
+	HAStatus() (params.HAStatusResult, error)
+}
+
+func (c *showHACommand) getHAClient() (HAStatusClient, error) {
+	if c.haClient != nil {
+		return c.haClient, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get API connection")
+	}
+	return highavailability.NewClient(root), nil
+}
+
+type haStatus struct {
+	Members  []haMemberStatus `json:"members" yaml:"members"`
+	Problems []string         `json:"problems,omitempty" yaml:"problems,omitempty"`
+}
+
+type haMemberStatus struct {
+	Machine       string   `json:"machine,omitempty" yaml:"machine,omitempty"`
+	ReplicaSetId  int      `json:"replicaset-id" yaml:"replicaset-id"`
+	Address       string   `json:"address,omitempty" yaml:"address,omitempty"`
+	WantsVote     bool     `json:"wants-vote" yaml:"wants-vote"`
+	HasVote       bool     `json:"has-vote" yaml:"has-vote"`
+	Voting        bool     `json:"voting" yaml:"voting"`
+	State         string   `json:"state,omitempty" yaml:"state,omitempty"`
+	Healthy       bool     `json:"healthy" yaml:"healthy"`
+	Lag           string   `json:"lag,omitempty" yaml:"lag,omitempty"`
+	LastHeartbeat string   `json:"last-heartbeat,omitempty" yaml:"last-heartbeat,omitempty"`
+	Message       string   `json:"message,omitempty" yaml:"message,omitempty"`
+	Problems      []string `json:"problems,omitempty" yaml:"problems,omitempty"`
+}
+
+// Run connects to the controller model and shows the status of
+// its replica set.
+func (c *showHACommand) Run(ctx *cmd.Context) error {
+	haClient, err := c.getHAClient()
+	if err != nil {
+		return err
+	}
+	defer haClient.Close()
+	result, err := haClient.HAStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	status := haStatus{
+		Members:  make([]haMemberStatus, len(result.Members)),
+		Problems: result.Problems,
+	}
+	for i, m := range result.Members {
+		member := haMemberStatus{
+			ReplicaSetId: m.ReplicaSetId,
+			Address:      m.Address,
+			WantsVote:    m.WantsVote,
+			HasVote:      m.HasVote,
+			Voting:       m.Voting,
+			State:        m.State,
+			Healthy:      m.Healthy,
+			Message:      m.Message,
+			Problems:     m.Problems,
+		}
+		if m.MachineTag != "" {
+			tag, err := names.ParseMachineTag(m.MachineTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			member.Machine = tag.Id()
+		}
+		if m.State != "" {
+			member.Lag = m.Lag.String()
+		}
+		if m.LastHeartbeat != nil {
+			member.LastHeartbeat = common.FormatTime(m.LastHeartbeat, c.isoTime)
+		}
+		status.Members[i] = member
+	}
+	return c.out.Write(ctx, status)
+}
+
+// formatHAStatusTabular returns a tabular summary of the replica
+// set, followed by any problems found.
+func formatHAStatusTabular(value interface{}) ([]byte, error) {
+	status, ok := value.(haStatus)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", status, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "MACHINE\tMEMBER\tADDRESS\tSTATE\tHEALTHY\tVOTING\tHAS-VOTE\tWANTS-VOTE\tLAG\tLAST-HEARTBEAT")
+	var problems []string
+	for _, m := range status.Members {
+		machine := m.Machine
+		if machine == "" {
+			machine = "-"
+		}
+		member := "-"
+		if m.ReplicaSetId >= 0 {
+			member = fmt.Sprint(m.ReplicaSetId)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%v\t%v\t%v\t%v\t%s\t%s\n",
+			machine, member, m.Address, m.State, m.Healthy,
+			m.Voting, m.HasVote, m.WantsVote, m.Lag, m.LastHeartbeat,
+		)
+		name := "machine " + m.Machine
+		if m.Machine == "" {
+			name = "member " + member
+		}
+		for _, p := range m.Problems {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, p))
+		}
+	}
+	tw.Flush()
+	problems = append(problems, status.Problems...)
+	if len(problems) > 0 {
+		fmt.Fprintln(&out)
+		fmt.Fprintln(&out, "Problems:")
+		for _, p := range problems {
+			fmt.Fprintf(&out, "  %s\n", p)
+		}
+	}
+	return out.Bytes(), nil
+}
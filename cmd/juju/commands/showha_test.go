@@ -0,0 +1,151 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type ShowHASuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  *fakeHAStatusClient
+	store *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&ShowHASuite{})
+
+func (s *ShowHASuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = jujuclienttesting.NewMemStore()
+	err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "eeeeeeee-0bad-400d-8000-4b1d0d06f00d",
+		CACert:         "cert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = modelcmd.WriteCurrentController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("ctrl", "admin@local", jujuclient.AccountDetails{
+		User: "admin@local",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCurrentAccount("ctrl", "admin@local")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel("ctrl", "admin@local", "controller", jujuclient.ModelDetails{
+		ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	heartbeat := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.fake = &fakeHAStatusClient{
+		result: params.HAStatusResult{
+			Members: []params.HAMemberStatus{{
+				MachineTag:   "machine-0",
+				ReplicaSetId: 1,
+				Address:      "10.0.0.1:37017",
+				WantsVote:    true,
+				HasVote:      true,
+				Voting:       true,
+				State:        "PRIMARY",
+				Healthy:      true,
+			}, {
+				MachineTag:    "machine-1",
+				ReplicaSetId:  2,
+				Address:       "10.0.0.2:37017",
+				WantsVote:     true,
+				State:         "RECOVERING",
+				Healthy:       true,
+				Lag:           3 * time.Second,
+				LastHeartbeat: &heartbeat,
+				Problems:      []string{"wants vote but member is not ready (RECOVERING)"},
+			}},
+			Problems: []string{"even number of voting members (2)"},
+		},
+	}
+}
+
+type fakeHAStatusClient struct {
+	result params.HAStatusResult
+	err    error
+}
+
+func (f *fakeHAStatusClient) Close() error {
+	return nil
+}
+
+func (f *fakeHAStatusClient) HAStatus() (params.HAStatusResult, error) {
+	return f.result, f.err
+}
+
+func (s *ShowHASuite) runShowHA(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &showHACommand{haClient: s.fake}
+	command.SetClientStore(s.store)
+	return testing.RunCommand(c, modelcmd.Wrap(command), append([]string{"-m", "controller"}, args...)...)
+}
+
+func (s *ShowHASuite) TestTabular(c *gc.C) {
+	ctx, err := s.runShowHA(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"MACHINE MEMBER ADDRESS        STATE      HEALTHY VOTING HAS-VOTE WANTS-VOTE LAG LAST-HEARTBEAT\n"+
+		"0       1      10.0.0.1:37017 PRIMARY    true    true   true     true       0s  \n"+
+		"1       2      10.0.0.2:37017 RECOVERING true    false  false    true       3s  2016-05-01 12:00:00Z\n"+
+		"\n"+
+		"Problems:\n"+
+		"  machine 1: wants vote but member is not ready (RECOVERING)\n"+
+		"  even number of voting members (2)\n",
+	)
+}
+
+func (s *ShowHASuite) TestYAML(c *gc.C) {
+	ctx, err := s.runShowHA(c, "--format", "yaml", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+members:
+- machine: "0"
+  replicaset-id: 1
+  address: 10.0.0.1:37017
+  wants-vote: true
+  has-vote: true
+  voting: true
+  state: PRIMARY
+  healthy: true
+  lag: 0s
+- machine: "1"
+  replicaset-id: 2
+  address: 10.0.0.2:37017
+  wants-vote: true
+  has-vote: false
+  voting: false
+  state: RECOVERING
+  healthy: true
+  lag: 3s
+  last-heartbeat: 2016-05-01 12:00:00Z
+  problems:
+  - wants vote but member is not ready (RECOVERING)
+problems:
+- even number of voting members (2)
+`[1:])
+}
+
+func (s *ShowHASuite) TestError(c *gc.C) {
+	s.fake.err = errors.New("unsupported with hosted models")
+	_, err := s.runShowHA(c)
+	c.Assert(err, gc.ErrorMatches, "unsupported with hosted models")
+}
+
+func (s *ShowHASuite) TestTooManyArgs(c *gc.C) {
+	_, err := s.runShowHA(c, "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/series"
 	"github.com/juju/version"
 	"launchpad.net/gnuflag"
@@ -52,9 +53,21 @@ If a failed upgrade has been resolved, '--reset-previous-upgrade' can be
 used to allow the upgrade to proceed.
 Backups are recommended prior to upgrading.
 
+By default, every agent in the model is upgraded at once. With '--canaries',
+the upgrade is staged: the controllers and the named canary machines are
+upgraded first, and the model's other machines are then upgraded
+'--batch-size' at a time, each batch starting once every machine upgraded
+so far has completed its upgrade steps and its agent is running. If a
+machine fails to upgrade, or a batch has not upgraded within 30 minutes, the
+upgrade is paused; once the problem has been resolved, it can be continued
+with '--resume'. The progress of a staged
+upgrade is shown by ` + "`juju status`" + `.
+
 Examples:
     juju upgrade-juju --dry-run
     juju upgrade-juju --version 2.0.1
+    juju upgrade-juju --canaries 3,7 --batch-size 10
+    juju upgrade-juju --resume
     
 See also: 
     sync-tools`
@@ -76,6 +89,15 @@ type upgradeJujuCommand struct {
 	ResetPrevious bool
 	AssumeYes     bool
 
+	// Canaries holds the ids of the machines to upgrade first in a
+	// staged upgrade.
+	Canaries []string
+	// BatchSize is the number of machines upgraded at a time once
+	// the canaries have upgraded.
+	BatchSize int
+	// Resume continues a paused staged upgrade.
+	Resume bool
+
 	// minMajorUpgradeVersion maps known major numbers to
 	// the minimum version that can be upgraded to that
 	// major version.  For example, users must be running
@@ -98,9 +120,26 @@ func (c *upgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.ResetPrevious, "reset-previous-upgrade", false, "Clear the previous (incomplete) upgrade status (use with care)")
 	f.BoolVar(&c.AssumeYes, "y", false, "Answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
+	f.Var(cmd.NewStringsValue(nil, &c.Canaries), "canaries", "Stage the upgrade, upgrading these machines first")
+	f.IntVar(&c.BatchSize, "batch-size", 1, "The number of machines to upgrade at a time after the canaries")
+	f.BoolVar(&c.Resume, "resume", false, "Continue a staged upgrade that was paused by a failure")
 }
 
 func (c *upgradeJujuCommand) Init(args []string) error {
+	if c.Resume {
+		if c.vers != "" || c.UploadTools || c.DryRun || c.ResetPrevious || len(c.Canaries) > 0 {
+			return errors.New("--resume cannot be combined with other options")
+		}
+		return cmd.CheckEmpty(args)
+	}
+	for _, id := range c.Canaries {
+		if !names.IsValidMachine(id) {
+			return errors.Errorf("invalid canary machine id %q", id)
+		}
+	}
+	if c.BatchSize < 1 {
+		return errors.Errorf("--batch-size must be at least 1, got %d", c.BatchSize)
+	}
 	if c.vers != "" {
 		vers, err := version.Parse(c.vers)
 		if err != nil {
@@ -168,7 +207,9 @@ type upgradeJujuAPI interface {
 	FindTools(majorVersion, minorVersion int, series, arch string) (result params.FindToolsResult, err error)
 	UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (coretools.List, error)
 	AbortCurrentUpgrade() error
-	SetModelAgentVersion(version version.Number) error
+	SetModelAgentVersion(version version.Number) (params.AgentVersionUpgradeResult, error)
+	StartUpgradeRollout(version version.Number, canaries []string, batchSize int) (params.AgentVersionUpgradeResult, error)
+	ResumeUpgradeRollout() error
 	Close() error
 }
 
@@ -195,6 +236,14 @@ func (c *upgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 		}
 	}()
 
+	if c.Resume {
+		if err := client.ResumeUpgradeRollout(); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		ctx.Infof("resumed staged upgrade")
+		return nil
+	}
+
 	// Determine the version to upgrade to, uploading tools if necessary.
 	attrs, err := client.ModelGet()
 	if err != nil {
@@ -303,7 +352,8 @@ func (c *upgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
-		if err := client.SetModelAgentVersion(context.chosen); err != nil {
+		result, err := c.setAgentVersion(client, context.chosen)
+		if err != nil {
 			if params.IsCodeUpgradeInProgress(err) {
 				return errors.Errorf("%s\n\n"+
 					"Please wait for the upgrade to complete or if there was a problem with\n"+
@@ -314,11 +364,27 @@ func (c *upgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 				return block.ProcessBlockedError(err, block.BlockChange)
 			}
 		}
-		logger.Infof("started upgrade to %s", context.chosen)
+		if len(c.Canaries) > 0 {
+			ctx.Infof("started staged upgrade to %s, with canary machines %s", context.chosen, strings.Join(c.Canaries, ", "))
+		} else {
+			logger.Infof("started upgrade to %s", context.chosen)
+		}
+		if result.NoRollback != "" {
+			fmt.Fprintf(ctx.Stderr, "WARNING: the upgrade to %s cannot be rolled back: %s\n", context.chosen, result.NoRollback)
+		}
 	}
 	return nil
 }
 
+// setAgentVersion sets the model's agent version, staging the upgrade
+// if canary machines were specified.
+func (c *upgradeJujuCommand) setAgentVersion(client upgradeJujuAPI, vers version.Number) (params.AgentVersionUpgradeResult, error) {
+	if len(c.Canaries) == 0 {
+		return client.SetModelAgentVersion(vers)
+	}
+	return client.StartUpgradeRollout(vers, c.Canaries, c.BatchSize)
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
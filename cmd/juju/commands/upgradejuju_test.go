@@ -7,6 +7,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
@@ -756,6 +757,22 @@ func (s *UpgradeJujuSuite) TestUpgradeInProgress(c *gc.C) {
 	)
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeNoRollback(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.noRollback = "cannot back up the controller: disk full"
+	fakeAPI.patch(s)
+	cmd := &upgradeJujuCommand{}
+	err := coretesting.InitCommand(modelcmd.Wrap(cmd), []string{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := coretesting.Context(c)
+	err = modelcmd.Wrap(cmd).Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), jc.Contains,
+		"WARNING: the upgrade to "+fakeAPI.nextVersion.Number.String()+
+			" cannot be rolled back: cannot back up the controller: disk full\n")
+}
+
 func (s *UpgradeJujuSuite) TestBlockUpgradeInProgress(c *gc.C) {
 	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
 	fakeAPI.setVersionErr = common.OperationBlockedError("the operation has been blocked")
@@ -828,6 +845,54 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestStagedUpgradeInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--canaries", "1,foo"},
+		err:  `invalid canary machine id "foo"`,
+	}, {
+		args: []string{"--canaries", "1", "--batch-size", "0"},
+		err:  "--batch-size must be at least 1, got 0",
+	}, {
+		args: []string{"--resume", "--version", "2.0.1"},
+		err:  "--resume cannot be combined with other options",
+	}, {
+		args: []string{"--resume", "--canaries", "1"},
+		err:  "--resume cannot be combined with other options",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := coretesting.InitCommand(modelcmd.Wrap(&upgradeJujuCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+
+	ctx, err := coretesting.RunCommand(c, newUpgradeJujuCommand(nil), "--canaries", "3,7", "--batch-size", "10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(fakeAPI.rolloutCalledWith, jc.DeepEquals, []interface{}{
+		fakeAPI.nextVersion.Number, []string{"3", "7"}, 10,
+	})
+	c.Assert(coretesting.Stderr(ctx), jc.Contains,
+		fmt.Sprintf("started staged upgrade to %s, with canary machines 3, 7\n", fakeAPI.nextVersion.Number))
+}
+
+func (s *UpgradeJujuSuite) TestResumeStagedUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+
+	ctx, err := coretesting.RunCommand(c, newUpgradeJujuCommand(nil), "--resume")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.resumeRolloutCalled, jc.IsTrue)
+	c.Assert(fakeAPI.findToolsCalled, jc.IsFalse)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "resumed staged upgrade\n")
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Binary{
 		Number: jujuversion.Current,
@@ -847,18 +912,24 @@ type fakeUpgradeJujuAPI struct {
 	st                        *state.State
 	nextVersion               version.Binary
 	setVersionErr             error
+	noRollback                string
 	abortCurrentUpgradeCalled bool
 	setVersionCalledWith      version.Number
 	tools                     []string
 	findToolsCalled           bool
+	rolloutCalledWith         []interface{}
+	resumeRolloutCalled       bool
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
 	a.setVersionErr = nil
+	a.noRollback = ""
 	a.abortCurrentUpgradeCalled = false
 	a.setVersionCalledWith = version.Number{}
 	a.tools = []string{}
 	a.findToolsCalled = false
+	a.rolloutCalledWith = nil
+	a.resumeRolloutCalled = false
 }
 
 func (a *fakeUpgradeJujuAPI) patch(s *UpgradeJujuSuite) {
@@ -902,9 +973,19 @@ func (a *fakeUpgradeJujuAPI) AbortCurrentUpgrade() error {
 	return nil
 }
 
-func (a *fakeUpgradeJujuAPI) SetModelAgentVersion(v version.Number) error {
+func (a *fakeUpgradeJujuAPI) SetModelAgentVersion(v version.Number) (params.AgentVersionUpgradeResult, error) {
 	a.setVersionCalledWith = v
-	return a.setVersionErr
+	return params.AgentVersionUpgradeResult{NoRollback: a.noRollback}, a.setVersionErr
+}
+
+func (a *fakeUpgradeJujuAPI) StartUpgradeRollout(v version.Number, canaries []string, batchSize int) (params.AgentVersionUpgradeResult, error) {
+	a.rolloutCalledWith = []interface{}{v, canaries, batchSize}
+	return params.AgentVersionUpgradeResult{NoRollback: a.noRollback}, a.setVersionErr
+}
+
+func (a *fakeUpgradeJujuAPI) ResumeUpgradeRollout() error {
+	a.resumeRolloutCalled = true
+	return nil
 }
 
 func (a *fakeUpgradeJujuAPI) Close() error {
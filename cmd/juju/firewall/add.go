@@ -0,0 +1,131 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network"
+)
+
+// AddFirewallRuleAPI defines the API methods that the add-firewall-rule
+// command uses.
+type AddFirewallRuleAPI interface {
+	Close() error
+	AddFirewallRule(network.FirewallRule) error
+}
+
+const addFirewallRuleCommandDoc = `
+Adds a model-level firewall rule, allowing traffic from machines to a
+destination on the specified ports.
+
+The destination is either a space, or a comma-separated list of CIDRs.
+If a source space is specified with --from, the rule only applies to
+machines with an address in that space; otherwise it applies to all
+machines in the model.
+
+Rules are allow-lists: once any rule covers a destination for a
+machine, traffic from that machine to the destination is limited to
+the ports allowed by the rules covering it. Traffic to destinations
+not covered by any rule is unaffected. Ports are specified as
+<port>[/<protocol>] or <from>-<to>[/<protocol>], where the protocol
+defaults to tcp.
+
+Where the provider supports it, rules are enforced with the provider's
+firewall, such as ec2 VPC or openstack neutron security groups, when the
+model uses the "instance" firewall mode. Otherwise they are enforced by
+the agent on each machine, using iptables.
+
+Examples:
+
+    juju add-firewall-rule dmz-to-app --from dmz --to app 443
+    juju add-firewall-rule dns --to 0.0.0.0/0 53/udp 53/tcp
+
+See also:
+    list-firewall-rules
+    remove-firewall-rule
+`
+
+// NewAddFirewallRuleCommand returns a command that adds
+// a model-level firewall rule.
+func NewAddFirewallRuleCommand() cmd.Command {
+	cmd := &addFirewallRuleCommand{}
+	cmd.newAPIFunc = func() (AddFirewallRuleAPI, error) {
+		return cmd.NewFirewallRulesAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// addFirewallRuleCommand adds a model-level firewall rule.
+type addFirewallRuleCommand struct {
+	FirewallCommandBase
+	newAPIFunc func() (AddFirewallRuleAPI, error)
+
+	from string
+	to   string
+	rule network.FirewallRule
+}
+
+// Info implements Command.Info.
+func (c *addFirewallRuleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-firewall-rule",
+		Args:    "<name> --to <space>|<cidr>[,<cidr>...] <port>[/<protocol>] ...",
+		Purpose: "Add a model-level firewall rule",
+		Doc:     addFirewallRuleCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.FirewallCommandBase.SetFlags(f)
+	f.StringVar(&c.from, "from", "", "the space that traffic is sent from")
+	f.StringVar(&c.to, "to", "", "the space or CIDRs that traffic is sent to")
+}
+
+// Init implements Command.Init.
+func (c *addFirewallRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no firewall rule name specified")
+	}
+	if c.to == "" {
+		return errors.New("no destination specified")
+	}
+	if len(args) == 1 {
+		return errors.New("no ports specified")
+	}
+	c.rule = network.FirewallRule{
+		Name:        args[0],
+		SourceSpace: c.from,
+	}
+	if _, _, err := net.ParseCIDR(strings.Split(c.to, ",")[0]); err == nil {
+		c.rule.DestinationCIDRs = strings.Split(c.to, ",")
+	} else {
+		c.rule.DestinationSpace = c.to
+	}
+	for _, arg := range args[1:] {
+		portRange, err := network.ParsePortRange(arg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.rule.Ports = append(c.rule.Ports, portRange)
+	}
+	return c.rule.Validate()
+}
+
+// Run implements Command.Run.
+func (c *addFirewallRuleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.AddFirewallRule(c.rule)
+}
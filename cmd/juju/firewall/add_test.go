@@ -0,0 +1,109 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type addSuite struct {
+	firewallSuite
+	mockAPI *mockAddAPI
+}
+
+var _ = gc.Suite(&addSuite{})
+
+func (s *addSuite) SetUpTest(c *gc.C) {
+	s.firewallSuite.SetUpTest(c)
+	s.mockAPI = &mockAddAPI{}
+}
+
+func (s *addSuite) runAdd(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, firewall.NewAddFirewallRuleCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *addSuite) TestAddSpaceRule(c *gc.C) {
+	_, err := s.runAdd(c, "dmz-to-app", "--from", "dmz", "--to", "app", "443", "8000-8080/tcp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.rules, jc.DeepEquals, []network.FirewallRule{{
+		Name:             "dmz-to-app",
+		SourceSpace:      "dmz",
+		DestinationSpace: "app",
+		Ports: []network.PortRange{
+			network.MustParsePortRange("443/tcp"),
+			network.MustParsePortRange("8000-8080/tcp"),
+		},
+	}})
+}
+
+func (s *addSuite) TestAddCIDRRule(c *gc.C) {
+	_, err := s.runAdd(c, "dns", "--to", "10.0.0.0/8,192.168.0.0/16", "53/udp")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.rules, jc.DeepEquals, []network.FirewallRule{{
+		Name:             "dns",
+		DestinationCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"},
+		Ports:            []network.PortRange{network.MustParsePortRange("53/udp")},
+	}})
+}
+
+func (s *addSuite) TestAddInvalidArgs(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		expect string
+	}{{
+		args:   []string{},
+		expect: "no firewall rule name specified",
+	}, {
+		args:   []string{"dns", "53"},
+		expect: "no destination specified",
+	}, {
+		args:   []string{"dns", "--to", "app"},
+		expect: "no ports specified",
+	}, {
+		args:   []string{"dns", "--to", "app", "fifty"},
+		expect: `invalid port "fifty": .*`,
+	}, {
+		args:   []string{"dns", "--to", "10.0.0.0/8,nope", "53"},
+		expect: `destination CIDR "nope" not valid`,
+	}, {
+		args:   []string{"same", "--from", "app", "--to", "app", "53"},
+		expect: `firewall rule "same" with the same source and destination space not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runAdd(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+	c.Assert(s.mockAPI.rules, gc.HasLen, 0)
+}
+
+func (s *addSuite) TestAddError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.runAdd(c, "dns", "--to", "0.0.0.0/0", "53/udp")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockAddAPI struct {
+	rules []network.FirewallRule
+	err   error
+}
+
+func (*mockAddAPI) Close() error {
+	return nil
+}
+
+func (m *mockAddAPI) AddFirewallRule(rule network.FirewallRule) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.rules = append(m.rules, rule)
+	return nil
+}
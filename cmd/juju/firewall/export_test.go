@@ -0,0 +1,35 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+func NewAddFirewallRuleCommandForTest(api AddFirewallRuleAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &addFirewallRuleCommand{newAPIFunc: func() (AddFirewallRuleAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewRemoveFirewallRuleCommandForTest(api RemoveFirewallRuleAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeFirewallRuleCommand{newAPIFunc: func() (RemoveFirewallRuleAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewListFirewallRulesCommandForTest(api ListFirewallRulesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listFirewallRulesCommand{newAPIFunc: func() (ListFirewallRulesAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
@@ -0,0 +1,27 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package firewall contains the commands used to manage
+// model-level firewall rules.
+package firewall
+
+import (
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// FirewallCommandBase is the base type embedded into all
+// firewall rule subcommands.
+type FirewallCommandBase struct {
+	modelcmd.ModelCommandBase
+}
+
+// NewFirewallRulesAPI returns a firewall rules api for the root api
+// endpoint that the model command returns.
+func (c *FirewallCommandBase) NewFirewallRulesAPI() (*firewallrules.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return firewallrules.NewClient(root), nil
+}
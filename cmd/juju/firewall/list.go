@@ -0,0 +1,156 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network"
+)
+
+// ListFirewallRulesAPI defines the API methods that the
+// list-firewall-rules command uses.
+type ListFirewallRulesAPI interface {
+	Close() error
+	ListFirewallRules() ([]network.FirewallRule, error)
+}
+
+const listFirewallRulesCommandDoc = `
+Lists the model-level firewall rules.
+
+See also:
+    add-firewall-rule
+    remove-firewall-rule
+`
+
+// NewListFirewallRulesCommand returns a command that lists
+// the model-level firewall rules.
+func NewListFirewallRulesCommand() cmd.Command {
+	cmd := &listFirewallRulesCommand{}
+	cmd.newAPIFunc = func() (ListFirewallRulesAPI, error) {
+		return cmd.NewFirewallRulesAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// listFirewallRulesCommand lists the model-level firewall rules.
+type listFirewallRulesCommand struct {
+	FirewallCommandBase
+	newAPIFunc func() (ListFirewallRulesAPI, error)
+	out        cmd.Output
+}
+
+// FirewallRule is the serialization format of a firewall rule.
+type FirewallRule struct {
+	From  string   `yaml:"from,omitempty" json:"from,omitempty"`
+	To    []string `yaml:"to" json:"to"`
+	Ports []string `yaml:"ports" json:"ports"`
+}
+
+// Info implements Command.Info.
+func (c *listFirewallRulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-firewall-rules",
+		Purpose: "List model-level firewall rules",
+		Doc:     listFirewallRulesCommandDoc,
+		Aliases: []string{"firewall-rules"},
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listFirewallRulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.FirewallCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatFirewallRulesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listFirewallRulesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	rules, err := api.ListFirewallRules()
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		ctx.Infof("No firewall rules to display.")
+		return nil
+	}
+	output := make(map[string]FirewallRule)
+	for _, rule := range rules {
+		output[rule.Name] = formatFirewallRule(rule)
+	}
+	return c.out.Write(ctx, output)
+}
+
+func formatFirewallRule(rule network.FirewallRule) FirewallRule {
+	out := FirewallRule{
+		From: rule.SourceSpace,
+		To:   rule.DestinationCIDRs,
+	}
+	if rule.DestinationSpace != "" {
+		out.To = []string{rule.DestinationSpace}
+	}
+	for _, portRange := range rule.Ports {
+		out.Ports = append(out.Ports, portRange.String())
+	}
+	return out
+}
+
+// formatFirewallRulesTabular returns a tabular summary of firewall rules.
+func formatFirewallRulesTabular(value interface{}) ([]byte, error) {
+	rules, ok := value.(map[string]FirewallRule)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", rules, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("NAME", "FROM", "TO", "PORTS")
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rule := rules[name]
+		from := rule.From
+		if from == "" {
+			from = "*"
+		}
+		print(name, from, strings.Join(rule.To, ","), strings.Join(rule.Ports, ","))
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type listSuite struct {
+	firewallSuite
+	mockAPI *mockListAPI
+}
+
+var _ = gc.Suite(&listSuite{})
+
+func (s *listSuite) SetUpTest(c *gc.C) {
+	s.firewallSuite.SetUpTest(c)
+	s.mockAPI = &mockListAPI{
+		rules: []network.FirewallRule{{
+			Name:             "dmz-to-app",
+			SourceSpace:      "dmz",
+			DestinationSpace: "app",
+			Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+		}, {
+			Name:             "dns",
+			DestinationCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"},
+			Ports: []network.PortRange{
+				network.MustParsePortRange("53/udp"),
+				network.MustParsePortRange("53/tcp"),
+			},
+		}},
+	}
+}
+
+func (s *listSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, firewall.NewListFirewallRulesCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *listSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+NAME        FROM  TO                         PORTS
+dmz-to-app  dmz   app                        443/tcp
+dns         *     10.0.0.0/8,192.168.0.0/16  53/udp,53/tcp
+
+`[1:])
+}
+
+func (s *listSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+dmz-to-app:
+  from: dmz
+  to:
+  - app
+  ports:
+  - 443/tcp
+dns:
+  to:
+  - 10.0.0.0/8
+  - 192.168.0.0/16
+  ports:
+  - 53/udp
+  - 53/tcp
+`[1:])
+}
+
+func (s *listSuite) TestListEmpty(c *gc.C) {
+	s.mockAPI.rules = nil
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No firewall rules to display.\n")
+}
+
+type mockListAPI struct {
+	rules []network.FirewallRule
+}
+
+func (*mockListAPI) Close() error {
+	return nil
+}
+
+func (m *mockListAPI) ListFirewallRules() ([]network.FirewallRule, error) {
+	return m.rules, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// RemoveFirewallRuleAPI defines the API methods that the
+// remove-firewall-rule command uses.
+type RemoveFirewallRuleAPI interface {
+	Close() error
+	RemoveFirewallRule(name string) error
+}
+
+const removeFirewallRuleCommandDoc = `
+Removes a model-level firewall rule. Traffic to the rule's destination
+is no longer restricted by it once every machine agent has applied the
+change.
+
+Examples:
+
+    juju remove-firewall-rule dmz-to-app
+
+See also:
+    add-firewall-rule
+    list-firewall-rules
+`
+
+// NewRemoveFirewallRuleCommand returns a command that removes
+// a model-level firewall rule.
+func NewRemoveFirewallRuleCommand() cmd.Command {
+	cmd := &removeFirewallRuleCommand{}
+	cmd.newAPIFunc = func() (RemoveFirewallRuleAPI, error) {
+		return cmd.NewFirewallRulesAPI()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// removeFirewallRuleCommand removes a model-level firewall rule.
+type removeFirewallRuleCommand struct {
+	FirewallCommandBase
+	newAPIFunc func() (RemoveFirewallRuleAPI, error)
+
+	name string
+}
+
+// Info implements Command.Info.
+func (c *removeFirewallRuleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-firewall-rule",
+		Args:    "<name>",
+		Purpose: "Remove a model-level firewall rule",
+		Doc:     removeFirewallRuleCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *removeFirewallRuleCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no firewall rule name specified")
+	case 1:
+		c.name = args[0]
+		return nil
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeFirewallRuleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return api.RemoveFirewallRule(c.name)
+}
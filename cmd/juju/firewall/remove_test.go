@@ -0,0 +1,59 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/testing"
+)
+
+type removeSuite struct {
+	firewallSuite
+	mockAPI *mockRemoveAPI
+}
+
+var _ = gc.Suite(&removeSuite{})
+
+func (s *removeSuite) SetUpTest(c *gc.C) {
+	s.firewallSuite.SetUpTest(c)
+	s.mockAPI = &mockRemoveAPI{}
+}
+
+func (s *removeSuite) runRemove(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, firewall.NewRemoveFirewallRuleCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *removeSuite) TestRemove(c *gc.C) {
+	_, err := s.runRemove(c, "dmz-to-app")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.removed, jc.DeepEquals, []string{"dmz-to-app"})
+}
+
+func (s *removeSuite) TestRemoveNoName(c *gc.C) {
+	_, err := s.runRemove(c)
+	c.Assert(err, gc.ErrorMatches, "no firewall rule name specified")
+}
+
+func (s *removeSuite) TestRemoveTooManyArgs(c *gc.C) {
+	_, err := s.runRemove(c, "dmz-to-app", "dns")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["dns"\]`)
+}
+
+type mockRemoveAPI struct {
+	removed []string
+}
+
+func (*mockRemoveAPI) Close() error {
+	return nil
+}
+
+func (m *mockRemoveAPI) RemoveFirewallRule(name string) error {
+	m.removed = append(m.removed, name)
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+func NewListSecretsCommandForTest(api ListSecretsAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listSecretsCommand{newAPIFunc: func() (ListSecretsAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+func NewShowSecretCommandForTest(api ShowSecretAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &showSecretCommand{newAPIFunc: func() (ShowSecretAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
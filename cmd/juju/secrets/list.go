@@ -0,0 +1,161 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// ListSecretsAPI defines the API methods that the list-secrets
+// command uses.
+type ListSecretsAPI interface {
+	Close() error
+	ListSecrets() ([]params.SecretDetails, error)
+}
+
+const listSecretsCommandDoc = `
+Lists the secrets stored by the charms deployed in the model, with
+the service that owns each one and the units and relations it has
+been granted to. Secret values are never displayed; only the names
+of their keys are shown.
+
+Secrets are added and shared by charms, using the secret-add,
+secret-grant and secret-revoke hook tools.
+
+See also:
+    show-secret
+`
+
+// NewListSecretsCommand returns a command that lists the secrets
+// stored in the model.
+func NewListSecretsCommand() cmd.Command {
+	cmd := &listSecretsCommand{}
+	cmd.newAPIFunc = func() (ListSecretsAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// listSecretsCommand lists the secrets stored in the model.
+type listSecretsCommand struct {
+	SecretsCommandBase
+	newAPIFunc func() (ListSecretsAPI, error)
+	out        cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *listSecretsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-secrets",
+		Purpose: "List the secrets stored by charms in the model.",
+		Doc:     listSecretsCommandDoc,
+		Aliases: []string{"secrets"},
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listSecretsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SecretsCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSecretsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listSecretsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *listSecretsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	secrets, err := api.ListSecrets()
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		ctx.Infof("No secrets to display.")
+		return nil
+	}
+	output := make(map[string]SecretInfo)
+	for _, secret := range secrets {
+		output[secret.Id] = formatSecret(secret)
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatSecretsTabular returns a tabular summary of secrets.
+func formatSecretsTabular(value interface{}) ([]byte, error) {
+	secrets, ok := value.(map[string]SecretInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", secrets, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("ID", "OWNER", "REVISION", "KEYS", "ROTATE", "GRANTS", "DESCRIPTION")
+	ids := make([]string, 0, len(secrets))
+	for id := range secrets {
+		ids = append(ids, id)
+	}
+	sort.Sort(secretIds(ids))
+	for _, id := range ids {
+		secret := secrets[id]
+		rotate := secret.RotateInterval
+		if rotate == "" {
+			rotate = "-"
+		}
+		print(
+			id,
+			secret.Owner,
+			strconv.Itoa(secret.Revision),
+			strings.Join(secret.Keys, ","),
+			rotate,
+			strconv.Itoa(len(secret.Grants)),
+			secret.Description,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+// secretIds sorts secret ids numerically.
+type secretIds []string
+
+func (s secretIds) Len() int      { return len(s) }
+func (s secretIds) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s secretIds) Less(i, j int) bool {
+	if len(s[i]) != len(s[j]) {
+		return len(s[i]) < len(s[j])
+	}
+	return s[i] < s[j]
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/secrets"
+	"github.com/juju/juju/testing"
+)
+
+type listSuite struct {
+	secretsSuite
+	mockAPI *mockSecretsAPI
+}
+
+var _ = gc.Suite(&listSuite{})
+
+var secretTime = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *listSuite) SetUpTest(c *gc.C) {
+	s.secretsSuite.SetUpTest(c)
+	s.mockAPI = newMockSecretsAPI()
+}
+
+func (s *listSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, secrets.NewListSecretsCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *listSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+ID  OWNER      REVISION  KEYS           ROTATE  GRANTS  DESCRIPTION
+2   mysql      2         password,user  1h0m0s  2       root credentials
+10  wordpress  1         token          -       0       
+
+`[1:])
+}
+
+func (s *listSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+"2":
+  owner: mysql
+  description: root credentials
+  revision: 2
+  keys:
+  - password
+  - user
+  rotate-interval: 1h0m0s
+  next-rotation: 2016-06-01T13:01:00Z
+  grants:
+  - unit wordpress/0
+  - relation wordpress:db mysql:server
+  created: 2016-06-01T12:00:00Z
+  updated: 2016-06-01T12:01:00Z
+"10":
+  owner: wordpress
+  revision: 1
+  keys:
+  - token
+  created: 2016-06-01T12:00:00Z
+  updated: 2016-06-01T12:00:00Z
+`[1:])
+}
+
+func (s *listSuite) TestListEmpty(c *gc.C) {
+	s.mockAPI.secrets = nil
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No secrets to display.\n")
+}
+
+func (s *listSuite) TestListError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.runList(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *listSuite) TestListArgs(c *gc.C) {
+	_, err := s.runList(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+type mockSecretsAPI struct {
+	secrets []params.SecretDetails
+	err     error
+}
+
+func newMockSecretsAPI() *mockSecretsAPI {
+	next := secretTime.Add(time.Hour + time.Minute)
+	return &mockSecretsAPI{
+		secrets: []params.SecretDetails{{
+			Id:       "10",
+			OwnerTag: "service-wordpress",
+			Revision: 1,
+			Keys:     []string{"token"},
+			Created:  secretTime,
+			Updated:  secretTime,
+			Revisions: []params.SecretRevision{
+				{Revision: 1, Keys: []string{"token"}, Created: secretTime},
+			},
+		}, {
+			Id:             "2",
+			OwnerTag:       "service-mysql",
+			Description:    "root credentials",
+			RotateInterval: time.Hour,
+			NextRotation:   &next,
+			Revision:       2,
+			Keys:           []string{"password", "user"},
+			Grants:         []string{"unit-wordpress-0", "relation-wordpress.db#mysql.server"},
+			Created:        secretTime,
+			Updated:        secretTime.Add(time.Minute),
+		}},
+	}
+}
+
+func (m *mockSecretsAPI) Close() error {
+	return nil
+}
+
+func (m *mockSecretsAPI) ListSecrets() ([]params.SecretDetails, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	// Revision histories are only returned by ShowSecret.
+	secrets := make([]params.SecretDetails, len(m.secrets))
+	for i, secret := range m.secrets {
+		secret.Revisions = nil
+		secrets[i] = secret
+	}
+	return secrets, nil
+}
+
+func (m *mockSecretsAPI) ShowSecret(id string) (*params.SecretDetails, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, secret := range m.secrets {
+		if secret.Id == id {
+			return &secret, nil
+		}
+	}
+	return nil, errors.NotFoundf("secret %q", id)
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets contains the commands used to inspect the secrets
+// stored by charms in a model. Secret values are never displayed.
+package secrets
+
+import (
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// SecretsCommandBase is the base type embedded into all secrets
+// subcommands.
+type SecretsCommandBase struct {
+	modelcmd.ModelCommandBase
+}
+
+// SecretInfo is the serialization format of a secret.
+type SecretInfo struct {
+	Owner          string         `yaml:"owner" json:"owner"`
+	Description    string         `yaml:"description,omitempty" json:"description,omitempty"`
+	Revision       int            `yaml:"revision" json:"revision"`
+	Keys           []string       `yaml:"keys" json:"keys"`
+	RotateInterval string         `yaml:"rotate-interval,omitempty" json:"rotate-interval,omitempty"`
+	NextRotation   *time.Time     `yaml:"next-rotation,omitempty" json:"next-rotation,omitempty"`
+	Grants         []string       `yaml:"grants,omitempty" json:"grants,omitempty"`
+	Created        time.Time      `yaml:"created" json:"created"`
+	Updated        time.Time      `yaml:"updated" json:"updated"`
+	Revisions      []RevisionInfo `yaml:"revisions,omitempty" json:"revisions,omitempty"`
+}
+
+// RevisionInfo is the serialization format of a revision of a secret.
+type RevisionInfo struct {
+	Revision int       `yaml:"revision" json:"revision"`
+	Keys     []string  `yaml:"keys" json:"keys"`
+	Created  time.Time `yaml:"created" json:"created"`
+}
+
+func formatSecret(secret params.SecretDetails) SecretInfo {
+	out := SecretInfo{
+		Owner:        secret.OwnerTag,
+		Description:  secret.Description,
+		Revision:     secret.Revision,
+		Keys:         secret.Keys,
+		NextRotation: secret.NextRotation,
+		Created:      secret.Created,
+		Updated:      secret.Updated,
+	}
+	if owner, err := names.ParseServiceTag(secret.OwnerTag); err == nil {
+		out.Owner = owner.Id()
+	}
+	if secret.RotateInterval > 0 {
+		out.RotateInterval = secret.RotateInterval.String()
+	}
+	for _, grant := range secret.Grants {
+		if tag, err := names.ParseTag(grant); err == nil {
+			grant = names.ReadableString(tag)
+		}
+		out.Grants = append(out.Grants, grant)
+	}
+	for _, revision := range secret.Revisions {
+		out.Revisions = append(out.Revisions, RevisionInfo{
+			Revision: revision.Revision,
+			Keys:     revision.Keys,
+			Created:  revision.Created,
+		})
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// ShowSecretAPI defines the API methods that the show-secret command
+// uses.
+type ShowSecretAPI interface {
+	Close() error
+	ShowSecret(id string) (*params.SecretDetails, error)
+}
+
+const showSecretCommandDoc = `
+Shows the details of a secret stored by a charm, including the keys
+and creation time of each of its revisions. Secret values are never
+displayed.
+
+Examples:
+    juju show-secret 3
+    juju show-secret 3 --format json
+
+See also:
+    list-secrets
+`
+
+// NewShowSecretCommand returns a command that shows the details of
+// a secret.
+func NewShowSecretCommand() cmd.Command {
+	cmd := &showSecretCommand{}
+	cmd.newAPIFunc = func() (ShowSecretAPI, error) {
+		return cmd.NewAPIClient()
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// showSecretCommand shows the details of a secret.
+type showSecretCommand struct {
+	SecretsCommandBase
+	newAPIFunc func() (ShowSecretAPI, error)
+	out        cmd.Output
+	id         string
+}
+
+// Info implements Command.Info.
+func (c *showSecretCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-secret",
+		Args:    "<id>",
+		Purpose: "Show the details of a secret stored by a charm.",
+		Doc:     showSecretCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showSecretCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SecretsCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showSecretCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret id specified")
+	}
+	c.id, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *showSecretCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	secret, err := api.ShowSecret(c.id)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, map[string]SecretInfo{
+		secret.Id: formatSecret(*secret),
+	})
+}
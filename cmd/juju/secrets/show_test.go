@@ -0,0 +1,69 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/secrets"
+	"github.com/juju/juju/testing"
+)
+
+type showSuite struct {
+	secretsSuite
+	mockAPI *mockSecretsAPI
+}
+
+var _ = gc.Suite(&showSuite{})
+
+func (s *showSuite) SetUpTest(c *gc.C) {
+	s.secretsSuite.SetUpTest(c)
+	s.mockAPI = newMockSecretsAPI()
+}
+
+func (s *showSuite) runShow(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, secrets.NewShowSecretCommandForTest(s.mockAPI, s.store), args...)
+}
+
+func (s *showSuite) TestShowYAML(c *gc.C) {
+	ctx, err := s.runShow(c, "10")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+"10":
+  owner: wordpress
+  revision: 1
+  keys:
+  - token
+  created: 2016-06-01T12:00:00Z
+  updated: 2016-06-01T12:00:00Z
+  revisions:
+  - revision: 1
+    keys:
+    - token
+    created: 2016-06-01T12:00:00Z
+`[1:])
+}
+
+func (s *showSuite) TestShowJSON(c *gc.C) {
+	ctx, err := s.runShow(c, "10", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `{"10":{"owner":"wordpress","revision":1,"keys":["token"],`+
+		`"created":"2016-06-01T12:00:00Z","updated":"2016-06-01T12:00:00Z",`+
+		`"revisions":[{"revision":1,"keys":["token"],"created":"2016-06-01T12:00:00Z"}]}}`+"\n")
+}
+
+func (s *showSuite) TestShowNotFound(c *gc.C) {
+	_, err := s.runShow(c, "3")
+	c.Assert(err, gc.ErrorMatches, `secret "3" not found`)
+}
+
+func (s *showSuite) TestShowArgs(c *gc.C) {
+	_, err := s.runShow(c)
+	c.Assert(err, gc.ErrorMatches, "no secret id specified")
+	_, err = s.runShow(c, "3", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
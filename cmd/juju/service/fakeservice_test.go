@@ -5,6 +5,7 @@ package service_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -18,6 +19,8 @@ type fakeServiceAPI struct {
 	charmName   string
 	values      map[string]interface{}
 	config      string
+	aggregation string
+	hookTimeout time.Duration
 	err         error
 }
 
@@ -31,6 +34,12 @@ func (f *fakeServiceAPI) Update(args params.ServiceUpdate) error {
 	}
 
 	f.config = args.SettingsYAML
+	if args.StatusAggregation != nil {
+		f.aggregation = *args.StatusAggregation
+	}
+	if args.HookTimeout != nil {
+		f.hookTimeout = *args.HookTimeout
+	}
 	return nil
 }
 
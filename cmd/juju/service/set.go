@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -34,7 +35,14 @@ type setCommand struct {
 	Options         []string
 	SettingsYAML    cmd.FileVar
 	SetDefault      bool
-	serviceApi      serviceAPI
+	// StatusAggregation holds the policy used to derive the service's
+	// status from its units' statuses, if it is to be changed.
+	StatusAggregation string
+	// HookTimeout holds the time after which the service's running
+	// hooks are killed, if it is to be changed.
+	HookTimeout *time.Duration
+	hookTimeout string
+	serviceApi  serviceAPI
 }
 
 var usageSetConfigSummary = `
@@ -52,10 +60,21 @@ and the actual value is read from it. The maximum size of the filename is
 Values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in referenced files.
 See ` + "`juju status`" + ` for service names.
+Unless the service's leader sets the service status itself, the status
+shown for the service is derived from its units' statuses according to
+--status-aggregation:
+    worst-of: the most severe unit status (the default).
+    majority: the status of most units, or the most severe in a tie.
+    leader-only: the status of the leader unit.
+--hook-timeout overrides the model's hook-timeout for the service: hooks
+that run for longer are killed and the unit is put into an error state.
+A value of 0 reverts to the model's setting.
 
 Examples:
     juju set-config mysql dataset-size=80% backup_dir=/vol1/mysql/backups
     juju set-config apache2 --model mymodel --config /home/ubuntu/mysql.yaml
+    juju set-config postgresql --status-aggregation majority
+    juju set-config postgresql --hook-timeout 30m
 
 See also: 
     get-config
@@ -79,6 +98,8 @@ func (c *setCommand) Info() *cmd.Info {
 func (c *setCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
 	f.BoolVar(&c.SetDefault, "to-default", false, "set service option values to default")
+	f.StringVar(&c.StatusAggregation, "status-aggregation", "", "how to derive the service status from unit statuses [worst-of|majority|leader-only]")
+	f.StringVar(&c.hookTimeout, "hook-timeout", "", "time after which the service's running hooks are killed, e.g. 30m")
 }
 
 // Init implements Command.Init.
@@ -90,6 +111,16 @@ func (c *setCommand) Init(args []string) error {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
 	c.ServiceName = args[0]
+	if c.hookTimeout != "" {
+		timeout, err := time.ParseDuration(c.hookTimeout)
+		if err != nil {
+			return errors.Annotate(err, "invalid --hook-timeout")
+		}
+		if timeout < 0 {
+			return errors.Errorf("invalid --hook-timeout: negative duration %v", timeout)
+		}
+		c.HookTimeout = &timeout
+	}
 	if c.SetDefault {
 		c.Options = args[1:]
 		if len(c.Options) == 0 {
@@ -134,6 +165,18 @@ func (c *setCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
+	if c.StatusAggregation != "" || c.HookTimeout != nil {
+		args := params.ServiceUpdate{
+			ServiceName: c.ServiceName,
+			HookTimeout: c.HookTimeout,
+		}
+		if c.StatusAggregation != "" {
+			args.StatusAggregation = &c.StatusAggregation
+		}
+		if err := apiclient.Update(args); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -68,6 +69,11 @@ func (s *SetSuite) TestSetCommandInit(c *gc.C) {
 	err = coretesting.InitCommand(service.NewSetCommandForTest(s.fakeServiceAPI), []string{"service", "--to-default"})
 	c.Assert(err, gc.ErrorMatches, "no configuration options specified")
 
+	// invalid --hook-timeout
+	err = coretesting.InitCommand(service.NewSetCommandForTest(s.fakeServiceAPI), []string{"service", "--hook-timeout", "soon"})
+	c.Assert(err, gc.ErrorMatches, `invalid --hook-timeout: time: invalid duration "?soon"?`)
+	err = coretesting.InitCommand(service.NewSetCommandForTest(s.fakeServiceAPI), []string{"service", "--hook-timeout", "-5m"})
+	c.Assert(err, gc.ErrorMatches, "invalid --hook-timeout: negative duration -5m0s")
 }
 
 func (s *SetSuite) TestSetOptionSuccess(c *gc.C) {
@@ -155,6 +161,20 @@ func (s *SetSuite) TestSetConfigToDefault(c *gc.C) {
 	}, make(map[string]interface{}))
 }
 
+func (s *SetSuite) TestSetStatusAggregation(c *gc.C) {
+	s.assertSetSuccess(c, s.dir, []string{
+		"--status-aggregation", "majority",
+	}, nil)
+	c.Check(s.fakeServiceAPI.aggregation, gc.Equals, "majority")
+}
+
+func (s *SetSuite) TestSetHookTimeout(c *gc.C) {
+	s.assertSetSuccess(c, s.dir, []string{
+		"--hook-timeout", "30m",
+	}, nil)
+	c.Check(s.fakeServiceAPI.hookTimeout, gc.Equals, 30*time.Minute)
+}
+
 func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fakeServiceAPI.err = common.OperationBlockedError("TestBlockSetConfig")
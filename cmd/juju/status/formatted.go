@@ -22,12 +22,26 @@ type formattedMachineStatus struct {
 	Machines map[string]machineStatus `json:"machines"`
 }
 
+type formattedUnitStatus struct {
+	Model string                `json:"model"`
+	Units map[string]unitStatus `json:"units"`
+}
+
 type errorStatus struct {
 	StatusError string `json:"status-error" yaml:"status-error"`
 }
 
 type modelStatus struct {
-	AvailableVersion string `json:"upgrade-available,omitempty" yaml:"upgrade-available,omitempty"`
+	AvailableVersion string                `json:"upgrade-available,omitempty" yaml:"upgrade-available,omitempty"`
+	UpgradeRollout   *upgradeRolloutStatus `json:"upgrade-rollout,omitempty" yaml:"upgrade-rollout,omitempty"`
+}
+
+type upgradeRolloutStatus struct {
+	Version  string   `json:"version" yaml:"version"`
+	Status   string   `json:"status" yaml:"status"`
+	Message  string   `json:"message,omitempty" yaml:"message,omitempty"`
+	Canaries []string `json:"canaries" yaml:"canaries"`
+	Released string   `json:"released" yaml:"released"`
 }
 
 type machineStatus struct {
@@ -64,6 +78,7 @@ func (s machineStatus) MarshalYAML() (interface{}, error) {
 type serviceStatus struct {
 	Err           error                 `json:"-" yaml:",omitempty"`
 	Charm         string                `json:"charm" yaml:"charm"`
+	Version       string                `json:"version,omitempty" yaml:"version,omitempty"`
 	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
 	Exposed       bool                  `json:"exposed" yaml:"exposed"`
 	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
@@ -96,15 +111,20 @@ type meterStatus struct {
 
 type unitStatus struct {
 	// New Juju Health Status fields.
-	WorkloadStatusInfo statusInfoContents `json:"workload-status,omitempty" yaml:"workload-status"`
-	JujuStatusInfo     statusInfoContents `json:"juju-status,omitempty" yaml:"juju-status"`
-	MeterStatus        *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
-
-	Charm         string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
-	Machine       string                `json:"machine,omitempty" yaml:"machine,omitempty"`
-	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
-	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
-	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	WorkloadStatusInfo statusInfoContents  `json:"workload-status,omitempty" yaml:"workload-status"`
+	JujuStatusInfo     statusInfoContents  `json:"juju-status,omitempty" yaml:"juju-status"`
+	MeterStatus        *meterStatus        `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
+	Health             *statusInfoContents `json:"health,omitempty" yaml:"health,omitempty"`
+
+	Charm           string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
+	WorkloadVersion string                `json:"workload-version,omitempty" yaml:"workload-version,omitempty"`
+	Machine         string                `json:"machine,omitempty" yaml:"machine,omitempty"`
+	OpenedPorts     []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
+	PublicAddress   string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	Subordinates    map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+
+	// CharmState is only populated by show-unit --state.
+	CharmState map[string]string `json:"charm-state,omitempty" yaml:"charm-state,omitempty"`
 }
 
 type statusInfoContents struct {
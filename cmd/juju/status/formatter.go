@@ -4,6 +4,8 @@
 package status
 
 import (
+	"fmt"
+
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/state/multiwatcher"
@@ -39,10 +41,19 @@ func (sf *statusFormatter) format() formattedStatus {
 		Machines: make(map[string]machineStatus),
 		Services: make(map[string]serviceStatus),
 	}
-	if sf.status.AvailableVersion != "" {
+	if sf.status.AvailableVersion != "" || sf.status.UpgradeRollout != nil {
 		out.ModelStatus = &modelStatus{
 			AvailableVersion: sf.status.AvailableVersion,
 		}
+		if r := sf.status.UpgradeRollout; r != nil {
+			out.ModelStatus.UpgradeRollout = &upgradeRolloutStatus{
+				Version:  r.Version,
+				Status:   r.Status,
+				Message:  r.Message,
+				Canaries: r.Canaries,
+				Released: fmt.Sprintf("%d/%d", r.Released, r.Total),
+			}
+		}
 	}
 
 	for k, m := range sf.status.Machines {
@@ -77,6 +88,40 @@ func (sf *statusFormatter) MachineFormat(machineId []string) formattedMachineSta
 	return out
 }
 
+// UnitFormat takes stored model information (params.FullStatus) and
+// formats the status of the named units, which may be subordinates.
+func (sf *statusFormatter) UnitFormat(unitNames []string) formattedUnitStatus {
+	if sf.status == nil {
+		return formattedUnitStatus{}
+	}
+	out := formattedUnitStatus{
+		Model: sf.status.ModelName,
+		Units: make(map[string]unitStatus),
+	}
+	wanted := make(map[string]bool)
+	for _, name := range unitNames {
+		wanted[name] = true
+	}
+	var addUnits func(units map[string]params.UnitStatus, info unitFormatInfo)
+	addUnits = func(units map[string]params.UnitStatus, info unitFormatInfo) {
+		for name, unit := range units {
+			if wanted[name] {
+				info.unit = unit
+				info.unitName = name
+				out.Units[name] = sf.formatUnit(info)
+			}
+			addUnits(unit.Subordinates, info)
+		}
+	}
+	for serviceName, service := range sf.status.Services {
+		addUnits(service.Units, unitFormatInfo{
+			serviceName:   serviceName,
+			meterStatuses: service.MeterStatuses,
+		})
+	}
+	return out
+}
+
 func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineStatus {
 	var out machineStatus
 
@@ -108,6 +153,7 @@ func (sf *statusFormatter) formatService(name string, service params.ServiceStat
 	out := serviceStatus{
 		Err:           service.Err,
 		Charm:         service.Charm,
+		Version:       service.WorkloadVersion,
 		Exposed:       service.Exposed,
 		Life:          service.Life,
 		Relations:     service.Relations,
@@ -158,9 +204,15 @@ func (sf *statusFormatter) formatUnit(info unitFormatInfo) unitStatus {
 		OpenedPorts:        info.unit.OpenedPorts,
 		PublicAddress:      info.unit.PublicAddress,
 		Charm:              info.unit.Charm,
+		WorkloadVersion:    info.unit.WorkloadVersion,
 		Subordinates:       make(map[string]unitStatus),
 	}
 
+	if info.unit.Health.Status != "" {
+		health := sf.getStatusInfoContents(info.unit.Health)
+		out.Health = &health
+	}
+
 	if ms, ok := info.meterStatuses[info.unitName]; ok {
 		out.MeterStatus = &meterStatus{
 			Color:   ms.Color,
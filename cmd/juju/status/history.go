@@ -41,6 +41,7 @@ The statuses are available for the following types.
     juju-unit: will show statuses for the unit's juju agent.
     workload: will show statuses for the unit's workload.
     unit: will show workload and juju agent combined for the specified unit.
+    workload-version: will show the workload versions set by the unit's charm.
     juju-machine: will show statuses for machine's juju agent.
     machine: will show statuses for machines.
     juju-container: will show statuses for the container's juju agent.
@@ -86,7 +87,7 @@ func (c *statusHistoryCommand) Init(args []string) error {
 	}
 	kind := params.HistoryKind(c.outputContent)
 	switch kind {
-	case params.KindUnit, params.KindUnitAgent, params.KindWorkload,
+	case params.KindUnit, params.KindUnitAgent, params.KindWorkload, params.KindWorkloadVersion,
 		params.KindMachineInstance, params.KindMachine, params.KindContainer,
 		params.KindContainerInstance:
 		return nil
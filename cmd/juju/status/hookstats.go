@@ -0,0 +1,216 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/hookstats"
+)
+
+// HookStatsAPI defines the API methods that the show-hook-stats
+// command uses.
+type HookStatsAPI interface {
+	Close() error
+	HookExecutions(unitName string) ([]params.HookExecution, error)
+}
+
+var showHookStatsDoc = `
+Shows how long the hooks, actions and juju-run commands recently
+executed by a unit took to run, with percentiles for each hook or
+action. The most recent executions of each unit are recorded by the
+controller; older executions are discarded, as are all of a unit's
+executions when it is removed.
+
+The same statistics, for the executions since the unit agent last
+started, are reported by the agent itself in the uniter section of
+"juju-introspect --agent unit-<name>-<number> depengine/", run on the
+unit's machine; this works even when the controller is unreachable.
+
+Durations are reported at the 50th, 90th and 99th percentiles, along
+with the longest execution. Executions which exited with a non-zero
+code, or were killed, are counted as failures. Tool calls give the
+mean number of hook tools invoked per execution.
+
+Examples:
+    juju show-hook-stats mysql/0
+    juju show-hook-stats mysql/0 --format yaml
+
+See also:
+    status-history
+`
+
+// NewShowHookStatsCommand returns a command that reports timing
+// statistics for the executions recorded for a unit.
+func NewShowHookStatsCommand() cmd.Command {
+	c := &showHookStatsCommand{}
+	c.newAPIFunc = func() (HookStatsAPI, error) {
+		return c.NewAPIClient()
+	}
+	return modelcmd.Wrap(c)
+}
+
+type showHookStatsCommand struct {
+	modelcmd.ModelCommandBase
+	newAPIFunc func() (HookStatsAPI, error)
+	out        cmd.Output
+	unitName   string
+}
+
+// HookStats holds timing statistics for the executions of a single
+// hook, action or juju-run.
+type HookStats struct {
+	Kind       string         `yaml:"kind" json:"kind"`
+	Name       string         `yaml:"name,omitempty" json:"name,omitempty"`
+	Executions int            `yaml:"executions" json:"executions"`
+	Failures   int            `yaml:"failures" json:"failures"`
+	P50        string         `yaml:"p50" json:"p50"`
+	P90        string         `yaml:"p90" json:"p90"`
+	P99        string         `yaml:"p99" json:"p99"`
+	Max        string         `yaml:"max" json:"max"`
+	Total      string         `yaml:"total" json:"total"`
+	LastRun    time.Time      `yaml:"last-run" json:"last-run"`
+	ToolCalls  map[string]int `yaml:"tool-calls,omitempty" json:"tool-calls,omitempty"`
+}
+
+// Info implements Command.Info.
+func (c *showHookStatsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-hook-stats",
+		Args:    "<unit name>",
+		Purpose: "Shows timings of the hooks recently executed by a unit.",
+		Doc:     showHookStatsDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showHookStatsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatHookStatsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showHookStatsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	unitName, args := args[0], args[1:]
+	if !names.IsValidUnit(unitName) {
+		return errors.NotValidf("unit name %q", unitName)
+	}
+	c.unitName = unitName
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *showHookStatsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	executions, err := api.HookExecutions(c.unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(executions) == 0 {
+		ctx.Infof("No hook executions recorded for %s.", c.unitName)
+		return nil
+	}
+	return c.out.Write(ctx, summarizeHookExecutions(executions))
+}
+
+// summarizeHookExecutions returns statistics for the supplied
+// executions, grouped by kind and name and sorted by the longest
+// total execution time first.
+func summarizeHookExecutions(executions []params.HookExecution) []HookStats {
+	converted := make([]hookstats.Execution, len(executions))
+	for i, execution := range executions {
+		converted[i] = hookstats.Execution{
+			Kind:      execution.Kind,
+			Name:      execution.Name,
+			Started:   execution.Started,
+			Duration:  execution.Duration,
+			ExitCode:  execution.ExitCode,
+			ToolCalls: execution.ToolCalls,
+		}
+	}
+	summary := hookstats.Summarize(converted)
+	stats := make([]HookStats, len(summary))
+	for i, s := range summary {
+		stats[i] = HookStats{
+			Kind:       s.Kind,
+			Name:       s.Name,
+			Executions: s.Executions,
+			Failures:   s.Failures,
+			P50:        formatHookDuration(s.P50),
+			P90:        formatHookDuration(s.P90),
+			P99:        formatHookDuration(s.P99),
+			Max:        formatHookDuration(s.Max),
+			Total:      formatHookDuration(s.Total),
+			LastRun:    s.LastRun,
+			ToolCalls:  s.ToolCalls,
+		}
+	}
+	return stats
+}
+
+// formatHookDuration formats a duration to millisecond precision.
+func formatHookDuration(d time.Duration) string {
+	return (d - d%time.Millisecond).String()
+}
+
+// formatHookStatsTabular returns a tabular summary of hook statistics.
+func formatHookStatsTabular(value interface{}) ([]byte, error) {
+	stats, ok := value.([]HookStats)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", stats, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("KIND", "NAME", "RUNS", "FAILED", "P50", "P90", "P99", "MAX", "TOTAL", "TOOL CALLS")
+	for _, s := range stats {
+		calls := 0
+		for _, count := range s.ToolCalls {
+			calls += count
+		}
+		name := s.Name
+		if name == "" {
+			name = "-"
+		}
+		print(
+			s.Kind, name,
+			fmt.Sprint(s.Executions), fmt.Sprint(s.Failures),
+			s.P50, s.P90, s.P99, s.Max, s.Total,
+			fmt.Sprintf("%.1f", float64(calls)/float64(s.Executions)),
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type hookStatsSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store *jujuclienttesting.MemStore
+	api   *mockHookStatsAPI
+}
+
+var _ = gc.Suite(&hookStatsSuite{})
+
+func (s *hookStatsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	err := modelcmd.WriteCurrentController("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = &jujuclient.ControllerAccounts{
+		CurrentAccount: "admin@local",
+	}
+
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.api = &mockHookStatsAPI{}
+	for i := 1; i <= 10; i++ {
+		s.api.executions = append(s.api.executions, params.HookExecution{
+			Kind:      "hook",
+			Name:      "update-status",
+			Started:   started.Add(time.Duration(i) * 5 * time.Minute),
+			Duration:  time.Duration(i) * time.Second,
+			ToolCalls: map[string]int{"status-set": 1},
+		})
+	}
+	s.api.executions = append(s.api.executions, params.HookExecution{
+		Kind:     "action",
+		Name:     "backup",
+		Started:  started,
+		Duration: 90*time.Second + 1234567,
+		ExitCode: 1,
+	})
+}
+
+func (s *hookStatsSuite) runShowHookStats(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &showHookStatsCommand{
+		newAPIFunc: func() (HookStatsAPI, error) {
+			return s.api, nil
+		},
+	}
+	command.SetClientStore(s.store)
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, modelcmd.Wrap(command), args...)
+}
+
+func (s *hookStatsSuite) TestInit(c *gc.C) {
+	_, err := s.runShowHookStats(c)
+	c.Assert(err, gc.ErrorMatches, "no unit name specified")
+	_, err = s.runShowHookStats(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+	_, err = s.runShowHookStats(c, "mysql/0", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *hookStatsSuite) TestShowHookStatsTabular(c *gc.C) {
+	ctx, err := s.runShowHookStats(c, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.unitName, gc.Equals, "mysql/0")
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+KIND    NAME           RUNS  FAILED  P50        P90        P99        MAX        TOTAL      TOOL CALLS
+action  backup         1     1       1m30.001s  1m30.001s  1m30.001s  1m30.001s  1m30.001s  0.0
+hook    update-status  10    0       5s         9s         10s        10s        55s        1.0
+
+`[1:])
+}
+
+func (s *hookStatsSuite) TestShowHookStatsYAML(c *gc.C) {
+	ctx, err := s.runShowHookStats(c, "mysql/0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- kind: action
+  name: backup
+  executions: 1
+  failures: 1
+  p50: 1m30.001s
+  p90: 1m30.001s
+  p99: 1m30.001s
+  max: 1m30.001s
+  total: 1m30.001s
+  last-run: 2016-06-01T12:00:00Z
+- kind: hook
+  name: update-status
+  executions: 10
+  failures: 0
+  p50: 5s
+  p90: 9s
+  p99: 10s
+  max: 10s
+  total: 55s
+  last-run: 2016-06-01T12:50:00Z
+  tool-calls:
+    status-set: 10
+`[1:])
+}
+
+func (s *hookStatsSuite) TestShowHookStatsNoExecutions(c *gc.C) {
+	s.api.executions = nil
+	ctx, err := s.runShowHookStats(c, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "No hook executions recorded for mysql/0.\n")
+}
+
+func (s *hookStatsSuite) TestShowHookStatsError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.runShowHookStats(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockHookStatsAPI struct {
+	executions []params.HookExecution
+	err        error
+	unitName   string
+	closed     bool
+}
+
+func (m *mockHookStatsAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockHookStatsAPI) HookExecutions(unitName string) ([]params.HookExecution, error) {
+	m.unitName = unitName
+	return m.executions, m.err
+}
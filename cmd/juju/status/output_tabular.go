@@ -99,15 +99,20 @@ func FormatTabular(value interface{}) ([]byte, error) {
 			p("UPGRADE-AVAILABLE")
 			p(envStatus.AvailableVersion)
 		}
+		if r := envStatus.UpgradeRollout; r != nil {
+			p("UPGRADING-TO\tROLLOUT\tRELEASED\tMESSAGE")
+			p(r.Version, r.Status, r.Released, r.Message)
+		}
 		p()
 		tw.Flush()
 	}
 
 	units := make(map[string]unitStatus)
 	metering := false
+	health := false
 	relations := newRelationFormatter()
 	p("[Services]")
-	p("NAME\tSTATUS\tEXPOSED\tCHARM")
+	p("NAME\tVERSION\tSTATUS\tEXPOSED\tCHARM")
 	for _, svcName := range common.SortStringsNaturally(stringKeysFromMap(fs.Services)) {
 		svc := fs.Services[svcName]
 		for un, u := range svc.Units {
@@ -115,10 +120,13 @@ func FormatTabular(value interface{}) ([]byte, error) {
 			if u.MeterStatus != nil {
 				metering = true
 			}
+			if hasHealth(u) {
+				health = true
+			}
 		}
 
 		subs := set.NewStrings(svc.SubordinateTo...)
-		p(svcName, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
+		p(svcName, svc.Version, svc.StatusInfo.Current, fmt.Sprintf("%t", svc.Exposed), svc.Charm)
 		for relType, relatedUnits := range svc.Relations {
 			for _, related := range relatedUnits {
 				relations.add(related, svcName, relType, subs.Contains(related))
@@ -169,6 +177,22 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	}
 	tw.Flush()
 
+	if health {
+		pHealth := func(name string, u unitStatus, level int) {
+			if u.Health != nil {
+				p(indent("", level*2, name), u.Health.Current, u.Health.Since, u.Health.Message)
+			}
+		}
+		p("\n[Health]")
+		p("ID\tHEALTH\tSINCE\tMESSAGE")
+		for _, name := range common.SortStringsNaturally(stringKeysFromMap(units)) {
+			u := units[name]
+			pHealth(name, u, 0)
+			recurseUnits(u, 1, pHealth)
+		}
+		tw.Flush()
+	}
+
 	if metering {
 		p("\n[Metering]")
 		p("ID\tSTATUS\tMESSAGE")
@@ -0,0 +1,117 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// ShowUnitAPI defines the API methods that the show-unit command uses.
+type ShowUnitAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+	UnitCharmState(unitName string) (map[string]string, error)
+}
+
+var showUnitDoc = `
+Shows the status of one or more units. With --state, the key/value data
+that each unit's charm has stored with the state-set hook tool is shown
+as well, under charm-state.
+
+Examples:
+    juju show-unit mysql/0
+    juju show-unit mysql/0 wordpress/1 --state
+
+See also:
+    status
+    show-hook-stats
+`
+
+// NewShowUnitCommand returns a command that shows the status of units,
+// and optionally their charm state.
+func NewShowUnitCommand() cmd.Command {
+	c := &showUnitCommand{}
+	c.newAPIFunc = func() (ShowUnitAPI, error) {
+		return c.NewAPIClient()
+	}
+	return modelcmd.Wrap(c)
+}
+
+type showUnitCommand struct {
+	modelcmd.ModelCommandBase
+	newAPIFunc func() (ShowUnitAPI, error)
+	out        cmd.Output
+	isoTime    bool
+	showState  bool
+	unitNames  []string
+}
+
+// Info implements Command.Info.
+func (c *showUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-unit",
+		Args:    "<unit name> ...",
+		Purpose: "Shows the status of units.",
+		Doc:     showUnitDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	f.BoolVar(&c.showState, "state", false, "Show the charm state stored by each unit")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showUnitCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	for _, unitName := range args {
+		if !names.IsValidUnit(unitName) {
+			return errors.NotValidf("unit name %q", unitName)
+		}
+	}
+	c.unitNames = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showUnitCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+	fullStatus, err := api.Status(c.unitNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	formatted := NewStatusFormatter(fullStatus, c.isoTime).UnitFormat(c.unitNames)
+	for _, unitName := range c.unitNames {
+		unit, ok := formatted.Units[unitName]
+		if !ok {
+			return errors.NotFoundf("unit %q", unitName)
+		}
+		if !c.showState {
+			continue
+		}
+		unit.CharmState, err = api.UnitCharmState(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		formatted.Units[unitName] = unit
+	}
+	return c.out.Write(ctx, formatted)
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/json"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing"
+)
+
+type showUnitSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	store *jujuclienttesting.MemStore
+	api   *mockShowUnitAPI
+}
+
+var _ = gc.Suite(&showUnitSuite{})
+
+func (s *showUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	err := modelcmd.WriteCurrentController("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = &jujuclient.ControllerAccounts{
+		CurrentAccount: "admin@local",
+	}
+
+	s.api = &mockShowUnitAPI{
+		status: &params.FullStatus{
+			ModelName: "admin",
+			Services: map[string]params.ServiceStatus{
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {
+							Machine:        "0",
+							WorkloadStatus: params.DetailedStatus{Status: status.StatusActive},
+							AgentStatus:    params.DetailedStatus{Status: status.StatusIdle},
+							Subordinates: map[string]params.UnitStatus{
+								"logging/0": {
+									AgentStatus: params.DetailedStatus{Status: status.StatusIdle},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		charmState: map[string]map[string]string{
+			"mysql/0":   {"initialised": "true"},
+			"logging/0": {},
+		},
+	}
+}
+
+func (s *showUnitSuite) runShowUnit(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := &showUnitCommand{
+		newAPIFunc: func() (ShowUnitAPI, error) {
+			return s.api, nil
+		},
+	}
+	command.SetClientStore(s.store)
+	args = append(args, "-m", "admin")
+	return testing.RunCommand(c, modelcmd.Wrap(command), args...)
+}
+
+func (s *showUnitSuite) showUnitJSON(c *gc.C, args ...string) formattedUnitStatus {
+	ctx, err := s.runShowUnit(c, append(args, "--format", "json")...)
+	c.Assert(err, jc.ErrorIsNil)
+	var out formattedUnitStatus
+	err = json.Unmarshal([]byte(testing.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	return out
+}
+
+func (s *showUnitSuite) TestInit(c *gc.C) {
+	_, err := s.runShowUnit(c)
+	c.Assert(err, gc.ErrorMatches, "no unit name specified")
+	_, err = s.runShowUnit(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+}
+
+func (s *showUnitSuite) TestShowUnit(c *gc.C) {
+	out := s.showUnitJSON(c, "mysql/0")
+	c.Assert(s.api.patterns, jc.DeepEquals, []string{"mysql/0"})
+	c.Assert(s.api.stateCalls, gc.HasLen, 0)
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(out.Model, gc.Equals, "admin")
+	c.Assert(out.Units, gc.HasLen, 1)
+	unit := out.Units["mysql/0"]
+	c.Assert(unit.Machine, gc.Equals, "0")
+	c.Assert(unit.Subordinates, gc.HasLen, 1)
+	c.Assert(unit.CharmState, gc.IsNil)
+}
+
+func (s *showUnitSuite) TestShowUnitState(c *gc.C) {
+	out := s.showUnitJSON(c, "mysql/0", "logging/0", "--state")
+	c.Assert(s.api.stateCalls, jc.DeepEquals, []string{"mysql/0", "logging/0"})
+	c.Assert(out.Units, gc.HasLen, 2)
+	c.Assert(out.Units["mysql/0"].CharmState, jc.DeepEquals, map[string]string{
+		"initialised": "true",
+	})
+	c.Assert(out.Units["logging/0"].CharmState, gc.HasLen, 0)
+}
+
+func (s *showUnitSuite) TestShowUnitNotFound(c *gc.C) {
+	_, err := s.runShowUnit(c, "mysql/1")
+	c.Assert(err, gc.ErrorMatches, `unit "mysql/1" not found`)
+}
+
+func (s *showUnitSuite) TestShowUnitStateError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.runShowUnit(c, "mysql/0", "--state")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockShowUnitAPI struct {
+	status     *params.FullStatus
+	charmState map[string]map[string]string
+	err        error
+	patterns   []string
+	stateCalls []string
+	closed     bool
+}
+
+func (m *mockShowUnitAPI) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockShowUnitAPI) Status(patterns []string) (*params.FullStatus, error) {
+	m.patterns = patterns
+	return m.status, nil
+}
+
+func (m *mockShowUnitAPI) UnitCharmState(unitName string) (map[string]string, error) {
+	m.stateCalls = append(m.stateCalls, unitName)
+	return m.charmState[unitName], m.err
+}
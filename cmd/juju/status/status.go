@@ -61,7 +61,7 @@ Explanation of the different formats:
            - SERVICES: total #, and # exposed of each service.
 - tabular (default): Displays information in a tabular format in these sections:
            - Machines: ID, STATE, DNS, INS-ID, SERIES, AZ
-           - Services: NAME, EXPOSED, CHARM
+           - Services: NAME, VERSION, STATUS, EXPOSED, CHARM
            - Units: ID, STATE, VERSION, MACHINE, PORTS, PUBLIC-ADDRESS
              - Also displays subordinate units.
 - yaml: Displays information on machines, services, and units in yaml format.
@@ -2710,6 +2710,18 @@ func (sus setUnitStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setUnitWorkloadVersion struct {
+	unitName string
+	version  string
+}
+
+func (swv setUnitWorkloadVersion) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(swv.unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.SetWorkloadVersion(swv.version)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setAgentStatus struct {
 	unitName   string
 	status     status.Status
@@ -3125,6 +3137,7 @@ func (s *StatusSuite) prepareTabularData(c *gc.C) *context {
 			status.StatusMaintenance,
 			"installing all the things", nil},
 		setUnitTools{"mysql/0", version.MustParseBinary("1.2.3-trusty-ppc")},
+		setUnitWorkloadVersion{"mysql/0", "5.7.13"},
 		addService{name: "logging", charm: "logging"},
 		setServiceExposed{"logging", true},
 		relateServices{"wordpress", "mysql"},
@@ -3159,10 +3172,10 @@ UPGRADE-AVAILABLE
 %s
 
 [Services] 
-NAME       STATUS      EXPOSED CHARM                  
-logging                true    cs:quantal/logging-1   
-mysql      maintenance true    cs:quantal/mysql-1     
-wordpress  active      true    cs:quantal/wordpress-3 
+NAME       VERSION STATUS      EXPOSED CHARM                  
+logging                        true    cs:quantal/logging-1   
+mysql      5.7.13  maintenance true    cs:quantal/mysql-1     
+wordpress          active      true    cs:quantal/wordpress-3 
 
 [Relations] 
 SERVICE1    SERVICE2  RELATION          TYPE        
@@ -3228,8 +3241,8 @@ func (s *StatusSuite) TestFormatTabularHookActionName(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(out), gc.Equals, `
 [Services] 
-NAME       STATUS EXPOSED CHARM 
-foo               false         
+NAME       VERSION STATUS EXPOSED CHARM 
+foo                       false         
 
 [Units] 
 ID      WORKLOAD-STATUS JUJU-STATUS VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE                           
@@ -3241,6 +3254,42 @@ ID         STATE DNS INS-ID SERIES AZ
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularUpgradeRollout(c *gc.C) {
+	status := NewStatusFormatter(&params.FullStatus{
+		UpgradeRollout: &params.UpgradeRolloutStatus{
+			Version:  "2.0.1",
+			Status:   "paused",
+			Message:  "machine 3: upgrade to 2.0.1 failed (giving up): boom",
+			Canaries: []string{"3"},
+			Released: 1,
+			Total:    4,
+		},
+	}, false).format()
+	c.Assert(status.ModelStatus.UpgradeRollout, jc.DeepEquals, &upgradeRolloutStatus{
+		Version:  "2.0.1",
+		Status:   "paused",
+		Message:  "machine 3: upgrade to 2.0.1 failed (giving up): boom",
+		Canaries: []string{"3"},
+		Released: "1/4",
+	})
+	out, err := FormatTabular(status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, `
+[Model]      
+UPGRADING-TO ROLLOUT RELEASED MESSAGE                                              
+2.0.1        paused  1/4      machine 3: upgrade to 2.0.1 failed (giving up): boom 
+
+[Services] 
+NAME       VERSION STATUS EXPOSED CHARM 
+
+[Units] 
+ID      WORKLOAD-STATUS JUJU-STATUS VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE 
+
+[Machines] 
+ID         STATE DNS INS-ID SERIES AZ 
+`[1:])
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
@@ -3294,8 +3343,8 @@ func (s *StatusSuite) TestFormatTabularMetering(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(out), gc.Equals, `
 [Services] 
-NAME       STATUS EXPOSED CHARM 
-foo               false         
+NAME       VERSION STATUS EXPOSED CHARM 
+foo                       false         
 
 [Units] 
 ID      WORKLOAD-STATUS JUJU-STATUS VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE 
@@ -3312,6 +3361,78 @@ ID         STATE DNS INS-ID SERIES AZ
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularHealth(c *gc.C) {
+	status := formattedStatus{
+		Services: map[string]serviceStatus{
+			"foo": serviceStatus{
+				Units: map[string]unitStatus{
+					"foo/0": unitStatus{
+						Health: &statusInfoContents{
+							Current: status.StatusHealthy,
+							Since:   "01 Apr 15 01:23+10:00",
+						},
+						Subordinates: map[string]unitStatus{
+							"bar/0": unitStatus{
+								Health: &statusInfoContents{
+									Current: status.StatusUnhealthy,
+									Message: "http: connection refused",
+									Since:   "01 Apr 15 01:24+10:00",
+								},
+							},
+						},
+					},
+					"foo/1": unitStatus{},
+				},
+			},
+		},
+	}
+	out, err := FormatTabular(status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(out), gc.Equals, `
+[Services] 
+NAME       VERSION STATUS EXPOSED CHARM 
+foo                       false         
+
+[Units] 
+ID      WORKLOAD-STATUS JUJU-STATUS VERSION MACHINE PORTS PUBLIC-ADDRESS MESSAGE 
+foo/0                                                                            
+  bar/0                                                                          
+foo/1                                                                            
+
+[Health] 
+ID       HEALTH    SINCE                 MESSAGE                  
+foo/0    healthy   01 Apr 15 01:23+10:00                          
+  bar/0  unhealthy 01 Apr 15 01:24+10:00 http: connection refused 
+
+[Machines] 
+ID         STATE DNS INS-ID SERIES AZ 
+`[1:])
+}
+
+func (s *StatusSuite) TestFormatUnitHealth(c *gc.C) {
+	formatted := NewStatusFormatter(&params.FullStatus{
+		Services: map[string]params.ServiceStatus{
+			"foo": {
+				Units: map[string]params.UnitStatus{
+					"foo/0": {
+						Health: params.DetailedStatus{
+							Status: status.StatusUnhealthy,
+							Info:   "tcp: connection refused",
+						},
+					},
+					"foo/1": {},
+				},
+			},
+		},
+	}, false).format()
+	units := formatted.Services["foo"].Units
+	c.Assert(units["foo/0"].Health, jc.DeepEquals, &statusInfoContents{
+		Current: status.StatusUnhealthy,
+		Message: "tcp: connection refused",
+	})
+	c.Assert(units["foo/1"].Health, gc.IsNil)
+}
+
 //
 // Filtering Feature
 //
@@ -36,3 +36,17 @@ func recurseUnits(u unitStatus, il int, recurseMap func(string, unitStatus, int)
 func indent(prepend string, level int, append string) string {
 	return fmt.Sprintf("%s%*s%s", prepend, level, "", append)
 }
+
+// hasHealth reports whether the given unit, or any of its
+// subordinates, has reported its health.
+func hasHealth(u unitStatus) bool {
+	if u.Health != nil {
+		return true
+	}
+	for _, sub := range u.Subordinates {
+		if hasHealth(sub) {
+			return true
+		}
+	}
+	return false
+}
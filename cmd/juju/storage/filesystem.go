@@ -47,8 +47,9 @@ type FilesystemAttachments struct {
 }
 
 type MachineFilesystemAttachment struct {
-	MountPoint string `yaml:"mount-point" json:"mount-point"`
-	ReadOnly   bool   `yaml:"read-only" json:"read-only"`
+	MountPoint string           `yaml:"mount-point" json:"mount-point"`
+	ReadOnly   bool             `yaml:"read-only" json:"read-only"`
+	Usage      *FilesystemUsage `yaml:"usage,omitempty" json:"usage,omitempty"`
 }
 
 // generateListFilesystemOutput returns a map filesystem IDs to filesystem info
@@ -131,8 +132,9 @@ func createFilesystemInfo(details params.FilesystemDetails) (names.FilesystemTag
 				return names.FilesystemTag{}, FilesystemInfo{}, errors.Trace(err)
 			}
 			machineAttachments[machineId] = MachineFilesystemAttachment{
-				attachment.MountPoint,
-				attachment.ReadOnly,
+				MountPoint: attachment.MountPoint,
+				ReadOnly:   attachment.ReadOnly,
+				Usage:      createFilesystemUsage(attachment.Usage),
 			}
 		}
 		info.Attachments = &FilesystemAttachments{
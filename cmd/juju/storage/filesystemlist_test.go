@@ -88,13 +88,13 @@ func (s *ListSuite) TestFilesystemListWithErrorResults(c *gc.C) {
 }
 
 var expectedFilesystemListTabular = `
-MACHINE  UNIT         STORAGE      ID   VOLUME  PROVIDER-ID                       MOUNTPOINT  SIZE    STATE      MESSAGE
-0        abc/0        db-dir/1001  0/0  0/1     provider-supplied-filesystem-0-0  /mnt/fuji   512MiB  attached   
-0        transcode/0  shared-fs/0  4            provider-supplied-filesystem-4    /mnt/doom   1.0GiB  attached   
-0                                  1            provider-supplied-filesystem-1                2.0GiB  attaching  failed to attach, will retry
-1        transcode/1  shared-fs/0  4            provider-supplied-filesystem-4    /mnt/huang  1.0GiB  attached   
-1                                  2            provider-supplied-filesystem-2    /mnt/zion   3.0MiB  attached   
-1                                  3                                                          42MiB   pending    
+MACHINE  UNIT         STORAGE      ID   VOLUME  PROVIDER-ID                       MOUNTPOINT  SIZE    USAGE  STATE      MESSAGE
+0        abc/0        db-dir/1001  0/0  0/1     provider-supplied-filesystem-0-0  /mnt/fuji   512MiB         attached   
+0        transcode/0  shared-fs/0  4            provider-supplied-filesystem-4    /mnt/doom   1.0GiB  30%    attached   
+0                                  1            provider-supplied-filesystem-1                2.0GiB         attaching  failed to attach, will retry
+1        transcode/1  shared-fs/0  4            provider-supplied-filesystem-4    /mnt/huang  1.0GiB  30%    attached   
+1                                  2            provider-supplied-filesystem-2    /mnt/zion   3.0MiB         attached   
+1                                  3                                                          42MiB          pending    
 
 `[1:]
 
@@ -256,6 +256,11 @@ func (s mockListAPI) ListFilesystems(machines []string) ([]params.FilesystemDeta
 				"machine-0": params.FilesystemAttachmentInfo{
 					MountPoint: "/mnt/doom",
 					ReadOnly:   true,
+					Usage: &params.FilesystemUsage{
+						Size:      100,
+						Used:      30,
+						Available: 70,
+					},
 				},
 				"machine-1": params.FilesystemAttachmentInfo{
 					MountPoint: "/mnt/huang",
@@ -279,6 +284,11 @@ func (s mockListAPI) ListFilesystems(machines []string) ([]params.FilesystemDeta
 						UnitTag:    "unit-transcode-1",
 						MachineTag: "machine-1",
 						Location:   "/mnt/pieces",
+						Usage: &params.FilesystemUsage{
+							Size:      100,
+							Used:      30,
+							Available: 70,
+						},
 					},
 				},
 			},
@@ -41,11 +41,11 @@ func (s *ListSuite) TestList(c *gc.C) {
 		// Default format is tabular
 		`
 \[Storage\]    
-UNIT         ID          LOCATION STATUS   MESSAGE 
-postgresql/0 db-dir/1100 hither   attached         
-transcode/0  db-dir/1000 thither  pending          
-transcode/0  shared-fs/0 there    attached         
-transcode/1  shared-fs/0 here     attached         
+UNIT         ID          LOCATION USAGE STATUS   MESSAGE 
+postgresql/0 db-dir/1100 hither         attached         
+transcode/0  db-dir/1000 thither        pending          
+transcode/0  shared-fs/0 there    45%   attached         
+transcode/1  shared-fs/0 here           attached         
 
 `[1:])
 }
@@ -86,6 +86,12 @@ storage:
       units:
         transcode/0:
           location: there
+          usage:
+            size: 100
+            used: 45
+            available: 55
+            used-percent: 45
+            updated: .*
         transcode/1:
           location: here
 `[1:])
@@ -98,11 +104,11 @@ func (s *ListSuite) TestListOwnerStorageIdSort(c *gc.C) {
 		// Default format is tabular
 		`
 \[Storage\]    
-UNIT         ID          LOCATION STATUS   MESSAGE 
-postgresql/0 db-dir/1100 hither   attached         
-transcode/0  db-dir/1000 thither  pending          
-transcode/0  shared-fs/0 there    attached         
-transcode/1  shared-fs/0 here     attached         
+UNIT         ID          LOCATION USAGE STATUS   MESSAGE 
+postgresql/0 db-dir/1100 hither         attached         
+transcode/0  db-dir/1000 thither        pending          
+transcode/0  shared-fs/0 there    45%   attached         
+transcode/1  shared-fs/0 here           attached         
 
 `[1:])
 }
@@ -188,6 +194,12 @@ func (s mockListAPI) ListStorageDetails() ([]params.StorageDetails, error) {
 		Attachments: map[string]params.StorageAttachmentDetails{
 			"unit-transcode-0": params.StorageAttachmentDetails{
 				Location: "there",
+				Usage: &params.FilesystemUsage{
+					Size:      100,
+					Used:      45,
+					Available: 55,
+					Updated:   &epoch,
+				},
 			},
 			"unit-transcode-1": params.StorageAttachmentDetails{
 				Location: "here",
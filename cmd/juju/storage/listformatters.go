@@ -30,7 +30,7 @@ func formatStorageListTabular(value interface{}) ([]byte, error) {
 		fmt.Fprintln(tw)
 	}
 	p("[Storage]")
-	p("UNIT\tID\tLOCATION\tSTATUS\tMESSAGE")
+	p("UNIT\tID\tLOCATION\tUSAGE\tSTATUS\tMESSAGE")
 
 	byUnit := make(map[string]map[string]storageAttachmentInfo)
 	for storageId, storageInfo := range storageInfo {
@@ -60,6 +60,7 @@ func formatStorageListTabular(value interface{}) ([]byte, error) {
 				kind:       storageInfo.Kind,
 				persistent: storageInfo.Persistent,
 				location:   a.Location,
+				usage:      a.Usage,
 				status:     storageInfo.Status,
 			}
 		}
@@ -83,7 +84,7 @@ func formatStorageListTabular(value interface{}) ([]byte, error) {
 
 		for _, storageId := range storageIds {
 			info := byStorage[storageId]
-			p(info.unitId, info.storageId, info.location, info.status.Current, info.status.Message)
+			p(info.unitId, info.storageId, info.location, info.usage.String(), info.status.Current, info.status.Message)
 		}
 	}
 	tw.Flush()
@@ -97,6 +98,7 @@ type storageAttachmentInfo struct {
 	kind       string
 	persistent bool
 	location   string
+	usage      *FilesystemUsage
 	status     EntityStatus
 }
 
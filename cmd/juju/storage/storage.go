@@ -7,6 +7,8 @@
 package storage
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
@@ -58,9 +60,66 @@ type UnitStorageAttachment struct {
 	// Location is the location of the storage attachment.
 	Location string `yaml:"location,omitempty" json:"location,omitempty"`
 
+	// Usage is the most recently reported usage of the attached
+	// filesystem, if any.
+	Usage *FilesystemUsage `yaml:"usage,omitempty" json:"usage,omitempty"`
+
 	// TODO(axw) per-unit status when we have it in state.
 }
 
+// FilesystemUsage contains details of the space and inode usage of an
+// attached filesystem.
+type FilesystemUsage struct {
+	// Size, Used and Available are measured in bytes.
+	Size      uint64 `yaml:"size" json:"size"`
+	Used      uint64 `yaml:"used" json:"used"`
+	Available uint64 `yaml:"available" json:"available"`
+
+	// UsedPercent is the percentage of space used, rounded up.
+	UsedPercent int `yaml:"used-percent" json:"used-percent"`
+
+	// Inodes and InodesUsed are omitted for filesystems that
+	// allocate inodes dynamically.
+	Inodes     uint64 `yaml:"inodes,omitempty" json:"inodes,omitempty"`
+	InodesUsed uint64 `yaml:"inodes-used,omitempty" json:"inodes-used,omitempty"`
+
+	// Updated is the time at which the usage was reported.
+	Updated string `yaml:"updated,omitempty" json:"updated,omitempty"`
+}
+
+// String returns the percentage of space used, as shown in
+// tabular output.
+func (u *FilesystemUsage) String() string {
+	if u == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d%%", u.UsedPercent)
+}
+
+// createFilesystemUsage converts a params.FilesystemUsage to a
+// FilesystemUsage, returning nil if no usage is supplied.
+func createFilesystemUsage(usage *params.FilesystemUsage) *FilesystemUsage {
+	if usage == nil {
+		return nil
+	}
+	var usedPercent int
+	if total := usage.Used + usage.Available; total > 0 {
+		usedPercent = int((usage.Used*100 + total - 1) / total)
+	}
+	result := &FilesystemUsage{
+		Size:        usage.Size,
+		Used:        usage.Used,
+		Available:   usage.Available,
+		UsedPercent: usedPercent,
+		Inodes:      usage.Inodes,
+		InodesUsed:  usage.InodesUsed,
+	}
+	if usage.Updated != nil {
+		result.Updated = common.FormatTime(usage.Updated, false)
+	}
+	return result
+}
+
 // formatStorageDetails takes a set of StorageDetail and
 // creates a mapping from storage ID to storage details.
 func formatStorageDetails(storages []params.StorageDetails) (map[string]StorageInfo, error) {
@@ -111,8 +170,9 @@ func createStorageInfo(details params.StorageDetails) (names.StorageTag, Storage
 				machineId = machineTag.Id()
 			}
 			unitStorageAttachments[unitTag.Id()] = UnitStorageAttachment{
-				machineId,
-				attachmentDetails.Location,
+				MachineId: machineId,
+				Location:  attachmentDetails.Location,
+				Usage:     createFilesystemUsage(attachmentDetails.Usage),
 			}
 		}
 		info.Attachments = &StorageAttachments{unitStorageAttachments}
@@ -89,13 +89,13 @@ func (s *ListSuite) TestVolumeListWithErrorResults(c *gc.C) {
 }
 
 var expectedVolumeListTabular = `
-MACHINE  UNIT         STORAGE      ID   PROVIDER-ID                   DEVICE  SIZE    STATE      MESSAGE
-0        abc/0        db-dir/1001  0/0  provider-supplied-volume-0-0  loop0   512MiB  attached   
-0        transcode/0  shared-fs/0  4    provider-supplied-volume-4    xvdf2   1.0GiB  attached   
-0                                  1    provider-supplied-volume-1            2.0GiB  attaching  failed to attach, will retry
-1        transcode/1  shared-fs/0  4    provider-supplied-volume-4    xvdf3   1.0GiB  attached   
-1                                  2    provider-supplied-volume-2    xvdf1   3.0MiB  attached   
-1                                  3                                          42MiB   pending    
+MACHINE  UNIT         STORAGE      ID   PROVIDER-ID                   DEVICE  SIZE    USAGE  STATE      MESSAGE
+0        abc/0        db-dir/1001  0/0  provider-supplied-volume-0-0  loop0   512MiB         attached   
+0        transcode/0  shared-fs/0  4    provider-supplied-volume-4    xvdf2   1.0GiB  30%    attached   
+0                                  1    provider-supplied-volume-1            2.0GiB         attaching  failed to attach, will retry
+1        transcode/1  shared-fs/0  4    provider-supplied-volume-4    xvdf3   1.0GiB         attached   
+1                                  2    provider-supplied-volume-2    xvdf1   3.0MiB         attached   
+1                                  3                                          42MiB          pending    
 
 `[1:]
 
@@ -276,6 +276,11 @@ func (s mockListAPI) ListVolumes(machines []string) ([]params.VolumeDetailsListR
 						UnitTag:    "unit-transcode-0",
 						MachineTag: "machine-0",
 						Location:   "/mnt/bits",
+						Usage: &params.FilesystemUsage{
+							Size:      100,
+							Used:      30,
+							Available: 70,
+						},
 					},
 					"unit-transcode-1": params.StorageAttachmentDetails{
 						StorageTag: "storage-shared-fs-0",
@@ -38,7 +38,7 @@ func formatVolumeListTabularTyped(infos map[string]VolumeInfo) []byte {
 	print := func(values ...string) {
 		fmt.Fprintln(tw, strings.Join(values, "\t"))
 	}
-	print("MACHINE", "UNIT", "STORAGE", "ID", "PROVIDER-ID", "DEVICE", "SIZE", "STATE", "MESSAGE")
+	print("MACHINE", "UNIT", "STORAGE", "ID", "PROVIDER-ID", "DEVICE", "SIZE", "USAGE", "STATE", "MESSAGE")
 
 	volumeAttachmentInfos := make(volumeAttachmentInfos, 0, len(infos))
 	for volumeId, info := range infos {
@@ -79,7 +79,7 @@ func formatVolumeListTabularTyped(infos map[string]VolumeInfo) []byte {
 		print(
 			info.MachineId, info.UnitId, info.Storage,
 			info.VolumeId, info.ProviderVolumeId,
-			info.DeviceName, size,
+			info.DeviceName, size, info.UnitStorageAttachment.Usage.String(),
 			string(info.Status.Current), info.Status.Message,
 		)
 	}
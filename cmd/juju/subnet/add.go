@@ -46,6 +46,11 @@ Any availablility zones associated with the added subnet are automatically
 discovered using the cloud API (if supported). If this is not possible,
 since any subnet needs to be part of at least one zone, specifying
 zone(s) is required.
+
+On clouds without native support for spaces (e.g. LXD or manual), with
+the "juju-managed-spaces" model config set, subnets are discovered from
+machines' network configuration. Adding such a subnet by its CIDR moves
+it into the given space; subnets not yet discovered are added as given.
 `
 
 // Info is defined on the cmd.Command interface.
@@ -0,0 +1,82 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+// newBackupScheduler returns a worker that takes scheduled backups of
+// the controller, storing them alongside those requested through the
+// API. It must only run on one controller at a time.
+func newBackupScheduler(st *state.State, agentConfig agent.Config) (worker.Worker, error) {
+	stor := backups.NewStorage(st)
+	backupsAPI := backups.NewBackups(stor)
+	paths := backups.Paths{
+		DataDir: agentConfig.DataDir(),
+		LogsDir: agentConfig.LogDir(),
+	}
+	machineId := agentConfig.Tag().Id()
+	w, err := backupscheduler.NewWorker(backupscheduler.Config{
+		Clock:       clock.WallClock,
+		ModelConfig: st.ModelConfig,
+		Backups:     backupsAPI,
+		NewMetadata: func() (*backups.Metadata, error) {
+			return backups.NewMetadataState(st, machineId)
+		},
+		CreateBackup: func(meta *backups.Metadata) error {
+			session := st.MongoSession().Copy()
+			defer session.Close()
+			// Don't go if HA isn't ready.
+			if err := replicaset.WaitUntilReady(session, 60); err != nil {
+				return errors.Annotate(err, "HA not ready")
+			}
+			dbInfo, err := backups.NewDBInfo(st.MongoConnectionInfo(), session)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// Scheduled backups are encrypted to the model's backup
+			// public key, when one is configured.
+			cfg, err := st.ModelConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			var key *backups.EncryptionKey
+			if publicKey := cfg.BackupEncryptionPublicKey(); publicKey != "" {
+				key = &backups.EncryptionKey{PublicKey: publicKey}
+			}
+			if err := backupsAPI.Create(meta, &paths, dbInfo, key); err != nil {
+				return errors.Trace(err)
+			}
+			// The backup exists now, so failing to copy it to
+			// remote storage is not a failure of the backup.
+			dest, err := backups.NewDestination(cfg)
+			if err != nil {
+				logger.Errorf("cannot open remote backup storage: %v", err)
+			} else if dest != nil {
+				if err := backups.Push(backupsAPI, dest, meta.ID()); err != nil {
+					logger.Errorf("%v", err)
+				}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		stor.Close()
+		return nil, errors.Annotate(err, "cannot start backup scheduler")
+	}
+	go func() {
+		w.Wait()
+		stor.Close()
+	}()
+	return w, nil
+}
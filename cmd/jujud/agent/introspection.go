@@ -0,0 +1,73 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/introspection"
+)
+
+// introspectionConfig defines the arguments for startIntrospection.
+type introspectionConfig struct {
+	Agent  names.Tag
+	Engine *dependency.Engine
+	Runner worker.Runner
+}
+
+// introspectionWorker holds the introspection worker started for an
+// agent's current dependency engine. The zero value is ready to use.
+type introspectionWorker struct {
+	mu      sync.Mutex
+	current worker.Worker
+}
+
+// startIntrospection creates the introspection worker for the agent,
+// which reports on the agent's dependency engine and runner until the
+// engine stops. Any worker started for a previous engine is stopped
+// first, so that its socket is closed before the new one is bound.
+//
+// An error is returned if the socket cannot be bound, so that the
+// caller can stop the engine and have the runner start it again.
+func (iw *introspectionWorker) startIntrospection(cfg introspectionConfig) error {
+	if !introspection.IsSupported() {
+		logger.Debugf("introspection worker not supported")
+		return nil
+	}
+	iw.mu.Lock()
+	defer iw.mu.Unlock()
+	if iw.current != nil {
+		if err := worker.Stop(iw.current); err != nil {
+			logger.Errorf("previous introspection worker stopped with error: %v", err)
+		}
+		iw.current = nil
+	}
+
+	runners := make(map[string]dependency.Reporter)
+	if reporter, ok := cfg.Runner.(dependency.Reporter); ok {
+		runners["agent"] = reporter
+	}
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketName: introspection.AgentSocketName(cfg.Agent),
+		Reporter:   cfg.Engine,
+		Runners:    runners,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot start introspection worker")
+	}
+	iw.current = w
+	go func() {
+		cfg.Engine.Wait()
+		logger.Debugf("engine stopped, stopping introspection worker")
+		if err := worker.Stop(w); err != nil {
+			logger.Errorf("introspection worker stopped with error: %v", err)
+		}
+	}()
+	return nil
+}
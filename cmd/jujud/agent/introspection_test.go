@@ -0,0 +1,82 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/names"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type introspectionSuite struct {
+	testing.IsolationSuite
+	tag names.Tag
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+func (s *introspectionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	if !introspection.IsSupported() {
+		c.Skip("introspection not supported")
+	}
+	s.tag = names.NewUnitTag("introspection-test/0")
+}
+
+func (s *introspectionSuite) newEngine(c *gc.C) *dependency.Engine {
+	engine, err := dependency.NewEngine(dependency.EngineConfig{
+		IsFatal:     cmdutil.IsFatal,
+		WorstError:  cmdutil.MoreImportantError,
+		ErrorDelay:  time.Second,
+		BounceDelay: time.Millisecond,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { worker.Stop(engine) })
+	return engine
+}
+
+func (s *introspectionSuite) TestStartIntrospectionStopsPrevious(c *gc.C) {
+	var iw introspectionWorker
+	err := iw.startIntrospection(introspectionConfig{
+		Agent:  s.tag,
+		Engine: s.newEngine(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	first := iw.current
+
+	// The first engine is still running, so its introspection worker
+	// still holds the socket until it is replaced.
+	err = iw.startIntrospection(introspectionConfig{
+		Agent:  s.tag,
+		Engine: s.newEngine(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(iw.current)
+	c.Assert(iw.current, gc.Not(gc.Equals), first)
+	c.Assert(first.Wait(), jc.ErrorIsNil)
+}
+
+func (s *introspectionSuite) TestStartIntrospectionBindError(c *gc.C) {
+	addr := &net.UnixAddr{Name: "@" + introspection.AgentSocketName(s.tag), Net: "unix"}
+	listener, err := net.ListenUnix("unix", addr)
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	var iw introspectionWorker
+	err = iw.startIntrospection(introspectionConfig{
+		Agent:  s.tag,
+		Engine: s.newEngine(c),
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot start introspection worker: cannot listen on introspection socket: .*")
+	c.Assert(iw.current, gc.IsNil)
+}
@@ -84,9 +84,10 @@ import (
 )
 
 var (
-	logger       = loggo.GetLogger("juju.cmd.jujud")
-	jujuRun      = paths.MustSucceed(paths.JujuRun(series.HostSeries()))
-	jujuDumpLogs = paths.MustSucceed(paths.JujuDumpLogs(series.HostSeries()))
+	logger         = loggo.GetLogger("juju.cmd.jujud")
+	jujuRun        = paths.MustSucceed(paths.JujuRun(series.HostSeries()))
+	jujuDumpLogs   = paths.MustSucceed(paths.JujuDumpLogs(series.HostSeries()))
+	jujuIntrospect = paths.MustSucceed(paths.JujuIntrospect(series.HostSeries()))
 
 	// The following are defined as variables to allow the tests to
 	// intercept calls to the functions.
@@ -299,6 +300,8 @@ type MachineAgent struct {
 	mongoInitialized bool
 
 	loopDeviceManager looputil.LoopDeviceManager
+
+	introspection introspectionWorker
 }
 
 // IsRestorePreparing returns bool representing if we are in restore mode
@@ -462,6 +465,16 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			}
 			return nil, err
 		}
+		if err := a.introspection.startIntrospection(introspectionConfig{
+			Agent:  a.CurrentConfig().Tag(),
+			Engine: engine,
+			Runner: a.runner,
+		}); err != nil {
+			if err := worker.Stop(engine); err != nil {
+				logger.Errorf("while stopping engine without introspection: %v", err)
+			}
+			return nil, err
+		}
 		return engine, nil
 	}
 }
@@ -860,6 +873,7 @@ func (a *MachineAgent) initState(agentConfig agent.Config) (*state.State, error)
 	if err != nil {
 		return nil, err
 	}
+	st.SetSecretsKeySource(a.secretsKey)
 
 	reportOpenedState(st)
 
@@ -939,7 +953,11 @@ func (a *MachineAgent) startStateWorkers(st *state.State) (worker.Worker, error)
 			stateOpener := func() (*state.State, error) {
 				logger.Debugf("opening state for apiserver worker")
 				st, _, err := openState(agentConfig, stateWorkerDialOpts)
-				return st, err
+				if err != nil {
+					return nil, err
+				}
+				st.SetSecretsKeySource(a.secretsKey)
+				return st, nil
 			}
 			runner.StartWorker("apiserver", a.apiserverWorkerStarter(stateOpener, certChangedChan))
 			var stateServingSetter certupdater.StateServingInfoSetter = func(info params.StateServingInfo, done <-chan struct{}) error {
@@ -965,6 +983,10 @@ func (a *MachineAgent) startStateWorkers(st *state.State) (worker.Worker, error)
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour*2), nil
 			})
+
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				return newBackupScheduler(st, agentConfig)
+			})
 		default:
 			return nil, errors.Errorf("unknown job type %q", job)
 		}
@@ -997,6 +1019,7 @@ func (a *MachineAgent) startModelWorkers(uuid string) (worker.Worker, error) {
 		Clock:                       clock.WallClock,
 		RunFlagDuration:             time.Minute,
 		CharmRevisionUpdateInterval: 24 * time.Hour,
+		UpgradeRolloutInterval:      30 * time.Second,
 		EntityStatusHistoryCount:    100,
 		EntityStatusHistoryInterval: 5 * time.Minute,
 		SpacesImportedGate:          a.discoverSpacesComplete,
@@ -1230,6 +1253,14 @@ func openState(agentConfig agent.Config, dialOpts mongo.DialOpts) (_ *state.Stat
 	return st, m, nil
 }
 
+// secretsKey returns the controller secrets key from the current agent
+// config, so that a key generated by an upgrade step or copied from
+// another controller is used without restarting the agent.
+func (a *MachineAgent) secretsKey() string {
+	info, _ := a.CurrentConfig().StateServingInfo()
+	return info.SecretsKey
+}
+
 func getMachine(st *state.State, tag names.Tag) (*state.Machine, error) {
 	m0, err := st.FindEntity(tag)
 	if err != nil {
@@ -1296,7 +1327,7 @@ func (a *MachineAgent) Tag() names.Tag {
 
 func (a *MachineAgent) createJujudSymlinks(dataDir string) error {
 	jujud := filepath.Join(tools.ToolsDir(dataDir, a.Tag().String()), jujunames.Jujud)
-	for _, link := range []string{jujuRun, jujuDumpLogs} {
+	for _, link := range []string{jujuRun, jujuDumpLogs, jujuIntrospect} {
 		err := a.createSymlink(jujud, link)
 		if err != nil {
 			return errors.Annotatef(err, "failed to create %s symlink", link)
@@ -1330,7 +1361,7 @@ func (a *MachineAgent) createSymlink(target, link string) error {
 }
 
 func (a *MachineAgent) removeJujudSymlinks() (errs []error) {
-	for _, link := range []string{jujuRun, jujuDumpLogs} {
+	for _, link := range []string{jujuRun, jujuDumpLogs, jujuIntrospect} {
 		err := os.Remove(utils.EnsureBaseDir(a.rootDir, link))
 		if err != nil && !os.IsNotExist(err) {
 			errs = append(errs, errors.Annotatef(err, "failed to remove %s symlink", link))
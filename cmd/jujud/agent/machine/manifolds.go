@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machineactions"
+	"github.com/juju/juju/worker/machinefirewaller"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/migrationminion"
 	"github.com/juju/juju/worker/proxyupdater"
@@ -308,6 +309,21 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The disk usage reporter periodically records the space and
+		// inode usage of the filesystems mounted on the machine it runs
+		// on, so that it can be shown alongside the storage using them.
+		diskUsageReporterName: ifFullyUpgraded(diskmanager.UsageManifold(diskmanager.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
+		// The machine firewaller enforces the model's firewall rules
+		// for traffic sent from the machine it runs on, using iptables.
+		machineFirewallerName: ifFullyUpgraded(machinefirewaller.Manifold(machinefirewaller.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		// The proxy config updater is a leaf worker that sets http/https/apt/etc
 		// proxy settings.
 		proxyConfigUpdater: ifFullyUpgraded(proxyupdater.Manifold(proxyupdater.ManifoldConfig{
@@ -430,6 +446,8 @@ const (
 	rebootName               = "reboot-executor"
 	loggingConfigUpdaterName = "logging-config-updater"
 	diskManagerName          = "disk-manager"
+	diskUsageReporterName    = "disk-usage-reporter"
+	machineFirewallerName    = "machine-firewaller"
 	proxyConfigUpdater       = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
 	machinerName             = "machiner"
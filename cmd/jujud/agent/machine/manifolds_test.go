@@ -47,10 +47,12 @@ func (*ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"api-caller",
 		"api-config-watcher",
 		"disk-manager",
+		"disk-usage-reporter",
 		"host-key-reporter",
 		"log-sender",
 		"logging-config-updater",
 		"machine-action-runner",
+		"machine-firewaller",
 		"machiner",
 		"mgo-txn-resumer",
 		"migration-fortress",
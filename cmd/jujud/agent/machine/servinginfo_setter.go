@@ -64,6 +64,11 @@ func ServingInfoSetterManifold(config ServingInfoSetterConfig) dependency.Manifo
 						return nil, errors.Errorf("cannot get state serving info: %v", err)
 					}
 					err = agent.ChangeConfig(func(config coreagent.ConfigSetter) error {
+						// Keep any secrets key we already have if
+						// the controller we asked has none to give.
+						if existing, ok := config.StateServingInfo(); ok && info.SecretsKey == "" {
+							info.SecretsKey = existing.SecretsKey
+						}
 						config.SetStateServingInfo(info)
 						return nil
 					})
@@ -139,6 +139,38 @@ func (s *ServingInfoSetterSuite) TestJobManageEnviron(c *gc.C) {
 	c.Assert(a.conf.ssi.APIPort, gc.Equals, mockAPIPort)
 }
 
+func (s *ServingInfoSetterSuite) TestKeepsSecretsKey(c *gc.C) {
+	// A secrets key already in the agent config is not discarded
+	// when the controller asked does not supply one.
+	a := &mockAgent{}
+	a.conf.SetStateServingInfo(params.StateServingInfo{SecretsKey: "key"})
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, response interface{}) error {
+			switch request {
+			case "GetEntities":
+				result := response.(*params.AgentGetEntitiesResults)
+				result.Entities = []params.AgentGetEntitiesResult{{
+					Jobs: []multiwatcher.MachineJob{multiwatcher.JobManageModel},
+				}}
+			case "StateServingInfo":
+				result := response.(*params.StateServingInfo)
+				*result = params.StateServingInfo{APIPort: 1234}
+			default:
+				c.Fatalf("not sure how to handle: %q", request)
+			}
+			return nil
+		},
+	)
+	w, err := s.manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"agent":      a,
+		"api-caller": apiCaller,
+	}))
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	c.Assert(a.conf.ssi.APIPort, gc.Equals, 1234)
+	c.Assert(a.conf.ssi.SecretsKey, gc.Equals, "key")
+}
+
 func (s *ServingInfoSetterSuite) TestJobHostUnits(c *gc.C) {
 	// State serving info should not be set for JobHostUnits.
 	s.checkNotController(c, multiwatcher.JobHostUnits)
@@ -205,6 +237,10 @@ func (mc *mockConfig) Tag() names.Tag {
 	return mc.tag
 }
 
+func (mc *mockConfig) StateServingInfo() (params.StateServingInfo, bool) {
+	return mc.ssi, mc.ssiSet
+}
+
 func (mc *mockConfig) SetStateServingInfo(info params.StateServingInfo) {
 	mc.ssiSet = true
 	mc.ssi = info
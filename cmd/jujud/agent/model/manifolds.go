@@ -38,6 +38,7 @@ import (
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/undertaker"
 	"github.com/juju/juju/worker/unitassigner"
+	"github.com/juju/juju/worker/upgraderollout"
 )
 
 // ManifoldsConfig holds the dependencies and configuration options for a
@@ -71,6 +72,10 @@ type ManifoldsConfig struct {
 	// revision worker will check for new revisions of known charms.
 	CharmRevisionUpdateInterval time.Duration
 
+	// UpgradeRolloutInterval determines how often the upgrade-rollout
+	// worker will check the progress of a staged agent upgrade.
+	UpgradeRolloutInterval time.Duration
+
 	// EntityStatusHistory* values control status-history pruning
 	// behaviour per entity.
 	EntityStatusHistoryCount    uint
@@ -226,6 +231,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewFacade: charmrevisionmanifold.NewAPIFacade,
 			NewWorker: charmrevision.NewWorker,
 		})),
+		upgradeRolloutName: ifNotDead(upgraderollout.Manifold(upgraderollout.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			Period:        config.UpgradeRolloutInterval,
+
+			NewFacade: upgraderollout.NewFacade,
+			NewWorker: upgraderollout.NewWorker,
+		})),
 		metricWorkerName: ifNotDead(metricworker.Manifold(metricworker.ManifoldConfig{
 			APICallerName: apiCallerName,
 		})),
@@ -307,6 +320,7 @@ const (
 	serviceScalerName        = "service-scaler"
 	instancePollerName       = "instance-poller"
 	charmRevisionUpdaterName = "charm-revision-updater"
+	upgradeRolloutName       = "upgrade-rollout"
 	metricWorkerName         = "metric-worker"
 	stateCleanerName         = "state-cleaner"
 	addressCleanerName       = "address-cleaner"
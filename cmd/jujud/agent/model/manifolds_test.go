@@ -56,6 +56,7 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 		"storage-provisioner",
 		"undertaker",
 		"unit-assigner",
+		"upgrade-rollout",
 	})
 }
 
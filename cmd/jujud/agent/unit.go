@@ -51,6 +51,8 @@ type UnitAgent struct {
 	// longer any immediately pending agent upgrades.
 	// Channel used as a selectable bool (closed means true).
 	initialUpgradeCheckComplete chan struct{}
+
+	introspection introspectionWorker
 }
 
 // NewUnitAgent creates a new UnitAgent value properly initialized.
@@ -192,6 +194,16 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		}
 		return nil, err
 	}
+	if err := a.introspection.startIntrospection(introspectionConfig{
+		Agent:  a.Tag(),
+		Engine: engine,
+		Runner: a.runner,
+	}); err != nil {
+		if err := worker.Stop(engine); err != nil {
+			logger.Errorf("while stopping engine without introspection: %v", err)
+		}
+		return nil, err
+	}
 	return engine, nil
 }
 
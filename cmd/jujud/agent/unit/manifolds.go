@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/voyeur"
 
 	coreagent "github.com/juju/juju/agent"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/healthcheck"
 	"github.com/juju/juju/worker/leadership"
 	"github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
@@ -203,6 +205,18 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			HookRetryStrategyName: hookRetryStrategyName,
 		}),
 
+		// The health check worker evaluates the health checks declared
+		// by the unit's charm, on its own schedule rather than that of
+		// the uniter's hooks, and reports the unit's health.
+		healthCheckName: healthcheck.Manifold(healthcheck.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			CharmDirName:  charmDirName,
+			Clock:         clock.WallClock,
+			NewFacade:     healthcheck.NewFacade,
+			NewWorker:     healthcheck.NewWorker,
+		}),
+
 		// TODO (mattyw) should be added to machine agent.
 		metricSpoolName: spool.Manifold(spool.ManifoldConfig{
 			AgentName: agentName,
@@ -256,6 +270,7 @@ const (
 	leadershipTrackerName = "leadership-tracker"
 	hookRetryStrategyName = "hook-retry-strategy"
 	uniterName            = "uniter"
+	healthCheckName       = "health-check"
 
 	metricSpoolName   = "metric-spool"
 	meterStatusName   = "meter-status"
@@ -53,6 +53,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"leadership-tracker",
 		"hook-retry-strategy",
 		"uniter",
+		"health-check",
 		"metric-spool",
 		"meter-status",
 		"metric-collect",
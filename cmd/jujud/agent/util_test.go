@@ -48,6 +48,7 @@ var (
 		"status-history-pruner",
 		"storage-provisioner",
 		"unit-assigner",
+		"upgrade-rollout",
 	}
 	deadModelWorkers = []string{
 		"environ-tracker", "undertaker",
@@ -0,0 +1,154 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspect provides the juju-introspect command, which
+// queries the introspection endpoint of a running agent on the local
+// machine. Intended for use when diagnosing an agent that is wedged or
+// otherwise misbehaving.
+package introspect
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/agent"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	corenames "github.com/juju/juju/juju/names"
+	"github.com/juju/juju/worker/introspection"
+)
+
+// NewCommand returns a new Command instance which implements the
+// "juju-introspect" command.
+func NewCommand() cmd.Command {
+	return &introspectCommand{}
+}
+
+type introspectCommand struct {
+	cmd.CommandBase
+	dataDir string
+	agent   string
+	path    string
+
+	// dial is used to connect to the agent's introspection socket,
+	// and is replaced in tests.
+	dial func(socketName string) (net.Conn, error)
+}
+
+// Info implements cmd.Command.
+func (c *introspectCommand) Info() *cmd.Info {
+	doc := `
+This tool queries the introspection endpoint served by a Juju agent
+running on the local machine, and writes the response to stdout. It
+must be run on the machine hosting the agent.
+
+By default the machine agent is queried; use --agent to query another
+agent on the machine, such as a unit agent ("unit-mysql-0").
+
+Useful paths include:
+
+    depengine/      the state of the agent's dependency engine
+    runners/        the state of the agent's worker runners
+    goroutines      the stack traces of all goroutines
+    debug/pprof/    the available pprof profiles
+
+Examples:
+
+    juju-introspect depengine/
+    juju-introspect --agent unit-mysql-0 goroutines
+    juju-introspect debug/pprof/heap?debug=1
+`[1:]
+	return &cmd.Info{
+		Name:    corenames.JujuIntrospect,
+		Args:    "<path>",
+		Purpose: "query the introspection endpoint of a local Juju agent",
+		Doc:     doc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *introspectCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", cmdutil.DataDir, "Juju base data directory")
+	f.StringVar(&c.agent, "agent", "", "tag of the agent to query (defaults to the machine agent)")
+}
+
+// Init implements cmd.Command.
+func (c *introspectCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no path specified")
+	}
+	c.path, args = args[0], args[1:]
+	if c.agent != "" {
+		if _, err := names.ParseTag(c.agent); err != nil {
+			return errors.Annotate(err, "invalid --agent")
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *introspectCommand) Run(ctx *cmd.Context) error {
+	tag, err := c.agentTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	socketName := introspection.AgentSocketName(tag)
+	dial := c.dial
+	if dial == nil {
+		dial = dialAbstractSocket
+	}
+	client := http.Client{
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return dial(socketName)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix.socket/" + strings.TrimPrefix(c.path, "/"))
+	if err != nil {
+		return errors.Annotatef(err, "cannot query %s", tag)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(ctx.Stdout, resp.Body)
+	return errors.Trace(err)
+}
+
+// agentTag returns the tag of the agent to query; if none was
+// specified, the machine agent is found in the data directory.
+func (c *introspectCommand) agentTag() (names.Tag, error) {
+	if c.agent != "" {
+		return names.ParseTag(c.agent)
+	}
+	entries, err := ioutil.ReadDir(agent.BaseDir(c.dataDir))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read agent configuration base directory")
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if tag, err := names.ParseMachineTag(entry.Name()); err == nil {
+			return tag, nil
+		}
+	}
+	return nil, errors.New("no machine agent configuration found; specify --agent")
+}
+
+func dialAbstractSocket(socketName string) (net.Conn, error) {
+	conn, err := net.Dial("unix", "@"+socketName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to %q (is the agent running?)", socketName)
+	}
+	return conn, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspect_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cmd/jujud/introspect"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type IntrospectSuite struct {
+	testing.BaseSuite
+	machineId string
+}
+
+var _ = gc.Suite(&IntrospectSuite{})
+
+func (s *IntrospectSuite) SetUpTest(c *gc.C) {
+	if !introspection.IsSupported() {
+		c.Skip("introspection is not supported on this platform")
+	}
+	s.BaseSuite.SetUpTest(c)
+	// Use the pid as the machine id, so that the socket name is
+	// unlikely to collide with a real agent's.
+	s.machineId = fmt.Sprint(os.Getpid())
+}
+
+func (s *IntrospectSuite) serve(c *gc.C) {
+	addr := "@jujud-machine-" + s.machineId
+	listener, err := net.Listen("unix", addr)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { listener.Close() })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/depengine/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "state: started\n")
+	})
+	go http.Serve(listener, mux)
+}
+
+func (s *IntrospectSuite) TestInitErrors(c *gc.C) {
+	err := testing.InitCommand(introspect.NewCommand(), nil)
+	c.Assert(err, gc.ErrorMatches, "no path specified")
+
+	err = testing.InitCommand(introspect.NewCommand(), []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+
+	err = testing.InitCommand(introspect.NewCommand(), []string{"--agent", "bad", "a"})
+	c.Assert(err, gc.ErrorMatches, `invalid --agent: "bad" is not a valid tag`)
+}
+
+func (s *IntrospectSuite) TestQueryAgent(c *gc.C) {
+	s.serve(c)
+	ctx, err := testing.RunCommand(c, introspect.NewCommand(), "--agent", "machine-"+s.machineId, "depengine/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "state: started\n")
+}
+
+func (s *IntrospectSuite) TestQueryMachineAgentFromDataDir(c *gc.C) {
+	s.serve(c)
+	dataDir := c.MkDir()
+	for _, dir := range []string{"unit-mysql-0", "machine-" + s.machineId} {
+		err := os.MkdirAll(filepath.Join(agent.BaseDir(dataDir), dir), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	ctx, err := testing.RunCommand(c, introspect.NewCommand(), "--data-dir", dataDir, "/depengine/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "state: started\n")
+}
+
+func (s *IntrospectSuite) TestNotFound(c *gc.C) {
+	s.serve(c)
+	_, err := testing.RunCommand(c, introspect.NewCommand(), "--agent", "machine-"+s.machineId, "missing")
+	c.Assert(err, gc.ErrorMatches, "404 Not Found: 404 page not found")
+}
+
+func (s *IntrospectSuite) TestAgentNotRunning(c *gc.C) {
+	_, err := testing.RunCommand(c, introspect.NewCommand(), "--agent", "machine-"+s.machineId, "depengine/")
+	c.Assert(err, gc.ErrorMatches, `cannot query machine-\d+: .*cannot connect to "jujud-machine-\d+" \(is the agent running\?\).*`)
+}
+
+func (s *IntrospectSuite) TestNoMachineAgent(c *gc.C) {
+	_, err := testing.RunCommand(c, introspect.NewCommand(), "--data-dir", c.MkDir(), "depengine/")
+	c.Assert(err, gc.ErrorMatches, "cannot read agent configuration base directory: .*")
+}
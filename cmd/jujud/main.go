@@ -21,6 +21,7 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	"github.com/juju/juju/cmd/jujud/dumplogs"
+	"github.com/juju/juju/cmd/jujud/introspect"
 	"github.com/juju/juju/cmd/pprof"
 	components "github.com/juju/juju/component/all"
 	"github.com/juju/juju/juju/names"
@@ -157,6 +158,7 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	jujud.Register(agentcmd.NewUnitAgent(ctx, logCh))
 
 	jujud.Register(NewUpgradeMongoCommand())
+	jujud.Register(NewUpgradeSimulateCommand())
 
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
@@ -204,6 +206,8 @@ func Main(args []string) int {
 		code = cmd.Main(&RunCommand{}, ctx, args[1:])
 	case names.JujuDumpLogs:
 		code = cmd.Main(dumplogs.NewCommand(), ctx, args[1:])
+	case names.JujuIntrospect:
+		code = cmd.Main(introspect.NewCommand(), ctx, args[1:])
 	default:
 		code, err = jujuCMain(commandName, ctx, args)
 	}
@@ -0,0 +1,866 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/retry"
+	"github.com/juju/utils"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/juju/paths"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+const upgradeSimulateDoc = `
+Rehearse the upgrade steps of this jujud against a copy of the
+controller's database.
+
+The most recent backup of the controller (or the one given with
+--backup) is restored into a scratch mongod, which is discarded
+afterwards. The state and API upgrade steps that would run when
+upgrading from the version that took the backup (or the one given with
+--from) to the version of this jujud are run against it, and their
+timings and errors are reported, along with the number of documents
+each step inserted, updated and removed in each collection.
+
+upgrade-simulate must be run on a controller machine, using the jujud
+binary of the version to upgrade to. The agent's configuration and the
+controller's database are not changed. Upgrade steps that act on the
+cloud or on the machine itself, rather than on the database, are not
+run; they are listed as "not simulated".
+`
+
+// NewUpgradeSimulateCommand returns a new UpgradeSimulate command
+// initialized with the default helper functions.
+func NewUpgradeSimulateCommand() *UpgradeSimulateCommand {
+	return &UpgradeSimulateCommand{
+		openBackups:   openControllerBackups,
+		createTempDir: createTempDir,
+		startMongo:    startScratchMongo,
+		restoreDump:   restoreScratchMongo,
+		rehearse:      rehearseUpgrade,
+	}
+}
+
+// scratchMongo is a mongod started to hold a copy of the controller's
+// database.
+type scratchMongo interface {
+	// Info returns the information needed to connect to the mongod
+	// as its administrator.
+	Info() *mongo.MongoInfo
+
+	// Stop stops the mongod.
+	Stop() error
+}
+
+type openBackupsFunc func(agent.Config) (backups.Backups, func(), error)
+type startMongoFunc func(agentConfig agent.Config, dir string) (scratchMongo, error)
+type restoreDumpFunc func(info *mongo.MongoInfo, dumpDir string) error
+type rehearseFunc func(agentConfig agent.ConfigSetter, info *mongo.MongoInfo, dir string, from version.Number) (*upgradeRehearsal, error)
+
+// UpgradeSimulateCommand represents a jujud upgrade-simulate command.
+type UpgradeSimulateCommand struct {
+	cmd.CommandBase
+	machineTag     string
+	series         string
+	configFilePath string
+	backupId       string
+	from           string
+	passphraseFile string
+	privateKeyFile string
+
+	// utils used by this struct.
+	openBackups   openBackupsFunc
+	createTempDir createTempDirFunc
+	startMongo    startMongoFunc
+	restoreDump   restoreDumpFunc
+	rehearse      rehearseFunc
+}
+
+// Info returns a decription of the command.
+func (*UpgradeSimulateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-simulate",
+		Purpose: "rehearse upgrade steps against a copy of the database",
+		Doc:     upgradeSimulateDoc,
+	}
+}
+
+// SetFlags adds the flags for this command to the passed gnuflag.FlagSet.
+func (u *UpgradeSimulateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&u.machineTag, "machinetag", "machine-0", "unique tag identifier for the controller machine")
+	f.StringVar(&u.series, "series", "", "series for the machine")
+	f.StringVar(&u.configFilePath, "configfile", "", "path to the config file")
+	f.StringVar(&u.backupId, "backup", "", "the id of the backup to restore (defaults to the most recent)")
+	f.StringVar(&u.from, "from", "", "the version to upgrade from (defaults to the version that took the backup)")
+	f.StringVar(&u.passphraseFile, "passphrase-file", "", "path to a file holding the passphrase of an encrypted backup")
+	f.StringVar(&u.privateKeyFile, "private-key-file", "", "path to the PEM-encoded private key of an encrypted backup")
+}
+
+// Init initializes the command for running.
+func (u *UpgradeSimulateCommand) Init(args []string) error {
+	if u.from != "" {
+		if _, err := version.Parse(u.from); err != nil {
+			return errors.Annotatef(err, "invalid --from version %q", u.from)
+		}
+	}
+	if u.passphraseFile != "" && u.privateKeyFile != "" {
+		return errors.New("only one of --passphrase-file and --private-key-file may be used")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run restores a backup into a scratch database, rehearses the
+// upgrade steps against it, and reports the results.
+func (u *UpgradeSimulateCommand) Run(ctx *cmd.Context) error {
+	agentConfig, err := u.readAgentConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	meta, archive, err := u.getBackup(agentConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	from := meta.Origin.Version
+	if u.from != "" {
+		from = version.MustParse(u.from)
+	}
+	ctx.Infof("simulating upgrade from %s to %s using backup %s", from, jujuversion.Current, meta.ID())
+
+	workspace, err := backups.NewArchiveWorkspaceReader(archive)
+	if err != nil {
+		return errors.Annotate(err, "cannot unpack backup")
+	}
+	defer workspace.Close()
+
+	dir, err := u.createTempDir()
+	if err != nil {
+		return errors.Annotate(err, "cannot create a directory for the scratch database")
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := u.startMongo(agentConfig, dir)
+	if err != nil {
+		return errors.Annotate(err, "cannot start scratch database")
+	}
+	defer func() {
+		if err := db.Stop(); err != nil {
+			logger.Errorf("cannot stop scratch database: %v", err)
+		}
+	}()
+
+	ctx.Infof("restoring backup into scratch database")
+	if err := u.restoreDump(db.Info(), workspace.DBDumpDir); err != nil {
+		return errors.Annotate(err, "cannot restore backup into scratch database")
+	}
+
+	ctx.Infof("running upgrade steps")
+	rehearsal, err := u.rehearse(agentConfig, db.Info(), dir, from)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeUpgradeRehearsal(ctx.Stdout, rehearsal); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotate(rehearsal.Err, "upgrade steps failed")
+}
+
+func (u *UpgradeSimulateCommand) readAgentConfig() (agent.ConfigSetterWriter, error) {
+	if u.configFilePath == "" {
+		dataDir, err := paths.DataDir(u.series)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot determine data dir for %q", u.series)
+		}
+		machineTag, err := names.ParseMachineTag(u.machineTag)
+		if err != nil {
+			return nil, errors.Annotatef(err, "%q is not a valid machine tag", u.machineTag)
+		}
+		u.configFilePath = agent.ConfigPath(dataDir, machineTag)
+	}
+	agentConfig, err := agent.ReadConfig(u.configFilePath)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read config file in %q", u.configFilePath)
+	}
+	return agentConfig, nil
+}
+
+// getBackup returns the metadata and plaintext archive of the backup
+// to restore.
+func (u *UpgradeSimulateCommand) getBackup(agentConfig agent.Config) (_ *backups.Metadata, _ io.ReadCloser, err error) {
+	backupsAPI, closer, err := u.openBackups(agentConfig)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot open controller backups")
+	}
+	defer closer()
+
+	list, err := backupsAPI.List()
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot list controller backups")
+	}
+	selected, err := selectBackup(list, u.backupId)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	meta, archive, err := backupsAPI.Get(selected.ID())
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "cannot get backup %q", selected.ID())
+	}
+	if meta.Encryption == "" {
+		return meta, archive, nil
+	}
+	defer func() {
+		if err != nil {
+			archive.Close()
+		}
+	}()
+	key, err := u.decryptionKey()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if key.IsZero() {
+		return nil, nil, errors.Errorf("backup %q is encrypted (%s); use --passphrase-file or --private-key-file", meta.ID(), meta.Encryption)
+	}
+	plaintext, err := backups.Decrypt(archive, key)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot decrypt backup")
+	}
+	return meta, readCloser{plaintext, archive}, nil
+}
+
+func (u *UpgradeSimulateCommand) decryptionKey() (backups.DecryptionKey, error) {
+	var key backups.DecryptionKey
+	if u.passphraseFile != "" {
+		data, err := ioutil.ReadFile(u.passphraseFile)
+		if err != nil {
+			return key, errors.Trace(err)
+		}
+		key.Passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+	if u.privateKeyFile != "" {
+		data, err := ioutil.ReadFile(u.privateKeyFile)
+		if err != nil {
+			return key, errors.Trace(err)
+		}
+		key.PrivateKey = string(data)
+	}
+	return key, nil
+}
+
+// readCloser reads from a reader, and closes the underlying closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// selectBackup returns the backup with the given id, or the most
+// recent backup if id is empty. Failed backups have no archive, and
+// cannot be selected.
+func selectBackup(list []*backups.Metadata, id string) (*backups.Metadata, error) {
+	var selected *backups.Metadata
+	for _, meta := range list {
+		if id != "" && meta.ID() != id {
+			continue
+		}
+		if meta.Failure != "" {
+			if id != "" {
+				return nil, errors.Errorf("backup %q failed, and has no archive", id)
+			}
+			continue
+		}
+		if selected == nil || meta.Started.After(selected.Started) {
+			selected = meta
+		}
+	}
+	if selected != nil {
+		return selected, nil
+	}
+	if id != "" {
+		return nil, errors.NotFoundf("backup %q", id)
+	}
+	return nil, errors.New("no backups of the controller to restore; create one with juju create-backup")
+}
+
+// openControllerBackups returns the backups stored by the controller,
+// and a function that releases them.
+func openControllerBackups(agentConfig agent.Config) (backups.Backups, func(), error) {
+	info, ok := agentConfig.MongoInfo()
+	if !ok {
+		return nil, nil, errors.New("no database connection info in agent config; is this a controller?")
+	}
+	st, err := state.Open(agentConfig.Model(), info, mongo.DefaultDialOpts(), nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stor := backups.NewStorage(st)
+	closer := func() {
+		stor.Close()
+		st.Close()
+	}
+	return backups.NewBackups(stor), closer, nil
+}
+
+// mongodProcess is a scratchMongo running as a child process.
+type mongodProcess struct {
+	cmd  *exec.Cmd
+	info *mongo.MongoInfo
+}
+
+// Info is part of the scratchMongo interface.
+func (p *mongodProcess) Info() *mongo.MongoInfo {
+	return p.info
+}
+
+// Stop is part of the scratchMongo interface.
+func (p *mongodProcess) Stop() error {
+	if err := p.cmd.Process.Kill(); err != nil {
+		return errors.Trace(err)
+	}
+	// The mongod was killed, so it always exits with an error.
+	p.cmd.Wait()
+	return nil
+}
+
+// startScratchMongo starts a mongod listening only on localhost, with
+// its data in the given directory, which must be accessible only by
+// the current user. It uses the controller's certificate, and
+// requires authentication with a newly generated admin password,
+// which is never passed on a command line.
+//
+// The admin user is created through mongod's localhost exception
+// before any data is restored, so if another user were to create the
+// first user instead, startScratchMongo fails rather than restoring
+// data they could read.
+func startScratchMongo(agentConfig agent.Config, dir string) (scratchMongo, error) {
+	mongoVersion := agentConfig.MongoVersion()
+	mongod, err := mongo.Path(mongoVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	port, err := freeLocalPort()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dbDir := filepath.Join(dir, "db")
+	if err := os.Mkdir(dbDir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	sharedSecret, err := mongo.GenerateSharedSecret()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keyFile := filepath.Join(dir, "shared-secret")
+	if err := ioutil.WriteFile(keyFile, []byte(sharedSecret), 0600); err != nil {
+		return nil, errors.Trace(err)
+	}
+	password, err := utils.RandomPassword()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := []string{
+		"--dbpath", dbDir,
+		"--bind_ip", "127.0.0.1",
+		"--port", strconv.Itoa(port),
+		"--sslOnNormalPorts",
+		"--sslPEMKeyFile", filepath.Join(agentConfig.DataDir(), "server.pem"),
+		"--sslPEMKeyPassword", "ignored",
+		"--logpath", filepath.Join(dir, "mongod.log"),
+		"--auth",
+		"--keyFile", keyFile,
+		"--quiet",
+	}
+	if mongoVersion.StorageEngine == mongo.WiredTiger {
+		args = append(args, "--storageEngine", "wiredTiger")
+	} else {
+		args = append(args, "--noprealloc", "--smallfiles")
+	}
+	cmd := exec.Command(mongod, args...)
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Annotate(err, "cannot start mongod")
+	}
+	p := &mongodProcess{
+		cmd: cmd,
+		info: &mongo.MongoInfo{
+			Info: mongo.Info{
+				Addrs:  []string{net.JoinHostPort("127.0.0.1", strconv.Itoa(port))},
+				CACert: agentConfig.CACert(),
+			},
+			Password: password,
+		},
+	}
+
+	// Wait for the mongod to accept connections, then create the
+	// admin user.
+	var session *mgo.Session
+	callArgs := defaultCallArgs
+	callArgs.Func = func() error {
+		session, err = mongo.DialWithInfo(p.info.Info, mongo.DefaultDialOpts())
+		return err
+	}
+	if err := retry.Call(callArgs); err != nil {
+		p.Stop()
+		return nil, errors.Annotate(err, "scratch mongod did not start")
+	}
+	defer session.Close()
+	if err := mongo.SetAdminMongoPassword(session, mongo.AdminUser, password); err != nil {
+		p.Stop()
+		return nil, errors.Annotate(err, "cannot create scratch database admin user")
+	}
+	return p, nil
+}
+
+// freeLocalPort returns a port on localhost that nothing is listening
+// on.
+func freeLocalPort() (int, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port, nil
+}
+
+// dialScratchMongo returns a session with the scratch mongod, logged
+// in as its administrator.
+func dialScratchMongo(info *mongo.MongoInfo) (*mgo.Session, error) {
+	session, err := mongo.DialWithInfo(info.Info, mongo.DefaultDialOpts())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := session.DB("admin").Login(mongo.AdminUser, info.Password); err != nil {
+		session.Close()
+		return nil, errors.Annotate(err, "cannot log in to scratch database")
+	}
+	return session, nil
+}
+
+// restoreScratchMongo restores the database dump in dumpDir into the
+// scratch mongod. The dump is read directly, rather than restored with
+// mongorestore, so that the scratch database's password is never
+// passed on a command line. The admin database is not restored, so
+// the controller's database users are not copied; indexes are ensured
+// when the state is opened.
+func restoreScratchMongo(info *mongo.MongoInfo, dumpDir string) error {
+	session, err := dialScratchMongo(info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer session.Close()
+
+	dbDirs, err := ioutil.ReadDir(dumpDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, dbDir := range dbDirs {
+		if !dbDir.IsDir() || dbDir.Name() == "admin" {
+			continue
+		}
+		db := session.DB(dbDir.Name())
+		if err := restoreDatabase(db, filepath.Join(dumpDir, dbDir.Name())); err != nil {
+			return errors.Annotatef(err, "cannot restore database %q", dbDir.Name())
+		}
+	}
+
+	// Backups taken while the controller was running include the
+	// oplog entries written during the dump, which are replayed.
+	oplog := filepath.Join(dumpDir, "oplog.bson")
+	if _, err := os.Stat(oplog); os.IsNotExist(err) {
+		return nil
+	}
+	err = readBSONFile(oplog, func(entry bson.Raw) error {
+		var op struct {
+			Namespace string `bson:"ns"`
+		}
+		if err := entry.Unmarshal(&op); err != nil {
+			return errors.Trace(err)
+		}
+		if strings.HasPrefix(op.Namespace, "admin.") {
+			return nil
+		}
+		return session.Run(bson.D{{"applyOps", []bson.Raw{entry}}}, nil)
+	})
+	return errors.Annotate(err, "cannot replay oplog")
+}
+
+// restoreDatabase inserts the documents of each collection dumped in
+// dir into the database.
+func restoreDatabase(db *mgo.Database, dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".bson") {
+			continue
+		}
+		collection := strings.TrimSuffix(name, ".bson")
+		if strings.HasPrefix(collection, "system.") {
+			continue
+		}
+		var batch []interface{}
+		insert := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			err := db.C(collection).Insert(batch...)
+			batch = batch[:0]
+			return errors.Annotatef(err, "cannot restore collection %q", collection)
+		}
+		err := readBSONFile(filepath.Join(dir, name), func(doc bson.Raw) error {
+			batch = append(batch, doc)
+			if len(batch) < restoreBatchSize {
+				return nil
+			}
+			return insert()
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := insert(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// restoreBatchSize is the number of documents inserted at a time when
+// restoring a collection.
+const restoreBatchSize = 1000
+
+// maxBSONSize is the largest size of a BSON document accepted by mongo,
+// plus room for the oplog entry holding it.
+const maxBSONSize = 16*1024*1024 + 16*1024
+
+// readBSONFile calls f with each document in the named file, which
+// holds a sequence of BSON documents as written by mongodump.
+func readBSONFile(path string, f func(bson.Raw) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Annotatef(err, "cannot read %s", path)
+		}
+		size := binary.LittleEndian.Uint32(header[:])
+		if size < 5 || size > maxBSONSize {
+			return errors.Errorf("invalid document size %d in %s", size, path)
+		}
+		data := make([]byte, size)
+		copy(data, header[:])
+		if _, err := io.ReadFull(r, data[len(header):]); err != nil {
+			return errors.Annotatef(err, "cannot read %s", path)
+		}
+		if err := f(bson.Raw{Kind: 3, Data: data}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// upgradeRehearsal holds the outcome of rehearsing an upgrade.
+type upgradeRehearsal struct {
+	From     version.Number
+	To       version.Number
+	Steps    []upgrades.StepResult
+	Changes  []collectionChanges
+	Duration time.Duration
+
+	// Err holds the error that stopped the upgrade steps, if any.
+	Err error
+}
+
+// rehearseUpgrade runs the upgrade steps from the given version to
+// this one against the scratch mongod described by info, as the
+// controller's database master would. An API server is started on the
+// scratch database for the API upgrade steps. Steps that would act
+// outside the database are reported but not run. The agent config is
+// not written.
+func rehearseUpgrade(agentConfig agent.ConfigSetter, info *mongo.MongoInfo, dir string, from version.Number) (*upgradeRehearsal, error) {
+	st, err := state.Open(agentConfig.Model(), info, mongo.DefaultDialOpts(), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open scratch database")
+	}
+	defer st.Close()
+
+	rehearsal := &upgradeRehearsal{
+		From: from,
+		To:   jujuversion.Current,
+	}
+	if _, err := st.EnsureUpgradeInfo(agentConfig.Tag().Id(), rehearsal.From, rehearsal.To); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	apiConn, stopAPI, err := startScratchAPIServer(st, agentConfig, dir)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot start API server on scratch database")
+	}
+	defer stopAPI()
+
+	db := st.MongoSession().DB(jujuDBName)
+	before, err := snapshotCollections(db)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	started := time.Now()
+	context := upgrades.NewContext(agentConfig, apiConn, st)
+	rehearsal.Steps, rehearsal.Err = upgrades.RehearseUpgrade(from, upgradeSimulateTargets(agentConfig), context)
+	rehearsal.Duration = time.Since(started)
+	after, err := snapshotCollections(db)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rehearsal.Changes = diffCollections(before, after)
+	return rehearsal, nil
+}
+
+// upgradeSimulateTargets returns the upgrade targets of the machine,
+// as the database master.
+func upgradeSimulateTargets(agentConfig agent.Config) []upgrades.Target {
+	targets := []upgrades.Target{upgrades.Controller, upgrades.DatabaseMaster}
+	for _, job := range agentConfig.Jobs() {
+		if job == multiwatcher.JobHostUnits {
+			targets = append(targets, upgrades.HostMachine)
+		}
+	}
+	return targets
+}
+
+// startScratchAPIServer starts an API server on the given state,
+// listening only on localhost, and returns a connection to it as the
+// machine agent, and a function that stops them.
+func startScratchAPIServer(st *state.State, agentConfig agent.Config, dir string) (api.Connection, func(), error) {
+	info, ok := agentConfig.StateServingInfo()
+	if !ok {
+		return nil, nil, errors.New("no state serving info in agent config; is this a controller?")
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	srv, err := apiserver.NewServer(st, lis, apiserver.ServerConfig{
+		Cert:    []byte(info.Cert),
+		Key:     []byte(info.PrivateKey),
+		Tag:     agentConfig.Tag(),
+		DataDir: dir,
+		LogDir:  dir,
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	apiInfo, ok := agentConfig.APIInfo()
+	if !ok {
+		srv.Stop()
+		return nil, nil, errors.New("no API connection info in agent config")
+	}
+	apiInfo.Addrs = []string{lis.Addr().String()}
+	conn, err := api.Open(apiInfo, api.DefaultDialOpts())
+	if err != nil {
+		srv.Stop()
+		return nil, nil, errors.Trace(err)
+	}
+	stop := func() {
+		conn.Close()
+		srv.Stop()
+	}
+	return conn, stop, nil
+}
+
+// jujuDBName is the name of the database holding juju's state.
+const jujuDBName = "juju"
+
+// ignoredCollections holds the collections that change whenever the
+// database does, and so are left out of the reported changes.
+var ignoredCollections = []string{"txns", "txns.log", "txns.stash"}
+
+// collectionSnapshot maps the ids of a collection's documents to a
+// hash of their contents.
+type collectionSnapshot map[string]uint64
+
+// snapshotCollections returns snapshots of all the collections in the
+// given database.
+func snapshotCollections(db *mgo.Database) (map[string]collectionSnapshot, error) {
+	names, err := db.CollectionNames()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list collections")
+	}
+	snapshots := make(map[string]collectionSnapshot)
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") || isIgnoredCollection(name) {
+			continue
+		}
+		snapshot := make(collectionSnapshot)
+		iter := db.C(name).Find(nil).Iter()
+		var doc bson.RawD
+		for iter.Next(&doc) {
+			id, sum := hashDocument(doc)
+			snapshot[id] = sum
+		}
+		if err := iter.Close(); err != nil {
+			return nil, errors.Annotatef(err, "cannot read collection %q", name)
+		}
+		snapshots[name] = snapshot
+	}
+	return snapshots, nil
+}
+
+func isIgnoredCollection(name string) bool {
+	for _, ignored := range ignoredCollections {
+		if name == ignored {
+			return true
+		}
+	}
+	return false
+}
+
+// hashDocument returns the id of the document, and a hash of its
+// contents. The queue of transactions touching the document is left
+// out, since transactions that only assert on the document add to it.
+func hashDocument(doc bson.RawD) (string, uint64) {
+	var id string
+	h := fnv.New64a()
+	for _, elem := range doc {
+		switch elem.Name {
+		case "_id":
+			id = fmt.Sprintf("%d:%x", elem.Value.Kind, elem.Value.Data)
+		case "txn-queue":
+			continue
+		}
+		h.Write([]byte(elem.Name))
+		h.Write([]byte{elem.Value.Kind})
+		h.Write(elem.Value.Data)
+	}
+	return id, h.Sum64()
+}
+
+// collectionChanges counts the documents in a collection that were
+// changed by the upgrade steps.
+type collectionChanges struct {
+	Collection string
+	Inserted   int
+	Updated    int
+	Removed    int
+}
+
+// diffCollections returns the changes between two snapshots of a
+// database, for each collection that changed, ordered by name.
+func diffCollections(before, after map[string]collectionSnapshot) []collectionChanges {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	var result []collectionChanges
+	for name := range names {
+		changes := collectionChanges{Collection: name}
+		for id, sum := range after[name] {
+			if oldSum, ok := before[name][id]; !ok {
+				changes.Inserted++
+			} else if oldSum != sum {
+				changes.Updated++
+			}
+		}
+		for id := range before[name] {
+			if _, ok := after[name][id]; !ok {
+				changes.Removed++
+			}
+		}
+		if changes.Inserted+changes.Updated+changes.Removed > 0 {
+			result = append(result, changes)
+		}
+	}
+	sort.Sort(byCollection(result))
+	return result
+}
+
+type byCollection []collectionChanges
+
+func (s byCollection) Len() int           { return len(s) }
+func (s byCollection) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byCollection) Less(i, j int) bool { return s[i].Collection < s[j].Collection }
+
+// writeUpgradeRehearsal writes a report of the rehearsal to w.
+func writeUpgradeRehearsal(w io.Writer, rehearsal *upgradeRehearsal) error {
+	fmt.Fprintf(w, "Upgrade from %s to %s\n\n", rehearsal.From, rehearsal.To)
+	if len(rehearsal.Steps) == 0 && rehearsal.Err == nil {
+		fmt.Fprintln(w, "No upgrade steps to run.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tTIME\tRESULT")
+	for _, step := range rehearsal.Steps {
+		description := step.Description
+		if step.Irreversible {
+			description += " (irreversible)"
+		}
+		duration, result := formatStepDuration(step.Duration), "ok"
+		if step.NotSimulated {
+			duration, result = "-", "not simulated"
+		} else if step.Err != nil {
+			result = "failed: " + step.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", description, duration, result)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(w)
+
+	if len(rehearsal.Changes) == 0 {
+		fmt.Fprintln(w, "No documents changed.")
+	} else {
+		tw = tabwriter.NewWriter(w, 0, 1, 2, ' ', 0)
+		fmt.Fprintln(tw, "COLLECTION\tINSERTED\tUPDATED\tREMOVED")
+		for _, changes := range rehearsal.Changes {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", changes.Collection, changes.Inserted, changes.Updated, changes.Removed)
+		}
+		if err := tw.Flush(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	fmt.Fprintf(w, "\nUpgrade steps took %s.\n", formatStepDuration(rehearsal.Duration))
+	return nil
+}
+
+func formatStepDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
@@ -0,0 +1,302 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state/backups"
+	bkpstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type UpgradeSimulateSuite struct {
+	testing.BaseSuite
+
+	configFile string
+	meta       *backups.Metadata
+	backups    *bkpstesting.FakeBackups
+	calls      []string
+	rehearsal  *upgradeRehearsal
+}
+
+var _ = gc.Suite(&UpgradeSimulateSuite{})
+
+func (s *UpgradeSimulateSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.calls = nil
+
+	dataDir := c.MkDir()
+	tag := names.NewMachineTag("0")
+	config, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		Paths:             agent.Paths{DataDir: dataDir},
+		Tag:               tag,
+		UpgradedToVersion: jujuversion.Current,
+		StateAddresses:    []string{"localhost:1234"},
+		CACert:            testing.CACert,
+		Password:          "fake",
+		Model:             testing.ModelTag,
+		MongoVersion:      mongo.Mongo32wt,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Write(), jc.ErrorIsNil)
+	s.configFile = agent.ConfigPath(dataDir, tag)
+
+	s.meta = bkpstesting.NewMetadata()
+	s.meta.Origin.Version = version.MustParse("1.25.5")
+	archive, err := bkpstesting.NewArchiveBasic(s.meta)
+	c.Assert(err, jc.ErrorIsNil)
+	s.backups = &bkpstesting.FakeBackups{
+		Meta:     s.meta,
+		MetaList: []*backups.Metadata{s.meta},
+		Archive:  ioutil.NopCloser(archive),
+	}
+	s.rehearsal = &upgradeRehearsal{
+		From: version.MustParse("1.25.5"),
+		To:   version.MustParse("2.0.0"),
+		Steps: []upgrades.StepResult{{
+			Description:  "add model UUID to collections",
+			Irreversible: true,
+			Duration:     1500 * time.Millisecond,
+		}, {
+			Description: "migrate charm archives",
+			Duration:    250 * time.Millisecond,
+		}, {
+			Description:  "provider side upgrades",
+			NotSimulated: true,
+		}},
+		Changes: []collectionChanges{
+			{Collection: "charms", Updated: 3},
+			{Collection: "settings", Inserted: 1, Removed: 2},
+		},
+		Duration: 1750 * time.Millisecond,
+	}
+}
+
+type fakeScratchMongo struct {
+	s *UpgradeSimulateSuite
+}
+
+func (m *fakeScratchMongo) Info() *mongo.MongoInfo {
+	return &mongo.MongoInfo{
+		Info:     mongo.Info{Addrs: []string{"127.0.0.1:37017"}},
+		Password: "sekrit",
+	}
+}
+
+func (m *fakeScratchMongo) Stop() error {
+	m.s.calls = append(m.s.calls, "Stop")
+	return nil
+}
+
+func (s *UpgradeSimulateSuite) newCommand() *UpgradeSimulateCommand {
+	return &UpgradeSimulateCommand{
+		openBackups: func(agent.Config) (backups.Backups, func(), error) {
+			s.calls = append(s.calls, "OpenBackups")
+			return s.backups, func() {}, nil
+		},
+		createTempDir: func() (string, error) {
+			return ioutil.TempDir("", "")
+		},
+		startMongo: func(_ agent.Config, dir string) (scratchMongo, error) {
+			s.calls = append(s.calls, "StartMongo")
+			return &fakeScratchMongo{s}, nil
+		},
+		restoreDump: func(info *mongo.MongoInfo, dumpDir string) error {
+			s.calls = append(s.calls, "RestoreDump "+info.Addrs[0]+" "+info.Password)
+			return nil
+		},
+		rehearse: func(_ agent.ConfigSetter, info *mongo.MongoInfo, dir string, from version.Number) (*upgradeRehearsal, error) {
+			s.calls = append(s.calls, "Rehearse "+info.Addrs[0]+" "+info.Password+" "+from.String())
+			return s.rehearsal, nil
+		},
+	}
+}
+
+func (s *UpgradeSimulateSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	args = append([]string{"--configfile", s.configFile}, args...)
+	return testing.RunCommand(c, s.newCommand(), args...)
+}
+
+func (s *UpgradeSimulateSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&UpgradeSimulateCommand{}, []string{"--from", "foo"})
+	c.Assert(err, gc.ErrorMatches, `invalid --from version "foo": .*`)
+
+	err = testing.InitCommand(&UpgradeSimulateCommand{}, []string{"--passphrase-file", "a", "--private-key-file", "b"})
+	c.Assert(err, gc.ErrorMatches, "only one of --passphrase-file and --private-key-file may be used")
+
+	err = testing.InitCommand(&UpgradeSimulateCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *UpgradeSimulateSuite) TestRun(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.calls, jc.DeepEquals, []string{
+		"OpenBackups",
+		"StartMongo",
+		"RestoreDump 127.0.0.1:37017 sekrit",
+		"Rehearse 127.0.0.1:37017 sekrit 1.25.5",
+		"Stop",
+	})
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+Upgrade from 1.25.5 to 2.0.0
+
+STEP                                          TIME    RESULT
+add model UUID to collections (irreversible)  1.500s  ok
+migrate charm archives                        0.250s  ok
+provider side upgrades                        -       not simulated
+
+COLLECTION  INSERTED  UPDATED  REMOVED
+charms      0         3        0
+settings    1         0        2
+
+Upgrade steps took 1.750s.
+`[1:])
+}
+
+func (s *UpgradeSimulateSuite) TestRunFrom(c *gc.C) {
+	_, err := s.run(c, "--from", "1.25.0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.calls, jc.Contains, "Rehearse 127.0.0.1:37017 sekrit 1.25.0")
+}
+
+func (s *UpgradeSimulateSuite) TestRunStepFailed(c *gc.C) {
+	s.rehearsal.Steps[1].Err = errors.New("boom")
+	s.rehearsal.Err = errors.New("migrate charm archives: boom")
+	ctx, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "upgrade steps failed: migrate charm archives: boom")
+	c.Assert(testing.Stdout(ctx), jc.Contains, "migrate charm archives                        0.250s  failed: boom\n")
+	c.Assert(s.calls[len(s.calls)-1], gc.Equals, "Stop")
+}
+
+func (s *UpgradeSimulateSuite) TestRunNoSteps(c *gc.C) {
+	s.rehearsal.Steps = nil
+	s.rehearsal.Changes = nil
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "Upgrade from 1.25.5 to 2.0.0\n\nNo upgrade steps to run.\n")
+}
+
+func (s *UpgradeSimulateSuite) TestRunRestoreFailed(c *gc.C) {
+	command := s.newCommand()
+	command.restoreDump = func(*mongo.MongoInfo, string) error {
+		return errors.New("boom")
+	}
+	_, err := testing.RunCommand(c, command, "--configfile", s.configFile)
+	c.Assert(err, gc.ErrorMatches, "cannot restore backup into scratch database: boom")
+	c.Assert(s.calls, jc.DeepEquals, []string{"OpenBackups", "StartMongo", "Stop"})
+}
+
+func (s *UpgradeSimulateSuite) TestRunEncryptedWithoutKey(c *gc.C) {
+	s.meta.Encryption = backups.EncryptionPassphrase
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, `backup ".*" is encrypted \(scrypt-aes256-gcm\); use --passphrase-file or --private-key-file`)
+	c.Assert(s.calls, jc.DeepEquals, []string{"OpenBackups"})
+}
+
+func newSelectBackupMetadata(id string, started time.Time, failure string) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Failure = failure
+	return meta
+}
+
+func (s *UpgradeSimulateSuite) TestSelectBackup(c *gc.C) {
+	t0 := time.Date(2016, 5, 1, 0, 0, 0, 0, time.UTC)
+	list := []*backups.Metadata{
+		newSelectBackupMetadata("old", t0, ""),
+		newSelectBackupMetadata("failed", t0.Add(2*time.Hour), "disk full"),
+		newSelectBackupMetadata("latest", t0.Add(time.Hour), ""),
+	}
+
+	meta, err := selectBackup(list, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.ID(), gc.Equals, "latest")
+
+	meta, err = selectBackup(list, "old")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(meta.ID(), gc.Equals, "old")
+
+	_, err = selectBackup(list, "failed")
+	c.Assert(err, gc.ErrorMatches, `backup "failed" failed, and has no archive`)
+
+	_, err = selectBackup(list, "missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = selectBackup(list[1:2], "")
+	c.Assert(err, gc.ErrorMatches, "no backups of the controller to restore; .*")
+}
+
+func (s *UpgradeSimulateSuite) TestDiffCollections(c *gc.C) {
+	before := map[string]collectionSnapshot{
+		"machines":     {"a": 1, "b": 2, "c": 3},
+		"unchanged":    {"a": 1},
+		"dropped":      {"a": 1},
+		"applications": {"a": 1},
+	}
+	after := map[string]collectionSnapshot{
+		"machines":     {"a": 1, "b": 20, "d": 4},
+		"unchanged":    {"a": 1},
+		"created":      {"a": 1, "b": 2},
+		"applications": {"a": 1},
+	}
+	c.Assert(diffCollections(before, after), jc.DeepEquals, []collectionChanges{
+		{Collection: "created", Inserted: 2},
+		{Collection: "dropped", Removed: 1},
+		{Collection: "machines", Inserted: 1, Updated: 1, Removed: 1},
+	})
+}
+
+func (s *UpgradeSimulateSuite) TestReadBSONFile(c *gc.C) {
+	var data []byte
+	for _, doc := range []bson.M{{"_id": "a"}, {"_id": "b", "n": 2}} {
+		raw, err := bson.Marshal(doc)
+		c.Assert(err, jc.ErrorIsNil)
+		data = append(data, raw...)
+	}
+	path := filepath.Join(c.MkDir(), "machines.bson")
+	err := ioutil.WriteFile(path, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = readBSONFile(path, func(raw bson.Raw) error {
+		var doc bson.M
+		if err := raw.Unmarshal(&doc); err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, jc.DeepEquals, []bson.M{{"_id": "a"}, {"_id": "b", "n": 2}})
+}
+
+func (s *UpgradeSimulateSuite) TestReadBSONFileTruncated(c *gc.C) {
+	raw, err := bson.Marshal(bson.M{"_id": "a"})
+	c.Assert(err, jc.ErrorIsNil)
+	path := filepath.Join(c.MkDir(), "machines.bson")
+	err = ioutil.WriteFile(path, raw[:len(raw)-1], 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = readBSONFile(path, func(bson.Raw) error { return nil })
+	c.Assert(err, gc.ErrorMatches, "cannot read .*machines.bson: unexpected EOF")
+}
@@ -0,0 +1,52 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmstate holds the rules for the key/value data that a
+// unit's charm persists between hooks.
+package charmstate
+
+import (
+	"github.com/juju/errors"
+)
+
+const (
+	// MaxKeySize is the maximum size, in bytes, of a key in a unit's
+	// charm state.
+	MaxKeySize = 256
+
+	// MaxSize is the maximum total size, in bytes, of the keys and
+	// values in a unit's charm state.
+	MaxSize = 64 * 1024
+)
+
+// Validate returns an error if the supplied charm state has an empty
+// or oversized key, or exceeds MaxSize in total.
+func Validate(charmState map[string]string) error {
+	size := 0
+	for key, value := range charmState {
+		if err := ValidateKey(key); err != nil {
+			return errors.Trace(err)
+		}
+		size += len(key) + len(value)
+	}
+	if size > MaxSize {
+		return errors.Errorf(
+			"charm state of %d bytes exceeds limit of %d bytes", size, MaxSize,
+		)
+	}
+	return nil
+}
+
+// ValidateKey returns an error if the supplied key is empty or larger
+// than MaxKeySize.
+func ValidateKey(key string) error {
+	if key == "" {
+		return errors.NotValidf("empty key")
+	}
+	if len(key) > MaxKeySize {
+		return errors.Errorf(
+			"key of %d bytes exceeds limit of %d bytes", len(key), MaxKeySize,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmstate_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/charmstate"
+)
+
+type CharmStateSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CharmStateSuite{})
+
+func (s *CharmStateSuite) TestValidate(c *gc.C) {
+	err := charmstate.Validate(map[string]string{
+		"key":                    "value",
+		strings.Repeat("k", 256): "",
+		"big":                    strings.Repeat("v", 60*1024),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmStateSuite) TestValidateEmptyKey(c *gc.C) {
+	err := charmstate.Validate(map[string]string{"": "value"})
+	c.Assert(err, gc.ErrorMatches, "empty key not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *CharmStateSuite) TestValidateKeyTooLarge(c *gc.C) {
+	err := charmstate.Validate(map[string]string{strings.Repeat("k", 257): "value"})
+	c.Assert(err, gc.ErrorMatches, "key of 257 bytes exceeds limit of 256 bytes")
+}
+
+func (s *CharmStateSuite) TestValidateTooLarge(c *gc.C) {
+	err := charmstate.Validate(map[string]string{
+		"a": strings.Repeat("v", 32*1024),
+		"b": strings.Repeat("v", 32*1024),
+	})
+	c.Assert(err, gc.ErrorMatches, "charm state of 65538 bytes exceeds limit of 65536 bytes")
+}
@@ -217,6 +217,8 @@ type Service interface {
 	ForceCharm() bool
 	Exposed() bool
 	MinUnits() int
+	StatusAggregation() string
+	HookTimeout() time.Duration
 
 	Settings() map[string]interface{}
 	SettingsRefCount() int
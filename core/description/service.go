@@ -5,6 +5,7 @@ package description
 
 import (
 	"encoding/base64"
+	"time"
 
 	"github.com/juju/utils/set"
 
@@ -32,6 +33,9 @@ type service struct {
 	Exposed_    bool `yaml:"exposed,omitempty"`
 	MinUnits_   int  `yaml:"min-units,omitempty"`
 
+	StatusAggregation_ string        `yaml:"status-aggregation,omitempty"`
+	HookTimeout_       time.Duration `yaml:"hook-timeout,omitempty"`
+
 	Status_        *status `yaml:"status"`
 	StatusHistory_ `yaml:"status-history"`
 
@@ -64,6 +68,8 @@ type ServiceArgs struct {
 	ForceCharm           bool
 	Exposed              bool
 	MinUnits             int
+	StatusAggregation    string
+	HookTimeout          time.Duration
 	Settings             map[string]interface{}
 	SettingsRefCount     int
 	Leader               string
@@ -83,6 +89,8 @@ func newService(args ServiceArgs) *service {
 		ForceCharm_:           args.ForceCharm,
 		Exposed_:              args.Exposed,
 		MinUnits_:             args.MinUnits,
+		StatusAggregation_:    args.StatusAggregation,
+		HookTimeout_:          args.HookTimeout,
 		Settings_:             args.Settings,
 		SettingsRefCount_:     args.SettingsRefCount,
 		Leader_:               args.Leader,
@@ -144,6 +152,16 @@ func (s *service) MinUnits() int {
 	return s.MinUnits_
 }
 
+// StatusAggregation implements Service.
+func (s *service) StatusAggregation() string {
+	return s.StatusAggregation_
+}
+
+// HookTimeout implements Service.
+func (s *service) HookTimeout() time.Duration {
+	return s.HookTimeout_
+}
+
 // Settings implements Service.
 func (s *service) Settings() map[string]interface{} {
 	return s.Settings_
@@ -308,6 +326,8 @@ func importServiceV1(source map[string]interface{}) (*service, error) {
 		"force-charm":         schema.Bool(),
 		"exposed":             schema.Bool(),
 		"min-units":           schema.Int(),
+		"status-aggregation":  schema.String(),
+		"hook-timeout":        schema.String(),
 		"status":              schema.StringMap(schema.Any()),
 		"settings":            schema.StringMap(schema.Any()),
 		"settings-refcount":   schema.Int(),
@@ -318,12 +338,14 @@ func importServiceV1(source map[string]interface{}) (*service, error) {
 	}
 
 	defaults := schema.Defaults{
-		"subordinate":   false,
-		"force-charm":   false,
-		"exposed":       false,
-		"min-units":     int64(0),
-		"leader":        "",
-		"metrics-creds": "",
+		"subordinate":        false,
+		"force-charm":        false,
+		"exposed":            false,
+		"min-units":          int64(0),
+		"status-aggregation": "",
+		"hook-timeout":       "",
+		"leader":             "",
+		"metrics-creds":      "",
 	}
 	addAnnotationSchema(fields, defaults)
 	addConstraintsSchema(fields, defaults)
@@ -347,6 +369,7 @@ func importServiceV1(source map[string]interface{}) (*service, error) {
 		ForceCharm_:           valid["force-charm"].(bool),
 		Exposed_:              valid["exposed"].(bool),
 		MinUnits_:             int(valid["min-units"].(int64)),
+		StatusAggregation_:    valid["status-aggregation"].(string),
 		Settings_:             valid["settings"].(map[string]interface{}),
 		SettingsRefCount_:     int(valid["settings-refcount"].(int64)),
 		Leader_:               valid["leader"].(string),
@@ -366,6 +389,15 @@ func importServiceV1(source map[string]interface{}) (*service, error) {
 		result.Constraints_ = constraints
 	}
 
+	// The hook timeout is serialized in its string form.
+	if hookTimeout := valid["hook-timeout"].(string); hookTimeout != "" {
+		d, err := time.ParseDuration(hookTimeout)
+		if err != nil {
+			return nil, errors.Annotate(err, "hook timeout not valid")
+		}
+		result.HookTimeout_ = d
+	}
+
 	encodedCreds := valid["metrics-creds"].(string)
 	// The model stores the creds encoded, but we want to make sure that
 	// we are storing something that can be decoded.
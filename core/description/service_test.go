@@ -4,6 +4,8 @@
 package description
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -111,6 +113,8 @@ func (s *ServiceSerializationSuite) TestNewService(c *gc.C) {
 		ForceCharm:           true,
 		Exposed:              true,
 		MinUnits:             42, // no judgement is made by the migration code
+		StatusAggregation:    "majority",
+		HookTimeout:          10 * time.Minute,
 		Settings: map[string]interface{}{
 			"key": "value",
 		},
@@ -133,6 +137,8 @@ func (s *ServiceSerializationSuite) TestNewService(c *gc.C) {
 	c.Assert(service.ForceCharm(), jc.IsTrue)
 	c.Assert(service.Exposed(), jc.IsTrue)
 	c.Assert(service.MinUnits(), gc.Equals, 42)
+	c.Assert(service.StatusAggregation(), gc.Equals, "majority")
+	c.Assert(service.HookTimeout(), gc.Equals, 10*time.Minute)
 	c.Assert(service.Settings(), jc.DeepEquals, args.Settings)
 	c.Assert(service.SettingsRefCount(), gc.Equals, 1)
 	c.Assert(service.Leader(), gc.Equals, "magic/1")
@@ -180,6 +186,13 @@ func (s *ServiceSerializationSuite) TestParsingSerializedData(c *gc.C) {
 	c.Assert(service, jc.DeepEquals, svc)
 }
 
+func (s *ServiceSerializationSuite) TestHookTimeout(c *gc.C) {
+	initial := minimalService()
+	initial.HookTimeout_ = 90 * time.Second
+	service := s.exportImport(c, initial)
+	c.Assert(service.HookTimeout(), gc.Equals, 90*time.Second)
+}
+
 func (s *ServiceSerializationSuite) TestAnnotations(c *gc.C) {
 	initial := minimalService()
 	annotations := map[string]string{
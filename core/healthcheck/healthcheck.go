@@ -0,0 +1,192 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package healthcheck parses the health checks that a charm declares
+// under the "health-checks" key of its metadata.yaml. The checks are
+// validated when a charm is added to a model, and evaluated by the
+// unit agent's healthcheck worker.
+package healthcheck
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultInterval is the time between evaluations of a check
+	// that does not specify an interval.
+	DefaultInterval = 30 * time.Second
+
+	// DefaultTimeout is the time allowed for a check that does not
+	// specify a timeout; it is reduced to the check's interval if
+	// that is shorter.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultThreshold is the number of consecutive failures of a
+	// check that does not specify a threshold, after which the unit
+	// is considered unhealthy.
+	DefaultThreshold = 3
+)
+
+// Check describes a single health check declared by a charm. Exactly
+// one of HTTP, TCP and Exec is set.
+type Check struct {
+	// Name identifies the check within the charm.
+	Name string
+
+	// HTTP holds a URL; the check passes if a GET request to it
+	// returns a 2xx or 3xx response.
+	HTTP string
+
+	// TCP holds a host:port address; the check passes if a TCP
+	// connection can be made to it.
+	TCP string
+
+	// Exec holds a shell command; the check passes if it exits with
+	// status 0 when run from the charm directory.
+	Exec string
+
+	// Interval holds the time between evaluations of the check.
+	Interval time.Duration
+
+	// Timeout holds the time allowed for the check to complete.
+	Timeout time.Duration
+
+	// Threshold holds the number of consecutive failures after which
+	// the unit is considered unhealthy.
+	Threshold int
+}
+
+// checkDoc is the representation of a health check in a charm's
+// metadata.yaml.
+type checkDoc struct {
+	HTTP      string `yaml:"http"`
+	TCP       string `yaml:"tcp"`
+	Exec      string `yaml:"exec"`
+	Interval  string `yaml:"interval"`
+	Timeout   string `yaml:"timeout"`
+	Threshold *int   `yaml:"threshold"`
+}
+
+// ReadChecks returns the health checks declared in the metadata of the
+// charm in the supplied directory, sorted by name.
+func ReadChecks(charmDir string) ([]Check, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, "metadata.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ParseChecks(data)
+}
+
+// ReadArchiveChecks returns the health checks declared in the metadata
+// of the charm archive at the supplied path, sorted by name.
+func ReadArchiveChecks(archivePath string) ([]Check, error) {
+	zipr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open charm archive")
+	}
+	defer zipr.Close()
+	for _, f := range zipr.File {
+		if f.Name != "metadata.yaml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read charm metadata")
+		}
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read charm metadata")
+		}
+		return ParseChecks(data)
+	}
+	return nil, nil
+}
+
+// ParseChecks returns the health checks declared under the
+// "health-checks" key of the supplied charm metadata, sorted by name.
+func ParseChecks(metadata []byte) ([]Check, error) {
+	var meta struct {
+		HealthChecks map[string]checkDoc `yaml:"health-checks"`
+	}
+	if err := goyaml.Unmarshal(metadata, &meta); err != nil {
+		return nil, errors.Annotate(err, "cannot parse health checks")
+	}
+	var checks []Check
+	for name, doc := range meta.HealthChecks {
+		check, err := parseCheck(name, doc)
+		if err != nil {
+			return nil, errors.Annotatef(err, "health check %q", name)
+		}
+		checks = append(checks, check)
+	}
+	sort.Sort(checksByName(checks))
+	return checks, nil
+}
+
+func parseCheck(name string, doc checkDoc) (Check, error) {
+	check := Check{
+		Name:      name,
+		HTTP:      doc.HTTP,
+		TCP:       doc.TCP,
+		Exec:      doc.Exec,
+		Interval:  DefaultInterval,
+		Threshold: DefaultThreshold,
+	}
+	kinds := 0
+	for _, value := range []string{doc.HTTP, doc.TCP, doc.Exec} {
+		if value != "" {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return Check{}, errors.New("expected exactly one of http, tcp or exec")
+	}
+	if doc.Interval != "" {
+		interval, err := time.ParseDuration(doc.Interval)
+		if err != nil {
+			return Check{}, errors.Annotate(err, "invalid interval")
+		}
+		if interval <= 0 {
+			return Check{}, errors.NotValidf("interval %q", doc.Interval)
+		}
+		check.Interval = interval
+	}
+	check.Timeout = DefaultTimeout
+	if doc.Timeout != "" {
+		timeout, err := time.ParseDuration(doc.Timeout)
+		if err != nil {
+			return Check{}, errors.Annotate(err, "invalid timeout")
+		}
+		if timeout <= 0 {
+			return Check{}, errors.NotValidf("timeout %q", doc.Timeout)
+		}
+		check.Timeout = timeout
+	}
+	if check.Timeout > check.Interval {
+		check.Timeout = check.Interval
+	}
+	if doc.Threshold != nil {
+		if *doc.Threshold < 1 {
+			return Check{}, errors.NotValidf("threshold %d", *doc.Threshold)
+		}
+		check.Threshold = *doc.Threshold
+	}
+	return check, nil
+}
+
+type checksByName []Check
+
+func (c checksByName) Len() int           { return len(c) }
+func (c checksByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+func (c checksByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
@@ -0,0 +1,151 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/healthcheck"
+)
+
+type ChecksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ChecksSuite{})
+
+func (*ChecksSuite) TestParseChecks(c *gc.C) {
+	checks, err := healthcheck.ParseChecks([]byte(`
+name: wordpress
+summary: a blog
+health-checks:
+  web:
+    http: http://localhost:8080/health
+    interval: 10s
+    timeout: 2s
+    threshold: 1
+  db:
+    tcp: localhost:3306
+  custom:
+    exec: bin/check-health
+    interval: 5s
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, jc.DeepEquals, []healthcheck.Check{{
+		Name:      "custom",
+		Exec:      "bin/check-health",
+		Interval:  5 * time.Second,
+		Timeout:   5 * time.Second,
+		Threshold: healthcheck.DefaultThreshold,
+	}, {
+		Name:      "db",
+		TCP:       "localhost:3306",
+		Interval:  healthcheck.DefaultInterval,
+		Timeout:   healthcheck.DefaultTimeout,
+		Threshold: healthcheck.DefaultThreshold,
+	}, {
+		Name:      "web",
+		HTTP:      "http://localhost:8080/health",
+		Interval:  10 * time.Second,
+		Timeout:   2 * time.Second,
+		Threshold: 1,
+	}})
+}
+
+func (*ChecksSuite) TestParseChecksNone(c *gc.C) {
+	checks, err := healthcheck.ParseChecks([]byte("name: wordpress\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 0)
+}
+
+func (*ChecksSuite) TestParseChecksInvalid(c *gc.C) {
+	for i, test := range []struct {
+		check string
+		err   string
+	}{{
+		check: "{}",
+		err:   `health check "bad": expected exactly one of http, tcp or exec`,
+	}, {
+		check: `{http: "http://localhost", tcp: "localhost:80"}`,
+		err:   `health check "bad": expected exactly one of http, tcp or exec`,
+	}, {
+		check: `{tcp: "localhost:80", interval: often}`,
+		err:   `health check "bad": invalid interval: time: invalid duration "?often"?`,
+	}, {
+		check: `{tcp: "localhost:80", interval: -1s}`,
+		err:   `health check "bad": interval "-1s" not valid`,
+	}, {
+		check: `{tcp: "localhost:80", timeout: 0s}`,
+		err:   `health check "bad": timeout "0s" not valid`,
+	}, {
+		check: `{tcp: "localhost:80", threshold: 0}`,
+		err:   `health check "bad": threshold 0 not valid`,
+	}} {
+		c.Logf("test %d: %s", i, test.check)
+		_, err := healthcheck.ParseChecks([]byte("health-checks:\n  bad: " + test.check + "\n"))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (*ChecksSuite) TestReadChecks(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(`
+health-checks:
+  db:
+    tcp: localhost:3306
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	checks, err := healthcheck.ReadChecks(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 1)
+	c.Assert(checks[0].Name, gc.Equals, "db")
+}
+
+func (*ChecksSuite) TestReadChecksNoMetadata(c *gc.C) {
+	checks, err := healthcheck.ReadChecks(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 0)
+}
+
+func (*ChecksSuite) TestReadArchiveChecks(c *gc.C) {
+	path := writeCharmArchive(c, `
+health-checks:
+  web:
+    http: http://localhost:8080/health
+`)
+	checks, err := healthcheck.ReadArchiveChecks(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 1)
+	c.Assert(checks[0].Name, gc.Equals, "web")
+}
+
+func (*ChecksSuite) TestReadArchiveChecksInvalid(c *gc.C) {
+	path := writeCharmArchive(c, "health-checks:\n  web:\n    interval: 10s\n")
+	_, err := healthcheck.ReadArchiveChecks(path)
+	c.Assert(err, gc.ErrorMatches, `health check "web": expected exactly one of http, tcp or exec`)
+}
+
+// writeCharmArchive writes a zip archive holding the supplied
+// metadata.yaml, and returns its path.
+func writeCharmArchive(c *gc.C, metadata string) string {
+	path := filepath.Join(c.MkDir(), "charm.zip")
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	zipw := zip.NewWriter(f)
+	w, err := zipw.Create("metadata.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(metadata))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zipw.Close(), jc.ErrorIsNil)
+	return path
+}
@@ -0,0 +1,122 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hookstats summarizes the timings of the hooks, actions and
+// juju-run commands executed by a unit agent.
+package hookstats
+
+import (
+	"sort"
+	"time"
+)
+
+// Execution describes a single execution of a hook, action or juju-run
+// commands by a unit agent.
+type Execution struct {
+	Kind      string
+	Name      string
+	Started   time.Time
+	Duration  time.Duration
+	ExitCode  int
+	ToolCalls map[string]int
+}
+
+// Stats holds timing statistics for the executions of a single hook,
+// action or juju-run.
+type Stats struct {
+	Kind       string
+	Name       string
+	Executions int
+	Failures   int
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	Total      time.Duration
+	LastRun    time.Time
+	ToolCalls  map[string]int
+}
+
+// Summarize returns statistics for the supplied executions, grouped by
+// kind and name and sorted by the longest total execution time first.
+// Executions with a non-zero exit code are counted as failures.
+func Summarize(executions []Execution) []Stats {
+	type key struct {
+		kind, name string
+	}
+	grouped := make(map[key][]Execution)
+	for _, execution := range executions {
+		k := key{execution.Kind, execution.Name}
+		grouped[k] = append(grouped[k], execution)
+	}
+
+	var stats []Stats
+	for k, group := range grouped {
+		durations := make([]time.Duration, len(group))
+		s := Stats{
+			Kind:       k.kind,
+			Name:       k.name,
+			Executions: len(group),
+		}
+		toolCalls := make(map[string]int)
+		for i, execution := range group {
+			durations[i] = execution.Duration
+			s.Total += execution.Duration
+			if execution.ExitCode != 0 {
+				s.Failures++
+			}
+			if execution.Started.After(s.LastRun) {
+				s.LastRun = execution.Started
+			}
+			for tool, count := range execution.ToolCalls {
+				toolCalls[tool] += count
+			}
+		}
+		sort.Sort(durationSlice(durations))
+		s.P50 = percentile(durations, 50)
+		s.P90 = percentile(durations, 90)
+		s.P99 = percentile(durations, 99)
+		s.Max = durations[len(durations)-1]
+		if len(toolCalls) > 0 {
+			s.ToolCalls = toolCalls
+		}
+		stats = append(stats, s)
+	}
+	sort.Sort(statsByTotal(stats))
+	return stats
+}
+
+// percentile returns the p-th percentile of the supplied sorted
+// durations, using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+type durationSlice []time.Duration
+
+func (s durationSlice) Len() int           { return len(s) }
+func (s durationSlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s durationSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// statsByTotal sorts stats by descending total execution time, and
+// then by kind and name.
+type statsByTotal []Stats
+
+func (s statsByTotal) Len() int      { return len(s) }
+func (s statsByTotal) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s statsByTotal) Less(i, j int) bool {
+	if s[i].Total != s[j].Total {
+		return s[i].Total > s[j].Total
+	}
+	if s[i].Kind != s[j].Kind {
+		return s[i].Kind < s[j].Kind
+	}
+	return s[i].Name < s[j].Name
+}
@@ -0,0 +1,78 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hookstats_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/hookstats"
+)
+
+type hookStatsSuite struct{}
+
+var _ = gc.Suite(&hookStatsSuite{})
+
+func (s *hookStatsSuite) TestSummarize(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	var executions []hookstats.Execution
+	for i := 1; i <= 10; i++ {
+		executions = append(executions, hookstats.Execution{
+			Kind:      "hook",
+			Name:      "update-status",
+			Started:   started.Add(time.Duration(i) * 5 * time.Minute),
+			Duration:  time.Duration(i) * time.Second,
+			ToolCalls: map[string]int{"status-set": 1},
+		})
+	}
+	executions = append(executions, hookstats.Execution{
+		Kind:     "action",
+		Name:     "backup",
+		Started:  started,
+		Duration: 90 * time.Second,
+		ExitCode: 1,
+	})
+
+	stats := hookstats.Summarize(executions)
+	c.Assert(stats, jc.DeepEquals, []hookstats.Stats{{
+		Kind:       "action",
+		Name:       "backup",
+		Executions: 1,
+		Failures:   1,
+		P50:        90 * time.Second,
+		P90:        90 * time.Second,
+		P99:        90 * time.Second,
+		Max:        90 * time.Second,
+		Total:      90 * time.Second,
+		LastRun:    started,
+	}, {
+		Kind:       "hook",
+		Name:       "update-status",
+		Executions: 10,
+		P50:        5 * time.Second,
+		P90:        9 * time.Second,
+		P99:        10 * time.Second,
+		Max:        10 * time.Second,
+		Total:      55 * time.Second,
+		LastRun:    started.Add(50 * time.Minute),
+		ToolCalls:  map[string]int{"status-set": 10},
+	}})
+}
+
+func (s *hookStatsSuite) TestSummarizeNone(c *gc.C) {
+	c.Assert(hookstats.Summarize(nil), gc.HasLen, 0)
+}
+
+func (s *hookStatsSuite) TestPercentile(c *gc.C) {
+	var durations []time.Duration
+	for i := 1; i <= 100; i++ {
+		durations = append(durations, time.Duration(i))
+	}
+	c.Assert(hookstats.Percentile(durations, 50), gc.Equals, time.Duration(50))
+	c.Assert(hookstats.Percentile(durations, 99), gc.Equals, time.Duration(99))
+	c.Assert(hookstats.Percentile(durations[:1], 99), gc.Equals, time.Duration(1))
+	c.Assert(hookstats.Percentile(nil, 50), gc.Equals, time.Duration(0))
+}
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"net/url"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/utils/proxy"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
 	"gopkg.in/juju/charmrepo.v2-unstable"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/macaroon-bakery.v1/bakery"
@@ -226,8 +228,63 @@ const (
 	// machine worker not to discover any machine addresses
 	// on start up.
 	IgnoreMachineAddresses = "ignore-machine-addresses"
+
+	// JujuManagedSpaces, when true, allows spaces to be defined and
+	// used on providers without native support for spaces, using
+	// subnets discovered from machines' network configuration.
+	JujuManagedSpaces = "juju-managed-spaces"
+
+	// BackupIntervalKey, when set to a positive duration, causes the
+	// controller to take a backup automatically at that interval.
+	BackupIntervalKey = "backup-interval"
+
+	// BackupRetentionCountKey, when set to a positive integer, limits
+	// the number of scheduled backups kept by the controller.
+	BackupRetentionCountKey = "backup-retention-count"
+
+	// BackupRetentionAgeKey, when set to a positive duration, causes
+	// scheduled backups older than that to be removed.
+	BackupRetentionAgeKey = "backup-retention-age"
+
+	// BackupEncryptionPublicKeyKey, when set to a PEM-encoded RSA
+	// public key, causes backups to be encrypted to that key unless
+	// another key is given when the backup is created.
+	BackupEncryptionPublicKeyKey = "backup-encryption-public-key"
+
+	// BackupS3BucketKey, when set, causes each completed backup to be
+	// copied to the named bucket in S3-compatible object storage.
+	// The remaining BackupS3 attributes describe how to reach it.
+	BackupS3BucketKey    = "backup-s3-bucket"
+	BackupS3EndpointKey  = "backup-s3-endpoint"
+	BackupS3RegionKey    = "backup-s3-region"
+	BackupS3PrefixKey    = "backup-s3-prefix"
+	BackupS3AccessKeyKey = "backup-s3-access-key"
+	BackupS3SecretKeyKey = "backup-s3-secret-key"
+
+	// HookTimeoutKey, when set to a positive duration, causes charm
+	// hooks that run for longer than that to be killed and treated as
+	// failed. Services may override it.
+	HookTimeoutKey = "hook-timeout"
+
+	// HookRetryMaxAttemptsKey, when set to a positive integer, limits
+	// the number of times a failed hook is automatically retried.
+	HookRetryMaxAttemptsKey = "hook-retry-max-attempts"
+
+	// HookRetryMinDelayKey and HookRetryMaxDelayKey bound the
+	// exponential backoff between automatic retries of a failed hook.
+	HookRetryMinDelayKey = "hook-retry-min-delay"
+	HookRetryMaxDelayKey = "hook-retry-max-delay"
+
+	// HookRetryKindsKey, when set, holds a comma-separated list of the
+	// hook kinds (e.g. "install,relation-changed") that are retried
+	// automatically when they fail.
+	HookRetryKindsKey = "hook-retry-kinds"
 )
 
+// DefaultBackupS3Region is the region used for backup object storage
+// when none is configured.
+const DefaultBackupS3Region = "us-east-1"
+
 // ParseHarvestMode parses description of harvesting method and
 // returns the representation.
 func ParseHarvestMode(description string) (HarvestMode, error) {
@@ -679,6 +736,35 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
 	}
 
+	// Check the backup schedule settings, when set.
+	for _, attr := range []string{BackupIntervalKey, BackupRetentionAgeKey} {
+		if _, err := cfg.duration(attr); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if count := cfg.BackupRetentionCount(); count < 0 {
+		return errors.Errorf("%s: expected positive integer, got %v", BackupRetentionCountKey, count)
+	}
+	if err := validateHookSettings(cfg); err != nil {
+		return errors.Trace(err)
+	}
+	if key := cfg.BackupEncryptionPublicKey(); key != "" {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil || !strings.HasSuffix(block.Type, "PUBLIC KEY") {
+			return errors.Errorf("%s: expected PEM-encoded public key", BackupEncryptionPublicKeyKey)
+		}
+	}
+	if s3, ok := cfg.BackupS3(); ok {
+		if s3.AccessKey == "" || s3.SecretKey == "" {
+			return errors.Errorf("%s requires %s and %s", BackupS3BucketKey, BackupS3AccessKeyKey, BackupS3SecretKeyKey)
+		}
+		if s3.Endpoint != "" {
+			if _, err := url.Parse(s3.Endpoint); err != nil {
+				return errors.Annotatef(err, "invalid %s", BackupS3EndpointKey)
+			}
+		}
+	}
+
 	cfg.defined = ProcessDeprecatedAttributes(cfg.defined)
 	return nil
 }
@@ -1174,6 +1260,160 @@ func (c *Config) IgnoreMachineAddresses() (bool, bool) {
 	return v, ok
 }
 
+// JujuManagedSpaces reports whether Juju should manage spaces itself
+// when the provider does not support them natively.
+func (c *Config) JujuManagedSpaces() bool {
+	v, _ := c.defined[JujuManagedSpaces].(bool)
+	return v
+}
+
+// BackupInterval returns the interval at which the controller takes
+// scheduled backups; zero means no backups are scheduled.
+func (c *Config) BackupInterval() time.Duration {
+	d, _ := c.duration(BackupIntervalKey)
+	return d
+}
+
+// BackupRetentionCount returns the maximum number of scheduled backups
+// to keep; zero means there is no limit.
+func (c *Config) BackupRetentionCount() int {
+	v, _ := c.defined[BackupRetentionCountKey].(int)
+	return v
+}
+
+// BackupRetentionAge returns the maximum age of scheduled backups to
+// keep; zero means there is no limit.
+func (c *Config) BackupRetentionAge() time.Duration {
+	d, _ := c.duration(BackupRetentionAgeKey)
+	return d
+}
+
+// BackupEncryptionPublicKey returns the PEM-encoded public key to
+// which backups are encrypted by default, or "" if backups are not
+// encrypted by default.
+func (c *Config) BackupEncryptionPublicKey() string {
+	v, _ := c.defined[BackupEncryptionPublicKeyKey].(string)
+	return v
+}
+
+// BackupS3Config holds the settings for copying backups to
+// S3-compatible object storage.
+type BackupS3Config struct {
+	// Endpoint is the URL of the storage service; if empty, the
+	// Amazon S3 endpoint for the region is used.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix is prepended to the names of the stored objects.
+	Prefix    string
+	AccessKey string
+	SecretKey string
+}
+
+// BackupS3 returns the settings for copying backups to S3-compatible
+// object storage, and whether backups should be copied at all.
+func (c *Config) BackupS3() (BackupS3Config, bool) {
+	s3 := BackupS3Config{
+		Endpoint:  c.asString(BackupS3EndpointKey),
+		Region:    c.asString(BackupS3RegionKey),
+		Bucket:    c.asString(BackupS3BucketKey),
+		Prefix:    c.asString(BackupS3PrefixKey),
+		AccessKey: c.asString(BackupS3AccessKeyKey),
+		SecretKey: c.asString(BackupS3SecretKeyKey),
+	}
+	if s3.Region == "" {
+		s3.Region = DefaultBackupS3Region
+	}
+	return s3, s3.Bucket != ""
+}
+
+// HookRetryMaxAttempts returns the maximum number of times a failed
+// hook is automatically retried; zero means there is no limit.
+func (c *Config) HookRetryMaxAttempts() int {
+	v, _ := c.defined[HookRetryMaxAttemptsKey].(int)
+	return v
+}
+
+// HookRetryMinDelay returns the delay before the first automatic retry
+// of a failed hook, or zero if the default should be used.
+func (c *Config) HookRetryMinDelay() time.Duration {
+	d, _ := c.duration(HookRetryMinDelayKey)
+	return d
+}
+
+// HookRetryMaxDelay returns the maximum delay between automatic retries
+// of a failed hook, or zero if the default should be used.
+func (c *Config) HookRetryMaxDelay() time.Duration {
+	d, _ := c.duration(HookRetryMaxDelayKey)
+	return d
+}
+
+// HookRetryKinds returns the kinds of hook that are automatically
+// retried when they fail; if none are returned, all hooks are retried.
+func (c *Config) HookRetryKinds() []string {
+	var kinds []string
+	for _, kind := range strings.Split(c.asString(HookRetryKindsKey), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// validateHookSettings returns an error if the hook timeout and retry
+// settings in cfg are not valid.
+func validateHookSettings(cfg *Config) error {
+	for _, attr := range []string{HookTimeoutKey, HookRetryMinDelayKey, HookRetryMaxDelayKey} {
+		if _, err := cfg.duration(attr); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if attempts := cfg.HookRetryMaxAttempts(); attempts < 0 {
+		return errors.Errorf("%s: expected positive integer, got %v", HookRetryMaxAttemptsKey, attempts)
+	}
+	minDelay, maxDelay := cfg.HookRetryMinDelay(), cfg.HookRetryMaxDelay()
+	if minDelay > 0 && maxDelay > 0 && minDelay > maxDelay {
+		return errors.Errorf("%s: %v is greater than %s %v", HookRetryMinDelayKey, minDelay, HookRetryMaxDelayKey, maxDelay)
+	}
+	known := make(map[hooks.Kind]bool)
+	for _, kinds := range [][]hooks.Kind{hooks.UnitHooks(), hooks.RelationHooks(), hooks.StorageHooks()} {
+		for _, kind := range kinds {
+			known[kind] = true
+		}
+	}
+	for _, kind := range cfg.HookRetryKinds() {
+		if !known[hooks.Kind(kind)] {
+			return errors.Errorf("%s: unknown hook kind %q", HookRetryKindsKey, kind)
+		}
+	}
+	return nil
+}
+
+// HookTimeout returns the time after which running charm hooks are
+// killed, unless overridden by their service; zero means hooks may
+// run indefinitely.
+func (c *Config) HookTimeout() time.Duration {
+	d, _ := c.duration(HookTimeoutKey)
+	return d
+}
+
+// duration returns the non-negative duration held by the named
+// attribute, or zero if the attribute is not set.
+func (c *Config) duration(attr string) (time.Duration, error) {
+	v, ok := c.defined[attr].(string)
+	if !ok || v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Annotatef(err, "invalid %s", attr)
+	}
+	if d < 0 {
+		return 0, errors.Errorf("%s: expected positive duration, got %v", attr, d)
+	}
+	return d, nil
+}
+
 // StorageDefaultBlockSource returns the default block storage
 // source for the environment.
 func (c *Config) StorageDefaultBlockSource() (string, bool) {
@@ -1322,6 +1562,22 @@ var alwaysOptional = schema.Defaults{
 	LXCDefaultMTU:                schema.Omit,
 	"disable-network-management": schema.Omit,
 	IgnoreMachineAddresses:       schema.Omit,
+	JujuManagedSpaces:            schema.Omit,
+	BackupIntervalKey:            schema.Omit,
+	BackupRetentionCountKey:      schema.Omit,
+	BackupRetentionAgeKey:        schema.Omit,
+	BackupEncryptionPublicKeyKey: schema.Omit,
+	BackupS3BucketKey:            schema.Omit,
+	BackupS3EndpointKey:          schema.Omit,
+	BackupS3RegionKey:            schema.Omit,
+	BackupS3PrefixKey:            schema.Omit,
+	BackupS3AccessKeyKey:         schema.Omit,
+	BackupS3SecretKeyKey:         schema.Omit,
+	HookTimeoutKey:               schema.Omit,
+	HookRetryMaxAttemptsKey:      schema.Omit,
+	HookRetryMinDelayKey:         schema.Omit,
+	HookRetryMaxDelayKey:         schema.Omit,
+	HookRetryKindsKey:            schema.Omit,
 	AgentStreamKey:               schema.Omit,
 	IdentityURL:                  schema.Omit,
 	IdentityPublicKey:            schema.Omit,
@@ -1395,6 +1651,7 @@ func allDefaults() schema.Defaults {
 		"prefer-ipv6":                false,
 		"disable-network-management": false,
 		IgnoreMachineAddresses:       false,
+		JujuManagedSpaces:            false,
 		SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 		AutomaticallyRetryHooks:      true,
 	}
@@ -1717,6 +1974,87 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	JujuManagedSpaces: {
+		Description: "Whether Juju should manage spaces and subnets itself on providers without native support for spaces",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupIntervalKey: {
+		Description: "The interval at which the controller takes scheduled backups, e.g. '24h' (controller model only; unset disables scheduled backups)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupRetentionCountKey: {
+		Description: "The maximum number of scheduled backups the controller keeps (0 means no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupRetentionAgeKey: {
+		Description: "The age after which scheduled backups are removed by the controller, e.g. '168h' (unset means no limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupEncryptionPublicKeyKey: {
+		Description: "A PEM-encoded RSA public key to which backup archives are encrypted when no other key is given",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3BucketKey: {
+		Description: "The bucket in S3-compatible object storage to which completed backups are copied (unset means backups are not copied)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3EndpointKey: {
+		Description: "The URL of the S3-compatible object storage service for backups (unset means Amazon S3)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3RegionKey: {
+		Description: "The region of the object storage service for backups",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3PrefixKey: {
+		Description: "A prefix for the names of backups copied to object storage",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3AccessKeyKey: {
+		Description: "The access key for the object storage service for backups",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3SecretKeyKey: {
+		Description: "The secret key for the object storage service for backups",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.EnvironGroup,
+	},
+	HookTimeoutKey: {
+		Description: "The time after which a running charm hook is killed and the unit put into an error state, e.g. '30m' (unset means hooks may run indefinitely)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryMaxAttemptsKey: {
+		Description: "The maximum number of times a failed hook is automatically retried (0 means no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryMinDelayKey: {
+		Description: "The delay before a failed hook is first retried automatically, e.g. '5s'; later retries back off exponentially",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryMaxDelayKey: {
+		Description: "The maximum delay between automatic retries of a failed hook, e.g. '5m'",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryKindsKey: {
+		Description: "A comma-separated list of the hook kinds that are retried automatically, e.g. 'install,config-changed' (unset means all hooks)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"enable-os-refresh-update": {
 		Description: `Whether newly provisioned instances should run their respective OS's update capability.`,
 		Type:        environschema.Tbool,
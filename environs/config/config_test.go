@@ -357,6 +357,91 @@ var configTests = []configTest{
 		attrs: minimalConfigAttrs.Merge(testing.Attrs{
 			"ignore-machine-addresses": true,
 		}),
+	}, {
+		about:       "Invalid juju-managed-spaces flag",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"juju-managed-spaces": "invalid",
+		}),
+		err: `juju-managed-spaces: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "juju-managed-spaces on",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"juju-managed-spaces": true,
+		}),
+	}, {
+		about:       "Invalid backup-interval",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-interval": "daily",
+		}),
+		err: `invalid backup-interval: time: invalid duration "?daily"?`,
+	}, {
+		about:       "Invalid hook-timeout",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"hook-timeout": "forever",
+		}),
+		err: `invalid hook-timeout: time: invalid duration "?forever"?`,
+	}, {
+		about:       "Negative hook-retry-max-attempts",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"hook-retry-max-attempts": -1,
+		}),
+		err: `hook-retry-max-attempts: expected positive integer, got -1`,
+	}, {
+		about:       "hook-retry-min-delay greater than hook-retry-max-delay",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"hook-retry-min-delay": "10m",
+			"hook-retry-max-delay": "1m",
+		}),
+		err: `hook-retry-min-delay: 10m0s is greater than hook-retry-max-delay 1m0s`,
+	}, {
+		about:       "Unknown hook-retry-kinds",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"hook-retry-kinds": "install,reinstall",
+		}),
+		err: `hook-retry-kinds: unknown hook kind "reinstall"`,
+	}, {
+		about:       "Negative backup-retention-age",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-retention-age": "-1h",
+		}),
+		err: `backup-retention-age: expected positive duration, got -1h0m0s`,
+	}, {
+		about:       "Negative backup-retention-count",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-retention-count": -1,
+		}),
+		err: `backup-retention-count: expected positive integer, got -1`,
+	}, {
+		about:       "Invalid backup-encryption-public-key",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-encryption-public-key": "not a key",
+		}),
+		err: `backup-encryption-public-key: expected PEM-encoded public key`,
+	}, {
+		about:       "backup-s3-bucket without credentials",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-s3-bucket": "juju-backups",
+		}),
+		err: `backup-s3-bucket requires backup-s3-access-key and backup-s3-secret-key`,
+	}, {
+		about:       "Backup schedule",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"backup-interval":        "24h",
+			"backup-retention-count": 7,
+			"backup-retention-age":   "168h",
+		}),
 	}, {
 		about:       "set-numa-control-policy on",
 		useDefaults: config.UseDefaults,
@@ -1591,6 +1676,90 @@ func (s *ConfigSuite) TestAutoHookRetryTrueEnv(c *gc.C) {
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
 }
 
+func (s *ConfigSuite) TestBackupScheduleDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.BackupInterval(), gc.Equals, time.Duration(0))
+	c.Assert(config.BackupRetentionCount(), gc.Equals, 0)
+	c.Assert(config.BackupRetentionAge(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestBackupSchedule(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{
+		"backup-interval":        "12h",
+		"backup-retention-count": 14,
+		"backup-retention-age":   "168h",
+	})
+	c.Assert(config.BackupInterval(), gc.Equals, 12*time.Hour)
+	c.Assert(config.BackupRetentionCount(), gc.Equals, 14)
+	c.Assert(config.BackupRetentionAge(), gc.Equals, 168*time.Hour)
+}
+
+func (s *ConfigSuite) TestHookTimeout(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.HookTimeout(), gc.Equals, time.Duration(0))
+
+	config = newTestConfig(c, testing.Attrs{"hook-timeout": "30m"})
+	c.Assert(config.HookTimeout(), gc.Equals, 30*time.Minute)
+}
+
+func (s *ConfigSuite) TestHookRetryPolicy(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.HookRetryMaxAttempts(), gc.Equals, 0)
+	c.Assert(config.HookRetryMinDelay(), gc.Equals, time.Duration(0))
+	c.Assert(config.HookRetryMaxDelay(), gc.Equals, time.Duration(0))
+	c.Assert(config.HookRetryKinds(), gc.HasLen, 0)
+
+	config = newTestConfig(c, testing.Attrs{
+		"hook-retry-max-attempts": 5,
+		"hook-retry-min-delay":    "10s",
+		"hook-retry-max-delay":    "10m",
+		"hook-retry-kinds":        "install, relation-changed",
+	})
+	c.Assert(config.HookRetryMaxAttempts(), gc.Equals, 5)
+	c.Assert(config.HookRetryMinDelay(), gc.Equals, 10*time.Second)
+	c.Assert(config.HookRetryMaxDelay(), gc.Equals, 10*time.Minute)
+	c.Assert(config.HookRetryKinds(), jc.DeepEquals, []string{"install", "relation-changed"})
+}
+
+func (s *ConfigSuite) TestBackupEncryptionPublicKey(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.BackupEncryptionPublicKey(), gc.Equals, "")
+
+	publicKey := "-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n"
+	config = newTestConfig(c, testing.Attrs{
+		"backup-encryption-public-key": publicKey,
+	})
+	c.Assert(config.BackupEncryptionPublicKey(), gc.Equals, publicKey)
+}
+
+func (s *ConfigSuite) TestBackupS3(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.BackupS3()
+	c.Assert(ok, jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-prefix":     "prod/",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	})
+	s3, ok := cfg.BackupS3()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(s3, jc.DeepEquals, config.BackupS3Config{
+		Region:    "us-east-1",
+		Bucket:    "juju-backups",
+		Prefix:    "prod/",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+}
+
 func (s *ConfigSuite) TestCloudImageBaseURL(c *gc.C) {
 	s.addJujuFiles(c)
 	config := newTestConfig(c, testing.Attrs{})
@@ -245,6 +245,21 @@ type Firewaller interface {
 	Ports() ([]network.PortRange, error)
 }
 
+// EgressFirewaller is an optional interface that a provider can
+// implement to enforce the egress rules for a machine with the
+// provider's firewall, rather than on the machine itself.
+type EgressFirewaller interface {
+	// SetMachineEgressRules replaces the egress rules enforced for
+	// the instance of the specified machine. Traffic to the allowed
+	// addresses is never restricted.
+	//
+	// An error satisfying errors.IsNotSupported is returned if the
+	// rules cannot be enforced for the machine, for example because
+	// of the model's firewall mode; the provider then leaves the
+	// machine's egress traffic unrestricted.
+	SetMachineEgressRules(machineId string, rules []network.EgressRule, allowed []string) error
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -0,0 +1,680 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	"github.com/juju/juju/core/description"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/binarystorage"
+	"github.com/juju/juju/state/storage"
+)
+
+// A model backup archive is a gzipped tar file. The first entry holds
+// the serialized model description; it is followed by the archives of
+// the charms used by the model, the tools used by its agents, and the
+// content of its resources.
+const (
+	modelArchiveModel     = "model.yaml"
+	modelArchiveCharms    = "charms/"
+	modelArchiveTools     = "tools/"
+	modelArchiveResources = "resources/"
+
+	resourceMetadataSuffix = ".json"
+)
+
+// ArchiveBackend defines the methods on *state.State that are needed
+// to write a model backup archive.
+type ArchiveBackend interface {
+	StateExporter
+	UploadBackend
+	Resources() (state.Resources, error)
+}
+
+// WriteModelArchive writes a model backup archive for the model of
+// the given backend to w. The archive holds the serialized model
+// together with the charms, tools and resources it uses, so that the
+// model can be recreated with RestoreModelArchive.
+func WriteModelArchive(w io.Writer, backend ArchiveBackend) error {
+	model, err := backend.Export()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	serialized, err := description.Serialize(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	err = writeArchiveEntry(tw, modelArchiveModel, int64(len(serialized)), bytes.NewReader(serialized))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeArchiveCharms(tw, backend, model); err != nil {
+		return errors.Annotate(err, "cannot archive charms")
+	}
+	if err := writeArchiveTools(tw, backend, model); err != nil {
+		return errors.Annotate(err, "cannot archive tools")
+	}
+	if err := writeArchiveResources(tw, backend, model); err != nil {
+		return errors.Annotate(err, "cannot archive resources")
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(gzw.Close())
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Annotatef(err, "cannot write header for %q", name)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return errors.Annotatef(err, "cannot write %q", name)
+	}
+	return nil
+}
+
+func writeArchiveCharms(tw *tar.Writer, backend ArchiveBackend, model description.Model) error {
+	stor := getStateStorage(backend)
+	for _, charmURL := range getUsedCharms(model).SortedValues() {
+		curl, err := charm.ParseURL(charmURL)
+		if err != nil {
+			return errors.Annotate(err, "bad charm URL")
+		}
+		storagePath, err := getCharmStoragePath(backend, curl)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reader, size, err := stor.Get(storagePath)
+		if err != nil {
+			return errors.Annotate(err, "cannot get charm from storage")
+		}
+		err = writeArchiveEntry(tw, modelArchiveCharms+curl.String(), size, reader)
+		reader.Close()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func writeArchiveTools(tw *tar.Writer, backend ArchiveBackend, model description.Model) error {
+	toolsStorage, err := backend.ToolsStorage()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer toolsStorage.Close()
+
+	for toolsVersion := range getUsedToolsVersions(model) {
+		metadata, reader, err := toolsStorage.Open(toolsVersion.String())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = writeArchiveEntry(tw, modelArchiveTools+toolsVersion.String(), metadata.Size, reader)
+		reader.Close()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// archivedResource is the metadata stored alongside the content of
+// each resource in a model backup archive.
+type archivedResource struct {
+	Service     string `json:"service"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
+	Origin      string `json:"origin"`
+	Revision    int    `json:"revision"`
+	Fingerprint string `json:"fingerprint"`
+	Size        int64  `json:"size"`
+	Username    string `json:"username,omitempty"`
+}
+
+func (r archivedResource) resource() (charmresource.Resource, error) {
+	resType, err := charmresource.ParseType(r.Type)
+	if err != nil {
+		return charmresource.Resource{}, errors.Trace(err)
+	}
+	origin, err := charmresource.ParseOrigin(r.Origin)
+	if err != nil {
+		return charmresource.Resource{}, errors.Trace(err)
+	}
+	fingerprint, err := charmresource.ParseFingerprint(r.Fingerprint)
+	if err != nil {
+		return charmresource.Resource{}, errors.Trace(err)
+	}
+	res := charmresource.Resource{
+		Meta: charmresource.Meta{
+			Name:        r.Name,
+			Type:        resType,
+			Path:        r.Path,
+			Description: r.Description,
+		},
+		Origin:      origin,
+		Revision:    r.Revision,
+		Fingerprint: fingerprint,
+		Size:        r.Size,
+	}
+	return res, errors.Trace(res.Validate())
+}
+
+func writeArchiveResources(tw *tar.Writer, backend ArchiveBackend, model description.Model) error {
+	resources, err := backend.Resources()
+	if errors.IsNotSupported(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range model.Services() {
+		serviceResources, err := resources.ListResources(service.Name())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, res := range serviceResources.Resources {
+			if res.IsPlaceholder() {
+				// There is no content to back up.
+				continue
+			}
+			doc, err := json.Marshal(archivedResource{
+				Service:     service.Name(),
+				Name:        res.Name,
+				Type:        res.Type.String(),
+				Path:        res.Path,
+				Description: res.Description,
+				Origin:      res.Origin.String(),
+				Revision:    res.Revision,
+				Fingerprint: res.Fingerprint.String(),
+				Size:        res.Size,
+				Username:    res.Username,
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			name := modelArchiveResources + service.Name() + "/" + res.Name
+			err = writeArchiveEntry(tw, name+resourceMetadataSuffix, int64(len(doc)), bytes.NewReader(doc))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, reader, err := resources.OpenResource(service.Name(), res.Name)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = writeArchiveEntry(tw, name, res.Size, reader)
+			reader.Close()
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// ModelArchive is a model backup archive opened for restoring.
+type ModelArchive struct {
+	gzr        *gzip.Reader
+	tr         *tar.Reader
+	serialized []byte
+	model      description.Model
+
+	// staged holds the checked content of the archive once it has
+	// been read in full; see stage.
+	staged *os.File
+}
+
+// OpenModelArchive reads the model description at the start of the
+// model backup archive read from r. The archive should be closed
+// when it is no longer needed.
+func OpenModelArchive(r io.Reader) (*ModelArchive, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid model backup archive")
+	}
+	tr := tar.NewReader(gzr)
+	hdr, err := tr.Next()
+	if err != nil {
+		gzr.Close()
+		return nil, errors.Annotate(err, "invalid model backup archive")
+	}
+	if hdr.Name != modelArchiveModel {
+		gzr.Close()
+		return nil, errors.Errorf("invalid model backup archive: expected %q, got %q", modelArchiveModel, hdr.Name)
+	}
+	serialized, err := ioutil.ReadAll(tr)
+	if err != nil {
+		gzr.Close()
+		return nil, errors.Annotate(err, "cannot read model description")
+	}
+	model, err := description.Deserialize(serialized)
+	if err != nil {
+		gzr.Close()
+		return nil, errors.Trace(err)
+	}
+	return &ModelArchive{
+		gzr:        gzr,
+		tr:         tr,
+		serialized: serialized,
+		model:      model,
+	}, nil
+}
+
+// Model returns the description of the model held in the archive.
+func (a *ModelArchive) Model() description.Model {
+	return a.model
+}
+
+// Close releases the resources used by the archive.
+func (a *ModelArchive) Close() error {
+	if a.staged != nil {
+		removeTempFile(a.staged)
+	}
+	return a.gzr.Close()
+}
+
+// Check reads the rest of the archive and checks that every charm,
+// tools and resource entry in it can be restored, without changing
+// the controller. Restore calls it before making any changes, so a
+// truncated or corrupt archive never causes a model to be replaced.
+func (a *ModelArchive) Check() error {
+	if a.staged != nil {
+		return nil
+	}
+	f, err := ioutil.TempFile("", "juju-model-restore")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := stageArchive(f, a.tr); err != nil {
+		removeTempFile(f)
+		return errors.Trace(err)
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		removeTempFile(f)
+		return errors.Trace(err)
+	}
+	a.staged = f
+	a.tr = tar.NewReader(f)
+	return nil
+}
+
+// stageArchive copies the entries read from tr to w, checking each
+// of them.
+func stageArchive(w io.Writer, tr *tar.Reader) error {
+	tw := tar.NewWriter(w)
+	var pendingResource *archivedResource
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Annotate(err, "cannot read model backup archive")
+		}
+		if err := stageArchiveEntry(tw, hdr.Name, tr, &pendingResource); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if pendingResource != nil {
+		return errors.Errorf("model backup archive has no content for resource %q of service %q",
+			pendingResource.Name, pendingResource.Service)
+	}
+	return errors.Trace(tw.Close())
+}
+
+// stageArchiveEntry checks the named archive entry, whose content is
+// read from r, and writes it to tw.
+func stageArchiveEntry(tw *tar.Writer, name string, r io.Reader, pendingResource **archivedResource) error {
+	entry, err := newArchiveEntry(r)
+	if err != nil {
+		return errors.Annotate(err, "cannot read model backup archive")
+	}
+	defer entry.Close()
+	if err := checkArchiveEntry(name, entry, pendingResource); err != nil {
+		return errors.Annotatef(err, "invalid entry %q in model backup archive", name)
+	}
+	return errors.Trace(writeArchiveEntry(tw, name, entry.size, entry.reader()))
+}
+
+// checkArchiveEntry returns an error if the named archive entry, with
+// the given content, cannot be restored. Resource metadata entries are
+// recorded in pendingResource, to be checked against the content that
+// must follow them.
+func checkArchiveEntry(name string, entry *archiveEntry, pendingResource **archivedResource) error {
+	switch {
+	case strings.HasPrefix(name, modelArchiveCharms):
+		if _, err := charm.ParseURL(strings.TrimPrefix(name, modelArchiveCharms)); err != nil {
+			return errors.Annotate(err, "bad charm URL")
+		}
+		_, err := charm.ReadCharmArchiveFromReader(entry.file, entry.size)
+		return errors.Annotate(err, "invalid charm archive")
+	case strings.HasPrefix(name, modelArchiveTools):
+		_, err := version.ParseBinary(strings.TrimPrefix(name, modelArchiveTools))
+		return errors.Trace(err)
+	case strings.HasPrefix(name, modelArchiveResources) && strings.HasSuffix(name, resourceMetadataSuffix):
+		var archived archivedResource
+		if err := json.NewDecoder(entry.reader()).Decode(&archived); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := archived.resource(); err != nil {
+			return errors.Trace(err)
+		}
+		*pendingResource = &archived
+		return nil
+	case strings.HasPrefix(name, modelArchiveResources):
+		pending := *pendingResource
+		*pendingResource = nil
+		if pending == nil || name != modelArchiveResources+path.Join(pending.Service, pending.Name) {
+			return errors.New("unexpected resource")
+		}
+		if entry.size != pending.Size {
+			return errors.Errorf("expected %d bytes, got %d", pending.Size, entry.size)
+		}
+		if entry.fingerprint.String() != pending.Fingerprint {
+			return errors.New("fingerprint mismatch")
+		}
+		return nil
+	}
+	return errors.New("unexpected entry")
+}
+
+// archiveEntry holds the content of a model backup archive entry,
+// copied to a temporary file so that large charms, tools and
+// resources are never held in memory.
+type archiveEntry struct {
+	file        *os.File
+	size        int64
+	sha256      string
+	fingerprint charmresource.Fingerprint
+}
+
+// newArchiveEntry copies the content read from r to a temporary file,
+// hashing it as it is copied. The entry should be closed when it is no
+// longer needed.
+func newArchiveEntry(r io.Reader) (*archiveEntry, error) {
+	f, err := ioutil.TempFile("", "juju-model-entry")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sha256hash := sha256.New()
+	fingerprintHash := charmresource.NewFingerprintHash()
+	size, err := io.Copy(f, io.TeeReader(r, io.MultiWriter(sha256hash, fingerprintHash)))
+	if err != nil {
+		removeTempFile(f)
+		return nil, errors.Trace(err)
+	}
+	return &archiveEntry{
+		file:        f,
+		size:        size,
+		sha256:      fmt.Sprintf("%x", sha256hash.Sum(nil)),
+		fingerprint: fingerprintHash.Fingerprint(),
+	}, nil
+}
+
+// reader returns a reader of the entry's content from its start.
+func (e *archiveEntry) reader() io.Reader {
+	return io.NewSectionReader(e.file, 0, e.size)
+}
+
+// Close removes the entry's temporary file.
+func (e *archiveEntry) Close() {
+	removeTempFile(e.file)
+}
+
+// Restore recreates the model held in the archive, together with its
+// charms, tools and resources. If the model already exists it is an
+// error unless replace is true, in which case the existing model is
+// replaced; other models are left untouched. The archive can only be
+// restored once.
+//
+// The archive is checked in full before any change is made. When
+// replacing a model, the existing model is first written to a model
+// backup of its own; if the restore then fails, the partly restored
+// model is removed and the existing model is put back from that
+// backup.
+func (a *ModelArchive) Restore(st *state.State, replace bool) (*state.Model, error) {
+	if err := a.Check(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tag := a.model.Tag()
+	_, err := st.GetModel(tag)
+	if errors.IsNotFound(err) {
+		return a.restore(st)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !replace {
+		return nil, errors.AlreadyExistsf("model %q", tag.Id())
+	}
+	existing, err := st.ForModel(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer existing.Close()
+	return a.replace(st, existing)
+}
+
+// replace replaces the existing model with the one held in the
+// archive, putting the existing model back if that fails.
+func (a *ModelArchive) replace(st, existing *state.State) (*state.Model, error) {
+	tag := a.model.Tag()
+	rollback, err := ioutil.TempFile("", "juju-model-rollback")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := WriteModelArchive(rollback, existing); err != nil {
+		removeTempFile(rollback)
+		return nil, errors.Annotate(err, "cannot back up existing model")
+	}
+	logger.Infof("removing model %q to replace it from backup", tag.Id())
+	if err := existing.RemoveRestoringModelDocs(); err != nil {
+		removeTempFile(rollback)
+		return nil, errors.Annotate(err, "cannot remove existing model")
+	}
+
+	model, err := a.restore(st)
+	if err == nil {
+		removeTempFile(rollback)
+		return model, nil
+	}
+	logger.Errorf("cannot restore model %q, putting back existing model: %v", tag.Id(), err)
+	if rollbackErr := restoreRollback(st, rollback); rollbackErr != nil {
+		// Keep the backup of the existing model, so that an
+		// administrator can restore it by hand.
+		rollback.Close()
+		logger.Errorf("cannot put back model %q: %v", tag.Id(), rollbackErr)
+		return nil, errors.Annotatef(err,
+			"cannot put back model %q (%v), a backup of it was kept in %q",
+			tag.Id(), rollbackErr, rollback.Name(),
+		)
+	}
+	removeTempFile(rollback)
+	return nil, errors.Annotate(err, "existing model left unchanged")
+}
+
+// restoreRollback restores the model backup written to f by replace.
+func restoreRollback(st *state.State, f *os.File) error {
+	if _, err := f.Seek(0, 0); err != nil {
+		return errors.Trace(err)
+	}
+	archive, err := OpenModelArchive(f)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	if err := archive.Check(); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = archive.restore(st)
+	return errors.Trace(err)
+}
+
+// restore imports the model held in the archive, which must not exist,
+// removing anything it has imported if it fails.
+func (a *ModelArchive) restore(st *state.State) (_ *state.Model, err error) {
+	tag := a.model.Tag()
+	dbModel, dbState, err := ImportModel(st, a.serialized)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer dbState.Close()
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := dbState.RemoveImportingModelDocs(); err != nil {
+			logger.Errorf("cannot remove partially restored model %q: %v", tag.Id(), err)
+		}
+	}()
+
+	if err := a.restoreBinaries(dbState); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := dbModel.SetMigrationMode(state.MigrationModeActive); err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("restored model %q from backup", tag.Id())
+	return dbModel, nil
+}
+
+func removeTempFile(f *os.File) {
+	f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		logger.Warningf("cannot remove %q: %v", f.Name(), err)
+	}
+}
+
+func (a *ModelArchive) restoreBinaries(st *state.State) error {
+	stor := storage.NewStorage(st.ModelUUID(), st.MongoSession())
+	toolsStorage, err := st.ToolsStorage()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer toolsStorage.Close()
+
+	var pendingResource *archivedResource
+	for {
+		hdr, err := a.tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Annotate(err, "cannot read model backup archive")
+		}
+		name := hdr.Name
+		switch {
+		case strings.HasPrefix(name, modelArchiveCharms):
+			err = restoreCharm(st, stor, strings.TrimPrefix(name, modelArchiveCharms), a.tr)
+		case strings.HasPrefix(name, modelArchiveTools):
+			err = restoreTools(toolsStorage, strings.TrimPrefix(name, modelArchiveTools), a.tr)
+		case strings.HasPrefix(name, modelArchiveResources) && strings.HasSuffix(name, resourceMetadataSuffix):
+			pendingResource = &archivedResource{}
+			err = json.NewDecoder(a.tr).Decode(pendingResource)
+		case strings.HasPrefix(name, modelArchiveResources):
+			if pendingResource == nil || name != modelArchiveResources+path.Join(pendingResource.Service, pendingResource.Name) {
+				return errors.Errorf("unexpected resource %q in model backup archive", name)
+			}
+			err = restoreResource(st, *pendingResource, a.tr)
+			pendingResource = nil
+		default:
+			return errors.Errorf("unexpected entry %q in model backup archive", name)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "cannot restore %q", name)
+		}
+	}
+	return nil
+}
+
+func restoreCharm(st *state.State, stor storage.Storage, charmURL string, r io.Reader) error {
+	curl, err := charm.ParseURL(charmURL)
+	if err != nil {
+		return errors.Annotate(err, "bad charm URL")
+	}
+	entry, err := newArchiveEntry(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer entry.Close()
+	ch, err := charm.ReadCharmArchiveFromReader(entry.file, entry.size)
+	if err != nil {
+		return errors.Annotate(err, "invalid charm archive")
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	storagePath := fmt.Sprintf("charms/%s-%s", curl, uuid)
+	if err := stor.Put(storagePath, entry.reader(), entry.size); err != nil {
+		return errors.Annotate(err, "cannot add charm to storage")
+	}
+	_, err = st.AddCharm(state.CharmInfo{
+		Charm:       ch,
+		ID:          curl,
+		StoragePath: storagePath,
+		SHA256:      entry.sha256,
+	})
+	return errors.Trace(err)
+}
+
+func restoreTools(toolsStorage binarystorage.Storage, toolsVersion string, r io.Reader) error {
+	if _, err := version.ParseBinary(toolsVersion); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := toolsStorage.Metadata(toolsVersion); err == nil {
+		// The controller already has these tools.
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	entry, err := newArchiveEntry(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer entry.Close()
+	metadata := binarystorage.Metadata{
+		Version: toolsVersion,
+		Size:    entry.size,
+		SHA256:  entry.sha256,
+	}
+	return errors.Trace(toolsStorage.Add(entry.reader(), metadata))
+}
+
+func restoreResource(st *state.State, archived archivedResource, r io.Reader) error {
+	res, err := archived.resource()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resources, err := st.Resources()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = resources.SetResource(archived.Service, archived.Username, res, r)
+	return errors.Trace(err)
+}
@@ -0,0 +1,183 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *ImportSuite) writeModelArchive(c *gc.C, st *state.State) []byte {
+	var buf bytes.Buffer
+	err := migration.WriteModelArchive(&buf, st)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *ImportSuite) TestOpenModelArchive(c *gc.C) {
+	data := s.writeModelArchive(c, s.State)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	c.Assert(archive.Model().Tag(), gc.Equals, s.State.ModelTag())
+}
+
+func (s *ImportSuite) TestOpenModelArchiveInvalid(c *gc.C) {
+	_, err := migration.OpenModelArchive(bytes.NewReader([]byte("not an archive")))
+	c.Assert(err, gc.ErrorMatches, "invalid model backup archive: .*")
+}
+
+func (s *ImportSuite) TestRestoreModelArchiveExisting(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	data := s.writeModelArchive(c, st)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	_, err = archive.Restore(s.State, false)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ImportSuite) TestRestoreModelArchiveReplace(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	data := s.writeModelArchive(c, st)
+
+	// Changes made after the backup are lost when it is restored.
+	f := factory.NewFactory(st)
+	f.MakeMachine(c, nil)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	model, err := archive.Restore(s.State, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.ModelTag(), gc.Equals, st.ModelTag())
+	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeActive)
+
+	restored, err := s.State.ForModel(st.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer restored.Close()
+	machines, err := restored.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *ImportSuite) TestRestoreModelArchiveRemoved(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	data := s.writeModelArchive(c, st)
+	err := st.RemoveRestoringModelDocs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	model, err := archive.Restore(s.State, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.ModelTag(), gc.Equals, st.ModelTag())
+}
+
+// appendArchiveEntries returns a copy of the model backup archive with
+// the given entries appended to it.
+func appendArchiveEntries(c *gc.C, data []byte, entries ...[2]string) []byte {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	tr := tar.NewReader(gzr)
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		err = tw.WriteHeader(hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = io.Copy(tw, tr)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	for _, entry := range entries {
+		err := tw.WriteHeader(&tar.Header{Name: entry[0], Mode: 0644, Size: int64(len(entry[1]))})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write([]byte(entry[1]))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *ImportSuite) assertMachineCount(c *gc.C, st *state.State, count int) {
+	model, err := s.State.ForModel(st.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer model.Close()
+	machines, err := model.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, count)
+}
+
+func (s *ImportSuite) TestRestoreModelArchiveReplaceInvalid(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	data := appendArchiveEntries(c, s.writeModelArchive(c, st),
+		[2]string{"charms/cs:quantal/wordpress-3", "not a charm"},
+	)
+	factory.NewFactory(st).MakeMachine(c, nil)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	_, err = archive.Restore(s.State, true)
+	c.Assert(err, gc.ErrorMatches, `invalid entry "charms/cs:quantal/wordpress-3" in model backup archive: invalid charm archive: .*`)
+
+	// The archive is checked before the existing model is touched.
+	s.assertMachineCount(c, st, 1)
+}
+
+func (s *ImportSuite) TestRestoreModelArchiveReplaceRollsBack(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	content := "resource content"
+	fingerprint, err := charmresource.GenerateFingerprint(bytes.NewReader([]byte(content)))
+	c.Assert(err, jc.ErrorIsNil)
+	// The resource is valid, but its service is not in the model, so
+	// it cannot be restored once the existing model has been removed.
+	metadata := fmt.Sprintf(
+		`{"service":"nosuch","name":"data","type":"file","path":"data.txt","origin":"upload","revision":0,"fingerprint":%q,"size":%d}`,
+		fingerprint.String(), len(content),
+	)
+	data := appendArchiveEntries(c, s.writeModelArchive(c, st),
+		[2]string{"resources/nosuch/data.json", metadata},
+		[2]string{"resources/nosuch/data", content},
+	)
+	factory.NewFactory(st).MakeMachine(c, nil)
+
+	archive, err := migration.OpenModelArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	_, err = archive.Restore(s.State, true)
+	c.Assert(err, gc.ErrorMatches, `existing model left unchanged: cannot restore "resources/nosuch/data": .*`)
+
+	// The existing model was put back as it was before the restore.
+	model, err := s.State.GetModel(st.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeActive)
+	s.assertMachineCount(c, st, 1)
+}
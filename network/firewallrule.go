@@ -0,0 +1,156 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"net"
+	"regexp"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+var validFirewallRuleName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// FirewallRule is a model-level rule describing the traffic that
+// machines may send to a destination, which is either a space or a
+// set of CIDRs.
+//
+// Rules are allow-lists: once any rule covers a destination for a
+// machine, traffic from that machine to the destination is restricted
+// to the ports allowed by the rules covering it. Traffic to
+// destinations not covered by any rule is unaffected.
+type FirewallRule struct {
+	// Name uniquely identifies the rule within the model.
+	Name string
+
+	// SourceSpace, if non-empty, restricts the rule to machines
+	// with addresses in the named space. Otherwise the rule
+	// applies to all machines in the model.
+	SourceSpace string
+
+	// DestinationSpace is the name of the space that traffic
+	// is sent to. Exactly one of DestinationSpace and
+	// DestinationCIDRs must be specified.
+	DestinationSpace string
+
+	// DestinationCIDRs are the CIDRs that traffic is sent to.
+	DestinationCIDRs []string
+
+	// Ports are the port ranges that traffic may be sent to.
+	Ports []PortRange
+}
+
+// Validate returns an error if the rule is not valid.
+func (r FirewallRule) Validate() error {
+	if !validFirewallRuleName.MatchString(r.Name) {
+		return errors.NotValidf("firewall rule name %q", r.Name)
+	}
+	if r.SourceSpace != "" && !names.IsValidSpace(r.SourceSpace) {
+		return errors.NotValidf("source space name %q", r.SourceSpace)
+	}
+	switch {
+	case r.DestinationSpace == "" && len(r.DestinationCIDRs) == 0:
+		return errors.NotValidf("firewall rule %q without destination", r.Name)
+	case r.DestinationSpace != "" && len(r.DestinationCIDRs) > 0:
+		return errors.NotValidf("firewall rule %q with both destination space and CIDRs", r.Name)
+	case r.DestinationSpace != "":
+		if !names.IsValidSpace(r.DestinationSpace) {
+			return errors.NotValidf("destination space name %q", r.DestinationSpace)
+		}
+		if r.DestinationSpace == r.SourceSpace {
+			return errors.NotValidf("firewall rule %q with the same source and destination space", r.Name)
+		}
+	}
+	for _, cidr := range r.DestinationCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("destination CIDR %q", cidr)
+		}
+	}
+	if len(r.Ports) == 0 {
+		return errors.NotValidf("firewall rule %q without ports", r.Name)
+	}
+	for _, portRange := range r.Ports {
+		if err := portRange.Validate(); err != nil {
+			return errors.Annotatef(err, "firewall rule %q", r.Name)
+		}
+	}
+	return nil
+}
+
+// EgressRule is a FirewallRule resolved for a particular machine: the
+// destination space, if any, is replaced by the CIDRs of its subnets.
+type EgressRule struct {
+	// Name is the name of the FirewallRule that the egress
+	// rule was resolved from.
+	Name string
+
+	// DestinationCIDRs are the CIDRs that traffic is sent to.
+	DestinationCIDRs []string
+
+	// Ports are the port ranges that traffic may be sent to.
+	Ports []PortRange
+}
+
+// ExcludeCIDRs returns the smallest set of CIDRs that covers all the
+// addresses in the base CIDR which are not in any of the excluded
+// CIDRs. Excluded CIDRs of the other address family are ignored.
+//
+// Providers whose firewalls can only allow traffic use it to allow
+// traffic to the destinations that no egress rule covers.
+func ExcludeCIDRs(base string, excluded []string) ([]string, error) {
+	_, baseNet, err := net.ParseCIDR(base)
+	if err != nil {
+		return nil, errors.NotValidf("CIDR %q", base)
+	}
+	remaining := []*net.IPNet{baseNet}
+	for _, cidr := range excluded {
+		_, exclNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.NotValidf("CIDR %q", cidr)
+		}
+		if len(exclNet.IP) != len(baseNet.IP) {
+			continue
+		}
+		var next []*net.IPNet
+		for _, ipNet := range remaining {
+			next = append(next, excludeNet(ipNet, exclNet)...)
+		}
+		remaining = next
+	}
+	result := make([]string, len(remaining))
+	for i, ipNet := range remaining {
+		result[i] = ipNet.String()
+	}
+	return result, nil
+}
+
+// excludeNet returns the networks covering the addresses in ipNet
+// that are not in excl, which must be of the same address family.
+func excludeNet(ipNet, excl *net.IPNet) []*net.IPNet {
+	ones, bits := ipNet.Mask.Size()
+	exclOnes, _ := excl.Mask.Size()
+	if exclOnes <= ones {
+		if excl.Contains(ipNet.IP) {
+			// ipNet is entirely excluded.
+			return nil
+		}
+		return []*net.IPNet{ipNet}
+	}
+	if !ipNet.Contains(excl.IP) {
+		return []*net.IPNet{ipNet}
+	}
+	// excl is a strict subset of ipNet: split ipNet in
+	// half, and exclude it from the half that contains it.
+	mask := net.CIDRMask(ones+1, bits)
+	lower := &net.IPNet{IP: ipNet.IP, Mask: mask}
+	upperIP := make(net.IP, len(ipNet.IP))
+	copy(upperIP, ipNet.IP)
+	upperIP[ones/8] |= 0x80 >> uint(ones%8)
+	upper := &net.IPNet{IP: upperIP, Mask: mask}
+	if lower.Contains(excl.IP) {
+		return append(excludeNet(lower, excl), upper)
+	}
+	return append([]*net.IPNet{lower}, excludeNet(upper, excl)...)
+}
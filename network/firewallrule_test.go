@@ -0,0 +1,141 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type FirewallRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&FirewallRuleSuite{})
+
+func (*FirewallRuleSuite) TestValidate(c *gc.C) {
+	https := []network.PortRange{network.MustParsePortRange("443/tcp")}
+	for i, test := range []struct {
+		rule   network.FirewallRule
+		expect string
+	}{{
+		rule: network.FirewallRule{
+			Name:             "dmz-to-app",
+			SourceSpace:      "dmz",
+			DestinationSpace: "app",
+			Ports:            https,
+		},
+	}, {
+		rule: network.FirewallRule{
+			Name:             "web-egress",
+			DestinationCIDRs: []string{"0.0.0.0/0", "2001:db8::/32"},
+			Ports:            https,
+		},
+	}, {
+		rule: network.FirewallRule{
+			Name:             "Bad_Name",
+			DestinationSpace: "app",
+			Ports:            https,
+		},
+		expect: `firewall rule name "Bad_Name" not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:        "no-destination",
+			SourceSpace: "dmz",
+			Ports:       https,
+		},
+		expect: `firewall rule "no-destination" without destination not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:             "both",
+			DestinationSpace: "app",
+			DestinationCIDRs: []string{"10.0.0.0/8"},
+			Ports:            https,
+		},
+		expect: `firewall rule "both" with both destination space and CIDRs not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:             "same",
+			SourceSpace:      "app",
+			DestinationSpace: "app",
+			Ports:            https,
+		},
+		expect: `firewall rule "same" with the same source and destination space not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:             "bad-cidr",
+			DestinationCIDRs: []string{"10.0.0.0"},
+			Ports:            https,
+		},
+		expect: `destination CIDR "10.0.0.0" not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:             "no-ports",
+			DestinationSpace: "app",
+		},
+		expect: `firewall rule "no-ports" without ports not valid`,
+	}, {
+		rule: network.FirewallRule{
+			Name:             "bad-ports",
+			DestinationSpace: "app",
+			Ports:            []network.PortRange{{FromPort: 443, ToPort: 80, Protocol: "tcp"}},
+		},
+		expect: `firewall rule "bad-ports": invalid port range 443-80/tcp`,
+	}} {
+		c.Logf("test %d: %+v", i, test.rule)
+		err := test.rule.Validate()
+		if test.expect == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expect)
+		}
+	}
+}
+
+func (*FirewallRuleSuite) TestExcludeCIDRs(c *gc.C) {
+	for i, test := range []struct {
+		base     string
+		excluded []string
+		expect   []string
+	}{{
+		base:   "0.0.0.0/0",
+		expect: []string{"0.0.0.0/0"},
+	}, {
+		base:     "0.0.0.0/0",
+		excluded: []string{"0.0.0.0/0"},
+		expect:   []string{},
+	}, {
+		base:     "0.0.0.0/0",
+		excluded: []string{"128.0.0.0/1"},
+		expect:   []string{"0.0.0.0/1"},
+	}, {
+		base:     "10.0.0.0/8",
+		excluded: []string{"10.0.0.0/10", "192.168.0.0/16", "2001:db8::/32"},
+		expect:   []string{"10.64.0.0/10", "10.128.0.0/9"},
+	}, {
+		base:     "10.0.0.0/24",
+		excluded: []string{"10.0.0.128/26", "10.0.0.16/28"},
+		expect: []string{
+			"10.0.0.0/28", "10.0.0.32/27", "10.0.0.64/26",
+			"10.0.0.192/26",
+		},
+	}, {
+		base:     "::/0",
+		excluded: []string{"8000::/1", "10.0.0.0/8"},
+		expect:   []string{"::/1"},
+	}} {
+		c.Logf("test %d: %s - %v", i, test.base, test.excluded)
+		cidrs, err := network.ExcludeCIDRs(test.base, test.excluded)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(cidrs, jc.SameContents, test.expect)
+	}
+}
+
+func (*FirewallRuleSuite) TestExcludeCIDRsInvalid(c *gc.C) {
+	_, err := network.ExcludeCIDRs("0.0.0.0/0", []string{"bogus"})
+	c.Assert(err, gc.ErrorMatches, `CIDR "bogus" not valid`)
+}
@@ -740,6 +740,9 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 		if err != nil {
 			panic(err)
 		}
+		if err := st.SetSecretsKey(testing.SecretsKey); err != nil {
+			panic(err)
+		}
 		if err := st.SetModelConstraints(args.ModelConstraints); err != nil {
 			panic(err)
 		}
@@ -0,0 +1,303 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/xml"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
+)
+
+var _ environs.EgressFirewaller = (*environ)(nil)
+
+// egressAPIVersion is the EC2 API version used for the egress
+// security group requests, which the amz client does not provide.
+const egressAPIVersion = "2014-10-01"
+
+// egressPerm is a single egress permission of a VPC security group,
+// allowing traffic to either a CIDR or the members of a group.
+type egressPerm struct {
+	protocol string
+	fromPort int
+	toPort   int
+	cidr     string
+	groupId  string
+}
+
+// SetMachineEgressRules is part of the environs.EgressFirewaller
+// interface.
+//
+// Egress permissions are added to the machine's security group, and
+// the default permission allowing all egress traffic is revoked from
+// the model's shared group, since EC2 allows any traffic that is
+// allowed by one of an instance's groups. This is only possible in
+// the "instance" firewall mode, and with VPC security groups.
+func (e *environ) SetMachineEgressRules(machineId string, rules []network.EgressRule, allowed []string) error {
+	if mode := e.Config().FirewallMode(); mode != config.FwInstance {
+		return errors.NotSupportedf("egress rules with firewall mode %q", mode)
+	}
+	machineGroup, err := e.groupInfoByName(e.machineGroupName(machineId))
+	if err != nil {
+		return errors.Annotate(err, "cannot get machine security group")
+	}
+	if machineGroup.VPCId == "" {
+		return errors.NotSupportedf("egress rules without VPC security groups")
+	}
+	want, err := egressPerms(rules, allowed)
+	if errors.IsNotSupported(err) {
+		// Remove any rules set previously, leaving the
+		// machine's egress traffic unrestricted.
+		unrestricted := []egressPerm{{protocol: "-1", cidr: "0.0.0.0/0"}}
+		if err := e.setEgressPerms(machineGroup.Id, unrestricted); err != nil {
+			return errors.Annotatef(err, "cannot reset egress rules for machine %s", machineId)
+		}
+		return errors.Trace(err)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if err := e.setEgressPerms(machineGroup.Id, want); err != nil {
+		return errors.Annotatef(err, "cannot set egress rules for machine %s", machineId)
+	}
+	jujuGroup, err := e.groupByName(e.jujuGroupName())
+	if err != nil {
+		return errors.Annotate(err, "cannot get model security group")
+	}
+	// Every machine group is created with a permission allowing all
+	// egress traffic, so this does not restrict any other machine.
+	if err := e.setEgressPerms(jujuGroup.Id, nil); err != nil {
+		return errors.Annotate(err, "cannot remove egress permissions from model security group")
+	}
+	logger.Infof("set egress rules for machine %s: %v", machineId, rules)
+	return nil
+}
+
+// egressPerms returns the egress permissions that enforce the given
+// rules: traffic is allowed to each destination covered by a rule
+// on the rule's ports, and to the allowed addresses and destinations
+// not covered by any rule on all ports.
+//
+// EC2 security groups only support IPv4 egress permissions here, so
+// rules with IPv6 destinations are not supported.
+func egressPerms(rules []network.EgressRule, allowed []string) ([]egressPerm, error) {
+	var perms, unrestricted []egressPerm
+	var covered []string
+	for _, rule := range rules {
+		for _, cidr := range rule.DestinationCIDRs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.NotValidf("destination CIDR %q", cidr)
+			}
+			if ip.To4() == nil {
+				return nil, errors.NotSupportedf("egress rule %q with IPv6 destination", rule.Name)
+			}
+			covered = append(covered, cidr)
+			for _, portRange := range rule.Ports {
+				perms = append(perms, egressPerm{
+					protocol: strings.ToLower(portRange.Protocol),
+					fromPort: portRange.FromPort,
+					toPort:   portRange.ToPort,
+					cidr:     cidr,
+				})
+			}
+		}
+	}
+	uncovered, err := network.ExcludeCIDRs("0.0.0.0/0", covered)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, addr := range allowed {
+		if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+			uncovered = append(uncovered, ip.String()+"/32")
+		}
+	}
+	for _, cidr := range uncovered {
+		unrestricted = append(unrestricted, egressPerm{protocol: "-1", cidr: cidr})
+	}
+	return append(unrestricted, perms...), nil
+}
+
+// setEgressPerms replaces the egress permissions of the security
+// group with the given ones. New permissions are authorized before
+// old ones are revoked, so that traffic allowed both before and
+// after is never interrupted.
+func (e *environ) setEgressPerms(groupId string, perms []egressPerm) error {
+	have, err := e.egressPermsInGroup(groupId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	want := make(map[egressPerm]bool)
+	for _, perm := range perms {
+		want[perm] = true
+	}
+	var authorize, revoke []egressPerm
+	for perm := range want {
+		if !have[perm] {
+			authorize = append(authorize, perm)
+		}
+	}
+	for perm := range have {
+		if !want[perm] {
+			revoke = append(revoke, perm)
+		}
+	}
+	if len(authorize) > 0 {
+		sortEgressPerms(authorize)
+		if err := e.egressPermsQuery("AuthorizeSecurityGroupEgress", groupId, authorize); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(revoke) > 0 {
+		sortEgressPerms(revoke)
+		if err := e.egressPermsQuery("RevokeSecurityGroupEgress", groupId, revoke); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// egressPermsInGroup returns the egress permissions of the
+// security group with the given id.
+func (e *environ) egressPermsInGroup(groupId string) (map[egressPerm]bool, error) {
+	var resp struct {
+		Groups []struct {
+			Id     string       `xml:"groupId"`
+			Egress []ec2.IPPerm `xml:"ipPermissionsEgress>item"`
+		} `xml:"securityGroupInfo>item"`
+	}
+	params := map[string]string{
+		"Action":    "DescribeSecurityGroups",
+		"GroupId.1": groupId,
+	}
+	if err := e.egressQuery(params, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(resp.Groups) != 1 {
+		return nil, errors.Errorf("expected one security group with id %q, got %d", groupId, len(resp.Groups))
+	}
+	perms := make(map[egressPerm]bool)
+	for _, p := range resp.Groups[0].Egress {
+		perm := egressPerm{protocol: p.Protocol}
+		if p.Protocol != "-1" {
+			perm.fromPort = p.FromPort
+			perm.toPort = p.ToPort
+		}
+		for _, cidr := range p.SourceIPs {
+			perm.cidr = cidr
+			perms[perm] = true
+		}
+		perm.cidr = ""
+		for _, g := range p.SourceGroups {
+			perm.groupId = g.Id
+			perms[perm] = true
+		}
+	}
+	return perms, nil
+}
+
+// egressPermsQuery authorizes or revokes, according to the action,
+// the given egress permissions of the security group.
+func (e *environ) egressPermsQuery(action, groupId string, perms []egressPerm) error {
+	params := map[string]string{
+		"Action":  action,
+		"GroupId": groupId,
+	}
+	for i, perm := range perms {
+		prefix := "IpPermissions." + strconv.Itoa(i+1)
+		params[prefix+".IpProtocol"] = perm.protocol
+		if perm.protocol != "-1" {
+			params[prefix+".FromPort"] = strconv.Itoa(perm.fromPort)
+			params[prefix+".ToPort"] = strconv.Itoa(perm.toPort)
+		}
+		if perm.cidr != "" {
+			params[prefix+".IpRanges.1.CidrIp"] = perm.cidr
+		} else {
+			params[prefix+".Groups.1.GroupId"] = perm.groupId
+		}
+	}
+	var resp ec2.SimpleResp
+	return errors.Trace(e.egressQuery(params, &resp))
+}
+
+// egressQuery sends a signed EC2 API request with the given parameters,
+// and decodes the response into resp.
+func (e *environ) egressQuery(params map[string]string, resp interface{}) error {
+	client := e.ec2()
+	req, err := http.NewRequest("GET", client.Region.EC2Endpoint, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := req.URL.Query()
+	for name, value := range params {
+		query.Add(name, value)
+	}
+	now := time.Now().In(time.UTC)
+	query.Add("Version", egressAPIVersion)
+	query.Add("Timestamp", now.Format(time.RFC3339))
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("x-amz-date", now.Format(aws.ISO8601BasicFormat))
+	if err := client.Sign(req, client.Auth); err != nil {
+		return errors.Trace(err)
+	}
+
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		var errResp struct {
+			RequestId string      `xml:"RequestID"`
+			Errors    []ec2.Error `xml:"Errors>Error"`
+		}
+		xml.NewDecoder(r.Body).Decode(&errResp)
+		var ec2Err ec2.Error
+		if len(errResp.Errors) > 0 {
+			ec2Err = errResp.Errors[0]
+		}
+		ec2Err.RequestId = errResp.RequestId
+		ec2Err.StatusCode = r.StatusCode
+		if ec2Err.Message == "" {
+			ec2Err.Message = r.Status
+		}
+		return &ec2Err
+	}
+	return xml.NewDecoder(r.Body).Decode(resp)
+}
+
+func sortEgressPerms(perms []egressPerm) {
+	sort.Sort(egressPermsByKey(perms))
+}
+
+type egressPermsByKey []egressPerm
+
+func (p egressPermsByKey) Len() int      { return len(p) }
+func (p egressPermsByKey) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p egressPermsByKey) Less(i, j int) bool {
+	a, b := p[i], p[j]
+	if a.cidr != b.cidr {
+		return a.cidr < b.cidr
+	}
+	if a.groupId != b.groupId {
+		return a.groupId < b.groupId
+	}
+	if a.protocol != b.protocol {
+		return a.protocol < b.protocol
+	}
+	if a.fromPort != b.fromPort {
+		return a.fromPort < b.fromPort
+	}
+	return a.toPort < b.toPort
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/ec2"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type egressSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&egressSuite{})
+
+func (*egressSuite) TestEgressPermsNoRules(c *gc.C) {
+	perms, err := ec2.EgressPerms(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms, jc.DeepEquals, []ec2.EgressPerm{
+		{"-1", 0, 0, "0.0.0.0/0"},
+	})
+}
+
+func (*egressSuite) TestEgressPerms(c *gc.C) {
+	perms, err := ec2.EgressPerms([]network.EgressRule{{
+		Name:             "app",
+		DestinationCIDRs: []string{"128.0.0.0/1"},
+		Ports: []network.PortRange{
+			network.MustParsePortRange("443/tcp"),
+			network.MustParsePortRange("8000-8080/tcp"),
+		},
+	}}, []string{"192.168.1.1", "2001:db8::1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(perms, jc.DeepEquals, []ec2.EgressPerm{
+		{"-1", 0, 0, "0.0.0.0/1"},
+		{"-1", 0, 0, "192.168.1.1/32"},
+		{"tcp", 443, 443, "128.0.0.0/1"},
+		{"tcp", 8000, 8080, "128.0.0.0/1"},
+	})
+}
+
+func (*egressSuite) TestEgressPermsIPv6NotSupported(c *gc.C) {
+	_, err := ec2.EgressPerms([]network.EgressRule{{
+		Name:             "app",
+		DestinationCIDRs: []string{"2001:db8::/32"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -16,6 +16,7 @@ import (
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	jujustorage "github.com/juju/juju/storage"
 )
 
@@ -399,3 +400,24 @@ var TestRegions = map[string]aws.Region{
 		EC2Endpoint: "https://ec2.endpoint.com",
 	},
 }
+
+// EgressPerm describes an egress permission as (protocol, from
+// port, to port, CIDR).
+type EgressPerm struct {
+	Protocol string
+	FromPort int
+	ToPort   int
+	CIDR     string
+}
+
+func EgressPerms(rules []network.EgressRule, allowed []string) ([]EgressPerm, error) {
+	perms, err := egressPerms(rules, allowed)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]EgressPerm, len(perms))
+	for i, p := range perms {
+		result[i] = EgressPerm{p.protocol, p.fromPort, p.toPort, p.cidr}
+	}
+	return result, nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	goosehttp "gopkg.in/goose.v1/http"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
+)
+
+var _ environs.EgressFirewaller = (*Environ)(nil)
+
+// neutronServiceType is the service type of the OpenStack networking
+// API, which manages egress security group rules.
+const neutronServiceType = "network"
+
+// SetMachineEgressRules is part of the environs.EgressFirewaller
+// interface. Egress rules are supported if the environ's firewaller
+// supports them.
+func (e *Environ) SetMachineEgressRules(machineId string, rules []network.EgressRule, allowed []string) error {
+	firewaller, ok := e.firewaller.(environs.EgressFirewaller)
+	if !ok {
+		return errors.NotSupportedf("egress rules")
+	}
+	return firewaller.SetMachineEgressRules(machineId, rules, allowed)
+}
+
+// egressRule is a single egress rule of a Neutron security group.
+// An empty protocol allows all protocols, with the ports ignored.
+type egressRule struct {
+	ethertype string
+	protocol  string
+	fromPort  int
+	toPort    int
+	cidr      string
+}
+
+// neutronRule holds the fields of a Neutron security group rule.
+type neutronRule struct {
+	Id              string  `json:"id,omitempty"`
+	SecurityGroupId string  `json:"security_group_id"`
+	Direction       string  `json:"direction"`
+	Ethertype       string  `json:"ethertype"`
+	Protocol        *string `json:"protocol"`
+	PortRangeMin    *int    `json:"port_range_min"`
+	PortRangeMax    *int    `json:"port_range_max"`
+	RemoteIPPrefix  *string `json:"remote_ip_prefix"`
+	RemoteGroupId   *string `json:"remote_group_id,omitempty"`
+}
+
+// SetMachineEgressRules is part of the environs.EgressFirewaller
+// interface.
+//
+// Egress rules are added to the machine's security group, and the
+// default rules allowing all egress traffic are removed from the
+// model's shared group, since OpenStack allows any traffic that is
+// allowed by one of an instance's groups. This is only possible in
+// the "instance" firewall mode, when the tenant's default group is
+// not used, and with the Neutron networking API.
+func (c *defaultFirewaller) SetMachineEgressRules(machineId string, rules []network.EgressRule, allowed []string) error {
+	if mode := c.environ.Config().FirewallMode(); mode != config.FwInstance {
+		return errors.NotSupportedf("egress rules with firewall mode %q", mode)
+	}
+	if c.environ.ecfg().useDefaultSecurityGroup() {
+		return errors.NotSupportedf("egress rules with the default security group")
+	}
+	if _, err := c.environ.client.MakeServiceURL(neutronServiceType, nil); err != nil {
+		return errors.NotSupportedf("egress rules without the networking API (%v)", err)
+	}
+	want, err := egressRules(rules, allowed)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machineGroupId, err := c.neutronGroupId(c.machineGroupName(machineId))
+	if err != nil {
+		return errors.Annotate(err, "cannot get machine security group")
+	}
+	if err := c.setEgressRules(machineGroupId, want); err != nil {
+		return errors.Annotatef(err, "cannot set egress rules for machine %s", machineId)
+	}
+	jujuGroupId, err := c.neutronGroupId(c.jujuGroupName())
+	if err != nil {
+		return errors.Annotate(err, "cannot get model security group")
+	}
+	// Every machine group is created with rules allowing all
+	// egress traffic, so this does not restrict any other machine.
+	if err := c.setEgressRules(jujuGroupId, nil); err != nil {
+		return errors.Annotate(err, "cannot remove egress rules from model security group")
+	}
+	logger.Infof("set egress rules for machine %s: %v", machineId, rules)
+	return nil
+}
+
+// egressRules returns the Neutron egress rules that enforce the given
+// rules: traffic is allowed to each destination covered by a rule on
+// the rule's ports, and to the allowed addresses and destinations not
+// covered by any rule on all ports.
+func egressRules(rules []network.EgressRule, allowed []string) ([]egressRule, error) {
+	var result, unrestricted []egressRule
+	covered := make(map[string][]string)
+	for _, rule := range rules {
+		for _, cidr := range rule.DestinationCIDRs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.NotValidf("destination CIDR %q", cidr)
+			}
+			ethertype := ethertypeForIP(ip)
+			covered[ethertype] = append(covered[ethertype], cidr)
+			for _, portRange := range rule.Ports {
+				result = append(result, egressRule{
+					ethertype: ethertype,
+					protocol:  strings.ToLower(portRange.Protocol),
+					fromPort:  portRange.FromPort,
+					toPort:    portRange.ToPort,
+					cidr:      cidr,
+				})
+			}
+		}
+	}
+	for _, family := range []struct {
+		ethertype string
+		all       string
+	}{{"IPv4", "0.0.0.0/0"}, {"IPv6", "::/0"}} {
+		uncovered, err := network.ExcludeCIDRs(family.all, covered[family.ethertype])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, cidr := range uncovered {
+			unrestricted = append(unrestricted, egressRule{ethertype: family.ethertype, cidr: cidr})
+		}
+	}
+	for _, addr := range allowed {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		ipNet := net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		if ip4 := ip.To4(); ip4 != nil {
+			ipNet = net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+		}
+		unrestricted = append(unrestricted, egressRule{ethertype: ethertypeForIP(ip), cidr: ipNet.String()})
+	}
+	return append(unrestricted, result...), nil
+}
+
+func ethertypeForIP(ip net.IP) string {
+	if ip.To4() != nil {
+		return "IPv4"
+	}
+	return "IPv6"
+}
+
+// neutronGroupId returns the id of the security group with the
+// given name.
+func (c *defaultFirewaller) neutronGroupId(name string) (string, error) {
+	var resp struct {
+		Groups []struct {
+			Id string `json:"id"`
+		} `json:"security_groups"`
+	}
+	params := url.Values{"name": {name}, "fields": {"id"}}
+	requestData := goosehttp.RequestData{Params: &params, RespValue: &resp}
+	err := c.environ.client.SendRequest("GET", neutronServiceType, "v2.0/security-groups", &requestData)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(resp.Groups) != 1 {
+		return "", errors.Errorf("expected one security group named %q, got %d", name, len(resp.Groups))
+	}
+	return resp.Groups[0].Id, nil
+}
+
+// setEgressRules replaces the egress rules of the security group with
+// the given ones. New rules are created before old ones are deleted,
+// so that traffic allowed both before and after is never interrupted.
+func (c *defaultFirewaller) setEgressRules(groupId string, rules []egressRule) error {
+	have, err := c.egressRulesInGroup(groupId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	want := make(map[egressRule]bool)
+	for _, rule := range rules {
+		want[rule] = true
+	}
+	var create []egressRule
+	for rule := range want {
+		if _, ok := have[rule]; !ok {
+			create = append(create, rule)
+		}
+	}
+	sort.Sort(egressRulesByKey(create))
+	for _, rule := range create {
+		if err := c.createEgressRule(groupId, rule); err != nil {
+			return errors.Annotatef(err, "cannot create egress rule %+v", rule)
+		}
+	}
+	var remove []string
+	for rule, id := range have {
+		if !want[rule] {
+			remove = append(remove, id)
+		}
+	}
+	sort.Strings(remove)
+	for _, id := range remove {
+		requestData := goosehttp.RequestData{ExpectedStatus: []int{http.StatusNoContent}}
+		err := c.environ.client.SendRequest("DELETE", neutronServiceType, "v2.0/security-group-rules/"+id, &requestData)
+		if err != nil {
+			return errors.Annotatef(err, "cannot delete egress rule %q", id)
+		}
+	}
+	return nil
+}
+
+// egressRulesInGroup returns the ids of the egress rules of the security
+// group with the given id. Rules allowing traffic to other groups are
+// never created by Juju, and are ignored.
+func (c *defaultFirewaller) egressRulesInGroup(groupId string) (map[egressRule]string, error) {
+	var resp struct {
+		Rules []neutronRule `json:"security_group_rules"`
+	}
+	params := url.Values{"security_group_id": {groupId}, "direction": {"egress"}}
+	requestData := goosehttp.RequestData{Params: &params, RespValue: &resp}
+	err := c.environ.client.SendRequest("GET", neutronServiceType, "v2.0/security-group-rules", &requestData)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules := make(map[egressRule]string)
+	for _, r := range resp.Rules {
+		if r.RemoteGroupId != nil && *r.RemoteGroupId != "" {
+			continue
+		}
+		rule := egressRule{ethertype: r.Ethertype}
+		if r.Protocol != nil {
+			rule.protocol = *r.Protocol
+			if r.PortRangeMin != nil && r.PortRangeMax != nil {
+				rule.fromPort, rule.toPort = *r.PortRangeMin, *r.PortRangeMax
+			}
+		}
+		switch {
+		case r.RemoteIPPrefix != nil:
+			rule.cidr = *r.RemoteIPPrefix
+		case r.Ethertype == "IPv6":
+			rule.cidr = "::/0"
+		default:
+			rule.cidr = "0.0.0.0/0"
+		}
+		rules[rule] = r.Id
+	}
+	return rules, nil
+}
+
+func (c *defaultFirewaller) createEgressRule(groupId string, rule egressRule) error {
+	req := neutronRule{
+		SecurityGroupId: groupId,
+		Direction:       "egress",
+		Ethertype:       rule.ethertype,
+		RemoteIPPrefix:  &rule.cidr,
+	}
+	if rule.protocol != "" {
+		req.Protocol = &rule.protocol
+		req.PortRangeMin = &rule.fromPort
+		req.PortRangeMax = &rule.toPort
+	}
+	requestData := goosehttp.RequestData{
+		ReqValue:       map[string]interface{}{"security_group_rule": req},
+		ExpectedStatus: []int{http.StatusCreated},
+	}
+	return c.environ.client.SendRequest("POST", neutronServiceType, "v2.0/security-group-rules", &requestData)
+}
+
+type egressRulesByKey []egressRule
+
+func (r egressRulesByKey) Len() int      { return len(r) }
+func (r egressRulesByKey) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r egressRulesByKey) Less(i, j int) bool {
+	a, b := r[i], r[j]
+	if a.cidr != b.cidr {
+		return a.cidr < b.cidr
+	}
+	if a.protocol != b.protocol {
+		return a.protocol < b.protocol
+	}
+	if a.fromPort != b.fromPort {
+		return a.fromPort < b.fromPort
+	}
+	return a.toPort < b.toPort
+}
@@ -0,0 +1,45 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/openstack"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type egressSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&egressSuite{})
+
+func (*egressSuite) TestEgressRulesNoRules(c *gc.C) {
+	rules, err := openstack.EgressRules(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []openstack.EgressRule{
+		{"IPv4", "", 0, 0, "0.0.0.0/0"},
+		{"IPv6", "", 0, 0, "::/0"},
+	})
+}
+
+func (*egressSuite) TestEgressRules(c *gc.C) {
+	rules, err := openstack.EgressRules([]network.EgressRule{{
+		Name:             "app",
+		DestinationCIDRs: []string{"128.0.0.0/1", "8000::/1"},
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}}, []string{"192.168.1.1", "2001:db8::1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []openstack.EgressRule{
+		{"IPv4", "", 0, 0, "0.0.0.0/1"},
+		{"IPv6", "", 0, 0, "::/1"},
+		{"IPv4", "", 0, 0, "192.168.1.1/32"},
+		{"IPv6", "", 0, 0, "2001:db8::1/128"},
+		{"IPv4", "tcp", 443, 443, "128.0.0.0/1"},
+		{"IPv6", "tcp", 443, 443, "8000::/1"},
+	})
+}
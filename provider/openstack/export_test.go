@@ -422,3 +422,25 @@ var MakeServiceURL = &makeServiceURL
 var ProviderInstance = providerInstance
 
 var GetVolumeEndpointURL = getVolumeEndpointURL
+
+// EgressRule describes a Neutron egress rule as (ethertype, protocol,
+// from port, to port, CIDR).
+type EgressRule struct {
+	Ethertype string
+	Protocol  string
+	FromPort  int
+	ToPort    int
+	CIDR      string
+}
+
+func EgressRules(rules []network.EgressRule, allowed []string) ([]EgressRule, error) {
+	egress, err := egressRules(rules, allowed)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]EgressRule, len(egress))
+	for i, r := range egress {
+		result[i] = EgressRule{r.ethertype, r.protocol, r.fromPort, r.toPort, r.cidr}
+	}
+	return result, nil
+}
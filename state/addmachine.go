@@ -588,8 +588,22 @@ func (st *State) machineStorageOps(
 		})
 	}
 
-	// TODO(axw) handle args.filesystemAttachments, args.volumeAttachments
-	// when we handle attaching to existing (e.g. shared) storage.
+	// Attach existing (i.e. shared) filesystems.
+	for filesystemTag, attachment := range args.filesystemAttachments {
+		filesystemOps = append(filesystemOps, txn.Op{
+			C:      filesystemsC,
+			Id:     filesystemTag.Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		})
+		fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
+			tag:    filesystemTag,
+			params: attachment,
+		})
+	}
+
+	// TODO(axw) handle args.volumeAttachments when we
+	// support attaching to existing (e.g. shared) volumes.
 
 	ops := make([]txn.Op, 0, len(filesystemOps)+len(volumeOps)+len(fsAttachments)+len(volumeAttachments))
 	if len(fsAttachments) > 0 {
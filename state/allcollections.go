@@ -198,6 +198,14 @@ func allCollections() collectionSchema {
 		// to ensure various IDs aren't reused.
 		sequenceC: {},
 
+		// This collection holds the progress of a staged agent upgrade,
+		// which releases the new agent version to machines in batches.
+		upgradeRolloutsC: {},
+
+		// This collection records the model's most recent agent
+		// upgrade, so that it can be rolled back.
+		upgradeRollbacksC: {},
+
 		// This collection holds lease data. It's currently only used to
 		// implement service leadership, but is namespaced and available
 		// for use by other clients in future.
@@ -225,6 +233,10 @@ func allCollections() collectionSchema {
 		},
 		minUnitsC: {},
 
+		// This collection holds the key/value data charms persist for
+		// their units between hooks, with the state-set hook tool.
+		unitStatesC: {},
+
 		// This collection holds documents that indicate units which are queued
 		// to be assigned to machines. It is used exclusively by the
 		// AssignUnitWorker.
@@ -320,6 +332,7 @@ func allCollections() collectionSchema {
 			}},
 		},
 		linkLayerDevicesRefsC: {},
+		firewallRulesC:        {},
 		ipAddressesC: {
 			indexes: []mgo.Index{{
 				Key:    []string{"providerid"},
@@ -378,9 +391,30 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection holds the most recent hook, action and
+		// juju-run executions for each unit, with their timings.
+		hookExecutionsC: {},
+
 		// This collection holds information about cloud image metadata.
 		cloudimagemetadataC: {},
 
+		// These collections hold the secrets stored by charms, and the
+		// encrypted values of each of their revisions. The key used to
+		// encrypt them is never stored in the database; it is held in
+		// the controller agents' config.
+		secretsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "owner"},
+			}, {
+				Key: []string{"model-uuid", "grants"},
+			}},
+		},
+		secretRevisionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "secret-id", "revision"},
+			}},
+		},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -411,8 +445,10 @@ const (
 	controllersC             = "controllers"
 	filesystemAttachmentsC   = "filesystemAttachments"
 	filesystemsC             = "filesystems"
+	firewallRulesC           = "firewallRules"
 	guimetadataC             = "guimetadata"
 	guisettingsC             = "guisettings"
+	hookExecutionsC          = "hookexecutions"
 	instanceDataC            = "instanceData"
 	legacyipaddressesC       = "ipaddresses"
 	leaseC                   = "lease"
@@ -434,6 +470,8 @@ const (
 	relationScopesC          = "relationscopes"
 	relationsC               = "relations"
 	restoreInfoC             = "restoreInfo"
+	secretRevisionsC         = "secretRevisions"
+	secretsC                 = "secrets"
 	sequenceC                = "sequence"
 	servicesC                = "services"
 	endpointBindingsC        = "endpointbindings"
@@ -453,8 +491,11 @@ const (
 	toolsmetadataC           = "toolsmetadata"
 	txnLogC                  = "txns.log"
 	txnsC                    = "txns"
+	unitStatesC              = "unitstates"
 	unitsC                   = "units"
 	upgradeInfoC             = "upgradeInfo"
+	upgradeRolloutsC         = "upgradeRollouts"
+	upgradeRollbacksC        = "upgradeRollbacks"
 	userLastLoginC           = "userLastLogin"
 	usermodelnameC           = "usermodelname"
 	usersC                   = "users"
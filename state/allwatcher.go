@@ -270,6 +270,23 @@ func unitAndAgentStatus(st *State, name string) (unitStatus, agentStatus *status
 	return &unitStatusResult, &agentStatusResult, nil
 }
 
+// unitHealth returns the health of the named unit, or an empty status
+// if none has been reported.
+func unitHealth(st *State, name string) (multiwatcher.StatusInfo, error) {
+	health, err := getStatus(st, unitHealthGlobalKey(name), "unit health")
+	if errors.IsNotFound(err) {
+		return multiwatcher.StatusInfo{}, nil
+	} else if err != nil {
+		return multiwatcher.StatusInfo{}, errors.Trace(err)
+	}
+	return multiwatcher.StatusInfo{
+		Current: health.Status,
+		Message: health.Message,
+		Data:    normaliseStatusData(health.Data),
+		Since:   health.Since,
+	}, nil
+}
+
 func (u *backingUnit) updated(st *State, store *multiwatcherStore, id string) error {
 	info := &multiwatcher.UnitInfo{
 		ModelUUID:   st.ModelUUID(),
@@ -308,6 +325,12 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id string) er
 			Since:   agentStatus.Since,
 		}
 
+		health, err := unitHealth(st, u.Name)
+		if err != nil {
+			return errors.Annotatef(err, "reading health for %q", u.Name)
+		}
+		info.Health = health
+
 		portRanges, compatiblePorts, err := getUnitPortRangesAndPorts(st, u.Name)
 		if err != nil {
 			return errors.Trace(err)
@@ -321,6 +344,7 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id string) er
 		// Unit and workload status.
 		info.JujuStatus = oldInfo.JujuStatus
 		info.WorkloadStatus = oldInfo.WorkloadStatus
+		info.Health = oldInfo.Health
 		info.Ports = oldInfo.Ports
 		info.PortRanges = oldInfo.PortRanges
 	}
@@ -618,6 +642,16 @@ func (s *backingStatus) updated(st *State, store *multiwatcherStore, id string)
 		return nil
 	case *multiwatcher.UnitInfo:
 		newInfo := *info
+		// Unit health is agent-managed and independent of the
+		// unit's other statuses, so it never affects the service.
+		if strings.HasSuffix(id, "#sat#health") {
+			newInfo.Health.Current = s.Status
+			newInfo.Health.Message = s.StatusInfo
+			newInfo.Health.Data = normaliseStatusData(s.StatusData)
+			newInfo.Health.Since = unixNanoToTime(s.Updated)
+			info0 = &newInfo
+			break
+		}
 		// Get the unit's current recorded status from state.
 		// It's needed to reset the unit status when a unit comes off error.
 		statusInfo, err := getStatus(st, unitGlobalKey(newInfo.Name), "unit")
@@ -945,6 +979,7 @@ func backingEntityIdForGlobalKey(modelUUID, key string) (multiwatcher.EntityId,
 		}).EntityId(), true
 	case 'u':
 		id = strings.TrimSuffix(id, "#charm")
+		id = strings.TrimSuffix(id, "#sat#health")
 		return (&multiwatcher.UnitInfo{
 			ModelUUID: modelUUID,
 			Name:      id,
@@ -360,6 +360,7 @@ func substNilSinceTimeForEntities(c *gc.C, entities []multiwatcher.EntityInfo) {
 		if unitInfo, ok := entity.(*multiwatcher.UnitInfo); ok {
 			substNilSinceTimeForStatus(c, &unitInfo.WorkloadStatus)
 			substNilSinceTimeForStatus(c, &unitInfo.JujuStatus)
+			substNilSinceTimeForStatus(c, &unitInfo.Health)
 			entities[i] = unitInfo
 		}
 		if serviceInfo, ok := entity.(*multiwatcher.ServiceInfo); ok {
@@ -380,6 +381,7 @@ func substNilSinceTimeForEntityNoCheck(entity multiwatcher.EntityInfo) multiwatc
 	if unitInfo, ok := entity.(*multiwatcher.UnitInfo); ok {
 		substNilSinceTimeForStatusNoCheck(&unitInfo.WorkloadStatus)
 		substNilSinceTimeForStatusNoCheck(&unitInfo.JujuStatus)
+		substNilSinceTimeForStatusNoCheck(&unitInfo.Health)
 		return unitInfo
 	}
 	if serviceInfo, ok := entity.(*multiwatcher.ServiceInfo); ok {
@@ -2662,6 +2664,58 @@ func testChangeUnits(c *gc.C, owner names.UserTag, runChangeTests func(*gc.C, []
 						},
 					}}}
 		},
+		func(c *gc.C, st *State) changeTestCase {
+			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), owner)
+			u, err := wordpress.AddUnit()
+			c.Assert(err, jc.ErrorIsNil)
+			err = u.SetHealth(status.StatusUnhealthy, "http: connection refused", nil)
+			c.Assert(err, jc.ErrorIsNil)
+
+			return changeTestCase{
+				about: "health is changed if the unit exists in the store",
+				initialContents: []multiwatcher.EntityInfo{&multiwatcher.UnitInfo{
+					ModelUUID: st.ModelUUID(),
+					Name:      "wordpress/0",
+					Service:   "wordpress",
+					JujuStatus: multiwatcher.StatusInfo{
+						Current: "idle",
+						Message: "",
+						Data:    map[string]interface{}{},
+						Since:   &now,
+					},
+					WorkloadStatus: multiwatcher.StatusInfo{
+						Current: "maintenance",
+						Message: "working",
+						Data:    map[string]interface{}{},
+						Since:   &now,
+					},
+				}},
+				change: watcher.Change{
+					C:  "statuses",
+					Id: st.docID("u#wordpress/0#sat#health"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.UnitInfo{
+						ModelUUID: st.ModelUUID(),
+						Name:      "wordpress/0",
+						Service:   "wordpress",
+						WorkloadStatus: multiwatcher.StatusInfo{
+							Current: "maintenance",
+							Message: "working",
+							Data:    map[string]interface{}{},
+						},
+						JujuStatus: multiwatcher.StatusInfo{
+							Current: "idle",
+							Message: "",
+							Data:    map[string]interface{}{},
+						},
+						Health: multiwatcher.StatusInfo{
+							Current: "unhealthy",
+							Message: "http: connection refused",
+							Data:    map[string]interface{}{},
+						},
+					}}}
+		},
 		func(c *gc.C, st *State) changeTestCase {
 			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), owner)
 			u, err := wordpress.AddUnit()
@@ -84,12 +84,17 @@ func StoreArchive(stor filestorage.FileStorage, meta *Metadata, file io.Reader)
 // Backups is an abstraction around all juju backup-related functionality.
 type Backups interface {
 	// Create creates and stores a new juju backup archive. It updates
-	// the provided metadata.
-	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error
+	// the provided metadata. If key is not nil, the archive is
+	// encrypted with it before being stored.
+	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key *EncryptionKey) error
 
 	// Add stores the backup archive and returns its new ID.
 	Add(archive io.Reader, meta *Metadata) (string, error)
 
+	// RecordFailure stores the metadata of a backup that could not
+	// be created, without an archive, and returns its new ID.
+	RecordFailure(meta *Metadata, failure error) (string, error)
+
 	// Get returns the metadata and archive file associated with the ID.
 	Get(id string) (*Metadata, io.ReadCloser, error)
 
@@ -119,7 +124,12 @@ func NewBackups(stor filestorage.FileStorage) Backups {
 
 // Create creates and stores a new juju backup archive and updates the
 // provided metadata.
-func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
+func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key *EncryptionKey) error {
+	if key != nil {
+		if err := key.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	// TODO(fwereade): 2016-03-17 lp:1558657
 	meta.Started = time.Now().UTC()
 
@@ -149,6 +159,17 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	}
 	defer result.archiveFile.Close()
 
+	// Encrypt the archive, so that the keys and certificates it
+	// holds are never stored in plaintext.
+	if key != nil {
+		result, err = encryptResult(result, *key)
+		if err != nil {
+			return errors.Annotate(err, "while encrypting backup archive")
+		}
+		defer result.archiveFile.Close()
+		meta.Encryption = key.Scheme()
+	}
+
 	// Finalize the metadata.
 	err = finishMeta(meta, result)
 	if err != nil {
@@ -175,6 +196,18 @@ func (b *backups) Add(archive io.Reader, meta *Metadata) (string, error) {
 	return meta.ID(), nil
 }
 
+// RecordFailure stores the metadata of a backup that could not be
+// created, without an archive, and returns its new ID.
+func (b *backups) RecordFailure(meta *Metadata, failure error) (string, error) {
+	meta.Failure = failure.Error()
+	id, err := b.storage.Add(meta, nil)
+	if err != nil {
+		return "", errors.Annotate(err, "while storing backup failure")
+	}
+	meta.SetID(id)
+	return id, nil
+}
+
 // Get retrieves the associated metadata and archive file from model storage.
 func (b *backups) Get(id string) (*Metadata, io.ReadCloser, error) {
 	rawmeta, archiveFile, err := b.storage.Get(id)
@@ -6,6 +6,7 @@
 package backups
 
 import (
+	"io"
 	"net"
 	"strconv"
 
@@ -68,7 +69,18 @@ func (b *backups) Restore(backupId string, args RestoreArgs) (names.Tag, error)
 
 	defer backupReader.Close()
 
-	workspace, err := NewArchiveWorkspaceReader(backupReader)
+	var archive io.Reader = backupReader
+	if meta.Encryption != "" {
+		if args.DecryptionKey.IsZero() {
+			return nil, errors.Errorf("backup %q is encrypted (%s); a key is needed to restore it", backupId, meta.Encryption)
+		}
+		archive, err = Decrypt(backupReader, args.DecryptionKey)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot decrypt backup file")
+		}
+	}
+
+	workspace, err := NewArchiveWorkspaceReader(archive)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot unpack backup file")
 	}
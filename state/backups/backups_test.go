@@ -5,11 +5,13 @@ package backups_test
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/filestorage"
 	"github.com/juju/utils/set"
 	gc "gopkg.in/check.v1"
 
@@ -56,11 +58,33 @@ func (s *backupsSuite) checkFailure(c *gc.C, expected string) {
 	dbInfo := backups.DBInfo{"a", "b", "c", targets}
 	meta := backupstesting.NewMetadataStarted()
 	meta.Notes = "some notes"
-	err := s.api.Create(meta, &paths, &dbInfo)
+	err := s.api.Create(meta, &paths, &dbInfo, nil)
 
 	c.Check(err, gc.ErrorMatches, expected)
 }
 
+func (s *backupsSuite) TestRecordFailure(c *gc.C) {
+	s.Storage.ID = "spam"
+	meta := backupstesting.NewMetadataStarted()
+	meta.Scheduled = true
+
+	id, err := s.api.RecordFailure(meta, errors.New("boom"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(id, gc.Equals, "spam")
+	c.Check(meta.ID(), gc.Equals, "spam")
+	c.Check(meta.Failure, gc.Equals, "boom")
+	s.Storage.CheckCalled(c, "", meta, nil, "Add")
+}
+
+func (s *backupsSuite) TestRecordFailureError(c *gc.C) {
+	s.Storage.Error = errors.New("failed!")
+	meta := backupstesting.NewMetadataStarted()
+
+	_, err := s.api.RecordFailure(meta, errors.New("boom"))
+	c.Check(err, gc.ErrorMatches, "while storing backup failure: failed!")
+}
+
 func (s *backupsSuite) TestNewBackups(c *gc.C) {
 	api := backups.NewBackups(s.Storage)
 
@@ -96,7 +120,7 @@ func (s *backupsSuite) TestCreateOkay(c *gc.C) {
 	meta := backupstesting.NewMetadataStarted()
 	backupstesting.SetOrigin(meta, "<model ID>", "<machine ID>", "<hostname>")
 	meta.Notes = "some notes"
-	err := s.api.Create(meta, &paths, &dbInfo)
+	err := s.api.Create(meta, &paths, &dbInfo, nil)
 
 	// Test the call values.
 	s.Storage.CheckCalled(c, "spam", meta, archiveFile, "Add", "Metadata")
@@ -132,6 +156,64 @@ func (s *backupsSuite) TestCreateOkay(c *gc.C) {
 	c.Check(string(data), gc.Equals, "<compressed tarball>")
 }
 
+// archiveCapture is a FileStorage that keeps a copy of the archive
+// it is given, which is otherwise closed once Create returns.
+type archiveCapture struct {
+	*backupstesting.FakeStorage
+	data []byte
+}
+
+func (s *archiveCapture) Add(meta filestorage.Metadata, file io.Reader) (string, error) {
+	var err error
+	if s.data, err = ioutil.ReadAll(file); err != nil {
+		return "", err
+	}
+	return s.FakeStorage.Add(meta, file)
+}
+
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	archiveFile := ioutil.NopCloser(bytes.NewBufferString("<compressed tarball>"))
+	result := backups.NewTestCreateResult(archiveFile, 20, "<checksum>")
+	_, testCreate := backups.NewTestCreate(result)
+	s.PatchValue(backups.RunCreate, testCreate)
+	s.PatchValue(backups.TestGetFilesToBackUp, func(string, *backups.Paths, string) ([]string, error) {
+		return []string{"<some file>"}, nil
+	})
+	s.PatchValue(backups.GetDBDumper, func(*backups.DBInfo) (backups.DBDumper, error) {
+		return nil, nil
+	})
+	s.setStored("spam")
+	stor := &archiveCapture{FakeStorage: s.Storage}
+	api := backups.NewBackups(stor)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju")}
+	meta := backupstesting.NewMetadataStarted()
+	key := &backups.EncryptionKey{Passphrase: "sekrit"}
+	err := api.Create(meta, &paths, &dbInfo, key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(meta.Encryption, gc.Equals, backups.EncryptionPassphrase)
+	c.Check(meta.Size(), gc.Equals, int64(len(stor.data)))
+	c.Check(meta.Checksum(), gc.Not(gc.Equals), "<checksum>")
+	c.Check(bytes.Contains(stor.data, []byte("<compressed tarball>")), jc.IsFalse)
+
+	r, err := backups.Decrypt(bytes.NewReader(stor.data), backups.DecryptionKey{Passphrase: "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<compressed tarball>")
+}
+
+func (s *backupsSuite) TestCreateInvalidKey(c *gc.C) {
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju")}
+	meta := backupstesting.NewMetadataStarted()
+	err := s.api.Create(meta, &paths, &dbInfo, &backups.EncryptionKey{})
+	c.Check(err, gc.ErrorMatches, "empty encryption key not valid")
+	c.Check(s.Storage.Calls, gc.HasLen, 0)
+}
+
 func (s *backupsSuite) TestCreateFailToListFiles(c *gc.C) {
 	s.PatchValue(backups.TestGetFilesToBackUp, func(root string, paths *backups.Paths, oldmachine string) ([]string, error) {
 		return nil, errors.New("failed!")
@@ -84,6 +84,41 @@ func create(args *createArgs) (_ *createResult, err error) {
 	return result, nil
 }
 
+// encryptResult encrypts the archive in the result into a new temp
+// file, and returns a result for that file. As with create, the file
+// is deleted while its handle remains readable.
+func encryptResult(result *createResult, key EncryptionKey) (_ *createResult, err error) {
+	file, err := ioutil.TempFile("", tempPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "while creating encrypted archive file")
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+	if err := os.Remove(file.Name()); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	hasher := hash.NewHashingWriter(file, sha1.New())
+	if err := Encrypt(hasher, result.archiveFile, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	size, err := file.Seek(0, os.SEEK_CUR)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &createResult{
+		archiveFile: file,
+		size:        size,
+		checksum:    hasher.Base64Sum(),
+	}, nil
+}
+
 // builder exposes the machinery for creating a backup of juju's state.
 type builder struct {
 	// rootDir is the root of the archive workspace.
@@ -0,0 +1,53 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// Destination is somewhere outside the controller to which completed
+// backups are copied, so that they survive the loss of the
+// controllers themselves.
+type Destination interface {
+	// Put copies the backup archive and its metadata to the
+	// destination, and verifies the checksum of the copy.
+	Put(meta *Metadata, archive io.Reader) error
+
+	// List returns the metadata for all backups held at the
+	// destination.
+	List() ([]*Metadata, error)
+}
+
+// NewDestination returns the destination configured for backups in
+// the given model config, or nil if none is configured.
+func NewDestination(cfg *config.Config) (Destination, error) {
+	s3Config, ok := cfg.BackupS3()
+	if !ok {
+		return nil, nil
+	}
+	dest, err := newS3Destination(s3Config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return dest, nil
+}
+
+// Push copies the stored backup with the given ID to the destination.
+func Push(b Backups, dest Destination, id string) error {
+	meta, archive, err := b.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	if err := dest.Put(meta, archive); err != nil {
+		return errors.Annotatef(err, "cannot copy backup %q", id)
+	}
+	logger.Infof("copied backup %q to remote storage", id)
+	return nil
+}
@@ -0,0 +1,407 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The encryption schemes supported for backup archives. An archive
+// with no encryption has an empty scheme.
+const (
+	// EncryptionPassphrase identifies archives encrypted with a key
+	// derived from a passphrase using scrypt.
+	EncryptionPassphrase = "scrypt-aes256-gcm"
+
+	// EncryptionPublicKey identifies archives encrypted with a random
+	// key, which is itself encrypted to an RSA public key.
+	EncryptionPublicKey = "rsa-oaep-aes256-gcm"
+)
+
+const (
+	// encryptedMagic starts every encrypted archive, and is followed
+	// by the scheme name on the same line.
+	encryptedMagic = "juju-backup-encrypted:"
+
+	// chunkSize is the amount of plaintext sealed at a time.
+	chunkSize = 64 * 1024
+
+	keySize  = 32
+	saltSize = 16
+
+	// scrypt cost parameters, as recommended for interactive use.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// EncryptionKey describes how a backup archive should be encrypted.
+// Exactly one of its fields should be set.
+type EncryptionKey struct {
+	// Passphrase is used to derive the archive key.
+	Passphrase string
+
+	// PublicKey holds a PEM-encoded RSA public key to which the
+	// archive key is encrypted.
+	PublicKey string
+}
+
+// Scheme returns the name of the encryption scheme used by the key.
+func (k EncryptionKey) Scheme() string {
+	if k.PublicKey != "" {
+		return EncryptionPublicKey
+	}
+	return EncryptionPassphrase
+}
+
+// Validate returns an error if the key is not usable.
+func (k EncryptionKey) Validate() error {
+	switch {
+	case k.Passphrase == "" && k.PublicKey == "":
+		return errors.NotValidf("empty encryption key")
+	case k.Passphrase != "" && k.PublicKey != "":
+		return errors.NotValidf("both passphrase and public key")
+	case k.PublicKey != "":
+		if _, err := ParsePublicKey(k.PublicKey); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// DecryptionKey holds what is needed to decrypt a backup archive.
+type DecryptionKey struct {
+	// Passphrase is used for archives encrypted with
+	// EncryptionPassphrase.
+	Passphrase string
+
+	// PrivateKey holds a PEM-encoded RSA private key, used for
+	// archives encrypted with EncryptionPublicKey.
+	PrivateKey string
+}
+
+// IsZero reports whether no key material has been supplied.
+func (k DecryptionKey) IsZero() bool {
+	return k.Passphrase == "" && k.PrivateKey == ""
+}
+
+// ParsePublicKey parses a PEM-encoded RSA public key, in either PKIX
+// or PKCS#1 form.
+func ParsePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.NotValidf("public key (no PEM data)")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return parsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse public key")
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.NotValidf("public key of type %T", key)
+	}
+	return rsaKey, nil
+}
+
+// parsePKCS1PublicKey parses an ASN.1 DER-encoded PKCS#1 RSA public
+// key. The x509 package only gained a parser for these in Go 1.10, so
+// the structure is unmarshalled directly.
+func parsePKCS1PublicKey(der []byte) (*rsa.PublicKey, error) {
+	var key struct {
+		N *big.Int
+		E int
+	}
+	rest, err := asn1.Unmarshal(der, &key)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse public key")
+	}
+	if len(rest) > 0 {
+		return nil, errors.NotValidf("public key (trailing data)")
+	}
+	if key.N.Sign() <= 0 || key.E <= 0 {
+		return nil, errors.NotValidf("public key (non-positive modulus or exponent)")
+	}
+	return &rsa.PublicKey{N: key.N, E: key.E}, nil
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA private key, in either
+// PKCS#1 or PKCS#8 form.
+func ParsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.NotValidf("private key (no PEM data)")
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		return key, errors.Annotate(err, "cannot parse private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.NotValidf("private key of type %T", key)
+	}
+	return rsaKey, nil
+}
+
+// ArchiveEncryption returns the encryption scheme of the archive read
+// from the given file, or "" if the archive is not encrypted. The file
+// is left positioned at its start.
+func ArchiveEncryption(archive io.ReadSeeker) (string, error) {
+	header := make([]byte, len(encryptedMagic)+64)
+	n, err := io.ReadFull(archive, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", errors.Trace(err)
+	}
+	if _, err := archive.Seek(0, os.SEEK_SET); err != nil {
+		return "", errors.Trace(err)
+	}
+	header = header[:n]
+	if !bytes.HasPrefix(header, []byte(encryptedMagic)) {
+		return "", nil
+	}
+	header = header[len(encryptedMagic):]
+	end := bytes.IndexByte(header, '\n')
+	if end < 0 {
+		return "", errors.New("invalid encrypted archive header")
+	}
+	return string(header[:end]), nil
+}
+
+// Encrypt writes the encrypted form of the archive read from r to w.
+// The archive is sealed in chunks with AES-256-GCM, so that truncation
+// or tampering is detected when it is decrypted.
+func Encrypt(w io.Writer, r io.Reader, key EncryptionKey) error {
+	if err := key.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	scheme := key.Scheme()
+	header := bytes.NewBufferString(encryptedMagic + scheme + "\n")
+
+	var dataKey []byte
+	switch scheme {
+	case EncryptionPassphrase:
+		salt := make([]byte, saltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return errors.Trace(err)
+		}
+		derived, err := passphraseKey(key.Passphrase, salt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		dataKey = derived
+		header.Write(salt)
+	case EncryptionPublicKey:
+		publicKey, err := ParsePublicKey(key.PublicKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		dataKey = make([]byte, keySize)
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return errors.Trace(err)
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, dataKey, []byte(scheme))
+		if err != nil {
+			return errors.Annotate(err, "cannot encrypt archive key")
+		}
+		binary.Write(header, binary.BigEndian, uint16(len(wrapped)))
+		header.Write(wrapped)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return errors.Trace(err)
+	}
+
+	in := bufio.NewReaderSize(r, chunkSize)
+	plain := make([]byte, chunkSize)
+	nonce := make([]byte, aead.NonceSize())
+	var sealed []byte
+	for counter := uint64(0); ; counter++ {
+		n, err := io.ReadFull(in, plain)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return errors.Trace(err)
+		}
+		final := n < chunkSize
+		if !final {
+			if _, err := in.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+		sealed = aead.Seal(sealed[:0], nonce, plain[:n], chunkData(final))
+		if err := binary.Write(w, binary.BigEndian, uint32(len(sealed))); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := w.Write(sealed); err != nil {
+			return errors.Trace(err)
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// Decrypt returns a reader that yields the plaintext of the encrypted
+// archive read from r.
+func Decrypt(r io.Reader, key DecryptionKey) (io.Reader, error) {
+	in := bufio.NewReader(r)
+	line, err := in.ReadString('\n')
+	if err != nil || len(line) <= len(encryptedMagic) || line[:len(encryptedMagic)] != encryptedMagic {
+		return nil, errors.New("archive is not encrypted")
+	}
+	scheme := line[len(encryptedMagic) : len(line)-1]
+
+	var dataKey []byte
+	switch scheme {
+	case EncryptionPassphrase:
+		if key.Passphrase == "" {
+			return nil, errors.Errorf("archive is encrypted with a passphrase")
+		}
+		salt := make([]byte, saltSize)
+		if _, err := io.ReadFull(in, salt); err != nil {
+			return nil, errors.Annotate(err, "cannot read archive header")
+		}
+		if dataKey, err = passphraseKey(key.Passphrase, salt); err != nil {
+			return nil, errors.Trace(err)
+		}
+	case EncryptionPublicKey:
+		if key.PrivateKey == "" {
+			return nil, errors.Errorf("archive is encrypted to a public key; a private key is needed")
+		}
+		privateKey, err := ParsePrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var size uint16
+		if err := binary.Read(in, binary.BigEndian, &size); err != nil {
+			return nil, errors.Annotate(err, "cannot read archive header")
+		}
+		wrapped := make([]byte, size)
+		if _, err := io.ReadFull(in, wrapped); err != nil {
+			return nil, errors.Annotate(err, "cannot read archive header")
+		}
+		dataKey, err = rsa.DecryptOAEP(sha256.New(), nil, privateKey, wrapped, []byte(scheme))
+		if err != nil {
+			return nil, errors.New("cannot decrypt archive key: wrong private key")
+		}
+	default:
+		return nil, errors.NotSupportedf("archive encryption scheme %q", scheme)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &decryptingReader{
+		in:    in,
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// decryptingReader opens the sealed chunks of an encrypted archive
+// as they are read.
+type decryptingReader struct {
+	in      io.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+	sealed  []byte
+	plain   []byte
+	done    bool
+}
+
+// Read is part of io.Reader.
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextChunk(); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) nextChunk() error {
+	var size uint32
+	if err := binary.Read(r.in, binary.BigEndian, &size); err != nil {
+		if err == io.EOF {
+			return errors.New("encrypted archive is truncated")
+		}
+		return errors.Trace(err)
+	}
+	if size > chunkSize+uint32(r.aead.Overhead()) {
+		return errors.New("encrypted archive is corrupt")
+	}
+	if cap(r.sealed) < int(size) {
+		r.sealed = make([]byte, size)
+	}
+	r.sealed = r.sealed[:size]
+	if _, err := io.ReadFull(r.in, r.sealed); err != nil {
+		return errors.New("encrypted archive is truncated")
+	}
+	binary.BigEndian.PutUint64(r.nonce[len(r.nonce)-8:], r.counter)
+	r.counter++
+	// A chunk opens with exactly one of the two additional data
+	// values, which tells us whether it is the last one.
+	plain, err := r.aead.Open(r.plain[:0], r.nonce, r.sealed, chunkData(false))
+	if err != nil {
+		plain, err = r.aead.Open(r.plain[:0], r.nonce, r.sealed, chunkData(true))
+		if err != nil {
+			return errors.New("cannot decrypt archive: wrong key or corrupt data")
+		}
+		r.done = true
+	}
+	r.plain = plain
+	return nil
+}
+
+func passphraseKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	return key, errors.Annotate(err, "cannot derive key from passphrase")
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkData(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,215 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+)
+
+type encryptionSuite struct {
+	testing.IsolationSuite
+
+	publicKey  string
+	privateKey string
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+func (s *encryptionSuite) SetUpSuite(c *gc.C) {
+	s.IsolationSuite.SetUpSuite(c)
+	s.publicKey, s.privateKey = generateKeyPair(c)
+}
+
+// generateKeyPair returns a new PEM-encoded RSA key pair.
+func generateKeyPair(c *gc.C) (public, private string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	public = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	private = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+	return public, private
+}
+
+// archiveData returns size bytes of random data.
+func archiveData(c *gc.C, size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *encryptionSuite) encrypt(c *gc.C, data []byte, key backups.EncryptionKey) []byte {
+	var buf bytes.Buffer
+	err := backups.Encrypt(&buf, bytes.NewReader(data), key)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *encryptionSuite) checkRoundTrip(c *gc.C, size int, enc backups.EncryptionKey, dec backups.DecryptionKey) {
+	data := archiveData(c, size)
+	encrypted := s.encrypt(c, data, enc)
+	if size > 0 {
+		c.Check(bytes.Contains(encrypted, data), jc.IsFalse)
+	}
+
+	r, err := backups.Decrypt(bytes.NewReader(encrypted), dec)
+	c.Assert(err, jc.ErrorIsNil)
+	decrypted, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(decrypted, jc.DeepEquals, data)
+}
+
+func (s *encryptionSuite) TestPassphraseRoundTrip(c *gc.C) {
+	enc := backups.EncryptionKey{Passphrase: "sekrit"}
+	dec := backups.DecryptionKey{Passphrase: "sekrit"}
+	for _, size := range []int{0, 100, 64 * 1024, 200 * 1024} {
+		c.Logf("size %d", size)
+		s.checkRoundTrip(c, size, enc, dec)
+	}
+}
+
+func (s *encryptionSuite) TestPublicKeyRoundTrip(c *gc.C) {
+	enc := backups.EncryptionKey{PublicKey: s.publicKey}
+	dec := backups.DecryptionKey{PrivateKey: s.privateKey}
+	s.checkRoundTrip(c, 100*1024, enc, dec)
+}
+
+func (s *encryptionSuite) TestArchiveEncryption(c *gc.C) {
+	plain := archiveData(c, 100)
+	scheme, err := backups.ArchiveEncryption(bytes.NewReader(plain))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scheme, gc.Equals, "")
+
+	encrypted := s.encrypt(c, plain, backups.EncryptionKey{Passphrase: "sekrit"})
+	r := bytes.NewReader(encrypted)
+	scheme, err = backups.ArchiveEncryption(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scheme, gc.Equals, backups.EncryptionPassphrase)
+	c.Check(r.Len(), gc.Equals, len(encrypted))
+
+	encrypted = s.encrypt(c, plain, backups.EncryptionKey{PublicKey: s.publicKey})
+	scheme, err = backups.ArchiveEncryption(bytes.NewReader(encrypted))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scheme, gc.Equals, backups.EncryptionPublicKey)
+}
+
+func (s *encryptionSuite) TestDecryptWrongPassphrase(c *gc.C) {
+	encrypted := s.encrypt(c, archiveData(c, 100), backups.EncryptionKey{Passphrase: "sekrit"})
+	r, err := backups.Decrypt(bytes.NewReader(encrypted), backups.DecryptionKey{Passphrase: "guess"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, "cannot decrypt archive: wrong key or corrupt data")
+}
+
+func (s *encryptionSuite) TestDecryptWrongPrivateKey(c *gc.C) {
+	_, otherPrivateKey := generateKeyPair(c)
+	encrypted := s.encrypt(c, archiveData(c, 100), backups.EncryptionKey{PublicKey: s.publicKey})
+	_, err := backups.Decrypt(bytes.NewReader(encrypted), backups.DecryptionKey{PrivateKey: otherPrivateKey})
+	c.Check(err, gc.ErrorMatches, "cannot decrypt archive key: wrong private key")
+}
+
+func (s *encryptionSuite) TestDecryptMissingKey(c *gc.C) {
+	encrypted := s.encrypt(c, archiveData(c, 100), backups.EncryptionKey{PublicKey: s.publicKey})
+	_, err := backups.Decrypt(bytes.NewReader(encrypted), backups.DecryptionKey{Passphrase: "sekrit"})
+	c.Check(err, gc.ErrorMatches, "archive is encrypted to a public key; a private key is needed")
+}
+
+func (s *encryptionSuite) TestDecryptTruncated(c *gc.C) {
+	data := archiveData(c, 200*1024)
+	encrypted := s.encrypt(c, data, backups.EncryptionKey{Passphrase: "sekrit"})
+	// Drop the final chunk entirely, so that every remaining chunk
+	// is intact.
+	truncated := encrypted[:len(encrypted)-(200*1024-3*64*1024)-16-4]
+	r, err := backups.Decrypt(bytes.NewReader(truncated), backups.DecryptionKey{Passphrase: "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, "encrypted archive is truncated")
+}
+
+func (s *encryptionSuite) TestDecryptTampered(c *gc.C) {
+	encrypted := s.encrypt(c, archiveData(c, 100), backups.EncryptionKey{Passphrase: "sekrit"})
+	encrypted[len(encrypted)-1] ^= 1
+	r, err := backups.Decrypt(bytes.NewReader(encrypted), backups.DecryptionKey{Passphrase: "sekrit"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, "cannot decrypt archive: wrong key or corrupt data")
+}
+
+func (s *encryptionSuite) TestDecryptNotEncrypted(c *gc.C) {
+	_, err := backups.Decrypt(bytes.NewReader(archiveData(c, 100)), backups.DecryptionKey{Passphrase: "sekrit"})
+	c.Check(err, gc.ErrorMatches, "archive is not encrypted")
+}
+
+func (s *encryptionSuite) TestEncryptionKeyValidate(c *gc.C) {
+	for i, test := range []struct {
+		key    backups.EncryptionKey
+		scheme string
+		err    string
+	}{{
+		key:    backups.EncryptionKey{Passphrase: "sekrit"},
+		scheme: backups.EncryptionPassphrase,
+	}, {
+		key:    backups.EncryptionKey{PublicKey: s.publicKey},
+		scheme: backups.EncryptionPublicKey,
+	}, {
+		err: "empty encryption key not valid",
+	}, {
+		key: backups.EncryptionKey{Passphrase: "sekrit", PublicKey: s.publicKey},
+		err: "both passphrase and public key not valid",
+	}, {
+		key: backups.EncryptionKey{PublicKey: "not a key"},
+		err: `public key \(no PEM data\) not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.key.Validate()
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(test.key.Scheme(), gc.Equals, test.scheme)
+	}
+}
+
+func (s *encryptionSuite) TestParsePublicKeyPKCS1(c *gc.C) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	der, err := asn1.Marshal(key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	data := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}))
+
+	parsed, err := backups.ParsePublicKey(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(parsed.N.Cmp(key.N), gc.Equals, 0)
+	c.Check(parsed.E, gc.Equals, key.E)
+}
+
+func (s *encryptionSuite) TestParsePublicKeyPKCS1Invalid(c *gc.C) {
+	der, err := asn1.Marshal(rsa.PublicKey{N: big.NewInt(0), E: 65537})
+	c.Assert(err, jc.ErrorIsNil)
+	data := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}))
+	_, err = backups.ParsePublicKey(data)
+	c.Check(err, gc.ErrorMatches, `public key \(non-positive modulus or exponent\) not valid`)
+
+	data = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: append(der, 0)}))
+	_, err = backups.ParsePublicKey(data)
+	c.Check(err, gc.ErrorMatches, `public key \(trailing data\) not valid`)
+}
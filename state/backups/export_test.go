@@ -13,6 +13,7 @@ import (
 	"github.com/juju/testing"
 	"github.com/juju/utils/filestorage"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -168,3 +169,8 @@ var PlaceNewMongo = placeNewMongo
 var MongoRestoreArgsForVersion = mongoRestoreArgsForVersion
 var RestorePath = &getMongorestorePath
 var RestoreArgsForVersion = &restoreArgsForVersion
+
+// NewS3Destination exposes newS3Destination for testing.
+func NewS3Destination(cfg config.BackupS3Config) (Destination, error) {
+	return newS3Destination(cfg)
+}
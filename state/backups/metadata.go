@@ -66,6 +66,19 @@ type Metadata struct {
 	// Notes is an optional user-supplied annotation.
 	Notes string
 
+	// Scheduled records whether the backup was taken automatically
+	// by the controller rather than requested by a user.
+	Scheduled bool
+
+	// Failure holds the reason a scheduled backup could not be
+	// created; no archive is stored for a failed backup.
+	Failure string
+
+	// Encryption names the scheme with which the stored archive is
+	// encrypted, or is empty if the archive is not encrypted. The
+	// size and checksum refer to the encrypted archive.
+	Encryption string
+
 	// TODO(wallyworld) - remove these ASAP
 	// These are only used by the restore CLI when re-bootstrapping.
 	// We will use a better solution but the way restore currently
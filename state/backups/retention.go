@@ -0,0 +1,86 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// RetentionPolicy determines which scheduled backups are kept.
+type RetentionPolicy struct {
+	// MaxCount is the number of scheduled backups to keep; the
+	// newest are kept. Failed backups are counted separately, so
+	// that a run of failures cannot displace good backups. Zero
+	// means there is no limit.
+	MaxCount int
+
+	// MaxAge is the age beyond which scheduled backups are removed.
+	// Zero means there is no limit.
+	MaxAge time.Duration
+}
+
+// PruneScheduled removes the scheduled backups, and records of failed
+// scheduled backups, that fall outside the retention policy, and
+// returns the IDs of those it removed. Backups created on request are
+// never removed.
+func PruneScheduled(b Backups, policy RetentionPolicy, now time.Time) ([]string, error) {
+	metaList, err := b.List()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var succeeded, failed []*Metadata
+	for _, meta := range metaList {
+		if !meta.Scheduled {
+			continue
+		}
+		if meta.Failure != "" {
+			failed = append(failed, meta)
+		} else {
+			succeeded = append(succeeded, meta)
+		}
+	}
+
+	var removed []string
+	for _, group := range [][]*Metadata{succeeded, failed} {
+		sort.Sort(byStartedNewestFirst(group))
+		for i, meta := range group {
+			tooMany := policy.MaxCount > 0 && i >= policy.MaxCount
+			tooOld := policy.MaxAge > 0 && meta.Started.Before(now.Add(-policy.MaxAge))
+			if !tooMany && !tooOld {
+				continue
+			}
+			if err := b.Remove(meta.ID()); err != nil {
+				return removed, errors.Annotatef(err, "while removing backup %q", meta.ID())
+			}
+			removed = append(removed, meta.ID())
+		}
+	}
+	return removed, nil
+}
+
+// LastScheduled returns the start time of the most recent scheduled
+// backup, whether or not it succeeded, or the zero time if there has
+// been none.
+func LastScheduled(b Backups) (time.Time, error) {
+	metaList, err := b.List()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	var last time.Time
+	for _, meta := range metaList {
+		if meta.Scheduled && meta.Started.After(last) {
+			last = meta.Started
+		}
+	}
+	return last, nil
+}
+
+type byStartedNewestFirst []*Metadata
+
+func (s byStartedNewestFirst) Len() int           { return len(s) }
+func (s byStartedNewestFirst) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byStartedNewestFirst) Less(i, j int) bool { return s[i].Started.After(s[j].Started) }
@@ -0,0 +1,112 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type retentionSuite struct {
+	testing.BaseSuite
+	now     time.Time
+	backups *removeRecorder
+}
+
+var _ = gc.Suite(&retentionSuite{})
+
+func (s *retentionSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.now = time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.backups = &removeRecorder{}
+}
+
+// add records a backup started the given number of hours ago.
+func (s *retentionSuite) add(id string, hoursAgo int, scheduled bool, failure string) {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = s.now.Add(-time.Duration(hoursAgo) * time.Hour)
+	meta.Scheduled = scheduled
+	meta.Failure = failure
+	s.backups.MetaList = append(s.backups.MetaList, meta)
+}
+
+func (s *retentionSuite) TestPruneScheduledByCount(c *gc.C) {
+	s.add("manual", 100, false, "")
+	s.add("old", 72, true, "")
+	s.add("middle", 48, true, "")
+	s.add("new", 24, true, "")
+	s.add("failed-old", 60, true, "boom")
+	s.add("failed-new", 36, true, "boom")
+
+	removed, err := backups.PruneScheduled(s.backups, backups.RetentionPolicy{MaxCount: 1}, s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(removed, jc.DeepEquals, []string{"middle", "old", "failed-old"})
+	c.Check(s.backups.removed, jc.DeepEquals, removed)
+}
+
+func (s *retentionSuite) TestPruneScheduledByAge(c *gc.C) {
+	s.add("manual", 100, false, "")
+	s.add("old", 72, true, "")
+	s.add("new", 24, true, "")
+	s.add("failed-old", 60, true, "boom")
+
+	removed, err := backups.PruneScheduled(s.backups, backups.RetentionPolicy{MaxAge: 48 * time.Hour}, s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(removed, jc.DeepEquals, []string{"old", "failed-old"})
+}
+
+func (s *retentionSuite) TestPruneScheduledNoLimits(c *gc.C) {
+	s.add("old", 1000, true, "")
+
+	removed, err := backups.PruneScheduled(s.backups, backups.RetentionPolicy{}, s.now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(removed, gc.HasLen, 0)
+}
+
+func (s *retentionSuite) TestPruneScheduledRemoveError(c *gc.C) {
+	s.add("old", 72, true, "")
+	s.backups.Error = errors.New("boom")
+
+	_, err := backups.PruneScheduled(s.backups, backups.RetentionPolicy{MaxAge: time.Hour}, s.now)
+	c.Assert(err, gc.ErrorMatches, `while removing backup "old": boom`)
+}
+
+func (s *retentionSuite) TestLastScheduled(c *gc.C) {
+	last, err := backups.LastScheduled(s.backups)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(last.IsZero(), jc.IsTrue)
+
+	s.add("manual", 1, false, "")
+	s.add("old", 72, true, "")
+	s.add("failed", 24, true, "boom")
+	last, err = backups.LastScheduled(s.backups)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(last, gc.Equals, s.now.Add(-24*time.Hour))
+}
+
+// removeRecorder is a FakeBackups that records every removed ID.
+type removeRecorder struct {
+	backupstesting.FakeBackups
+	removed []string
+}
+
+func (b *removeRecorder) List() ([]*backups.Metadata, error) {
+	return b.MetaList, nil
+}
+
+func (b *removeRecorder) Remove(id string) error {
+	if b.Error != nil {
+		return b.Error
+	}
+	b.removed = append(b.removed, id)
+	return nil
+}
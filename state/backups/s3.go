@@ -0,0 +1,206 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/hash"
+	"github.com/juju/version"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+
+	"github.com/juju/juju/environs/config"
+)
+
+const (
+	s3ArchiveSuffix  = ".tar.gz"
+	s3MetadataSuffix = ".json"
+)
+
+// s3Destination copies backups to a bucket in S3-compatible object
+// storage. Each backup is held as two objects: the archive, and a
+// JSON document with its metadata.
+type s3Destination struct {
+	bucket *s3.Bucket
+	prefix string
+}
+
+// newS3Destination returns a destination that copies backups to the
+// configured bucket.
+func newS3Destination(cfg config.BackupS3Config) (*s3Destination, error) {
+	region, ok := aws.Regions[cfg.Region]
+	if cfg.Endpoint != "" {
+		region = aws.Region{
+			Name:       cfg.Region,
+			S3Endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		}
+	} else if !ok {
+		return nil, errors.NotValidf("backup storage region %q", cfg.Region)
+	}
+	auth := aws.Auth{
+		AccessKey: cfg.AccessKey,
+		SecretKey: cfg.SecretKey,
+	}
+	bucket, err := s3.New(auth, region).Bucket(cfg.Bucket)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &s3Destination{
+		bucket: bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+// remoteMetadata is the metadata document stored alongside each
+// archive. The CA keys held in Metadata are deliberately left out.
+type remoteMetadata struct {
+	ID             string         `json:"id"`
+	Checksum       string         `json:"checksum"`
+	ChecksumFormat string         `json:"checksum-format"`
+	Size           int64          `json:"size"`
+	Stored         time.Time      `json:"stored"`
+	Started        time.Time      `json:"started"`
+	Finished       time.Time      `json:"finished"`
+	Notes          string         `json:"notes,omitempty"`
+	Scheduled      bool           `json:"scheduled,omitempty"`
+	Encryption     string         `json:"encryption,omitempty"`
+	Model          string         `json:"model"`
+	Machine        string         `json:"machine"`
+	Hostname       string         `json:"hostname"`
+	Version        version.Number `json:"version"`
+}
+
+// Put is part of the Destination interface.
+func (d *s3Destination) Put(meta *Metadata, archive io.Reader) error {
+	archiveKey := d.prefix + meta.ID() + s3ArchiveSuffix
+	err := d.bucket.PutReader(archiveKey, archive, meta.Size(), "application/octet-stream", s3.Private)
+	if err != nil {
+		return errors.Annotate(err, "cannot upload backup archive")
+	}
+	if err := d.verify(archiveKey, meta.Checksum()); err != nil {
+		if err := d.bucket.Del(archiveKey); err != nil {
+			logger.Errorf("cannot remove bad copy of backup archive %q: %v", archiveKey, err)
+		}
+		return errors.Trace(err)
+	}
+
+	doc := remoteMetadata{
+		ID:             meta.ID(),
+		Checksum:       meta.Checksum(),
+		ChecksumFormat: meta.ChecksumFormat(),
+		Size:           meta.Size(),
+		Stored:         time.Now().UTC(),
+		Started:        meta.Started,
+		Notes:          meta.Notes,
+		Scheduled:      meta.Scheduled,
+		Encryption:     meta.Encryption,
+		Model:          meta.Origin.Model,
+		Machine:        meta.Origin.Machine,
+		Hostname:       meta.Origin.Hostname,
+		Version:        meta.Origin.Version,
+	}
+	if meta.Finished != nil {
+		doc.Finished = *meta.Finished
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	metadataKey := d.prefix + meta.ID() + s3MetadataSuffix
+	if err := d.bucket.Put(metadataKey, data, "application/json", s3.Private); err != nil {
+		return errors.Annotate(err, "cannot upload backup metadata")
+	}
+	return nil
+}
+
+// verify reads back the stored archive and checks that it has the
+// expected checksum.
+func (d *s3Destination) verify(key, checksum string) error {
+	stored, err := d.bucket.GetReader(key)
+	if err != nil {
+		return errors.Annotate(err, "cannot read back backup archive")
+	}
+	defer stored.Close()
+	hasher := hash.NewHashingWriter(ioutil.Discard, sha1.New())
+	if _, err := io.Copy(hasher, stored); err != nil {
+		return errors.Annotate(err, "cannot read back backup archive")
+	}
+	if sum := hasher.Base64Sum(); sum != checksum {
+		return errors.Errorf("checksum mismatch for uploaded backup archive: expected %q, got %q", checksum, sum)
+	}
+	return nil
+}
+
+// List is part of the Destination interface.
+func (d *s3Destination) List() ([]*Metadata, error) {
+	var metaList []*Metadata
+	marker := ""
+	for {
+		resp, err := d.bucket.List(d.prefix, "", marker, 0)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot list remote backups")
+		}
+		for _, key := range resp.Contents {
+			marker = key.Key
+			if !strings.HasSuffix(key.Key, s3MetadataSuffix) {
+				continue
+			}
+			meta, err := d.readMetadata(key.Key)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			metaList = append(metaList, meta)
+		}
+		if !resp.IsTruncated {
+			break
+		}
+	}
+	sort.Sort(byStarted(metaList))
+	return metaList, nil
+}
+
+func (d *s3Destination) readMetadata(key string) (*Metadata, error) {
+	data, err := d.bucket.Get(key)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read remote backup metadata %q", key)
+	}
+	var doc remoteMetadata
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Annotatef(err, "invalid remote backup metadata %q", key)
+	}
+	meta := NewMetadata()
+	meta.SetID(doc.ID)
+	if err := meta.SetFileInfo(doc.Size, doc.Checksum, doc.ChecksumFormat); err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.SetStored(&doc.Stored)
+	meta.Started = doc.Started
+	if !doc.Finished.IsZero() {
+		meta.Finished = &doc.Finished
+	}
+	meta.Notes = doc.Notes
+	meta.Scheduled = doc.Scheduled
+	meta.Encryption = doc.Encryption
+	meta.Origin = Origin{
+		Model:    doc.Model,
+		Machine:  doc.Machine,
+		Hostname: doc.Hostname,
+		Version:  doc.Version,
+	}
+	return meta, nil
+}
+
+type byStarted []*Metadata
+
+func (s byStarted) Len() int           { return len(s) }
+func (s byStarted) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byStarted) Less(i, j int) bool { return s[i].Started.Before(s[j].Started) }
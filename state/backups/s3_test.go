@@ -0,0 +1,171 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+)
+
+type s3Suite struct {
+	testing.IsolationSuite
+
+	srv    *s3test.Server
+	bucket *s3.Bucket
+	config config.BackupS3Config
+}
+
+var _ = gc.Suite(&s3Suite{})
+
+func (s *s3Suite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	var err error
+	s.srv, err = s3test.NewServer(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { s.srv.Quit() })
+
+	s.config = config.BackupS3Config{
+		Endpoint:  s.srv.URL(),
+		Region:    "test",
+		Bucket:    "juju-backups",
+		Prefix:    "ctrl/",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+	region := aws.Region{
+		Name:                 "test",
+		S3Endpoint:           s.srv.URL(),
+		S3LocationConstraint: true,
+	}
+	s.bucket, err = s3.New(aws.Auth{}, region).Bucket("juju-backups")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.bucket.PutBucket(s3.Private)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *s3Suite) newMetadata(c *gc.C, id string, data []byte) *backups.Metadata {
+	meta := backupstesting.NewMetadataStarted()
+	meta.SetID(id)
+	sum := sha1.Sum(data)
+	err := meta.MarkComplete(int64(len(data)), base64.StdEncoding.EncodeToString(sum[:]))
+	c.Assert(err, jc.ErrorIsNil)
+	return meta
+}
+
+func (s *s3Suite) TestPutAndList(c *gc.C) {
+	dest, err := backups.NewS3Destination(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := []byte("<compressed tarball>")
+	meta := s.newMetadata(c, "spam", data)
+	meta.Notes = "important"
+	meta.Encryption = backups.EncryptionPassphrase
+	meta.CAPrivateKey = "<private key>"
+	err = dest.Put(meta, bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+
+	stored, err := s.bucket.Get("ctrl/spam.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stored, jc.DeepEquals, data)
+	storedMeta, err := s.bucket.Get("ctrl/spam.json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(storedMeta), gc.Not(jc.Contains), "<private key>")
+
+	older := s.newMetadata(c, "eggs", data)
+	older.Started = meta.Started.Add(-time.Hour)
+	err = dest.Put(older, bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+
+	metaList, err := dest.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metaList, gc.HasLen, 2)
+	c.Check(metaList[0].ID(), gc.Equals, "eggs")
+	remote := metaList[1]
+	c.Check(remote.ID(), gc.Equals, "spam")
+	c.Check(remote.Checksum(), gc.Equals, meta.Checksum())
+	c.Check(remote.Size(), gc.Equals, meta.Size())
+	c.Check(remote.Stored(), gc.NotNil)
+	c.Check(remote.Started.Equal(meta.Started), jc.IsTrue)
+	c.Check(remote.Notes, gc.Equals, "important")
+	c.Check(remote.Encryption, gc.Equals, backups.EncryptionPassphrase)
+	c.Check(remote.Origin, jc.DeepEquals, meta.Origin)
+	c.Check(remote.CAPrivateKey, gc.Equals, "")
+}
+
+func (s *s3Suite) TestPutChecksumMismatch(c *gc.C) {
+	dest, err := backups.NewS3Destination(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := []byte("<compressed tarball>")
+	meta := s.newMetadata(c, "spam", []byte("<compressed TARBALL>"))
+	err = dest.Put(meta, bytes.NewReader(data))
+	c.Assert(err, gc.ErrorMatches, "checksum mismatch for uploaded backup archive: .*")
+
+	resp, err := s.bucket.List("", "", "", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resp.Contents, gc.HasLen, 0)
+}
+
+func (s *s3Suite) TestListEmpty(c *gc.C) {
+	dest, err := backups.NewS3Destination(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	metaList, err := dest.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(metaList, gc.HasLen, 0)
+}
+
+func (s *s3Suite) TestUnknownRegion(c *gc.C) {
+	s.config.Endpoint = ""
+	s.config.Region = "nowhere"
+	_, err := backups.NewS3Destination(s.config)
+	c.Check(err, gc.ErrorMatches, `backup storage region "nowhere" not valid`)
+}
+
+type fakeDestination struct {
+	meta *backups.Metadata
+	data []byte
+}
+
+func (d *fakeDestination) Put(meta *backups.Metadata, archive io.Reader) error {
+	d.meta = meta
+	var err error
+	d.data, err = ioutil.ReadAll(archive)
+	return err
+}
+
+func (d *fakeDestination) List() ([]*backups.Metadata, error) {
+	return nil, nil
+}
+
+func (s *s3Suite) TestPush(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	fake := &backupstesting.FakeBackups{
+		Meta:    meta,
+		Archive: ioutil.NopCloser(bytes.NewBufferString("<archive>")),
+	}
+	dest := &fakeDestination{}
+	err := backups.Push(fake, dest, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"Get"})
+	c.Check(fake.IDArg, gc.Equals, "spam")
+	c.Check(dest.meta, gc.Equals, meta)
+	c.Check(string(dest.data), gc.Equals, "<archive>")
+}
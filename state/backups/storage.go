@@ -46,6 +46,11 @@ type storageMetaDoc struct {
 	Finished int64  `bson:"finished,minsize"`
 	Notes    string `bson:"notes,omitempty"`
 
+	Scheduled bool   `bson:"scheduled,omitempty"`
+	Failure   string `bson:"failure,omitempty"`
+
+	Encryption string `bson:"encryption,omitempty"`
+
 	// origin
 
 	Model    string         `bson:"model"`
@@ -121,6 +126,9 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.Scheduled = doc.Scheduled
+	meta.Failure = doc.Failure
+	meta.Encryption = doc.Encryption
 
 	meta.Origin.Model = doc.Model
 	meta.Origin.Machine = doc.Machine
@@ -171,6 +179,9 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.Scheduled = meta.Scheduled
+	doc.Failure = meta.Failure
+	doc.Encryption = meta.Encryption
 
 	doc.Model = meta.Origin.Model
 	doc.Machine = meta.Origin.Machine
@@ -44,18 +44,23 @@ type FakeBackups struct {
 	InstanceId instance.Id
 	// ArchiveArg holds the backup archive that was passed in.
 	ArchiveArg io.Reader
+	// KeyArg holds the encryption key that was passed in.
+	KeyArg *backups.EncryptionKey
+	// DecryptionKeyArg holds the decryption key passed to Restore.
+	DecryptionKeyArg backups.DecryptionKey
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
 
 // Create creates and stores a new juju backup archive and returns
 // its associated metadata.
-func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo) error {
+func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo, key *backups.EncryptionKey) error {
 	b.Calls = append(b.Calls, "Create")
 
 	b.PathsArg = paths
 	b.DBInfoArg = dbInfo
 	b.MetaArg = meta
+	b.KeyArg = key
 
 	if b.Meta != nil {
 		*meta = *b.Meta
@@ -76,6 +81,19 @@ func (b *FakeBackups) Add(archive io.Reader, meta *backups.Metadata) (string, er
 	return id, b.Error
 }
 
+// RecordFailure stores the failed backup's metadata and returns its
+// new ID.
+func (b *FakeBackups) RecordFailure(meta *backups.Metadata, failure error) (string, error) {
+	b.Calls = append(b.Calls, "RecordFailure")
+	b.MetaArg = meta
+	meta.Failure = failure.Error()
+	id := ""
+	if b.Meta != nil {
+		id = b.Meta.ID()
+	}
+	return id, b.Error
+}
+
 // Get returns the metadata and archive file associated with the ID.
 func (b *FakeBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
 	b.Calls = append(b.Calls, "Get")
@@ -101,6 +119,7 @@ func (b *FakeBackups) Restore(bkpId string, args backups.RestoreArgs) (names.Tag
 	b.Calls = append(b.Calls, "Restore")
 	b.PrivateAddr = args.PrivateAddress
 	b.InstanceId = args.NewInstId
+	b.DecryptionKeyArg = args.DecryptionKey
 	return nil, errors.Trace(b.Error)
 }
 
@@ -30,6 +30,8 @@ const (
 	cleanupAttachmentsForDyingFilesystem cleanupKind = "filesystemAttachments"
 	cleanupModelsForDyingController      cleanupKind = "models"
 	cleanupMachinesForDyingModel         cleanupKind = "modelMachines"
+	cleanupStorageForRemovedService      cleanupKind = "serviceStorage"
+	cleanupSecretsForRemovedService      cleanupKind = "serviceSecrets"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -105,6 +107,10 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupModelsForDyingController()
 		case cleanupMachinesForDyingModel:
 			err = st.cleanupMachinesForDyingModel()
+		case cleanupStorageForRemovedService:
+			err = st.cleanupStorageForRemovedService(doc.Prefix)
+		case cleanupSecretsForRemovedService:
+			err = st.cleanupSecretsForRemovedService(doc.Prefix)
 		default:
 			handler, ok := cleanupHandlers[doc.Kind]
 			if !ok {
@@ -508,6 +514,19 @@ func (st *State) cleanupAttachmentsForDyingStorage(storageId string) (err error)
 	return nil
 }
 
+// cleanupStorageForRemovedService destroys the shared storage instances
+// owned by the specified service, which has been removed from state.
+func (st *State) cleanupStorageForRemovedService(serviceTag string) error {
+	tag, err := names.ParseServiceTag(serviceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Annotate(
+		st.destroyEntityStorageInstances(tag),
+		"destroying shared storage",
+	)
+}
+
 // cleanupAttachmentsForDyingVolume sets all volume attachments related
 // to the specified volume to Dying, if they are not already Dying or
 // Dead. It's expected to be used when a volume is destroyed.
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/testcharms"
 )
 
@@ -39,6 +40,10 @@ const (
 	StorageInstancesC = storageInstancesC
 	StatusesHistoryC  = statusesHistoryC
 	GUISettingsC      = guisettingsC
+	SecretRevisionsC  = secretRevisionsC
+	HookExecutionsC   = hookExecutionsC
+
+	MaxHookExecutions = maxHookExecutions
 )
 
 var (
@@ -46,6 +51,7 @@ var (
 	ImageStorageNewStorage = &imageStorageNewStorage
 	MachineIdLessThan      = machineIdLessThan
 	ControllerAvailable    = &controllerAvailable
+	RolloutAgentAlive      = &rolloutAgentAlive
 	GetOrCreatePorts       = getOrCreatePorts
 	GetPorts               = getPorts
 	PortsGlobalKey         = portsGlobalKey
@@ -499,3 +505,9 @@ func AssertEndpointBindingsNotFoundForService(c *gc.C, service *Service) {
 func LeadershipLeases(st *State) map[string]lease.Info {
 	return st.leadershipClient.Leases()
 }
+
+// FilesystemStatusHistory returns the status history of the specified
+// filesystem, most recent first.
+func FilesystemStatusHistory(st *State, tag names.FilesystemTag, size int) ([]status.StatusInfo, error) {
+	return statusHistory(st, filesystemGlobalKey(tag.Id()), size)
+}
@@ -85,6 +85,10 @@ type FilesystemAttachment interface {
 	// if it has not already been made. Params returns true if the returned
 	// parameters are usable for creating an attachment, otherwise false.
 	Params() (FilesystemAttachmentParams, bool)
+
+	// Usage returns the most recently reported usage of the attached
+	// filesystem. Usage returns false if no usage has been reported.
+	Usage() (FilesystemUsage, bool)
 }
 
 type filesystem struct {
@@ -123,6 +127,7 @@ type filesystemAttachmentDoc struct {
 	Life       Life                        `bson:"life"`
 	Info       *FilesystemAttachmentInfo   `bson:"info,omitempty"`
 	Params     *FilesystemAttachmentParams `bson:"params,omitempty"`
+	Usage      *FilesystemUsage            `bson:"usage,omitempty"`
 }
 
 // FilesystemParams records parameters for provisioning a new filesystem.
@@ -135,6 +140,12 @@ type FilesystemParams struct {
 	// the filesystem's lifecycle will be bound.
 	binding names.Tag
 
+	// shared, if true, indicates that the filesystem is shared
+	// by the units of a service. Shared filesystems are created
+	// without any attachments; attachments are added as units
+	// are assigned to machines.
+	shared bool
+
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
 }
@@ -310,6 +321,14 @@ func (f *filesystemAttachment) Params() (FilesystemAttachmentParams, bool) {
 	return *f.doc.Params, true
 }
 
+// Usage is required to implement FilesystemAttachment.
+func (f *filesystemAttachment) Usage() (FilesystemUsage, bool) {
+	if f.doc.Usage == nil {
+		return FilesystemUsage{}, false
+	}
+	return *f.doc.Usage, true
+}
+
 // Filesystem returns the Filesystem with the specified name.
 func (st *State) Filesystem(tag names.FilesystemTag) (Filesystem, error) {
 	f, err := st.filesystemByTag(tag)
@@ -387,6 +406,10 @@ func (st *State) filesystem(query bson.D, description string) (*filesystem, erro
 // FilesystemAttachment returns the FilesystemAttachment corresponding to
 // the specified filesystem and machine.
 func (st *State) FilesystemAttachment(machine names.MachineTag, filesystem names.FilesystemTag) (FilesystemAttachment, error) {
+	return st.filesystemAttachment(machine, filesystem)
+}
+
+func (st *State) filesystemAttachment(machine names.MachineTag, filesystem names.FilesystemTag) (*filesystemAttachment, error) {
 	coll, cleanup := st.getCollection(filesystemAttachmentsC)
 	defer cleanup()
 
@@ -465,7 +488,18 @@ func (st *State) removeMachineFilesystemsOps(machine names.MachineTag) ([]txn.Op
 			return nil, errors.Trace(err)
 		}
 		if !canRemove {
-			return nil, errors.Errorf("machine has non-machine bound filesystem %v", filesystemTag.Id())
+			if _, ok := names.FilesystemMachine(filesystemTag); ok {
+				return nil, errors.Errorf("machine has non-machine bound filesystem %v", filesystemTag.Id())
+			}
+			// The filesystem is shared with other machines;
+			// just drop this machine's reference to it.
+			ops = append(ops, txn.Op{
+				C:      filesystemsC,
+				Id:     filesystemTag.Id(),
+				Assert: bson.D{{"attachmentcount", bson.D{{"$gt", 0}}}},
+				Update: bson.D{{"$inc", bson.D{{"attachmentcount", -1}}}},
+			})
+			continue
 		}
 		ops = append(ops, txn.Op{
 			C:      filesystemsC,
@@ -481,10 +515,26 @@ func (st *State) removeMachineFilesystemsOps(machine names.MachineTag) ([]txn.Op
 // with the specified tag is inherently bound to the lifetime of the machine,
 // and will be removed along with it, leaving no resources dangling.
 func isFilesystemInherentlyMachineBound(st *State, tag names.FilesystemTag) (bool, error) {
-	// TODO(axw) when we have support for persistent filesystems,
-	// e.g. NFS shares, then we need to check the filesystem info
-	// to decide whether or not to remove.
-	return true, nil
+	if _, ok := names.FilesystemMachine(tag); ok {
+		return true, nil
+	}
+	// Model-scoped filesystems are only inherently machine-bound
+	// if they cannot be attached to other machines.
+	f, err := st.filesystemByTag(tag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	var pool string
+	if f.doc.Info != nil {
+		pool = f.doc.Info.Pool
+	} else if f.doc.Params != nil {
+		pool = f.doc.Params.Pool
+	}
+	_, provider, err := poolStorageProvider(st, pool)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !storage.IsSharedFilesystemProvider(provider), nil
 }
 
 // DetachFilesystem marks the filesystem attachment identified by the specified machine
@@ -753,6 +803,12 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 		ops = append(ops, volumeOps...)
 	}
 
+	// Every filesystem is created with one attachment,
+	// except for shared filesystems.
+	attachmentCount := 1
+	if params.shared {
+		attachmentCount = 0
+	}
 	filesystemOps := []txn.Op{
 		createStatusOp(st, filesystemGlobalKey(filesystemId), statusDoc{
 			Status: status.StatusPending,
@@ -764,13 +820,12 @@ func (st *State) addFilesystemOps(params FilesystemParams, machineId string) ([]
 			Id:     filesystemId,
 			Assert: txn.DocMissing,
 			Insert: &filesystemDoc{
-				FilesystemId: filesystemId,
-				VolumeId:     volumeId,
-				StorageId:    params.storage.Id(),
-				Binding:      params.binding.String(),
-				Params:       &params,
-				// Every filesystem is created with one attachment.
-				AttachmentCount: 1,
+				FilesystemId:    filesystemId,
+				VolumeId:        volumeId,
+				StorageId:       params.storage.Id(),
+				Binding:         params.binding.String(),
+				Params:          &params,
+				AttachmentCount: attachmentCount,
 			},
 		},
 	}
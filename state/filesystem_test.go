@@ -405,7 +405,9 @@ func (s *FilesystemStateSuite) TestWatchMachineFilesystemAttachments(c *gc.C) {
 	w := s.State.WatchMachineFilesystemAttachments(names.NewMachineTag("0"))
 	defer testing.AssertStop(c, w)
 	wc := testing.NewStringsWatcherC(c, s.State, w)
-	wc.AssertChangeInSingleEvent("0:0/1", "0:0/2") // initial
+	// Attachments of model-scoped filesystems are reported too, since
+	// they may be shared filesystems attached by the machine.
+	wc.AssertChangeInSingleEvent("0:0", "0:0/1", "0:0/2") // initial
 	wc.AssertNoChange()
 
 	addUnit(nil)
@@ -414,8 +416,7 @@ func (s *FilesystemStateSuite) TestWatchMachineFilesystemAttachments(c *gc.C) {
 
 	err := s.State.DetachFilesystem(names.NewMachineTag("0"), names.NewFilesystemTag("0"))
 	c.Assert(err, jc.ErrorIsNil)
-	// no change, since we're only interested in attachments of
-	// machine-scoped volumes.
+	wc.AssertChangeInSingleEvent("0:0") // dying
 	wc.AssertNoChange()
 
 	err = s.State.DetachFilesystem(names.NewMachineTag("0"), names.NewFilesystemTag("0/1"))
@@ -0,0 +1,162 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// filesystemUsageThresholds are the percentages of space or inode usage
+// at which a note is recorded in the filesystem's status history, when
+// crossed in either direction.
+var filesystemUsageThresholds = []int{80, 90, 95}
+
+// FilesystemUsage describes the space and inode usage of an attached
+// filesystem, as most recently reported by the machine agent.
+type FilesystemUsage struct {
+	// Size, Used and Available are measured in bytes.
+	Size      uint64 `bson:"size"`
+	Used      uint64 `bson:"used"`
+	Available uint64 `bson:"available"`
+
+	// Inodes, InodesUsed and InodesFree will be zero for
+	// filesystems that allocate inodes dynamically.
+	Inodes     uint64 `bson:"inodes,omitempty"`
+	InodesUsed uint64 `bson:"inodes-used,omitempty"`
+	InodesFree uint64 `bson:"inodes-free,omitempty"`
+
+	// Updated is the time at which the usage was recorded.
+	Updated time.Time `bson:"updated"`
+}
+
+// UsedPercent returns the percentage of the filesystem's space that is
+// used, rounded up. As with df(1), space reserved for privileged users
+// is not taken into account.
+func (u FilesystemUsage) UsedPercent() int {
+	return percentUsed(u.Used, u.Used+u.Available)
+}
+
+// InodesUsedPercent returns the percentage of the filesystem's inodes
+// that are in use, rounded up.
+func (u FilesystemUsage) InodesUsedPercent() int {
+	return percentUsed(u.InodesUsed, u.InodesUsed+u.InodesFree)
+}
+
+func percentUsed(used, total uint64) int {
+	if total == 0 {
+		return 0
+	}
+	return int((used*100 + total - 1) / total)
+}
+
+// usageThresholdLevel returns the number of usage thresholds
+// that the given percentage meets or exceeds.
+func usageThresholdLevel(percent int) int {
+	var level int
+	for _, threshold := range filesystemUsageThresholds {
+		if percent >= threshold {
+			level++
+		}
+	}
+	return level
+}
+
+// SetFilesystemAttachmentUsage records the usage of the filesystem attached
+// to the specified machine. If the usage crosses one of the usage thresholds,
+// a note is recorded in the filesystem's status history.
+func (st *State) SetFilesystemAttachmentUsage(
+	machineTag names.MachineTag,
+	filesystemTag names.FilesystemTag,
+	usage FilesystemUsage,
+) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set usage for filesystem attachment %s:%s", filesystemTag.Id(), machineTag.Id())
+	usage.Updated = time.Now()
+
+	var previous FilesystemUsage
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		fsa, err := st.filesystemAttachment(machineTag, filesystemTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fsa.doc.Life == Dead {
+			return nil, jujutxn.ErrNoOperations
+		}
+		if fsa.doc.Usage != nil {
+			previous = *fsa.doc.Usage
+		}
+		return []txn.Op{{
+			C:      filesystemAttachmentsC,
+			Id:     filesystemAttachmentId(machineTag.Id(), filesystemTag.Id()),
+			Assert: notDeadDoc,
+			Update: bson.D{{"$set", bson.D{{"usage", &usage}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(st.recordFilesystemUsageThresholds(
+		machineTag, filesystemTag, previous, usage,
+	))
+}
+
+// recordFilesystemUsageThresholds records a note in the status history of
+// the specified filesystem for each usage threshold crossed between the
+// previous and current usage.
+func (st *State) recordFilesystemUsageThresholds(
+	machineTag names.MachineTag,
+	filesystemTag names.FilesystemTag,
+	previous, current FilesystemUsage,
+) error {
+	var notes []string
+	for _, u := range []struct {
+		what          string
+		before, after int
+	}{
+		{"space", previous.UsedPercent(), current.UsedPercent()},
+		{"inode", previous.InodesUsedPercent(), current.InodesUsedPercent()},
+	} {
+		before, after := usageThresholdLevel(u.before), usageThresholdLevel(u.after)
+		switch {
+		case after > before:
+			notes = append(notes, fmt.Sprintf(
+				"%s usage on machine %s rose above %d%% (%d%% used)",
+				u.what, machineTag.Id(), filesystemUsageThresholds[after-1], u.after,
+			))
+		case after < before:
+			notes = append(notes, fmt.Sprintf(
+				"%s usage on machine %s fell below %d%% (%d%% used)",
+				u.what, machineTag.Id(), filesystemUsageThresholds[after], u.after,
+			))
+		}
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	// Record the notes alongside the filesystem's current status,
+	// without changing it; usage is not a lifecycle concern.
+	globalKey := filesystemGlobalKey(filesystemTag.Id())
+	statusInfo, err := getStatus(st, globalKey, "filesystem")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, note := range notes {
+		probablyUpdateStatusHistory(st, globalKey, statusDoc{
+			Status:     statusInfo.Status,
+			StatusInfo: note,
+			StatusData: escapeKeys(map[string]interface{}{
+				"machine": machineTag.Id(),
+			}),
+			Updated: time.Now().UnixNano(),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type FilesystemUsageSuite struct {
+	FilesystemStateSuite
+}
+
+var _ = gc.Suite(&FilesystemUsageSuite{})
+
+func (s *FilesystemUsageSuite) TestUsedPercent(c *gc.C) {
+	usage := state.FilesystemUsage{
+		Size:       100,
+		Used:       45,
+		Available:  50, // 5 reserved
+		InodesUsed: 1,
+		InodesFree: 2,
+	}
+	c.Assert(usage.UsedPercent(), gc.Equals, 48)
+	c.Assert(usage.InodesUsedPercent(), gc.Equals, 34)
+	c.Assert(state.FilesystemUsage{}.UsedPercent(), gc.Equals, 0)
+	c.Assert(state.FilesystemUsage{}.InodesUsedPercent(), gc.Equals, 0)
+}
+
+func (s *FilesystemUsageSuite) TestSetFilesystemAttachmentUsage(c *gc.C) {
+	filesystem, machine := s.setupFilesystemAttachment(c, "rootfs")
+	attachment := s.filesystemAttachment(c, machine.MachineTag(), filesystem.FilesystemTag())
+	_, ok := attachment.Usage()
+	c.Assert(ok, jc.IsFalse)
+
+	usage := state.FilesystemUsage{
+		Size:       1024,
+		Used:       256,
+		Available:  768,
+		Inodes:     100,
+		InodesUsed: 10,
+		InodesFree: 90,
+	}
+	err := s.State.SetFilesystemAttachmentUsage(machine.MachineTag(), filesystem.FilesystemTag(), usage)
+	c.Assert(err, jc.ErrorIsNil)
+
+	attachment = s.filesystemAttachment(c, machine.MachineTag(), filesystem.FilesystemTag())
+	stored, ok := attachment.Usage()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(stored.Updated.IsZero(), jc.IsFalse)
+	stored.Updated = usage.Updated
+	c.Assert(stored, jc.DeepEquals, usage)
+}
+
+func (s *FilesystemUsageSuite) TestSetFilesystemAttachmentUsageNotFound(c *gc.C) {
+	err := s.State.SetFilesystemAttachmentUsage(
+		names.NewMachineTag("0"), names.NewFilesystemTag("0"), state.FilesystemUsage{},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot set usage for filesystem attachment 0:0: filesystem "0" on machine "0" not found`)
+}
+
+func (s *FilesystemUsageSuite) TestSetFilesystemAttachmentUsageThresholds(c *gc.C) {
+	filesystem, machine := s.setupFilesystemAttachment(c, "rootfs")
+	history, err := state.FilesystemStatusHistory(s.State, filesystem.FilesystemTag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	initialHistory := len(history)
+
+	setUsage := func(used, inodesUsed uint64) {
+		err := s.State.SetFilesystemAttachmentUsage(
+			machine.MachineTag(), filesystem.FilesystemTag(),
+			state.FilesystemUsage{
+				Size:       100,
+				Used:       used,
+				Available:  100 - used,
+				Inodes:     100,
+				InodesUsed: inodesUsed,
+				InodesFree: 100 - inodesUsed,
+			},
+		)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	setUsage(50, 10)
+	setUsage(79, 10)
+	setUsage(91, 10) // crosses 80 and 90
+	setUsage(92, 85) // inodes cross 80
+	setUsage(60, 85) // falls below 80
+
+	history, err = state.FilesystemStatusHistory(s.State, filesystem.FilesystemTag(), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, initialHistory+3)
+	var messages []string
+	for _, h := range history[:3] {
+		messages = append(messages, h.Message)
+		c.Check(h.Data, jc.DeepEquals, map[string]interface{}{"machine": machine.Id()})
+	}
+	c.Assert(messages, jc.DeepEquals, []string{
+		"space usage on machine 0 fell below 80% (60% used)",
+		"inode usage on machine 0 rose above 80% (85% used)",
+		"space usage on machine 0 rose above 90% (91% used)",
+	})
+
+	// The filesystem's status is unchanged.
+	filesystemStatus, err := filesystem.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystemStatus.Message, gc.Equals, "")
+}
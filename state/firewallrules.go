@@ -0,0 +1,193 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// firewallRuleDoc records a model-level firewall rule.
+type firewallRuleDoc struct {
+	DocID            string              `bson:"_id"`
+	ModelUUID        string              `bson:"model-uuid"`
+	Name             string              `bson:"name"`
+	SourceSpace      string              `bson:"source-space,omitempty"`
+	DestinationSpace string              `bson:"destination-space,omitempty"`
+	DestinationCIDRs []string            `bson:"destination-cidrs,omitempty"`
+	Ports            []network.PortRange `bson:"ports"`
+}
+
+func (doc firewallRuleDoc) rule() network.FirewallRule {
+	return network.FirewallRule{
+		Name:             doc.Name,
+		SourceSpace:      doc.SourceSpace,
+		DestinationSpace: doc.DestinationSpace,
+		DestinationCIDRs: doc.DestinationCIDRs,
+		Ports:            doc.Ports,
+	}
+}
+
+// AddFirewallRule adds a model-level firewall rule. Any spaces
+// referenced by the rule must exist.
+func (st *State) AddFirewallRule(rule network.FirewallRule) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add firewall rule %q", rule.Name)
+	if err := rule.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	doc := firewallRuleDoc{
+		DocID:            st.docID(rule.Name),
+		ModelUUID:        st.ModelUUID(),
+		Name:             rule.Name,
+		SourceSpace:      rule.SourceSpace,
+		DestinationSpace: rule.DestinationSpace,
+		DestinationCIDRs: rule.DestinationCIDRs,
+		Ports:            rule.Ports,
+	}
+	ops := []txn.Op{{
+		C:      firewallRulesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	spaceNames := set.NewStrings()
+	for _, spaceName := range []string{rule.SourceSpace, rule.DestinationSpace} {
+		if spaceName == "" {
+			continue
+		}
+		spaceNames.Add(spaceName)
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(spaceName),
+			Assert: isAliveDoc,
+		})
+	}
+
+	if err := st.runTransaction(ops); err != txn.ErrAborted {
+		return errors.Trace(err)
+	}
+	if _, err := st.FirewallRule(rule.Name); err == nil {
+		return errors.AlreadyExistsf("firewall rule %q", rule.Name)
+	}
+	for _, spaceName := range spaceNames.SortedValues() {
+		space, err := st.Space(spaceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if space.Life() != Alive {
+			return errors.Errorf("space %q is not alive", spaceName)
+		}
+	}
+	return errors.Trace(txn.ErrAborted)
+}
+
+// RemoveFirewallRule removes the model-level firewall rule
+// with the specified name.
+func (st *State) RemoveFirewallRule(name string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove firewall rule %q", name)
+	ops := []txn.Op{{
+		C:      firewallRulesC,
+		Id:     st.docID(name),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err = st.runTransaction(ops)
+	return onAbort(err, errors.NotFoundf("firewall rule %q", name))
+}
+
+// FirewallRule returns the model-level firewall rule
+// with the specified name.
+func (st *State) FirewallRule(name string) (network.FirewallRule, error) {
+	coll, closer := st.getCollection(firewallRulesC)
+	defer closer()
+
+	var doc firewallRuleDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return network.FirewallRule{}, errors.NotFoundf("firewall rule %q", name)
+	}
+	if err != nil {
+		return network.FirewallRule{}, errors.Annotatef(err, "cannot get firewall rule %q", name)
+	}
+	return doc.rule(), nil
+}
+
+// FirewallRules returns all model-level firewall rules,
+// ordered by name.
+func (st *State) FirewallRules() ([]network.FirewallRule, error) {
+	coll, closer := st.getCollection(firewallRulesC)
+	defer closer()
+
+	var docs []firewallRuleDoc
+	if err := coll.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get firewall rules")
+	}
+	rules := make([]network.FirewallRule, len(docs))
+	for i, doc := range docs {
+		rules[i] = doc.rule()
+	}
+	return rules, nil
+}
+
+// MachineEgressRules returns the model-level firewall rules that apply
+// to traffic sent by the specified machine, with destination spaces
+// resolved to the CIDRs of their subnets. A rule applies to a machine
+// if it has no source space, or if the machine has an address in the
+// source space. Rules whose destination space has no subnets are
+// omitted, as there is nothing to restrict.
+func (st *State) MachineEgressRules(machineId string) ([]network.EgressRule, error) {
+	m, err := st.Machine(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules, err := st.FirewallRules()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	subnets, err := st.AllSubnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineSpaces, err := machineSpaceNames(m, subnets)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceCIDRs := make(map[string][]string)
+	for _, subnet := range subnets {
+		if spaceName := subnet.SpaceName(); spaceName != "" {
+			spaceCIDRs[spaceName] = append(spaceCIDRs[spaceName], subnet.CIDR())
+		}
+	}
+
+	var egressRules []network.EgressRule
+	for _, rule := range rules {
+		if rule.SourceSpace != "" && !machineSpaces.Contains(rule.SourceSpace) {
+			continue
+		}
+		cidrs := rule.DestinationCIDRs
+		if rule.DestinationSpace != "" {
+			cidrs = spaceCIDRs[rule.DestinationSpace]
+			sort.Strings(cidrs)
+		}
+		if len(cidrs) == 0 {
+			continue
+		}
+		egressRules = append(egressRules, network.EgressRule{
+			Name:             rule.Name,
+			DestinationCIDRs: cidrs,
+			Ports:            rule.Ports,
+		})
+	}
+	return egressRules, nil
+}
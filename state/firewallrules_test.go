@@ -0,0 +1,218 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type FirewallRulesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&FirewallRulesSuite{})
+
+func (s *FirewallRulesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	for _, info := range []state.SubnetInfo{
+		{CIDR: "10.0.1.0/24"},
+		{CIDR: "10.0.2.0/24"},
+		{CIDR: "10.0.3.0/24"},
+	} {
+		_, err := s.State.AddSubnet(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("dmz", "", []string{"10.0.1.0/24"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("app", "", []string{"10.0.2.0/24", "10.0.3.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+var (
+	dmzToApp = network.FirewallRule{
+		Name:             "dmz-to-app",
+		SourceSpace:      "dmz",
+		DestinationSpace: "app",
+		Ports:            []network.PortRange{network.MustParsePortRange("443/tcp")},
+	}
+	dnsEgress = network.FirewallRule{
+		Name:             "dns",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports: []network.PortRange{
+			network.MustParsePortRange("53/udp"),
+			network.MustParsePortRange("53/tcp"),
+		},
+	}
+)
+
+func (s *FirewallRulesSuite) TestAddFirewallRule(c *gc.C) {
+	err := s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddFirewallRule(dnsEgress)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rule, err := s.State.FirewallRule("dmz-to-app")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule, jc.DeepEquals, dmzToApp)
+
+	rules, err := s.State.FirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.FirewallRule{dmzToApp, dnsEgress})
+}
+
+func (s *FirewallRulesSuite) TestAddFirewallRuleAlreadyExists(c *gc.C) {
+	err := s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, gc.ErrorMatches, `cannot add firewall rule "dmz-to-app": firewall rule "dmz-to-app" already exists`)
+}
+
+func (s *FirewallRulesSuite) TestAddFirewallRuleSpaceNotFound(c *gc.C) {
+	rule := dmzToApp
+	rule.DestinationSpace = "db"
+	err := s.State.AddFirewallRule(rule)
+	c.Assert(err, gc.ErrorMatches, `cannot add firewall rule "dmz-to-app": space "db" not found`)
+}
+
+func (s *FirewallRulesSuite) TestAddFirewallRuleInvalid(c *gc.C) {
+	rule := dmzToApp
+	rule.Ports = nil
+	err := s.State.AddFirewallRule(rule)
+	c.Assert(err, gc.ErrorMatches, `cannot add firewall rule "dmz-to-app": firewall rule "dmz-to-app" without ports not valid`)
+}
+
+func (s *FirewallRulesSuite) TestRemoveFirewallRule(c *gc.C) {
+	err := s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveFirewallRule("dmz-to-app")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.FirewallRule("dmz-to-app")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveFirewallRule("dmz-to-app")
+	c.Assert(err, gc.ErrorMatches, `cannot remove firewall rule "dmz-to-app": firewall rule "dmz-to-app" not found`)
+}
+
+func (s *FirewallRulesSuite) TestMachineEgressRules(c *gc.C) {
+	err := s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddFirewallRule(dnsEgress)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dmzMachine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = dmzMachine.SetProviderAddresses(network.NewAddress("10.0.1.10"))
+	c.Assert(err, jc.ErrorIsNil)
+	appMachine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = appMachine.SetProviderAddresses(network.NewAddress("10.0.2.10"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	rules, err := s.State.MachineEgressRules(dmzMachine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{{
+		Name:             "dmz-to-app",
+		DestinationCIDRs: []string{"10.0.2.0/24", "10.0.3.0/24"},
+		Ports:            dmzToApp.Ports,
+	}, {
+		Name:             "dns",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            dnsEgress.Ports,
+	}})
+
+	rules, err = s.State.MachineEgressRules(appMachine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.EgressRule{{
+		Name:             "dns",
+		DestinationCIDRs: []string{"0.0.0.0/0"},
+		Ports:            dnsEgress.Ports,
+	}})
+}
+
+func (s *FirewallRulesSuite) TestMachineEgressRulesNone(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err := s.State.MachineEgressRules(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (s *FirewallRulesSuite) TestWatchFirewallRules(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	w := m.WatchFirewallRules()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.State.AddFirewallRule(dmzToApp)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.4.0/24", SpaceName: "app"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.RemoveFirewallRule("dmz-to-app")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *FirewallRulesSuite) TestWatchFirewallRulesMachineAddresses(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	w := m.WatchFirewallRules()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Changing the machine's addresses moves it between spaces.
+	err = m.SetProviderAddresses(network.NewAddress("10.0.1.10"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other changes to the machine are ignored.
+	err = m.SetProvisioned("i-am", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = m.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: "eth0",
+		Type: state.EthernetDevice,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.2.10/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Other machines' addresses are ignored.
+	err = other.SetProviderAddresses(network.NewAddress("10.0.1.11"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: "eth0",
+		Type: state.EthernetDevice,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		ConfigMethod: state.StaticAddress,
+		CIDRAddress:  "10.0.2.11/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
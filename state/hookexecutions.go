@@ -0,0 +1,175 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// maxHookExecutions is the number of executions kept for each unit;
+// older executions are discarded as new ones are recorded.
+const maxHookExecutions = 200
+
+// HookExecution describes a single execution of a hook, action or
+// juju-run commands by a unit agent.
+type HookExecution struct {
+	// Kind identifies what was executed: "hook", "action" or "juju-run".
+	Kind string
+
+	// Name holds the name of the hook or action executed.
+	Name string
+
+	// Started holds the time at which execution started.
+	Started time.Time
+
+	// Duration holds the time taken to execute.
+	Duration time.Duration
+
+	// ExitCode holds the exit code of the executed process, or -1
+	// if it could not be started or was killed.
+	ExitCode int
+
+	// ToolCalls holds the number of hook tool invocations made while
+	// executing, keyed on tool name.
+	ToolCalls map[string]int
+}
+
+// hookExecutionsDoc holds the most recent executions by a unit's
+// agent, oldest first.
+type hookExecutionsDoc struct {
+	DocID      string             `bson:"_id"`
+	ModelUUID  string             `bson:"model-uuid"`
+	Executions []hookExecutionDoc `bson:"executions"`
+}
+
+// hookExecutionDoc represents a HookExecution in mongo.
+type hookExecutionDoc struct {
+	Kind      string         `bson:"kind"`
+	Name      string         `bson:"name"`
+	Started   int64          `bson:"started"`
+	Duration  int64          `bson:"duration"`
+	ExitCode  int            `bson:"exitcode"`
+	ToolCalls map[string]int `bson:"toolcalls,omitempty"`
+}
+
+// hookExecutionDocsByStarted sorts executions by start time, oldest
+// first.
+type hookExecutionDocsByStarted []hookExecutionDoc
+
+func (d hookExecutionDocsByStarted) Len() int           { return len(d) }
+func (d hookExecutionDocsByStarted) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d hookExecutionDocsByStarted) Less(i, j int) bool { return d[i].Started < d[j].Started }
+
+// RecordHookExecutions records a batch of executions by the unit's
+// agent in a single transaction. Only the most recent executions are
+// kept for each unit.
+func (u *Unit) RecordHookExecutions(executions []HookExecution) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record hook executions for unit %q", u)
+	if len(executions) == 0 {
+		return nil
+	}
+	docs := make([]hookExecutionDoc, len(executions))
+	for i, execution := range executions {
+		docs[i] = hookExecutionDoc{
+			Kind:      execution.Kind,
+			Name:      execution.Name,
+			Started:   execution.Started.UTC().UnixNano(),
+			Duration:  int64(execution.Duration),
+			ExitCode:  execution.ExitCode,
+			ToolCalls: execution.ToolCalls,
+		}
+	}
+	sort.Sort(hookExecutionDocsByStarted(docs))
+	if excess := len(docs) - maxHookExecutions; excess > 0 {
+		docs = docs[excess:]
+	}
+	collection, closer := u.st.getCollection(hookExecutionsC)
+	defer closer()
+
+	docID := u.st.docID(u.globalKey())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: txn.DocExists,
+		}}
+		count, err := collection.FindId(docID).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return append(ops, txn.Op{
+				C:      hookExecutionsC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &hookExecutionsDoc{
+					DocID:      docID,
+					Executions: docs,
+				},
+			}), nil
+		}
+		// Keep the executions sorted by start time, and only
+		// the most recent of them.
+		return append(ops, txn.Op{
+			C:      hookExecutionsC,
+			Id:     docID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$push", bson.D{{"executions", bson.D{
+				{"$each", docs},
+				{"$sort", bson.D{{"started", 1}}},
+				{"$slice", -maxHookExecutions},
+			}}}}},
+		}), nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// HookExecutions returns the executions recorded for the unit, most
+// recent first.
+func (u *Unit) HookExecutions() ([]HookExecution, error) {
+	executions, closer := u.st.getCollection(hookExecutionsC)
+	defer closer()
+
+	var doc hookExecutionsDoc
+	err := executions.FindId(u.st.docID(u.globalKey())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get hook executions for unit %q", u)
+	}
+	count := len(doc.Executions)
+	results := make([]HookExecution, count)
+	for i, execution := range doc.Executions {
+		results[count-1-i] = HookExecution{
+			Kind:      execution.Kind,
+			Name:      execution.Name,
+			Started:   time.Unix(0, execution.Started).UTC(),
+			Duration:  time.Duration(execution.Duration),
+			ExitCode:  execution.ExitCode,
+			ToolCalls: execution.ToolCalls,
+		}
+	}
+	return results, nil
+}
+
+// removeHookExecutionsOp returns the operation needed to remove the
+// executions recorded for the unit with the given global key, if any.
+func removeHookExecutionsOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      hookExecutionsC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
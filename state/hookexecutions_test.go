@@ -0,0 +1,170 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type HookExecutionsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&HookExecutionsSuite{})
+
+func (s *HookExecutionsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *HookExecutionsSuite) TestNoExecutions(c *gc.C) {
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecution(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := state.HookExecution{
+		Kind:      "hook",
+		Name:      "install",
+		Started:   started,
+		Duration:  90 * time.Second,
+		ExitCode:  0,
+		ToolCalls: map[string]int{"status-set": 2},
+	}
+	second := state.HookExecution{
+		Kind:     "action",
+		Name:     "backup",
+		Started:  started.Add(time.Hour),
+		Duration: time.Second,
+		ExitCode: 1,
+	}
+	err := s.unit.RecordHookExecutions([]state.HookExecution{first})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.RecordHookExecutions([]state.HookExecution{second})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{second, first})
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionsBatch(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := state.HookExecution{
+		Kind:    "hook",
+		Name:    "install",
+		Started: started,
+	}
+	second := state.HookExecution{
+		Kind:    "hook",
+		Name:    "start",
+		Started: started.Add(time.Minute),
+	}
+	third := state.HookExecution{
+		Kind:    "hook",
+		Name:    "config-changed",
+		Started: started.Add(2 * time.Minute),
+	}
+	err := s.unit.RecordHookExecutions([]state.HookExecution{second, first})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.RecordHookExecutions([]state.HookExecution{third})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, jc.DeepEquals, []state.HookExecution{third, second, first})
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionsEmpty(c *gc.C) {
+	err := s.unit.RecordHookExecutions(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionPrunes(c *gc.C) {
+	started := time.Date(2016, 6, 1, 12, 0, 0, 0, time.UTC)
+	var batch []state.HookExecution
+	for i := 0; i < state.MaxHookExecutions+5; i++ {
+		batch = append(batch, state.HookExecution{
+			Kind:    "hook",
+			Name:    "update-status",
+			Started: started.Add(time.Duration(i) * time.Minute),
+		})
+		if len(batch) == 10 {
+			err := s.unit.RecordHookExecutions(batch)
+			c.Assert(err, jc.ErrorIsNil)
+			batch = nil
+		}
+	}
+	err := s.unit.RecordHookExecutions(batch)
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, state.MaxHookExecutions)
+	newest := started.Add(time.Duration(state.MaxHookExecutions+4) * time.Minute)
+	oldest := started.Add(5 * time.Minute)
+	c.Assert(executions[0].Started, gc.Equals, newest)
+	c.Assert(executions[len(executions)-1].Started, gc.Equals, oldest)
+}
+
+func (s *HookExecutionsSuite) TestExecutionsAreKeptPerUnit(c *gc.C) {
+	other := s.Factory.MakeUnit(c, nil)
+	err := other.RecordHookExecutions([]state.HookExecution{{
+		Kind:    "hook",
+		Name:    "start",
+		Started: time.Now(),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions, err := s.unit.HookExecutions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(executions, gc.HasLen, 0)
+}
+
+func (s *HookExecutionsSuite) TestRemoveUnitRemovesExecutions(c *gc.C) {
+	err := s.unit.RecordHookExecutions([]state.HookExecution{{
+		Kind:    "hook",
+		Name:    "stop",
+		Started: time.Now(),
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	executions := s.State.MongoSession().DB("juju").C(state.HookExecutionsC)
+	count, err := executions.Find(bson.D{{"model-uuid", s.State.ModelUUID()}}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *HookExecutionsSuite) TestRecordHookExecutionUnitRemoved(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.RecordHookExecutions([]state.HookExecution{{
+		Kind:    "hook",
+		Name:    "stop",
+		Started: time.Now(),
+	}})
+	c.Assert(err, gc.ErrorMatches, `cannot record hook executions for unit ".*": unit ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
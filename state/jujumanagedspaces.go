@@ -0,0 +1,149 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+// DiscoverSubnets adds to the model any subnets of the machine's
+// link-layer device addresses that are not yet known. Loopback and
+// link-local addresses are ignored. Discovered subnets are not part
+// of any space until one is assigned to them.
+func (m *Machine) DiscoverSubnets() error {
+	addresses, err := m.AllAddresses()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, addr := range addresses {
+		cidr := addr.SubnetCIDR()
+		if cidr == "" || addr.ConfigMethod() == LoopbackAddress {
+			continue
+		}
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Debugf("not discovering subnet of %s: %v", addr, err)
+			continue
+		}
+		if ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		_, err = m.st.AddSubnet(SubnetInfo{CIDR: cidr})
+		if errors.IsAlreadyExists(err) {
+			continue
+		} else if err != nil {
+			return errors.Annotatef(err, "cannot discover subnets of machine %q", m.Id())
+		}
+		logger.Infof("discovered subnet %q on machine %q", cidr, m.Id())
+	}
+	return nil
+}
+
+// machineSpaceNames returns the names of the spaces in which the
+// machine has addresses, using the given subnets to map the subnets
+// of its link-layer device, machine and provider addresses to spaces.
+func machineSpaceNames(m *Machine, subnets []*Subnet) (set.Strings, error) {
+	addresses, err := m.AllAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnetCIDRs := set.NewStrings()
+	for _, addr := range addresses {
+		subnetCIDRs.Add(addr.SubnetCIDR())
+	}
+
+	spaceNames := set.NewStrings()
+	for _, address := range m.Addresses() {
+		if address.SpaceName != "" {
+			spaceNames.Add(string(address.SpaceName))
+		}
+	}
+	for _, subnet := range subnets {
+		spaceName := subnet.SpaceName()
+		if spaceName == "" {
+			continue
+		}
+		if subnetCIDRs.Contains(subnet.CIDR()) {
+			spaceNames.Add(spaceName)
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			continue
+		}
+		for _, address := range m.Addresses() {
+			if ip := net.ParseIP(address.Value); ip != nil && ipNet.Contains(ip) {
+				spaceNames.Add(spaceName)
+				break
+			}
+		}
+	}
+	return spaceNames, nil
+}
+
+// validateMachineSpaces checks that the machine has addresses in all
+// the spaces required by the unit's constraints and endpoint bindings,
+// and none in the spaces excluded by its constraints. The check is only
+// made when the model uses Juju-managed spaces; otherwise spaces are
+// honoured by the provider when the machine is provisioned.
+func (u *Unit) validateMachineSpaces(m *Machine) error {
+	cfg, err := u.st.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !cfg.JujuManagedSpaces() {
+		return nil
+	}
+
+	cons, err := u.Constraints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	required := set.NewStrings(cons.IncludeSpaces()...)
+	excluded := set.NewStrings(cons.ExcludeSpaces()...)
+	service, err := u.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bindings, err := service.EndpointBindings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, spaceName := range bindings {
+		if spaceName != "" {
+			required.Add(spaceName)
+		}
+	}
+	if required.IsEmpty() && excluded.IsEmpty() {
+		return nil
+	}
+
+	subnets, err := u.st.AllSubnets()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spaceNames, err := machineSpaceNames(m, subnets)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, spaceName := range required.SortedValues() {
+		if !spaceNames.Contains(spaceName) {
+			return errors.NewNotValid(nil, fmt.Sprintf(
+				"machine %q has no address in space %q", m.Id(), spaceName,
+			))
+		}
+	}
+	for _, spaceName := range excluded.SortedValues() {
+		if spaceNames.Contains(spaceName) {
+			return errors.NewNotValid(nil, fmt.Sprintf(
+				"machine %q has an address in excluded space %q", m.Id(), spaceName,
+			))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type JujuManagedSpacesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&JujuManagedSpacesSuite{})
+
+func (s *JujuManagedSpacesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"juju-managed-spaces": true,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, cidr := range []string{"10.0.1.0/24", "10.0.2.0/24"} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err = s.State.AddSpace("db", "", []string{"10.0.1.0/24"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *JujuManagedSpacesSuite) setDeviceAddresses(c *gc.C, m *state.Machine, deviceType state.LinkLayerDeviceType, name string, method state.AddressConfigMethod, addresses ...string) {
+	err := m.SetLinkLayerDevices(state.LinkLayerDeviceArgs{
+		Name: name,
+		Type: deviceType,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	args := make([]state.LinkLayerDeviceAddress, len(addresses))
+	for i, address := range addresses {
+		args[i] = state.LinkLayerDeviceAddress{
+			DeviceName:   name,
+			ConfigMethod: method,
+			CIDRAddress:  address,
+		}
+	}
+	err = m.SetDevicesAddresses(args...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *JujuManagedSpacesSuite) subnetCIDRs(c *gc.C) []string {
+	subnets, err := s.State.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		cidrs[i] = subnet.CIDR()
+	}
+	return cidrs
+}
+
+func (s *JujuManagedSpacesSuite) TestDiscoverSubnets(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setDeviceAddresses(c, m, state.LoopbackDevice, "lo", state.LoopbackAddress, "127.0.0.1/8")
+	s.setDeviceAddresses(c, m, state.EthernetDevice, "eth0", state.StaticAddress, "10.0.1.10/24", "fe80::1/64")
+	s.setDeviceAddresses(c, m, state.EthernetDevice, "eth1", state.DynamicAddress, "10.0.3.10/24")
+
+	err = m.DiscoverSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.subnetCIDRs(c), jc.SameContents, []string{"10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"})
+
+	subnet, err := s.State.Subnet("10.0.3.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+
+	// Discovering again changes nothing.
+	err = m.DiscoverSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.subnetCIDRs(c), gc.HasLen, 3)
+}
+
+func (s *JujuManagedSpacesSuite) addBoundUnit(c *gc.C) *state.Unit {
+	service := s.AddTestingServiceWithBindings(c, "mysql", s.AddTestingCharm(c, "mysql"), map[string]string{
+		"server": "db",
+	})
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *JujuManagedSpacesSuite) addMachineWithAddress(c *gc.C, address string) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProviderAddresses(network.NewAddress(address))
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *JujuManagedSpacesSuite) TestAssignToMachineHonoursBindings(c *gc.C) {
+	unit := s.addBoundUnit(c)
+	outside := s.addMachineWithAddress(c, "10.0.2.10")
+	inside := s.addMachineWithAddress(c, "10.0.1.10")
+
+	err := unit.AssignToMachine(outside)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine "0" has no address in space "db"`)
+
+	err = unit.AssignToMachine(inside)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *JujuManagedSpacesSuite) TestAssignToMachineHonoursLinkLayerAddresses(c *gc.C) {
+	unit := s.addBoundUnit(c)
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setDeviceAddresses(c, m, state.EthernetDevice, "eth0", state.StaticAddress, "10.0.1.10/24")
+
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *JujuManagedSpacesSuite) TestAssignToMachineHonoursExcludedSpaces(c *gc.C) {
+	service := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := service.SetConstraints(constraints.MustParse("spaces=^db"))
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m := s.addMachineWithAddress(c, "10.0.1.10")
+
+	err = unit.AssignToMachine(m)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0: machine "0" has an address in excluded space "db"`)
+}
+
+func (s *JujuManagedSpacesSuite) TestAssignToCleanMachineSkipsMachinesOutsideSpaces(c *gc.C) {
+	unit := s.addBoundUnit(c)
+	s.addMachineWithAddress(c, "10.0.2.10")
+	inside := s.addMachineWithAddress(c, "10.0.1.10")
+
+	m, err := unit.AssignToCleanMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, inside.Id())
+}
+
+func (s *JujuManagedSpacesSuite) TestAssignToMachineWithoutJujuManagedSpaces(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"juju-managed-spaces": false,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.addBoundUnit(c)
+	m := s.addMachineWithAddress(c, "10.0.2.10")
+
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -443,6 +443,8 @@ func (e *exporter) addService(service *Service, refcounts map[string]int, units
 		ForceCharm:           service.doc.ForceCharm,
 		Exposed:              service.doc.Exposed,
 		MinUnits:             service.doc.MinUnits,
+		StatusAggregation:    string(service.doc.StatusAggregation),
+		HookTimeout:          service.doc.HookTimeout,
 		Settings:             serviceSettingsDoc.Settings,
 		SettingsRefCount:     refCount,
 		Leader:               leader,
@@ -668,6 +668,8 @@ func (i *importer) makeServiceDoc(s description.Service) (*serviceDoc, error) {
 		RelationCount:        i.relationCount(s.Name()),
 		Exposed:              s.Exposed(),
 		MinUnits:             s.MinUnits(),
+		StatusAggregation:    StatusAggregation(s.StatusAggregation()),
+		HookTimeout:          s.HookTimeout(),
 		MetricCredentials:    s.MetricsCredentials(),
 	}, nil
 }
@@ -71,6 +71,12 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// upgradeInfoC is used to coordinate upgrades and schema migrations,
 		// and aren't needed for model migrations.
 		upgradeInfoC,
+		// A staged upgrade in progress is tied to the machines of the
+		// source controller; the target starts without one.
+		upgradeRolloutsC,
+		// Upgrades made on the source controller cannot be rolled
+		// back on the target.
+		upgradeRollbacksC,
 		// Not exported, but the tools will possibly need to be either bundled
 		// with the representation or sent separately.
 		toolsmetadataC,
@@ -112,6 +118,11 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// The SSH host keys for each machine will be reported as each
 		// machine agent starts up.
 		sshHostKeysC,
+
+		// Hook execution timings are diagnostic, and are recorded
+		// afresh by the unit agents once they're running against
+		// the target controller.
+		hookExecutionsC,
 	)
 
 	// THIS SET WILL BE REMOVED WHEN MIGRATIONS ARE COMPLETE
@@ -127,6 +138,7 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		"payloads",
 		"resources",
 		endpointBindingsC,
+		unitStatesC,
 
 		// storage
 		blockDevicesC,
@@ -144,12 +156,18 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		linkLayerDevicesRefsC,
 		subnetsC,
 		spacesC,
+		firewallRulesC,
 
 		// actions
 		actionsC,
 		actionNotificationsC,
 		actionresultsC,
 
+		// secrets, which must be re-encrypted with the target
+		// controller's key
+		secretsC,
+		secretRevisionsC,
+
 		// uncategorised
 		metricsManagerC, // should really be copied across
 	)
@@ -311,6 +329,8 @@ func (s *MigrationSuite) TestServiceDocFields(c *gc.C) {
 		"Exposed",
 		"MinUnits",
 		"MetricCredentials",
+		"StatusAggregation",
+		"HookTimeout",
 	)
 	s.AssertExportedFields(c, serviceDoc{}, migrated.Union(ignored))
 }
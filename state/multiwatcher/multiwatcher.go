@@ -205,6 +205,9 @@ type UnitInfo struct {
 	// Workload and agent state are modelled separately.
 	WorkloadStatus StatusInfo
 	JujuStatus     StatusInfo
+	// Health is evaluated by the unit agent from the health
+	// checks declared by the unit's charm.
+	Health StatusInfo
 }
 
 // EntityId returns a unique identifier for a unit across
@@ -265,12 +265,13 @@ func newState(modelTag names.ModelTag, session *mgo.Session, mongoInfo *mongo.Mo
 
 	// Create State.
 	return &State{
-		modelTag:  modelTag,
-		mongoInfo: mongoInfo,
-		session:   session,
-		database:  database,
-		policy:    policy,
-		watcher:   watcher.New(rawDB.C(txnLogC)),
+		modelTag:   modelTag,
+		mongoInfo:  mongoInfo,
+		session:    session,
+		database:   database,
+		policy:     policy,
+		watcher:    watcher.New(rawDB.C(txnLogC)),
+		secretsKey: &secretsKeyHolder{},
 	}, nil
 }
 
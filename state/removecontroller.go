@@ -0,0 +1,131 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RemoveControllerMachine takes the controller machine with the given
+// id one step further towards removal, and reports the changes made.
+//
+// A controller that wants a vote is demoted, so that the peergrouper
+// removes its vote from the replica set. As the peergrouper keeps the
+// number of votes odd, another controller must take over the vote at
+// the same time: a new controller machine is added using replacement
+// if it is not nil, and otherwise an available non-voting controller
+// is promoted.
+//
+// A demoted controller that still has a vote is left alone until the
+// peergrouper has removed it, and no changes are reported.
+//
+// A controller with no vote stops being a controller and is
+// destroyed. The peergrouper publishes the new set of API addresses
+// as soon as the machine leaves the controller set, which happens
+// before the machine's agent shuts down and its instance is stopped.
+func (st *State) RemoveControllerMachine(id string, replacement *MachineTemplate) (ControllersChanges, error) {
+	var change ControllersChanges
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		change = ControllersChanges{}
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !m.IsManager() {
+			return nil, errors.Errorf("machine %s is not a controller", id)
+		}
+		if principals := m.Principals(); len(principals) > 0 {
+			return nil, &HasAssignedUnitsError{
+				MachineId: id,
+				UnitNames: principals,
+			}
+		}
+		switch {
+		case m.WantsVote():
+			info, err := st.ControllerInfo()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			var ops []txn.Op
+			ops, change, err = st.retireVoterOps(m, info, replacement)
+			return ops, errors.Trace(err)
+		case m.HasVote():
+			logger.Infof("waiting for vote to be removed from controller machine %s", id)
+			return nil, jujutxn.ErrNoOperations
+		}
+		change.Removed = []string{id}
+		return removeControllerOps(m), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return ControllersChanges{}, errors.Annotatef(err, "cannot remove controller machine %s", id)
+	}
+	if len(change.Removed) == 0 {
+		return change, nil
+	}
+	m, err := st.Machine(id)
+	if err != nil {
+		return change, errors.Trace(err)
+	}
+	if err := m.Destroy(); err != nil {
+		return change, errors.Annotatef(err, "cannot destroy machine %s", id)
+	}
+	return change, nil
+}
+
+// retireVoterOps returns the operations needed to demote the given
+// voting controller, and to pass its vote to another controller.
+func (st *State) retireVoterOps(
+	m *Machine, info *ControllerInfo, replacement *MachineTemplate,
+) ([]txn.Op, ControllersChanges, error) {
+	change := ControllersChanges{
+		Demoted: []string{m.Id()},
+	}
+	ops := demoteControllerOps(m)
+	if replacement != nil {
+		template := *replacement
+		template.Jobs = []MachineJob{
+			JobHostUnits,
+			JobManageModel,
+		}
+		mdoc, addOps, err := st.addMachineOps(template)
+		if err != nil {
+			return nil, ControllersChanges{}, errors.Trace(err)
+		}
+		ssOps, err := st.maintainControllersOps([]*machineDoc{mdoc}, info)
+		if err != nil {
+			return nil, ControllersChanges{}, errors.Annotate(err, "cannot prepare machine add operations")
+		}
+		ops = append(ops, addOps...)
+		ops = append(ops, ssOps...)
+		change.Added = []string{mdoc.Id}
+		return ops, change, nil
+	}
+	for _, id := range info.MachineIds {
+		if id == m.Id() {
+			continue
+		}
+		candidate, err := st.Machine(id)
+		if err != nil {
+			return nil, ControllersChanges{}, errors.Trace(err)
+		}
+		if candidate.WantsVote() || candidate.HasVote() {
+			// A controller that has a vote but does not want
+			// one is already being removed.
+			continue
+		}
+		available, err := controllerAvailable(candidate)
+		if err != nil {
+			return nil, ControllersChanges{}, errors.Trace(err)
+		}
+		if !available {
+			continue
+		}
+		ops = append(ops, promoteControllerOps(candidate)...)
+		change.Promoted = []string{id}
+		return ops, change, nil
+	}
+	return nil, ControllersChanges{}, errors.New("no replacement was requested, and there is no available non-voting controller to take over its vote")
+}
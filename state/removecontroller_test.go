@@ -0,0 +1,116 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+func (s *StateSuite) enableHA3(c *gc.C) {
+	s.PatchValue(state.ControllerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 3)
+	for _, id := range changes.Added {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetHasVote(true)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *StateSuite) TestRemoveControllerMachineWithReplacement(c *gc.C) {
+	s.enableHA3(c)
+
+	changes, err := s.State.RemoveControllerMachine("1", &state.MachineTemplate{
+		Series:    "quantal",
+		Placement: "zone=z2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, state.ControllersChanges{
+		Added:   []string{"3"},
+		Demoted: []string{"1"},
+	})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"}, nil)
+	m3, err := s.State.Machine("3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m3.Placement(), gc.Equals, "zone=z2")
+	c.Assert(m3.WantsVote(), jc.IsTrue)
+
+	// Machine 1 keeps its controller job until the peergrouper
+	// has removed its vote.
+	changes, err = s.State.RemoveControllerMachine("1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, state.ControllersChanges{})
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsTrue)
+
+	err = m1.SetHasVote(false)
+	c.Assert(err, jc.ErrorIsNil)
+	changes, err = s.State.RemoveControllerMachine("1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, state.ControllersChanges{
+		Removed: []string{"1"},
+	})
+	s.assertControllerInfo(c, []string{"0", "2", "3"}, []string{"0", "2", "3"}, nil)
+	err = m1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m1.IsManager(), jc.IsFalse)
+	c.Assert(m1.Life(), gc.Equals, state.Dying)
+}
+
+func (s *StateSuite) TestRemoveControllerMachinePromotesSpare(c *gc.C) {
+	s.enableHA3(c)
+	// Make machine 2 a non-voting spare controller.
+	changes, err := s.State.RemoveControllerMachine("2", &state.MachineTemplate{Series: "quantal"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, jc.DeepEquals, []string{"3"})
+	m2, err := s.State.Machine("2")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m2.SetHasVote(false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err = s.State.RemoveControllerMachine("0", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, state.ControllersChanges{
+		Demoted:  []string{"0"},
+		Promoted: []string{"2"},
+	})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"1", "2", "3"}, nil)
+}
+
+func (s *StateSuite) TestRemoveControllerMachineNeedsReplacement(c *gc.C) {
+	s.enableHA3(c)
+	_, err := s.State.RemoveControllerMachine("1", nil)
+	c.Assert(err, gc.ErrorMatches, "cannot remove controller machine 1: no replacement was requested, .*")
+	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, nil)
+}
+
+func (s *StateSuite) TestRemoveControllerMachineNotController(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.RemoveControllerMachine(m.Id(), nil)
+	c.Assert(err, gc.ErrorMatches, "cannot remove controller machine 0: machine 0 is not a controller")
+}
+
+func (s *StateSuite) TestRemoveControllerMachineWithUnits(c *gc.C) {
+	s.enableHA3(c)
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	m1, err := s.State.Machine("1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.RemoveControllerMachine("1", &state.MachineTemplate{Series: "quantal"})
+	c.Assert(err, gc.ErrorMatches, `cannot remove controller machine 1: machine 1 has unit "wordpress/0" assigned`)
+}
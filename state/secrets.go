@@ -0,0 +1,713 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// secretsKeySize is the size in bytes of the secrets key, selecting
+// AES-256.
+const secretsKeySize = 32
+
+// secretDoc represents a Secret in mongo. The values held by the
+// secret are stored, encrypted, in its revisions.
+type secretDoc struct {
+	DocID          string    `bson:"_id"`
+	ModelUUID      string    `bson:"model-uuid"`
+	Id             string    `bson:"id"`
+	Owner          string    `bson:"owner"`
+	Description    string    `bson:"description"`
+	RotateInterval int64     `bson:"rotate-interval"`
+	Revision       int       `bson:"revision"`
+	Keys           []string  `bson:"keys"`
+	Grants         []string  `bson:"grants"`
+	Created        time.Time `bson:"created"`
+	Updated        time.Time `bson:"updated"`
+}
+
+// secretRevisionDoc holds the encrypted values of a single revision
+// of a secret.
+type secretRevisionDoc struct {
+	DocID     string    `bson:"_id"`
+	ModelUUID string    `bson:"model-uuid"`
+	SecretId  string    `bson:"secret-id"`
+	Revision  int       `bson:"revision"`
+	Keys      []string  `bson:"keys"`
+	Value     []byte    `bson:"value"`
+	Created   time.Time `bson:"created"`
+}
+
+// Secret represents a set of values stored by a charm, and shared by
+// the units of the owning service with chosen units and relations.
+type Secret struct {
+	st  *State
+	doc secretDoc
+}
+
+// SecretRevision describes a single revision of a secret.
+type SecretRevision struct {
+	Revision int
+	Keys     []string
+	Created  time.Time
+}
+
+// AddSecretParams holds the parameters for adding a secret.
+type AddSecretParams struct {
+	// Owner is the service whose units may read, revise, grant
+	// and revoke the secret.
+	Owner names.ServiceTag
+
+	// Description describes the secret to administrators.
+	Description string
+
+	// RotateInterval is the interval at which the owner is expected
+	// to add new revisions of the secret, or zero if it need not be
+	// rotated.
+	RotateInterval time.Duration
+
+	// Data holds the values of the secret's first revision.
+	Data map[string]string
+}
+
+// Id returns the id of the secret.
+func (s *Secret) Id() string {
+	return s.doc.Id
+}
+
+// String returns a human readable string representation of the secret.
+func (s *Secret) String() string {
+	return s.doc.Id
+}
+
+// Owner returns the tag of the service that owns the secret.
+func (s *Secret) Owner() names.ServiceTag {
+	return names.NewServiceTag(s.doc.Owner)
+}
+
+// Description returns the description of the secret.
+func (s *Secret) Description() string {
+	return s.doc.Description
+}
+
+// RotateInterval returns the interval at which the secret should be
+// rotated, or zero if it need not be.
+func (s *Secret) RotateInterval() time.Duration {
+	return time.Duration(s.doc.RotateInterval)
+}
+
+// NextRotation returns the time at which the secret is next due to be
+// rotated, and false if it need not be.
+func (s *Secret) NextRotation() (time.Time, bool) {
+	if s.doc.RotateInterval == 0 {
+		return time.Time{}, false
+	}
+	return s.doc.Updated.Add(s.RotateInterval()), true
+}
+
+// Revision returns the latest revision of the secret.
+func (s *Secret) Revision() int {
+	return s.doc.Revision
+}
+
+// Keys returns the sorted keys of the latest revision of the secret.
+func (s *Secret) Keys() []string {
+	return s.doc.Keys
+}
+
+// Created returns the time at which the secret was added.
+func (s *Secret) Created() time.Time {
+	return s.doc.Created
+}
+
+// Updated returns the time at which the latest revision was added.
+func (s *Secret) Updated() time.Time {
+	return s.doc.Updated
+}
+
+// Grants returns the tags of the units and relations with which the
+// secret has been shared.
+func (s *Secret) Grants() ([]names.Tag, error) {
+	tags := make([]names.Tag, len(s.doc.Grants))
+	for i, grant := range s.doc.Grants {
+		tag, err := names.ParseTag(grant)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tags[i] = tag
+	}
+	return tags, nil
+}
+
+// IsGranted returns whether the secret has been shared with the
+// supplied unit or relation.
+func (s *Secret) IsGranted(subject names.Tag) bool {
+	for _, grant := range s.doc.Grants {
+		if grant == subject.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh refreshes the contents of the secret from the underlying
+// state. It returns an error that satisfies errors.IsNotFound if the
+// secret has been removed.
+func (s *Secret) Refresh() error {
+	secrets, closer := s.st.getCollection(secretsC)
+	defer closer()
+
+	err := secrets.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("secret %q", s)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh secret %q", s)
+	}
+	return nil
+}
+
+// Revisions returns every revision of the secret, oldest first.
+func (s *Secret) Revisions() ([]SecretRevision, error) {
+	revisions, closer := s.st.getCollection(secretRevisionsC)
+	defer closer()
+
+	var docs []secretRevisionDoc
+	query := revisions.Find(bson.D{{"secret-id", s.doc.Id}}).Sort("revision")
+	if err := query.Select(bson.D{{"value", 0}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get revisions of secret %q", s)
+	}
+	results := make([]SecretRevision, len(docs))
+	for i, doc := range docs {
+		results[i] = SecretRevision{
+			Revision: doc.Revision,
+			Keys:     doc.Keys,
+			Created:  doc.Created,
+		}
+	}
+	return results, nil
+}
+
+// Value returns the decrypted values of the supplied revision of the
+// secret, or of its latest revision if revision is zero.
+func (s *Secret) Value(revision int) (map[string]string, error) {
+	if revision == 0 {
+		revision = s.doc.Revision
+	}
+	revisions, closer := s.st.getCollection(secretRevisionsC)
+	defer closer()
+
+	var doc secretRevisionDoc
+	err := revisions.FindId(secretRevisionKey(s.doc.Id, revision)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q revision %d", s, revision)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q revision %d", s, revision)
+	}
+	key, err := s.st.getSecretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := decryptSecretValue(key, doc.DocID, doc.Value)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot decrypt secret %q revision %d", s, revision)
+	}
+	return data, nil
+}
+
+// AddRevision stores the supplied values as a new revision of the
+// secret, which readers of the secret will see from then on. This is
+// how a secret is rotated.
+func (s *Secret) AddRevision(data map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add revision to secret %q", s)
+	if len(data) == 0 {
+		return errors.New("no values specified")
+	}
+	key, err := s.st.getSecretsKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	keys := secretKeys(data)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		revision := s.doc.Revision + 1
+		revisionDocID := s.st.docID(secretRevisionKey(s.doc.Id, revision))
+		value, err := encryptSecretValue(key, revisionDocID, data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		now := nowToTheSecond()
+		return []txn.Op{{
+			C:      secretsC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"revision", s.doc.Revision}},
+			Update: bson.D{{"$set", bson.D{
+				{"revision", revision},
+				{"keys", keys},
+				{"updated", now},
+			}}},
+		}, {
+			C:      secretRevisionsC,
+			Id:     secretRevisionKey(s.doc.Id, revision),
+			Assert: txn.DocMissing,
+			Insert: &secretRevisionDoc{
+				DocID:    revisionDocID,
+				SecretId: s.doc.Id,
+				Revision: revision,
+				Keys:     keys,
+				Value:    value,
+				Created:  now,
+			},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	return s.Refresh()
+}
+
+// Grant shares the secret with the supplied unit or relation, which
+// must be alive. Units are granted access to a secret shared with a
+// relation by virtue of their service being one of the relation's
+// endpoints. Grants are revoked when the unit or relation is removed.
+func (s *Secret) Grant(subject names.Tag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot grant secret %q to %s", s, names.ReadableString(subject))
+	if err := validateSecretSubject(subject); err != nil {
+		return errors.Trace(err)
+	}
+	var subjectOp txn.Op
+	switch subject := subject.(type) {
+	case names.UnitTag:
+		unit, err := s.st.Unit(subject.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		subjectOp = txn.Op{C: unitsC, Id: unit.doc.DocID, Assert: isAliveDoc}
+	case names.RelationTag:
+		relation, err := s.st.KeyRelation(subject.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		subjectOp = txn.Op{C: relationsC, Id: relation.doc.DocID, Assert: isAliveDoc}
+	}
+	ops := []txn.Op{subjectOp, {
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"grants", subject.String()}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Errorf("%s is not alive", names.ReadableString(subject))
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return s.Refresh()
+}
+
+// Revoke withdraws a grant of the secret to the supplied unit or
+// relation. It is not an error to revoke a grant that was never made.
+func (s *Secret) Revoke(subject names.Tag) error {
+	if err := validateSecretSubject(subject); err != nil {
+		return errors.Annotatef(err, "cannot revoke secret %q", s)
+	}
+	op := txn.Op{
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pull", bson.D{{"grants", subject.String()}}}},
+	}
+	if err := s.updateGrants(op); err != nil {
+		return errors.Annotatef(err, "cannot revoke secret %q from %s", s, names.ReadableString(subject))
+	}
+	return nil
+}
+
+func (s *Secret) updateGrants(op txn.Op) error {
+	err := s.st.runTransaction([]txn.Op{op})
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("secret %q", s)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return s.Refresh()
+}
+
+// revokeSecretGrantsOps returns the operations required to revoke all
+// grants of secrets to the supplied unit or relation, which is being
+// removed, so that a later unit or relation with the same name is not
+// granted access to them.
+func revokeSecretGrantsOps(st *State, subject names.Tag) ([]txn.Op, error) {
+	secrets, err := st.secrets(bson.D{{"grants", subject.String()}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(secrets))
+	for i, secret := range secrets {
+		ops[i] = txn.Op{
+			C:      secretsC,
+			Id:     secret.doc.DocID,
+			Update: bson.D{{"$pull", bson.D{{"grants", subject.String()}}}},
+		}
+	}
+	return ops, nil
+}
+
+// removeOps returns the operations required to remove the secret and
+// all of its revisions.
+func (s *Secret) removeOps() []txn.Op {
+	ops := []txn.Op{{
+		C:      secretsC,
+		Id:     s.doc.DocID,
+		Remove: true,
+	}}
+	for revision := 1; revision <= s.doc.Revision; revision++ {
+		ops = append(ops, txn.Op{
+			C:      secretRevisionsC,
+			Id:     s.st.docID(secretRevisionKey(s.doc.Id, revision)),
+			Remove: true,
+		})
+	}
+	return ops
+}
+
+// AddSecret stores a new secret owned by a service, and returns it.
+func (st *State) AddSecret(args AddSecretParams) (_ *Secret, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add secret for %s", names.ReadableString(args.Owner))
+	if len(args.Data) == 0 {
+		return nil, errors.New("no values specified")
+	}
+	if args.RotateInterval < 0 {
+		return nil, errors.NotValidf("rotate interval %v", args.RotateInterval)
+	}
+	service, err := st.Service(args.Owner.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if service.Life() != Alive {
+		return nil, errors.Errorf("service is not alive")
+	}
+	key, err := st.getSecretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := st.sequence("secret")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	revisionDocID := st.docID(secretRevisionKey(id, 1))
+	value, err := encryptSecretValue(key, revisionDocID, args.Data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := secretKeys(args.Data)
+	now := nowToTheSecond()
+	doc := secretDoc{
+		DocID:          st.docID(id),
+		Id:             id,
+		Owner:          args.Owner.Id(),
+		Description:    args.Description,
+		RotateInterval: int64(args.RotateInterval),
+		Revision:       1,
+		Keys:           keys,
+		Created:        now,
+		Updated:        now,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     service.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      secretsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}, {
+		C:      secretRevisionsC,
+		Id:     secretRevisionKey(id, 1),
+		Assert: txn.DocMissing,
+		Insert: &secretRevisionDoc{
+			DocID:    revisionDocID,
+			SecretId: id,
+			Revision: 1,
+			Keys:     keys,
+			Value:    value,
+			Created:  now,
+		},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.Errorf("service is not alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc.ModelUUID = st.ModelUUID()
+	return &Secret{st: st, doc: doc}, nil
+}
+
+// Secret returns the secret with the supplied id.
+func (st *State) Secret(id string) (*Secret, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+
+	var doc secretDoc
+	err := secrets.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get secret %q", id)
+	}
+	return &Secret{st: st, doc: doc}, nil
+}
+
+// AllSecrets returns every secret in the model, ordered by id.
+func (st *State) AllSecrets() ([]*Secret, error) {
+	return st.secrets(nil)
+}
+
+// SecretsDueForRotation returns the secrets owned by the supplied
+// service that were due to be rotated at or before the supplied time,
+// ordered by id.
+func (st *State) SecretsDueForRotation(owner names.ServiceTag, now time.Time) ([]*Secret, error) {
+	secrets, err := st.secrets(bson.D{
+		{"owner", owner.Id()},
+		{"rotate-interval", bson.D{{"$gt", 0}}},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var due []*Secret
+	for _, secret := range secrets {
+		if next, ok := secret.NextRotation(); ok && !next.After(now) {
+			due = append(due, secret)
+		}
+	}
+	return due, nil
+}
+
+func (st *State) secrets(query bson.D) ([]*Secret, error) {
+	secrets, closer := st.getCollection(secretsC)
+	defer closer()
+
+	var docs []secretDoc
+	if err := secrets.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get secrets")
+	}
+	results := make([]*Secret, len(docs))
+	for i, doc := range docs {
+		results[i] = &Secret{st: st, doc: doc}
+	}
+	sort.Sort(secretsById(results))
+	return results, nil
+}
+
+// cleanupSecretsForRemovedService removes the secrets owned by the
+// specified service, which has been removed from state.
+func (st *State) cleanupSecretsForRemovedService(serviceName string) error {
+	secrets, err := st.secrets(bson.D{{"owner", serviceName}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, secret := range secrets {
+		if err := st.runTransaction(secret.removeOps()); err != nil {
+			return errors.Annotatef(err, "cannot remove secret %q", secret)
+		}
+	}
+	return nil
+}
+
+// NewSecretsKey returns a new randomly generated key, suitable for
+// passing to SetSecretsKey, with which a controller encrypts secret
+// values. The key is never stored in the database; it is kept by the
+// controller agents alongside the other state serving info.
+func NewSecretsKey() (string, error) {
+	key := make([]byte, secretsKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Annotate(err, "cannot generate secrets key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// SecretsKeySource returns the controller's secrets key, in the form
+// returned by NewSecretsKey, or "" if the controller does not have one.
+type SecretsKeySource func() string
+
+// secretsKeyHolder holds the controller's secrets key, and the source
+// from which it is obtained if it has not been set.
+type secretsKeyHolder struct {
+	mu     sync.Mutex
+	key    []byte
+	source SecretsKeySource
+}
+
+// SetSecretsKey sets the key, as returned by NewSecretsKey, with which
+// secret values are encrypted and decrypted. States returned by
+// ForModel share the key.
+func (st *State) SetSecretsKey(encoded string) error {
+	key, err := decodeSecretsKey(encoded)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st.secretsKey.mu.Lock()
+	defer st.secretsKey.mu.Unlock()
+	st.secretsKey.key = key
+	return nil
+}
+
+// SetSecretsKeySource sets the source from which the secrets key is
+// obtained when it is first needed, if none has been set with
+// SetSecretsKey. The source is consulted until it returns a key, so
+// a key provisioned after the State is opened, by an upgrade step
+// or by another controller, is used without reopening it. States
+// returned by ForModel share the source.
+func (st *State) SetSecretsKeySource(source SecretsKeySource) {
+	st.secretsKey.mu.Lock()
+	defer st.secretsKey.mu.Unlock()
+	st.secretsKey.source = source
+}
+
+// SecretsKey returns the key supplied to SetSecretsKey or obtained
+// from the source, in the same form, or "" if there is none. It
+// allows the key to be passed on to new controllers.
+func (st *State) SecretsKey() string {
+	key, err := st.getSecretsKey()
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// getSecretsKey returns the controller's secrets key, or an error if
+// none was supplied with SetSecretsKey or by the source.
+func (st *State) getSecretsKey() ([]byte, error) {
+	st.secretsKey.mu.Lock()
+	defer st.secretsKey.mu.Unlock()
+	if st.secretsKey.key == nil && st.secretsKey.source != nil {
+		if encoded := st.secretsKey.source(); encoded != "" {
+			key, err := decodeSecretsKey(encoded)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			st.secretsKey.key = key
+		}
+	}
+	if st.secretsKey.key == nil {
+		return nil, errors.NotProvisionedf("secrets key")
+	}
+	return st.secretsKey.key, nil
+}
+
+func decodeSecretsKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode secrets key")
+	}
+	if len(key) != secretsKeySize {
+		return nil, errors.NotValidf("secrets key of %d bytes", len(key))
+	}
+	return key, nil
+}
+
+// encryptSecretValue encrypts the supplied values with AES-GCM,
+// prefixing the result with the random nonce used. The ciphertext is
+// bound to the revision doc it is stored in by authenticating the
+// doc's id, so that it cannot be decrypted as any other revision or
+// secret.
+func encryptSecretValue(key []byte, revisionDocID string, data map[string]string) ([]byte, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := newSecretsAEAD(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(revisionDocID)), nil
+}
+
+// decryptSecretValue reverses encryptSecretValue.
+func decryptSecretValue(key []byte, revisionDocID string, value []byte) (map[string]string, error) {
+	aead, err := newSecretsAEAD(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(value) < aead.NonceSize() {
+		return nil, errors.New("value too short")
+	}
+	nonce, ciphertext := value[:aead.NonceSize()], value[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(revisionDocID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var data map[string]string
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+func newSecretsAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func validateSecretSubject(subject names.Tag) error {
+	switch subject.(type) {
+	case names.UnitTag, names.RelationTag:
+		return nil
+	}
+	return errors.NotValidf("grant to %s", names.ReadableString(subject))
+}
+
+func secretRevisionKey(id string, revision int) string {
+	return fmt.Sprintf("%s#%d", id, revision)
+}
+
+func secretKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type secretsById []*Secret
+
+func (s secretsById) Len() int      { return len(s) }
+func (s secretsById) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s secretsById) Less(i, j int) bool {
+	a, _ := strconv.Atoi(s[i].doc.Id)
+	b, _ := strconv.Atoi(s[j].doc.Id)
+	return a < b
+}
@@ -0,0 +1,390 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Name: "mysql"})
+}
+
+func (s *SecretsSuite) addSecret(c *gc.C) *state.Secret {
+	secret, err := s.State.AddSecret(state.AddSecretParams{
+		Owner:          s.service.ServiceTag(),
+		Description:    "root credentials",
+		RotateInterval: time.Hour,
+		Data:           map[string]string{"user": "root", "password": "s3cr3t"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return secret
+}
+
+func (s *SecretsSuite) TestAddSecret(c *gc.C) {
+	secret := s.addSecret(c)
+	c.Check(secret.Owner(), gc.Equals, s.service.ServiceTag())
+	c.Check(secret.Description(), gc.Equals, "root credentials")
+	c.Check(secret.Revision(), gc.Equals, 1)
+	c.Check(secret.Keys(), jc.DeepEquals, []string{"password", "user"})
+	c.Check(secret.RotateInterval(), gc.Equals, time.Hour)
+	next, ok := secret.NextRotation()
+	c.Check(ok, jc.IsTrue)
+	c.Check(next, gc.Equals, secret.Updated().Add(time.Hour))
+
+	value, err := secret.Value(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, jc.DeepEquals, map[string]string{"user": "root", "password": "s3cr3t"})
+
+	same, err := s.State.Secret(secret.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(same.Keys(), jc.DeepEquals, secret.Keys())
+}
+
+func (s *SecretsSuite) TestAddSecretNoValues(c *gc.C) {
+	_, err := s.State.AddSecret(state.AddSecretParams{Owner: s.service.ServiceTag()})
+	c.Assert(err, gc.ErrorMatches, `cannot add secret for service mysql: no values specified`)
+}
+
+func (s *SecretsSuite) TestAddSecretServiceNotAlive(c *gc.C) {
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSecret(state.AddSecretParams{
+		Owner: s.service.ServiceTag(),
+		Data:  map[string]string{"a": "b"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add secret for service mysql: service is not alive`)
+}
+
+func (s *SecretsSuite) TestValuesEncrypted(c *gc.C) {
+	s.addSecret(c)
+	revisions := s.State.MongoSession().DB("juju").C(state.SecretRevisionsC)
+	var doc struct {
+		Value []byte `bson:"value"`
+	}
+	err := revisions.Find(bson.D{{"model-uuid", s.State.ModelUUID()}}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc.Value, gc.Not(gc.HasLen), 0)
+	c.Check(bytes.Contains(doc.Value, []byte("s3cr3t")), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestValuesBoundToRevision(c *gc.C) {
+	secret := s.addSecret(c)
+	err := secret.AddRevision(map[string]string{"password": "n3w"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Swapping the ciphertexts of two revisions does not let one be
+	// read as the other.
+	revisions := s.State.MongoSession().DB("juju").C(state.SecretRevisionsC)
+	var docs []struct {
+		DocID string `bson:"_id"`
+		Value []byte `bson:"value"`
+	}
+	err = revisions.Find(bson.D{{"secret-id", secret.Id()}}).Sort("revision").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	err = revisions.UpdateId(docs[0].DocID, bson.D{{"$set", bson.D{{"value", docs[1].Value}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = secret.Value(1)
+	c.Assert(err, gc.ErrorMatches, `cannot decrypt secret ".*" revision 1: .*`)
+	value, err := secret.Value(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, jc.DeepEquals, map[string]string{"password": "n3w"})
+}
+
+func (s *SecretsSuite) TestSecretsKeyNotInDatabase(c *gc.C) {
+	s.addSecret(c)
+	count, err := s.State.MongoSession().DB("juju").C("controllers").FindId("secretsKey").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+}
+
+func (s *SecretsSuite) TestSecretsKeyRequired(c *gc.C) {
+	secret := s.addSecret(c)
+	st, err := state.Open(s.modelTag, statetesting.NewMongoInfo(), statetesting.NewDialOpts(), state.Policy(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.AddSecret(state.AddSecretParams{
+		Owner: s.service.ServiceTag(),
+		Data:  map[string]string{"a": "b"},
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot add secret for service mysql: secrets key not provisioned")
+	secret, err = st.Secret(secret.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = secret.Value(0)
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *SecretsSuite) TestSecretsKeySource(c *gc.C) {
+	st, err := state.Open(s.modelTag, statetesting.NewMongoInfo(), statetesting.NewDialOpts(), state.Policy(nil))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	modelSt, err := st.ForModel(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	defer modelSt.Close()
+
+	// The source is consulted until it has a key, which is then
+	// shared with the States returned by ForModel.
+	var key string
+	st.SetSecretsKeySource(func() string { return key })
+	c.Check(modelSt.SecretsKey(), gc.Equals, "")
+	_, err = modelSt.AddSecret(state.AddSecretParams{
+		Owner: s.service.ServiceTag(),
+		Data:  map[string]string{"a": "b"},
+	})
+	c.Assert(err, gc.ErrorMatches, ".*secrets key not provisioned")
+
+	key = testing.SecretsKey
+	secret, err := modelSt.AddSecret(state.AddSecretParams{
+		Owner: s.service.ServiceTag(),
+		Data:  map[string]string{"a": "b"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(st.SecretsKey(), gc.Equals, testing.SecretsKey)
+	secret, err = s.State.Secret(secret.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	value, err := secret.Value(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, jc.DeepEquals, map[string]string{"a": "b"})
+}
+
+func (s *SecretsSuite) TestSetSecretsKeyInvalid(c *gc.C) {
+	err := s.State.SetSecretsKey("not base64!")
+	c.Assert(err, gc.ErrorMatches, "cannot decode secrets key: .*")
+	err = s.State.SetSecretsKey("c2hvcnQ=")
+	c.Assert(err, gc.ErrorMatches, "secrets key of 5 bytes not valid")
+}
+
+func (s *SecretsSuite) TestNewSecretsKey(c *gc.C) {
+	key, err := state.NewSecretsKey()
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := state.NewSecretsKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(key, gc.Not(gc.Equals), other)
+	err = s.State.SetSecretsKey(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.State.SecretsKey(), gc.Equals, key)
+}
+
+func (s *SecretsSuite) TestSecretsDueForRotation(c *gc.C) {
+	secret := s.addSecret(c)
+	_, err := s.State.AddSecret(state.AddSecretParams{
+		Owner: s.service.ServiceTag(),
+		Data:  map[string]string{"never": "rotated"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeService(c, &factory.ServiceParams{Name: "other"})
+	_, err = s.State.AddSecret(state.AddSecretParams{
+		Owner:          other.ServiceTag(),
+		RotateInterval: time.Minute,
+		Data:           map[string]string{"a": "b"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	next, _ := secret.NextRotation()
+	due, err := s.State.SecretsDueForRotation(s.service.ServiceTag(), next.Add(-time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(due, gc.HasLen, 0)
+	due, err = s.State.SecretsDueForRotation(s.service.ServiceTag(), next)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.HasLen, 1)
+	c.Check(due[0].Id(), gc.Equals, secret.Id())
+
+	// Rotating the secret defers the next rotation.
+	err = secret.AddRevision(map[string]string{"password": "n3w"})
+	c.Assert(err, jc.ErrorIsNil)
+	due, err = s.State.SecretsDueForRotation(s.service.ServiceTag(), secret.Updated())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(due, gc.HasLen, 0)
+}
+
+func (s *SecretsSuite) TestAddRevision(c *gc.C) {
+	secret := s.addSecret(c)
+	err := secret.AddRevision(map[string]string{"user": "root", "password": "n3w"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.Revision(), gc.Equals, 2)
+
+	value, err := secret.Value(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, jc.DeepEquals, map[string]string{"user": "root", "password": "n3w"})
+	value, err = secret.Value(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, jc.DeepEquals, map[string]string{"user": "root", "password": "s3cr3t"})
+	_, err = secret.Value(3)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	revisions, err := secret.Revisions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revisions, gc.HasLen, 2)
+	c.Check(revisions[0].Revision, gc.Equals, 1)
+	c.Check(revisions[1].Revision, gc.Equals, 2)
+	c.Check(revisions[1].Keys, jc.DeepEquals, []string{"password", "user"})
+}
+
+func (s *SecretsSuite) TestAddRevisionConcurrent(c *gc.C) {
+	secret := s.addSecret(c)
+	other, err := s.State.Secret(secret.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.AddRevision(map[string]string{"password": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = secret.AddRevision(map[string]string{"password": "second"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.Revision(), gc.Equals, 3)
+}
+
+// addWordpress adds a wordpress service related to mysql, with a
+// single unit.
+func (s *SecretsSuite) addWordpress(c *gc.C) (*state.Unit, *state.Relation) {
+	wordpress := s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: wordpress})
+	relation := s.addRelation(c)
+	return unit, relation
+}
+
+func (s *SecretsSuite) addRelation(c *gc.C) *state.Relation {
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	relation, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return relation
+}
+
+func (s *SecretsSuite) TestGrantRevoke(c *gc.C) {
+	secret := s.addSecret(c)
+	u, r := s.addWordpress(c)
+	unit, relation := u.Tag(), r.Tag()
+
+	err := secret.Grant(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(relation)
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	grants, err := secret.Grants()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(grants, jc.DeepEquals, []names.Tag{unit, relation})
+	c.Check(secret.IsGranted(unit), jc.IsTrue)
+
+	err = secret.Revoke(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.IsGranted(unit), jc.IsFalse)
+	c.Check(secret.IsGranted(relation), jc.IsTrue)
+}
+
+func (s *SecretsSuite) TestGrantNotFound(c *gc.C) {
+	secret := s.addSecret(c)
+	err := secret.Grant(names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = secret.Grant(names.NewRelationTag("wordpress:db mysql:server"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(secret.IsGranted(names.NewUnitTag("wordpress/0")), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestGrantNotAlive(c *gc.C) {
+	secret := s.addSecret(c)
+	unit, _ := s.addWordpress(c)
+	// A unit whose agent has started is not removed immediately.
+	err := unit.SetAgentStatus(status.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Grant(unit.Tag())
+	c.Assert(err, gc.ErrorMatches, `cannot grant secret ".*" to unit wordpress/0: unit wordpress/0 is not alive`)
+	c.Check(secret.IsGranted(unit.Tag()), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestRemoveRelationRevokesGrants(c *gc.C) {
+	secret := s.addSecret(c)
+	_, relation := s.addWordpress(c)
+	err := secret.Grant(relation.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = relation.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.IsGranted(relation.Tag()), jc.IsFalse)
+
+	// A new relation between the same endpoints has the same tag,
+	// and is not granted access.
+	relation = s.addRelation(c)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.IsGranted(relation.Tag()), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestRemoveUnitRevokesGrants(c *gc.C) {
+	secret := s.addSecret(c)
+	unit, _ := s.addWordpress(c)
+	err := secret.Grant(unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = secret.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secret.IsGranted(unit.Tag()), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestGrantInvalid(c *gc.C) {
+	secret := s.addSecret(c)
+	err := secret.Grant(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, `cannot grant secret "0" to machine 0: grant to machine 0 not valid`)
+}
+
+func (s *SecretsSuite) TestAllSecrets(c *gc.C) {
+	first := s.addSecret(c)
+	second := s.addSecret(c)
+	secrets, err := s.State.AllSecrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 2)
+	c.Check(secrets[0].Id(), gc.Equals, first.Id())
+	c.Check(secrets[1].Id(), gc.Equals, second.Id())
+}
+
+func (s *SecretsSuite) TestRemoveServiceRemovesSecrets(c *gc.C) {
+	secret := s.addSecret(c)
+	err := secret.AddRevision(map[string]string{"password": "n3w"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Secret(secret.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	count, err := s.State.MongoSession().DB("juju").C(state.SecretRevisionsC).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+}
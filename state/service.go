@@ -52,6 +52,14 @@ type serviceDoc struct {
 	OwnerTag             string     `bson:"ownertag"`
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
+
+	// StatusAggregation is empty for services created before status
+	// aggregation policies were introduced; treat that as worst-of.
+	StatusAggregation StatusAggregation `bson:"status-aggregation,omitempty"`
+
+	// HookTimeout, when positive, overrides the model's hook-timeout
+	// for the service's units.
+	HookTimeout time.Duration `bson:"hook-timeout,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -270,6 +278,8 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeLeadershipSettingsOp(s.Name()),
 		removeStatusOp(s.st, s.globalKey()),
 		removeModelServiceRefOp(s.st, s.Name()),
+		s.st.newCleanupOp(cleanupStorageForRemovedService, s.Tag().String()),
+		s.st.newCleanupOp(cleanupSecretsForRemovedService, s.Name()),
 	}
 	return ops
 }
@@ -307,6 +317,60 @@ func (s *Service) setExposed(exposed bool) (err error) {
 	return nil
 }
 
+// StatusAggregation returns the policy used to derive the service's
+// status from its units' statuses.
+func (s *Service) StatusAggregation() StatusAggregation {
+	if s.doc.StatusAggregation == "" {
+		return StatusAggregationWorstOf
+	}
+	return s.doc.StatusAggregation
+}
+
+// SetStatusAggregation sets the policy used to derive the service's
+// status from its units' statuses.
+func (s *Service) SetStatusAggregation(policy StatusAggregation) error {
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"status-aggregation", policy}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errNotAlive), "cannot set status aggregation for service %q", s)
+	}
+	s.doc.StatusAggregation = policy
+	return nil
+}
+
+// HookTimeout returns the time after which the service's running hooks
+// are killed. Zero means the model's hook-timeout applies.
+func (s *Service) HookTimeout() time.Duration {
+	return s.doc.HookTimeout
+}
+
+// SetHookTimeout sets the time after which the service's running hooks
+// are killed, overriding the model's hook-timeout. Setting it to zero
+// removes the override.
+func (s *Service) SetHookTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return errors.NotValidf("negative hook timeout %v", timeout)
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"hook-timeout", timeout}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errNotAlive), "cannot set hook timeout for service %q", s)
+	}
+	s.doc.HookTimeout = timeout
+	return nil
+}
+
 // Charm returns the service's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (s *Service) Charm() (ch *Charm, force bool, err error) {
@@ -886,10 +950,15 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 	if err != nil {
 		return "", nil, err
 	}
+	sharedStorage, err := s.st.sharedStorageInstances(s.ServiceTag())
+	if err != nil {
+		return "", nil, err
+	}
 	args := serviceAddUnitOpsArgs{
 		cons:          cons,
 		principalName: principalName,
 		storageCons:   storageCons,
+		sharedStorage: sharedStorage,
 	}
 	names, ops, err := s.addUnitOpsWithCons(args)
 	if err != nil {
@@ -905,6 +974,10 @@ type serviceAddUnitOpsArgs struct {
 	principalName string
 	cons          constraints.Value
 	storageCons   map[string]StorageConstraints
+
+	// sharedStorage holds the tags of the service's shared
+	// storage instances, to which the new unit will be attached.
+	sharedStorage []names.StorageTag
 }
 
 // addServiceUnitOps is just like addUnitOps but explicitly takes a
@@ -930,7 +1003,7 @@ func (s *Service) addUnitOpsWithCons(args serviceAddUnitOpsArgs) (string, []txn.
 	}
 
 	// Create instances of the charm's declared stores.
-	storageOps, numStorageAttachments, err := s.unitStorageOps(name, args.storageCons)
+	storageOps, numStorageAttachments, err := s.unitStorageOps(name, args.storageCons, args.sharedStorage)
 	if err != nil {
 		return "", nil, errors.Trace(err)
 	}
@@ -1005,10 +1078,16 @@ func (s *Service) incUnitCountOp(asserts bson.D) txn.Op {
 }
 
 // unitStorageOps returns operations for creating storage
-// instances and attachments for a new unit. unitStorageOps
-// returns the number of initial storage attachments, to
-// initialise the unit's storage attachment refcount.
-func (s *Service) unitStorageOps(unitName string, cons map[string]StorageConstraints) (ops []txn.Op, numStorageAttachments int, err error) {
+// instances and attachments for a new unit, including
+// attachments to the service's shared storage instances.
+// unitStorageOps returns the number of initial storage
+// attachments, to initialise the unit's storage attachment
+// refcount.
+func (s *Service) unitStorageOps(
+	unitName string,
+	cons map[string]StorageConstraints,
+	sharedStorage []names.StorageTag,
+) (ops []txn.Op, numStorageAttachments int, err error) {
 	charm, _, err := s.Charm()
 	if err != nil {
 		return nil, -1, err
@@ -1025,6 +1104,8 @@ func (s *Service) unitStorageOps(unitName string, cons map[string]StorageConstra
 	if err != nil {
 		return nil, -1, errors.Trace(err)
 	}
+	ops = append(ops, sharedStorageAttachmentOps(tag, sharedStorage)...)
+	numStorageAttachments += len(sharedStorage)
 	return ops, numStorageAttachments, nil
 }
 
@@ -1082,6 +1163,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, resOps...)
+	secretOps, err := revokeSecretGrantsOps(s.st, u.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretOps...)
 
 	observedFieldsMatch := bson.D{
 		{"charmurl", u.doc.CharmURL},
@@ -1097,6 +1183,10 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeMeterStatusOp(s.st, u.globalMeterStatusKey()),
 		removeStatusOp(s.st, u.globalAgentKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeStatusOp(s.st, u.globalWorkloadVersionKey()),
+		removeStatusOp(s.st, u.globalHealthKey()),
+		removeUnitStateOp(s.st, u.globalKey()),
+		removeHookExecutionsOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
@@ -1594,23 +1684,39 @@ func (s *Service) ServiceAndUnitsStatus() (status.StatusInfo, map[string]status.
 
 }
 
+// deriveStatus derives the service status from the given units'
+// statuses according to the service's status aggregation policy.
 func (s *Service) deriveStatus(units []*Unit) (status.StatusInfo, error) {
-	var result status.StatusInfo
-	for _, unit := range units {
-		currentSeverity := statusServerities[result.Status]
+	policy := s.StatusAggregation()
+	if policy == StatusAggregationLeaderOnly {
+		leader := s.st.leadershipClient.Leases()[s.Name()].Holder
+		for _, unit := range units {
+			if unit.Name() != leader {
+				continue
+			}
+			unitStatus, err := unit.Status()
+			if err != nil {
+				return status.StatusInfo{}, errors.Annotatef(err, "deriving service status from %q", unit.Name())
+			}
+			return unitStatus, nil
+		}
+		// Without a leader, fall back to the default policy rather
+		// than report nothing at all.
+		logger.Debugf("service %q has no leader; deriving status from all units", s.Name())
+		policy = StatusAggregationWorstOf
+	}
+	statuses := make([]status.StatusInfo, len(units))
+	for i, unit := range units {
 		unitStatus, err := unit.Status()
 		if err != nil {
 			return status.StatusInfo{}, errors.Annotatef(err, "deriving service status from %q", unit.Name())
 		}
-		unitSeverity := statusServerities[unitStatus.Status]
-		if unitSeverity > currentSeverity {
-			result.Status = unitStatus.Status
-			result.Message = unitStatus.Message
-			result.Data = unitStatus.Data
-			result.Since = unitStatus.Since
-		}
+		statuses[i] = unitStatus
 	}
-	return result, nil
+	if policy == StatusAggregationMajority {
+		return majorityStatus(statuses), nil
+	}
+	return worstStatus(statuses), nil
 }
 
 // statusSeverities holds status values with a severity measure.
@@ -96,6 +96,12 @@ type State struct {
 	allModelManager        *storeManager
 	allModelWatcherBacking Backing
 
+	// secretsKey holds the key with which secret values are
+	// encrypted. It is supplied by the controller agent, never
+	// stored in the database, and shared with States returned by
+	// ForModel.
+	secretsKey *secretsKeyHolder
+
 	// TODO(anastasiamac 2015-07-16) As state gets broken up, remove this.
 	CloudImageMetadataStorage cloudimagemetadata.Storage
 }
@@ -135,6 +141,16 @@ func (st *State) RemoveImportingModelDocs() error {
 	return st.removeAllModelDocs(bson.D{{"migration-mode", MigrationModeImporting}})
 }
 
+// RemoveRestoringModelDocs removes all documents from multi-model
+// collections for the current model, so that it can be recreated from
+// a model backup. The controller model cannot be removed this way.
+func (st *State) RemoveRestoringModelDocs() error {
+	if st.IsController() {
+		return errors.New("cannot replace the controller model")
+	}
+	return st.removeAllModelDocs(bson.D{{"life", Alive}})
+}
+
 func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 	env, err := st.Model()
 	if err != nil {
@@ -201,6 +217,7 @@ func (st *State) ForModel(env names.ModelTag) (*State, error) {
 	if err := newState.start(st.controllerTag); err != nil {
 		return nil, errors.Trace(err)
 	}
+	newState.secretsKey = st.secretsKey
 	return newState, nil
 }
 
@@ -507,43 +524,8 @@ func (st *State) SetModelAgentVersion(newVersion version.Number) (err error) {
 	}
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		settings, err := readSettings(st, modelGlobalKey)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		agentVersion, ok := settings.Get("agent-version")
-		if !ok {
-			return nil, errors.Errorf("no agent version set in the model")
-		}
-		currentVersion, ok := agentVersion.(string)
-		if !ok {
-			return nil, errors.Errorf("invalid agent version format: expected string, got %v", agentVersion)
-		}
-		if newVersion.String() == currentVersion {
-			// Nothing to do.
-			return nil, jujutxn.ErrNoOperations
-		}
-
-		if err := st.checkCanUpgrade(currentVersion, newVersion.String()); err != nil {
-			return nil, errors.Trace(err)
-		}
-
-		ops := []txn.Op{
-			// Can't set agent-version if there's an active upgradeInfo doc.
-			{
-				C:      upgradeInfoC,
-				Id:     currentUpgradeId,
-				Assert: txn.DocMissing,
-			}, {
-				C:      settingsC,
-				Id:     st.docID(modelGlobalKey),
-				Assert: bson.D{{"version", settings.version}},
-				Update: bson.D{
-					{"$set", bson.D{{"settings.agent-version", newVersion.String()}}},
-				},
-			},
-		}
-		return ops, nil
+		ops, _, err := st.setAgentVersionOps(newVersion)
+		return ops, err
 	}
 	if err = st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
 		// Although there is a small chance of a race here, try to
@@ -558,6 +540,53 @@ func (st *State) SetModelAgentVersion(newVersion version.Number) (err error) {
 	return errors.Trace(err)
 }
 
+// setAgentVersionOps returns the operations needed to set the model's
+// agent version, along with the version it replaces. It returns
+// jujutxn.ErrNoOperations if the version is unchanged.
+func (st *State) setAgentVersionOps(newVersion version.Number) ([]txn.Op, version.Number, error) {
+	settings, err := readSettings(st, modelGlobalKey)
+	if err != nil {
+		return nil, version.Number{}, errors.Trace(err)
+	}
+	agentVersion, ok := settings.Get("agent-version")
+	if !ok {
+		return nil, version.Number{}, errors.Errorf("no agent version set in the model")
+	}
+	currentVersion, ok := agentVersion.(string)
+	if !ok {
+		return nil, version.Number{}, errors.Errorf("invalid agent version format: expected string, got %v", agentVersion)
+	}
+	if newVersion.String() == currentVersion {
+		// Nothing to do.
+		return nil, newVersion, jujutxn.ErrNoOperations
+	}
+
+	if err := st.checkCanUpgrade(currentVersion, newVersion.String()); err != nil {
+		return nil, version.Number{}, errors.Trace(err)
+	}
+	previous, err := version.Parse(currentVersion)
+	if err != nil {
+		return nil, version.Number{}, errors.Trace(err)
+	}
+
+	ops := []txn.Op{
+		// Can't set agent-version if there's an active upgradeInfo doc.
+		{
+			C:      upgradeInfoC,
+			Id:     currentUpgradeId,
+			Assert: txn.DocMissing,
+		}, {
+			C:      settingsC,
+			Id:     st.docID(modelGlobalKey),
+			Assert: bson.D{{"version", settings.version}},
+			Update: bson.D{
+				{"$set", bson.D{{"settings.agent-version", newVersion.String()}}},
+			},
+		},
+	}
+	return ops, previous, nil
+}
+
 func (st *State) buildAndValidateModelConfig(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) (validCfg *config.Config, err error) {
 	newConfig, err := oldConfig.Apply(updateAttrs)
 	if err != nil {
@@ -1378,6 +1407,15 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 	}
 	ops = append(ops, peerOps...)
 
+	// Collect shared storage creation operations.
+	sharedStorageOps, sharedStorage, err := createSharedStorageOps(
+		st, svc.ServiceTag(), args.Charm.Meta(), args.Charm.URL(), args.Storage,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, sharedStorageOps...)
+
 	if len(args.Resources) > 0 {
 		// Collect pending resource resolution operations.
 		resources, err := st.Resources()
@@ -1393,7 +1431,11 @@ func (st *State) AddService(args AddServiceArgs) (service *Service, err error) {
 
 	// Collect unit-adding operations.
 	for x := 0; x < args.NumUnits; x++ {
-		unitName, unitOps, err := svc.addServiceUnitOps(serviceAddUnitOpsArgs{cons: args.Constraints, storageCons: args.Storage})
+		unitName, unitOps, err := svc.addServiceUnitOps(serviceAddUnitOpsArgs{
+			cons:          args.Constraints,
+			storageCons:   args.Storage,
+			sharedStorage: sharedStorage,
+		})
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
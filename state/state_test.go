@@ -2997,6 +2997,25 @@ func (s *StateSuite) TestRemoveImportingModelDocsImporting(c *gc.C) {
 	c.Assert(state.HostedModelCount(c, s.State), gc.Equals, 0)
 }
 
+func (s *StateSuite) TestRemoveRestoringModelDocs(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	userModelKey := s.insertFakeModelDocs(c, st)
+	c.Assert(state.HostedModelCount(c, s.State), gc.Equals, 1)
+
+	err := st.RemoveRestoringModelDocs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkUserModelNameExists(c, checkUserModelNameArgs{st: st, id: userModelKey, exists: false})
+	s.AssertModelDeleted(c, st)
+	c.Assert(state.HostedModelCount(c, s.State), gc.Equals, 0)
+}
+
+func (s *StateSuite) TestRemoveRestoringModelDocsControllerModel(c *gc.C) {
+	err := s.State.RemoveRestoringModelDocs()
+	c.Assert(err, gc.ErrorMatches, "cannot replace the controller model")
+}
+
 type attrs map[string]interface{}
 
 func (s *StateSuite) TestWatchModelConfig(c *gc.C) {
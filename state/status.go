@@ -310,3 +310,64 @@ func getEntitiesWithStatuses(coll mongo.Collection) ([]string, error) {
 	}
 	return globalKeys, nil
 }
+
+// StatusAggregation describes how a service's status is derived from
+// the workload statuses of its units, when the service's leader has
+// not set the service status explicitly.
+type StatusAggregation string
+
+const (
+	// StatusAggregationWorstOf uses the most severe unit status. This
+	// is the default.
+	StatusAggregationWorstOf StatusAggregation = "worst-of"
+
+	// StatusAggregationMajority uses the status shared by most units,
+	// preferring the more severe status in case of a tie.
+	StatusAggregationMajority StatusAggregation = "majority"
+
+	// StatusAggregationLeaderOnly uses the status of the leader unit.
+	StatusAggregationLeaderOnly StatusAggregation = "leader-only"
+)
+
+// Validate returns an error if the aggregation policy is not known.
+func (a StatusAggregation) Validate() error {
+	switch a {
+	case StatusAggregationWorstOf, StatusAggregationMajority, StatusAggregationLeaderOnly:
+		return nil
+	}
+	return errors.NotValidf("status aggregation %q", a)
+}
+
+// worstStatus returns the most severe of the given statuses. When
+// several share the highest severity, the first of them is returned.
+func worstStatus(statuses []status.StatusInfo) status.StatusInfo {
+	var result status.StatusInfo
+	for _, info := range statuses {
+		if statusServerities[info.Status] > statusServerities[result.Status] {
+			result = info
+		}
+	}
+	return result
+}
+
+// majorityStatus returns the status shared by the most of the given
+// statuses, using the message and data of the first of them. Ties are
+// broken in favour of the more severe status.
+func majorityStatus(statuses []status.StatusInfo) status.StatusInfo {
+	counts := make(map[status.Status]int)
+	first := make(map[status.Status]status.StatusInfo)
+	for _, info := range statuses {
+		if counts[info.Status] == 0 {
+			first[info.Status] = info
+		}
+		counts[info.Status]++
+	}
+	var result status.StatusInfo
+	for current, count := range counts {
+		best := counts[result.Status]
+		if count > best || count == best && statusServerities[current] > statusServerities[result.Status] {
+			result = first[current]
+		}
+	}
+	return result
+}
@@ -4,8 +4,10 @@
 package state_test
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -180,3 +182,81 @@ func (s *ServiceStatusSuite) TestServiceStatusOverridesDerivedStatus(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(info.Status, gc.Equals, status.StatusMaintenance)
 }
+
+func (s *ServiceStatusSuite) TestStatusAggregation(c *gc.C) {
+	c.Assert(s.service.StatusAggregation(), gc.Equals, state.StatusAggregationWorstOf)
+
+	err := s.service.SetStatusAggregation(state.StatusAggregationMajority)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.StatusAggregation(), gc.Equals, state.StatusAggregationMajority)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.StatusAggregation(), gc.Equals, state.StatusAggregationMajority)
+
+	err = s.service.SetStatusAggregation("best-of")
+	c.Assert(err, gc.ErrorMatches, `status aggregation "best-of" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ServiceStatusSuite) addUnitsWithStatus(c *gc.C, statuses ...status.Status) []*state.Unit {
+	units := make([]*state.Unit, len(statuses))
+	for i, unitStatus := range statuses {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetStatus(unitStatus, fmt.Sprintf("unit %d", i), nil)
+		c.Assert(err, jc.ErrorIsNil)
+		units[i] = unit
+	}
+	return units
+}
+
+func (s *ServiceStatusSuite) TestDeriveStatusMajority(c *gc.C) {
+	err := s.service.SetStatusAggregation(state.StatusAggregationMajority)
+	c.Assert(err, jc.ErrorIsNil)
+	units := s.addUnitsWithStatus(c,
+		status.StatusActive, status.StatusBlocked, status.StatusActive,
+	)
+
+	info, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status, gc.Equals, status.StatusActive)
+	c.Check(info.Message, gc.Equals, "unit 0")
+
+	// A tie goes to the more severe status.
+	err = units[2].SetStatus(status.StatusMaintenance, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status, gc.Equals, status.StatusBlocked)
+	c.Check(info.Message, gc.Equals, "unit 1")
+}
+
+func (s *ServiceStatusSuite) TestDeriveStatusLeaderOnly(c *gc.C) {
+	err := s.service.SetStatusAggregation(state.StatusAggregationLeaderOnly)
+	c.Assert(err, jc.ErrorIsNil)
+	units := s.addUnitsWithStatus(c, status.StatusBlocked, status.StatusActive)
+
+	// With no leader, the worst status is used.
+	info, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status, gc.Equals, status.StatusBlocked)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership(s.service.Name(), units[1].Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status, gc.Equals, status.StatusActive)
+	c.Check(info.Message, gc.Equals, "unit 1")
+}
+
+func (s *ServiceStatusSuite) TestServiceStatusOverridesAggregation(c *gc.C) {
+	err := s.service.SetStatusAggregation(state.StatusAggregationMajority)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnitsWithStatus(c, status.StatusActive)
+	err = s.service.SetStatus(status.StatusMaintenance, "zot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.service.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status, gc.Equals, status.StatusMaintenance)
+}
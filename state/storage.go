@@ -433,16 +433,129 @@ func createStorageOps(
 		}
 	}
 
-	// TODO(axw) create storage attachments for each shared storage
-	// instance owned by the service.
-	//
-	// TODO(axw) prevent creation of shared storage after service
-	// creation, because the only sane time to add storage attachments
-	// is when units are added to said service.
-
 	return ops, numStorageAttachments, nil
 }
 
+// createSharedStorageOps returns txn.Ops for creating the shared storage
+// instances for a new service, along with the filesystems that back them.
+// The tags of the new storage instances are returned so that units created
+// in the same transaction can be attached to them.
+//
+// Shared storage is only created along with the service, since the only
+// sane time to add storage attachments is when units are added to it.
+func createSharedStorageOps(
+	st *State,
+	service names.ServiceTag,
+	charmMeta *charm.Meta,
+	curl *charm.URL,
+	cons map[string]StorageConstraints,
+) ([]txn.Op, []names.StorageTag, error) {
+	storageNames := set.NewStrings()
+	for name, charmStorage := range charmMeta.Storage {
+		if charmStorage.Shared {
+			storageNames.Add(name)
+		}
+	}
+	var ops []txn.Op
+	var storageTags []names.StorageTag
+	for _, store := range storageNames.SortedValues() {
+		cons, ok := cons[store]
+		if !ok {
+			return nil, nil, errors.NotFoundf("constraints for shared storage %q", store)
+		}
+		if kind := storageKind(charmMeta.Storage[store].Type); kind != storage.StorageKindFilesystem {
+			return nil, nil, errors.NotSupportedf("shared %s storage", kind)
+		}
+		for i := uint64(0); i < cons.Count; i++ {
+			id, err := newStorageInstanceId(st, store)
+			if err != nil {
+				return nil, nil, errors.Annotate(err, "cannot generate storage instance name")
+			}
+			storageTag := names.NewStorageTag(id)
+			ops = append(ops, txn.Op{
+				C:      storageInstancesC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &storageInstanceDoc{
+					Id:          id,
+					Kind:        StorageKindFilesystem,
+					Owner:       service.String(),
+					StorageName: store,
+					CharmURL:    curl,
+				},
+			})
+			// Shared filesystems are not scoped to any machine;
+			// they are attached to the machine of each unit as
+			// the unit is assigned.
+			filesystemOps, _, _, err := st.addFilesystemOps(FilesystemParams{
+				storage: storageTag,
+				binding: storageTag,
+				shared:  true,
+				Pool:    cons.Pool,
+				Size:    cons.Size,
+			}, "")
+			if err != nil {
+				return nil, nil, errors.Annotatef(err, "creating filesystem for storage %s", id)
+			}
+			ops = append(ops, filesystemOps...)
+			storageTags = append(storageTags, storageTag)
+		}
+	}
+	return ops, storageTags, nil
+}
+
+// sharedStorageAttachmentOps returns txn.Ops for attaching the specified
+// shared storage instances to a new unit.
+func sharedStorageAttachmentOps(unit names.UnitTag, storageTags []names.StorageTag) []txn.Op {
+	ops := make([]txn.Op, 0, len(storageTags)*2)
+	for _, storageTag := range storageTags {
+		ops = append(ops, createStorageAttachmentOp(storageTag, unit), txn.Op{
+			C:      storageInstancesC,
+			Id:     storageTag.Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"attachmentcount", 1}}}},
+		})
+	}
+	return ops
+}
+
+// sharedStorageInstances returns the tags of the alive storage instances
+// owned by the specified service.
+func (st *State) sharedStorageInstances(service names.ServiceTag) ([]names.StorageTag, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	query := bson.D{{"owner", service.String()}, {"life", Alive}}
+	if err := coll.Find(query).Select(bson.D{{"id", true}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instances for %s", service)
+	}
+	storageTags := make([]names.StorageTag, len(docs))
+	for i, doc := range docs {
+		storageTags[i] = names.NewStorageTag(doc.Id)
+	}
+	return storageTags, nil
+}
+
+// destroyEntityStorageInstances destroys all of the storage instances
+// owned by the specified entity.
+func (st *State) destroyEntityStorageInstances(owner names.Tag) error {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	err := coll.Find(bson.D{{"owner", owner.String()}}).Select(bson.D{{"id", true}}).All(&docs)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get storage instances for %s", owner)
+	}
+	for _, doc := range docs {
+		if err := st.DestroyStorageInstance(names.NewStorageTag(doc.Id)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // unitAssignedMachineStorageOps returns ops for creating volumes, filesystems
 // and their attachments to the machine that the specified unit is assigned to,
 // corresponding to the specified storage instance.
@@ -828,13 +941,6 @@ func validateStorageConstraintsAgainstCharm(
 		if !ok {
 			return errors.Errorf("charm %q has no store called %q", charmMeta.Name, name)
 		}
-		if charmStorage.Shared {
-			// TODO(axw) implement shared storage support.
-			return errors.Errorf(
-				"charm %q store %q: shared storage support not implemented",
-				charmMeta.Name, name,
-			)
-		}
 		if cons.Count < uint64(charmStorage.CountMin) {
 			return errors.Errorf(
 				"charm %q store %q: %d instances required, %d specified",
@@ -859,6 +965,31 @@ func validateStorageConstraintsAgainstCharm(
 		if err := validateStoragePool(st, cons.Pool, kind, nil); err != nil {
 			return err
 		}
+		if charmStorage.Shared {
+			if err := validateSharedStoragePool(st, cons.Pool, kind); err != nil {
+				return errors.Annotatef(
+					err, "charm %q store %q", charmMeta.Name, name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSharedStoragePool validates that the storage pool may be used
+// for storage that is shared by all units of a service.
+func validateSharedStoragePool(st *State, poolName string, kind storage.StorageKind) error {
+	if kind != storage.StorageKindFilesystem {
+		return errors.NotSupportedf("shared %s storage", kind)
+	}
+	_, provider, err := poolStorageProvider(st, poolName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !storage.IsSharedFilesystemProvider(provider) {
+		return errors.Errorf(
+			"pool %q does not support shared filesystems", poolName,
+		)
 	}
 	return nil
 }
@@ -1151,6 +1282,13 @@ func (st *State) addStorageForUnit(
 func (st *State) validateUnitStorage(
 	charmMeta *charm.Meta, u *Unit, name string, cons StorageConstraints,
 ) error {
+	if charmMeta.Storage[name].Shared {
+		// Shared storage is owned by the service.
+		return errors.Errorf(
+			"cannot add shared storage %q to unit %s", name, u.Name(),
+		)
+	}
+
 	// Storage directive may provide storage instance count
 	// which combined with existing storage instance may exceed
 	// number of storage instances specified by charm.
@@ -923,3 +923,109 @@ func (s *StorageStateSuite) testStorageLocationConflict(c *gc.C, first, second,
 // - StorageInstance without attachments is removed by Destroy
 // - concurrent add-unit and StorageAttachment removal does not
 //   remove storage instance.
+
+func (s *StorageStateSuite) setupSharedStorageService(c *gc.C, pool string) (*state.Service, error) {
+	ch := s.createStorageCharm(c, "shared-storage", charm.Storage{
+		Name:     "data",
+		Type:     charm.StorageFilesystem,
+		Shared:   true,
+		CountMin: 1,
+		CountMax: 1,
+		Location: "/srv/data",
+	})
+	return s.State.AddService(state.AddServiceArgs{
+		Name:  "shared-storage",
+		Owner: s.Owner.String(),
+		Charm: ch,
+		Storage: map[string]state.StorageConstraints{
+			"data": makeStorageCons(pool, 1024, 1),
+		},
+	})
+}
+
+func (s *StorageStateSuite) createNFSPool(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("nfs-pool", provider.NFSProviderType, map[string]interface{}{
+		"server": "nas",
+		"export": "/srv/exports/data",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	registry.RegisterEnvironStorageProviders("someprovider", provider.NFSProviderType)
+}
+
+func (s *StorageStateSuite) TestAddServiceSharedStorageUnsupportedPool(c *gc.C) {
+	_, err := s.setupSharedStorageService(c, "environscoped")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "shared-storage": `+
+		`charm "shared-storage" store "data": pool "environscoped" does not support shared filesystems`)
+}
+
+func (s *StorageStateSuite) TestAddServiceSharedStorage(c *gc.C) {
+	s.createNFSPool(c)
+	service, err := s.setupSharedStorageService(c, "nfs-pool")
+	c.Assert(err, jc.ErrorIsNil)
+
+	all, err := s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	storageTag := all[0].StorageTag()
+	c.Assert(all[0].Owner(), gc.Equals, service.Tag())
+	c.Assert(all[0].Kind(), gc.Equals, state.StorageKindFilesystem)
+
+	// The shared filesystem is model-scoped, and has
+	// no attachments until units are assigned.
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	_, ok := names.FilesystemMachine(filesystem.FilesystemTag())
+	c.Assert(ok, jc.IsFalse)
+	attachments, err := s.State.FilesystemAttachments(filesystem.FilesystemTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, gc.HasLen, 0)
+}
+
+func (s *StorageStateSuite) TestAddUnitSharedStorage(c *gc.C) {
+	s.createNFSPool(c)
+	service, err := s.setupSharedStorageService(c, "nfs-pool")
+	c.Assert(err, jc.ErrorIsNil)
+	all, err := s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	storageTag := all[0].StorageTag()
+	filesystemTag := s.storageInstanceFilesystem(c, storageTag).FilesystemTag()
+
+	var machines []names.MachineTag
+	for i := 0; i < 2; i++ {
+		u, err := service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		attachments, err := s.State.UnitStorageAttachments(u.UnitTag())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(attachments, gc.HasLen, 1)
+		c.Assert(attachments[0].StorageInstance(), gc.Equals, storageTag)
+
+		err = s.State.AssignUnit(u, state.AssignNew)
+		c.Assert(err, jc.ErrorIsNil)
+		machineId, err := u.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, names.NewMachineTag(machineId))
+	}
+
+	// No additional storage instances are created for the
+	// units; they all share the service's storage instance.
+	all, err = s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+
+	for _, m := range machines {
+		s.assertFilesystemAttachmentUnprovisioned(c, m, filesystemTag)
+		assertMachineStorageRefs(c, s.State, m)
+	}
+}
+
+func (s *StorageStateSuite) TestAddStorageForUnitSharedStorage(c *gc.C) {
+	s.createNFSPool(c)
+	service, err := s.setupSharedStorageService(c, "nfs-pool")
+	c.Assert(err, jc.ErrorIsNil)
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AddStorageForUnit(u.UnitTag(), "data", makeStorageCons("nfs-pool", 1024, 1))
+	c.Assert(err, gc.ErrorMatches, `adding storage to unit shared-storage/0: cannot add shared storage "data" to unit shared-storage/0`)
+}
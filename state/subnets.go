@@ -191,6 +191,43 @@ func (s *Subnet) SpaceName() string {
 	return s.doc.SpaceName
 }
 
+// SetSpaceName associates the subnet with the given space, replacing
+// any previous association. The space must exist and be alive.
+func (s *Subnet) SetSpaceName(spaceName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set space of subnet %q to %q", s, spaceName)
+
+	ops := []txn.Op{{
+		C:      subnetsC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"space-name", spaceName}}}},
+	}, {
+		C:      spacesC,
+		Id:     s.st.docID(spaceName),
+		Assert: isAliveDoc,
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return errNotAlive
+		}
+		space, err := s.st.Space(spaceName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if space.Life() != Alive {
+			return errors.Errorf("space %q is not alive", spaceName)
+		}
+		return errors.Trace(txn.ErrAborted)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.SpaceName = spaceName
+	return nil
+}
+
 // Validate validates the subnet, checking the CIDR, VLANTag and
 // AllocatableIPHigh and Low, if present.
 func (s *Subnet) Validate() error {
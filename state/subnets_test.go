@@ -246,6 +246,38 @@ func (s *SubnetSuite) TestEnsureDeadSetsLifeToDeadWhenNotAlive(c *gc.C) {
 	s.ensureDeadAndAssertLifeIsDead(c, subnet)
 }
 
+func (s *SubnetSuite) TestSetSpaceName(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.0/24")
+	_, err := s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = subnet.SetSpaceName("dmz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "dmz")
+
+	err = subnet.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "dmz")
+}
+
+func (s *SubnetSuite) TestSetSpaceNameFailsForUnknownSpace(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.0/24")
+
+	err := subnet.SetSpaceName("missing")
+	c.Assert(err, gc.ErrorMatches, `cannot set space of subnet "192.168.0.0/24" to "missing": space "missing" not found`)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SubnetSuite) TestSetSpaceNameFailsWhenNotAlive(c *gc.C) {
+	subnet := s.addAliveSubnet(c, "192.168.0.0/24")
+	_, err := s.State.AddSpace("dmz", "", nil, true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.ensureDeadAndAssertLifeIsDead(c, subnet)
+
+	err = subnet.SetSpaceName("dmz")
+	c.Assert(err, gc.ErrorMatches, `cannot set space of subnet "192.168.0.0/24" to "dmz": not found or not alive`)
+}
+
 func (s *SubnetSuite) TestRemoveFailsIfStillAlive(c *gc.C) {
 	subnet := s.addAliveSubnet(c, "192.168.0.1/24")
 
@@ -27,6 +27,8 @@ func Initialize(c *gc.C, owner names.UserTag, cfg *config.Config, policy state.P
 
 	st, err := state.Initialize(owner, mgoInfo, cfg, dialOpts, policy)
 	c.Assert(err, jc.ErrorIsNil)
+	err = st.SetSecretsKey(testing.SecretsKey)
+	c.Assert(err, jc.ErrorIsNil)
 	return st
 }
 
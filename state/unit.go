@@ -165,6 +165,30 @@ func unitGlobalKey(name string) string {
 	return "u#" + name + "#charm"
 }
 
+// unitWorkloadVersionGlobalKey returns the global database key for the
+// workload version of the named unit.
+func unitWorkloadVersionGlobalKey(name string) string {
+	return "u#" + name + "#sat#workload-version"
+}
+
+// globalWorkloadVersionKey returns the global database key for the
+// workload version of the unit.
+func (u *Unit) globalWorkloadVersionKey() string {
+	return unitWorkloadVersionGlobalKey(u.doc.Name)
+}
+
+// unitHealthGlobalKey returns the global database key for the health
+// of the named unit.
+func unitHealthGlobalKey(name string) string {
+	return "u#" + name + "#sat#health"
+}
+
+// globalHealthKey returns the global database key for the health of
+// the unit.
+func (u *Unit) globalHealthKey() string {
+	return unitHealthGlobalKey(u.doc.Name)
+}
+
 // globalAgentKey returns the global database key for the unit.
 func (u *Unit) globalAgentKey() string {
 	return unitAgentGlobalKey(u.doc.Name)
@@ -832,6 +856,131 @@ func (u *Unit) SetStatus(unitStatus status.Status, info string, data map[string]
 	})
 }
 
+// WorkloadVersion returns the version of the workload the unit is
+// running, as set by its charm, or "" if it has not been set.
+func (u *Unit) WorkloadVersion() (string, error) {
+	info, err := getStatus(u.st, u.globalWorkloadVersionKey(), "workload version")
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.Message, nil
+}
+
+// SetWorkloadVersion records the version of the workload the unit is
+// running, as opposed to the version of its charm. The version is
+// stored as the message of a status, so that changes to it are
+// recorded in the status history.
+func (u *Unit) SetWorkloadVersion(version string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set workload version for unit %q", u)
+	globalKey := u.globalWorkloadVersionKey()
+	doc := statusDoc{
+		Status:     status.StatusActive,
+		StatusInfo: version,
+		Updated:    time.Now().UnixNano(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		txnRevno, err := u.st.readTxnRevno(statusesC, globalKey)
+		if errors.Cause(err) == mgo.ErrNotFound {
+			return append(ops, createStatusOp(u.st, globalKey, doc)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      statusesC,
+			Id:     globalKey,
+			Assert: bson.D{{"txn-revno", txnRevno}},
+			Update: bson.D{{"$set", &doc}},
+		}), nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	probablyUpdateStatusHistory(u.st, globalKey, doc)
+	return nil
+}
+
+// WorkloadVersionHistory returns the most recent workload versions set
+// for the unit, newest first, up to size entries.
+func (u *Unit) WorkloadVersionHistory(size int) ([]status.StatusInfo, error) {
+	return statusHistory(u.st, u.globalWorkloadVersionKey(), size)
+}
+
+// Health returns the health of the unit, as evaluated by its agent
+// against the health checks declared by its charm. If no health has
+// been reported, the returned status is empty.
+func (u *Unit) Health() (status.StatusInfo, error) {
+	info, err := getStatus(u.st, u.globalHealthKey(), "unit health")
+	if errors.IsNotFound(err) {
+		return status.StatusInfo{}, nil
+	} else if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// SetHealth records the health of the unit. Health is managed by the
+// unit agent, independently of the workload status set by the charm.
+func (u *Unit) SetHealth(healthStatus status.Status, info string, data map[string]interface{}) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set health for unit %q", u)
+	if !status.ValidHealthStatus(healthStatus) {
+		return errors.Errorf("invalid health status %q", healthStatus)
+	}
+	globalKey := u.globalHealthKey()
+	doc := statusDoc{
+		Status:     healthStatus,
+		StatusInfo: info,
+		StatusData: escapeKeys(data),
+		Updated:    time.Now().UnixNano(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		txnRevno, err := u.st.readTxnRevno(statusesC, globalKey)
+		if errors.Cause(err) == mgo.ErrNotFound {
+			return append(ops, createStatusOp(u.st, globalKey, doc)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      statusesC,
+			Id:     globalKey,
+			Assert: bson.D{{"txn-revno", txnRevno}},
+			Update: bson.D{{"$set", &doc}},
+		}), nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	probablyUpdateStatusHistory(u.st, globalKey, doc)
+	return nil
+}
+
 // OpenPortsOnSubnet opens the given port range and protocol for the unit on the
 // given subnet, which can be empty. When non-empty, subnetID must refer to an
 // existing, alive subnet, otherwise an error is returned. Returns an error if
@@ -1220,6 +1369,9 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := u.validateMachineSpaces(m); err != nil {
+		return nil, errors.Trace(err)
+	}
 	storageOps, volumesAttached, filesystemsAttached, err := u.st.machineStorageOps(
 		&m.doc, storageParams,
 	)
@@ -2010,6 +2162,10 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
+		if errors.IsNotValid(err) {
+			// The machine is not in the spaces the unit requires.
+			continue
+		}
 		switch errors.Cause(err) {
 		case inUseErr, machineNotAliveErr:
 		default:
@@ -107,6 +107,103 @@ func (s *UnitSuite) TestConfigSettingsReflectCharm(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{})
 }
 
+func (s *UnitSuite) TestWorkloadVersion(c *gc.C) {
+	version, err := s.unit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "")
+
+	err = s.unit.SetWorkloadVersion("9.4.1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadVersion("9.5.3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	version, err = unit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "9.5.3")
+
+	history, err := unit.WorkloadVersionHistory(10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Message, gc.Equals, "9.5.3")
+	c.Assert(history[1].Message, gc.Equals, "9.4.1")
+}
+
+func (s *UnitSuite) TestSetWorkloadVersionDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadVersion("9.4.1")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload version for unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitSuite) TestRemoveUnitRemovesWorkloadVersion(c *gc.C) {
+	err := s.unit.SetWorkloadVersion("9.4.1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The workload version is removed along with the unit.
+	statuses, closer := state.GetRawCollection(s.State, "statuses")
+	defer closer()
+	n, err := statuses.FindId(s.State.ModelUUID() + ":u#wordpress/0#sat#workload-version").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+}
+
+func (s *UnitSuite) TestHealth(c *gc.C) {
+	health, err := s.unit.Health()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, jc.DeepEquals, status.StatusInfo{})
+
+	err = s.unit.SetHealth(status.StatusHealthy, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetHealth(status.StatusUnhealthy, "http: connection refused", map[string]interface{}{
+		"failing": []interface{}{"http"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	health, err = unit.Health()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health.Status, gc.Equals, status.StatusUnhealthy)
+	c.Assert(health.Message, gc.Equals, "http: connection refused")
+	c.Assert(health.Data, jc.DeepEquals, map[string]interface{}{
+		"failing": []interface{}{"http"},
+	})
+	c.Assert(health.Since, gc.NotNil)
+}
+
+func (s *UnitSuite) TestSetHealthInvalid(c *gc.C) {
+	err := s.unit.SetHealth(status.StatusActive, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set health for unit "wordpress/0": invalid health status "active"`)
+}
+
+func (s *UnitSuite) TestSetHealthDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetHealth(status.StatusHealthy, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set health for unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitSuite) TestRemoveUnitRemovesHealth(c *gc.C) {
+	err := s.unit.SetHealth(status.StatusHealthy, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	statuses, closer := state.GetRawCollection(s.State, "statuses")
+	defer closer()
+	n, err := statuses.FindId(s.State.ModelUUID() + ":u#wordpress/0#sat#health").Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+}
+
 func (s *UnitSuite) TestWatchConfigSettingsNeedsCharmURL(c *gc.C) {
 	_, err := s.unit.WatchConfigSettings()
 	c.Assert(err, gc.ErrorMatches, "unit charm not set")
@@ -0,0 +1,155 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/charmstate"
+)
+
+const (
+	// MaxCharmStateKeySize is the maximum size, in bytes, of a key in
+	// a unit's charm state.
+	MaxCharmStateKeySize = charmstate.MaxKeySize
+
+	// MaxCharmStateSize is the maximum total size, in bytes, of the
+	// keys and values in a unit's charm state.
+	MaxCharmStateSize = charmstate.MaxSize
+)
+
+// unitStateDoc holds the key/value data a unit's charm persists
+// between hooks. Keys are escaped, as for settings.
+type unitStateDoc struct {
+	ModelUUID  string            `bson:"model-uuid"`
+	CharmState map[string]string `bson:"charm-state"`
+}
+
+// CharmState returns the key/value data the unit's charm has stored
+// with the state-set hook tool. It is empty if nothing has been stored.
+func (u *Unit) CharmState() (map[string]string, error) {
+	unitStates, closer := u.st.getCollection(unitStatesC)
+	defer closer()
+
+	var doc unitStateDoc
+	err := unitStates.FindId(u.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get charm state for unit %q", u)
+	}
+	charmState := make(map[string]string, len(doc.CharmState))
+	for key, value := range doc.CharmState {
+		charmState[unescapeReplacer.Replace(key)] = value
+	}
+	return charmState, nil
+}
+
+// SetCharmState replaces the key/value data stored for the unit's
+// charm. The keys and values must fit within MaxCharmStateKeySize and
+// MaxCharmStateSize.
+func (u *Unit) SetCharmState(charmState map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set charm state for unit %q", u)
+	if charmState == nil {
+		charmState = map[string]string{}
+	}
+	return errors.Trace(u.commitHookChanges(nil, charmState))
+}
+
+// CommitHookChanges writes the changes made by one of the unit's hooks
+// in a single transaction, so that either all of them are applied or
+// none are. Each of the supplied relation settings, which must be the
+// unit's own settings in relations it is in scope of, is written, and
+// the unit's charm state is replaced with charmState unless it is nil.
+func (u *Unit) CommitHookChanges(relationSettings []*Settings, charmState map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot commit hook changes for unit %q", u)
+	return errors.Trace(u.commitHookChanges(relationSettings, charmState))
+}
+
+func (u *Unit) commitHookChanges(relationSettings []*Settings, charmState map[string]string) error {
+	if charmState != nil {
+		if err := charmstate.Validate(charmState); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		for _, settings := range relationSettings {
+			if attempt > 0 {
+				if _, err := readSettingsDoc(u.st, settings.key); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			_, settingsOps := settings.writeOps()
+			ops = append(ops, settingsOps...)
+		}
+		if charmState != nil {
+			op, err := u.charmStateOp(charmState)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, op)
+		}
+		return ops, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	for _, settings := range relationSettings {
+		settings.disk = copyMap(settings.core, nil)
+	}
+	return nil
+}
+
+// charmStateOp returns the operation needed to replace the unit's
+// charm state with the supplied data.
+func (u *Unit) charmStateOp(charmState map[string]string) (txn.Op, error) {
+	escaped := make(map[string]string, len(charmState))
+	for key, value := range charmState {
+		escaped[escapeReplacer.Replace(key)] = value
+	}
+	docID := u.st.docID(u.globalKey())
+	txnRevno, err := u.st.readTxnRevno(unitStatesC, docID)
+	if errors.Cause(err) == mgo.ErrNotFound {
+		return txn.Op{
+			C:      unitStatesC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &unitStateDoc{CharmState: escaped},
+		}, nil
+	} else if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     docID,
+		Assert: bson.D{{"txn-revno", txnRevno}},
+		Update: bson.D{{"$set", bson.D{{"charm-state", escaped}}}},
+	}, nil
+}
+
+// removeUnitStateOp returns the operation needed to remove the charm
+// state stored for the unit with the given global key, if any.
+func removeUnitStateOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
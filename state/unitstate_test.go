@@ -0,0 +1,181 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitStateSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitStateSuite{})
+
+func (s *UnitStateSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *UnitStateSuite) TestNoCharmState(c *gc.C) {
+	charmState, err := s.unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{})
+}
+
+func (s *UnitStateSuite) TestSetCharmState(c *gc.C) {
+	err := s.unit.SetCharmState(map[string]string{
+		"initialised":  "true",
+		"db.version":   "9.5",
+		"$cluster-key": "abc",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmState(map[string]string{
+		"initialised": "true",
+		"db.version":  "9.6",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	charmState, err := unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{
+		"initialised": "true",
+		"db.version":  "9.6",
+	})
+}
+
+func (s *UnitStateSuite) TestSetCharmStateEmptyKey(c *gc.C) {
+	err := s.unit.SetCharmState(map[string]string{"": "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot set charm state for unit ".*": empty key not valid`)
+}
+
+func (s *UnitStateSuite) TestSetCharmStateKeyTooLarge(c *gc.C) {
+	key := strings.Repeat("k", state.MaxCharmStateKeySize+1)
+	err := s.unit.SetCharmState(map[string]string{key: "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot set charm state for unit ".*": key of 257 bytes exceeds limit of 256 bytes`)
+}
+
+func (s *UnitStateSuite) TestSetCharmStateTooLarge(c *gc.C) {
+	charmState := map[string]string{
+		"a": strings.Repeat("v", state.MaxCharmStateSize/2),
+		"b": strings.Repeat("v", state.MaxCharmStateSize/2),
+	}
+	err := s.unit.SetCharmState(charmState)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(
+		`cannot set charm state for unit ".*": charm state of %d bytes exceeds limit of %d bytes`,
+		state.MaxCharmStateSize+2, state.MaxCharmStateSize,
+	))
+
+	// Nothing is written when the limit is exceeded.
+	stored, err := s.unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored, jc.DeepEquals, map[string]string{})
+}
+
+func (s *UnitStateSuite) TestSetCharmStateDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmState(map[string]string{"key": "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot set charm state for unit ".*": not found or dead`)
+}
+
+func (s *UnitStateSuite) TestRemoveUnitRemovesCharmState(c *gc.C) {
+	err := s.unit.SetCharmState(map[string]string{"key": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	unitStates, closer := state.GetRawCollection(s.State, "unitstates")
+	defer closer()
+	n, err := unitStates.Find(nil).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+}
+
+func (s *UnitStateSuite) addRelationUnit(c *gc.C) (*state.Unit, *state.RelationUnit) {
+	riak := s.AddTestingService(c, "riak", s.AddTestingCharm(c, "riak"))
+	unit, err := riak.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	riakEP, err := riak.Endpoint("ring")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.EndpointsRelation(riakEP)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	return unit, ru
+}
+
+func (s *UnitStateSuite) TestCommitHookChanges(c *gc.C) {
+	unit, ru := s.addRelationUnit(c)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("baz", "qux")
+	settings.Delete("foo")
+
+	err = unit.CommitHookChanges([]*state.Settings{settings}, map[string]string{"key": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	written, err := ru.ReadSettings(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(written, jc.DeepEquals, map[string]interface{}{"baz": "qux"})
+	charmState, err := unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"key": "value"})
+
+	// Nothing is written when nothing has changed.
+	err = unit.CommitHookChanges([]*state.Settings{settings}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	charmState, err = unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, jc.DeepEquals, map[string]string{"key": "value"})
+}
+
+func (s *UnitStateSuite) TestCommitHookChangesInvalidCharmState(c *gc.C) {
+	unit, ru := s.addRelationUnit(c)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("baz", "qux")
+
+	err = unit.CommitHookChanges([]*state.Settings{settings}, map[string]string{"": "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot commit hook changes for unit "riak/0": empty key not valid`)
+
+	written, err := ru.ReadSettings(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(written, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+}
+
+func (s *UnitStateSuite) TestCommitHookChangesUnitDies(c *gc.C) {
+	unit, ru := s.addRelationUnit(c)
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("baz", "qux")
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := unit.EnsureDead()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = unit.CommitHookChanges([]*state.Settings{settings}, map[string]string{"key": "value"})
+	c.Assert(err, gc.ErrorMatches, `cannot commit hook changes for unit "riak/0": not found or dead`)
+
+	written, err := ru.ReadSettings(unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(written, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+	charmState, err := unit.CharmState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charmState, gc.HasLen, 0)
+}
@@ -82,6 +82,10 @@ type upgradeInfoDoc struct {
 	Started          time.Time      `bson:"started"`
 	ControllersReady []string       `bson:"controllersReady"`
 	ControllersDone  []string       `bson:"controllersDone"`
+
+	// IrreversibleSteps holds the descriptions of the irreversible
+	// upgrade steps that have been started.
+	IrreversibleSteps []string `bson:"irreversibleSteps,omitempty"`
 }
 
 // UpgradeInfo is used to synchronise controller upgrades.
@@ -126,6 +130,15 @@ func (info *UpgradeInfo) ControllersDone() []string {
 	return result
 }
 
+// IrreversibleSteps returns the descriptions of the irreversible
+// upgrade steps that have been started. The upgrade cannot be rolled
+// back if there are any.
+func (info *UpgradeInfo) IrreversibleSteps() []string {
+	result := make([]string, len(info.doc.IrreversibleSteps))
+	copy(result, info.doc.IrreversibleSteps)
+	return result
+}
+
 // Refresh updates the contents of the UpgradeInfo from underlying state.
 func (info *UpgradeInfo) Refresh() error {
 	doc, err := currentUpgradeInfoDoc(info.st)
@@ -515,6 +528,42 @@ func assertExpectedVersions(previousVersion, targetVersion version.Number) bson.
 	}}
 }
 
+// RecordIrreversibleUpgradeStep records, against the current upgrade,
+// that the irreversible upgrade step with the given description is
+// about to run.
+func (st *State) RecordIrreversibleUpgradeStep(description string) error {
+	ops := []txn.Op{{
+		C:      upgradeInfoC,
+		Id:     currentUpgradeId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"irreversibleSteps", description}}}},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("current upgrade info")
+	}
+	return errors.Annotate(err, "cannot record irreversible upgrade step")
+}
+
+// irreversibleUpgradeSteps returns the descriptions of the irreversible
+// steps started by any upgrade, current or archived, between the given
+// versions.
+func (st *State) irreversibleUpgradeSteps(previousVersion, targetVersion version.Number) ([]string, error) {
+	upgradeInfo, closer := st.getCollection(upgradeInfoC)
+	defer closer()
+
+	var docs []upgradeInfoDoc
+	err := upgradeInfo.Find(assertExpectedVersions(previousVersion, targetVersion)).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read upgrade info")
+	}
+	steps := set.NewStrings()
+	for _, doc := range docs {
+		steps = steps.Union(set.NewStrings(doc.IrreversibleSteps...))
+	}
+	return steps.SortedValues(), nil
+}
+
 // ClearUpgradeInfo clears information about an upgrade in progress. It returns
 // an error if no upgrade is current.
 func (st *State) ClearUpgradeInfo() error {
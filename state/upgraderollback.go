@@ -0,0 +1,214 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// upgradeRollbackKey is the id of the single upgrade rollback
+// document of a model.
+const upgradeRollbackKey = "upgrade-rollback"
+
+type upgradeRollbackDoc struct {
+	DocID           string         `bson:"_id"`
+	ModelUUID       string         `bson:"model-uuid"`
+	PreviousVersion version.Number `bson:"previous-version"`
+	TargetVersion   version.Number `bson:"target-version"`
+	BackupId        string         `bson:"backup-id"`
+	RolledBack      bool           `bson:"rolled-back"`
+}
+
+// UpgradeRollback records the model's most recent agent upgrade, and
+// what is needed to roll it back.
+type UpgradeRollback struct {
+	st  *State
+	doc upgradeRollbackDoc
+}
+
+// PreviousVersion returns the agent version the model ran before the
+// upgrade.
+func (r *UpgradeRollback) PreviousVersion() version.Number {
+	return r.doc.PreviousVersion
+}
+
+// TargetVersion returns the agent version the model was upgraded to.
+func (r *UpgradeRollback) TargetVersion() version.Number {
+	return r.doc.TargetVersion
+}
+
+// BackupId returns the id of the backup of the controller taken
+// before the upgrade, or "" if there is none. Only upgrades of the
+// controller model are backed up.
+func (r *UpgradeRollback) BackupId() string {
+	return r.doc.BackupId
+}
+
+// RolledBack reports whether the upgrade has been rolled back.
+func (r *UpgradeRollback) RolledBack() bool {
+	return r.doc.RolledBack
+}
+
+// UpgradeRollback returns the model's most recent agent upgrade. It
+// returns an error satisfying errors.IsNotFound if none has been
+// recorded.
+func (st *State) UpgradeRollback() (*UpgradeRollback, error) {
+	rollbacks, closer := st.getCollection(upgradeRollbacksC)
+	defer closer()
+
+	var doc upgradeRollbackDoc
+	err := rollbacks.FindId(upgradeRollbackKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("upgrade rollback")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get upgrade rollback")
+	}
+	return &UpgradeRollback{st: st, doc: doc}, nil
+}
+
+// RecordUpgrade records that the model's agent version has been
+// changed from previous to target, along with the id of the backup
+// taken beforehand, if any. It replaces any earlier record.
+func (st *State) RecordUpgrade(previous, target version.Number, backupId string) error {
+	doc := upgradeRollbackDoc{
+		DocID:           upgradeRollbackKey,
+		PreviousVersion: previous,
+		TargetVersion:   target,
+		BackupId:        backupId,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		return st.upgradeRollbackOps(doc)
+	}
+	return errors.Annotatef(st.run(buildTxn), "cannot record upgrade to %s", target)
+}
+
+// upgradeRollbackOps returns the operations that replace the model's
+// upgrade rollback document with the one given.
+func (st *State) upgradeRollbackOps(doc upgradeRollbackDoc) ([]txn.Op, error) {
+	_, err := st.UpgradeRollback()
+	if errors.IsNotFound(err) {
+		return []txn.Op{{
+			C:      upgradeRollbacksC,
+			Id:     upgradeRollbackKey,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      upgradeRollbacksC,
+		Id:     upgradeRollbackKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"previous-version", doc.PreviousVersion},
+			{"target-version", doc.TargetVersion},
+			{"backup-id", doc.BackupId},
+			{"rolled-back", doc.RolledBack},
+		}}},
+	}}, nil
+}
+
+// CheckUpgradeRollback returns the model's most recent agent upgrade
+// if it can be rolled back. It cannot be if the model's agent version
+// has changed since, or if the upgrade has started any irreversible
+// steps. Upgrades of the controller model can only be rolled back if
+// a backup was taken beforehand.
+func (st *State) CheckUpgradeRollback() (_ *UpgradeRollback, err error) {
+	rollback, err := st.UpgradeRollback()
+	if errors.IsNotFound(err) {
+		return nil, errors.NewNotFound(nil, "no upgrade to roll back")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot roll back upgrade to %s", rollback.TargetVersion())
+	if rollback.RolledBack() {
+		return nil, errors.New("upgrade has already been rolled back")
+	}
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if agentVersion, _ := cfg.AgentVersion(); agentVersion != rollback.TargetVersion() {
+		return nil, errors.Errorf("model agent version has since changed to %s", agentVersion)
+	}
+	if !st.IsController() {
+		return rollback, nil
+	}
+	if rollback.BackupId() == "" {
+		return nil, errors.New("no backup of the controller was taken before the upgrade")
+	}
+	steps, err := st.irreversibleUpgradeSteps(rollback.PreviousVersion(), rollback.TargetVersion())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(steps) > 0 {
+		return nil, errors.Errorf("irreversible upgrade steps have run: %s", strings.Join(steps, ", "))
+	}
+	return rollback, nil
+}
+
+// FinishUpgradeRollback sets the model's agent version back from
+// target to previous, and records that the upgrade was rolled back so
+// that agents already running target will downgrade. If this is the
+// controller model, any upgrade between those versions still in
+// progress is aborted.
+//
+// The model's database may have been restored from the backup taken
+// before the upgrade, in which case its agent version will already be
+// previous.
+func (st *State) FinishUpgradeRollback(previous, target version.Number) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		settings, err := readSettings(st, modelGlobalKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		switch agentVersion, _ := settings.Get("agent-version"); agentVersion {
+		case previous.String():
+		case target.String():
+			ops = append(ops, txn.Op{
+				C:      settingsC,
+				Id:     st.docID(modelGlobalKey),
+				Assert: bson.D{{"version", settings.version}},
+				Update: bson.D{
+					{"$set", bson.D{{"settings.agent-version", previous.String()}}},
+				},
+			})
+		default:
+			return nil, errors.Errorf("model agent version is %v, not %s", agentVersion, target)
+		}
+		if st.IsController() {
+			doc, err := currentUpgradeInfoDoc(st)
+			if err == nil && doc.PreviousVersion == previous && doc.TargetVersion == target {
+				info := &UpgradeInfo{st: st, doc: *doc}
+				ops = append(ops, info.makeArchiveOps(doc, UpgradeAborted)...)
+			} else if err != nil && !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+		}
+		doc := upgradeRollbackDoc{
+			DocID:           upgradeRollbackKey,
+			PreviousVersion: previous,
+			TargetVersion:   target,
+			RolledBack:      true,
+		}
+		if existing, err := st.UpgradeRollback(); err == nil {
+			doc.BackupId = existing.BackupId()
+		}
+		rollbackOps, err := st.upgradeRollbackOps(doc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, rollbackOps...), nil
+	}
+	err := st.run(buildTxn)
+	return errors.Annotatef(err, "cannot roll back upgrade to %s", target)
+}
@@ -0,0 +1,150 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+// upgradeModel sets the model's agent version to the next patch
+// version, and returns the previous and new versions.
+func (s *UpgradeSuite) upgradeModel(c *gc.C) (previous, target version.Number) {
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	previous, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	controller, err := s.State.Machine(s.serverIdA)
+	c.Assert(err, jc.ErrorIsNil)
+	err = controller.SetAgentVersion(version.Binary{Number: previous, Series: "quantal", Arch: "amd64"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	target = previous
+	target.Patch++
+	err = s.State.SetModelAgentVersion(target)
+	c.Assert(err, jc.ErrorIsNil)
+	return previous, target
+}
+
+func (s *UpgradeSuite) TestRecordUpgrade(c *gc.C) {
+	_, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RecordUpgrade(vers("1.2.3"), vers("1.2.4"), "backup-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RecordUpgrade(vers("1.2.4"), vers("1.3.0"), "backup-2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollback, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.PreviousVersion(), gc.Equals, vers("1.2.4"))
+	c.Assert(rollback.TargetVersion(), gc.Equals, vers("1.3.0"))
+	c.Assert(rollback.BackupId(), gc.Equals, "backup-2")
+	c.Assert(rollback.RolledBack(), jc.IsFalse)
+}
+
+func (s *UpgradeSuite) TestCheckUpgradeRollback(c *gc.C) {
+	_, err := s.State.CheckUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "no upgrade to roll back")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	previous, target := s.upgradeModel(c)
+	err = s.State.RecordUpgrade(previous, target, "backup-id")
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollback, err := s.State.CheckUpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.PreviousVersion(), gc.Equals, previous)
+	c.Assert(rollback.TargetVersion(), gc.Equals, target)
+	c.Assert(rollback.BackupId(), gc.Equals, "backup-id")
+}
+
+func (s *UpgradeSuite) TestCheckUpgradeRollbackWithoutBackup(c *gc.C) {
+	previous, target := s.upgradeModel(c)
+	err := s.State.RecordUpgrade(previous, target, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.CheckUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to .*: no backup of the controller was taken before the upgrade")
+}
+
+func (s *UpgradeSuite) TestCheckUpgradeRollbackVersionChanged(c *gc.C) {
+	previous, target := s.upgradeModel(c)
+	superseded := previous
+	superseded.Minor++
+	err := s.State.RecordUpgrade(target, superseded, "backup-id")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.CheckUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to .*: model agent version has since changed to "+target.String())
+}
+
+func (s *UpgradeSuite) TestCheckUpgradeRollbackIrreversible(c *gc.C) {
+	previous, target := s.upgradeModel(c)
+	err := s.State.RecordUpgrade(previous, target, "backup-id")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RecordIrreversibleUpgradeStep("drop the old schema")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	info, err := s.State.EnsureUpgradeInfo(s.serverIdA, previous, target)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RecordIrreversibleUpgradeStep("drop the old schema")
+	c.Assert(err, jc.ErrorIsNil)
+	err = info.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.IrreversibleSteps(), jc.DeepEquals, []string{"drop the old schema"})
+
+	// Aborting the upgrade does not make the step reversible.
+	err = info.Abort()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.CheckUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to .*: irreversible upgrade steps have run: drop the old schema")
+}
+
+func (s *UpgradeSuite) TestFinishUpgradeRollback(c *gc.C) {
+	previous, target := s.upgradeModel(c)
+	err := s.State.RecordUpgrade(previous, target, "backup-id")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnsureUpgradeInfo(s.serverIdA, previous, target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.FinishUpgradeRollback(previous, target)
+	c.Assert(err, jc.ErrorIsNil)
+	assertAgentVersion(c, s.State, previous.String())
+	s.assertUpgrading(c, false)
+	rollback, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.RolledBack(), jc.IsTrue)
+	c.Assert(rollback.BackupId(), gc.Equals, "backup-id")
+
+	_, err = s.State.CheckUpgradeRollback()
+	c.Assert(err, gc.ErrorMatches, "cannot roll back upgrade to .*: upgrade has already been rolled back")
+}
+
+func (s *UpgradeSuite) TestFinishUpgradeRollbackAfterRestore(c *gc.C) {
+	// A database restored from the pre-upgrade backup is already
+	// running the previous version, and has no record of the upgrade.
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	previous, _ := cfg.AgentVersion()
+	target := previous
+	target.Patch++
+
+	err = s.State.FinishUpgradeRollback(previous, target)
+	c.Assert(err, jc.ErrorIsNil)
+	assertAgentVersion(c, s.State, previous.String())
+	rollback, err := s.State.UpgradeRollback()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.PreviousVersion(), gc.Equals, previous)
+	c.Assert(rollback.TargetVersion(), gc.Equals, target)
+	c.Assert(rollback.RolledBack(), jc.IsTrue)
+}
+
+func (s *UpgradeSuite) TestFinishUpgradeRollbackWrongVersion(c *gc.C) {
+	err := s.State.FinishUpgradeRollback(vers("1.2.3"), vers("1.2.4"))
+	c.Assert(err, gc.ErrorMatches, `cannot roll back upgrade to 1.2.4: model agent version is .*, not 1.2.4`)
+}
@@ -0,0 +1,458 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/status"
+)
+
+// UpgradeRolloutStatus describes the progress of a staged agent
+// upgrade.
+type UpgradeRolloutStatus string
+
+const (
+	// RolloutRunning means that machines are being released to the
+	// new agent version as earlier ones complete their upgrades.
+	RolloutRunning UpgradeRolloutStatus = "running"
+
+	// RolloutPaused means that a released machine failed, or did not
+	// upgrade within the rollout's batch timeout, and no further
+	// machines will be released until the rollout is resumed.
+	RolloutPaused UpgradeRolloutStatus = "paused"
+
+	// RolloutComplete means that every machine has been released.
+	RolloutComplete UpgradeRolloutStatus = "complete"
+)
+
+// upgradeRolloutKey is the id of the single upgrade rollout
+// document of a model.
+const upgradeRolloutKey = "upgrade-rollout"
+
+// DefaultUpgradeRolloutBatchTimeout is the time released machines are
+// given to upgrade, if the rollout does not specify one.
+const DefaultUpgradeRolloutBatchTimeout = 30 * time.Minute
+
+// errUpgradeRolloutChanged is returned when the upgrade rollout was
+// changed by another party while being updated.
+var errUpgradeRolloutChanged = errors.New("upgrade rollout has changed")
+
+// rolloutAgentAlive reports whether the agent of the given machine
+// is alive. It is a variable so that tests can patch it.
+var rolloutAgentAlive = func(m *Machine) (bool, error) {
+	return m.AgentPresence()
+}
+
+type upgradeRolloutDoc struct {
+	DocID           string               `bson:"_id"`
+	ModelUUID       string               `bson:"model-uuid"`
+	PreviousVersion version.Number       `bson:"previous-version"`
+	Version         version.Number       `bson:"version"`
+	Canaries        []string             `bson:"canaries"`
+	BatchSize       int                  `bson:"batch-size"`
+	BatchTimeout    time.Duration        `bson:"batch-timeout"`
+	Released        []string             `bson:"released"`
+	ReleasedTime    int64                `bson:"released-time"`
+	Status          UpgradeRolloutStatus `bson:"status"`
+	Message         string               `bson:"message"`
+	TxnRevno        int64                `bson:"txn-revno"`
+}
+
+// UpgradeRollout holds the progress of a staged agent upgrade. The
+// new agent version is released to a set of canary machines first,
+// and then to the model's other machines in batches, each batch
+// being released once every earlier machine has upgraded. The model's
+// controllers are not held back.
+type UpgradeRollout struct {
+	st  *State
+	doc upgradeRolloutDoc
+}
+
+// PreviousVersion returns the agent version the model ran before the
+// rollout started.
+func (r *UpgradeRollout) PreviousVersion() version.Number {
+	return r.doc.PreviousVersion
+}
+
+// Version returns the agent version being rolled out.
+func (r *UpgradeRollout) Version() version.Number {
+	return r.doc.Version
+}
+
+// Canaries returns the ids of the machines that were upgraded first.
+func (r *UpgradeRollout) Canaries() []string {
+	return r.doc.Canaries
+}
+
+// BatchSize returns the number of machines released at a time once
+// the canaries have upgraded.
+func (r *UpgradeRollout) BatchSize() int {
+	return r.doc.BatchSize
+}
+
+// BatchTimeout returns the time the released machines are given to
+// upgrade before the rollout is paused.
+func (r *UpgradeRollout) BatchTimeout() time.Duration {
+	return r.doc.BatchTimeout
+}
+
+// Released returns the ids of the machines that have been released
+// to the new agent version.
+func (r *UpgradeRollout) Released() []string {
+	return r.doc.Released
+}
+
+// Status returns the status of the rollout.
+func (r *UpgradeRollout) Status() UpgradeRolloutStatus {
+	return r.doc.Status
+}
+
+// Message returns a description of why the rollout was paused or
+// completed early, if it was.
+func (r *UpgradeRollout) Message() string {
+	return r.doc.Message
+}
+
+// Active reports whether the rollout is still holding machines back
+// from the new agent version.
+func (r *UpgradeRollout) Active() bool {
+	return r.doc.Status != RolloutComplete
+}
+
+// IsReleased reports whether the machine with the given id may
+// upgrade to the new agent version.
+func (r *UpgradeRollout) IsReleased(machineId string) bool {
+	if !r.Active() {
+		return true
+	}
+	for _, id := range r.doc.Released {
+		if id == machineId {
+			return true
+		}
+	}
+	return false
+}
+
+// UpgradeRolloutParams defines a staged agent upgrade.
+type UpgradeRolloutParams struct {
+	// Version is the agent version to roll out.
+	Version version.Number
+
+	// Canaries holds the ids of the machines to upgrade first.
+	Canaries []string
+
+	// BatchSize is the number of machines to release at a time
+	// once the canaries have upgraded.
+	BatchSize int
+
+	// BatchTimeout is the time the released machines are given to
+	// upgrade, after which the rollout is paused. If it is zero,
+	// DefaultUpgradeRolloutBatchTimeout is used.
+	BatchTimeout time.Duration
+}
+
+// Validate returns an error if the parameters are not valid.
+func (p UpgradeRolloutParams) Validate() error {
+	if len(p.Canaries) == 0 {
+		return errors.NotValidf("upgrade rollout without canaries")
+	}
+	if p.BatchSize < 1 {
+		return errors.NotValidf("batch size %d", p.BatchSize)
+	}
+	if p.BatchTimeout < 0 {
+		return errors.NotValidf("batch timeout %v", p.BatchTimeout)
+	}
+	return nil
+}
+
+// UpgradeRollout returns the model's most recent upgrade rollout. It
+// returns an error satisfying errors.IsNotFound if there has never
+// been one.
+func (st *State) UpgradeRollout() (*UpgradeRollout, error) {
+	rollouts, closer := st.getCollection(upgradeRolloutsC)
+	defer closer()
+
+	var doc upgradeRolloutDoc
+	err := rollouts.FindId(upgradeRolloutKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("upgrade rollout")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get upgrade rollout")
+	}
+	return &UpgradeRollout{st: st, doc: doc}, nil
+}
+
+// StartUpgradeRollout sets the model's agent version, as
+// SetModelAgentVersion does, but releases the new version only to the
+// canary machines given in args. Other machines are released by
+// AdvanceUpgradeRollout.
+func (st *State) StartUpgradeRollout(args UpgradeRolloutParams) (_ *UpgradeRollout, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot start upgrade rollout to %s", args.Version)
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	canaries := set.NewStrings(args.Canaries...).SortedValues()
+	batchTimeout := args.BatchTimeout
+	if batchTimeout == 0 {
+		batchTimeout = DefaultUpgradeRolloutBatchTimeout
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		existing, err := st.UpgradeRollout()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if existing != nil && existing.Active() {
+			return nil, errors.AlreadyExistsf("upgrade rollout to %s", existing.Version())
+		}
+		var ops []txn.Op
+		for _, id := range canaries {
+			m, err := st.Machine(id)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if m.IsManager() {
+				return nil, errors.Errorf("machine %s is a controller, and is always upgraded first", id)
+			}
+			ops = append(ops, txn.Op{
+				C:      machinesC,
+				Id:     m.doc.DocID,
+				Assert: notDeadDoc,
+			})
+		}
+		versionOps, previous, err := st.setAgentVersionOps(args.Version)
+		if err == jujutxn.ErrNoOperations {
+			return nil, errors.Errorf("model is already running agent version %s", args.Version)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, versionOps...)
+
+		doc := upgradeRolloutDoc{
+			DocID:           upgradeRolloutKey,
+			PreviousVersion: previous,
+			Version:         args.Version,
+			Canaries:        canaries,
+			BatchSize:       args.BatchSize,
+			BatchTimeout:    batchTimeout,
+			Released:        canaries,
+			ReleasedTime:    GetClock().Now().UnixNano(),
+			Status:          RolloutRunning,
+		}
+		if existing == nil {
+			return append(ops, txn.Op{
+				C:      upgradeRolloutsC,
+				Id:     upgradeRolloutKey,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}), nil
+		}
+		// A completed rollout is replaced.
+		return append(ops, txn.Op{
+			C:      upgradeRolloutsC,
+			Id:     upgradeRolloutKey,
+			Assert: bson.D{{"txn-revno", existing.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{
+				{"previous-version", doc.PreviousVersion},
+				{"version", doc.Version},
+				{"canaries", doc.Canaries},
+				{"batch-size", doc.BatchSize},
+				{"batch-timeout", doc.BatchTimeout},
+				{"released", doc.Released},
+				{"released-time", doc.ReleasedTime},
+				{"status", doc.Status},
+				{"message", doc.Message},
+			}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.UpgradeRollout()
+}
+
+// ResumeUpgradeRollout resumes a paused upgrade rollout. Machines
+// that failed to upgrade are expected to have been fixed; if they
+// have not, the rollout will pause again. The released machines are
+// given a new batch timeout in which to upgrade.
+func (st *State) ResumeUpgradeRollout() error {
+	rollout, err := st.UpgradeRollout()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rollout.Status() != RolloutPaused {
+		return errors.Errorf("upgrade rollout to %s is %s, not paused", rollout.Version(), rollout.Status())
+	}
+	ops := []txn.Op{{
+		C:      upgradeRolloutsC,
+		Id:     rollout.doc.DocID,
+		Assert: bson.D{{"txn-revno", rollout.doc.TxnRevno}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", RolloutRunning},
+			{"message", ""},
+			{"released-time", GetClock().Now().UnixNano()},
+		}}},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(onAbort(err, errUpgradeRolloutChanged), "cannot resume upgrade rollout")
+	}
+	return nil
+}
+
+// WatchUpgradeRollout returns a NotifyWatcher that fires when the
+// model's upgrade rollout changes.
+func (st *State) WatchUpgradeRollout() NotifyWatcher {
+	return newEntityWatcher(st, upgradeRolloutsC, st.docID(upgradeRolloutKey))
+}
+
+// AdvanceUpgradeRollout takes the model's running upgrade rollout one
+// step further, and returns its new state.
+//
+// Once every released machine has upgraded, the next batch of machines
+// is released; when there are none left, the rollout is complete. A
+// released machine that reports an error status pauses the rollout,
+// so that a bad upgrade does not spread beyond the machines already
+// released. So do released machines that have not upgraded within the
+// rollout's batch timeout, which covers agents that died or hung
+// without reporting an error. A rollout whose version is no longer the model's agent
+// version has been superseded, and is completed.
+func (st *State) AdvanceUpgradeRollout() (*UpgradeRollout, error) {
+	rollout, err := st.UpgradeRollout()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if rollout.Status() != RolloutRunning {
+		return rollout, nil
+	}
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if agentVersion, _ := cfg.AgentVersion(); agentVersion != rollout.Version() {
+		message := fmt.Sprintf("superseded by agent version %s", agentVersion)
+		return rollout, errors.Trace(rollout.setStatus(RolloutComplete, message))
+	}
+
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var pending, waiting []string
+	for _, m := range machines {
+		if m.IsManager() || m.Life() == Dead {
+			continue
+		}
+		if !rollout.IsReleased(m.Id()) {
+			pending = append(pending, m.Id())
+			continue
+		}
+		upgraded, problem, err := machineUpgraded(m, rollout.Version())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if problem != "" {
+			message := fmt.Sprintf("machine %s: %s", m.Id(), problem)
+			logger.Warningf("pausing upgrade rollout to %s: %s", rollout.Version(), message)
+			return rollout, errors.Trace(rollout.setStatus(RolloutPaused, message))
+		}
+		if !upgraded {
+			waiting = append(waiting, m.Id())
+		}
+	}
+	if len(waiting) > 0 {
+		deadline := time.Unix(0, rollout.doc.ReleasedTime).Add(rollout.BatchTimeout())
+		if GetClock().Now().Before(deadline) {
+			return rollout, nil
+		}
+		message := fmt.Sprintf("machines %s did not upgrade within %v", strings.Join(waiting, ", "), rollout.BatchTimeout())
+		if len(waiting) == 1 {
+			message = fmt.Sprintf("machine %s did not upgrade within %v", waiting[0], rollout.BatchTimeout())
+		}
+		logger.Warningf("pausing upgrade rollout to %s: %s", rollout.Version(), message)
+		return rollout, errors.Trace(rollout.setStatus(RolloutPaused, message))
+	}
+	if len(pending) == 0 {
+		logger.Infof("upgrade rollout to %s is complete", rollout.Version())
+		return rollout, errors.Trace(rollout.setStatus(RolloutComplete, ""))
+	}
+	if len(pending) > rollout.BatchSize() {
+		pending = pending[:rollout.BatchSize()]
+	}
+	logger.Infof("releasing machines %s to agent version %s", strings.Join(pending, ", "), rollout.Version())
+	ops := []txn.Op{{
+		C:      upgradeRolloutsC,
+		Id:     rollout.doc.DocID,
+		Assert: bson.D{{"txn-revno", rollout.doc.TxnRevno}},
+		Update: bson.D{
+			{"$push", bson.D{{"released", bson.D{{"$each", pending}}}}},
+			{"$set", bson.D{{"released-time", GetClock().Now().UnixNano()}}},
+		},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Annotate(onAbort(err, errUpgradeRolloutChanged), "cannot release machines")
+	}
+	return st.UpgradeRollout()
+}
+
+// machineUpgraded reports whether the given machine is running the
+// given agent version, with its upgrade steps complete and its agent
+// alive. If the machine is in an error state, the returned problem
+// describes it.
+func machineUpgraded(m *Machine, v version.Number) (upgraded bool, problem string, err error) {
+	statusInfo, err := m.Status()
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if statusInfo.Status == status.StatusError {
+		return false, statusInfo.Message, nil
+	}
+	agentTools, err := m.AgentTools()
+	if errors.IsNotFound(err) {
+		return false, "", nil
+	} else if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	if agentTools.Version.Number != v {
+		return false, "", nil
+	}
+	// The upgradesteps worker reports the upgrade in the status
+	// message until the steps have completed.
+	if statusInfo.Status != status.StatusStarted || strings.HasPrefix(statusInfo.Message, "upgrading to") {
+		return false, "", nil
+	}
+	alive, err := rolloutAgentAlive(m)
+	if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	return alive, "", nil
+}
+
+func (r *UpgradeRollout) setStatus(rolloutStatus UpgradeRolloutStatus, message string) error {
+	ops := []txn.Op{{
+		C:      upgradeRolloutsC,
+		Id:     r.doc.DocID,
+		Assert: bson.D{{"txn-revno", r.doc.TxnRevno}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", rolloutStatus},
+			{"message", message},
+		}}},
+	}}
+	if err := r.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, errUpgradeRolloutChanged), "cannot set upgrade rollout status to %s", rolloutStatus)
+	}
+	r.doc.Status = rolloutStatus
+	r.doc.Message = message
+	r.doc.TxnRevno++
+	return nil
+}
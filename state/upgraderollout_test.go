@@ -0,0 +1,304 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var rolloutVersion = version.MustParse("4.5.6")
+
+// addRolloutMachines adds the given number of machines running the
+// model's current agent version, and makes their agents look alive.
+func (s *StateSuite) addRolloutMachines(c *gc.C, n int) []*state.Machine {
+	s.PatchValue(state.RolloutAgentAlive, func(*state.Machine) (bool, error) {
+		return true, nil
+	})
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	current, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	machines := make([]*state.Machine, n)
+	for i := range machines {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = m.SetAgentVersion(version.Binary{Number: current, Series: "quantal", Arch: "amd64"})
+		c.Assert(err, jc.ErrorIsNil)
+		machines[i] = m
+	}
+	return machines
+}
+
+// upgradeMachine makes the given machine look as though it has
+// completed its upgrade to rolloutVersion.
+func upgradeMachine(c *gc.C, m *state.Machine) {
+	err := m.SetAgentVersion(version.Binary{Number: rolloutVersion, Series: "quantal", Arch: "amd64"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetStatus(status.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StateSuite) startRollout(c *gc.C, canaries []string, batchSize int) *state.UpgradeRollout {
+	rollout, err := s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   rolloutVersion,
+		Canaries:  canaries,
+		BatchSize: batchSize,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return rollout
+}
+
+func (s *StateSuite) TestStartUpgradeRollout(c *gc.C) {
+	s.addRolloutMachines(c, 3)
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	previous, _ := cfg.AgentVersion()
+
+	rollout := s.startRollout(c, []string{"1", "0", "1"}, 2)
+	c.Assert(rollout.Version(), gc.Equals, rolloutVersion)
+	c.Assert(rollout.PreviousVersion(), gc.Equals, previous)
+	c.Assert(rollout.Canaries(), jc.DeepEquals, []string{"0", "1"})
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"0", "1"})
+	c.Assert(rollout.BatchSize(), gc.Equals, 2)
+	c.Assert(rollout.BatchTimeout(), gc.Equals, state.DefaultUpgradeRolloutBatchTimeout)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Assert(rollout.Active(), jc.IsTrue)
+	c.Assert(rollout.IsReleased("1"), jc.IsTrue)
+	c.Assert(rollout.IsReleased("2"), jc.IsFalse)
+	assertAgentVersion(c, s.State, rolloutVersion.String())
+}
+
+func (s *StateSuite) TestStartUpgradeRolloutInvalid(c *gc.C) {
+	s.addRolloutMachines(c, 1)
+	_, err := s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   rolloutVersion,
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot start upgrade rollout to 4.5.6: upgrade rollout without canaries not valid")
+	_, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:  rolloutVersion,
+		Canaries: []string{"0"},
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot start upgrade rollout to 4.5.6: batch size 0 not valid")
+	_, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:      rolloutVersion,
+		Canaries:     []string{"0"},
+		BatchSize:    1,
+		BatchTimeout: -time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot start upgrade rollout to 4.5.6: batch timeout -1s not valid")
+	_, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   rolloutVersion,
+		Canaries:  []string{"42"},
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StateSuite) TestStartUpgradeRolloutControllerCanary(c *gc.C) {
+	s.addRolloutMachines(c, 1)
+	_, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   rolloutVersion,
+		Canaries:  []string{"1"},
+		BatchSize: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot start upgrade rollout to 4.5.6: machine 1 is a controller, and is always upgraded first")
+}
+
+func (s *StateSuite) TestStartUpgradeRolloutAlreadyActive(c *gc.C) {
+	s.addRolloutMachines(c, 2)
+	s.startRollout(c, []string{"0"}, 1)
+	_, err := s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   version.MustParse("4.5.7"),
+		Canaries:  []string{"1"},
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *StateSuite) TestAdvanceUpgradeRollout(c *gc.C) {
+	machines := s.addRolloutMachines(c, 4)
+	s.startRollout(c, []string{"2"}, 2)
+
+	// The canary has not upgraded yet.
+	rollout, err := s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"2"})
+
+	// A canary that is still running its upgrade steps holds
+	// back the rollout.
+	err = machines[2].SetAgentVersion(version.Binary{Number: rolloutVersion, Series: "quantal", Arch: "amd64"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machines[2].SetStatus(status.StatusStarted, "upgrading to 4.5.6", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"2"})
+
+	upgradeMachine(c, machines[2])
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"2", "0", "1"})
+
+	upgradeMachine(c, machines[0])
+	upgradeMachine(c, machines[1])
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"2", "0", "1", "3"})
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+
+	upgradeMachine(c, machines[3])
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutComplete)
+	c.Assert(rollout.Active(), jc.IsFalse)
+	c.Assert(rollout.IsReleased("3"), jc.IsTrue)
+}
+
+func (s *StateSuite) TestAdvanceUpgradeRolloutWaitsForAgent(c *gc.C) {
+	machines := s.addRolloutMachines(c, 2)
+	s.startRollout(c, []string{"0"}, 1)
+	upgradeMachine(c, machines[0])
+	s.PatchValue(state.RolloutAgentAlive, func(*state.Machine) (bool, error) {
+		return false, nil
+	})
+	rollout, err := s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"0"})
+}
+
+func (s *StateSuite) TestAdvanceUpgradeRolloutPausesOnError(c *gc.C) {
+	machines := s.addRolloutMachines(c, 2)
+	s.startRollout(c, []string{"0"}, 1)
+	err := machines[0].SetStatus(status.StatusError, "upgrade to 4.5.6 failed (giving up): boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollout, err := s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutPaused)
+	c.Assert(rollout.Message(), gc.Equals, "machine 0: upgrade to 4.5.6 failed (giving up): boom")
+	c.Assert(rollout.Active(), jc.IsTrue)
+
+	// A paused rollout releases nothing more until it is resumed.
+	upgradeMachine(c, machines[0])
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"0"})
+
+	err = s.State.ResumeUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Assert(rollout.Message(), gc.Equals, "")
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"0", "1"})
+}
+
+func (s *StateSuite) TestAdvanceUpgradeRolloutPausesOnTimeout(c *gc.C) {
+	testClock := coretesting.NewClock(time.Now())
+	s.PatchValue(&state.GetClock, func() clock.Clock {
+		return testClock
+	})
+	machines := s.addRolloutMachines(c, 3)
+	_, err := s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:      rolloutVersion,
+		Canaries:     []string{"0"},
+		BatchSize:    2,
+		BatchTimeout: time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each batch gets the full timeout from when it is released.
+	testClock.Advance(50 * time.Minute)
+	upgradeMachine(c, machines[0])
+	rollout, err := s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Released(), jc.DeepEquals, []string{"0", "1", "2"})
+
+	// The agent of machine 2 upgrades, but then dies without
+	// reporting an error.
+	upgradeMachine(c, machines[2])
+	s.PatchValue(state.RolloutAgentAlive, func(*state.Machine) (bool, error) {
+		return false, nil
+	})
+	testClock.Advance(50 * time.Minute)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+
+	testClock.Advance(10 * time.Minute)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutPaused)
+	c.Assert(rollout.Message(), gc.Equals, "machines 0, 1, 2 did not upgrade within 1h0m0s")
+
+	// Resuming gives the machines a new timeout.
+	err = s.State.ResumeUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+
+	s.PatchValue(state.RolloutAgentAlive, func(*state.Machine) (bool, error) {
+		return true, nil
+	})
+	testClock.Advance(time.Hour)
+	rollout, err = s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutPaused)
+	c.Assert(rollout.Message(), gc.Equals, "machine 1 did not upgrade within 1h0m0s")
+}
+
+func (s *StateSuite) TestResumeUpgradeRolloutNotPaused(c *gc.C) {
+	s.addRolloutMachines(c, 1)
+	err := s.State.ResumeUpgradeRollout()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.startRollout(c, []string{"0"}, 1)
+	err = s.State.ResumeUpgradeRollout()
+	c.Assert(err, gc.ErrorMatches, "upgrade rollout to 4.5.6 is running, not paused")
+}
+
+func (s *StateSuite) TestAdvanceUpgradeRolloutSuperseded(c *gc.C) {
+	machines := s.addRolloutMachines(c, 2)
+	s.startRollout(c, []string{"0"}, 1)
+	// The agent version can only be changed once every agent is
+	// running the current one.
+	for _, m := range machines {
+		upgradeMachine(c, m)
+	}
+	err := s.State.SetModelAgentVersion(version.MustParse("4.5.7"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	rollout, err := s.State.AdvanceUpgradeRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutComplete)
+	c.Assert(rollout.Message(), gc.Equals, "superseded by agent version 4.5.7")
+
+	// A completed rollout may be replaced by a new one.
+	for _, m := range machines {
+		err := m.SetAgentVersion(version.MustParseBinary("4.5.7-quantal-amd64"))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	rollout, err = s.State.StartUpgradeRollout(state.UpgradeRolloutParams{
+		Version:   version.MustParse("4.5.8"),
+		Canaries:  []string{"1"},
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Status(), gc.Equals, state.RolloutRunning)
+	c.Assert(rollout.PreviousVersion(), gc.Equals, version.MustParse("4.5.7"))
+}
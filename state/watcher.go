@@ -286,9 +286,30 @@ func (st *State) WatchMachineVolumeAttachments(m names.MachineTag) StringsWatche
 
 // WatchMachineFilesystemAttachments returns a StringsWatcher that notifies of
 // changes to the lifecycles of all filesystem attachments related to the specified
-// machine, for filesystems scoped to the machine.
+// machine, for filesystems scoped to the machine, and for model-scoped filesystems
+// which may be shared between machines.
+//
+// Shared filesystems are attached by each machine's storage provisioner. The
+// watcher cannot distinguish them from other model-scoped filesystems, so the
+// storage provisioner is responsible for filtering out attachments that it
+// does not manage.
 func (st *State) WatchMachineFilesystemAttachments(m names.MachineTag) StringsWatcher {
-	return st.watchMachineStorageAttachments(m, filesystemAttachmentsC)
+	pattern := fmt.Sprintf("^%s:", st.docID(m.Id()))
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	prefix := m.Id() + ":"
+	machinePrefix := prefix + m.Id() + "/"
+	filter := func(id interface{}) bool {
+		k, err := st.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		if strings.HasPrefix(k, machinePrefix) {
+			return true
+		}
+		// Include attachments of model-scoped filesystems.
+		return strings.HasPrefix(k, prefix) && !strings.Contains(k[len(prefix):], "/")
+	}
+	return newLifecycleWatcher(st, filesystemAttachmentsC, members, filter, nil)
 }
 
 func (st *State) watchMachineStorageAttachments(m names.MachineTag, collection string) StringsWatcher {
@@ -1879,6 +1900,108 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// firewallRulesWatcher notifies of changes to the model's firewall
+// rules, to the subnets that the rules' spaces resolve to, and to the
+// addresses that determine which spaces a machine is in.
+type firewallRulesWatcher struct {
+	commonWatcher
+	out     chan struct{}
+	machine *Machine
+}
+
+var _ Watcher = (*firewallRulesWatcher)(nil)
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of changes
+// to the model's firewall rules, to its subnets, or to the machine's
+// addresses, any of which may change the egress rules that apply to
+// the machine.
+func (m *Machine) WatchFirewallRules() NotifyWatcher {
+	w := &firewallRulesWatcher{
+		commonWatcher: commonWatcher{st: m.st},
+		out:           make(chan struct{}),
+		machine:       &Machine{st: m.st, doc: m.doc}, // Copy so it may be freely refreshed
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *firewallRulesWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// isForMachine returns whether the IP address document with the
+// specified id belongs to the watched machine.
+func (w *firewallRulesWatcher) isForMachine(id interface{}) bool {
+	localID, err := w.st.strictLocalID(id.(string))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(localID, w.machine.globalKey()+"#")
+}
+
+func (w *firewallRulesWatcher) loop() (err error) {
+	machines, closer := w.st.getCollection(machinesC)
+	revno, err := getTxnRevno(machines, w.machine.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	machineIn := make(chan watcher.Change)
+	w.st.watcher.Watch(machinesC, w.machine.doc.DocID, revno, machineIn)
+	defer w.st.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineIn)
+	addressesIn := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(ipAddressesC, addressesIn, w.isForMachine)
+	defer w.st.watcher.UnwatchCollection(ipAddressesC, addressesIn)
+	rulesIn := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(firewallRulesC, rulesIn, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(firewallRulesC, rulesIn)
+	subnetsIn := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(subnetsC, subnetsIn, w.st.isForStateEnv)
+	defer w.st.watcher.UnwatchCollection(subnetsC, subnetsIn)
+
+	addresses := w.machine.Addresses()
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case <-machineIn:
+			if err := w.machine.Refresh(); err != nil {
+				return err
+			}
+			newAddresses := w.machine.Addresses()
+			if !addressesEqual(newAddresses, addresses) {
+				addresses = newAddresses
+				out = w.out
+			}
+		case ch := <-addressesIn:
+			if _, ok := collect(ch, addressesIn, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case ch := <-rulesIn:
+			if _, ok := collect(ch, rulesIn, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case ch := <-subnetsIn:
+			if _, ok := collect(ch, subnetsIn, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // actionStatusWatcher is a StringsWatcher that filters notifications
 // to Action Id's that match the ActionReceiver and ActionStatus set
 // provided.
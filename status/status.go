@@ -179,6 +179,20 @@ const (
 	StatusAvailable Status = "available"
 )
 
+const (
+	// Status values specific to unit health, as evaluated by the
+	// health checks declared by a unit's charm.
+
+	// StatusHealthy indicates that all of the unit's health checks
+	// are passing.
+	StatusHealthy Status = "healthy"
+
+	// StatusUnhealthy indicates that at least one of the unit's
+	// health checks has failed more times in a row than its
+	// threshold allows.
+	StatusUnhealthy Status = "unhealthy"
+)
+
 const (
 	// Status values that are common to several entities.
 
@@ -290,6 +304,20 @@ func ValidModelStatus(status Status) bool {
 	}
 }
 
+// ValidHealthStatus returns true if status has a valid value (that is to say,
+// a value that it's OK to set) for unit health.
+func ValidHealthStatus(status Status) bool {
+	switch status {
+	case
+		StatusHealthy,
+		StatusUnhealthy,
+		StatusUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
 // Matches returns true if the candidate matches status,
 // taking into account that the candidate may be a legacy
 // status value which has been deprecated.
@@ -52,3 +52,32 @@ type FilesystemAttachmentInfo struct {
 	// ReadOnly indicates that the filesystem is mounted read-only.
 	ReadOnly bool
 }
+
+// FilesystemUsage describes the space and inode usage of a filesystem
+// mounted on a machine, as reported by the machine.
+type FilesystemUsage struct {
+	// MountPoint is the path at which the filesystem is mounted.
+	MountPoint string `yaml:"mountpoint"`
+
+	// Size is the total size of the filesystem, in bytes.
+	Size uint64 `yaml:"size"`
+
+	// Used is the number of bytes used on the filesystem.
+	Used uint64 `yaml:"used"`
+
+	// Available is the number of bytes available to unprivileged
+	// users on the filesystem. Because some space may be reserved
+	// for privileged users, Used+Available may be less than Size.
+	Available uint64 `yaml:"available"`
+
+	// Inodes is the total number of inodes on the filesystem.
+	// Some filesystems allocate inodes dynamically, in which
+	// case Inodes, InodesUsed and InodesFree will be zero.
+	Inodes uint64 `yaml:"inodes,omitempty"`
+
+	// InodesUsed is the number of inodes in use on the filesystem.
+	InodesUsed uint64 `yaml:"inodes-used,omitempty"`
+
+	// InodesFree is the number of free inodes on the filesystem.
+	InodesFree uint64 `yaml:"inodes-free,omitempty"`
+}
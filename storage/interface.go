@@ -62,6 +62,26 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// SharedFilesystemProvider is an optional interface that a Provider may
+// implement if the filesystems it creates may be attached to multiple
+// machines at once. Such filesystems are scoped to the model, but must
+// be attached (i.e. mounted) by the storage provisioner running on each
+// machine.
+type SharedFilesystemProvider interface {
+	Provider
+
+	// SharedFilesystems reports whether or not filesystems created
+	// by the provider may be attached to multiple machines at once.
+	SharedFilesystems() bool
+}
+
+// IsSharedFilesystemProvider reports whether or not the given
+// provider creates filesystems that may be shared between machines.
+func IsSharedFilesystemProvider(p Provider) bool {
+	shared, ok := p.(SharedFilesystemProvider)
+	return ok && shared.SharedFilesystems()
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
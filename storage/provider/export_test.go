@@ -33,6 +33,12 @@ func LoopProvider(
 	return &loopProvider{run}
 }
 
+func LVMProvider(
+	run func(string, ...string) (string, error),
+) storage.Provider {
+	return &lvmProvider{run}
+}
+
 func NewMockManagedFilesystemSource(
 	run func(string, ...string) (string, error),
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
@@ -125,6 +131,20 @@ func TmpfsFilesystemSource(storageDir string, run func(string, ...string) (strin
 	}
 }
 
+func NFSFilesystemSource(run func(string, ...string) (string, error)) storage.FilesystemSource {
+	return &nfsFilesystemSource{
+		&MockDirFuncs{
+			osDirFuncs{run},
+			set.NewStrings(),
+		},
+		run,
+	}
+}
+
+func NFSProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &nfsProvider{run}
+}
+
 func TmpfsProvider(run func(string, ...string) (string, error)) storage.Provider {
 	return &tmpfsProvider{run}
 }
@@ -0,0 +1,356 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVMProviderType is the provider type for the lvm provider,
+	// which creates logical volumes in a volume group on the
+	// machine.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the storage config attribute
+	// that specifies the LVM volume group in which to create
+	// logical volumes. The volume group must already exist on
+	// the machine. If it is not specified, LVMDefaultVolumeGroup
+	// is used, so that the "lvm" provider may be used as a pool
+	// without any configuration.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMDefaultVolumeGroup is the volume group used when none
+	// is specified in the storage config.
+	LVMDefaultVolumeGroup = "juju"
+
+	// LVMThinPool is the name of the storage config attribute
+	// that specifies an existing thin pool in the volume group.
+	// If specified, logical volumes will be thinly provisioned
+	// from the pool.
+	LVMThinPool = "thin-pool"
+)
+
+// lvmProvider creates volume sources which use LVM logical volumes.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newLVMConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	lvmConfig, err := newLVMConfig(sourceConfig.Attrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &lvmVolumeSource{p.run, lvmConfig}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *lvmProvider) FilesystemSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// lvmConfig holds the validated configuration for an lvm volume source.
+type lvmConfig struct {
+	volumeGroup string
+	thinPool    string
+}
+
+func newLVMConfig(attrs map[string]interface{}) (*lvmConfig, error) {
+	var cfg lvmConfig
+	for _, attr := range []struct {
+		name string
+		dest *string
+	}{
+		{LVMVolumeGroup, &cfg.volumeGroup},
+		{LVMThinPool, &cfg.thinPool},
+	} {
+		value, ok := attrs[attr.name]
+		if !ok {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf(
+				"expected string for %q, got %T", attr.name, value,
+			)
+		}
+		if err := validateLVMName(s); err != nil {
+			return nil, errors.Annotatef(err, "validating %q", attr.name)
+		}
+		*attr.dest = s
+	}
+	if cfg.volumeGroup == "" {
+		cfg.volumeGroup = LVMDefaultVolumeGroup
+	}
+	return &cfg, nil
+}
+
+// validateLVMName checks that the given volume group or logical
+// volume name is acceptable to LVM. We are deliberately conservative
+// here, as the names are passed on the command line.
+func validateLVMName(name string) error {
+	if name == "" {
+		return errors.New("name must not be empty")
+	}
+	if name == "." || name == ".." || strings.HasPrefix(name, "-") {
+		return errors.Errorf("invalid name %q", name)
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '+', r == '_', r == '.', r == '-':
+		default:
+			return errors.Errorf("invalid character %q in name %q", r, name)
+		}
+	}
+	return nil
+}
+
+// lvmVolumeSource provides an implementation of storage.VolumeSource
+// that creates LVM logical volumes in a volume group on the machine.
+type lvmVolumeSource struct {
+	run    runCommandFunc
+	config *lvmConfig
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(args))
+	for i, arg := range args {
+		volume, err := s.createVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotate(err, "creating volume")
+			continue
+		}
+		results[i].Volume = &volume
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
+	volumeId := params.Tag.String()
+	args := []string{"--yes", "-n", volumeId}
+	if s.config.thinPool != "" {
+		args = append(args,
+			"-V", fmt.Sprintf("%dm", params.Size),
+			"-T", s.config.volumeGroup+"/"+s.config.thinPool,
+		)
+	} else {
+		args = append(args,
+			"-L", fmt.Sprintf("%dm", params.Size),
+			s.config.volumeGroup,
+		)
+	}
+	if _, err := s.run("lvcreate", args...); err != nil {
+		return storage.Volume{}, errors.Annotatef(
+			err, "creating logical volume %q", volumeId,
+		)
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     params.Size,
+		},
+	}, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ListVolumes() ([]string, error) {
+	logicalVolumes, err := s.listLogicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := make([]string, 0, len(logicalVolumes))
+	for name := range logicalVolumes {
+		if _, err := names.ParseVolumeTag(name); err != nil {
+			// Not created by Juju.
+			continue
+		}
+		volumeIds = append(volumeIds, name)
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	logicalVolumes, err := s.listLogicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := logicalVolumes[volumeId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("logical volume %q", volumeId)
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// listLogicalVolumes returns the names and sizes, in MiB, of the
+// logical volumes in the configured volume group.
+func (s *lvmVolumeSource) listLogicalVolumes() (map[string]uint64, error) {
+	stdout, err := s.run(
+		"lvs", "--noheadings", "--nosuffix",
+		"--units", "m", "--separator", ":",
+		"-o", "lv_name,lv_size",
+		s.config.volumeGroup,
+	)
+	if err != nil {
+		return nil, errors.Annotatef(
+			err, "listing logical volumes in %q", s.config.volumeGroup,
+		)
+	}
+	logicalVolumes := make(map[string]uint64)
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 2 {
+			return nil, errors.Errorf("unexpected output %q", line)
+		}
+		size, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of %q", fields[0])
+		}
+		logicalVolumes[fields[0]] = uint64(size)
+	}
+	return logicalVolumes, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DestroyVolumes(volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		if err := s.destroyVolume(volumeId); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) destroyVolume(volumeId string) error {
+	if _, err := names.ParseVolumeTag(volumeId); err != nil {
+		return errors.Errorf("invalid lvm volume ID %q", volumeId)
+	}
+	_, err := s.run("lvremove", "-f", s.logicalVolumePath(volumeId))
+	if err != nil {
+		return errors.Annotate(err, "removing logical volume")
+	}
+	return nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the logical volume will be created, so we cannot
+	// check the volume group until we get to CreateVolumes.
+	return nil
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachVolume(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+			continue
+		}
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (*storage.VolumeAttachment, error) {
+	// Logical volumes are always attached to the machine on which
+	// they are created; we just need to make sure it is activated.
+	volumeId := arg.Volume.String()
+	if _, err := s.run("lvchange", "-a", "y", s.logicalVolumePath(volumeId)); err != nil {
+		return nil, errors.Annotate(err, "activating logical volume")
+	}
+	if arg.ReadOnly {
+		if _, err := s.run("lvchange", "-p", "r", s.logicalVolumePath(volumeId)); err != nil {
+			return nil, errors.Annotate(err, "setting logical volume read-only")
+		}
+	}
+	return &storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			// The kernel device name (dm-N) may change
+			// across reboots, but the link will not.
+			DeviceLink: path.Join("/dev", s.logicalVolumePath(volumeId)),
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (s *lvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		_, err := s.run("lvchange", "-a", "n", s.logicalVolumePath(arg.Volume.String()))
+		if err != nil {
+			results[i] = errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return results, nil
+}
+
+// logicalVolumePath returns the "<vg>/<lv>" path used to identify
+// the logical volume with the given name to the LVM tools.
+func (s *lvmVolumeSource) logicalVolumePath(volumeId string) string {
+	return s.config.volumeGroup + "/" + volumeId
+}
@@ -0,0 +1,395 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	if s.commands != nil {
+		s.commands.assertDrained()
+	}
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) lvmProvider(c *gc.C) storage.Provider {
+	s.commands = &mockRunCommand{c: c}
+	return provider.LVMProvider(s.commands.run)
+}
+
+func (s *lvmSuite) lvmVolumeSource(c *gc.C, attrs map[string]interface{}) storage.VolumeSource {
+	p := s.lvmProvider(c)
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := s.lvmProvider(c)
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{},
+	}, {
+		attrs: map[string]interface{}{"volume-group": 123},
+		err:   `expected string for "volume-group", got int`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "a/b"},
+		err:   `validating "volume-group": invalid character '/' in name "a/b"`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "-vg"},
+		err:   `validating "volume-group": invalid name "-vg"`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg", "thin-pool": ""},
+		err:   `validating "thin-pool": name must not be empty`,
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg"},
+	}, {
+		attrs: map[string]interface{}{"volume-group": "vg", "thin-pool": "pool"},
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("name", provider.LVMProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *lvmSuite) TestVolumeSourceInvalidConfig(c *gc.C) {
+	p := s.lvmProvider(c)
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "a/b",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.VolumeSource(nil, cfg)
+	c.Assert(err, gc.ErrorMatches, `validating "volume-group": invalid character '/' in name "a/b"`)
+}
+
+func (s *lvmSuite) TestCreateVolumesDefaultVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{})
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-0", "-L", "1024m", "juju")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestFilesystemSource(c *gc.C) {
+	p := s.lvmProvider(c)
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "vg",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.FilesystemSource(nil, cfg)
+	c.Assert(err, gc.ErrorMatches, "filesystems not supported")
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := s.lvmProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *lvmSuite) TestScope(c *gc.C) {
+	p := s.lvmProvider(c)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+}
+
+func (s *lvmSuite) TestDynamic(c *gc.C) {
+	p := s.lvmProvider(c)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *lvmSuite) TestCreateVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-0", "-L", "2048m", "vg")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment, gc.IsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0"),
+		storage.VolumeInfo{
+			VolumeId: "volume-0",
+			Size:     2048,
+		},
+	})
+}
+
+func (s *lvmSuite) TestCreateVolumesThinPool(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{
+		"volume-group": "vg",
+		"thin-pool":    "pool",
+	})
+	s.commands.expect("lvcreate", "--yes", "-n", "volume-0-1", "-V", "1024m", "-T", "vg/pool")
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0/1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "volume-0-1")
+}
+
+func (s *lvmSuite) TestCreateVolumesError(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	cmd := s.commands.expect("lvcreate", "--yes", "-n", "volume-0", "-L", "2048m", "vg")
+	cmd.respond("", errors.New("insufficient free space"))
+
+	results, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches,
+		`creating volume: creating logical volume "volume-0": insufficient free space`,
+	)
+	c.Assert(results[0].Volume, gc.IsNil)
+}
+
+func (s *lvmSuite) expectListLogicalVolumes() {
+	cmd := s.commands.expect(
+		"lvs", "--noheadings", "--nosuffix",
+		"--units", "m", "--separator", ":",
+		"-o", "lv_name,lv_size", "vg",
+	)
+	cmd.respond("  root:10240.00\n  volume-0:2048.00\n  volume-1-2:512.00\n", nil)
+}
+
+func (s *lvmSuite) TestListVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.expectListLogicalVolumes()
+
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.SameContents, []string{"volume-0", "volume-1-2"})
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.expectListLogicalVolumes()
+
+	results, err := source.DescribeVolumes([]string{"volume-1-2", "volume-3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "volume-1-2",
+		Size:     512,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `logical volume "volume-3" not found`)
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.commands.expect("lvremove", "-f", "vg/volume-0")
+
+	errs, err := source.DestroyVolumes([]string{"volume-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestDestroyVolumesInvalidVolumeId(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	errs, err := source.DestroyVolumes([]string{"../root"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying "../root": invalid lvm volume ID "../root"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.commands.expect("lvchange", "-a", "y", "vg/volume-0")
+	s.commands.expect("lvchange", "-a", "y", "vg/volume-1")
+	s.commands.expect("lvchange", "-p", "r", "vg/volume-1")
+
+	results, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		Volume: names.NewVolumeTag("0"),
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}, {
+		Volume: names.NewVolumeTag("1"),
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachVolumesResult{{
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("0"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/vg/volume-0",
+			},
+		},
+	}, {
+		VolumeAttachment: &storage.VolumeAttachment{
+			names.NewVolumeTag("1"),
+			names.NewMachineTag("0"),
+			storage.VolumeAttachmentInfo{
+				DeviceLink: "/dev/vg/volume-1",
+				ReadOnly:   true,
+			},
+		},
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource(c, map[string]interface{}{"volume-group": "vg"})
+	s.commands.expect("lvchange", "-a", "n", "vg/volume-0")
+
+	errs, err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume: names.NewVolumeTag("0"),
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+// lvmLoopSuite exercises the lvm provider against a real volume group,
+// created on a loop device. It must be run as root.
+type lvmLoopSuite struct {
+	testing.BaseSuite
+	volumeGroup string
+}
+
+var _ = gc.Suite(&lvmLoopSuite{})
+
+func (s *lvmLoopSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	if runtime.GOOS != "linux" {
+		c.Skip("not running linux")
+	}
+	if os.Getuid() != 0 {
+		c.Skip("not running as root")
+	}
+	for _, command := range []string{"losetup", "vgcreate", "lvcreate"} {
+		if _, err := exec.LookPath(command); err != nil {
+			c.Skip(command + " not available")
+		}
+	}
+
+	backingFile := filepath.Join(c.MkDir(), "pv")
+	f, err := os.Create(backingFile)
+	c.Assert(err, jc.ErrorIsNil)
+	err = f.Truncate(64 * 1024 * 1024)
+	f.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := exec.Command("losetup", "-f", "--show", backingFile).CombinedOutput()
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("%s", out))
+	loopDevice := strings.TrimSpace(string(out))
+	s.AddCleanup(func(c *gc.C) {
+		out, err := exec.Command("losetup", "-d", loopDevice).CombinedOutput()
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("%s", out))
+	})
+
+	s.volumeGroup = fmt.Sprintf("juju-test-%d", os.Getpid())
+	out, err = exec.Command("vgcreate", s.volumeGroup, loopDevice).CombinedOutput()
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("%s", out))
+	s.AddCleanup(func(c *gc.C) {
+		out, err := exec.Command("vgremove", "-f", s.volumeGroup).CombinedOutput()
+		c.Check(err, jc.ErrorIsNil, gc.Commentf("%s", out))
+	})
+}
+
+func (s *lvmLoopSuite) TestVolumeLifecycle(c *gc.C) {
+	p := provider.CommonProviders()[provider.LVMProviderType]
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": s.volumeGroup,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(nil, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volumeTag := names.NewVolumeTag("0")
+	created, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  volumeTag,
+		Size: 8,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(created, gc.HasLen, 1)
+	c.Assert(created[0].Error, jc.ErrorIsNil)
+
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"volume-0"})
+
+	described, err := source.DescribeVolumes(volumeIds)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(described, gc.HasLen, 1)
+	c.Assert(described[0].Error, jc.ErrorIsNil)
+	c.Assert(described[0].VolumeInfo.Size, gc.Equals, uint64(8))
+
+	attachment := storage.VolumeAttachmentParams{
+		Volume:   volumeTag,
+		VolumeId: "volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}
+	attached, err := source.AttachVolumes([]storage.VolumeAttachmentParams{attachment})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attached[0].Error, jc.ErrorIsNil)
+	deviceLink := attached[0].VolumeAttachment.DeviceLink
+	_, err = os.Stat(deviceLink)
+	c.Assert(err, jc.ErrorIsNil)
+
+	detachErrs, err := source.DetachVolumes([]storage.VolumeAttachmentParams{attachment})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(detachErrs[0], jc.ErrorIsNil)
+
+	destroyErrs, err := source.DestroyVolumes(volumeIds)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(destroyErrs[0], jc.ErrorIsNil)
+	volumeIds, err = source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, gc.HasLen, 0)
+}
@@ -0,0 +1,250 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// NFSProviderType is the provider type for the nfs provider,
+	// which provides filesystems backed by an existing NFS export.
+	// NFS filesystems may be shared by multiple machines.
+	NFSProviderType = storage.ProviderType("nfs")
+
+	// NFSServer is the name of the storage config attribute that
+	// specifies the host name or address of the NFS server.
+	NFSServer = "server"
+
+	// NFSExport is the name of the storage config attribute that
+	// specifies the absolute path of the directory exported by
+	// the NFS server.
+	NFSExport = "export"
+)
+
+// nfsProvider creates filesystem sources which mount NFS exports.
+type nfsProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var (
+	_ storage.Provider                 = (*nfsProvider)(nil)
+	_ storage.SharedFilesystemProvider = (*nfsProvider)(nil)
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (*nfsProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newNFSConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// VolumeSource is defined on the Provider interface.
+func (*nfsProvider) VolumeSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *nfsProvider) FilesystemSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	// The server and export are taken from the filesystem
+	// parameters, which are derived from the pool config;
+	// the source itself has no configuration.
+	return &nfsFilesystemSource{
+		&osDirFuncs{p.run},
+		p.run,
+	}, nil
+}
+
+// Supports is defined on the Provider interface.
+func (*nfsProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindFilesystem
+}
+
+// Scope is defined on the Provider interface.
+//
+// NFS filesystems exist independently of any machine, and so are
+// model-scoped. Attachment is performed by the storage provisioner
+// on each machine; see SharedFilesystems.
+func (*nfsProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (*nfsProvider) Dynamic() bool {
+	return true
+}
+
+// SharedFilesystems is defined on the SharedFilesystemProvider interface.
+func (*nfsProvider) SharedFilesystems() bool {
+	return true
+}
+
+// nfsConfig holds the validated configuration for an nfs pool.
+type nfsConfig struct {
+	server string
+	export string
+}
+
+func newNFSConfig(attrs map[string]interface{}) (*nfsConfig, error) {
+	server, err := nfsConfigString(attrs, NFSServer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if strings.ContainsAny(server, ":/ ") {
+		return nil, errors.Errorf("invalid %q %q", NFSServer, server)
+	}
+	export, err := nfsConfigString(attrs, NFSExport)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !strings.HasPrefix(export, "/") {
+		return nil, errors.Errorf("%q must be an absolute path, got %q", NFSExport, export)
+	}
+	return &nfsConfig{server, export}, nil
+}
+
+func nfsConfigString(attrs map[string]interface{}, name string) (string, error) {
+	value, ok := attrs[name]
+	if !ok {
+		return "", errors.Errorf("%q not specified", name)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("expected string for %q, got %T", name, value)
+	}
+	if s == "" {
+		return "", errors.Errorf("%q not specified", name)
+	}
+	return s, nil
+}
+
+// source returns the "server:/export" source of the NFS mount.
+func (c *nfsConfig) source() string {
+	return c.server + ":" + c.export
+}
+
+// nfsFilesystemSource is an implementation of storage.FilesystemSource
+// that mounts existing NFS exports.
+type nfsFilesystemSource struct {
+	dirFuncs dirFuncs
+	run      runCommandFunc
+}
+
+var _ storage.FilesystemSource = (*nfsFilesystemSource)(nil)
+
+// ValidateFilesystemParams is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
+	_, err := newNFSConfig(params.Attributes)
+	return errors.Trace(err)
+}
+
+// CreateFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) CreateFilesystems(args []storage.FilesystemParams) ([]storage.CreateFilesystemsResult, error) {
+	results := make([]storage.CreateFilesystemsResult, len(args))
+	for i, arg := range args {
+		// The export already exists on the NFS server; Juju
+		// just records where it is so it can be mounted.
+		cfg, err := newNFSConfig(arg.Attributes)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
+			continue
+		}
+		results[i].Filesystem = &storage.Filesystem{
+			arg.Tag,
+			arg.Volume,
+			storage.FilesystemInfo{
+				FilesystemId: cfg.source(),
+				Size:         arg.Size,
+			},
+		}
+	}
+	return results, nil
+}
+
+// DestroyFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DestroyFilesystems(filesystemIds []string) ([]error, error) {
+	// The export is managed outside of Juju, so there
+	// is nothing to destroy; it is merely forgotten.
+	return make([]error, len(filesystemIds)), nil
+}
+
+// AttachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) AttachFilesystems(args []storage.FilesystemAttachmentParams) ([]storage.AttachFilesystemsResult, error) {
+	results := make([]storage.AttachFilesystemsResult, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachFilesystem(arg)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].FilesystemAttachment = attachment
+	}
+	return results, nil
+}
+
+func (s *nfsFilesystemSource) attachFilesystem(arg storage.FilesystemAttachmentParams) (*storage.FilesystemAttachment, error) {
+	path := arg.Path
+	if path == "" {
+		return nil, errNoMountPoint
+	}
+	if arg.FilesystemId == "" {
+		return nil, errors.Errorf("filesystem %v not provisioned", arg.Filesystem.Id())
+	}
+	if err := ensureDir(s.dirFuncs, path); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Check if the mount already exists.
+	source, err := s.dirFuncs.mountPointSource(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if source != arg.FilesystemId {
+		if err := ensureEmptyDir(s.dirFuncs, path); err != nil {
+			return nil, err
+		}
+		options := "rw"
+		if arg.ReadOnly {
+			options = "ro"
+		}
+		if _, err := s.run(
+			"mount", "-t", "nfs", arg.FilesystemId, path, "-o", options,
+		); err != nil {
+			os.Remove(path)
+			return nil, errors.Annotate(err, "cannot mount nfs export")
+		}
+	}
+
+	return &storage.FilesystemAttachment{
+		arg.Filesystem,
+		arg.Machine,
+		storage.FilesystemAttachmentInfo{
+			Path:     path,
+			ReadOnly: arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DetachFilesystems(args []storage.FilesystemAttachmentParams) ([]error, error) {
+	results := make([]error, len(args))
+	for i, arg := range args {
+		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
+			results[i] = err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&nfsSuite{})
+
+type nfsSuite struct {
+	testing.BaseSuite
+	commands *mockRunCommand
+}
+
+func (s *nfsSuite) TearDownTest(c *gc.C) {
+	if s.commands != nil {
+		s.commands.assertDrained()
+	}
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *nfsSuite) nfsProvider(c *gc.C) storage.Provider {
+	s.commands = &mockRunCommand{c: c}
+	return provider.NFSProvider(s.commands.run)
+}
+
+func (s *nfsSuite) nfsFilesystemSource(c *gc.C) storage.FilesystemSource {
+	s.commands = &mockRunCommand{c: c}
+	return provider.NFSFilesystemSource(s.commands.run)
+}
+
+func (s *nfsSuite) TestValidateConfig(c *gc.C) {
+	p := s.nfsProvider(c)
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{},
+		err:   `"server" not specified`,
+	}, {
+		attrs: map[string]interface{}{"server": "nas", "export": ""},
+		err:   `"export" not specified`,
+	}, {
+		attrs: map[string]interface{}{"server": 42, "export": "/srv"},
+		err:   `expected string for "server", got int`,
+	}, {
+		attrs: map[string]interface{}{"server": "nas:/srv", "export": "/srv"},
+		err:   `invalid "server" "nas:/srv"`,
+	}, {
+		attrs: map[string]interface{}{"server": "nas", "export": "srv"},
+		err:   `"export" must be an absolute path, got "srv"`,
+	}, {
+		attrs: map[string]interface{}{"server": "10.0.0.1", "export": "/srv/uploads"},
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("name", provider.NFSProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *nfsSuite) TestSupports(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestScope(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+}
+
+func (s *nfsSuite) TestShared(c *gc.C) {
+	p := s.nfsProvider(c)
+	c.Assert(storage.IsSharedFilesystemProvider(p), jc.IsTrue)
+	c.Assert(storage.IsSharedFilesystemProvider(provider.TmpfsProvider(nil)), jc.IsFalse)
+}
+
+func (s *nfsSuite) TestVolumeSource(c *gc.C) {
+	p := s.nfsProvider(c)
+	_, err := p.VolumeSource(nil, nil)
+	c.Assert(err, gc.ErrorMatches, "volumes not supported")
+}
+
+func (s *nfsSuite) TestCreateFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	results, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:  names.NewFilesystemTag("6"),
+		Size: 1024,
+		Attributes: map[string]interface{}{
+			"server": "nas",
+			"export": "/srv/uploads",
+		},
+	}, {
+		Tag:        names.NewFilesystemTag("7"),
+		Size:       1024,
+		Attributes: map[string]interface{}{},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Filesystem, jc.DeepEquals, &storage.Filesystem{
+		Tag: names.NewFilesystemTag("6"),
+		FilesystemInfo: storage.FilesystemInfo{
+			FilesystemId: "nas:/srv/uploads",
+			Size:         1024,
+		},
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `"server" not specified`)
+}
+
+func (s *nfsSuite) TestDestroyFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	errs, err := source.DestroyFilesystems([]string{"nas:/srv/uploads"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+}
+
+func (s *nfsSuite) TestAttachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "/srv/app/uploads")
+	cmd.respond("header\n/dev/sda1", nil)
+	s.commands.expect("mount", "-t", "nfs", "nas:/srv/uploads", "/srv/app/uploads", "-o", "ro")
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("6"),
+		FilesystemId: "nas:/srv/uploads",
+		Path:         "/srv/app/uploads",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("2"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.AttachFilesystemsResult{{
+		FilesystemAttachment: &storage.FilesystemAttachment{
+			Filesystem: names.NewFilesystemTag("6"),
+			Machine:    names.NewMachineTag("2"),
+			FilesystemAttachmentInfo: storage.FilesystemAttachmentInfo{
+				Path:     "/srv/app/uploads",
+				ReadOnly: true,
+			},
+		},
+	}})
+}
+
+func (s *nfsSuite) TestAttachFilesystemsAlreadyMounted(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "exists")
+	cmd.respond("header\nnas:/srv/uploads", nil)
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("6"),
+		FilesystemId: "nas:/srv/uploads",
+		Path:         "exists",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsNotProvisioned(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem: names.NewFilesystemTag("6"),
+		Path:       "/srv/app/uploads",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "filesystem 6 not provisioned")
+}
+
+func (s *nfsSuite) TestAttachFilesystemsMountFails(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	cmd := s.commands.expect("df", "--output=source", "/srv/app/uploads")
+	cmd.respond("header\n/dev/sda1", nil)
+	cmd = s.commands.expect("mount", "-t", "nfs", "nas:/srv/uploads", "/srv/app/uploads", "-o", "rw")
+	cmd.respond("", errors.New("access denied by server"))
+
+	results, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("6"),
+		FilesystemId: "nas:/srv/uploads",
+		Path:         "/srv/app/uploads",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "cannot mount nfs export: access denied by server")
+}
+
+func (s *nfsSuite) TestDetachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, true)
+}
+
+func (s *nfsSuite) TestDetachFilesystemsUnattached(c *gc.C) {
+	source := s.nfsFilesystemSource(c)
+	testDetachFilesystems(c, s.commands, source, false)
+}
@@ -91,6 +91,10 @@ const (
 
 const DefaultMongoPassword = "conn-from-name-secret"
 
+// SecretsKey is the controller secrets key used in tests, in the form
+// returned by state.NewSecretsKey.
+const SecretsKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
 // FakeJujuXDGDataHomeSuite isolates the user's home directory and
 // sets up a Juju home with a sample environment and certificate.
 type FakeJujuXDGDataHomeSuite struct {
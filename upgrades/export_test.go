@@ -8,6 +8,12 @@ import "github.com/juju/juju/environs"
 var (
 	UpgradeOperations      = &upgradeOperations
 	StateUpgradeOperations = &stateUpgradeOperations
+	RecordIrreversibleStep = &recordIrreversibleStep
+	Now                    = &now
+	EnsureSecretsKey       = ensureSecretsKey
+	CopySecretsKey         = copySecretsKey
+	MasterHostPort         = &masterHostPort
+	APIOpen                = &apiOpen
 )
 
 type ModelConfigUpdater environConfigUpdater
@@ -0,0 +1,91 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+
+	"github.com/juju/juju/api"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/state"
+)
+
+// ensureSecretsKey generates the key with which the controller
+// encrypts charm secrets, if the agent does not already have one. The
+// key is kept in the agent config rather than in state; the agent's
+// State connections read it from there once the step is done.
+func ensureSecretsKey(context Context) error {
+	config := context.AgentConfig()
+	info, ok := config.StateServingInfo()
+	if !ok {
+		return errors.New("no state serving info")
+	}
+	if info.SecretsKey != "" {
+		return nil
+	}
+	key, err := state.NewSecretsKey()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info.SecretsKey = key
+	config.SetStateServingInfo(info)
+	return nil
+}
+
+// masterHostPort returns the address of the master mongo server.
+// It is a variable so it can be patched in tests.
+var masterHostPort = func(st *state.State) (string, error) {
+	return replicaset.MasterHostPort(st.MongoSession())
+}
+
+// apiOpen is a variable so it can be patched in tests.
+var apiOpen = api.Open
+
+// copySecretsKey copies the secrets key generated by ensureSecretsKey
+// on the master controller into the agent config of another
+// controller, if it does not already have one. The other controllers
+// only run their upgrade steps once the master has finished, so the
+// key is always available from the master's API server by then.
+func copySecretsKey(context Context) error {
+	config := context.AgentConfig()
+	info, ok := config.StateServingInfo()
+	if !ok {
+		return errors.New("no state serving info")
+	}
+	if info.SecretsKey != "" {
+		return nil
+	}
+	hostPort, err := masterHostPort(context.State())
+	if err != nil {
+		return errors.Annotate(err, "cannot find master controller")
+	}
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	apiInfo, ok := config.APIInfo()
+	if !ok {
+		return errors.New("no API info")
+	}
+	apiInfo.Addrs = []string{net.JoinHostPort(host, strconv.Itoa(info.APIPort))}
+	conn, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to master controller")
+	}
+	defer conn.Close()
+	masterInfo, err := apiagent.NewState(conn).StateServingInfo()
+	if err != nil {
+		return errors.Annotate(err, "cannot get state serving info from master controller")
+	}
+	if masterInfo.SecretsKey == "" {
+		return errors.New("master controller has no secrets key")
+	}
+	info.SecretsKey = masterInfo.SecretsKey
+	config.SetStateServingInfo(info)
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type secretsKeySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&secretsKeySuite{})
+
+func (s *secretsKeySuite) TestEnsureSecretsKey(c *gc.C) {
+	config := &mockAgentConfig{servingInfo: params.StateServingInfo{APIPort: 1234}}
+	err := upgrades.EnsureSecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.servingInfo.APIPort, gc.Equals, 1234)
+	c.Check(config.servingInfo.SecretsKey, gc.Not(gc.Equals), "")
+}
+
+func (s *secretsKeySuite) TestEnsureSecretsKeyKeepsExisting(c *gc.C) {
+	config := &mockAgentConfig{servingInfo: params.StateServingInfo{SecretsKey: testing.SecretsKey}}
+	err := upgrades.EnsureSecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.servingInfo.SecretsKey, gc.Equals, testing.SecretsKey)
+}
+
+func (s *secretsKeySuite) patchMaster(c *gc.C, masterKey string) *[]string {
+	s.PatchValue(upgrades.MasterHostPort, func(*state.State) (string, error) {
+		return "10.0.0.1:37017", nil
+	})
+	var dialed []string
+	s.PatchValue(upgrades.APIOpen, func(info *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(info.Tag, gc.Equals, names.NewMachineTag("1"))
+		dialed = append(dialed, info.Addrs...)
+		return &mockAPIConnection{caller: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Agent")
+			c.Check(request, gc.Equals, "StateServingInfo")
+			*(result.(*params.StateServingInfo)) = params.StateServingInfo{SecretsKey: masterKey}
+			return nil
+		}}, nil
+	})
+	return &dialed
+}
+
+func (s *secretsKeySuite) TestCopySecretsKey(c *gc.C) {
+	dialed := s.patchMaster(c, testing.SecretsKey)
+	config := &mockAgentConfig{
+		tag:         names.NewMachineTag("1"),
+		servingInfo: params.StateServingInfo{APIPort: 17070},
+	}
+	err := upgrades.CopySecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*dialed, jc.DeepEquals, []string{"10.0.0.1:17070"})
+	c.Check(config.servingInfo.APIPort, gc.Equals, 17070)
+	c.Check(config.servingInfo.SecretsKey, gc.Equals, testing.SecretsKey)
+}
+
+func (s *secretsKeySuite) TestCopySecretsKeyKeepsExisting(c *gc.C) {
+	dialed := s.patchMaster(c, "")
+	config := &mockAgentConfig{servingInfo: params.StateServingInfo{SecretsKey: testing.SecretsKey}}
+	err := upgrades.CopySecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*dialed, gc.HasLen, 0)
+	c.Check(config.servingInfo.SecretsKey, gc.Equals, testing.SecretsKey)
+}
+
+func (s *secretsKeySuite) TestCopySecretsKeyMasterHasNone(c *gc.C) {
+	s.patchMaster(c, "")
+	config := &mockAgentConfig{tag: names.NewMachineTag("1")}
+	err := upgrades.CopySecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, gc.ErrorMatches, "master controller has no secrets key")
+	c.Check(config.servingInfo.SecretsKey, gc.Equals, "")
+}
+
+func (s *secretsKeySuite) TestCopySecretsKeyNoMaster(c *gc.C) {
+	s.PatchValue(upgrades.MasterHostPort, func(*state.State) (string, error) {
+		return "", errors.New("boom")
+	})
+	config := &mockAgentConfig{}
+	err := upgrades.CopySecretsKey(&mockContext{agentConfig: config})
+	c.Assert(err, gc.ErrorMatches, "cannot find master controller: boom")
+}
+
+type mockAPIConnection struct {
+	api.Connection
+	caller basetesting.APICallerFunc
+}
+
+func (c *mockAPIConnection) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	return c.caller(objType, version, id, request, args, response)
+}
+
+func (c *mockAPIConnection) BestFacadeVersion(facade string) int {
+	return 0
+}
+
+func (c *mockAPIConnection) Close() error {
+	return nil
+}
@@ -23,6 +23,9 @@ func stateStepsFor126() []Step {
 		&upgradeStep{
 			description: "add the version field to all settings docs",
 			targets:     []Target{DatabaseMaster},
+			// Settings are moved into a subdocument, which
+			// earlier agents cannot read.
+			irreversible: true,
 			run: func(context Context) error {
 				return state.MigrateSettingsSchema(context.State())
 			},
@@ -37,6 +40,9 @@ func stateStepsFor126() []Step {
 		&upgradeStep{
 			description: "upgrade model config",
 			targets:     []Target{DatabaseMaster},
+			// Attributes may be removed or renamed by the
+			// provider, and earlier agents may need them.
+			irreversible: true,
 			run: func(context Context) error {
 				// TODO(axw) updateModelConfig should be
 				// called for all upgrades, to decouple this
@@ -50,6 +56,7 @@ func stateStepsFor126() []Step {
 		&upgradeStep{
 			description: "provider side upgrades",
 			targets:     []Target{DatabaseMaster},
+			external:    true,
 			run: func(context Context) error {
 				st := context.State()
 				env, err := utils.GetEnviron(st)
@@ -73,5 +80,16 @@ func stateStepsFor126() []Step {
 				return state.AddDefaultEndpointBindingsToServices(context.State())
 			},
 		},
+		&upgradeStep{
+			description: "generate controller secrets key",
+			targets:     []Target{DatabaseMaster},
+			run:         ensureSecretsKey,
+		},
+		&upgradeStep{
+			description: "copy controller secrets key from master",
+			targets:     []Target{Controller},
+			external:    true,
+			run:         copySecretsKey,
+		},
 	}
 }
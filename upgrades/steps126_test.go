@@ -4,10 +4,12 @@
 package upgrades_test
 
 import (
+	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
 )
 
 type steps126Suite struct {
@@ -29,6 +31,26 @@ func (s *steps126Suite) TestStateStepsFor126(c *gc.C) {
 		"provider side upgrades",
 		"update machine preferred addresses",
 		"add default endpoint bindings to services",
+		"generate controller secrets key",
+		"copy controller secrets key from master",
 	}
 	assertStateSteps(c, version.MustParse("1.26.0"), expected)
 }
+
+func (s *steps126Suite) TestIrreversibleStateStepsFor126(c *gc.C) {
+	var irreversible []string
+	for _, op := range (*upgrades.StateUpgradeOperations)() {
+		if op.TargetVersion() != version.MustParse("1.26.0") {
+			continue
+		}
+		for _, step := range op.Steps() {
+			if step.Irreversible() {
+				irreversible = append(irreversible, step.Description())
+			}
+		}
+	}
+	c.Assert(irreversible, jc.DeepEquals, []string{
+		"add the version field to all settings docs",
+		"upgrade model config",
+	})
+}
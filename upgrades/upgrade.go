@@ -5,7 +5,9 @@ package upgrades
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
 )
@@ -21,6 +23,17 @@ type Step interface {
 	// Targets returns the target machine types for which the upgrade step is applicable.
 	Targets() []Target
 
+	// Irreversible reports whether the step changes the database in a
+	// way that agents running an earlier version of Juju cannot work
+	// with. An upgrade cannot be rolled back once such a step has run.
+	// Only state-based steps may be irreversible.
+	Irreversible() bool
+
+	// External reports whether the step acts on the cloud, rather than
+	// only on the database. External steps are not run when rehearsing
+	// an upgrade.
+	External() bool
+
 	// Run executes the upgrade business logic.
 	Run(Context) error
 }
@@ -93,15 +106,54 @@ func AreUpgradesDefined(from version.Number) bool {
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
 // version of Juju on the "target" type of machine.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
+	return performUpgrade(from, targets, context, nil, false)
+}
+
+// StepResult records the outcome of running a single upgrade step.
+type StepResult struct {
+	// Description is the description of the step.
+	Description string
+
+	// Irreversible reports whether the step was declared irreversible.
+	Irreversible bool
+
+	// NotSimulated reports whether the step was skipped by
+	// RehearseUpgrade because it would act outside the database.
+	NotSimulated bool
+
+	// Duration is how long the step took to run.
+	Duration time.Duration
+
+	// Err holds the error returned by the step, if any.
+	Err error
+}
+
+// RehearseUpgrade runs the upgrade steps as PerformUpgrade does, and
+// also returns the outcome of every step, in order. It is intended to
+// be run against a copy of the database, to find out how an upgrade
+// would behave, so only steps that act on the database are run. Steps
+// that are external, or that apply to the machine rather than to the
+// controller, are reported with NotSimulated set instead.
+func RehearseUpgrade(from version.Number, targets []Target, context Context) ([]StepResult, error) {
+	var results []StepResult
+	err := performUpgrade(from, targets, context, func(result StepResult) {
+		results = append(results, result)
+	}, true)
+	return results, err
+}
+
+// performUpgrade implements PerformUpgrade and RehearseUpgrade,
+// calling report, if it is not nil, with the outcome of each step.
+func performUpgrade(from version.Number, targets []Target, context Context, report func(StepResult), rehearse bool) error {
 	if hasStateTarget(targets) {
 		ops := newStateUpgradeOpsIterator(from)
-		if err := runUpgradeSteps(ops, targets, context.StateContext()); err != nil {
+		if err := runUpgradeSteps(ops, targets, context.StateContext(), report, rehearse); err != nil {
 			return err
 		}
 	}
 
 	ops := newUpgradeOpsIterator(from)
-	if err := runUpgradeSteps(ops, targets, context.APIContext()); err != nil {
+	if err := runUpgradeSteps(ops, targets, context.APIContext(), report, rehearse); err != nil {
 		return err
 	}
 
@@ -118,6 +170,15 @@ func hasStateTarget(targets []Target) bool {
 	return false
 }
 
+// recordIrreversibleStep records, against the current upgrade, that
+// the irreversible step with the given description is about to run.
+var recordIrreversibleStep = func(context Context, description string) error {
+	return context.State().RecordIrreversibleUpgradeStep(description)
+}
+
+// now returns the current time, for timing upgrade steps.
+var now = time.Now
+
 // runUpgradeSteps finds all the upgrade operations relevant to
 // the targets given and runs the associated upgrade steps.
 //
@@ -125,12 +186,48 @@ func hasStateTarget(targets []Target) bool {
 // subsequent steps may required successful completion of earlier
 // ones. The steps must be idempotent so that the entire upgrade
 // operation can be retried.
-func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error {
+//
+// Irreversible steps are recorded before they are run, so that the
+// upgrade is not rolled back after one has started.
+//
+// If report is not nil, it is called with the outcome of each step.
+// If rehearse is true, steps that cannot be simulated are reported
+// but not run.
+func runUpgradeSteps(ops *opsIterator, targets []Target, context Context, report func(StepResult), rehearse bool) error {
 	for ops.Next() {
 		for _, step := range ops.Get().Steps() {
 			if targetsMatch(targets, step.Targets()) {
+				if rehearse && !canSimulate(step) {
+					logger.Infof("not simulating upgrade step: %v", step.Description())
+					if report != nil {
+						report(StepResult{
+							Description:  step.Description(),
+							Irreversible: step.Irreversible(),
+							NotSimulated: true,
+						})
+					}
+					continue
+				}
+				if step.Irreversible() {
+					if err := recordIrreversibleStep(context, step.Description()); err != nil {
+						return &upgradeError{
+							description: step.Description(),
+							err:         errors.Annotate(err, "cannot record irreversible step"),
+						}
+					}
+				}
 				logger.Infof("running upgrade step: %v", step.Description())
-				if err := step.Run(context); err != nil {
+				started := now()
+				err := step.Run(context)
+				if report != nil {
+					report(StepResult{
+						Description:  step.Description(),
+						Irreversible: step.Irreversible(),
+						Duration:     now().Sub(started),
+						Err:          err,
+					})
+				}
+				if err != nil {
 					logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
 					return &upgradeError{
 						description: step.Description(),
@@ -143,6 +240,23 @@ func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error
 	return nil
 }
 
+// canSimulate reports whether the step may be run when rehearsing an
+// upgrade: it must not be external, and it must be targeted at the
+// controller or the database master rather than at every machine or
+// at machines hosting units, since such steps act on the machine
+// itself.
+func canSimulate(step Step) bool {
+	if step.External() {
+		return false
+	}
+	for _, target := range step.Targets() {
+		if target == Controller || target == DatabaseMaster {
+			return true
+		}
+	}
+	return false
+}
+
 // targetsMatch returns true if any machineTargets match any of
 // stepTargets.
 func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
@@ -158,9 +272,11 @@ func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
 
 // upgradeStep is a default Step implementation.
 type upgradeStep struct {
-	description string
-	targets     []Target
-	run         func(Context) error
+	description  string
+	targets      []Target
+	irreversible bool
+	external     bool
+	run          func(Context) error
 }
 
 var _ Step = (*upgradeStep)(nil)
@@ -175,6 +291,16 @@ func (step *upgradeStep) Targets() []Target {
 	return step.targets
 }
 
+// Irreversible is defined on the Step interface.
+func (step *upgradeStep) Irreversible() bool {
+	return step.irreversible
+}
+
+// External is defined on the Step interface.
+func (step *upgradeStep) External() bool {
+	return step.external
+}
+
 // Run is defined on the Step interface.
 func (step *upgradeStep) Run(context Context) error {
 	return step.run(context)
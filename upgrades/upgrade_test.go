@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -89,8 +90,10 @@ func (m *mockUpgradeOperation) Steps() []upgrades.Step {
 }
 
 type mockUpgradeStep struct {
-	msg     string
-	targets []upgrades.Target
+	msg          string
+	targets      []upgrades.Target
+	irreversible bool
+	external     bool
 }
 
 func (u *mockUpgradeStep) Description() string {
@@ -101,6 +104,14 @@ func (u *mockUpgradeStep) Targets() []upgrades.Target {
 	return u.targets
 }
 
+func (u *mockUpgradeStep) Irreversible() bool {
+	return u.irreversible
+}
+
+func (u *mockUpgradeStep) External() bool {
+	return u.external
+}
+
 func (u *mockUpgradeStep) Run(ctx upgrades.Context) error {
 	if strings.HasSuffix(u.msg, "error") {
 		return errors.New("upgrade error occurred")
@@ -192,6 +203,10 @@ func (mock *mockAgentConfig) Value(name string) string {
 	return mock.values[name]
 }
 
+func (mock *mockAgentConfig) APIInfo() (*api.Info, bool) {
+	return &api.Info{Addrs: mock.apiAddresses, Tag: mock.tag}, true
+}
+
 func (mock *mockAgentConfig) MongoInfo() (*mongo.MongoInfo, bool) {
 	return mock.mongoInfo, true
 }
@@ -572,6 +587,14 @@ func (s *contextStep) Targets() []upgrades.Target {
 	return []upgrades.Target{upgrades.Controller}
 }
 
+func (s *contextStep) Irreversible() bool {
+	return false
+}
+
+func (s *contextStep) External() bool {
+	return false
+}
+
 func (s *contextStep) Run(context upgrades.Context) error {
 	if s.useAPI {
 		context.APIState()
@@ -655,6 +678,71 @@ func (s *upgradeSuite) TestStateStepsNotAttemptedWhenNoStateTarget(c *gc.C) {
 	check(upgrades.HostMachine, 0)
 }
 
+func (s *upgradeSuite) TestIrreversibleStepsRecorded(c *gc.C) {
+	irreversible := newUpgradeStep("state step 2 - 1.21.0", upgrades.DatabaseMaster)
+	irreversible.irreversible = true
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("state step 1 - 1.21.0", upgrades.DatabaseMaster),
+					irreversible,
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation { return nil })
+	ctx := &mockContext{}
+	s.PatchValue(upgrades.RecordIrreversibleStep, func(context upgrades.Context, description string) error {
+		c.Check(context, gc.Equals, ctx)
+		ctx.messages = append(ctx.messages, "recorded "+description)
+		return nil
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.messages, jc.DeepEquals, []string{
+		"state step 1 - 1.21.0",
+		"recorded state step 2 - 1.21.0",
+		"state step 2 - 1.21.0",
+	})
+}
+
+func (s *upgradeSuite) TestIrreversibleStepNotRunIfNotRecorded(c *gc.C) {
+	irreversible := newUpgradeStep("state step 1 - 1.21.0", upgrades.DatabaseMaster)
+	irreversible.irreversible = true
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{irreversible},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation { return nil })
+	s.PatchValue(upgrades.RecordIrreversibleStep, func(upgrades.Context, string) error {
+		return errors.New("boom")
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+
+	ctx := &mockContext{}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster), ctx)
+	c.Assert(err, gc.ErrorMatches, "state step 1 - 1.21.0: cannot record irreversible step: boom")
+	c.Assert(ctx.messages, gc.HasLen, 0)
+}
+
+func (s *upgradeSuite) TestAPIStepsNotIrreversible(c *gc.C) {
+	// Irreversible steps are recorded in state, which API-based
+	// steps do not have access to.
+	for _, op := range (*upgrades.UpgradeOperations)() {
+		for _, step := range op.Steps() {
+			c.Check(step.Irreversible(), jc.IsFalse, gc.Commentf("%s", step.Description()))
+		}
+	}
+}
+
 func (s *upgradeSuite) TestUpgradeOperationsOrdered(c *gc.C) {
 	var previous version.Number
 	for i, utv := range (*upgrades.UpgradeOperations)() {
@@ -692,3 +780,105 @@ func extractUpgradeVersions(c *gc.C, ops []upgrades.Operation) []string {
 	}
 	return versions
 }
+
+func (s *upgradeSuite) TestRehearseUpgrade(c *gc.C) {
+	irreversible := newUpgradeStep("state step 2 - 1.21.0", upgrades.DatabaseMaster)
+	irreversible.irreversible = true
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("state step 1 - 1.21.0", upgrades.DatabaseMaster),
+					irreversible,
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("api step 1 - 1.21.0 error", upgrades.Controller),
+					newUpgradeStep("api step 2 - 1.21.0", upgrades.Controller),
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.RecordIrreversibleStep, func(upgrades.Context, string) error {
+		return nil
+	})
+	clock := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.PatchValue(upgrades.Now, func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+
+	ctx := &mockContext{}
+	results, err := upgrades.RehearseUpgrade(version.MustParse("1.20.0"), targets(upgrades.DatabaseMaster, upgrades.Controller), ctx)
+	c.Assert(err, gc.ErrorMatches, "api step 1 - 1.21.0 error: upgrade error occurred")
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[0], jc.DeepEquals, upgrades.StepResult{
+		Description: "state step 1 - 1.21.0",
+		Duration:    time.Second,
+	})
+	c.Check(results[1], jc.DeepEquals, upgrades.StepResult{
+		Description:  "state step 2 - 1.21.0",
+		Irreversible: true,
+		Duration:     time.Second,
+	})
+	c.Check(results[2].Description, gc.Equals, "api step 1 - 1.21.0 error")
+	c.Check(results[2].Err, gc.ErrorMatches, "upgrade error occurred")
+	c.Check(ctx.messages, jc.DeepEquals, []string{"state step 1 - 1.21.0", "state step 2 - 1.21.0"})
+}
+
+func (s *upgradeSuite) TestRehearseUpgradeSkipsExternalAndHostSteps(c *gc.C) {
+	external := newUpgradeStep("state step 2 - 1.21.0", upgrades.DatabaseMaster)
+	external.external = true
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("state step 1 - 1.21.0", upgrades.DatabaseMaster),
+					external,
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					newUpgradeStep("api step 1 - 1.21.0", upgrades.Controller),
+					newUpgradeStep("api step 2 - 1.21.0", upgrades.HostMachine),
+					newUpgradeStep("api step 3 - 1.21.0", upgrades.AllMachines),
+				},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+
+	ctx := &mockContext{}
+	results, err := upgrades.RehearseUpgrade(
+		version.MustParse("1.20.0"),
+		targets(upgrades.DatabaseMaster, upgrades.Controller, upgrades.HostMachine),
+		ctx,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	var skipped []string
+	for _, result := range results {
+		if result.NotSimulated {
+			skipped = append(skipped, result.Description)
+		}
+	}
+	c.Check(skipped, jc.DeepEquals, []string{
+		"state step 2 - 1.21.0",
+		"api step 2 - 1.21.0",
+		"api step 3 - 1.21.0",
+	})
+	c.Check(ctx.messages, jc.DeepEquals, []string{"state step 1 - 1.21.0", "api step 1 - 1.21.0"})
+}
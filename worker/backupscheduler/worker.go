@@ -0,0 +1,184 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker that takes controller
+// backups on the schedule set in the controller model's config, and
+// removes old scheduled backups according to its retention settings.
+package backupscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// ConfigCheckPeriod is the longest time the worker waits before
+// checking the model config for changes to the backup schedule.
+const ConfigCheckPeriod = 5 * time.Minute
+
+// Config holds the dependencies and configuration of the worker.
+type Config struct {
+	// Clock is used to determine when to take backups.
+	Clock clock.Clock
+
+	// ModelConfig returns the current config of the controller model,
+	// which determines the backup schedule and retention policy.
+	ModelConfig func() (*config.Config, error)
+
+	// Backups is used to store and list backups.
+	Backups backups.Backups
+
+	// NewMetadata returns the metadata for a new backup.
+	NewMetadata func() (*backups.Metadata, error)
+
+	// CreateBackup creates and stores a backup, updating the
+	// supplied metadata.
+	CreateBackup func(*backups.Metadata) error
+}
+
+// Validate returns an error if the config cannot be used to start
+// the worker.
+func (config Config) Validate() error {
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.ModelConfig == nil {
+		return errors.NotValidf("nil ModelConfig")
+	}
+	if config.Backups == nil {
+		return errors.NotValidf("nil Backups")
+	}
+	if config.NewMetadata == nil {
+		return errors.NotValidf("nil NewMetadata")
+	}
+	if config.CreateBackup == nil {
+		return errors.NotValidf("nil CreateBackup")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that takes a backup whenever the interval
+// configured by the controller model's backup-interval setting has
+// passed since the last scheduled backup. A scheduled backup that fails
+// is recorded, and counts as a backup for scheduling purposes so that a
+// persistent failure is not retried continuously. After each backup,
+// scheduled backups outside the configured retention policy are
+// removed.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &backupScheduler{config: config}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+type backupScheduler struct {
+	tomb   tomb.Tomb
+	config Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *backupScheduler) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *backupScheduler) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *backupScheduler) loop() error {
+	for {
+		wait, err := w.step()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.config.Clock.After(wait):
+		}
+	}
+}
+
+// step takes a backup if one is due, and returns how long to wait
+// before checking again.
+func (w *backupScheduler) step() (time.Duration, error) {
+	modelConfig, err := w.config.ModelConfig()
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot read model config")
+	}
+	interval := modelConfig.BackupInterval()
+	if interval == 0 {
+		return ConfigCheckPeriod, nil
+	}
+	last, err := backups.LastScheduled(w.config.Backups)
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot list backups")
+	}
+	now := w.config.Clock.Now()
+	if next := last.Add(interval); now.Before(next) {
+		return minDuration(next.Sub(now), ConfigCheckPeriod), nil
+	}
+
+	if err := w.backup(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	policy := backups.RetentionPolicy{
+		MaxCount: modelConfig.BackupRetentionCount(),
+		MaxAge:   modelConfig.BackupRetentionAge(),
+	}
+	removed, err := backups.PruneScheduled(w.config.Backups, policy, w.config.Clock.Now())
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot remove old backups")
+	}
+	for _, id := range removed {
+		logger.Infof("removed scheduled backup %q", id)
+	}
+	return minDuration(interval, ConfigCheckPeriod), nil
+}
+
+// backup takes a scheduled backup, recording its failure if it cannot
+// be created. Only a failure to record the outcome is returned.
+func (w *backupScheduler) backup() error {
+	meta, err := w.config.NewMetadata()
+	if err != nil {
+		return errors.Annotate(err, "cannot prepare backup metadata")
+	}
+	started := w.config.Clock.Now().UTC()
+	meta.Started = started
+	meta.Scheduled = true
+	meta.Notes = "scheduled backup"
+	if err := w.config.CreateBackup(meta); err != nil {
+		logger.Errorf("scheduled backup failed: %v", err)
+		// Creating the backup may have updated the start time;
+		// the failure is recorded against the scheduled time.
+		meta.Started = started
+		if _, err := w.config.Backups.RecordFailure(meta, err); err != nil {
+			return errors.Annotate(err, "cannot record backup failure")
+		}
+		return nil
+	}
+	logger.Infof("created scheduled backup %q", meta.ID())
+	return nil
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
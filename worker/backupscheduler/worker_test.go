@@ -0,0 +1,256 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	clock   *coretesting.Clock
+	backups *fakeBackups
+	config  backupscheduler.Config
+
+	mu          sync.Mutex
+	modelConfig *config.Config
+	createErr   error
+	created     chan *backups.Metadata
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = coretesting.NewClock(time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC))
+	s.backups = &fakeBackups{}
+	s.created = make(chan *backups.Metadata, 10)
+	s.setModelConfig(c, coretesting.Attrs{
+		"backup-interval":        "24h",
+		"backup-retention-count": 2,
+	})
+	s.config = backupscheduler.Config{
+		Clock: s.clock,
+		ModelConfig: func() (*config.Config, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.modelConfig, nil
+		},
+		Backups: s.backups,
+		NewMetadata: func() (*backups.Metadata, error) {
+			return backups.NewMetadata(), nil
+		},
+		CreateBackup: func(meta *backups.Metadata) error {
+			s.mu.Lock()
+			createErr := s.createErr
+			s.mu.Unlock()
+			defer func() { s.created <- meta }()
+			if createErr != nil {
+				return createErr
+			}
+			_, err := s.backups.Add(nil, meta)
+			return err
+		},
+	}
+}
+
+func (s *WorkerSuite) setModelConfig(c *gc.C, attrs coretesting.Attrs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modelConfig = coretesting.CustomModelConfig(c, attrs)
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := backupscheduler.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.CleanKill(c, w)
+	})
+}
+
+func (s *WorkerSuite) waitCreated(c *gc.C) *backups.Metadata {
+	select {
+	case meta := <-s.created:
+		return meta
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for backup")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) assertNotCreated(c *gc.C) {
+	select {
+	case meta := <-s.created:
+		c.Fatalf("unexpected backup %v", meta)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+// waitAlarm waits for the worker to wait on the clock.
+func (s *WorkerSuite) waitAlarm(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker to wait")
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.config.Clock = nil
+	_, err := backupscheduler.NewWorker(s.config)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *WorkerSuite) TestBacksUpImmediatelyWithNoPreviousBackup(c *gc.C) {
+	s.startWorker(c)
+	meta := s.waitCreated(c)
+	c.Check(meta.Scheduled, jc.IsTrue)
+	c.Check(meta.Started, gc.Equals, s.clock.Now())
+	c.Check(s.backups.ids(), jc.DeepEquals, []string{"20160601-000000"})
+}
+
+func (s *WorkerSuite) TestBacksUpOnSchedule(c *gc.C) {
+	s.backups.add("20160531-120000", s.clock.Now().Add(-12*time.Hour), true, "")
+	s.startWorker(c)
+	s.waitAlarm(c)
+	s.assertNotCreated(c)
+
+	// The worker rechecks the config every ConfigCheckPeriod.
+	for elapsed := time.Duration(0); elapsed < 12*time.Hour; elapsed += backupscheduler.ConfigCheckPeriod {
+		s.clock.Advance(backupscheduler.ConfigCheckPeriod)
+		if elapsed+backupscheduler.ConfigCheckPeriod < 12*time.Hour {
+			s.waitAlarm(c)
+		}
+	}
+	meta := s.waitCreated(c)
+	c.Check(meta.Started, gc.Equals, s.clock.Now())
+}
+
+func (s *WorkerSuite) TestManualBackupsDoNotAffectSchedule(c *gc.C) {
+	s.backups.add("20160531-230000", s.clock.Now().Add(-time.Hour), false, "")
+	s.startWorker(c)
+	s.waitCreated(c)
+}
+
+func (s *WorkerSuite) TestNoSchedule(c *gc.C) {
+	s.setModelConfig(c, coretesting.Attrs{})
+	s.startWorker(c)
+	s.waitAlarm(c)
+	s.assertNotCreated(c)
+
+	s.setModelConfig(c, coretesting.Attrs{"backup-interval": "1h"})
+	s.clock.Advance(backupscheduler.ConfigCheckPeriod)
+	s.waitCreated(c)
+}
+
+func (s *WorkerSuite) TestRecordsFailure(c *gc.C) {
+	s.createErr = errors.New("HA not ready")
+	s.startWorker(c)
+	meta := s.waitCreated(c)
+	s.waitAlarm(c)
+
+	c.Check(meta.Failure, gc.Equals, "HA not ready")
+	c.Check(s.backups.ids(), jc.DeepEquals, []string{"20160601-000000"})
+	recorded := s.backups.get("20160601-000000")
+	c.Check(recorded.Scheduled, jc.IsTrue)
+	c.Check(recorded.Failure, gc.Equals, "HA not ready")
+}
+
+func (s *WorkerSuite) TestPrunesAfterBackup(c *gc.C) {
+	now := s.clock.Now()
+	s.backups.add("manual", now.Add(-100*time.Hour), false, "")
+	s.backups.add("old", now.Add(-72*time.Hour), true, "")
+	s.backups.add("middle", now.Add(-48*time.Hour), true, "")
+	s.backups.add("new", now.Add(-24*time.Hour), true, "")
+	s.startWorker(c)
+	s.waitCreated(c)
+	s.waitAlarm(c)
+
+	c.Check(s.backups.ids(), jc.SameContents, []string{"manual", "new", "20160601-000000"})
+}
+
+// fakeBackups is a goroutine-safe in-memory backups.Backups.
+type fakeBackups struct {
+	backupstesting.FakeBackups
+	mu   sync.Mutex
+	list []*backups.Metadata
+}
+
+func (b *fakeBackups) add(id string, started time.Time, scheduled bool, failure string) {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Scheduled = scheduled
+	meta.Failure = failure
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.list = append(b.list, meta)
+}
+
+func (b *fakeBackups) ids() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ids []string
+	for _, meta := range b.list {
+		ids = append(ids, meta.ID())
+	}
+	return ids
+}
+
+func (b *fakeBackups) get(id string) *backups.Metadata {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, meta := range b.list {
+		if meta.ID() == id {
+			return meta
+		}
+	}
+	return nil
+}
+
+func (b *fakeBackups) Add(_ io.Reader, meta *backups.Metadata) (string, error) {
+	id := meta.Started.Format("20060102-150405")
+	meta.SetID(id)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.list = append(b.list, meta)
+	return id, nil
+}
+
+func (b *fakeBackups) RecordFailure(meta *backups.Metadata, failure error) (string, error) {
+	meta.Failure = failure.Error()
+	return b.Add(nil, meta)
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.list...), nil
+}
+
+func (b *fakeBackups) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.list {
+		if meta.ID() == id {
+			b.list = append(b.list[:i], b.list[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
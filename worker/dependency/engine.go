@@ -229,6 +229,7 @@ func (engine *Engine) manifoldsReport() map[string]interface{} {
 			KeyInputs:      engine.manifolds[name].Inputs,
 			KeyReport:      info.report(),
 			KeyResourceLog: resourceLogReport(info.resourceLog),
+			KeyStartCount:  info.startCount,
 		}
 	}
 	return manifolds
@@ -315,6 +316,7 @@ func (engine *Engine) requestStart(name string, delay time.Duration) {
 	// ...then update the info, copy it back to the engine, and start a worker
 	// goroutine based on current known state.
 	info.starting = true
+	info.startCount++
 	info.abort = make(chan struct{})
 	engine.current[name] = info
 	context := engine.context(name, manifold.Inputs, info.abort)
@@ -466,6 +468,7 @@ func (engine *Engine) gotStarted(name string, worker worker.Worker, resourceLog
 		engine.current[name] = workerInfo{
 			worker:      worker,
 			resourceLog: resourceLog,
+			startCount:  info.startCount,
 		}
 
 		// Any manifold that declares this one as an input needs to be restarted.
@@ -494,6 +497,7 @@ func (engine *Engine) gotStopped(name string, err error, resourceLog []resourceA
 	engine.current[name] = workerInfo{
 		err:         err,
 		resourceLog: resourceLog,
+		startCount:  info.startCount,
 	}
 	if engine.isDying() {
 		logger.Tracef("permanently stopped %q manifold worker (shutting down)", name)
@@ -603,6 +607,10 @@ type workerInfo struct {
 	worker      worker.Worker
 	err         error
 	resourceLog []resourceAccess
+
+	// startCount is the number of times a worker has been started for
+	// the manifold, and is kept across worker restarts.
+	startCount int
 }
 
 // stopped returns true unless the worker is either assigned or starting.
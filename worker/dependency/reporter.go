@@ -57,6 +57,11 @@ const (
 	// error encountered.
 	KeyResourceLog = "resource-log"
 
+	// KeyStartCount holds the number of times a worker has been started
+	// for a manifold; a rising count indicates a worker failing and being
+	// restarted.
+	KeyStartCount = "start-count"
+
 	// KeyName holds the name of some resource.
 	KeyName = "name"
 
@@ -101,6 +101,7 @@ func (s *ReportSuite) TestReportStopping(c *gc.C) {
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
+					"start-count": 1,
 				},
 			},
 		})
@@ -132,6 +133,7 @@ func (s *ReportSuite) TestReportInputs(c *gc.C) {
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
+					"start-count": 1,
 				},
 				"another task": map[string]interface{}{
 					"state":  "started",
@@ -145,6 +147,7 @@ func (s *ReportSuite) TestReportInputs(c *gc.C) {
 					"report": map[string]interface{}{
 						"key1": "hello there",
 					},
+					"start-count": 1,
 				},
 			},
 		})
@@ -174,7 +177,8 @@ func (s *ReportSuite) TestReportError(c *gc.C) {
 						"type":  "<nil>",
 						"error": dependency.ErrMissing,
 					}},
-					"report": (map[string]interface{})(nil),
+					"report":      (map[string]interface{})(nil),
+					"start-count": 1,
 				},
 			},
 		})
@@ -0,0 +1,99 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package diskmanager
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+func init() {
+	DefaultListFilesystemUsage = listFilesystemUsage
+}
+
+// dfColumns are the columns requested from df. The mount point must be
+// last, as it is the only column which may contain spaces.
+var dfColumns = []string{
+	"size",   // total size
+	"used",   // space used
+	"avail",  // space available
+	"itotal", // total inodes
+	"iused",  // inodes used
+	"iavail", // inodes available
+	"target", // mount point
+}
+
+func listFilesystemUsage() ([]storage.FilesystemUsage, error) {
+	logger.Tracef("executing df")
+	output, err := exec.Command(
+		"df",
+		"-B1", // output sizes in bytes
+		"--output="+strings.Join(dfColumns, ","),
+	).Output()
+	if err != nil {
+		// df exits non-zero if any filesystem could not be
+		// queried (e.g. a stale NFS mount), but still reports
+		// those that could.
+		if _, ok := err.(*exec.ExitError); !ok || len(output) == 0 {
+			return nil, errors.Annotate(
+				err, "cannot list filesystem usage: df failed",
+			)
+		}
+		logger.Debugf("df failed, using partial output: %v", err)
+	}
+	return parseDfOutput(output)
+}
+
+func parseDfOutput(output []byte) ([]storage.FilesystemUsage, error) {
+	var usage []storage.FilesystemUsage
+	s := bufio.NewScanner(bytes.NewReader(output))
+	for line := 0; s.Scan(); line++ {
+		if line == 0 {
+			// Skip the header.
+			continue
+		}
+		fields := strings.Fields(s.Text())
+		if len(fields) < len(dfColumns) {
+			logger.Errorf("unexpected output from df: %q", s.Text())
+			continue
+		}
+		var values [6]uint64
+		for i := range values {
+			// Filesystems without a fixed number of
+			// inodes report "-" for the inode columns.
+			if fields[i] == "-" {
+				continue
+			}
+			value, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return nil, errors.Annotatef(
+					err, "invalid %s %q from df", dfColumns[i], fields[i],
+				)
+			}
+			values[i] = value
+		}
+		usage = append(usage, storage.FilesystemUsage{
+			Size:       values[0],
+			Used:       values[1],
+			Available:  values[2],
+			Inodes:     values[3],
+			InodesUsed: values[4],
+			InodesFree: values[5],
+			MountPoint: strings.Join(fields[len(values):], " "),
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Annotate(err, "reading df output")
+	}
+	return usage, nil
+}
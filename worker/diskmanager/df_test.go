@@ -0,0 +1,81 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package diskmanager_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmanager"
+)
+
+var _ = gc.Suite(&ListFilesystemUsageSuite{})
+
+type ListFilesystemUsageSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *ListFilesystemUsageSuite) TestListFilesystemUsage(c *gc.C) {
+	testing.PatchExecutable(c, s, "df", `#!/bin/bash --norc
+cat <<EOF
+1B-blocks         Used    Avail  Inodes IUsed   IFree Mounted on
+21097951232 9336471552 11745087488 1310720 225341 1085379 /
+1073741824           0 1073741824  -      -       -     /srv/my data
+EOF`)
+
+	usage, err := diskmanager.ListFilesystemUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []storage.FilesystemUsage{{
+		MountPoint: "/",
+		Size:       21097951232,
+		Used:       9336471552,
+		Available:  11745087488,
+		Inodes:     1310720,
+		InodesUsed: 225341,
+		InodesFree: 1085379,
+	}, {
+		MountPoint: "/srv/my data",
+		Size:       1073741824,
+		Available:  1073741824,
+	}})
+}
+
+func (s *ListFilesystemUsageSuite) TestListFilesystemUsagePartialFailure(c *gc.C) {
+	testing.PatchExecutable(c, s, "df", `#!/bin/bash --norc
+echo "df: /mnt/nfs: Stale file handle" >&2
+cat <<EOF
+1B-blocks Used Avail Inodes IUsed IFree Mounted on
+1024 512 512 10 1 9 /
+EOF
+exit 1`)
+
+	usage, err := diskmanager.ListFilesystemUsage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.HasLen, 1)
+	c.Assert(usage[0].MountPoint, gc.Equals, "/")
+}
+
+func (s *ListFilesystemUsageSuite) TestListFilesystemUsageFailure(c *gc.C) {
+	testing.PatchExecutable(c, s, "df", `#!/bin/bash --norc
+exit 1`)
+
+	_, err := diskmanager.ListFilesystemUsage()
+	c.Assert(err, gc.ErrorMatches, "cannot list filesystem usage: df failed: exit status 1")
+}
+
+func (s *ListFilesystemUsageSuite) TestListFilesystemUsageInvalidOutput(c *gc.C) {
+	testing.PatchExecutable(c, s, "df", `#!/bin/bash --norc
+cat <<EOF
+1B-blocks Used Avail Inodes IUsed IFree Mounted on
+lots 512 512 10 1 9 /
+EOF`)
+
+	_, err := diskmanager.ListFilesystemUsage()
+	c.Assert(err, gc.ErrorMatches, `invalid size "lots" from df: .*`)
+}
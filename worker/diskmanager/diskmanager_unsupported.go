@@ -20,10 +20,16 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	return nil, nil
 }
 
+func listFilesystemUsage() ([]storage.FilesystemUsage, error) {
+	// Return an empty list each time.
+	return nil, nil
+}
+
 func init() {
 	logger.Infof(
 		"block device support has not been implemented for %s",
 		runtime.GOOS,
 	)
 	DefaultListBlockDevices = listBlockDevices
+	DefaultListFilesystemUsage = listFilesystemUsage
 }
@@ -8,4 +8,8 @@ var (
 	BlockDeviceInUse = &blockDeviceInUse
 	DoWork           = doWork
 	NewWorkerFunc    = newWorker
+
+	ListFilesystemUsage          = listFilesystemUsage
+	DoFilesystemUsageWork        = doFilesystemUsageWork
+	NewFilesystemUsageWorkerFunc = newFilesystemUsageWorker
 )
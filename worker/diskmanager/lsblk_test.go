@@ -44,6 +44,7 @@ KNAME="sda1" SIZE="254803968" LABEL="" UUID="7a62bd85-a350-4c09-8944-5b99bf2080c
 KNAME="sda2" SIZE="1024" LABEL="boot" UUID="" TYPE="disk"
 KNAME="sdb" SIZE="32017047552" LABEL="" UUID="" TYPE="disk"
 KNAME="sdb1" SIZE="32015122432" LABEL="media" UUID="2c1c701d-f2ce-43a4-b345-33e2e39f9503" FSTYPE="ext4" TYPE="disk"
+KNAME="dm-0" SIZE="2147483648" LABEL="" UUID="" TYPE="lvm"
 EOF`)
 
 	devices, err := diskmanager.ListBlockDevices()
@@ -70,6 +71,9 @@ EOF`)
 		Label:          "media",
 		UUID:           "2c1c701d-f2ce-43a4-b345-33e2e39f9503",
 		FilesystemType: "ext4",
+	}, {
+		DeviceName: "dm-0",
+		Size:       2048,
 	}})
 }
 
@@ -64,6 +64,34 @@ func (s *manifoldSuite) TestMachineDiskmanager(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *manifoldSuite) TestMachineFilesystemUsage(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return nil
+		})
+
+	s.PatchValue(&diskmanager.NewFilesystemUsageWorker, func(l diskmanager.ListFilesystemUsageFunc, u diskmanager.FilesystemUsageSetter) worker.Worker {
+		called = true
+
+		c.Assert(l, gc.FitsTypeOf, diskmanager.DefaultListFilesystemUsage)
+		api, ok := u.(*apidiskmanager.State)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(api, gc.NotNil)
+
+		return nil
+	})
+
+	a := &dummyAgent{tag: names.NewMachineTag("1")}
+	_, err := diskmanager.NewFilesystemUsageWorkerFunc(a, apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
 type dummyAgent struct {
 	agent.Agent
 	tag  names.Tag
@@ -0,0 +1,97 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmanager
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	apidiskmanager "github.com/juju/juju/api/diskmanager"
+	"github.com/juju/juju/cmd/jujud/agent/util"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// listFilesystemUsagePeriod is the time period between filesystem usage
+// listings. Usage changes continually, so this is much less frequent
+// than block device listing to limit the load on the controller.
+const listFilesystemUsagePeriod = time.Minute * 5
+
+// FilesystemUsageSetter is an interface that is supplied to
+// NewFilesystemUsageWorker for setting the usage of the filesystems
+// mounted on the local host.
+type FilesystemUsageSetter interface {
+	SetMachineFilesystemUsage([]storage.FilesystemUsage) error
+}
+
+// ListFilesystemUsageFunc is the type of a function that is supplied to
+// NewFilesystemUsageWorker for listing the usage of the filesystems
+// mounted on the local host.
+type ListFilesystemUsageFunc func() ([]storage.FilesystemUsage, error)
+
+// DefaultListFilesystemUsage is the default function for listing the
+// usage of filesystems for the operating system of the local host.
+var DefaultListFilesystemUsage ListFilesystemUsageFunc
+
+// NewFilesystemUsageWorker returns a worker that lists the usage of the
+// filesystems mounted on the machine, and records it in state.
+var NewFilesystemUsageWorker = func(l ListFilesystemUsageFunc, s FilesystemUsageSetter) worker.Worker {
+	var old []storage.FilesystemUsage
+	f := func(stop <-chan struct{}) error {
+		return doFilesystemUsageWork(l, s, &old)
+	}
+	return worker.NewPeriodicWorker(f, listFilesystemUsagePeriod, worker.NewTimer)
+}
+
+func doFilesystemUsageWork(listf ListFilesystemUsageFunc, s FilesystemUsageSetter, old *[]storage.FilesystemUsage) error {
+	usage, err := listf()
+	if err != nil {
+		return err
+	}
+	sort.Sort(byMountPoint(usage))
+	if reflect.DeepEqual(usage, *old) {
+		logger.Tracef("no changes to filesystem usage detected")
+		return nil
+	}
+	logger.Debugf("filesystem usage changed: %v", usage)
+	if err := s.SetMachineFilesystemUsage(usage); err != nil {
+		return err
+	}
+	*old = usage
+	return nil
+}
+
+type byMountPoint []storage.FilesystemUsage
+
+func (u byMountPoint) Len() int           { return len(u) }
+func (u byMountPoint) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u byMountPoint) Less(i, j int) bool { return u[i].MountPoint < u[j].MountPoint }
+
+// UsageManifold returns a dependency manifold that runs a filesystem
+// usage worker, using the resource names defined in the supplied config.
+func UsageManifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := util.AgentApiManifoldConfig(config)
+	return util.AgentApiManifold(typedConfig, newFilesystemUsageWorker)
+}
+
+// newFilesystemUsageWorker trivially wraps NewFilesystemUsageWorker for
+// use in a util.AgentApiManifold.
+func newFilesystemUsageWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	t := a.CurrentConfig().Tag()
+	tag, ok := t.(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected MachineTag, got %#v", t)
+	}
+
+	api := apidiskmanager.NewState(apiCaller, tag)
+
+	return NewFilesystemUsageWorker(DefaultListFilesystemUsage, api), nil
+}
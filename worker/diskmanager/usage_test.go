@@ -0,0 +1,88 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package diskmanager_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/diskmanager"
+)
+
+var _ = gc.Suite(&FilesystemUsageWorkerSuite{})
+
+type FilesystemUsageWorkerSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *FilesystemUsageWorkerSuite) TestWorker(c *gc.C) {
+	done := make(chan struct{})
+	var setUsage FilesystemUsageSetterFunc = func(usage []storage.FilesystemUsage) error {
+		close(done)
+		return nil
+	}
+
+	var listUsage diskmanager.ListFilesystemUsageFunc = func() ([]storage.FilesystemUsage, error) {
+		return []storage.FilesystemUsage{{MountPoint: "/"}}, nil
+	}
+
+	w := diskmanager.NewFilesystemUsageWorker(listUsage, setUsage)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for filesystem usage to be updated")
+	}
+}
+
+func (s *FilesystemUsageWorkerSuite) TestFilesystemUsageChanges(c *gc.C) {
+	var oldUsage []storage.FilesystemUsage
+	var usageSet [][]storage.FilesystemUsage
+	var setUsage FilesystemUsageSetterFunc = func(usage []storage.FilesystemUsage) error {
+		usageSet = append(usageSet, usage)
+		return nil
+	}
+
+	var listUsage diskmanager.ListFilesystemUsageFunc = func() ([]storage.FilesystemUsage, error) {
+		return []storage.FilesystemUsage{
+			{MountPoint: "/srv", Used: 1},
+			{MountPoint: "/", Used: 2},
+		}, nil
+	}
+	for i := 0; i < 2; i++ {
+		err := diskmanager.DoFilesystemUsageWork(listUsage, setUsage, &oldUsage)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	listUsage = func() ([]storage.FilesystemUsage, error) {
+		return []storage.FilesystemUsage{
+			{MountPoint: "/", Used: 2},
+			{MountPoint: "/srv", Used: 3},
+		}, nil
+	}
+	err := diskmanager.DoFilesystemUsageWork(listUsage, setUsage, &oldUsage)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The usage is only recorded when it changes, and is
+	// sorted by mount point.
+	c.Assert(usageSet, jc.DeepEquals, [][]storage.FilesystemUsage{{
+		{MountPoint: "/", Used: 2},
+		{MountPoint: "/srv", Used: 1},
+	}, {
+		{MountPoint: "/", Used: 2},
+		{MountPoint: "/srv", Used: 3},
+	}})
+}
+
+type FilesystemUsageSetterFunc func([]storage.FilesystemUsage) error
+
+func (f FilesystemUsageSetterFunc) SetMachineFilesystemUsage(usage []storage.FilesystemUsage) error {
+	return f(usage)
+}
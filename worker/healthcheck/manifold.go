@@ -0,0 +1,107 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	corehealthcheck "github.com/juju/juju/core/healthcheck"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/uniter"
+)
+
+// ManifoldConfig holds the dependencies and configuration for a
+// Worker manifold.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+	CharmDirName  string
+	Clock         clock.Clock
+
+	NewFacade func(base.APICaller, names.UnitTag) (Facade, error)
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// validate is called by start to check for bad configuration.
+func (config ManifoldConfig) validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.CharmDirName == "" {
+		return errors.NotValidf("empty CharmDirName")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.NewFacade == nil {
+		return errors.NotValidf("nil NewFacade")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var agent agent.Agent
+	if err := context.Get(config.AgentName, &agent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var charmDirGuest fortress.Guest
+	if err := context.Get(config.CharmDirName, &charmDirGuest); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	agentConfig := agent.CurrentConfig()
+	unitTag, ok := agentConfig.Tag().(names.UnitTag)
+	if !ok {
+		return nil, errors.Errorf("expected a unit tag, got %v", agentConfig.Tag())
+	}
+	facade, err := config.NewFacade(apiCaller, unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	paths := uniter.NewWorkerPaths(agentConfig.DataDir(), unitTag, "health-check")
+	worker, err := config.NewWorker(Config{
+		Facade:        facade,
+		Clock:         config.Clock,
+		CharmDir:      paths.GetCharmDir(),
+		CharmDirGuest: charmDirGuest,
+		ReadChecks:    corehealthcheck.ReadChecks,
+		Probe:         Probe,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
+
+// Manifold packages a Worker for use in a dependency.Engine.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.APICallerName,
+			config.CharmDirName,
+		},
+		Start: config.start,
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/healthcheck"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func validManifoldConfig() healthcheck.ManifoldConfig {
+	return healthcheck.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+		CharmDirName:  "charm-dir",
+		Clock:         coretesting.NewClock(time.Time{}),
+		NewFacade: func(base.APICaller, names.UnitTag) (healthcheck.Facade, error) {
+			return &struct{ healthcheck.Facade }{}, nil
+		},
+		NewWorker: func(healthcheck.Config) (worker.Worker, error) {
+			return &struct{ worker.Worker }{}, nil
+		},
+	}
+}
+
+func (*ManifoldSuite) stubContext() dependency.Context {
+	return dt.StubContext(nil, map[string]interface{}{
+		"agent":      &fakeAgent{tag: names.NewUnitTag("wordpress/0"), dataDir: "/var/lib/juju"},
+		"api-caller": &struct{ base.APICaller }{},
+		"charm-dir":  &fakeGuest{},
+	})
+}
+
+func (*ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := healthcheck.Manifold(validManifoldConfig())
+	c.Check(manifold.Inputs, jc.DeepEquals, []string{"agent", "api-caller", "charm-dir"})
+}
+
+func (s *ManifoldSuite) TestStartInvalidConfig(c *gc.C) {
+	config := validManifoldConfig()
+	config.Clock = nil
+	manifold := healthcheck.Manifold(config)
+	worker, err := manifold.Start(s.stubContext())
+	c.Check(worker, gc.IsNil)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (*ManifoldSuite) TestStartMissingCharmDir(c *gc.C) {
+	context := dt.StubContext(nil, map[string]interface{}{
+		"agent":      &fakeAgent{tag: names.NewUnitTag("wordpress/0"), dataDir: "/var/lib/juju"},
+		"api-caller": &struct{ base.APICaller }{},
+		"charm-dir":  dependency.ErrMissing,
+	})
+	manifold := healthcheck.Manifold(validManifoldConfig())
+	worker, err := manifold.Start(context)
+	c.Check(worker, gc.IsNil)
+	c.Check(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (s *ManifoldSuite) TestStartNewFacadeError(c *gc.C) {
+	config := validManifoldConfig()
+	config.NewFacade = func(_ base.APICaller, tag names.UnitTag) (healthcheck.Facade, error) {
+		c.Check(tag, gc.Equals, names.NewUnitTag("wordpress/0"))
+		return nil, errors.New("bort")
+	}
+	manifold := healthcheck.Manifold(config)
+	worker, err := manifold.Start(s.stubContext())
+	c.Check(worker, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "bort")
+}
+
+func (s *ManifoldSuite) TestStartSuccess(c *gc.C) {
+	expectFacade := &struct{ healthcheck.Facade }{}
+	expectWorker := &struct{ worker.Worker }{}
+	config := validManifoldConfig()
+	config.NewFacade = func(base.APICaller, names.UnitTag) (healthcheck.Facade, error) {
+		return expectFacade, nil
+	}
+	config.NewWorker = func(workerConfig healthcheck.Config) (worker.Worker, error) {
+		c.Check(workerConfig.Facade, gc.Equals, expectFacade)
+		c.Check(workerConfig.Clock, gc.Equals, config.Clock)
+		c.Check(workerConfig.CharmDir, gc.Equals, filepath.Join("/var/lib/juju", "agents", "unit-wordpress-0", "charm"))
+		c.Check(workerConfig.CharmDirGuest, gc.NotNil)
+		c.Check(workerConfig.Validate(), jc.ErrorIsNil)
+		return expectWorker, nil
+	}
+	manifold := healthcheck.Manifold(config)
+	worker, err := manifold.Start(s.stubContext())
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(worker, gc.Equals, expectWorker)
+}
+
+type fakeAgent struct {
+	agent.Agent
+	tag     names.Tag
+	dataDir string
+}
+
+func (a *fakeAgent) CurrentConfig() agent.Config {
+	return &fakeAgentConfig{tag: a.tag, dataDir: a.dataDir}
+}
+
+type fakeAgentConfig struct {
+	agent.Config
+	tag     names.Tag
+	dataDir string
+}
+
+func (c *fakeAgentConfig) Tag() names.Tag {
+	return c.tag
+}
+
+func (c *fakeAgentConfig) DataDir() string {
+	return c.dataDir
+}
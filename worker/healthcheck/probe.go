@@ -0,0 +1,84 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	corehealthcheck "github.com/juju/juju/core/healthcheck"
+)
+
+// Probe runs the supplied check once, returning an error if it fails.
+// Exec checks are run by the shell from the supplied charm directory,
+// and are killed along with any processes they started if they time
+// out according to the supplied clock.
+func Probe(clock clock.Clock, check corehealthcheck.Check, charmDir string) error {
+	switch {
+	case check.HTTP != "":
+		return probeHTTP(check.HTTP, check.Timeout)
+	case check.TCP != "":
+		return probeTCP(check.TCP, check.Timeout)
+	case check.Exec != "":
+		return probeExec(clock, check.Exec, charmDir, check.Timeout)
+	}
+	return errors.Errorf("health check %q has nothing to check", check.Name)
+}
+
+func probeHTTP(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+func probeTCP(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return conn.Close()
+}
+
+func probeExec(clock clock.Clock, command, charmDir string, timeout time.Duration) error {
+	var output bytes.Buffer
+	cmd := shellCommand(command)
+	cmd.Dir = charmDir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-clock.After(timeout):
+		killProcessGroup(cmd.Process)
+		<-done
+		return errors.Errorf("%q timed out after %v", command, timeout)
+	}
+	if err == nil {
+		return nil
+	}
+	if out := strings.TrimSpace(output.String()); out != "" {
+		return errors.Errorf("%q failed: %v: %s", command, err, out)
+	}
+	return errors.Errorf("%q failed: %v", command, err)
+}
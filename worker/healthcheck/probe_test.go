@@ -0,0 +1,98 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	corehealthcheck "github.com/juju/juju/core/healthcheck"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/healthcheck"
+)
+
+type ProbeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ProbeSuite{})
+
+func (*ProbeSuite) TestHTTP(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.Error(w, "sick", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	check := corehealthcheck.Check{HTTP: server.URL + "/health", Timeout: time.Second}
+	c.Check(healthcheck.Probe(clock.WallClock, check, ""), jc.ErrorIsNil)
+
+	check.HTTP = server.URL + "/other"
+	err := healthcheck.Probe(clock.WallClock, check, "")
+	c.Check(err, gc.ErrorMatches, `GET http://.*/other: 503 Service Unavailable`)
+}
+
+func (*ProbeSuite) TestTCP(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	address := listener.Addr().String()
+
+	check := corehealthcheck.Check{TCP: address, Timeout: time.Second}
+	c.Check(healthcheck.Probe(clock.WallClock, check, ""), jc.ErrorIsNil)
+
+	listener.Close()
+	err = healthcheck.Probe(clock.WallClock, check, "")
+	c.Check(err, gc.ErrorMatches, ".*connection refused")
+}
+
+func (*ProbeSuite) TestExec(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "check"), []byte("#!/bin/sh\necho $1 >&2\nexit $2\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	check := corehealthcheck.Check{Exec: "./check fine 0", Timeout: time.Second}
+	c.Check(healthcheck.Probe(clock.WallClock, check, dir), jc.ErrorIsNil)
+
+	check.Exec = "./check broken 3"
+	err = healthcheck.Probe(clock.WallClock, check, dir)
+	c.Check(err, gc.ErrorMatches, `"./check broken 3" failed: exit status 3: broken`)
+}
+
+func (*ProbeSuite) TestExecTimeout(c *gc.C) {
+	check := corehealthcheck.Check{Exec: "sleep 10", Timeout: 50 * time.Millisecond}
+	err := healthcheck.Probe(clock.WallClock, check, c.MkDir())
+	c.Check(err, gc.ErrorMatches, `"sleep 10" timed out after 50ms`)
+}
+
+func (*ProbeSuite) TestExecTimeoutUsesClock(c *gc.C) {
+	testClock := coretesting.NewClock(time.Time{})
+	check := corehealthcheck.Check{Exec: "sleep 10", Timeout: time.Minute}
+	dir := c.MkDir()
+	done := make(chan error, 1)
+	go func() {
+		done <- healthcheck.Probe(testClock, check, dir)
+	}()
+	select {
+	case <-testClock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for probe to wait on the clock")
+	}
+	testClock.Advance(time.Minute)
+	select {
+	case err := <-done:
+		c.Check(err, gc.ErrorMatches, `"sleep 10" timed out after 1m0s`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for probe to time out")
+	}
+}
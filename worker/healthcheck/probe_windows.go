@@ -0,0 +1,20 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"os"
+	"os/exec"
+)
+
+// shellCommand returns a command that runs the supplied command with
+// powershell.
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("powershell.exe", "-NonInteractive", "-Command", command)
+}
+
+// killProcessGroup kills the given process.
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
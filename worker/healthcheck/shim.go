@@ -0,0 +1,31 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/worker"
+)
+
+// NewFacade returns the *uniter.Unit for the supplied tag as a Facade.
+func NewFacade(apiCaller base.APICaller, unitTag names.UnitTag) (Facade, error) {
+	unit, err := uniter.NewState(apiCaller, unitTag).Unit(unitTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unit, nil
+}
+
+// NewWorker creates a *Worker and returns it as a worker.Worker.
+func NewWorker(config Config) (worker.Worker, error) {
+	worker, err := New(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return worker, nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package healthcheck provides a worker that evaluates the health
+// checks declared by a unit's charm, and reports the unit's health to
+// the controller.
+package healthcheck
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+
+	corehealthcheck "github.com/juju/juju/core/healthcheck"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/worker/catacomb"
+	"github.com/juju/juju/worker/fortress"
+)
+
+var logger = loggo.GetLogger("juju.worker.healthcheck")
+
+// reloadInterval is the time between reads of the charm's health
+// checks, so that changes made by charm upgrades are picked up.
+const reloadInterval = time.Minute
+
+// Facade exposes controller functionality required by a Worker.
+type Facade interface {
+	SetHealth(health status.Status, info string, data map[string]interface{}) error
+}
+
+// Config holds the dependencies and configuration for a Worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+
+	// CharmDir is the path of the unit's charm directory, and
+	// CharmDirGuest guards access to it.
+	CharmDir      string
+	CharmDirGuest fortress.Guest
+
+	// ReadChecks returns the health checks declared by the charm in
+	// the supplied directory.
+	ReadChecks func(charmDir string) ([]corehealthcheck.Check, error)
+
+	// Probe runs a check once, returning an error if it fails. The
+	// supplied clock is used to time the check out.
+	Probe func(clock clock.Clock, check corehealthcheck.Check, charmDir string) error
+}
+
+// Validate returns an error if the config cannot be expected to
+// drive a functional Worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.CharmDir == "" {
+		return errors.NotValidf("empty CharmDir")
+	}
+	if config.CharmDirGuest == nil {
+		return errors.NotValidf("nil CharmDirGuest")
+	}
+	if config.ReadChecks == nil {
+		return errors.NotValidf("nil ReadChecks")
+	}
+	if config.Probe == nil {
+		return errors.NotValidf("nil Probe")
+	}
+	return nil
+}
+
+// New returns a Worker that evaluates the charm's health checks, each
+// at its own interval and independently of the hooks run by the
+// uniter, and reports the unit's health whenever it changes.
+func New(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config: config,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Worker evaluates the health checks declared by a unit's charm.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// The fields below are only accessed by the loop goroutine.
+	checks     []*checkState
+	nextReload time.Time
+	reported   *health
+}
+
+// checkState tracks the evaluations of a single check.
+type checkState struct {
+	corehealthcheck.Check
+	next     time.Time
+	passed   bool
+	failures int
+	lastErr  error
+}
+
+// health describes the reported health of the unit.
+type health struct {
+	status  status.Status
+	message string
+	failing []string
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	var wait time.Duration
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.config.Clock.After(wait):
+		}
+		var err error
+		wait, err = w.evaluate()
+		if err == fortress.ErrAborted {
+			return w.catacomb.ErrDying()
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// visit calls f while the charm directory is guaranteed not to change,
+// returning fortress.ErrAborted if the worker is stopped first. It is
+// held only for as long as the charm directory is needed, so that a
+// slow check does not delay charm upgrades.
+func (w *Worker) visit(f func() error) error {
+	return w.config.CharmDirGuest.Visit(f, w.catacomb.Dying())
+}
+
+// evaluate runs every check that is due, reports the unit's health if
+// it has changed, and returns the time until the next check is due.
+func (w *Worker) evaluate() (time.Duration, error) {
+	now := w.config.Clock.Now()
+	if !now.Before(w.nextReload) {
+		if err := w.visit(func() error {
+			w.reload()
+			return nil
+		}); err != nil {
+			return 0, err
+		}
+		w.nextReload = now.Add(reloadInterval)
+	}
+	next := w.nextReload
+	for _, check := range w.checks {
+		if !now.Before(check.next) {
+			if err := w.probe(check); err != nil {
+				return 0, err
+			}
+			check.next = now.Add(check.Interval)
+		}
+		if check.next.Before(next) {
+			next = check.next
+		}
+	}
+	if err := w.report(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return next.Sub(w.config.Clock.Now()), nil
+}
+
+// reload reads the charm's health checks, keeping the state of any
+// check that has not changed.
+func (w *Worker) reload() {
+	checks, err := w.config.ReadChecks(w.config.CharmDir)
+	if err != nil {
+		logger.Errorf("cannot read health checks: %v", err)
+		return
+	}
+	existing := make(map[string]*checkState)
+	for _, check := range w.checks {
+		existing[check.Name] = check
+	}
+	states := make([]*checkState, len(checks))
+	for i, check := range checks {
+		if old, ok := existing[check.Name]; ok && reflect.DeepEqual(old.Check, check) {
+			states[i] = old
+			continue
+		}
+		logger.Debugf("evaluating health check %q every %v", check.Name, check.Interval)
+		states[i] = &checkState{Check: check}
+	}
+	w.checks = states
+}
+
+// probe runs a check once and records the result. Only exec checks,
+// which run from the charm directory, do so while visiting it; the
+// returned error is fortress.ErrAborted if the worker was stopped
+// while waiting to visit.
+func (w *Worker) probe(check *checkState) error {
+	var err error
+	run := func() error {
+		err = w.config.Probe(w.config.Clock, check.Check, w.config.CharmDir)
+		return nil
+	}
+	if check.Exec != "" {
+		if visitErr := w.visit(run); visitErr != nil {
+			return visitErr
+		}
+	} else {
+		run()
+	}
+	if err == nil {
+		if check.failures >= check.Threshold {
+			logger.Infof("health check %q passed", check.Name)
+		}
+		check.passed = true
+		check.failures = 0
+		check.lastErr = nil
+		return nil
+	}
+	check.failures++
+	check.lastErr = err
+	logger.Debugf("health check %q failed (%d/%d): %v", check.Name, check.failures, check.Threshold, err)
+	return nil
+}
+
+// report sets the unit's health if it differs from that last reported.
+// The unit is unhealthy if any check has failed at least as many times
+// in a row as its threshold. Health is not reported until every check
+// has either passed or reached its threshold.
+func (w *Worker) report() error {
+	current := &health{status: status.StatusHealthy}
+	var messages []string
+	undetermined := false
+	for _, check := range w.checks {
+		if check.failures >= check.Threshold {
+			current.status = status.StatusUnhealthy
+			current.failing = append(current.failing, check.Name)
+			messages = append(messages, fmt.Sprintf("%s: %v", check.Name, check.lastErr))
+		} else if !check.passed {
+			undetermined = true
+		}
+	}
+	if undetermined && current.status != status.StatusUnhealthy {
+		return nil
+	}
+	current.message = strings.Join(messages, "; ")
+	if len(w.checks) == 0 {
+		if w.reported == nil {
+			// Nothing has been reported for a charm without
+			// health checks, so there is nothing to correct.
+			return nil
+		}
+		current.status = status.StatusUnknown
+		current.message = "no health checks declared"
+	}
+	if reflect.DeepEqual(current, w.reported) {
+		return nil
+	}
+	var data map[string]interface{}
+	if len(current.failing) > 0 {
+		data = map[string]interface{}{"failing": current.failing}
+	}
+	if err := w.config.Facade.SetHealth(current.status, current.message, data); err != nil {
+		return errors.Annotate(err, "cannot set unit health")
+	}
+	w.reported = current
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package healthcheck_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	corehealthcheck "github.com/juju/juju/core/healthcheck"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/healthcheck"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	clock  *coretesting.Clock
+	facade *fakeFacade
+	guest  *fakeGuest
+	config healthcheck.Config
+
+	mu     sync.Mutex
+	checks []corehealthcheck.Check
+	errors map[string]error
+	probes []string
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = coretesting.NewClock(time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC))
+	s.facade = &fakeFacade{calls: make(chan healthCall, 10)}
+	s.guest = &fakeGuest{}
+	s.checks = []corehealthcheck.Check{{
+		Name:      "web",
+		HTTP:      "http://localhost:8080/health",
+		Interval:  10 * time.Second,
+		Timeout:   time.Second,
+		Threshold: 2,
+	}}
+	s.errors = make(map[string]error)
+	s.probes = nil
+	s.config = healthcheck.Config{
+		Facade:        s.facade,
+		Clock:         s.clock,
+		CharmDir:      "/var/lib/juju/agents/unit-wordpress-0/charm",
+		CharmDirGuest: s.guest,
+		ReadChecks: func(charmDir string) ([]corehealthcheck.Check, error) {
+			c.Check(charmDir, gc.Equals, "/var/lib/juju/agents/unit-wordpress-0/charm")
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.checks, nil
+		},
+		Probe: func(clock clock.Clock, check corehealthcheck.Check, charmDir string) error {
+			c.Check(clock, gc.Equals, s.clock)
+			c.Check(charmDir, gc.Equals, "/var/lib/juju/agents/unit-wordpress-0/charm")
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.probes = append(s.probes, check.Name)
+			return s.errors[check.Name]
+		},
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := healthcheck.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.CleanKill(c, w)
+	})
+	// The first evaluation happens immediately.
+	s.waitAlarm(c)
+	s.waitAlarm(c)
+}
+
+func (s *WorkerSuite) setError(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[name] = err
+}
+
+func (s *WorkerSuite) setChecks(checks []corehealthcheck.Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = checks
+}
+
+func (s *WorkerSuite) takeProbes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	probes := s.probes
+	s.probes = nil
+	return probes
+}
+
+// advance advances the clock and waits for the worker to evaluate its
+// checks and wait again.
+func (s *WorkerSuite) advance(c *gc.C, d time.Duration) {
+	s.clock.Advance(d)
+	s.waitAlarm(c)
+}
+
+// waitAlarm waits for the worker to wait on the clock.
+func (s *WorkerSuite) waitAlarm(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker to wait")
+	}
+}
+
+func (s *WorkerSuite) assertHealth(c *gc.C, expect healthCall) {
+	select {
+	case call := <-s.facade.calls:
+		c.Assert(call, jc.DeepEquals, expect)
+	default:
+		c.Fatalf("health not set")
+	}
+}
+
+func (s *WorkerSuite) assertNoHealth(c *gc.C) {
+	select {
+	case call := <-s.facade.calls:
+		c.Fatalf("unexpected health %v", call)
+	default:
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.config.Probe = nil
+	_, err := healthcheck.New(s.config)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, "nil Probe not valid")
+}
+
+func (s *WorkerSuite) TestReportsTransitions(c *gc.C) {
+	s.startWorker(c)
+	s.assertHealth(c, healthCall{status: status.StatusHealthy})
+
+	// A single failure is within the threshold.
+	s.setError("web", errors.New("connection refused"))
+	s.advance(c, 10*time.Second)
+	s.assertNoHealth(c)
+
+	s.advance(c, 10*time.Second)
+	s.assertHealth(c, healthCall{
+		status: status.StatusUnhealthy,
+		info:   "web: connection refused",
+		data:   map[string]interface{}{"failing": []string{"web"}},
+	})
+
+	s.advance(c, 10*time.Second)
+	s.assertNoHealth(c)
+
+	s.setError("web", nil)
+	s.advance(c, 10*time.Second)
+	s.assertHealth(c, healthCall{status: status.StatusHealthy})
+}
+
+func (s *WorkerSuite) TestUndeterminedUntilThreshold(c *gc.C) {
+	s.setError("web", errors.New("connection refused"))
+	s.startWorker(c)
+	s.assertNoHealth(c)
+
+	s.advance(c, 10*time.Second)
+	s.assertHealth(c, healthCall{
+		status: status.StatusUnhealthy,
+		info:   "web: connection refused",
+		data:   map[string]interface{}{"failing": []string{"web"}},
+	})
+}
+
+func (s *WorkerSuite) TestIndependentIntervals(c *gc.C) {
+	s.setChecks([]corehealthcheck.Check{{
+		Name: "fast", TCP: "localhost:80", Interval: 10 * time.Second, Threshold: 1,
+	}, {
+		Name: "slow", Exec: "true", Interval: 25 * time.Second, Threshold: 1,
+	}})
+	s.startWorker(c)
+	c.Assert(s.takeProbes(), jc.DeepEquals, []string{"fast", "slow"})
+
+	s.advance(c, 10*time.Second)
+	c.Assert(s.takeProbes(), jc.DeepEquals, []string{"fast"})
+	s.advance(c, 10*time.Second)
+	c.Assert(s.takeProbes(), jc.DeepEquals, []string{"fast"})
+	s.advance(c, 5*time.Second)
+	c.Assert(s.takeProbes(), jc.DeepEquals, []string{"slow"})
+}
+
+func (s *WorkerSuite) TestNoChecks(c *gc.C) {
+	s.setChecks(nil)
+	s.startWorker(c)
+	s.assertNoHealth(c)
+	c.Assert(s.takeProbes(), gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestChecksRemoved(c *gc.C) {
+	s.startWorker(c)
+	s.assertHealth(c, healthCall{status: status.StatusHealthy})
+
+	s.setChecks(nil)
+	for i := 0; i < 6; i++ {
+		s.advance(c, 10*time.Second)
+	}
+	s.assertHealth(c, healthCall{
+		status: status.StatusUnknown,
+		info:   "no health checks declared",
+	})
+}
+
+func (s *WorkerSuite) TestSetHealthError(c *gc.C) {
+	s.facade.err = errors.New("boom")
+	w, err := healthcheck.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "cannot set unit health: boom")
+}
+
+func (s *WorkerSuite) TestKillWhileCharmDirLocked(c *gc.C) {
+	s.guest.locked = true
+	w, err := healthcheck.New(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+	c.Assert(s.takeProbes(), gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestOnlyExecChecksVisitCharmDir(c *gc.C) {
+	s.setChecks([]corehealthcheck.Check{{
+		Name: "http", HTTP: "http://localhost:8080/", Interval: 10 * time.Second, Threshold: 1,
+	}, {
+		Name: "script", Exec: "true", Interval: 10 * time.Second, Threshold: 1,
+	}, {
+		Name: "tcp", TCP: "localhost:80", Interval: 10 * time.Second, Threshold: 1,
+	}})
+	visiting := make(map[string]bool)
+	s.config.Probe = func(clock clock.Clock, check corehealthcheck.Check, charmDir string) error {
+		// The guest is only touched by the worker's goroutine.
+		visiting[check.Name] = s.guest.visiting
+		return nil
+	}
+	s.startWorker(c)
+	c.Assert(visiting, jc.DeepEquals, map[string]bool{
+		"http":   false,
+		"script": true,
+		"tcp":    false,
+	})
+}
+
+type healthCall struct {
+	status status.Status
+	info   string
+	data   map[string]interface{}
+}
+
+type fakeFacade struct {
+	calls chan healthCall
+	err   error
+}
+
+func (f *fakeFacade) SetHealth(health status.Status, info string, data map[string]interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.calls <- healthCall{health, info, data}
+	return nil
+}
+
+type fakeGuest struct {
+	locked   bool
+	visiting bool
+}
+
+func (g *fakeGuest) Visit(visit fortress.Visit, abort fortress.Abort) error {
+	if g.locked {
+		<-abort
+		return fortress.ErrAborted
+	}
+	g.visiting = true
+	defer func() { g.visiting = false }()
+	return visit()
+}
@@ -0,0 +1,216 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection provides a worker that serves information about
+// the internals of a running agent over HTTP on an abstract unix domain
+// socket, so that it can be queried on the machine by juju-introspect
+// even when the agent is otherwise unresponsive.
+package introspection
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"gopkg.in/yaml.v2"
+	"launchpad.net/tomb"
+
+	jujupprof "github.com/juju/juju/cmd/pprof"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.introspection")
+
+// Config describes the arguments required to create the introspection
+// worker.
+type Config struct {
+	// SocketName is the name of the abstract unix domain socket on
+	// which the worker listens. It must not include the leading "@".
+	SocketName string
+
+	// Reporter reports the state of the agent's dependency engine.
+	Reporter dependency.Reporter
+
+	// Runners holds reporters for the agent's worker runners,
+	// keyed on a name that identifies each runner.
+	Runners map[string]dependency.Reporter
+}
+
+// Validate returns an error if the configuration is not complete.
+func (config Config) Validate() error {
+	if config.SocketName == "" {
+		return errors.NotValidf("empty SocketName")
+	}
+	if config.Reporter == nil {
+		return errors.NotValidf("nil Reporter")
+	}
+	for name, runner := range config.Runners {
+		if runner == nil {
+			return errors.NotValidf("nil Runner %q", name)
+		}
+	}
+	return nil
+}
+
+// AgentSocketName returns the name of the socket on which the
+// introspection worker for the agent with the given tag listens.
+func AgentSocketName(agentTag names.Tag) string {
+	return "jujud-" + agentTag.String()
+}
+
+// IsSupported returns whether introspection is available on the current
+// host; abstract unix domain sockets are only supported on linux.
+func IsSupported() bool {
+	return runtime.GOOS == "linux"
+}
+
+// NewWorker starts an HTTP server listening on the abstract unix domain
+// socket named in the config, which serves:
+//
+//   /depengine/     the dependency engine report, as YAML
+//   /runners/       the worker runner reports, as YAML
+//   /goroutines     a dump of the stacks of all goroutines
+//   /debug/pprof/   the standard pprof profiles
+//
+// The server is stopped when the worker is killed.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !IsSupported() {
+		return nil, errors.NotSupportedf("introspection on %q", runtime.GOOS)
+	}
+	addr, err := net.ResolveUnixAddr("unix", "@"+config.SocketName)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot resolve introspection socket")
+	}
+	listener, err := net.ListenUnix("unix", addr)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot listen on introspection socket")
+	}
+	logger.Debugf("introspection listening on %q", addr)
+	w := &introspectionWorker{
+		config:   config,
+		listener: listener,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+type introspectionWorker struct {
+	tomb     tomb.Tomb
+	config   Config
+	listener net.Listener
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *introspectionWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *introspectionWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *introspectionWorker) loop() error {
+	srv := http.Server{Handler: w.handler()}
+	serveDone := make(chan struct{})
+	go func() {
+		defer close(serveDone)
+		// The error from Serve is only interesting if we weren't
+		// expecting the listener to be closed.
+		if err := srv.Serve(w.listener); err != nil {
+			select {
+			case <-w.tomb.Dying():
+			default:
+				w.tomb.Kill(errors.Annotate(err, "introspection server failed"))
+			}
+		}
+	}()
+	<-w.tomb.Dying()
+	w.listener.Close()
+	<-serveDone
+	return tomb.ErrDying
+}
+
+func (w *introspectionWorker) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/depengine/", reportHandler{func() map[string]interface{} {
+		return w.config.Reporter.Report()
+	}})
+	mux.Handle("/runners/", reportHandler{w.runnersReport})
+	mux.HandleFunc("/goroutines", goroutines)
+	mux.HandleFunc("/debug/pprof/", jujupprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", jujupprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", jujupprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", jujupprof.Symbol)
+	return mux
+}
+
+func (w *introspectionWorker) runnersReport() map[string]interface{} {
+	report := make(map[string]interface{})
+	for name, runner := range w.config.Runners {
+		report[name] = runner.Report()
+	}
+	return report
+}
+
+// reportHandler serves the report returned by its function as YAML.
+type reportHandler struct {
+	report func() map[string]interface{}
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h reportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	out, err := yaml.Marshal(sanitize(h.report()))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot marshal report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(out)
+}
+
+// goroutines writes the stacks of all running goroutines.
+func goroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// sanitize returns a copy of the supplied report value in which errors,
+// which do not marshal usefully, are replaced by their messages.
+func sanitize(value interface{}) interface{} {
+	switch value := value.(type) {
+	case error:
+		return value.Error()
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = sanitize(item)
+		}
+		return result
+	case []map[string]interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = sanitize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = sanitize(item)
+		}
+		return result
+	}
+	return value
+}
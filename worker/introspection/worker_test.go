@@ -0,0 +1,157 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/introspection"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&suite{})
+
+func (s *suite) SetUpTest(c *gc.C) {
+	if !introspection.IsSupported() {
+		c.Skip("introspection is not supported on this platform")
+	}
+	s.BaseSuite.SetUpTest(c)
+}
+
+func (s *suite) TestConfigValidation(c *gc.C) {
+	w, err := introspection.NewWorker(introspection.Config{})
+	c.Check(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "empty SocketName not valid")
+
+	w, err = introspection.NewWorker(introspection.Config{
+		SocketName: "socket",
+	})
+	c.Check(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "nil Reporter not valid")
+
+	w, err = introspection.NewWorker(introspection.Config{
+		SocketName: "socket",
+		Reporter:   &reporter{},
+		Runners:    map[string]dependency.Reporter{"agent": nil},
+	})
+	c.Check(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, `nil Runner "agent" not valid`)
+}
+
+func (s *suite) startWorker(c *gc.C) string {
+	name := fmt.Sprintf("introspection-test-%d", os.Getpid())
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketName: name,
+		Reporter: &reporter{map[string]interface{}{
+			"state": "started",
+			"error": errors.New("boom"),
+			"manifolds": map[string]interface{}{
+				"task": map[string]interface{}{
+					"start-count": 3,
+				},
+			},
+		}},
+		Runners: map[string]dependency.Reporter{
+			"agent": &reporter{map[string]interface{}{
+				"state": "started",
+			}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.CleanKill(c, w)
+	})
+	return name
+}
+
+func (s *suite) get(c *gc.C, socketName, path string) (int, string) {
+	client := http.Client{
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return net.Dial("unix", "@"+socketName)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix.socket" + path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp.StatusCode, string(body)
+}
+
+func (s *suite) TestEngineReport(c *gc.C) {
+	name := s.startWorker(c)
+	status, body := s.get(c, name, "/depengine/")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `
+error: boom
+manifolds:
+  task:
+    start-count: 3
+state: started
+`[1:])
+}
+
+func (s *suite) TestRunnersReport(c *gc.C) {
+	name := s.startWorker(c)
+	status, body := s.get(c, name, "/runners/")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `
+agent:
+  state: started
+`[1:])
+}
+
+func (s *suite) TestGoroutines(c *gc.C) {
+	name := s.startWorker(c)
+	status, body := s.get(c, name, "/goroutines")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, jc.Contains, "goroutine ")
+}
+
+func (s *suite) TestPprof(c *gc.C) {
+	name := s.startWorker(c)
+	status, body := s.get(c, name, "/debug/pprof/")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, jc.Contains, "full goroutine stack dump")
+
+	status, _ = s.get(c, name, "/debug/pprof/heap")
+	c.Assert(status, gc.Equals, http.StatusOK)
+}
+
+func (s *suite) TestSocketClosedOnKill(c *gc.C) {
+	name := fmt.Sprintf("introspection-test-kill-%d", os.Getpid())
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketName: name,
+		Reporter:   &reporter{},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+
+	_, err = net.Dial("unix", "@"+name)
+	c.Assert(err, gc.NotNil)
+}
+
+type reporter struct {
+	report map[string]interface{}
+}
+
+func (r *reporter) Report() map[string]interface{} {
+	return r.report
+}
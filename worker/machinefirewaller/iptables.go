@@ -0,0 +1,199 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+// egressChain is the name of the iptables chain that holds the
+// egress rules. It is jumped to from the OUTPUT chain.
+const egressChain = "juju-egress"
+
+// newEgressChain is the name of the chain in which replacement egress
+// rules are built before they are swapped in for the egress chain.
+const newEgressChain = "juju-egress-new"
+
+// runCommand runs the named command with the specified arguments.
+// It is a variable so it can be patched in tests.
+var runCommand = func(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "%s %s: %s", name, strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// NewIptablesFirewall returns a Firewall that enforces egress rules
+// with iptables and ip6tables. Traffic to the specified addresses is
+// always allowed, so that rules cannot cut the agent off from the
+// controller.
+func NewIptablesFirewall(allowedAddresses []string) Firewall {
+	return &iptablesFirewall{allowedAddresses}
+}
+
+type iptablesFirewall struct {
+	allowedAddresses []string
+}
+
+// SetEgressRules is part of the Firewall interface.
+func (f *iptablesFirewall) SetEgressRules(rules []network.EgressRule) error {
+	for _, family := range []struct {
+		command string
+		ipv6    bool
+	}{{"iptables", false}, {"ip6tables", true}} {
+		ruleArgs := egressRuleArgs(rules, f.allowedAddresses, family.ipv6)
+		if err := setChainRules(family.command, ruleArgs); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// setChainRules replaces the contents of the egress chain with the
+// specified rules, and ensures that the chain is only jumped to from
+// the OUTPUT chain when there are rules to enforce.
+//
+// The new rules are built in a separate chain, which is jumped to
+// before the old chain is removed, so that the old rules stay in force
+// until the new ones are complete, and remain in force if they cannot
+// be set.
+func setChainRules(command string, ruleArgs [][]string) error {
+	if len(ruleArgs) == 0 {
+		if err := removeChain(command, newEgressChain); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(removeChain(command, egressChain))
+	}
+
+	// The new chain is only jumped to once it is complete, so a jump
+	// to it means an earlier attempt failed while swapping the chains.
+	// The swap is finished first, so that the chain in force is never
+	// flushed below.
+	if jumpExists(command, newEgressChain) {
+		if err := swapChains(command); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	// Creating the chain fails if it is left over from an earlier
+	// attempt, which is fine; any other problem will be reported
+	// when flushing it.
+	runCommand(command, "-w", "-N", newEgressChain)
+	if err := runCommand(command, "-w", "-F", newEgressChain); err != nil {
+		return errors.Trace(err)
+	}
+	for _, args := range ruleArgs {
+		args = append([]string{"-w", "-A", newEgressChain}, args...)
+		if err := runCommand(command, args...); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := runCommand(command, "-w", "-I", "OUTPUT", "-j", newEgressChain); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(swapChains(command))
+}
+
+// swapChains replaces the egress chain with the new egress chain,
+// which must already be jumped to from the OUTPUT chain.
+func swapChains(command string) error {
+	if err := removeChain(command, egressChain); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(runCommand(command, "-w", "-E", newEgressChain, egressChain))
+}
+
+// removeChain removes the named chain, and the jump to it from the
+// OUTPUT chain, if they exist.
+func removeChain(command, chain string) error {
+	if jumpExists(command, chain) {
+		if err := runCommand(command, "-w", "-D", "OUTPUT", "-j", chain); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if runCommand(command, "-w", "-S", chain) != nil {
+		// The chain does not exist.
+		return nil
+	}
+	if err := runCommand(command, "-w", "-F", chain); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(runCommand(command, "-w", "-X", chain))
+}
+
+// jumpExists reports whether the OUTPUT chain jumps to the named chain.
+func jumpExists(command, chain string) bool {
+	return runCommand(command, "-w", "-C", "OUTPUT", "-j", chain) == nil
+}
+
+// egressRuleArgs returns the arguments for each iptables rule in the
+// egress chain that enforces the specified egress rules, for either
+// IPv4 or IPv6 destinations.
+//
+// Traffic that is allowed by any rule is accepted before any traffic
+// is dropped, so that a destination covered by several rules may be
+// sent traffic on the ports allowed by any of them.
+func egressRuleArgs(rules []network.EgressRule, allowedAddresses []string, ipv6 bool) [][]string {
+	var accept [][]string
+	covered := make(map[string]bool)
+	for _, rule := range rules {
+		for _, cidr := range rule.DestinationCIDRs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil || (ip.To4() == nil) != ipv6 {
+				continue
+			}
+			covered[cidr] = true
+			for _, portRange := range rule.Ports {
+				accept = append(accept, []string{
+					"-d", cidr,
+					"-p", portRange.Protocol,
+					"--dport", portRangeArg(portRange),
+					"-j", "ACCEPT",
+				})
+			}
+		}
+	}
+	if len(covered) == 0 {
+		return nil
+	}
+
+	args := [][]string{
+		{"-o", "lo", "-j", "ACCEPT"},
+		{"-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"},
+	}
+	for _, addr := range allowedAddresses {
+		ip := net.ParseIP(addr)
+		if ip == nil || (ip.To4() == nil) != ipv6 {
+			continue
+		}
+		args = append(args, []string{"-d", ip.String(), "-j", "ACCEPT"})
+	}
+	args = append(args, accept...)
+
+	cidrs := make([]string, 0, len(covered))
+	for cidr := range covered {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	for _, cidr := range cidrs {
+		args = append(args, []string{"-d", cidr, "-j", "DROP"})
+	}
+	return args
+}
+
+func portRangeArg(portRange network.PortRange) string {
+	if portRange.FromPort == portRange.ToPort {
+		return fmt.Sprint(portRange.FromPort)
+	}
+	return fmt.Sprintf("%d:%d", portRange.FromPort, portRange.ToPort)
+}
@@ -0,0 +1,210 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/machinefirewaller"
+)
+
+type IptablesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&IptablesSuite{})
+
+var testEgressRules = []network.EgressRule{{
+	Name:             "dmz-to-app",
+	DestinationCIDRs: []string{"10.0.2.0/24", "2001:db8::/32"},
+	Ports: []network.PortRange{
+		network.MustParsePortRange("443/tcp"),
+		network.MustParsePortRange("8000-8080/tcp"),
+	},
+}, {
+	Name:             "dns",
+	DestinationCIDRs: []string{"0.0.0.0/0"},
+	Ports:            []network.PortRange{network.MustParsePortRange("53/udp")},
+}}
+
+func (s *IptablesSuite) TestEgressRuleArgs(c *gc.C) {
+	args := machinefirewaller.EgressRuleArgs(testEgressRules, []string{"10.0.0.1", "2001:db8::1", "controller"}, false)
+	c.Assert(args, jc.DeepEquals, [][]string{
+		{"-o", "lo", "-j", "ACCEPT"},
+		{"-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"},
+		{"-d", "10.0.0.1", "-j", "ACCEPT"},
+		{"-d", "10.0.2.0/24", "-p", "tcp", "--dport", "443", "-j", "ACCEPT"},
+		{"-d", "10.0.2.0/24", "-p", "tcp", "--dport", "8000:8080", "-j", "ACCEPT"},
+		{"-d", "0.0.0.0/0", "-p", "udp", "--dport", "53", "-j", "ACCEPT"},
+		{"-d", "0.0.0.0/0", "-j", "DROP"},
+		{"-d", "10.0.2.0/24", "-j", "DROP"},
+	})
+
+	args = machinefirewaller.EgressRuleArgs(testEgressRules, []string{"10.0.0.1", "2001:db8::1"}, true)
+	c.Assert(args, jc.DeepEquals, [][]string{
+		{"-o", "lo", "-j", "ACCEPT"},
+		{"-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"},
+		{"-d", "2001:db8::1", "-j", "ACCEPT"},
+		{"-d", "2001:db8::/32", "-p", "tcp", "--dport", "443", "-j", "ACCEPT"},
+		{"-d", "2001:db8::/32", "-p", "tcp", "--dport", "8000:8080", "-j", "ACCEPT"},
+		{"-d", "2001:db8::/32", "-j", "DROP"},
+	})
+}
+
+func (s *IptablesSuite) TestEgressRuleArgsNoRules(c *gc.C) {
+	args := machinefirewaller.EgressRuleArgs(nil, []string{"10.0.0.1"}, false)
+	c.Assert(args, gc.HasLen, 0)
+}
+
+// patchIptables records the iptables commands run, failing those
+// that check for, list or create chains or jumps that are not in
+// existing.
+func (s *IptablesSuite) patchIptables(existing ...string) *[]string {
+	var commands []string
+	s.PatchValue(machinefirewaller.RunCommand, func(name string, args ...string) error {
+		command := strings.Join(append([]string{name}, args...), " ")
+		commands = append(commands, command)
+		chain := args[len(args)-1]
+		switch args[1] {
+		case "-C", "-S":
+			for _, e := range existing {
+				if e == chain {
+					return nil
+				}
+			}
+			return errors.New("no such chain")
+		case "-N":
+			for _, e := range existing {
+				if e == chain {
+					return errors.New("chain already exists")
+				}
+			}
+		}
+		return nil
+	})
+	return &commands
+}
+
+func (s *IptablesSuite) TestSetEgressRules(c *gc.C) {
+	commands := s.patchIptables()
+
+	firewall := machinefirewaller.NewIptablesFirewall([]string{"10.0.0.1"})
+	err := firewall.SetEgressRules(testEgressRules[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*commands, jc.DeepEquals, []string{
+		"iptables -w -C OUTPUT -j juju-egress-new",
+		"iptables -w -N juju-egress-new",
+		"iptables -w -F juju-egress-new",
+		"iptables -w -A juju-egress-new -o lo -j ACCEPT",
+		"iptables -w -A juju-egress-new -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+		"iptables -w -A juju-egress-new -d 10.0.0.1 -j ACCEPT",
+		"iptables -w -A juju-egress-new -d 0.0.0.0/0 -p udp --dport 53 -j ACCEPT",
+		"iptables -w -A juju-egress-new -d 0.0.0.0/0 -j DROP",
+		"iptables -w -I OUTPUT -j juju-egress-new",
+		"iptables -w -C OUTPUT -j juju-egress",
+		"iptables -w -S juju-egress",
+		"iptables -w -E juju-egress-new juju-egress",
+		"ip6tables -w -C OUTPUT -j juju-egress-new",
+		"ip6tables -w -S juju-egress-new",
+		"ip6tables -w -C OUTPUT -j juju-egress",
+		"ip6tables -w -S juju-egress",
+	})
+}
+
+func (s *IptablesSuite) TestSetEgressRulesReplacesChain(c *gc.C) {
+	commands := s.patchIptables("juju-egress")
+
+	firewall := machinefirewaller.NewIptablesFirewall(nil)
+	err := firewall.SetEgressRules(testEgressRules[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	// The old chain is only removed once the new one is in place.
+	c.Assert((*commands)[:11], jc.DeepEquals, []string{
+		"iptables -w -C OUTPUT -j juju-egress-new",
+		"iptables -w -N juju-egress-new",
+		"iptables -w -F juju-egress-new",
+		"iptables -w -A juju-egress-new -o lo -j ACCEPT",
+		"iptables -w -A juju-egress-new -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+		"iptables -w -A juju-egress-new -d 0.0.0.0/0 -p udp --dport 53 -j ACCEPT",
+		"iptables -w -A juju-egress-new -d 0.0.0.0/0 -j DROP",
+		"iptables -w -I OUTPUT -j juju-egress-new",
+		"iptables -w -C OUTPUT -j juju-egress",
+		"iptables -w -D OUTPUT -j juju-egress",
+		"iptables -w -S juju-egress",
+	})
+	c.Assert((*commands)[11:14], jc.DeepEquals, []string{
+		"iptables -w -F juju-egress",
+		"iptables -w -X juju-egress",
+		"iptables -w -E juju-egress-new juju-egress",
+	})
+}
+
+func (s *IptablesSuite) TestSetEgressRulesRemovesChains(c *gc.C) {
+	commands := s.patchIptables("juju-egress", "juju-egress-new")
+
+	firewall := machinefirewaller.NewIptablesFirewall(nil)
+	err := firewall.SetEgressRules(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var expect []string
+	for _, command := range []string{"iptables", "ip6tables"} {
+		for _, chain := range []string{"juju-egress-new", "juju-egress"} {
+			expect = append(expect,
+				command+" -w -C OUTPUT -j "+chain,
+				command+" -w -D OUTPUT -j "+chain,
+				command+" -w -S "+chain,
+				command+" -w -F "+chain,
+				command+" -w -X "+chain,
+			)
+		}
+	}
+	c.Assert(*commands, jc.DeepEquals, expect)
+}
+
+func (s *IptablesSuite) TestSetEgressRulesErrorKeepsOldChain(c *gc.C) {
+	var commands []string
+	s.PatchValue(machinefirewaller.RunCommand, func(name string, args ...string) error {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		switch args[1] {
+		case "-C":
+			return errors.New("no such rule")
+		case "-A":
+			return errors.New("boom")
+		}
+		return nil
+	})
+	firewall := machinefirewaller.NewIptablesFirewall(nil)
+	err := firewall.SetEgressRules(testEgressRules)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(commands, jc.DeepEquals, []string{
+		"iptables -w -C OUTPUT -j juju-egress-new",
+		"iptables -w -N juju-egress-new",
+		"iptables -w -F juju-egress-new",
+		"iptables -w -A juju-egress-new -o lo -j ACCEPT",
+	})
+}
+
+func (s *IptablesSuite) TestSetEgressRulesFinishesInterruptedSwap(c *gc.C) {
+	// An earlier attempt removed the old chain, but failed to
+	// rename the new one, which is the only one jumped to.
+	commands := s.patchIptables("juju-egress-new")
+
+	firewall := machinefirewaller.NewIptablesFirewall(nil)
+	err := firewall.SetEgressRules(testEgressRules[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert((*commands)[:6], jc.DeepEquals, []string{
+		"iptables -w -C OUTPUT -j juju-egress-new",
+		"iptables -w -C OUTPUT -j juju-egress",
+		"iptables -w -S juju-egress",
+		"iptables -w -E juju-egress-new juju-egress",
+		// The chain in force was renamed before the
+		// new chain is created and flushed.
+		"iptables -w -N juju-egress-new",
+		"iptables -w -F juju-egress-new",
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller
+
+import (
+	"net"
+	"os/exec"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	apimachinefirewaller "github.com/juju/juju/api/machinefirewaller"
+	"github.com/juju/juju/cmd/jujud/agent/util"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig util.AgentApiManifoldConfig
+
+// Manifold returns a dependency manifold that runs a machine firewaller
+// worker, using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := util.AgentApiManifoldConfig(config)
+	return util.AgentApiManifold(typedConfig, newWorker)
+}
+
+// lookPath is a variable so it can be patched in tests.
+var lookPath = exec.LookPath
+
+// newWorker trivially wraps NewWorker for use in a util.AgentApiManifold.
+func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	agentConfig := a.CurrentConfig()
+	tag, ok := agentConfig.Tag().(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected MachineTag, got %#v", agentConfig.Tag())
+	}
+	if _, err := lookPath("iptables"); err != nil {
+		logger.Infof("iptables not available, egress rules will not be enforced")
+		return nil, dependency.ErrUninstall
+	}
+
+	// Always allow traffic to the controllers, so that
+	// the agent can never be cut off by egress rules.
+	apiAddresses, err := agentConfig.APIAddresses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var allowed []string
+	for _, addr := range apiAddresses {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		allowed = append(allowed, host)
+	}
+
+	return NewWorker(Config{
+		Tag:      tag,
+		Facade:   apimachinefirewaller.NewClient(apiCaller),
+		Firewall: NewIptablesFirewall(allowed),
+	})
+}
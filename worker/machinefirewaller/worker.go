@@ -0,0 +1,128 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machinefirewaller provides a worker that enforces the
+// model-level firewall rules which apply to traffic sent by the
+// machine it runs on.
+//
+// The controller is asked to enforce the rules with the provider's
+// firewall first; if the provider cannot, they are enforced on the
+// machine itself.
+package machinefirewaller
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.machinefirewaller")
+
+// Facade exposes the controller functionality needed by the worker.
+type Facade interface {
+	WatchFirewallRules(names.MachineTag) (watcher.NotifyWatcher, error)
+	EgressRules(names.MachineTag) ([]network.EgressRule, error)
+	SetProviderEgressRules(names.MachineTag) (bool, error)
+}
+
+// Firewall is the interface used by the worker to enforce egress
+// rules on the local machine.
+type Firewall interface {
+	// SetEgressRules replaces any previously set egress
+	// rules with the ones specified.
+	SetEgressRules([]network.EgressRule) error
+}
+
+// Config holds the configuration and dependencies for a worker.
+type Config struct {
+	Tag      names.MachineTag
+	Facade   Facade
+	Firewall Firewall
+}
+
+// Validate returns an error if the config cannot be expected
+// to drive a functional worker.
+func (config Config) Validate() error {
+	if config.Tag == (names.MachineTag{}) {
+		return errors.NotValidf("empty Tag")
+	}
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Firewall == nil {
+		return errors.NotValidf("nil Firewall")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that enforces the egress rules for
+// the configured machine, updating them whenever they change.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &handler{config: config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// handler implements watcher.NotifyHandler.
+type handler struct {
+	config Config
+
+	// applied records whether rules have been set since the worker
+	// started. The rules are always set the first time through, so
+	// that any left over from a previous run are replaced.
+	applied bool
+	rules   []network.EgressRule
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (h *handler) SetUp() (watcher.NotifyWatcher, error) {
+	return h.config.Facade.WatchFirewallRules(h.config.Tag)
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (h *handler) Handle(_ <-chan struct{}) error {
+	rules, err := h.config.Facade.EgressRules(h.config.Tag)
+	if err != nil {
+		return errors.Annotate(err, "cannot get egress rules")
+	}
+	if h.applied && reflect.DeepEqual(rules, h.rules) {
+		logger.Tracef("no changes to egress rules detected")
+		return nil
+	}
+	logger.Infof("setting egress rules: %v", rules)
+	enforced, err := h.config.Facade.SetProviderEgressRules(h.config.Tag)
+	if err != nil {
+		return errors.Annotate(err, "cannot set egress rules with the provider")
+	}
+	localRules := rules
+	if enforced {
+		// The provider enforces the rules, so any
+		// set on the machine previously are removed.
+		logger.Infof("egress rules enforced by the provider")
+		localRules = nil
+	}
+	if err := h.config.Firewall.SetEgressRules(localRules); err != nil {
+		return errors.Annotate(err, "cannot set egress rules")
+	}
+	h.applied = true
+	h.rules = rules
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (h *handler) TearDown() error {
+	return nil
+}
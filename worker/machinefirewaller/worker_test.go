@@ -0,0 +1,233 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinefirewaller_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/machinefirewaller"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	facade   *mockFacade
+	firewall *mockFirewall
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &mockFacade{
+		watcher: newMockNotifyWatcher(),
+		rules:   testEgressRules,
+	}
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(worker.Stop(s.facade.watcher), jc.ErrorIsNil)
+	})
+	s.firewall = &mockFirewall{set: make(chan []network.EgressRule, 10)}
+}
+
+func (s *WorkerSuite) config() machinefirewaller.Config {
+	return machinefirewaller.Config{
+		Tag:      names.NewMachineTag("0"),
+		Facade:   s.facade,
+		Firewall: s.firewall,
+	}
+}
+
+func (s *WorkerSuite) assertSet(c *gc.C, expect []network.EgressRule) {
+	select {
+	case rules := <-s.firewall.set:
+		c.Assert(rules, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for egress rules to be set")
+	}
+}
+
+func (s *WorkerSuite) assertNotSet(c *gc.C) {
+	select {
+	case rules := <-s.firewall.set:
+		c.Fatalf("unexpected egress rules set: %v", rules)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.Facade = nil
+	_, err := machinefirewaller.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "nil Facade not valid")
+
+	config = s.config()
+	config.Firewall = nil
+	_, err = machinefirewaller.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "nil Firewall not valid")
+
+	config = s.config()
+	config.Tag = names.MachineTag{}
+	_, err = machinefirewaller.NewWorker(config)
+	c.Assert(err, gc.ErrorMatches, "empty Tag not valid")
+}
+
+func (s *WorkerSuite) TestSetsRulesOnChange(c *gc.C) {
+	w, err := machinefirewaller.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, testEgressRules)
+
+	// Unchanged rules are not set again.
+	s.facade.watcher.changes <- struct{}{}
+	s.assertNotSet(c)
+
+	s.facade.setRules(nil)
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, nil)
+
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) TestAlwaysSetsRulesInitially(c *gc.C) {
+	s.facade.setRules(nil)
+	w, err := machinefirewaller.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, nil)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) TestSetRulesError(c *gc.C) {
+	s.firewall.err = errors.New("boom")
+	w, err := machinefirewaller.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, testEgressRules)
+	err = w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot set egress rules: boom")
+}
+
+func (s *WorkerSuite) TestProviderEnforcesRules(c *gc.C) {
+	s.facade.setEnforced(true, nil)
+	w, err := machinefirewaller.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	// Rules enforced by the provider are removed from the machine.
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, nil)
+
+	// If the provider can no longer enforce them, they
+	// are enforced on the machine instead.
+	s.facade.setEnforced(false, nil)
+	s.facade.setRules(testEgressRules[:1])
+	s.facade.watcher.changes <- struct{}{}
+	s.assertSet(c, testEgressRules[:1])
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) TestProviderEgressRulesError(c *gc.C) {
+	s.facade.setEnforced(false, errors.New("boom"))
+	w, err := machinefirewaller.NewWorker(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	s.facade.watcher.changes <- struct{}{}
+	err = w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot set egress rules with the provider: boom")
+	s.assertNotSet(c)
+}
+
+type mockFacade struct {
+	watcher *mockNotifyWatcher
+
+	mu       sync.Mutex
+	rules    []network.EgressRule
+	enforced bool
+	err      error
+}
+
+func (f *mockFacade) setEnforced(enforced bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enforced = enforced
+	f.err = err
+}
+
+func (f *mockFacade) setRules(rules []network.EgressRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = rules
+}
+
+func (f *mockFacade) WatchFirewallRules(tag names.MachineTag) (watcher.NotifyWatcher, error) {
+	if tag != names.NewMachineTag("0") {
+		return nil, errors.Errorf("unexpected tag %v", tag)
+	}
+	return f.watcher, nil
+}
+
+func (f *mockFacade) EgressRules(tag names.MachineTag) ([]network.EgressRule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rules, nil
+}
+
+func (f *mockFacade) SetProviderEgressRules(tag names.MachineTag) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.enforced, f.err
+}
+
+type mockFirewall struct {
+	set chan []network.EgressRule
+	err error
+}
+
+func (f *mockFirewall) SetEgressRules(rules []network.EgressRule) error {
+	f.set <- rules
+	return f.err
+}
+
+type mockNotifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newMockNotifyWatcher() *mockNotifyWatcher {
+	w := &mockNotifyWatcher{changes: make(chan struct{})}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return w.changes
+}
+
+func (w *mockNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *mockNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
@@ -83,6 +83,10 @@ func (ctx *limitedContext) ResetExecutionSetUnitStatus() {}
 // Id implements runner.Context.
 func (ctx *limitedContext) Id() string { return ctx.id }
 
+// HookTimeout implements runner.Context; hooks run in this context are
+// not subject to the service's hook timeout.
+func (ctx *limitedContext) HookTimeout() time.Duration { return 0 }
+
 // Prepare implements runner.Context.
 func (ctx *limitedContext) Prepare() error {
 	return jujuc.ErrRestrictedContext
@@ -85,6 +85,10 @@ func (ctx *hookContext) ResetExecutionSetUnitStatus() {}
 // Id implements runner.Context.
 func (ctx *hookContext) Id() string { return ctx.id }
 
+// HookTimeout implements runner.Context; hooks run in this context are
+// not subject to the service's hook timeout.
+func (ctx *hookContext) HookTimeout() time.Duration { return 0 }
+
 // Prepare implements runner.Context.
 func (ctx *hookContext) Prepare() error {
 	return jujuc.ErrRestrictedContext
@@ -0,0 +1,250 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package peergrouper
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+// MemberReport describes a controller machine's membership of the
+// mongo replica set, as seen by both the peergrouper and mongo.
+type MemberReport struct {
+	// MachineId holds the id of the controller machine. It is empty
+	// for replica set members that have no corresponding machine.
+	MachineId string
+
+	// ReplicaSetId holds the id of the replica set member, or -1 if
+	// the machine is not a member.
+	ReplicaSetId int
+
+	// Address holds the address of the replica set member.
+	Address string
+
+	// WantsVote and HasVote hold the voting status of the machine
+	// as recorded in state; Voting reports whether the member
+	// actually has a vote in the replica set.
+	WantsVote bool
+	HasVote   bool
+	Voting    bool
+
+	// State holds the replica set member state, such as PRIMARY
+	// or SECONDARY.
+	State string
+
+	// Healthy reports whether mongo considers the member to be up.
+	Healthy bool
+
+	// Lag holds how far the member's replication trails the primary.
+	Lag time.Duration
+
+	// LastHeartbeat holds when the member last responded to a
+	// heartbeat. It is zero for the member reporting the status.
+	LastHeartbeat time.Time
+
+	// Message holds the member's most recent error or status
+	// message, if any.
+	Message string
+
+	// Problems holds the reasons the peergrouper cannot reconcile
+	// the member with its machine, if any.
+	Problems []string
+}
+
+// Report holds the status of the controller replica set.
+type Report struct {
+	Members []MemberReport
+
+	// Problems holds issues with the replica set as a whole.
+	Problems []string
+}
+
+// memberStatus holds the parts of a member's replSetGetStatus output
+// that are not exposed by replicaset.MemberStatus.
+type memberStatus struct {
+	Id                   int                    `bson:"_id"`
+	Name                 string                 `bson:"name"`
+	Healthy              bool                   `bson:"health"`
+	State                replicaset.MemberState `bson:"state"`
+	Self                 bool                   `bson:"self"`
+	ErrMsg               string                 `bson:"errmsg"`
+	LastHeartbeatMessage string                 `bson:"lastHeartbeatMessage"`
+	OptimeDate           time.Time              `bson:"optimeDate"`
+	LastHeartbeat        time.Time              `bson:"lastHeartbeat"`
+}
+
+type replicaSetStatus struct {
+	Members []memberStatus `bson:"members"`
+}
+
+// reportMachine holds what the report needs to know about a
+// controller machine.
+type reportMachine struct {
+	id         string
+	wantsVote  bool
+	hasVote    bool
+	hasAddress bool
+}
+
+// NewReport returns the status of the controller replica set,
+// together with any differences between it and the controller
+// machines that the peergrouper cannot reconcile.
+func NewReport(st *state.State) (*Report, error) {
+	info, err := st.ControllerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var machines []reportMachine
+	for _, id := range info.MachineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machines = append(machines, reportMachine{
+			id:         m.Id(),
+			wantsVote:  m.WantsVote(),
+			hasVote:    m.HasVote(),
+			hasAddress: len(m.Addresses()) > 0,
+		})
+	}
+	session := st.MongoSession()
+	members, err := replicaset.CurrentMembers(session)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get replica set members")
+	}
+	statuses, err := currentStatus(session)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get replica set status")
+	}
+	return buildReport(machines, members, statuses), nil
+}
+
+func currentStatus(session *mgo.Session) ([]memberStatus, error) {
+	var status replicaSetStatus
+	err := session.DB("admin").Run(bson.D{{Name: "replSetGetStatus", Value: 1}}, &status)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return status.Members, nil
+}
+
+// buildReport matches replica set members to machines in the same
+// way as the peergrouper, and reports where they disagree.
+func buildReport(machines []reportMachine, members []replicaset.Member, statuses []memberStatus) *Report {
+	statusById := make(map[int]memberStatus)
+	var primaryOptime time.Time
+	for _, status := range statuses {
+		statusById[status.Id] = status
+		if status.State == replicaset.PrimaryState {
+			primaryOptime = status.OptimeDate
+		}
+	}
+	memberByMachine := make(map[string]replicaset.Member)
+	var extra []replicaset.Member
+	machineIds := make(map[string]bool)
+	for _, m := range machines {
+		machineIds[m.id] = true
+	}
+	for _, member := range members {
+		if id, ok := member.Tags[jujuMachineKey]; ok && machineIds[id] {
+			memberByMachine[id] = member
+		} else {
+			extra = append(extra, member)
+		}
+	}
+
+	report := &Report{}
+	fillStatus := func(r *MemberReport, member replicaset.Member) {
+		r.ReplicaSetId = member.Id
+		r.Address = member.Address
+		r.Voting = isVotingMember(&member)
+		status, ok := statusById[member.Id]
+		if !ok {
+			r.Problems = append(r.Problems, "no status reported by mongo")
+			return
+		}
+		r.State = status.State.String()
+		r.Healthy = status.Healthy
+		r.LastHeartbeat = status.LastHeartbeat
+		r.Message = status.ErrMsg
+		if r.Message == "" {
+			r.Message = status.LastHeartbeatMessage
+		}
+		if !primaryOptime.IsZero() && !status.OptimeDate.IsZero() && status.OptimeDate.Before(primaryOptime) {
+			r.Lag = primaryOptime.Sub(status.OptimeDate)
+		}
+		if !status.Healthy {
+			r.Problems = append(r.Problems, "member is not healthy")
+		}
+	}
+
+	voters := 0
+	for _, m := range machines {
+		r := MemberReport{
+			MachineId:    m.id,
+			ReplicaSetId: -1,
+			WantsVote:    m.wantsVote,
+			HasVote:      m.hasVote,
+		}
+		member, ok := memberByMachine[m.id]
+		if ok {
+			fillStatus(&r, member)
+		} else if !m.hasAddress {
+			r.Problems = append(r.Problems, "machine has no address, so cannot join the replica set")
+		} else {
+			r.Problems = append(r.Problems, "machine is not yet a replica set member")
+		}
+		if r.Voting {
+			voters++
+		}
+		if r.HasVote != r.Voting {
+			r.Problems = append(r.Problems, fmt.Sprintf("has-vote is %v but replica set vote is %v", r.HasVote, r.Voting))
+		}
+		if r.WantsVote && !r.Voting && ok && r.State != "" &&
+			!isReady(replicaset.MemberStatus{Healthy: r.Healthy, State: statusById[member.Id].State}) {
+			r.Problems = append(r.Problems, fmt.Sprintf("wants vote but member is not ready (%s)", r.State))
+		}
+		report.Members = append(report.Members, r)
+	}
+	for _, member := range extra {
+		r := MemberReport{}
+		fillStatus(&r, member)
+		if r.Voting {
+			voters++
+			r.Problems = append(r.Problems, "voting member has no controller machine")
+		} else {
+			r.Problems = append(r.Problems, "member has no controller machine and will be removed")
+		}
+		report.Members = append(report.Members, r)
+	}
+	sort.Sort(byMachineId(report.Members))
+
+	if voters%2 == 0 {
+		report.Problems = append(report.Problems, fmt.Sprintf("even number of voting members (%d)", voters))
+	}
+	return report
+}
+
+type byMachineId []MemberReport
+
+func (l byMachineId) Len() int      { return len(l) }
+func (l byMachineId) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l byMachineId) Less(i, j int) bool {
+	if l[i].MachineId != l[j].MachineId {
+		// Members without a machine sort last.
+		if l[i].MachineId == "" || l[j].MachineId == "" {
+			return l[j].MachineId == ""
+		}
+		return l[i].MachineId < l[j].MachineId
+	}
+	return l[i].ReplicaSetId < l[j].ReplicaSetId
+}
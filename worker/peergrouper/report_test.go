@@ -0,0 +1,121 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package peergrouper
+
+import (
+	"time"
+
+	"github.com/juju/replicaset"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type reportSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&reportSuite{})
+
+func reportMember(id int, machineId string, voting bool) replicaset.Member {
+	member := replicaset.Member{
+		Id:      id,
+		Address: "0.1.2." + machineId + ":1234",
+	}
+	if machineId != "" {
+		member.Tags = map[string]string{jujuMachineKey: machineId}
+	}
+	setMemberVoting(&member, voting)
+	return member
+}
+
+func (s *reportSuite) TestHealthy(c *gc.C) {
+	now := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	machines := []reportMachine{
+		{id: "0", wantsVote: true, hasVote: true, hasAddress: true},
+		{id: "1", wantsVote: true, hasVote: true, hasAddress: true},
+		{id: "2", wantsVote: true, hasVote: true, hasAddress: true},
+	}
+	members := []replicaset.Member{
+		reportMember(1, "0", true),
+		reportMember(2, "1", true),
+		reportMember(3, "2", true),
+	}
+	statuses := []memberStatus{
+		{Id: 1, Healthy: true, State: replicaset.PrimaryState, Self: true, OptimeDate: now},
+		{Id: 2, Healthy: true, State: replicaset.SecondaryState, OptimeDate: now.Add(-2 * time.Second), LastHeartbeat: now},
+		{Id: 3, Healthy: true, State: replicaset.SecondaryState, OptimeDate: now, LastHeartbeat: now},
+	}
+	report := buildReport(machines, members, statuses)
+	c.Assert(report.Problems, gc.HasLen, 0)
+	c.Assert(report.Members, jc.DeepEquals, []MemberReport{{
+		MachineId:    "0",
+		ReplicaSetId: 1,
+		Address:      "0.1.2.0:1234",
+		WantsVote:    true,
+		HasVote:      true,
+		Voting:       true,
+		State:        "PRIMARY",
+		Healthy:      true,
+	}, {
+		MachineId:     "1",
+		ReplicaSetId:  2,
+		Address:       "0.1.2.1:1234",
+		WantsVote:     true,
+		HasVote:       true,
+		Voting:        true,
+		State:         "SECONDARY",
+		Healthy:       true,
+		Lag:           2 * time.Second,
+		LastHeartbeat: now,
+	}, {
+		MachineId:     "2",
+		ReplicaSetId:  3,
+		Address:       "0.1.2.2:1234",
+		WantsVote:     true,
+		HasVote:       true,
+		Voting:        true,
+		State:         "SECONDARY",
+		Healthy:       true,
+		LastHeartbeat: now,
+	}})
+}
+
+func (s *reportSuite) TestProblems(c *gc.C) {
+	machines := []reportMachine{
+		{id: "0", wantsVote: true, hasVote: true, hasAddress: true},
+		{id: "1", wantsVote: true, hasVote: false, hasAddress: true},
+		{id: "2", wantsVote: true, hasVote: false, hasAddress: false},
+		{id: "3", wantsVote: false, hasVote: true, hasAddress: true},
+	}
+	members := []replicaset.Member{
+		reportMember(1, "0", true),
+		reportMember(2, "1", false),
+		reportMember(3, "3", false),
+		reportMember(4, "", true),
+	}
+	statuses := []memberStatus{
+		{Id: 1, Healthy: true, State: replicaset.PrimaryState},
+		{Id: 2, Healthy: true, State: replicaset.RecoveringState, ErrMsg: "still syncing"},
+		{Id: 3, Healthy: false, State: replicaset.DownState},
+		{Id: 4, Healthy: true, State: replicaset.SecondaryState},
+	}
+	report := buildReport(machines, members, statuses)
+	c.Assert(report.Problems, jc.DeepEquals, []string{"even number of voting members (2)"})
+
+	problems := make(map[string][]string)
+	for _, m := range report.Members {
+		problems[m.MachineId] = m.Problems
+	}
+	c.Assert(problems, jc.DeepEquals, map[string][]string{
+		"0": nil,
+		"1": {"wants vote but member is not ready (RECOVERING)"},
+		"2": {"machine has no address, so cannot join the replica set"},
+		"3": {"member is not healthy", "has-vote is true but replica set vote is false"},
+		"":  {"voting member has no controller machine"},
+	})
+	c.Assert(report.Members[1].Message, gc.Equals, "still syncing")
+	c.Assert(report.Members[4].ReplicaSetId, gc.Equals, 4)
+}
@@ -56,7 +56,7 @@ func (s *ManifoldSuite) SetUpSuite(c *gc.C) {
 		return func(wc retrystrategy.WorkerConfig) (worker.Worker, error) {
 			c.Assert(wc.Facade, gc.Equals, s.fakeFacade)
 			c.Assert(wc.AgentTag, gc.Equals, fakeTag)
-			c.Assert(wc.RetryStrategy, gc.Equals, fakeStrategy)
+			c.Assert(wc.RetryStrategy, jc.DeepEquals, fakeStrategy)
 			return w, err
 		}
 	}
@@ -154,7 +154,7 @@ func (s *ManifoldSuite) TestOutputSuccess(c *gc.C) {
 	var out params.RetryStrategy
 	err = manifold.Output(w, &out)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(out, gc.Equals, fakeStrategy)
+	c.Assert(out, jc.DeepEquals, fakeStrategy)
 }
 
 func (s *ManifoldSuite) TestOutputBadInput(c *gc.C) {
@@ -170,7 +170,7 @@ func (s *ManifoldSuite) TestOutputBadInput(c *gc.C) {
 
 	var out params.RetryStrategy
 	err = manifold.Output(w, &out)
-	c.Assert(out, gc.Equals, params.RetryStrategy{})
+	c.Assert(out, jc.DeepEquals, params.RetryStrategy{})
 	c.Assert(err.Error(), gc.Equals, "in should be a *retryStrategyWorker; is *retrystrategy_test.fakeWorker")
 }
 
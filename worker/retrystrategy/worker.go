@@ -5,6 +5,8 @@
 package retrystrategy
 
 import (
+	"reflect"
+
 	"github.com/juju/errors"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/watcher"
@@ -33,8 +35,7 @@ func (c WorkerConfig) Validate() error {
 	if c.AgentTag == nil {
 		return errors.NotValidf("nil AgentTag")
 	}
-	empty := params.RetryStrategy{}
-	if c.RetryStrategy == empty {
+	if reflect.DeepEqual(c.RetryStrategy, params.RetryStrategy{}) {
 		return errors.NotValidf("empty RetryStrategy")
 	}
 	return nil
@@ -85,7 +86,7 @@ func (h retryStrategyHandler) Handle(_ <-chan struct{}) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if newRetryStrategy != h.config.RetryStrategy {
+	if !reflect.DeepEqual(newRetryStrategy, h.config.RetryStrategy) {
 		return errors.Errorf("bouncing retrystrategy worker to get new values")
 	}
 	return nil
@@ -42,6 +42,7 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan map[string]interface{}
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 
@@ -81,6 +82,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan map[string]interface{}),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 		restartDelay:  restartDelay,
@@ -124,6 +126,27 @@ func (runner *runner) StopWorker(id string) error {
 	return ErrDead
 }
 
+// Report returns a map describing the state of the runner and of each
+// of its workers, including how many times each has been started and
+// the error with which it last exited. It is safe to call concurrently
+// with the runner's other methods; once the runner has stopped, only
+// its state is reported.
+func (runner *runner) Report() map[string]interface{} {
+	reply := make(chan map[string]interface{}, 1)
+	select {
+	case runner.reportc <- reply:
+		select {
+		case report := <-reply:
+			return report
+		case <-runner.tomb.Dead():
+		}
+	case <-runner.tomb.Dead():
+	}
+	return map[string]interface{}{
+		"state": "stopped",
+	}
+}
+
 func (runner *runner) Wait() error {
 	return runner.tomb.Wait()
 }
@@ -144,6 +167,31 @@ type workerInfo struct {
 	worker       Worker
 	restartDelay time.Duration
 	stopping     bool
+
+	// startCount and lastErr record the worker's history, for
+	// inclusion in the runner's report.
+	startCount int
+	lastErr    error
+}
+
+// report returns a map describing the state of the worker.
+func (info *workerInfo) report() map[string]interface{} {
+	state := "starting"
+	switch {
+	case info.stopping:
+		state = "stopping"
+	case info.worker != nil:
+		state = "started"
+	}
+	var lastErr interface{}
+	if info.lastErr != nil {
+		lastErr = info.lastErr.Error()
+	}
+	return map[string]interface{}{
+		"state":       state,
+		"start-count": info.startCount,
+		"error":       lastErr,
+	}
 }
 
 func (runner *runner) run() error {
@@ -179,6 +227,7 @@ func (runner *runner) run() error {
 				workers[req.id] = &workerInfo{
 					start:        req.start,
 					restartDelay: runner.restartDelay,
+					startCount:   1,
 				}
 				go runner.runWorker(0, req.id, req.start)
 				break
@@ -193,6 +242,19 @@ func (runner *runner) run() error {
 			// the new start function.
 			info.start = req.start
 			info.restartDelay = 0
+		case reply := <-runner.reportc:
+			state := "started"
+			if isDying {
+				state = "stopping"
+			}
+			workerReports := make(map[string]interface{})
+			for id, info := range workers {
+				workerReports[id] = info.report()
+			}
+			reply <- map[string]interface{}{
+				"state":   state,
+				"workers": workerReports,
+			}
 		case id := <-runner.stopc:
 			logger.Debugf("stop %q", id)
 			if info := workers[id]; info != nil {
@@ -208,6 +270,8 @@ func (runner *runner) run() error {
 		case info := <-runner.donec:
 			logger.Debugf("%q done: %v", info.id, info.err)
 			workerInfo := workers[info.id]
+			workerInfo.worker = nil
+			workerInfo.lastErr = info.err
 			if !workerInfo.stopping && info.err == nil {
 				logger.Debugf("removing %q from known workers", info.id)
 				delete(workers, info.id)
@@ -238,6 +302,7 @@ func (runner *runner) run() error {
 				break
 			}
 			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
+			workerInfo.startCount++
 			workerInfo.restartDelay = runner.restartDelay
 		}
 	}
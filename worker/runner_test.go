@@ -84,6 +84,46 @@ func (*runnerSuite) TestOneWorkerRestart(c *gc.C) {
 	starter.assertStarted(c, false)
 }
 
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance, time.Millisecond)
+	reporter, ok := runner.(interface {
+		Report() map[string]interface{}
+	})
+	c.Assert(ok, jc.IsTrue)
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	starter.die <- errors.New("an error")
+	starter.assertStarted(c, false)
+	starter.assertStarted(c, true)
+
+	expect := map[string]interface{}{
+		"state": "started",
+		"workers": map[string]interface{}{
+			"id": map[string]interface{}{
+				"state":       "started",
+				"start-count": 2,
+				"error":       "an error",
+			},
+		},
+	}
+	// The runner may not yet have recorded the restarted worker.
+	var report map[string]interface{}
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		report = reporter.Report()
+		if ok, _ := jc.DeepEquals.Check([]interface{}{report, expect}, nil); ok {
+			break
+		}
+	}
+	c.Assert(report, jc.DeepEquals, expect)
+
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	c.Assert(reporter.Report(), jc.DeepEquals, map[string]interface{}{
+		"state": "stopped",
+	})
+}
+
 func (*runnerSuite) TestOneWorkerStartFatalError(c *gc.C) {
 	runner := worker.NewRunner(allFatal, noImportance, time.Millisecond)
 	starter := newTestWorkerStarter()
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/registry"
 	"github.com/juju/juju/watcher"
 )
 
@@ -68,7 +69,10 @@ func filesystemsChanged(ctx *context, changes []string) error {
 // filesystemAttachmentsChanged is called when the lifecycle states of the filesystem
 // attachments with the provided IDs have been seen to have changed.
 func filesystemAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStorageId) error {
-	ids := copyMachineStorageIds(watcherIds)
+	ids, err := filterFilesystemAttachments(ctx, copyMachineStorageIds(watcherIds))
+	if err != nil {
+		return errors.Trace(err)
+	}
 	alive, dying, dead, err := attachmentLife(ctx, ids)
 	if err != nil {
 		return errors.Trace(err)
@@ -106,6 +110,72 @@ func filesystemAttachmentsChanged(ctx *context, watcherIds []watcher.MachineStor
 	return nil
 }
 
+// filterFilesystemAttachments returns the subset of the given filesystem
+// attachment IDs that are managed by this storage provisioner. Attachments
+// of shared filesystems are managed by the storage provisioner of the
+// machine to which they are attached, whereas attachments of other
+// model-scoped filesystems are managed by the model storage provisioner.
+func filterFilesystemAttachments(ctx *context, ids []params.MachineStorageId) ([]params.MachineStorageId, error) {
+	var modelScoped []params.MachineStorageId
+	for _, id := range ids {
+		filesystemTag, err := names.ParseFilesystemTag(id.AttachmentTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := names.FilesystemMachine(filesystemTag); !ok {
+			modelScoped = append(modelScoped, id)
+		}
+	}
+	if len(modelScoped) == 0 {
+		return ids, nil
+	}
+	results, err := ctx.config.Filesystems.FilesystemAttachmentParams(modelScoped)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting filesystem attachment params")
+	}
+	shared := make(map[params.MachineStorageId]bool)
+	for i, result := range results {
+		if result.Error != nil {
+			if params.IsCodeNotFound(result.Error) {
+				// The attachment has been removed; it does not
+				// matter which storage provisioner observes it.
+				continue
+			}
+			return nil, errors.Annotatef(
+				result.Error, "getting parameters for filesystem attachment %v", modelScoped[i],
+			)
+		}
+		provider, err := registry.StorageProvider(storage.ProviderType(result.Result.Provider))
+		if err != nil {
+			return nil, errors.Annotate(err, "getting provider")
+		}
+		shared[modelScoped[i]] = storage.IsSharedFilesystemProvider(provider)
+	}
+	_, machineScoped := ctx.config.Scope.(names.MachineTag)
+	filtered := make([]params.MachineStorageId, 0, len(ids))
+	for _, id := range ids {
+		isShared, ok := shared[id]
+		if ok && isShared != machineScoped {
+			logger.Tracef("ignoring filesystem attachment %v", id)
+			continue
+		}
+		filtered = append(filtered, id)
+	}
+	return filtered, nil
+}
+
+// isSharedFilesystemAttachment reports whether or not the specified
+// filesystem is a shared filesystem attached by this (machine-scoped)
+// storage provisioner. This relies on filterFilesystemAttachments having
+// dropped all other attachments of model-scoped filesystems.
+func isSharedFilesystemAttachment(ctx *context, tag names.FilesystemTag) bool {
+	if _, ok := ctx.config.Scope.(names.MachineTag); !ok {
+		return false
+	}
+	_, ok := names.FilesystemMachine(tag)
+	return !ok
+}
+
 // processDyingFilesystems processes the FilesystemResults for Dying filesystems,
 // removing them from provisioning-pending as necessary.
 func processDyingFilesystems(ctx *context, tags []names.FilesystemTag, filesystemResults []params.FilesystemResult) error {
@@ -158,9 +228,15 @@ func updatePendingFilesystemAttachment(
 	params storage.FilesystemAttachmentParams,
 ) {
 	var incomplete bool
+	shared := isSharedFilesystemAttachment(ctx, params.Filesystem)
 	filesystem, ok := ctx.filesystems[params.Filesystem]
 	if !ok {
-		incomplete = true
+		// Shared filesystems are provisioned by the model's
+		// storage provisioner, so we will never observe them
+		// here. If the filesystem has not been provisioned yet,
+		// the attach operation will refresh the parameters and
+		// retry until it has.
+		incomplete = !shared
 	} else {
 		params.FilesystemId = filesystem.FilesystemId
 		if filesystem.Volume != (names.VolumeTag{}) {
@@ -177,7 +253,7 @@ func updatePendingFilesystemAttachment(
 		watchMachine(ctx, params.Machine)
 		incomplete = true
 	}
-	if params.FilesystemId == "" {
+	if params.FilesystemId == "" && !shared {
 		incomplete = true
 	}
 	if incomplete {
@@ -115,9 +115,20 @@ func createFilesystems(ctx *context, ops map[names.FilesystemTag]*createFilesyst
 
 // attachFilesystems creates filesystem attachments with the specified parameters.
 func attachFilesystems(ctx *context, ops map[params.MachineStorageId]*attachFilesystemOp) error {
+	if err := refreshSharedFilesystemIds(ctx, ops); err != nil {
+		return errors.Trace(err)
+	}
+	var reschedule []scheduleOp
 	filesystemAttachmentParams := make([]storage.FilesystemAttachmentParams, 0, len(ops))
 	for _, op := range ops {
 		args := op.args
+		if args.FilesystemId == "" {
+			// The shared filesystem has not been provisioned
+			// yet; try again later.
+			logger.Debugf("%s not provisioned yet", names.ReadableString(args.Filesystem))
+			reschedule = append(reschedule, op)
+			continue
+		}
 		if args.Path == "" {
 			args.Path = filepath.Join(ctx.config.StorageDir, args.Filesystem.Id())
 		}
@@ -133,7 +144,6 @@ func attachFilesystems(ctx *context, ops map[params.MachineStorageId]*attachFile
 	if err != nil {
 		return errors.Trace(err)
 	}
-	var reschedule []scheduleOp
 	var filesystemAttachments []storage.FilesystemAttachment
 	var statuses []params.EntityStatusArgs
 	for sourceName, filesystemAttachmentParams := range paramsBySource {
@@ -176,13 +186,51 @@ func attachFilesystems(ctx *context, ops map[params.MachineStorageId]*attachFile
 		}
 	}
 	scheduleOperations(ctx, reschedule...)
-	setStatus(ctx, statuses)
+	setStatus(ctx, filterSharedFilesystemStatuses(ctx, statuses))
 	if err := setFilesystemAttachmentInfo(ctx, filesystemAttachments); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
 }
 
+// refreshSharedFilesystemIds updates the parameters of attach operations
+// for shared filesystems that had not been provisioned when the operations
+// were scheduled, with the filesystem IDs recorded since.
+func refreshSharedFilesystemIds(ctx *context, ops map[params.MachineStorageId]*attachFilesystemOp) error {
+	var ids []params.MachineStorageId
+	for id, op := range ops {
+		if op.args.FilesystemId == "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	attachmentParams, err := filesystemAttachmentParams(ctx, ids)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, p := range attachmentParams {
+		ops[ids[i]].args.FilesystemId = p.FilesystemId
+	}
+	return nil
+}
+
+// filterSharedFilesystemStatuses removes the statuses of shared filesystems
+// from the given slice. Shared filesystems are attached to many machines, so
+// their status is managed by the model storage provisioner.
+func filterSharedFilesystemStatuses(ctx *context, statuses []params.EntityStatusArgs) []params.EntityStatusArgs {
+	filtered := make([]params.EntityStatusArgs, 0, len(statuses))
+	for _, s := range statuses {
+		tag, err := names.ParseFilesystemTag(s.Tag)
+		if err == nil && isSharedFilesystemAttachment(ctx, tag) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
 // destroyFilesystems destroys filesystems with the specified parameters.
 func destroyFilesystems(ctx *context, ops map[names.FilesystemTag]*destroyFilesystemOp) error {
 	tags := make([]names.FilesystemTag, 0, len(ops))
@@ -483,8 +531,8 @@ func filesystemAttachmentsFromStorage(in []storage.FilesystemAttachment) []param
 			f.Filesystem.String(),
 			f.Machine.String(),
 			params.FilesystemAttachmentInfo{
-				f.Path,
-				f.ReadOnly,
+				MountPoint: f.Path,
+				ReadOnly:   f.ReadOnly,
 			},
 		}
 	}
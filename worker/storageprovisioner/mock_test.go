@@ -279,6 +279,11 @@ type mockFilesystemAccessor struct {
 
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+
+	// sharedFilesystemId, if set, reports whether the filesystem with
+	// the given tag is created by the "shared" provider, and if so the
+	// filesystem ID to report in its attachment parameters.
+	sharedFilesystemId func(tag string) (string, bool)
 }
 
 func (m *mockFilesystemAccessor) provisionFilesystem(tag names.FilesystemTag) params.Filesystem {
@@ -357,13 +362,20 @@ func (f *mockFilesystemAccessor) FilesystemAttachmentParams(ids []params.Machine
 		// Parameters are returned regardless of whether the attachment
 		// exists; this is to support reattachment.
 		instanceId := f.provisionedMachines[id.MachineTag]
-		result = append(result, params.FilesystemAttachmentParamsResult{Result: params.FilesystemAttachmentParams{
+		attachmentParams := params.FilesystemAttachmentParams{
 			MachineTag:    id.MachineTag,
 			FilesystemTag: id.AttachmentTag,
 			InstanceId:    string(instanceId),
 			Provider:      "dummy",
 			ReadOnly:      true,
-		}})
+		}
+		if f.sharedFilesystemId != nil {
+			if filesystemId, ok := f.sharedFilesystemId(id.AttachmentTag); ok {
+				attachmentParams.Provider = "shared"
+				attachmentParams.FilesystemId = filesystemId
+			}
+		}
+		result = append(result, params.FilesystemAttachmentParamsResult{Result: attachmentParams})
 	}
 	return result, nil
 }
@@ -468,6 +480,16 @@ type dummyProvider struct {
 	validateFilesystemParamsFunc func(storage.FilesystemParams) error
 }
 
+// sharedDummyProvider is a dummyProvider that creates filesystems
+// that may be shared between machines.
+type sharedDummyProvider struct {
+	*dummyProvider
+}
+
+func (sharedDummyProvider) SharedFilesystems() bool {
+	return true
+}
+
 type dummyVolumeSource struct {
 	storage.VolumeSource
 	provider          *dummyProvider
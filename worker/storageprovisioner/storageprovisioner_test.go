@@ -955,6 +955,158 @@ func (s *storageProvisionerSuite) TestFilesystemAttachmentAdded(c *gc.C) {
 	assertNoEvent(c, filesystemAttachmentInfoSet, "filesystem attachment info set")
 }
 
+// registerSharedProvider registers a "shared" storage provider, which
+// creates filesystems that may be shared between machines.
+func (s *storageProvisionerSuite) registerSharedProvider() {
+	registry.RegisterProvider("shared", sharedDummyProvider{&dummyProvider{dynamic: true}})
+	s.AddCleanup(func(*gc.C) {
+		registry.RegisterProvider("shared", nil)
+	})
+}
+
+// filesystemAttachmentLife returns a lifecycle manager that reports
+// all attachments to be alive, and sends the IDs of the attachments
+// whose life is requested on the returned channel.
+func filesystemAttachmentLife() (*mockLifecycleManager, chan interface{}) {
+	attachmentIds := make(chan interface{}, 1)
+	life := &mockLifecycleManager{
+		attachmentLife: func(ids []params.MachineStorageId) ([]params.LifeResult, error) {
+			attachmentIds <- ids
+			results := make([]params.LifeResult, len(ids))
+			for i := range results {
+				results[i].Life = params.Alive
+			}
+			return results, nil
+		},
+	}
+	return life, attachmentIds
+}
+
+func (s *storageProvisionerSuite) TestMachineFilesystemAttachmentsFiltered(c *gc.C) {
+	s.registerSharedProvider()
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	filesystemAccessor.sharedFilesystemId = func(tag string) (string, bool) {
+		return "fs-1", tag == "filesystem-1"
+	}
+	filesystemAttachmentInfoSet := make(chan interface{})
+	filesystemAccessor.setFilesystemAttachmentInfo = func(filesystemAttachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		filesystemAttachmentInfoSet <- filesystemAttachments
+		return make([]params.ErrorResult, len(filesystemAttachments)), nil
+	}
+	life, attachmentIds := filesystemAttachmentLife()
+
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+		life:        life,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// The machine's storage provisioner attaches the shared
+	// filesystem-1, but leaves the attachment of the model-scoped
+	// filesystem-2 to the model storage provisioner.
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "filesystem-1",
+	}, {
+		MachineTag: "machine-0", AttachmentTag: "filesystem-2",
+	}}
+	args.environ.watcher.changes <- struct{}{}
+	ids := waitChannel(c, attachmentIds, "waiting for attachment life")
+	c.Assert(ids, jc.DeepEquals, []params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "filesystem-1",
+	}})
+	attachments := waitChannel(c, filesystemAttachmentInfoSet, "waiting for filesystem attachments to be set")
+	c.Assert(attachments, jc.DeepEquals, []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-1",
+		MachineTag:    "machine-0",
+		Info: params.FilesystemAttachmentInfo{
+			MountPoint: "/srv/fs-1",
+		},
+	}})
+
+	// The status of the shared filesystem is left to the model
+	// storage provisioner, since it may be attached to many machines.
+	c.Assert(args.statusSetter.args, gc.HasLen, 0)
+}
+
+func (s *storageProvisionerSuite) TestModelFilesystemAttachmentsFiltered(c *gc.C) {
+	s.registerSharedProvider()
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.sharedFilesystemId = func(tag string) (string, bool) {
+		return "fs-1", tag == "filesystem-1"
+	}
+	life, attachmentIds := filesystemAttachmentLife()
+
+	args := &workerArgs{filesystems: filesystemAccessor, life: life}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// The model storage provisioner leaves the attachment of the
+	// shared filesystem-1 to the machine's storage provisioner.
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag: "machine-1", AttachmentTag: "filesystem-1",
+	}, {
+		MachineTag: "machine-1", AttachmentTag: "filesystem-2",
+	}}
+	args.environ.watcher.changes <- struct{}{}
+	ids := waitChannel(c, attachmentIds, "waiting for attachment life")
+	c.Assert(ids, jc.DeepEquals, []params.MachineStorageId{{
+		MachineTag: "machine-1", AttachmentTag: "filesystem-2",
+	}})
+}
+
+func (s *storageProvisionerSuite) TestAttachSharedFilesystemWaitsForProvisioning(c *gc.C) {
+	s.registerSharedProvider()
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	// The attachment parameters are requested when filtering the
+	// attachments, when processing them, and when first attempting
+	// to attach; the filesystem is provisioned by the model storage
+	// provisioner only after that.
+	var paramsCalls int
+	filesystemAccessor.sharedFilesystemId = func(tag string) (string, bool) {
+		paramsCalls++
+		if paramsCalls <= 3 {
+			return "", true
+		}
+		return "fs-1", true
+	}
+	filesystemAttachmentInfoSet := make(chan interface{})
+	filesystemAccessor.setFilesystemAttachmentInfo = func(filesystemAttachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		filesystemAttachmentInfoSet <- filesystemAttachments
+		return make([]params.ErrorResult, len(filesystemAttachments)), nil
+	}
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "filesystem-1",
+	}}
+	args.environ.watcher.changes <- struct{}{}
+
+	// The dummy filesystem source panics if asked to attach an
+	// unprovisioned filesystem, so the attachment is only made once
+	// the refreshed parameters hold the filesystem ID.
+	attachments := waitChannel(c, filesystemAttachmentInfoSet, "waiting for filesystem attachments to be set")
+	c.Assert(attachments, jc.DeepEquals, []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-1",
+		MachineTag:    "machine-0",
+		Info: params.FilesystemAttachmentInfo{
+			MountPoint: "/srv/fs-1",
+		},
+	}})
+	c.Assert(paramsCalls, gc.Equals, 4)
+}
+
 func (s *storageProvisionerSuite) TestCreateVolumeBackedFilesystem(c *gc.C) {
 	filesystemInfoSet := make(chan interface{})
 	filesystemAccessor := newMockFilesystemAccessor()
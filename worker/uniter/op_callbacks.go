@@ -12,9 +12,11 @@ import (
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hookstats"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
@@ -91,6 +93,20 @@ func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context)
 	}
 }
 
+// RecordExecution is part of the operation.Callbacks interface. The
+// execution is kept for the uniter's introspection report, and queued
+// to be sent to the controller with the next batch.
+func (opc *operationCallbacks) RecordExecution(record operation.ExecutionRecord) {
+	opc.u.addExecution(hookstats.Execution{
+		Kind:      string(record.Kind),
+		Name:      record.Name,
+		Started:   record.Started,
+		Duration:  record.Duration,
+		ExitCode:  record.ExitCode,
+		ToolCalls: record.ToolCalls,
+	})
+}
+
 // FailAction is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	if !names.IsValidAction(actionId) {
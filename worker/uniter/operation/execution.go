@@ -0,0 +1,66 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"time"
+
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+// ExecutionKind identifies the sort of execution described by an
+// ExecutionRecord.
+type ExecutionKind string
+
+const (
+	// ExecutionHook identifies the execution of a charm hook.
+	ExecutionHook ExecutionKind = "hook"
+
+	// ExecutionAction identifies the execution of a charm action,
+	// including the juju-run action.
+	ExecutionAction ExecutionKind = "action"
+
+	// ExecutionCommands identifies the execution of commands sent
+	// to the unit by juju-run.
+	ExecutionCommands ExecutionKind = "juju-run"
+)
+
+// ExecutionRecord describes a single execution of a hook, action or
+// set of commands by the uniter.
+type ExecutionRecord struct {
+	// Kind identifies what was executed.
+	Kind ExecutionKind
+
+	// Name holds the name of the hook or action executed; it is
+	// empty for commands.
+	Name string
+
+	// Started holds the time at which execution started.
+	Started time.Time
+
+	// Duration holds the time taken to execute.
+	Duration time.Duration
+
+	// ExitCode holds the exit code of the executed process, or -1
+	// if it could not be started or was killed.
+	ExitCode int
+
+	// ToolCalls holds the number of hook tool invocations made while
+	// executing, keyed on tool name.
+	ToolCalls map[string]int
+}
+
+// recordExecution reports the execution, started at the supplied time,
+// of whatever was most recently run by rnr.
+func recordExecution(callbacks Callbacks, kind ExecutionKind, name string, started time.Time, rnr runner.Runner) {
+	stats := rnr.Stats()
+	callbacks.RecordExecution(ExecutionRecord{
+		Kind:      kind,
+		Name:      name,
+		Started:   started,
+		Duration:  time.Since(started),
+		ExitCode:  stats.ExitCode,
+		ToolCalls: stats.ToolCalls,
+	})
+}
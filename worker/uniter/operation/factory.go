@@ -84,6 +84,16 @@ func (f *factory) NewRunHook(hookInfo hook.Info) (Operation, error) {
 	}, nil
 }
 
+// NewRetryHook is part of the Factory interface.
+func (f *factory) NewRetryHook(hookInfo hook.Info, retries int) (Operation, error) {
+	op, err := f.NewRunHook(hookInfo)
+	if err != nil {
+		return nil, err
+	}
+	op.(*runHook).retries = retries
+	return op, nil
+}
+
 // NewSkipHook is part of the Factory interface.
 func (f *factory) NewSkipHook(hookInfo hook.Info) (Operation, error) {
 	hookOp, err := f.NewRunHook(hookInfo)
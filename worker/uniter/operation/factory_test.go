@@ -179,6 +179,12 @@ func (s *FactorySuite) TestNewHookError_Run(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewRunHook)
 }
 
+func (s *FactorySuite) TestNewHookError_Retry(c *gc.C) {
+	s.testNewHookError(c, func(f operation.Factory, info hook.Info) (operation.Operation, error) {
+		return f.NewRetryHook(info, 1)
+	})
+}
+
 func (s *FactorySuite) TestNewHookError_Skip(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewSkipHook)
 }
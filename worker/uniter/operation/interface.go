@@ -83,6 +83,10 @@ type Factory interface {
 	// NewRunHook creates an operation to execute the supplied hook.
 	NewRunHook(hookInfo hook.Info) (Operation, error)
 
+	// NewRetryHook creates an operation to execute the supplied failed
+	// hook again as the given automatic retry attempt.
+	NewRetryHook(hookInfo hook.Info, retries int) (Operation, error)
+
 	// NewSkipHook creates an operation to mark the supplied hook as
 	// completed successfully, without executing the hook.
 	NewSkipHook(hookInfo hook.Info) (Operation, error)
@@ -138,6 +142,11 @@ type Callbacks interface {
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// RecordExecution records the timing and outcome of a hook, action
+	// or commands execution. It's used by RunHook, RunAction and
+	// RunCommands operations.
+	RecordExecution(ExecutionRecord)
+
 	// The following methods exist primarily to allow us to test operation code
 	// without using a live api connection.
 
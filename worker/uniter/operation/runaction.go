@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -72,7 +73,9 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
+	started := time.Now()
 	err := ra.runner.RunAction(ra.name)
+	recordExecution(ra.callbacks, ExecutionAction, ra.name, started, ra.runner)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
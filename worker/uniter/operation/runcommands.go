@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -65,7 +66,9 @@ func (rc *runCommands) Execute(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 
+	started := time.Now()
 	response, err := rc.runner.RunCommands(rc.args.Commands)
+	recordExecution(rc.callbacks, ExecutionCommands, "", started, rc.runner)
 	switch err {
 	case context.ErrRequeueAndReboot:
 		logger.Warningf("cannot requeue external commands")
@@ -11,6 +11,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
@@ -143,6 +144,7 @@ func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
 	runnerFactory := NewRunCommandsRunnerFactory(
 		&utilexec.ExecResponse{Code: 222}, nil,
 	)
+	runnerFactory.MockNewCommandRunner.runner.stats = runner.Stats{ExitCode: 222}
 	callbacks := &RunCommandsCallbacks{}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
@@ -160,6 +162,12 @@ func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
 	c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 	c.Assert(*sendResponse.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 222})
 	c.Assert(*sendResponse.gotErr, jc.ErrorIsNil)
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	record := callbacks.executions[0]
+	c.Assert(record.Kind, gc.Equals, operation.ExecutionCommands)
+	c.Assert(record.Name, gc.Equals, "")
+	c.Assert(record.ExitCode, gc.Equals, 222)
+	c.Assert(record.Started.IsZero(), jc.IsFalse)
 }
 
 func (s *RunCommandsSuite) TestCommit(c *gc.C) {
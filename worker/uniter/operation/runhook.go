@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
@@ -19,6 +20,10 @@ import (
 type runHook struct {
 	info hook.Info
 
+	// retries is the number of times the hook has been retried
+	// automatically, recorded in the state while the hook is pending.
+	retries int
+
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
@@ -63,9 +68,10 @@ func (rh *runHook) Prepare(state State) (*State, error) {
 	rh.runner = rnr
 
 	return stateChange{
-		Kind: RunHook,
-		Step: Pending,
-		Hook: &rh.info,
+		Kind:        RunHook,
+		Step:        Pending,
+		Hook:        &rh.info,
+		HookRetries: rh.retries,
 	}.apply(state), nil
 }
 
@@ -91,8 +97,12 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	ranHook := true
 	step := Done
 
+	started := time.Now()
 	err := rh.runner.RunHook(rh.name)
 	cause := errors.Cause(err)
+	if !context.IsMissingHookError(cause) {
+		recordExecution(rh.callbacks, ExecutionHook, rh.name, started, rh.runner)
+	}
 	switch {
 	case context.IsMissingHookError(cause):
 		ranHook = false
@@ -103,6 +113,18 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case cause == context.ErrReboot:
 		err = ErrNeedsReboot
 	case err == nil:
+	case context.IsHookTimeoutError(cause):
+		logger.Errorf("hook %q failed: %v", rh.name, err)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		// Record the timeout so that it can be reported in the unit's
+		// status; the hook can be retried as usual.
+		return stateChange{
+			Kind:         RunHook,
+			Step:         Pending,
+			Hook:         &rh.info,
+			HookTimedOut: true,
+			HookRetries:  rh.retries,
+		}.apply(state), ErrHookFailed
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
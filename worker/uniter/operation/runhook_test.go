@@ -4,6 +4,8 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -212,6 +215,7 @@ func (s *RunHookSuite) TestExecuteMissingHookError(c *gc.C) {
 		c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
 		c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 		c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+		c.Assert(callbacks.executions, gc.HasLen, 0)
 
 		status, err := runnerFactory.MockNewHookRunner.runner.Context().UnitStatus()
 		c.Assert(err, jc.ErrorIsNil)
@@ -272,6 +276,107 @@ func (s *RunHookSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 }
 
+func (s *RunHookSuite) TestExecuteRecordsExecution(c *gc.C) {
+	runErr := errors.New("exit status 3")
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.UpdateStatus, runErr)
+	runnerFactory.MockNewHookRunner.runner.stats = runner.Stats{
+		ExitCode:  3,
+		ToolCalls: map[string]int{"status-set": 2, "juju-log": 1},
+	}
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := time.Now()
+	_, err = op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(callbacks.executions, gc.HasLen, 1)
+	record := callbacks.executions[0]
+	c.Assert(record.Kind, gc.Equals, operation.ExecutionHook)
+	c.Assert(record.Name, gc.Equals, "some-hook-name")
+	c.Assert(record.Started.Before(before), jc.IsFalse)
+	c.Assert(record.Duration >= 0, jc.IsTrue)
+	c.Assert(record.ExitCode, gc.Equals, 3)
+	c.Assert(record.ToolCalls, jc.DeepEquals, map[string]int{"status-set": 2, "juju-log": 1})
+}
+
+func (s *RunHookSuite) TestExecuteHookTimeoutError(c *gc.C) {
+	runErr := context.NewHookTimeoutError("config-changed", time.Minute)
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.ConfigChanged, runErr)
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:         operation.RunHook,
+		Step:         operation.Pending,
+		Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+		HookTimedOut: true,
+	})
+	c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
+	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+
+	// Retrying the hook clears the timeout.
+	retryState, err := op.Prepare(*newState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retryState.HookTimedOut, jc.IsFalse)
+}
+
+func (s *RunHookSuite) TestExecuteRetryHookError(c *gc.C) {
+	newRetryHook := func(f operation.Factory, info hook.Info) (operation.Operation, error) {
+		return f.NewRetryHook(info, 2)
+	}
+	op, _, _ := s.getExecuteRunnerTest(c, newRetryHook, hooks.ConfigChanged, errors.New("graaargh"))
+	preparedState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(preparedState, gc.DeepEquals, &operation.State{
+		Kind:        operation.RunHook,
+		Step:        operation.Pending,
+		Hook:        &hook.Info{Kind: hooks.ConfigChanged},
+		HookRetries: 2,
+	})
+
+	// The prepared state, recording the retry, is kept when the hook fails.
+	newState, err := op.Execute(*preparedState)
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.IsNil)
+}
+
+func (s *RunHookSuite) TestExecuteRetryHookTimeoutError(c *gc.C) {
+	newRetryHook := func(f operation.Factory, info hook.Info) (operation.Operation, error) {
+		return f.NewRetryHook(info, 2)
+	}
+	runErr := context.NewHookTimeoutError("config-changed", time.Minute)
+	op, _, _ := s.getExecuteRunnerTest(c, newRetryHook, hooks.ConfigChanged, runErr)
+	preparedState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(*preparedState)
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:         operation.RunHook,
+		Step:         operation.Pending,
+		Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+		HookTimedOut: true,
+		HookRetries:  2,
+	})
+}
+
+func (s *RunHookSuite) TestExecuteRetryHookSuccess(c *gc.C) {
+	newRetryHook := func(f operation.Factory, info hook.Info) (operation.Operation, error) {
+		return f.NewRetryHook(info, 2)
+	}
+	op, _, _ := s.getExecuteRunnerTest(c, newRetryHook, hooks.ConfigChanged, nil)
+	preparedState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Once the hook succeeds, the retries are forgotten.
+	newState, err := op.Execute(*preparedState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState.HookRetries, gc.Equals, 0)
+}
+
 func (s *RunHookSuite) testExecuteSuccess(
 	c *gc.C, before, after operation.State, setStatusCalled bool,
 ) {
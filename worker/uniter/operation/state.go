@@ -85,6 +85,14 @@ type State struct {
 	// upgrade is complete (instead of running an upgrade-charm hook).
 	Hook *hook.Info `yaml:"hook,omitempty"`
 
+	// HookTimedOut indicates that the hook in a pending RunHook operation
+	// failed because it ran for longer than the hook timeout.
+	HookTimedOut bool `yaml:"hook-timed-out,omitempty"`
+
+	// HookRetries holds the number of times the hook in a pending
+	// RunHook operation has been retried automatically.
+	HookRetries int `yaml:"hook-retries,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
@@ -165,6 +173,8 @@ type stateChange struct {
 	ActionId        *string
 	CharmURL        *charm.URL
 	HasRunStatusSet bool
+	HookTimedOut    bool
+	HookRetries     int
 }
 
 func (change stateChange) apply(state State) *State {
@@ -173,6 +183,8 @@ func (change stateChange) apply(state State) *State {
 	state.Hook = change.Hook
 	state.ActionId = change.ActionId
 	state.CharmURL = change.CharmURL
+	state.HookTimedOut = change.HookTimedOut
+	state.HookRetries = change.HookRetries
 	state.StatusSet = state.StatusSet || change.HasRunStatusSet
 	return &state
 }
@@ -131,6 +131,14 @@ var stateTests = []struct {
 			Step: operation.Pending,
 			Hook: &hook.Info{Kind: hooks.ConfigChanged},
 		},
+	}, {
+		st: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+			HookRetries:  3,
+		},
 	}, {
 		st: operation.State{
 			Kind: operation.RunHook,
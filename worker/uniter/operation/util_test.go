@@ -124,6 +124,7 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	executions       []operation.ExecutionRecord
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -135,9 +136,14 @@ func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunActionCallbacks) RecordExecution(record operation.ExecutionRecord) {
+	cb.executions = append(cb.executions, record)
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	executingMessage string
+	executions       []operation.ExecutionRecord
 }
 
 func (cb *RunCommandsCallbacks) SetExecutingStatus(message string) error {
@@ -145,6 +151,10 @@ func (cb *RunCommandsCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunCommandsCallbacks) RecordExecution(record operation.ExecutionRecord) {
+	cb.executions = append(cb.executions, record)
+}
+
 type MockPrepareHook struct {
 	gotHook *hook.Info
 	name    string
@@ -160,6 +170,7 @@ type PrepareHookCallbacks struct {
 	operation.Callbacks
 	*MockPrepareHook
 	executingMessage string
+	executions       []operation.ExecutionRecord
 }
 
 func (cb *PrepareHookCallbacks) PrepareHook(hookInfo hook.Info) (string, error) {
@@ -171,6 +182,10 @@ func (cb *PrepareHookCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *PrepareHookCallbacks) RecordExecution(record operation.ExecutionRecord) {
+	cb.executions = append(cb.executions, record)
+}
+
 type MockNotify struct {
 	gotName    *string
 	gotContext *runner.Context
@@ -363,12 +378,17 @@ type MockRunner struct {
 	*MockRunCommands
 	*MockRunHook
 	context runner.Context
+	stats   runner.Stats
 }
 
 func (r *MockRunner) Context() runner.Context {
 	return r.context
 }
 
+func (r *MockRunner) Stats() runner.Stats {
+	return r.stats
+}
+
 func (r *MockRunner) RunAction(actionName string) error {
 	return r.MockRunAction.Call(actionName)
 }
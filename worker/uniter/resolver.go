@@ -27,6 +27,18 @@ type ResolverConfig struct {
 	Relations           resolver.Resolver
 	Storage             resolver.Resolver
 	Commands            resolver.Resolver
+
+	// MaxHookRetries limits the number of times a failed hook is
+	// retried automatically; zero means there is no limit.
+	MaxHookRetries int
+
+	// RetryHookKinds holds the kinds of hook that are retried
+	// automatically; if it is empty, all hooks are retried.
+	RetryHookKinds []hooks.Kind
+
+	// ReportHookRetry, if set, is called before a failed hook is
+	// retried automatically, with the number of the retry attempt.
+	ReportHookRetry func(hook.Info, int) error
 }
 
 type uniterResolver struct {
@@ -72,12 +84,13 @@ func (s *uniterResolver) NextOp(
 		}
 	}
 
-	if s.retryHookTimerStarted && (localState.Kind != operation.RunHook || localState.Step != operation.Pending) {
-		// The hook-retry timer is running, but there is no pending
-		// hook operation. We're not in an error state, so stop the
-		// timer now to reset the backoff state.
-		s.config.StopRetryHookTimer()
-		s.retryHookTimerStarted = false
+	if localState.Kind != operation.RunHook || localState.Step != operation.Pending {
+		if s.retryHookTimerStarted {
+			// The hook-retry timer is running; stop it now to
+			// reset the backoff state.
+			s.config.StopRetryHookTimer()
+			s.retryHookTimerStarted = false
+		}
 	}
 
 	op, err := s.config.Leadership.NextOp(localState, remoteState, opFactory)
@@ -179,9 +192,15 @@ func (s *uniterResolver) nextOpHookError(
 			// timer. If the hook succeeds, we'll enter nextOp
 			// and stop the timer.
 			s.retryHookTimerStarted = false
-			return opFactory.NewRunHook(*localState.Hook)
+			retries := localState.HookRetries + 1
+			if s.config.ReportHookRetry != nil {
+				if err := s.config.ReportHookRetry(*localState.Hook, retries); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			return opFactory.NewRetryHook(*localState.Hook, retries)
 		}
-		if !s.retryHookTimerStarted && s.config.ShouldRetryHooks {
+		if !s.retryHookTimerStarted && s.shouldRetryHook(*localState.Hook, localState.HookRetries) {
 			// We haven't yet started a retry timer, so start one
 			// now. If we retry and fail, retryHookTimerStarted is
 			// cleared so that we'll still start it again.
@@ -210,6 +229,28 @@ func (s *uniterResolver) nextOpHookError(
 	}
 }
 
+// shouldRetryHook reports whether the given failed hook should be
+// retried automatically, according to the configured retry policy and
+// the number of automatic retries already attempted.
+func (s *uniterResolver) shouldRetryHook(info hook.Info, retries int) bool {
+	if !s.config.ShouldRetryHooks {
+		return false
+	}
+	if max := s.config.MaxHookRetries; max > 0 && retries >= max {
+		logger.Debugf("not retrying %q hook: %d retries attempted", info.Kind, retries)
+		return false
+	}
+	if len(s.config.RetryHookKinds) == 0 {
+		return true
+	}
+	for _, kind := range s.config.RetryHookKinds {
+		if kind == info.Kind {
+			return true
+		}
+	}
+	return false
+}
+
 func charmModified(local resolver.LocalState, remote remotestate.Snapshot) bool {
 	if *local.CharmURL != *remote.CharmURL {
 		logger.Debugf("upgrade from %v to %v", local.CharmURL, remote.CharmURL)
@@ -40,6 +40,14 @@ func (s *resolverOpFactory) NewRunHook(info hook.Info) (operation.Operation, err
 	return s.wrapHookOp(op, info), nil
 }
 
+func (s *resolverOpFactory) NewRetryHook(info hook.Info, retries int) (operation.Operation, error) {
+	op, err := s.Factory.NewRetryHook(info, retries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.wrapHookOp(op, info), nil
+}
+
 func (s *resolverOpFactory) NewSkipHook(info hook.Info) (operation.Operation, error) {
 	op, err := s.Factory.NewSkipHook(info)
 	if err != nil {
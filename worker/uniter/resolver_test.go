@@ -190,6 +190,105 @@ func (s *resolverSuite) TestHookErrorStartRetryTimerAgain(c *gc.C) {
 	s.stub.CheckCallNames(c, "StartRetryHookTimer", "StartRetryHookTimer")
 }
 
+func (s *resolverSuite) hookErrorLocalState(kind hooks.Kind) resolver.LocalState {
+	return resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.RunHook,
+			Step:      operation.Pending,
+			Installed: true,
+			Started:   true,
+			Hook: &hook.Info{
+				Kind: kind,
+			},
+		},
+	}
+}
+
+func (s *resolverSuite) TestHookErrorRetryMaxAttempts(c *gc.C) {
+	s.reportHookError = func(hook.Info) error { return nil }
+	s.resolverConfig.MaxHookRetries = 2
+	s.resolverConfig.ReportHookRetry = func(info hook.Info, attempt int) error {
+		s.stub.AddCall("ReportHookRetry", info.Kind, attempt)
+		return nil
+	}
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	localState := s.hookErrorLocalState(hooks.Install)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+		c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+		s.remoteState.RetryHookVersion = attempt
+		op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(op.String(), gc.Equals, "run install hook")
+		localState.RetryHookVersion = attempt
+		localState.HookRetries = attempt
+	}
+
+	// The hook has been retried as many times as allowed, so the
+	// timer is not started again.
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "StartRetryHookTimer",
+	}, {
+		FuncName: "ReportHookRetry",
+		Args:     []interface{}{hooks.Install, 1},
+	}, {
+		FuncName: "StartRetryHookTimer",
+	}, {
+		FuncName: "ReportHookRetry",
+		Args:     []interface{}{hooks.Install, 2},
+	}})
+
+	// Once the hook succeeds, a later failure is retried again.
+	localState.Step = operation.Done
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.ResetCalls()
+	localState = s.hookErrorLocalState(hooks.ConfigChanged)
+	localState.RetryHookVersion = 2
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "StartRetryHookTimer")
+}
+
+func (s *resolverSuite) TestHookErrorRetryMaxAttemptsPersisted(c *gc.C) {
+	// The number of retries is read from the local state, so it is
+	// not reset when the uniter restarts.
+	s.reportHookError = func(hook.Info) error { return nil }
+	s.resolverConfig.MaxHookRetries = 2
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	localState := s.hookErrorLocalState(hooks.Install)
+	localState.HookRetries = 2
+
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckNoCalls(c)
+
+	localState.HookRetries = 1
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "StartRetryHookTimer")
+}
+
+func (s *resolverSuite) TestHookErrorRetryHookKinds(c *gc.C) {
+	s.reportHookError = func(hook.Info) error { return nil }
+	s.resolverConfig.RetryHookKinds = []hooks.Kind{hooks.Install}
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+
+	_, err := s.resolver.NextOp(s.hookErrorLocalState(hooks.ConfigChanged), s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckNoCalls(c)
+
+	_, err = s.resolver.NextOp(s.hookErrorLocalState(hooks.Install), s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "StartRetryHookTimer")
+}
+
 func (s *resolverSuite) TestResolvedRetryHooksStopRetryTimer(c *gc.C) {
 	// Resolving a failed hook should stop the retry timer.
 	s.testResolveHookErrorStopRetryTimer(c, params.ResolvedRetryHooks)
@@ -0,0 +1,73 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/charmstate"
+)
+
+// CharmState is part of the jujuc.Context interface.
+func (ctx *HookContext) CharmState() (map[string]string, error) {
+	if err := ctx.ensureCharmState(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	charmState := make(map[string]string, len(ctx.charmState))
+	for key, value := range ctx.charmState {
+		charmState[key] = value
+	}
+	return charmState, nil
+}
+
+// SetCharmStateValue is part of the jujuc.Context interface.
+func (ctx *HookContext) SetCharmStateValue(key, value string) error {
+	if err := ctx.ensureCharmState(); err != nil {
+		return errors.Trace(err)
+	}
+	if current, ok := ctx.charmState[key]; ok && current == value {
+		return nil
+	}
+	charmState := make(map[string]string, len(ctx.charmState)+1)
+	for k, v := range ctx.charmState {
+		charmState[k] = v
+	}
+	charmState[key] = value
+	if err := charmstate.Validate(charmState); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.charmState = charmState
+	ctx.charmStateChanged = true
+	return nil
+}
+
+// DeleteCharmStateValue is part of the jujuc.Context interface.
+func (ctx *HookContext) DeleteCharmStateValue(key string) error {
+	if err := ctx.ensureCharmState(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := ctx.charmState[key]; !ok {
+		return nil
+	}
+	delete(ctx.charmState, key)
+	ctx.charmStateChanged = true
+	return nil
+}
+
+// ensureCharmState reads the unit's charm state from the controller,
+// if it has not already been read in this context.
+func (ctx *HookContext) ensureCharmState() error {
+	if ctx.charmState != nil {
+		return nil
+	}
+	charmState, err := ctx.unit.CharmState()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if charmState == nil {
+		charmState = make(map[string]string)
+	}
+	ctx.charmState = charmState
+	return nil
+}
@@ -7,6 +7,7 @@ package context
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -162,6 +163,15 @@ type HookContext struct {
 	// meterStatus is the status of the unit's metering.
 	meterStatus *meterStatus
 
+	// hookTimeout is the time after which a running hook is killed.
+	// If it is zero, hooks may run indefinitely.
+	hookTimeout time.Duration
+
+	// secretsDue holds the ids of the secrets owned by the unit's
+	// service that are due to be rotated. It is only set in the
+	// leader's update-status hook.
+	secretsDue []string
+
 	// pendingPorts contains a list of port ranges to be opened or
 	// closed when the current hook is committed.
 	pendingPorts map[PortRange]PortRangeInfo
@@ -202,6 +212,13 @@ type HookContext struct {
 	// hook run, so the actual add will happen in a flush.
 	storageAddConstraints map[string][]params.StorageConstraints
 
+	// charmState holds the unit's charm state, as read from the
+	// controller and then modified by the hook. It is nil until first
+	// used, and written on successful hook run if charmStateChanged
+	// is true.
+	charmState        map[string]string
+	charmStateChanged bool
+
 	// clock is used for any time operations.
 	clock clock.Clock
 
@@ -278,6 +295,12 @@ func (ctx *HookContext) Id() string {
 	return ctx.id
 }
 
+// HookTimeout returns the time after which a hook run in the context
+// should be killed, or zero if it may run indefinitely.
+func (ctx *HookContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *HookContext) UnitName() string {
 	return ctx.unitName
 }
@@ -352,6 +375,13 @@ func (ctx *HookContext) SetUnitStatus(unitStatus jujuc.StatusInfo) error {
 	)
 }
 
+// SetWorkloadVersion records the version of the workload the unit is
+// running.
+func (ctx *HookContext) SetWorkloadVersion(version string) error {
+	logger.Tracef("[WORKLOAD-VERSION] %s", version)
+	return ctx.unit.SetWorkloadVersion(version)
+}
+
 // SetServiceStatus will set the given status to the service to which this
 // unit's belong, only if this unit is the leader.
 func (ctx *HookContext) SetServiceStatus(serviceStatus jujuc.StatusInfo) error {
@@ -460,6 +490,13 @@ func (ctx *HookContext) OpenedPorts() []network.PortRange {
 	return unitRanges
 }
 
+// GoalState returns the units expected in the unit's service and in
+// each of its relations. It is read from the controller on each call,
+// since it changes as units come and go.
+func (ctx *HookContext) GoalState() (params.GoalState, error) {
+	return ctx.unit.GoalState()
+}
+
 func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	if ctx.configSettings == nil {
 		var err error
@@ -591,6 +628,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	if len(context.secretsDue) > 0 {
+		vars = append(vars, "JUJU_SECRETS_ROTATION_DUE="+strings.Join(context.secretsDue, " "))
+	}
 	if context.actionData != nil {
 		vars = append(vars,
 			"JUJU_ACTION_NAME="+context.actionData.Name,
@@ -657,18 +697,16 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		defer ctx.handleReboot(&err)
 	}
 
-	for id, rctx := range ctx.relations {
-		if writeChanges {
-			if e := rctx.WriteSettings(); e != nil {
-				e = errors.Errorf(
-					"could not write settings from %q to relation %d: %v",
-					process, id, e,
-				)
-				logger.Errorf("%v", e)
-				if ctxErr == nil {
-					ctxErr = e
-				}
+	// Relation settings and charm state are written together, so that
+	// a hook's changes to them are either all applied or none are.
+	if writeChanges {
+		if e := ctx.commitHookChanges(); e != nil {
+			e = errors.Annotatef(e, "cannot write changes from %q", process)
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
 			}
+			writeChanges = false
 		}
 	}
 
@@ -724,6 +762,30 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 	return ctxErr
 }
 
+// commitHookChanges writes the unit's changed relation settings and
+// charm state, if any, in a single API call.
+func (ctx *HookContext) commitHookChanges() error {
+	ids := make([]int, 0, len(ctx.relations))
+	for id := range ctx.relations {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var relationSettings []*uniter.Settings
+	for _, id := range ids {
+		if settings := ctx.relations[id].settings; settings != nil {
+			relationSettings = append(relationSettings, settings)
+		}
+	}
+	var charmState map[string]string
+	if ctx.charmStateChanged {
+		charmState = ctx.charmState
+	}
+	if len(relationSettings) == 0 && charmState == nil {
+		return nil
+	}
+	return errors.Trace(ctx.unit.CommitHookChanges(relationSettings, charmState))
+}
+
 // finalizeAction passes back the final status of an Action hook to state.
 // It wraps any errors which occurred in normal behavior of the Action run;
 // only errors passed in unhandledErr will be returned.
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/juju/names"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -422,6 +423,45 @@ func assertStorageAddInContext(c *gc.C,
 	}
 }
 
+func (s *InterfaceSuite) TestSecrets(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	id, err := ctx.AddSecret(jujuc.SecretArgs{
+		Description: "root credentials",
+		Data:        map[string]string{"password": "s3cr3t"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	revised, err := ctx.AddSecret(jujuc.SecretArgs{
+		Id:   id,
+		Data: map[string]string{"password": "n3w"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revised, gc.Equals, id)
+
+	data, err := ctx.Secret(id, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "n3w"})
+	data, err = ctx.Secret(id, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, map[string]string{"password": "s3cr3t"})
+
+	err = ctx.GrantSecret(id, jujuc.SecretGrantee{RelationId: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.GrantSecret(id, jujuc.SecretGrantee{UnitName: "u/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.GrantSecret(id, jujuc.SecretGrantee{RelationId: 123})
+	c.Assert(err, gc.ErrorMatches, "relation 123 not found")
+	err = ctx.RevokeSecret(id, jujuc.SecretGrantee{UnitName: "u/0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := s.State.Secret(id)
+	c.Assert(err, jc.ErrorIsNil)
+	grants, err := secret.Grants()
+	c.Assert(err, jc.ErrorIsNil)
+	relation, err := s.State.Relation(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grants, jc.DeepEquals, []names.Tag{relation.Tag()})
+}
+
 type mockProcess struct {
 	kill func() error
 }
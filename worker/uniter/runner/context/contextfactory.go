@@ -213,6 +213,9 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	if hookInfo.Kind == hooks.UpdateStatus {
+		ctx.checkSecretsDue()
+	}
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -291,6 +294,11 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 	}
 	ctx.proxySettings = environConfig.ProxySettings()
 
+	ctx.hookTimeout, err = f.unit.HookTimeout()
+	if err != nil {
+		return errors.Annotate(err, "could not retrieve hook timeout for unit")
+	}
+
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
 	// unset as we always have; this isn't great but it's about behaviour preservation.
@@ -5,6 +5,7 @@ package context_test
 
 import (
 	"os"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -162,6 +163,41 @@ func (s *ContextFactorySuite) TestRelationHookContext(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) TestHookContextHookTimeout(c *gc.C) {
+	err := s.service.SetHookTimeout(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.HookTimeout(), gc.Equals, time.Minute)
+}
+
+func (s *ContextFactorySuite) TestUpdateStatusHookContextSecretsDue(c *gc.C) {
+	restore := context.PatchNewLeadershipContext(
+		func(context.LeadershipSettingsAccessor, leadership.Tracker) context.LeadershipContext {
+			return leaderContext{}
+		},
+	)
+	defer restore()
+	secret, err := s.State.AddSecret(state.AddSecretParams{
+		Owner:          s.service.ServiceTag(),
+		RotateInterval: time.Nanosecond,
+		Data:           map[string]string{"password": "s3cr3t"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.UpdateStatus})
+	c.Assert(err, jc.ErrorIsNil)
+	vars, err := ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Join(vars, "\n"), jc.Contains, "JUJU_SECRETS_ROTATION_DUE="+secret.Id())
+
+	ctx, err = s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	vars, err = ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Join(vars, "\n"), gc.Not(jc.Contains), "JUJU_SECRETS_ROTATION_DUE")
+}
+
 func (s *ContextFactorySuite) TestNewHookContextWithStorage(c *gc.C) {
 	// We need to set up a unit that has storage metadata defined.
 	ch := s.AddTestingCharm(c, "storage-block")
@@ -447,3 +483,11 @@ func (stub *StubLeadershipContext) IsLeader() (bool, error) {
 	stub.MethodCall(stub, "IsLeader")
 	return false, stub.NextErr()
 }
+
+type leaderContext struct {
+	context.LeadershipContext
+}
+
+func (leaderContext) IsLeader() (bool, error) {
+	return true, nil
+}
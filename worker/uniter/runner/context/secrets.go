@@ -0,0 +1,89 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// AddSecret is part of the jujuc.Context interface.
+func (ctx *HookContext) AddSecret(args jujuc.SecretArgs) (string, error) {
+	if args.Id == "" {
+		return ctx.unit.AddSecret(args.Description, args.RotateInterval, args.Data)
+	}
+	if err := ctx.unit.AddSecretRevision(args.Id, args.Data); err != nil {
+		return "", errors.Trace(err)
+	}
+	return args.Id, nil
+}
+
+// Secret is part of the jujuc.Context interface.
+func (ctx *HookContext) Secret(id string, revision int) (map[string]string, error) {
+	data, _, err := ctx.unit.Secret(id, revision)
+	return data, err
+}
+
+// GrantSecret is part of the jujuc.Context interface.
+func (ctx *HookContext) GrantSecret(id string, grantee jujuc.SecretGrantee) error {
+	subject, err := ctx.secretGranteeTag(grantee)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.unit.GrantSecret(id, subject)
+}
+
+// RevokeSecret is part of the jujuc.Context interface.
+func (ctx *HookContext) RevokeSecret(id string, grantee jujuc.SecretGrantee) error {
+	subject, err := ctx.secretGranteeTag(grantee)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return ctx.unit.RevokeSecret(id, subject)
+}
+
+// checkSecretsDue records the secrets the unit's service is due to
+// rotate, if the unit is the leader, so that they can be passed to the
+// hook. Only the leader is prompted, so that a secret is rotated once.
+// Failure to check is logged rather than failing the hook.
+func (ctx *HookContext) checkSecretsDue() {
+	isLeader, err := ctx.IsLeader()
+	if err != nil {
+		logger.Warningf("cannot check secrets due for rotation: %v", err)
+		return
+	}
+	if !isLeader {
+		return
+	}
+	ids, err := ctx.unit.SecretsDueForRotation()
+	if err != nil {
+		logger.Warningf("cannot check secrets due for rotation: %v", err)
+		return
+	}
+	if len(ids) > 0 {
+		logger.Infof("secrets due for rotation: %s", strings.Join(ids, ", "))
+	}
+	ctx.secretsDue = ids
+}
+
+// secretGranteeTag returns the tag of the unit or relation identified
+// by the grantee. Relations are identified by id, and must be known to
+// the context.
+func (ctx *HookContext) secretGranteeTag(grantee jujuc.SecretGrantee) (names.Tag, error) {
+	if grantee.UnitName != "" {
+		if !names.IsValidUnit(grantee.UnitName) {
+			return nil, errors.NotValidf("unit name %q", grantee.UnitName)
+		}
+		return names.NewUnitTag(grantee.UnitName), nil
+	}
+	relation, found := ctx.relations[grantee.RelationId]
+	if !found {
+		return nil, errors.NotFoundf("relation %d", grantee.RelationId)
+	}
+	return relation.ru.Relation().Tag(), nil
+}
@@ -4,6 +4,8 @@
 package runner
 
 import (
+	"github.com/juju/utils/clock"
+
 	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
@@ -17,3 +19,9 @@ var (
 func RunnerPaths(rnr Runner) context.Paths {
 	return rnr.(*runner).paths
 }
+
+// NewRunnerWithClock returns a Runner that uses the supplied clock for
+// hook and command timeouts.
+func NewRunnerWithClock(ctx Context, paths context.Paths, clock clock.Clock) Runner {
+	return &runner{context: ctx, paths: paths, clock: clock}
+}
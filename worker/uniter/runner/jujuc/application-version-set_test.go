@@ -0,0 +1,66 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ApplicationVersionSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ApplicationVersionSetSuite{})
+
+func (s *ApplicationVersionSetSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("application-version-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *ApplicationVersionSetSuite) TestInitNoArgs(c *gc.C) {
+	_, com := s.createCommand(c)
+	err := testing.InitCommand(com, nil)
+	c.Assert(err, gc.ErrorMatches, "no version specified")
+}
+
+func (s *ApplicationVersionSetSuite) TestInitTooManyArgs(c *gc.C) {
+	_, com := s.createCommand(c)
+	err := testing.InitCommand(com, []string{"9.5", "9.6"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["9.6"\]`)
+}
+
+func (s *ApplicationVersionSetSuite) TestSetVersion(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"9.5.3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(hctx.info.WorkloadVersion, gc.Equals, "9.5.3")
+}
+
+func (s *ApplicationVersionSetSuite) TestClearVersion(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	hctx.info.WorkloadVersion = "9.5.3"
+	code := cmd.Main(com, testing.Context(c), []string{""})
+	c.Check(code, gc.Equals, 0)
+	c.Check(hctx.info.WorkloadVersion, gc.Equals, "")
+}
+
+func (s *ApplicationVersionSetSuite) TestSetVersionError(c *gc.C) {
+	_, com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"9.5.3"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot set workload version: boom\n")
+}
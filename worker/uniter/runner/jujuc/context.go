@@ -52,6 +52,8 @@ type HookContext interface {
 	ContextStorage
 	ContextComponents
 	ContextRelations
+	ContextSecrets
+	ContextCharmState
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -105,6 +107,10 @@ type ContextUnit interface {
 
 	// Config returns the current service configuration of the executing unit.
 	ConfigSettings() (charm.Settings, error)
+
+	// GoalState returns the units expected in the executing unit's
+	// service and in each of its relations, with their status.
+	GoalState() (params.GoalState, error)
 }
 
 // ContextStatus is the part of a hook context related to the unit's status.
@@ -121,6 +127,10 @@ type ContextStatus interface {
 
 	// SetServiceStatus updates the status for the unit's service.
 	SetServiceStatus(StatusInfo) error
+
+	// SetWorkloadVersion records the version of the workload the
+	// executing unit is running.
+	SetWorkloadVersion(string) error
 }
 
 // ContextInstance is the part of a hook context related to the unit's instance.
@@ -160,11 +170,6 @@ type ContextNetworking interface {
 
 	// NetworkConfig returns the network configuration for the unit and the
 	// given bindingName.
-	//
-	// TODO(dimitern): Currently, only the Address is populated, add the
-	// rest later.
-	//
-	// LKK Card: https://canonical.leankit.com/Boards/View/101652562/119258804
 	NetworkConfig(bindingName string) ([]params.NetworkConfig, error)
 }
 
@@ -231,6 +236,61 @@ type ContextRelations interface {
 	RelationIds() ([]int, error)
 }
 
+// ContextSecrets is the part of a hook context related to the secrets
+// owned by the unit's service, and those shared with the unit.
+type ContextSecrets interface {
+	// AddSecret stores a new secret owned by the unit's service, or a
+	// new revision of an existing one, and returns its id.
+	AddSecret(SecretArgs) (string, error)
+
+	// Secret returns the values of the supplied revision of a secret,
+	// or of its latest revision if revision is zero.
+	Secret(id string, revision int) (map[string]string, error)
+
+	// GrantSecret shares a secret owned by the unit's service with the
+	// supplied unit or relation.
+	GrantSecret(id string, grantee SecretGrantee) error
+
+	// RevokeSecret withdraws a grant made by GrantSecret.
+	RevokeSecret(id string, grantee SecretGrantee) error
+}
+
+// ContextCharmState is the part of a hook context related to the
+// key/value data the unit's charm persists between hooks.
+type ContextCharmState interface {
+	// CharmState returns the unit's charm state, including any changes
+	// made in this context.
+	CharmState() (map[string]string, error)
+
+	// SetCharmStateValue sets a key in the unit's charm state. Changes
+	// are written to the controller only when the hook completes
+	// successfully.
+	SetCharmStateValue(key, value string) error
+
+	// DeleteCharmStateValue removes a key from the unit's charm state.
+	DeleteCharmStateValue(key string) error
+}
+
+// SecretArgs holds the parameters for adding a secret, or a new
+// revision of an existing secret.
+type SecretArgs struct {
+	// Id identifies the secret to revise; it is empty when adding a
+	// new secret, and only then are Description and RotateInterval
+	// used.
+	Id string
+
+	Description    string
+	RotateInterval time.Duration
+	Data           map[string]string
+}
+
+// SecretGrantee identifies the unit or relation with which a secret is
+// shared: UnitName if it is set, and otherwise RelationId.
+type SecretGrantee struct {
+	UnitName   string
+	RelationId int
+}
+
 // ContextComponent is a single modular Juju component as it relates to
 // the current unit and hook. Components should implement this interfaces
 // in a type-safe way. Ensuring checked type-conversions are preformed on
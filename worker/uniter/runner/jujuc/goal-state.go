@@ -0,0 +1,84 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// goalStateCommand implements the goal-state command.
+type goalStateCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+}
+
+// NewGoalStateCommand returns a new goalStateCommand with the given context.
+func NewGoalStateCommand(ctx Context) (cmd.Command, error) {
+	return &goalStateCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *goalStateCommand) Info() *cmd.Info {
+	doc := `
+goal-state prints the units expected in the local unit's service (units), and
+the units expected at the other end of each of its relations, by endpoint name
+(relations). Each unit has a status of:
+
+  active   the unit is running, or has joined the relation
+  joining  the unit has not yet started, or not yet joined the relation
+  dying    the unit is being removed
+
+A charm can use goal-state to wait until all the expected units have joined
+before, for example, bootstrapping a cluster.
+`
+	return &cmd.Info{
+		Name:    "goal-state",
+		Purpose: "print the units expected in the service and its relations",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *goalStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+// Init is part of the cmd.Command interface.
+func (c *goalStateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// goalStateStatus holds the status of a unit, as output by goal-state.
+type goalStateStatus struct {
+	Status string `json:"status" yaml:"status"`
+}
+
+// Run is part of the cmd.Command interface.
+func (c *goalStateCommand) Run(ctx *cmd.Context) error {
+	goalState, err := c.ctx.GoalState()
+	if err != nil {
+		return errors.Annotate(err, "cannot get goal state")
+	}
+	relations := make(map[string]map[string]goalStateStatus)
+	for endpoint, units := range goalState.Relations {
+		relations[endpoint] = formatGoalStateUnits(units)
+	}
+	return c.out.Write(ctx, map[string]interface{}{
+		"units":     formatGoalStateUnits(goalState.Units),
+		"relations": relations,
+	})
+}
+
+func formatGoalStateUnits(units map[string]params.GoalStateStatus) map[string]goalStateStatus {
+	result := make(map[string]goalStateStatus)
+	for name, status := range units {
+		result[name] = goalStateStatus{Status: status.Status}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type GoalStateSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&GoalStateSuite{})
+
+func (s *GoalStateSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.GoalState = params.GoalState{
+		Units: map[string]params.GoalStateStatus{
+			"u/0": {Status: "active"},
+			"u/1": {Status: "joining"},
+		},
+		Relations: map[string]map[string]params.GoalStateStatus{
+			"db": {
+				"mysql/0": {Status: "active"},
+				"mysql/1": {Status: "dying"},
+			},
+		},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *GoalStateSuite) TestOutputFormats(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{{
+		out: `
+relations:
+  db:
+    mysql/0:
+      status: active
+    mysql/1:
+      status: dying
+units:
+  u/0:
+    status: active
+  u/1:
+    status: joining
+`[1:],
+	}, {
+		args: []string{"--format", "json"},
+		out: `{"relations":{"db":{"mysql/0":{"status":"active"},"mysql/1":{"status":"dying"}}},` +
+			`"units":{"u/0":{"status":"active"},"u/1":{"status":"joining"}}}` + "\n",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *GoalStateSuite) TestError(c *gc.C) {
+	com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot get goal state: boom\n")
+}
+
+func (s *GoalStateSuite) TestUnrecognizedArgs(c *gc.C) {
+	com := s.createCommand(c)
+	err := testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
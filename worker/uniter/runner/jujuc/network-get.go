@@ -9,6 +9,8 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 // NetworkGetCommand implements the network-get command.
@@ -29,11 +31,15 @@ func NewNetworkGetCommand(ctx Context) (cmd.Command, error) {
 
 // Info is part of the cmd.Command interface.
 func (c *NetworkGetCommand) Info() *cmd.Info {
-	args := "<binding-name> --primary-address"
+	args := "<binding-name> [--primary-address]"
 	doc := `
-network-get returns the network config for a given binding name. The only
-supported flag for now is --primary-address, which is required and returns
-the IP address the local unit should advertise as its endpoint to its peers.
+network-get returns the network config for a given binding name. By default
+it returns a list of the interfaces on the unit's machine bound to the
+endpoint, with their MAC addresses and the addresses and CIDRs assigned to
+them.
+
+With --primary-address, only the IP address the local unit should advertise
+as its endpoint to its peers is returned.
 `
 	return &cmd.Info{
 		Name:    "network-get",
@@ -60,13 +66,22 @@ func (c *NetworkGetCommand) Init(args []string) error {
 		return fmt.Errorf("no binding name specified")
 	}
 
-	if !c.primaryAddress {
-		return fmt.Errorf("--primary-address is currently required")
-	}
-
 	return cmd.CheckEmpty(args[1:])
 }
 
+// interfaceInfo holds the addresses bound to a network interface.
+type interfaceInfo struct {
+	MACAddress    string        `json:"mac-address,omitempty" yaml:"mac-address,omitempty"`
+	InterfaceName string        `json:"interface-name,omitempty" yaml:"interface-name,omitempty"`
+	Addresses     []addressInfo `json:"addresses" yaml:"addresses"`
+}
+
+// addressInfo holds an address and the CIDR of its subnet.
+type addressInfo struct {
+	Address string `json:"address" yaml:"address"`
+	CIDR    string `json:"cidr,omitempty" yaml:"cidr,omitempty"`
+}
+
 func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	netConfig, err := c.ctx.NetworkConfig(c.bindingName)
 	if err != nil {
@@ -79,6 +94,31 @@ func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
 	if c.primaryAddress {
 		return c.out.Write(ctx, netConfig[0].Address)
 	}
+	return c.out.Write(ctx, makeNetworkInfo(netConfig))
+}
 
-	return nil // never reached as --primary-address is required.
+// makeNetworkInfo groups the addresses in the given network config by
+// interface.
+func makeNetworkInfo(netConfig []params.NetworkConfig) map[string]interface{} {
+	var bindAddresses []interfaceInfo
+	interfaces := make(map[string]int)
+	for _, config := range netConfig {
+		key := config.InterfaceName + "/" + config.MACAddress
+		i, ok := interfaces[key]
+		if !ok {
+			i = len(bindAddresses)
+			interfaces[key] = i
+			bindAddresses = append(bindAddresses, interfaceInfo{
+				MACAddress:    config.MACAddress,
+				InterfaceName: config.InterfaceName,
+			})
+		}
+		bindAddresses[i].Addresses = append(bindAddresses[i].Addresses, addressInfo{
+			Address: config.Address,
+			CIDR:    config.CIDR,
+		})
+	}
+	return map[string]interface{}{
+		"bind-addresses": bindAddresses,
+	}
 }
@@ -25,10 +25,22 @@ func (s *NetworkGetSuite) createCommand(c *gc.C) cmd.Command {
 	hctx := s.GetHookContext(c, -1, "")
 
 	presetBindings := make(map[string][]params.NetworkConfig)
-	presetBindings["known-relation"] = []params.NetworkConfig{
-		{Address: "10.10.0.23"},
-		{Address: "192.168.1.111"},
-	}
+	presetBindings["known-relation"] = []params.NetworkConfig{{
+		MACAddress:    "00:11:22:33:44:00",
+		InterfaceName: "eth0",
+		Address:       "10.10.0.23",
+		CIDR:          "10.10.0.0/24",
+	}, {
+		MACAddress:    "00:11:22:33:44:00",
+		InterfaceName: "eth0",
+		Address:       "10.10.0.24",
+		CIDR:          "10.10.0.0/24",
+	}, {
+		MACAddress:    "00:11:22:33:44:11",
+		InterfaceName: "eth1",
+		Address:       "192.168.1.111",
+		CIDR:          "192.168.1.0/24",
+	}}
 	presetBindings["known-extra"] = []params.NetworkConfig{
 		{Address: "10.20.1.42"},
 		{Address: "fc00::1/64"},
@@ -62,10 +74,10 @@ func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
 		args:    []string{""},
 		out:     `no binding name specified`,
 	}, {
-		summary: "binding name given, no --primary-address given",
+		summary: "too many arguments",
 		code:    2,
-		args:    []string{"foo"},
-		out:     `--primary-address is currently required`,
+		args:    []string{"foo", "bar"},
+		out:     `unrecognized args: \["bar"\]`,
 	}, {
 		summary: "unknown binding given, with --primary-address",
 		args:    []string{"unknown", "--primary-address"},
@@ -88,6 +100,32 @@ func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
 		summary: "implicitly bound binding name given with --primary-address",
 		args:    []string{"known-unbound", "--primary-address"},
 		out:     "10.33.1.8", // preferred private address used for unspecified bindings.
+	}, {
+		summary: "unknown binding given, no flags",
+		args:    []string{"unknown"},
+		code:    1,
+		out:     "insert server error for unknown binding here",
+	}, {
+		summary: "explicitly bound relation name given, no flags",
+		args:    []string{"known-relation"},
+		out: `
+bind-addresses:
+- mac-address: "00:11:22:33:44:00"
+  interface-name: eth0
+  addresses:
+  - address: 10.10.0.23
+    cidr: 10.10.0.0/24
+  - address: 10.10.0.24
+    cidr: 10.10.0.0/24
+- mac-address: "00:11:22:33:44:11"
+  interface-name: eth1
+  addresses:
+  - address: 192.168.1.111
+    cidr: 192.168.1.0/24`[1:],
+	}, {
+		summary: "explicitly bound extra-binding name given, JSON format",
+		args:    []string{"known-extra", "--format", "json"},
+		out:     `{"bind-addresses":[{"addresses":[{"address":"10.20.1.42"},{"address":"fc00::1/64"}]}]}`,
 	}} {
 		c.Logf("test %d: %s", i, t.summary)
 		com := s.createCommand(c)
@@ -112,7 +150,7 @@ func (s *NetworkGetSuite) TestNetworkGet(c *gc.C) {
 func (s *NetworkGetSuite) TestHelp(c *gc.C) {
 
 	var helpTemplate = `
-Usage: network-get [options] <binding-name> --primary-address
+Usage: network-get [options] <binding-name> [--primary-address]
 
 Summary:
 get network config
@@ -126,9 +164,13 @@ Options:
     get the primary address for the binding
 
 Details:
-network-get returns the network config for a given binding name. The only
-supported flag for now is --primary-address, which is required and returns
-the IP address the local unit should advertise as its endpoint to its peers.
+network-get returns the network config for a given binding name. By default
+it returns a list of the interfaces on the unit's machine bound to the
+endpoint, with their MAC addresses and the addresses and CIDRs assigned to
+them.
+
+With --primary-address, only the IP address the local unit should advertise
+as its endpoint to its peers is returned.
 `[1:]
 
 	com := s.createCommand(c)
@@ -25,6 +25,11 @@ type RestrictedContext struct{}
 // ConfigSettings implements jujuc.Context.
 func (*RestrictedContext) ConfigSettings() (charm.Settings, error) { return nil, ErrRestrictedContext }
 
+// GoalState implements jujuc.Context.
+func (*RestrictedContext) GoalState() (params.GoalState, error) {
+	return params.GoalState{}, ErrRestrictedContext
+}
+
 // UnitStatus implements jujuc.Context.
 func (*RestrictedContext) UnitStatus() (*StatusInfo, error) { return nil, ErrRestrictedContext }
 
@@ -39,6 +44,9 @@ func (*RestrictedContext) ServiceStatus() (ServiceStatusInfo, error) {
 // SetServiceStatus implements jujuc.Context.
 func (*RestrictedContext) SetServiceStatus(StatusInfo) error { return ErrRestrictedContext }
 
+// SetWorkloadVersion implements jujuc.Context.
+func (*RestrictedContext) SetWorkloadVersion(string) error { return ErrRestrictedContext }
+
 // AvailabilityZone implements jujuc.Context.
 func (*RestrictedContext) AvailabilityZone() (string, error) { return "", ErrRestrictedContext }
 
@@ -137,3 +145,26 @@ func (*RestrictedContext) SetActionFailed() error { return ErrRestrictedContext
 func (*RestrictedContext) Component(string) (ContextComponent, error) {
 	return nil, ErrRestrictedContext
 }
+
+// AddSecret implements jujuc.Context.
+func (*RestrictedContext) AddSecret(SecretArgs) (string, error) { return "", ErrRestrictedContext }
+
+// Secret implements jujuc.Context.
+func (*RestrictedContext) Secret(string, int) (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
+// GrantSecret implements jujuc.Context.
+func (*RestrictedContext) GrantSecret(string, SecretGrantee) error { return ErrRestrictedContext }
+
+// RevokeSecret implements jujuc.Context.
+func (*RestrictedContext) RevokeSecret(string, SecretGrantee) error { return ErrRestrictedContext }
+
+// CharmState implements jujuc.Context.
+func (*RestrictedContext) CharmState() (map[string]string, error) { return nil, ErrRestrictedContext }
+
+// SetCharmStateValue implements jujuc.Context.
+func (*RestrictedContext) SetCharmStateValue(string, string) error { return ErrRestrictedContext }
+
+// DeleteCharmStateValue implements jujuc.Context.
+func (*RestrictedContext) DeleteCharmStateValue(string) error { return ErrRestrictedContext }
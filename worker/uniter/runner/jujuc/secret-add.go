@@ -0,0 +1,89 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
+)
+
+// secretAddCommand implements the secret-add command.
+type secretAddCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	id             string
+	description    string
+	rotateInterval time.Duration
+	data           map[string]string
+}
+
+// NewSecretAddCommand returns a new secretAddCommand with the given context.
+func NewSecretAddCommand(ctx Context) (cmd.Command, error) {
+	return &secretAddCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretAddCommand) Info() *cmd.Info {
+	doc := `
+secret-add stores the supplied key/value pairs as a new secret owned by the
+unit's service, and prints the secret's id. The values are encrypted by the
+controller, and may only be read by units of the service, and by the units
+and relations the secret has been shared with using secret-grant.
+
+If --id is given, the values are stored as a new revision of that secret
+instead; this is how a secret is rotated. Readers see the latest revision
+unless they ask for an earlier one. The --rotate interval records how often
+the secret should be rotated; once it has passed since the latest revision,
+the secret's id is passed to the service leader's update-status hook in
+$JUJU_SECRETS_ROTATION_DUE until a new revision is added.
+`
+	return &cmd.Info{
+		Name:    "secret-add",
+		Args:    "<key>=<value> [...]",
+		Purpose: "add a secret, or a new revision of a secret",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretAddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.id, "id", "", "the id of a secret to add a revision to")
+	f.StringVar(&c.description, "description", "", "a description of the secret")
+	f.DurationVar(&c.rotateInterval, "rotate", 0, "how often the secret should be rotated")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretAddCommand) Init(args []string) (err error) {
+	if c.id != "" && (c.description != "" || c.rotateInterval != 0) {
+		return errors.New("--description and --rotate cannot be used with --id")
+	}
+	if c.rotateInterval < 0 {
+		return errors.Errorf("invalid rotate interval %v", c.rotateInterval)
+	}
+	if len(args) == 0 {
+		return errors.New("no values specified")
+	}
+	c.data, err = keyvalues.Parse(args, false)
+	return err
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretAddCommand) Run(ctx *cmd.Context) error {
+	id, err := c.ctx.AddSecret(SecretArgs{
+		Id:             c.id,
+		Description:    c.description,
+		RotateInterval: c.rotateInterval,
+		Data:           c.data,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot add secret")
+	}
+	fmt.Fprintln(ctx.Stdout, id)
+	return nil
+}
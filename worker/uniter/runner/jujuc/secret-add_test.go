@@ -0,0 +1,104 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretAddSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretAddSuite{})
+
+func (s *SecretAddSuite) createCommand(c *gc.C) (*Context, cmd.Command) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-add"))
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx, com
+}
+
+func (s *SecretAddSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no values specified",
+	}, {
+		args: []string{"password"},
+		err:  `expected "key=value", got "password"`,
+	}, {
+		args: []string{"--id", "0", "--description", "x", "password=s3cr3t"},
+		err:  "--description and --rotate cannot be used with --id",
+	}, {
+		args: []string{"--id", "0", "--rotate", "1h", "password=s3cr3t"},
+		err:  "--description and --rotate cannot be used with --id",
+	}, {
+		args: []string{"--rotate", "-1h", "password=s3cr3t"},
+		err:  "invalid rotate interval -1h0m0s",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		_, com := s.createCommand(c)
+		err := testing.InitCommand(com, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SecretAddSuite) TestAddSecret(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{
+		"--description", "root credentials", "--rotate", "720h",
+		"user=root", "password=s3cr3t",
+	})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "0\n")
+	c.Check(hctx.info.Secrets.Secrets, jc.DeepEquals, map[string]*jujuctesting.Secret{
+		"0": {
+			Description:    "root credentials",
+			RotateInterval: 720 * time.Hour,
+			Revisions: []map[string]string{
+				{"user": "root", "password": "s3cr3t"},
+			},
+		},
+	})
+}
+
+func (s *SecretAddSuite) TestAddRevision(c *gc.C) {
+	hctx, com := s.createCommand(c)
+	hctx.info.SetSecret("0", &jujuctesting.Secret{
+		Revisions: []map[string]string{{"password": "s3cr3t"}},
+	})
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--id", "0", "password=n3w"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "0\n")
+	c.Check(hctx.info.Secrets.Secrets["0"].Revisions, jc.DeepEquals, []map[string]string{
+		{"password": "s3cr3t"},
+		{"password": "n3w"},
+	})
+}
+
+func (s *SecretAddSuite) TestAddSecretError(c *gc.C) {
+	_, com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"password=s3cr3t"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot add secret: boom\n")
+}
@@ -0,0 +1,94 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretGetSuite{})
+
+func (s *SecretGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.SetSecret("3", &jujuctesting.Secret{
+		Revisions: []map[string]string{
+			{"user": "root", "password": "s3cr3t"},
+			{"user": "root", "password": "n3w"},
+		},
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *SecretGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no secret id specified",
+	}, {
+		args: []string{"3", "x=y"},
+		err:  `invalid key "x=y"`,
+	}, {
+		args: []string{"3", "user", "password"},
+		err:  `unrecognized args: \["password"\]`,
+	}, {
+		args: []string{"--revision", "-1", "3"},
+		err:  "invalid revision -1",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(s.createCommand(c), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SecretGetSuite) testOutput(c *gc.C, args []string, expect string) {
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, args)
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, expect)
+}
+
+func (s *SecretGetSuite) TestGetKey(c *gc.C) {
+	s.testOutput(c, []string{"3", "password"}, "n3w\n")
+}
+
+func (s *SecretGetSuite) TestGetMissingKey(c *gc.C) {
+	s.testOutput(c, []string{"3", "unknown"}, "")
+}
+
+func (s *SecretGetSuite) TestGetRevision(c *gc.C) {
+	s.testOutput(c, []string{"--revision", "1", "3", "password"}, "s3cr3t\n")
+}
+
+func (s *SecretGetSuite) TestGetAll(c *gc.C) {
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"--format", "json", "3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), jc.JSONEquals, map[string]string{"user": "root", "password": "n3w"})
+	s.Stub.CheckCall(c, 0, "Secret", "3", 0)
+}
+
+func (s *SecretGetSuite) TestGetError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("permission denied"))
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"3"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, `error: cannot read secret "3": permission denied`+"\n")
+}
@@ -0,0 +1,117 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+)
+
+// secretGrantCommand implements the secret-grant and secret-revoke
+// commands, which differ only in what they do with the grantee.
+type secretGrantCommand struct {
+	cmd.CommandBase
+	ctx             Context
+	revoke          bool
+	id              string
+	unitName        string
+	relationId      int
+	relationIdProxy gnuflag.Value
+}
+
+// NewSecretGrantCommand returns a new secret-grant command with the
+// given context.
+func NewSecretGrantCommand(ctx Context) (cmd.Command, error) {
+	return newSecretGrantCommand(ctx, false)
+}
+
+// NewSecretRevokeCommand returns a new secret-revoke command with the
+// given context.
+func NewSecretRevokeCommand(ctx Context) (cmd.Command, error) {
+	return newSecretGrantCommand(ctx, true)
+}
+
+func newSecretGrantCommand(ctx Context, revoke bool) (cmd.Command, error) {
+	c := &secretGrantCommand{ctx: ctx, revoke: revoke}
+	rV, err := newRelationIdValue(ctx, &c.relationId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.relationIdProxy = rV
+	return c, nil
+}
+
+const secretGrantDoc = `
+secret-grant shares a secret owned by the unit's service with a unit, or
+with the units of the services in a relation, allowing them to read it with
+secret-get. If --unit is given, the secret is shared with that unit;
+otherwise it is shared with the specified relation, which defaults to the
+relation of the current hook. Access granted through a relation ends when
+the relation is removed.
+`
+
+const secretRevokeDoc = `
+secret-revoke withdraws a grant made by secret-grant. If --unit is given,
+the grant to that unit is withdrawn; otherwise the grant to the specified
+relation, which defaults to the relation of the current hook. Units that
+have already read the secret may still know its values, so a secret that
+is revoked should usually also be rotated.
+`
+
+// Info is part of the cmd.Command interface.
+func (c *secretGrantCommand) Info() *cmd.Info {
+	if c.revoke {
+		return &cmd.Info{
+			Name:    "secret-revoke",
+			Args:    "<id>",
+			Purpose: "revoke access to a secret",
+			Doc:     secretRevokeDoc,
+		}
+	}
+	return &cmd.Info{
+		Name:    "secret-grant",
+		Args:    "<id>",
+		Purpose: "grant access to a secret",
+		Doc:     secretGrantDoc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *secretGrantCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(c.relationIdProxy, "r", "specify a relation by id")
+	f.Var(c.relationIdProxy, "relation", "")
+	f.StringVar(&c.unitName, "unit", "", "specify a unit by name")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretGrantCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no secret id specified")
+	}
+	c.id = args[0]
+	if c.unitName != "" {
+		if !names.IsValidUnit(c.unitName) {
+			return errors.Errorf("invalid unit name %q", c.unitName)
+		}
+	} else if c.relationId == -1 {
+		return errors.New("no unit or relation specified")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretGrantCommand) Run(_ *cmd.Context) error {
+	grantee := SecretGrantee{
+		UnitName:   c.unitName,
+		RelationId: c.relationId,
+	}
+	if c.revoke {
+		err := c.ctx.RevokeSecret(c.id, grantee)
+		return errors.Annotatef(err, "cannot revoke secret %q", c.id)
+	}
+	err := c.ctx.GrantSecret(c.id, grantee)
+	return errors.Annotatef(err, "cannot grant secret %q", c.id)
+}
@@ -0,0 +1,117 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
+)
+
+type SecretGrantSuite struct {
+	relationSuite
+}
+
+var _ = gc.Suite(&SecretGrantSuite{})
+
+func (s *SecretGrantSuite) createCommand(c *gc.C, name string, relid int) (cmd.Command, *relationInfo) {
+	hctx, info := s.newHookContext(relid, "")
+	info.SetSecret("3", &jujuctesting.Secret{
+		Revisions: []map[string]string{{"password": "s3cr3t"}},
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString(name))
+	c.Assert(err, jc.ErrorIsNil)
+	return com, info
+}
+
+func (s *SecretGrantSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		relid int
+		args  []string
+		err   string
+	}{{
+		relid: 1,
+		args:  nil,
+		err:   "no secret id specified",
+	}, {
+		relid: -1,
+		args:  []string{"3"},
+		err:   "no unit or relation specified",
+	}, {
+		relid: -1,
+		args:  []string{"--unit", "wordpress", "3"},
+		err:   `invalid unit name "wordpress"`,
+	}, {
+		relid: -1,
+		args:  []string{"-r", "peer1:7", "3"},
+		err:   `invalid value "peer1:7" for flag -r: relation not found`,
+	}, {
+		relid: 1,
+		args:  []string{"3", "4"},
+		err:   `unrecognized args: \["4"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		for _, name := range []string{"secret-grant", "secret-revoke"} {
+			com, _ := s.createCommand(c, name, t.relid)
+			err := testing.InitCommand(com, t.args)
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *SecretGrantSuite) TestGrantHookRelation(c *gc.C) {
+	com, info := s.createCommand(c, "secret-grant", 1)
+	code := cmd.Main(com, testing.Context(c), []string{"3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(info.Secrets.Secrets["3"].Grants, jc.DeepEquals, []jujuc.SecretGrantee{{RelationId: 1}})
+}
+
+func (s *SecretGrantSuite) TestGrantRelation(c *gc.C) {
+	com, info := s.createCommand(c, "secret-grant", -1)
+	code := cmd.Main(com, testing.Context(c), []string{"-r", "peer0:0", "3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(info.Secrets.Secrets["3"].Grants, jc.DeepEquals, []jujuc.SecretGrantee{{RelationId: 0}})
+}
+
+func (s *SecretGrantSuite) TestGrantUnit(c *gc.C) {
+	com, info := s.createCommand(c, "secret-grant", 1)
+	code := cmd.Main(com, testing.Context(c), []string{"--unit", "wordpress/0", "3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(info.Secrets.Secrets["3"].Grants, jc.DeepEquals, []jujuc.SecretGrantee{{
+		UnitName:   "wordpress/0",
+		RelationId: 1,
+	}})
+}
+
+func (s *SecretGrantSuite) TestRevoke(c *gc.C) {
+	com, info := s.createCommand(c, "secret-revoke", -1)
+	info.Secrets.Secrets["3"].Grants = []jujuc.SecretGrantee{
+		{RelationId: 0}, {UnitName: "wordpress/0", RelationId: -1},
+	}
+	code := cmd.Main(com, testing.Context(c), []string{"--unit", "wordpress/0", "3"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(info.Secrets.Secrets["3"].Grants, jc.DeepEquals, []jujuc.SecretGrantee{{RelationId: 0}})
+}
+
+func (s *SecretGrantSuite) TestErrors(c *gc.C) {
+	for _, t := range []struct {
+		name   string
+		expect string
+	}{
+		{"secret-grant", `error: cannot grant secret "3": boom` + "\n"},
+		{"secret-revoke", `error: cannot revoke secret "3": boom` + "\n"},
+	} {
+		com, _ := s.createCommand(c, t.name, 1)
+		s.Stub.SetErrors(errors.New("boom"))
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, []string{"3"})
+		c.Check(code, gc.Equals, 1)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.expect)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StateDeleteSuite struct {
+	ContextSuite
+	hctx *Context
+}
+
+var _ = gc.Suite(&StateDeleteSuite{})
+
+func (s *StateDeleteSuite) createCommand(c *gc.C) cmd.Command {
+	s.hctx = s.GetHookContext(c, -1, "")
+	s.hctx.info.CharmState.Values = map[string]string{
+		"initialised": "true",
+		"db-version":  "9.5",
+	}
+	com, err := jujuc.NewCommand(s.hctx, cmdString("state-delete"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *StateDeleteSuite) TestInitNoKeys(c *gc.C) {
+	err := testing.InitCommand(s.createCommand(c), nil)
+	c.Check(err, gc.ErrorMatches, "no keys specified")
+}
+
+func (s *StateDeleteSuite) TestDelete(c *gc.C) {
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"db-version", "unknown"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(s.hctx.info.CharmState.Values, jc.DeepEquals, map[string]string{
+		"initialised": "true",
+	})
+}
+
+func (s *StateDeleteSuite) TestDeleteError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"initialised"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot delete charm state: boom\n")
+}
@@ -0,0 +1,85 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StateGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StateGetSuite{})
+
+func (s *StateGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.CharmState.Values = map[string]string{
+		"initialised": "true",
+		"db-version":  "9.5",
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("state-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *StateGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"x=y"},
+		err:  `invalid key "x=y"`,
+	}, {
+		args: []string{"initialised", "db-version"},
+		err:  `unrecognized args: \["db-version"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(s.createCommand(c), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *StateGetSuite) testOutput(c *gc.C, args []string, expect string) {
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, args)
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, expect)
+}
+
+func (s *StateGetSuite) TestGetKey(c *gc.C) {
+	s.testOutput(c, []string{"db-version"}, "9.5\n")
+}
+
+func (s *StateGetSuite) TestGetMissingKey(c *gc.C) {
+	s.testOutput(c, []string{"unknown"}, "")
+}
+
+func (s *StateGetSuite) TestGetAll(c *gc.C) {
+	for _, args := range [][]string{nil, {"-"}} {
+		ctx := testing.Context(c)
+		code := cmd.Main(s.createCommand(c), ctx, append(args, "--format", "json"))
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stdout), jc.JSONEquals, map[string]string{
+			"initialised": "true",
+			"db-version":  "9.5",
+		})
+	}
+}
+
+func (s *StateGetSuite) TestGetError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("connection refused"))
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot read charm state: connection refused\n")
+}
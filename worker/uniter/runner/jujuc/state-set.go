@@ -0,0 +1,71 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+
+	"github.com/juju/juju/core/charmstate"
+)
+
+// stateSetCommand implements the state-set command.
+type stateSetCommand struct {
+	cmd.CommandBase
+	ctx    Context
+	values map[string]string
+}
+
+// NewStateSetCommand returns a new stateSetCommand with the given context.
+func NewStateSetCommand(ctx Context) (cmd.Command, error) {
+	return &stateSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *stateSetCommand) Info() *cmd.Info {
+	doc := `
+state-set stores the supplied key/value pairs in the unit's charm state, which
+is kept by the controller and can be read by later hooks with state-get. Like
+relation-set, changes are only written when the hook completes successfully.
+
+The total size of the keys and values stored for a unit is limited to 64KiB,
+and each key to 256 bytes; state-set fails if a change would exceed them.
+`
+	return &cmd.Info{
+		Name:    "state-set",
+		Args:    "<key>=<value> [...]",
+		Purpose: "set unit charm state",
+		Doc:     doc,
+	}
+}
+
+// Init is part of the cmd.Command interface.
+func (c *stateSetCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New("no values specified")
+	}
+	c.values, err = keyvalues.Parse(args, true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(charmstate.Validate(c.values))
+}
+
+// Run is part of the cmd.Command interface.
+func (c *stateSetCommand) Run(_ *cmd.Context) error {
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := c.ctx.SetCharmStateValue(key, c.values[key]); err != nil {
+			return errors.Annotatef(err, "cannot set charm state")
+		}
+	}
+	return nil
+}
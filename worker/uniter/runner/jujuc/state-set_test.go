@@ -0,0 +1,75 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StateSetSuite struct {
+	ContextSuite
+	hctx *Context
+}
+
+var _ = gc.Suite(&StateSetSuite{})
+
+func (s *StateSetSuite) createCommand(c *gc.C) cmd.Command {
+	s.hctx = s.GetHookContext(c, -1, "")
+	s.hctx.info.CharmState.Values = map[string]string{"initialised": "false"}
+	com, err := jujuc.NewCommand(s.hctx, cmdString("state-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *StateSetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no values specified",
+	}, {
+		args: []string{"initialised"},
+		err:  `expected "key=value", got "initialised"`,
+	}, {
+		args: []string{strings.Repeat("k", 257) + "=value"},
+		err:  "key of 257 bytes exceeds limit of 256 bytes",
+	}, {
+		args: []string{"big=" + strings.Repeat("v", 64*1024)},
+		err:  "charm state of 65539 bytes exceeds limit of 65536 bytes",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(s.createCommand(c), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *StateSetSuite) TestSet(c *gc.C) {
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"initialised=true", "db-version=9.5"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	s.Stub.CheckCall(c, 0, "SetCharmStateValue", "db-version", "9.5")
+	s.Stub.CheckCall(c, 1, "SetCharmStateValue", "initialised", "true")
+	c.Check(s.hctx.info.CharmState.Values, jc.DeepEquals, map[string]string{
+		"initialised": "true",
+		"db-version":  "9.5",
+	})
+}
+
+func (s *StateSetSuite) TestSetError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := testing.Context(c)
+	code := cmd.Main(s.createCommand(c), ctx, []string{"initialised=true"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot set charm state: boom\n")
+}
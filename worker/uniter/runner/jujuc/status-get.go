@@ -14,10 +14,11 @@ import (
 // StatusGetCommand implements the status-get command.
 type StatusGetCommand struct {
 	cmd.CommandBase
-	ctx         Context
-	includeData bool
-	serviceWide bool
-	out         cmd.Output
+	ctx          Context
+	includeData  bool
+	serviceWide  bool
+	includeUnits bool
+	out          cmd.Output
 }
 
 func NewStatusGetCommand(ctx Context) (cmd.Command, error) {
@@ -28,10 +29,13 @@ func (c *StatusGetCommand) Info() *cmd.Info {
 	doc := `
 By default, only the status value is printed.
 If the --include-data flag is passed, the associated data are printed also.
+If the --include-units flag is passed with --service, the statuses of all
+the service's units are printed along with the service status; otherwise
+only the service status is printed.
 `
 	return &cmd.Info{
 		Name:    "status-get",
-		Args:    "[--include-data] [--service]",
+		Args:    "[--include-data] [--service [--include-units]]",
 		Purpose: "print status information",
 		Doc:     doc,
 	}
@@ -41,9 +45,13 @@ func (c *StatusGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.BoolVar(&c.includeData, "include-data", false, "print all status data")
 	f.BoolVar(&c.serviceWide, "service", false, "print status for all units of this service if this unit is the leader")
+	f.BoolVar(&c.includeUnits, "include-units", false, "print the status of each unit with the service status")
 }
 
 func (c *StatusGetCommand) Init(args []string) error {
+	if c.includeUnits && !c.serviceWide {
+		return errors.New("--include-units requires --service")
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -83,17 +91,18 @@ func (c *StatusGetCommand) ServiceStatus(ctx *cmd.Context) error {
 		}
 		return errors.Annotatef(err, "finding service status")
 	}
-	if !c.includeData && c.out.Name() == "smart" {
+	if !c.includeData && !c.includeUnits && c.out.Name() == "smart" {
 		return c.out.Write(ctx, serviceStatus.Service.Status)
 	}
 	statusDetails := make(map[string]interface{})
 	details := toDetails(serviceStatus.Service, c.includeData)
-
-	units := make(map[string]interface{}, len(serviceStatus.Units))
-	for _, unit := range serviceStatus.Units {
-		units[unit.Tag] = toDetails(unit, c.includeData)
+	if c.includeUnits {
+		units := make(map[string]interface{}, len(serviceStatus.Units))
+		for _, unit := range serviceStatus.Units {
+			units[unit.Tag] = toDetails(unit, c.includeData)
+		}
+		details["units"] = units
 	}
-	details["units"] = units
 	statusDetails["service-status"] = details
 	c.out.Write(ctx, statusDetails)
 
@@ -104,7 +104,7 @@ func (s *statusGetSuite) TestHelp(c *gc.C) {
 	code := cmd.Main(com, ctx, []string{"--help"})
 	c.Assert(code, gc.Equals, 0)
 	expectedHelp := "" +
-		"Usage: status-get [options] [--include-data] [--service]\n" +
+		"Usage: status-get [options] [--include-data] [--service [--include-units]]\n" +
 		"\n" +
 		"Summary:\n" +
 		"print status information\n" +
@@ -114,6 +114,8 @@ func (s *statusGetSuite) TestHelp(c *gc.C) {
 		"    Specify output format (json|smart|yaml)\n" +
 		"--include-data  (= false)\n" +
 		"    print all status data\n" +
+		"--include-units  (= false)\n" +
+		"    print the status of each unit with the service status\n" +
 		"-o, --output (= \"\")\n" +
 		"    Specify an output file\n" +
 		"--service  (= false)\n" +
@@ -121,7 +123,10 @@ func (s *statusGetSuite) TestHelp(c *gc.C) {
 		"\n" +
 		"Details:\n" +
 		"By default, only the status value is printed.\n" +
-		"If the --include-data flag is passed, the associated data are printed also.\n"
+		"If the --include-data flag is passed, the associated data are printed also.\n" +
+		"If the --include-units flag is passed with --service, the statuses of all\n" +
+		"the service's units are printed along with the service status; otherwise\n" +
+		"only the service status is printed.\n"
 
 	c.Assert(bufferString(ctx.Stdout), gc.Equals, expectedHelp)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
@@ -164,7 +169,7 @@ func (s *statusGetSuite) TestServiceStatus(c *gc.C) {
 	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
 	c.Assert(err, jc.ErrorIsNil)
 	ctx := testing.Context(c)
-	code := cmd.Main(com, ctx, []string{"--format", "json", "--include-data", "--service"})
+	code := cmd.Main(com, ctx, []string{"--format", "json", "--include-data", "--service", "--include-units"})
 	c.Assert(code, gc.Equals, 0)
 
 	var out map[string]interface{}
@@ -172,3 +177,44 @@ func (s *statusGetSuite) TestServiceStatus(c *gc.C) {
 	c.Assert(out, gc.DeepEquals, expected)
 
 }
+
+func (s *statusGetSuite) TestServiceStatusWithoutUnits(c *gc.C) {
+	hctx := s.GetStatusHookContext(c)
+	setFakeServiceStatus(hctx)
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--include-data", "--service"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `
+service-status:
+  message: this is a service status
+  status: active
+  status-data: {}
+`[1:])
+}
+
+func (s *statusGetSuite) TestServiceStatusIncludeUnits(c *gc.C) {
+	hctx := s.GetStatusHookContext(c)
+	setFakeServiceStatus(hctx)
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--service", "--include-units"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `
+service-status:
+  status: active
+  units:
+    "":
+      status: active
+`[1:])
+}
+
+func (s *statusGetSuite) TestIncludeUnitsRequiresService(c *gc.C) {
+	hctx := s.GetStatusHookContext(c)
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"--include-units"})
+	c.Assert(err, gc.ErrorMatches, "--include-units requires --service")
+}
@@ -22,6 +22,8 @@ type ContextInfo struct {
 	Relations
 	RelationHook
 	ActionHook
+	Secrets
+	CharmState
 }
 
 // Context returns a Context that wraps the info.
@@ -61,6 +63,8 @@ type Context struct {
 	ContextRelations
 	ContextRelationHook
 	ContextActionHook
+	ContextSecrets
+	ContextCharmState
 }
 
 // NewContext builds a jujuc.Context test double.
@@ -88,5 +92,9 @@ func NewContext(stub *testing.Stub, info *ContextInfo) *Context {
 	ctx.ContextRelationHook.info = &info.RelationHook
 	ctx.ContextActionHook.stub = stub
 	ctx.ContextActionHook.info = &info.ActionHook
+	ctx.ContextSecrets.stub = stub
+	ctx.ContextSecrets.info = &info.Secrets
+	ctx.ContextCharmState.stub = stub
+	ctx.ContextCharmState.info = &info.CharmState
 	return &ctx
 }
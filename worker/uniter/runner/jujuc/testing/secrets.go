@@ -0,0 +1,132 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// Secret holds the values for a single secret in the hook context.
+type Secret struct {
+	Description    string
+	RotateInterval time.Duration
+
+	// Revisions holds the values of each revision of the secret,
+	// oldest first.
+	Revisions []map[string]string
+
+	Grants []jujuc.SecretGrantee
+}
+
+// Secrets holds the values for the hook context.
+type Secrets struct {
+	Secrets map[string]*Secret
+}
+
+// SetSecret adds or replaces the identified secret.
+func (s *Secrets) SetSecret(id string, secret *Secret) {
+	if s.Secrets == nil {
+		s.Secrets = make(map[string]*Secret)
+	}
+	s.Secrets[id] = secret
+}
+
+// ContextSecrets is a test double for jujuc.ContextSecrets.
+type ContextSecrets struct {
+	contextBase
+	info *Secrets
+}
+
+// AddSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) AddSecret(args jujuc.SecretArgs) (string, error) {
+	c.stub.AddCall("AddSecret", args)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	if args.Id == "" {
+		id := strconv.Itoa(len(c.info.Secrets))
+		c.info.SetSecret(id, &Secret{
+			Description:    args.Description,
+			RotateInterval: args.RotateInterval,
+			Revisions:      []map[string]string{args.Data},
+		})
+		return id, nil
+	}
+	secret, err := c.secret(args.Id)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	secret.Revisions = append(secret.Revisions, args.Data)
+	return args.Id, nil
+}
+
+// Secret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) Secret(id string, revision int) (map[string]string, error) {
+	c.stub.AddCall("Secret", id, revision)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	secret, err := c.secret(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if revision == 0 {
+		revision = len(secret.Revisions)
+	}
+	if revision < 1 || revision > len(secret.Revisions) {
+		return nil, errors.NotFoundf("secret %q revision %d", id, revision)
+	}
+	return secret.Revisions[revision-1], nil
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GrantSecret(id string, grantee jujuc.SecretGrantee) error {
+	c.stub.AddCall("GrantSecret", id, grantee)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	secret, err := c.secret(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	secret.Grants = append(secret.Grants, grantee)
+	return nil
+}
+
+// RevokeSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) RevokeSecret(id string, grantee jujuc.SecretGrantee) error {
+	c.stub.AddCall("RevokeSecret", id, grantee)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	secret, err := c.secret(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var grants []jujuc.SecretGrantee
+	for _, grant := range secret.Grants {
+		if grant != grantee {
+			grants = append(grants, grant)
+		}
+	}
+	secret.Grants = grants
+	return nil
+}
+
+func (c *ContextSecrets) secret(id string) (*Secret, error) {
+	secret, ok := c.info.Secrets[id]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", id)
+	}
+	return secret, nil
+}
@@ -11,8 +11,9 @@ import (
 
 // Status  holds the values for the hook context.
 type Status struct {
-	UnitStatus    jujuc.StatusInfo
-	ServiceStatus jujuc.ServiceStatusInfo
+	UnitStatus      jujuc.StatusInfo
+	ServiceStatus   jujuc.ServiceStatusInfo
+	WorkloadVersion string
 }
 
 // SetServiceStatus builds a service status and sets it on the Status.
@@ -70,3 +71,14 @@ func (c *ContextStatus) SetServiceStatus(status jujuc.StatusInfo) error {
 	c.info.SetServiceStatus(status, nil)
 	return nil
 }
+
+// SetWorkloadVersion implements jujuc.ContextStatus.
+func (c *ContextStatus) SetWorkloadVersion(version string) error {
+	c.stub.AddCall("SetWorkloadVersion", version)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	c.info.WorkloadVersion = version
+	return nil
+}
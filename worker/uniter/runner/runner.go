@@ -9,6 +9,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 	"unicode/utf8"
 
@@ -41,6 +44,21 @@ type Runner interface {
 
 	// RunCommands executes the supplied script.
 	RunCommands(commands string) (*utilexec.ExecResponse, error)
+
+	// Stats returns information about the most recent execution.
+	Stats() Stats
+}
+
+// Stats holds information about a hook, action or command execution,
+// gathered by the Runner that ran it.
+type Stats struct {
+	// ExitCode holds the exit code of the executed process, or -1
+	// if the process could not be started or did not exit normally.
+	ExitCode int
+
+	// ToolCalls holds the number of hook tool invocations made while
+	// executing, keyed on tool name.
+	ToolCalls map[string]int
 }
 
 // Context exposes jujuc.Context, and additional methods needed by Runner.
@@ -50,6 +68,7 @@ type Context interface {
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
 	SetProcess(process context.HookProcess)
+	HookTimeout() time.Duration
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 
@@ -59,22 +78,71 @@ type Context interface {
 
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths context.Paths) Runner {
-	return &runner{context, paths}
+	return &runner{context: context, paths: paths, clock: clock.WallClock}
 }
 
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   context.Paths
+	clock   clock.Clock
+
+	mu    sync.Mutex
+	stats Stats
 }
 
 func (runner *runner) Context() Context {
 	return runner.context
 }
 
+// Stats exists to satisfy the Runner interface.
+func (runner *runner) Stats() Stats {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	toolCalls := make(map[string]int, len(runner.stats.ToolCalls))
+	for name, count := range runner.stats.ToolCalls {
+		toolCalls[name] = count
+	}
+	return Stats{
+		ExitCode:  runner.stats.ExitCode,
+		ToolCalls: toolCalls,
+	}
+}
+
+// setExitCode records the exit code of the most recent execution.
+func (runner *runner) setExitCode(code int) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	runner.stats.ExitCode = code
+}
+
+// recordToolCall counts an invocation of the named hook tool.
+func (runner *runner) recordToolCall(name string) {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	if runner.stats.ToolCalls == nil {
+		runner.stats.ToolCalls = make(map[string]int)
+	}
+	runner.stats.ToolCalls[name]++
+}
+
+// exitCode returns the exit code implied by the error returned from
+// running a process.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	return -1
+}
+
 // RunCommands exists to satisfy the Runner interface.
 func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, error) {
-	result, err := runner.runCommandsWithTimeout(commands, 0, clock.WallClock)
+	result, err := runner.runCommandsWithTimeout(commands, 0, runner.clock)
 	return result, runner.context.Flush("run commands", err)
 }
 
@@ -100,6 +168,7 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 
 	err = command.Run()
 	if err != nil {
+		runner.setExitCode(-1)
 		return nil, err
 	}
 	runner.context.SetProcess(hookProcess{command.Process()})
@@ -114,7 +183,13 @@ func (runner *runner) runCommandsWithTimeout(commands string, timeout time.Durat
 	}
 
 	// Block and wait for process to finish
-	return command.WaitWithCancel(cancel)
+	response, err := command.WaitWithCancel(cancel)
+	if err != nil {
+		runner.setExitCode(exitCode(err))
+	} else {
+		runner.setExitCode(response.Code)
+	}
+	return response, err
 }
 
 // runJujuRunAction is the function that executes when a juju-run action is ran.
@@ -135,7 +210,7 @@ func (runner *runner) runJujuRunAction() (err error) {
 		logger.Debugf("unable to read juju-run action timeout, will continue running action without one")
 	}
 
-	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), clock.WallClock)
+	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), runner.clock)
 
 	if err != nil {
 		return runner.context.Flush("juju-run", err)
@@ -195,15 +270,19 @@ func (runner *runner) RunAction(actionName string) error {
 	if actionName == actions.JujuRunActionName {
 		return runner.runJujuRunAction()
 	}
-	return runner.runCharmHookWithLocation(actionName, "actions")
+	return runner.runCharmHookWithLocation(actionName, "actions", 0)
 }
 
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) error {
-	return runner.runCharmHookWithLocation(hookName, "hooks")
+	return runner.runCharmHookWithLocation(hookName, "hooks", runner.context.HookTimeout())
 }
 
-func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) error {
+// runCharmHookWithLocation runs the named hook or action found under
+// charmLocation in the charm directory. If timeout is positive, the
+// hook is killed if it runs for longer than that; hooks run via
+// debug-hooks are never killed.
+func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, timeout time.Duration) error {
 	srv, err := runner.startJujucServer()
 	if err != nil {
 		return err
@@ -226,12 +305,13 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
 	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+		err = runner.runCharmHook(hookName, env, charmLocation, timeout)
 	}
+	runner.setExitCode(exitCode(err))
 	return runner.context.Flush(hookName, err)
 }
 
-func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
+func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string, timeout time.Duration) error {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
 	if err != nil {
@@ -241,6 +321,11 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	if timeout > 0 {
+		// Run the hook in its own process group, so that any
+		// processes it starts are killed along with it.
+		setProcessGroup(ps)
+	}
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
@@ -259,18 +344,43 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
-		err = ps.Wait()
+		err = waitHook(ps, hookName, timeout, runner.clock)
 	}
 	hookLogger.stop()
 	return errors.Trace(err)
 }
 
+// waitHook waits for the hook process to finish. If timeout is positive
+// and the hook is still running when it expires, the hook's process
+// group is killed and a hook timeout error is returned.
+func waitHook(ps *exec.Cmd, hookName string, timeout time.Duration, clock clock.Clock) error {
+	if timeout <= 0 {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-clock.After(timeout):
+	}
+	logger.Warningf("killing %s hook after %v", hookName, timeout)
+	if err := killProcessGroup(ps.Process); err != nil {
+		logger.Errorf("cannot kill %s hook: %v", hookName, err)
+	}
+	<-done
+	return context.NewHookTimeoutError(hookName, timeout)
+}
+
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
 		if ctxId != runner.context.Id() {
 			return nil, errors.Errorf("expected context id %q, got %q", runner.context.Id(), ctxId)
 		}
+		runner.recordToolCall(strings.TrimSuffix(cmdName, jujuc.CmdSuffix))
 		return jujuc.NewCommand(runner.context, cmdName)
 	}
 	srv, err := jujuc.NewServer(getCmd, runner.paths.GetJujucSocket())
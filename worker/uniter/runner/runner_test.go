@@ -19,6 +19,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -57,6 +58,7 @@ exit 42
 	c.Assert(strings.TrimRight(string(result.Stdout), "\r\n"), gc.Equals, paths.GetCharmDir())
 	c.Assert(strings.TrimRight(string(result.Stderr), "\r\n"), gc.Equals, "this is standard err")
 	c.Assert(ctx.GetProcess(), gc.NotNil)
+	c.Assert(runner.Stats().ExitCode, gc.Equals, 42)
 }
 
 type RunHookSuite struct {
@@ -155,6 +157,7 @@ type MockContext struct {
 	flushBadge      string
 	flushFailure    error
 	flushResult     error
+	hookTimeout     time.Duration
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -176,6 +179,10 @@ func (ctx *MockContext) SetProcess(process context.HookProcess) {
 	ctx.expectPid = process.Pid()
 }
 
+func (ctx *MockContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *MockContext) Prepare() error {
 	return nil
 }
@@ -245,10 +252,50 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 		perm: 0700,
 		code: 123,
 	}, s.paths.GetCharmDir())
-	actualErr := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	rnr := runner.NewRunner(ctx, s.paths)
+	actualErr := rnr.RunHook("something-happened")
 	c.Assert(actualErr, gc.Equals, expectErr)
 	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
 	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
+	c.Assert(rnr.Stats().ExitCode, gc.Equals, 123)
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks cannot sleep on windows")
+	}
+	ctx := &MockContext{
+		hookTimeout: 100 * time.Millisecond,
+	}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 60,
+	}, s.paths.GetCharmDir())
+	clock := coretesting.NewClock(time.Time{})
+	rnr := runner.NewRunnerWithClock(ctx, s.paths, clock)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rnr.RunHook("something-happened")
+	}()
+	select {
+	case <-clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("hook timeout not started")
+	}
+	clock.Advance(100 * time.Millisecond)
+	select {
+	case err := <-errc:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("hook not killed after timeout")
+	}
+	c.Assert(rnr.Stats().ExitCode, gc.Equals, -1)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "something-happened hook timed out after 100ms")
+	c.Assert(errors.Cause(ctx.flushFailure), jc.Satisfies, context.IsHookTimeoutError)
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
@@ -0,0 +1,18 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing on windows, where processes started by
+// the hook are not killed with it.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the given process.
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
@@ -18,9 +18,11 @@ import (
 	"github.com/juju/utils/exec"
 	"github.com/juju/utils/fslock"
 	corecharm "gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hookstats"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker"
@@ -98,8 +100,30 @@ type Uniter struct {
 
 	// hookRetryStrategy represents configuration for hook retries
 	hookRetryStrategy params.RetryStrategy
+
+	// executionsMutex guards executions, the most recent hook, action
+	// and juju-run executions, which are summarized by Report, and
+	// pendingExecutions, those not yet sent to the controller.
+	executionsMutex   sync.Mutex
+	executions        []hookstats.Execution
+	pendingExecutions []params.HookExecution
 }
 
+const (
+	// maxRecentExecutions is the number of executions kept in memory
+	// for the uniter's introspection report.
+	maxRecentExecutions = 200
+
+	// maxPendingExecutions is the number of executions that may be
+	// queued before they are sent to the controller, without waiting
+	// for executionsFlushInterval to pass.
+	maxPendingExecutions = 50
+
+	// executionsFlushInterval is how often queued executions are sent
+	// to the controller.
+	executionsFlushInterval = time.Minute
+)
+
 // UniterParams hold all the necessary parameters for a new Uniter.
 type UniterParams struct {
 	UniterFacade         *uniter.State
@@ -157,6 +181,20 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 	}
 	logger.Infof("unit %q started", u.unit)
 
+	// Executions are sent to the controller in batches, rather than
+	// one API call per hook; send whatever is left on the way out.
+	stopFlushing := make(chan struct{})
+	flushingDone := make(chan struct{})
+	go func() {
+		defer close(flushingDone)
+		u.flushExecutionsLoop(stopFlushing)
+	}()
+	defer func() {
+		close(stopFlushing)
+		<-flushingDone
+		u.flushExecutions()
+	}()
+
 	// Install is a special case, as it must run before there
 	// is any remote state, and before the remote state watcher
 	// is started.
@@ -195,6 +233,10 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 	)
 
 	logger.Infof("hooks are retried %v", u.hookRetryStrategy.ShouldRetry)
+	var retryHookKinds []hooks.Kind
+	for _, kind := range u.hookRetryStrategy.HookKinds {
+		retryHookKinds = append(retryHookKinds, hooks.Kind(kind))
+	}
 	retryHookChan := make(chan struct{}, 1)
 	retryHookTimer := utils.NewBackoffTimer(utils.BackoffTimerConfig{
 		Min:    u.hookRetryStrategy.MinRetryTime,
@@ -286,6 +328,9 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 			Commands: runcommands.NewCommandsResolver(
 				u.commands, watcher.CommandCompleted,
 			),
+			MaxHookRetries:  u.hookRetryStrategy.MaxAttempts,
+			RetryHookKinds:  retryHookKinds,
+			ReportHookRetry: u.reportHookRetry,
 		})
 
 		// We should not do anything until there has been a change
@@ -509,6 +554,92 @@ func (u *Uniter) Wait() error {
 	return u.catacomb.Wait()
 }
 
+// Report is part of the dependency.Reporter interface. It summarizes the
+// timings of the unit's most recent executions, so that they can be
+// inspected with juju-introspect without going through the controller.
+func (u *Uniter) Report() map[string]interface{} {
+	u.executionsMutex.Lock()
+	executions := make([]hookstats.Execution, len(u.executions))
+	copy(executions, u.executions)
+	u.executionsMutex.Unlock()
+
+	var stats []map[string]interface{}
+	for _, s := range hookstats.Summarize(executions) {
+		stats = append(stats, map[string]interface{}{
+			"kind":       s.Kind,
+			"name":       s.Name,
+			"executions": s.Executions,
+			"failures":   s.Failures,
+			"p50":        s.P50.String(),
+			"p90":        s.P90.String(),
+			"p99":        s.P99.String(),
+			"max":        s.Max.String(),
+			"total":      s.Total.String(),
+			"last-run":   s.LastRun.Format(time.RFC3339),
+		})
+	}
+	return map[string]interface{}{
+		"hook-stats": stats,
+	}
+}
+
+// addExecution records an execution for the uniter's introspection
+// report, discarding the oldest once maxRecentExecutions is exceeded,
+// and queues it to be sent to the controller. The queue is flushed
+// straight away once it holds maxPendingExecutions.
+func (u *Uniter) addExecution(execution hookstats.Execution) {
+	u.executionsMutex.Lock()
+	u.executions = append(u.executions, execution)
+	if excess := len(u.executions) - maxRecentExecutions; excess > 0 {
+		u.executions = append(u.executions[:0], u.executions[excess:]...)
+	}
+	u.pendingExecutions = append(u.pendingExecutions, params.HookExecution{
+		Kind:      execution.Kind,
+		Name:      execution.Name,
+		Started:   execution.Started,
+		Duration:  execution.Duration,
+		ExitCode:  execution.ExitCode,
+		ToolCalls: execution.ToolCalls,
+	})
+	flush := len(u.pendingExecutions) >= maxPendingExecutions
+	u.executionsMutex.Unlock()
+	if flush {
+		u.flushExecutions()
+	}
+}
+
+// flushExecutionsLoop sends queued executions to the controller every
+// executionsFlushInterval, until stop is closed.
+func (u *Uniter) flushExecutionsLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-u.clock.After(executionsFlushInterval):
+			u.flushExecutions()
+		}
+	}
+}
+
+// flushExecutions sends queued executions to the controller in a
+// single call. Failure to send them is logged, but otherwise ignored:
+// the timings are a diagnostic aid, and must not stop the uniter.
+func (u *Uniter) flushExecutions() {
+	u.executionsMutex.Lock()
+	pending := u.pendingExecutions
+	u.pendingExecutions = nil
+	u.executionsMutex.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	err := u.unit.RecordHookExecutions(pending)
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("cannot record executions: %v", err)
+	} else if err != nil {
+		logger.Errorf("cannot record %d executions: %v", len(pending), err)
+	}
+}
+
 func (u *Uniter) getServiceCharmURL() (*corecharm.URL, error) {
 	// TODO(fwereade): pretty sure there's no reason to make 2 API calls here.
 	service, err := u.st.Service(u.unit.ServiceTag())
@@ -555,6 +686,42 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	// Set the agent status to "error". We must do this here in case the
 	// hook is interrupted (e.g. unit agent crashes), rather than immediately
 	// after attempting a runHookOp.
+	hookName, statusData, err := u.hookStatusData(hookInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	if u.operationExecutor.State().HookTimedOut {
+		statusMessage = fmt.Sprintf("hook timed out: %q", hookName)
+	}
+	return setAgentStatus(u, status.StatusError, statusMessage, statusData)
+}
+
+// reportHookRetry records, in the agent status, that a failed hook is
+// about to be retried automatically for the given attempt.
+func (u *Uniter) reportHookRetry(hookInfo hook.Info, attempt int) error {
+	hookName, statusData, err := u.hookStatusData(hookInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statusData["retry-attempt"] = attempt
+	statusData["retry-min-time"] = u.hookRetryStrategy.MinRetryTime.String()
+	statusData["retry-max-time"] = u.hookRetryStrategy.MaxRetryTime.String()
+	if len(u.hookRetryStrategy.HookKinds) > 0 {
+		statusData["retry-hook-kinds"] = u.hookRetryStrategy.HookKinds
+	}
+	statusMessage := fmt.Sprintf("retrying %q hook (attempt %d)", hookName, attempt)
+	if maxAttempts := u.hookRetryStrategy.MaxAttempts; maxAttempts > 0 {
+		statusData["retry-max-attempts"] = maxAttempts
+		statusMessage = fmt.Sprintf("retrying %q hook (attempt %d of %d)", hookName, attempt, maxAttempts)
+	}
+	logger.Infof("%s", statusMessage)
+	return setAgentStatus(u, status.StatusExecuting, statusMessage, statusData)
+}
+
+// hookStatusData returns the name of the given hook as shown to the
+// user, and the agent status data describing it.
+func (u *Uniter) hookStatusData(hookInfo hook.Info) (string, map[string]interface{}, error) {
 	hookName := string(hookInfo.Kind)
 	statusData := map[string]interface{}{}
 	if hookInfo.Kind.IsRelation() {
@@ -564,11 +731,10 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 		}
 		relationName, err := u.relations.Name(hookInfo.RelationId)
 		if err != nil {
-			return errors.Trace(err)
+			return "", nil, errors.Trace(err)
 		}
 		hookName = fmt.Sprintf("%s-%s", relationName, hookInfo.Kind)
 	}
 	statusData["hook"] = hookName
-	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
-	return setAgentStatus(u, status.StatusError, statusMessage, statusData)
+	return hookName, statusData, nil
 }
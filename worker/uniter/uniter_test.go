@@ -303,6 +303,16 @@ func (s *UniterSuite) TestUniterStartHook(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterHookStats(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"hook executions are reported",
+			quickStart{},
+			verifyHookStats{startupHooks(false)},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterMultipleErrors(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
@@ -44,6 +44,7 @@ import (
 	"github.com/juju/juju/testcharms"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/uniter/charm"
@@ -657,6 +658,24 @@ func (s quickStart) step(c *gc.C, ctx *context) {
 	step(c, ctx, verifyCharm{})
 }
 
+type verifyHookStats struct {
+	names []string
+}
+
+func (s verifyHookStats) step(c *gc.C, ctx *context) {
+	var reporter dependency.Reporter = ctx.uniter
+	stats, ok := reporter.Report()["hook-stats"].([]map[string]interface{})
+	c.Assert(ok, jc.IsTrue)
+	var names []string
+	for _, stat := range stats {
+		c.Check(stat["kind"], gc.Equals, "hook")
+		c.Check(stat["executions"], gc.Equals, 1)
+		c.Check(stat["failures"], gc.Equals, 0)
+		names = append(names, stat["name"].(string))
+	}
+	c.Check(names, jc.SameContents, s.names)
+}
+
 type quickStartRelation struct{}
 
 func (s quickStartRelation) step(c *gc.C, ctx *context) {
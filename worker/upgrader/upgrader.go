@@ -95,16 +95,21 @@ func (u *Upgrader) Stop() error {
 }
 
 // allowedTargetVersion checks if targetVersion is too different from
-// curVersion to allow a downgrade.
+// curVersion to allow a downgrade. Any downgrade is allowed when an
+// upgrade is being rolled back.
 func allowedTargetVersion(
 	origAgentVersion version.Number,
 	curVersion version.Number,
 	upgradeStepsRunning bool,
+	rollback bool,
 	targetVersion version.Number,
 ) bool {
 	if upgradeStepsRunning && targetVersion == origAgentVersion {
 		return true
 	}
+	if rollback {
+		return true
+	}
 	if targetVersion.Major < curVersion.Major {
 		return false
 	}
@@ -171,7 +176,7 @@ func (u *Upgrader) loop() error {
 			}
 		}
 
-		wantVersion, err := u.st.DesiredVersion(u.tag.String())
+		wantVersion, rollback, err := u.st.DesiredVersionInfo(u.tag.String())
 		if err != nil {
 			return err
 		}
@@ -184,6 +189,7 @@ func (u *Upgrader) loop() error {
 			u.origAgentVersion,
 			jujuversion.Current,
 			!u.upgradeStepsWaiter.IsUnlocked(),
+			rollback,
 			wantVersion,
 		) {
 			// See also bug #1299802 where when upgrading from
@@ -196,7 +202,11 @@ func (u *Upgrader) loop() error {
 			u.initialUpgradeCheckComplete.Unlock()
 			continue
 		}
-		logger.Infof("upgrade requested from %v to %v", jujuversion.Current, wantVersion)
+		if rollback {
+			logger.Infof("upgrade to %v rolled back, downgrading to %v", jujuversion.Current, wantVersion)
+		} else {
+			logger.Infof("upgrade requested from %v to %v", jujuversion.Current, wantVersion)
+		}
 
 		// Check if tools have already been downloaded.
 		wantVersionBinary := toBinaryVersion(wantVersion)
@@ -391,6 +391,7 @@ type allowedTest struct {
 	current        string
 	target         string
 	upgradeRunning bool
+	rollback       bool
 	allowed        bool
 }
 
@@ -403,14 +404,16 @@ func (s *AllowedTargetVersionSuite) TestAllowedTargetVersionSuite(c *gc.C) {
 		{original: "1.2.3", current: "1.2.3", upgradeRunning: false, target: "1.2.2", allowed: true}, // downgrade between builds
 		{original: "1.2.3", current: "1.2.3", upgradeRunning: false, target: "0.2.3", allowed: false},
 		{original: "0.2.3", current: "1.2.3", upgradeRunning: false, target: "0.2.3", allowed: false},
-		{original: "0.2.3", current: "1.2.3", upgradeRunning: true, target: "0.2.3", allowed: true}, // downgrade during upgrade
+		{original: "0.2.3", current: "1.2.3", upgradeRunning: true, target: "0.2.3", allowed: true},                  // downgrade during upgrade
+		{original: "1.2.3", current: "1.3.0", upgradeRunning: false, rollback: true, target: "1.2.3", allowed: true}, // rollback
+		{original: "1.2.3", current: "2.0.0", upgradeRunning: false, rollback: true, target: "1.2.3", allowed: true},
 	}
 	for i, test := range cases {
 		c.Logf("test case %d, %#v", i, test)
 		original := version.MustParse(test.original)
 		current := version.MustParse(test.current)
 		target := version.MustParse(test.target)
-		result := upgrader.AllowedTargetVersion(original, current, test.upgradeRunning, target)
+		result := upgrader.AllowedTargetVersion(original, current, test.upgradeRunning, test.rollback, target)
 		c.Check(result, gc.Equals, test.allowed)
 	}
 }
@@ -0,0 +1,81 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/upgraderollout"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+type fakeAPICaller struct {
+	base.APICaller
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := upgraderollout.Manifold(upgraderollout.ManifoldConfig{
+		APICallerName: "api-caller",
+		ClockName:     "clock",
+	})
+	c.Check(manifold.Inputs, jc.DeepEquals, []string{"api-caller", "clock"})
+	c.Check(manifold.Output, gc.IsNil)
+}
+
+func (s *ManifoldSuite) TestMissingAPICaller(c *gc.C) {
+	manifold := upgraderollout.Manifold(upgraderollout.ManifoldConfig{
+		APICallerName: "api-caller",
+		ClockName:     "clock",
+	})
+	_, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"api-caller": dependency.ErrMissing,
+		"clock":      coretesting.NewClock(time.Time{}),
+	}))
+	c.Check(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	clock := coretesting.NewClock(time.Time{})
+	caller := fakeAPICaller{}
+	facade := &mockFacade{}
+	expectWorker := &struct{ worker.Worker }{}
+	manifold := upgraderollout.Manifold(upgraderollout.ManifoldConfig{
+		APICallerName: "api-caller",
+		ClockName:     "clock",
+		Period:        time.Minute,
+		NewFacade: func(apiCaller base.APICaller) (upgraderollout.Facade, error) {
+			c.Check(apiCaller, gc.Equals, caller)
+			return facade, nil
+		},
+		NewWorker: func(config upgraderollout.Config) (worker.Worker, error) {
+			c.Check(config, jc.DeepEquals, upgraderollout.Config{
+				Facade: facade,
+				Clock:  clock,
+				Period: time.Minute,
+			})
+			return expectWorker, nil
+		},
+	})
+	w, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"api-caller": caller,
+		"clock":      clock,
+	}))
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(w, gc.Equals, expectWorker)
+}
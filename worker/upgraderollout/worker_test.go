@@ -0,0 +1,111 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgraderollout_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/upgraderollout"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	clock  *coretesting.Clock
+	facade *mockFacade
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = coretesting.NewClock(time.Time{})
+	s.facade = &mockFacade{calls: make(chan struct{}, 10)}
+}
+
+type mockFacade struct {
+	testing.Stub
+	calls chan struct{}
+}
+
+func (mock *mockFacade) Advance() error {
+	mock.MethodCall(mock, "Advance")
+	mock.calls <- struct{}{}
+	return mock.NextErr()
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := upgraderollout.NewWorker(upgraderollout.Config{
+		Facade: s.facade,
+		Clock:  s.clock,
+		Period: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w
+}
+
+func (s *WorkerSuite) waitCall(c *gc.C) {
+	select {
+	case <-s.facade.calls:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for Advance")
+	}
+}
+
+func (s *WorkerSuite) waitNoCall(c *gc.C) {
+	select {
+	case <-s.facade.calls:
+		c.Fatalf("unexpected Advance")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		config upgraderollout.Config
+		err    string
+	}{{
+		config: upgraderollout.Config{Clock: s.clock, Period: time.Minute},
+		err:    "nil Facade not valid",
+	}, {
+		config: upgraderollout.Config{Facade: s.facade, Period: time.Minute},
+		err:    "nil Clock not valid",
+	}, {
+		config: upgraderollout.Config{Facade: s.facade, Clock: s.clock},
+		err:    "non-positive Period not valid",
+	}} {
+		c.Logf("test %d", i)
+		w, err := upgraderollout.NewWorker(test.config)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(w, gc.IsNil)
+	}
+}
+
+func (s *WorkerSuite) TestAdvancesEveryPeriod(c *gc.C) {
+	w := s.startWorker(c)
+	defer worker.Stop(w)
+
+	s.waitCall(c)
+	s.clock.Advance(time.Minute - time.Nanosecond)
+	s.waitNoCall(c)
+	s.clock.Advance(time.Nanosecond)
+	s.waitCall(c)
+
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	s.facade.CheckCallNames(c, "Advance", "Advance")
+}
+
+func (s *WorkerSuite) TestAdvanceError(c *gc.C) {
+	s.facade.SetErrors(errors.New("cannot release machines"))
+	w := s.startWorker(c)
+	s.waitCall(c)
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot release machines")
+}